@@ -0,0 +1,80 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"fmt"
+	"unicode"
+)
+
+// answerLangInstructions 是 -answer-lang 追加在 system prompt 之后的要求. prompt 是中文, tool 的描述和结果大多是英文,
+// 不加要求时模型回答用哪种语言并不确定.
+var answerLangInstructions = map[string]string{
+	"en": "# Answer language:\nAlways write the final answer to the user in English, even though this prompt is in Chinese. " +
+		"Keep restaurant and dish names as they are returned by the tools.",
+	"zh": "# 回答语言:\n最终回答必须使用中文, 即使工具的描述和返回结果是英文.",
+}
+
+// answerLangInstruction 返回 lang 对应的要求, lang 不支持时返回错误.
+func answerLangInstruction(lang string) (string, error) {
+	instruction, ok := answerLangInstructions[lang]
+	if !ok {
+		return "", fmt.Errorf("unsupported -answer-lang %q, use en or zh", lang)
+	}
+	return instruction, nil
+}
+
+// cjkRatio 是汉字在所有字母类字符中所占的比例, 数字、标点和 emoji 不计入; 没有字母类字符时返回 0.
+func cjkRatio(s string) float64 {
+	var letters, cjk int
+	for _, r := range s {
+		if !unicode.IsLetter(r) {
+			continue
+		}
+		letters++
+		if unicode.Is(unicode.Han, r) {
+			cjk++
+		}
+	}
+	if letters == 0 {
+		return 0
+	}
+	return float64(cjk) / float64(letters)
+}
+
+// detectAnswerLang 用汉字的比例粗略判断回答的语言: 一个汉字对应一个英文字母来计数, 英文的回答里夹着几个中文的菜名
+// 仍然会判断为 en. 没有可以判断的文字时返回空字符串.
+func detectAnswerLang(answer string) string {
+	for _, r := range answer {
+		if unicode.IsLetter(r) {
+			if cjkRatio(answer) >= 0.5 {
+				return "zh"
+			}
+			return "en"
+		}
+	}
+	return ""
+}
+
+// answerLangMismatch 在回答的语言和 lang 不一致时返回一条警告, 一致或无法判断时返回空字符串.
+func answerLangMismatch(lang, answer string) string {
+	detected := detectAnswerLang(answer)
+	if detected == "" || detected == lang {
+		return ""
+	}
+	return fmt.Sprintf("the answer looks like %s (%.0f%% CJK characters), but -answer-lang is %s", detected, cjkRatio(answer)*100, lang)
+}
@@ -0,0 +1,43 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDetectAnswerLang(t *testing.T) {
+	assert.Equal(t, "zh", detectAnswerLang("推荐云边小馆的红烧肉, 评分 8 分."))
+	assert.Equal(t, "en", detectAnswerLang("I recommend the 红烧肉 at 云边小馆, rated 8 out of 10."))
+	assert.Equal(t, "", detectAnswerLang("1. 🍖 20"))
+
+	assert.Empty(t, answerLangMismatch("zh", "推荐云边小馆的红烧肉."))
+	assert.Contains(t, answerLangMismatch("zh", "I recommend the braised pork."), "looks like en")
+	assert.Contains(t, answerLangMismatch("en", "推荐云边小馆的红烧肉."), "looks like zh")
+	assert.Empty(t, answerLangMismatch("en", ""))
+}
+
+func TestAnswerLangInstruction(t *testing.T) {
+	instruction, err := answerLangInstruction("en")
+	assert.NoError(t, err)
+	assert.Contains(t, instruction, "in English")
+
+	_, err = answerLangInstruction("fr")
+	assert.ErrorContains(t, err, "unsupported -answer-lang")
+}
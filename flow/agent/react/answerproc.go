@@ -0,0 +1,160 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// AnswerProcessor 在最终回答展示之前加工它, 比如去掉首尾空白、追加免责声明或限制长度.
+// 和 tools.ToolMiddleware 加工 tool 的调用一样, 多个 processor 用 ChainAnswerProcessors 组合.
+type AnswerProcessor func(answer string) (string, error)
+
+// ChainAnswerProcessors 把多个 processor 合成一个, 按顺序执行, 前一个的输出是后一个的输入.
+// 某个 processor 返回错误时后面的不再执行, 错误中带上它在链中的位置.
+func ChainAnswerProcessors(processors ...AnswerProcessor) AnswerProcessor {
+	return func(answer string) (string, error) {
+		for i, p := range processors {
+			var err error
+			if answer, err = p(answer); err != nil {
+				return "", fmt.Errorf("answer processor #%d: %w", i+1, err)
+			}
+		}
+		return answer, nil
+	}
+}
+
+// TrimAnswer 去掉回答首尾的空白.
+func TrimAnswer() AnswerProcessor {
+	return func(answer string) (string, error) {
+		return strings.TrimSpace(answer), nil
+	}
+}
+
+// DisclaimerAnswer 在回答之后另起一段追加 text, 回答已经以 text 结尾时不重复追加.
+func DisclaimerAnswer(text string) AnswerProcessor {
+	return func(answer string) (string, error) {
+		if text == "" || strings.HasSuffix(strings.TrimSpace(answer), text) {
+			return answer, nil
+		}
+		return strings.TrimRight(answer, "\n") + "\n\n" + text, nil
+	}
+}
+
+// MaxLengthAnswer 把超过 maxRunes 个字符的回答截断, 结尾加上 "...", 加上之后仍不超过 maxRunes.
+func MaxLengthAnswer(maxRunes int) AnswerProcessor {
+	const ellipsis = "..."
+	return func(answer string) (string, error) {
+		if utf8.RuneCountInString(answer) <= maxRunes {
+			return answer, nil
+		}
+		if maxRunes <= len(ellipsis) {
+			return "", fmt.Errorf("max length %d is too short to truncate the answer", maxRunes)
+		}
+		runes := []rune(answer)
+		return strings.TrimRight(string(runes[:maxRunes-len(ellipsis)]), " \n") + ellipsis, nil
+	}
+}
+
+var (
+	mdCodeFence = regexp.MustCompile("(?m)^```.*$\n?")
+	mdHeading   = regexp.MustCompile(`(?m)^#{1,6}\s+`)
+	mdQuote     = regexp.MustCompile(`(?m)^>\s?`)
+	mdListItem  = regexp.MustCompile(`(?m)^(\s*)[*+]\s+`)
+	mdImage     = regexp.MustCompile(`!\[([^\]]*)\]\([^)]*\)`)
+	mdLink      = regexp.MustCompile(`\[([^\]]+)\]\(([^)]+)\)`)
+	mdEmphasis  = regexp.MustCompile(`(\*\*|__|\*|~~)(\S(?:.*?\S)?)(\*\*|__|\*|~~)`)
+	mdCode      = regexp.MustCompile("`([^`]+)`")
+	mdRule      = regexp.MustCompile(`(?m)^\s*(?:-{3,}|\*{3,}|_{3,})\s*$\n?`)
+)
+
+// PlainTextAnswer 把 markdown 转成纯文本, 适合不渲染 markdown 的终端或短信: 去掉标题、引用、强调和代码的标记,
+// 链接改成 "文字 (地址)", 图片只保留替代文字, 列表统一用 "- ". 只处理模型回答中常见的写法, 不是完整的 markdown 解析.
+func PlainTextAnswer() AnswerProcessor {
+	return func(answer string) (string, error) {
+		answer = mdRule.ReplaceAllString(answer, "")
+		answer = mdCodeFence.ReplaceAllString(answer, "")
+		answer = mdHeading.ReplaceAllString(answer, "")
+		answer = mdQuote.ReplaceAllString(answer, "")
+		answer = mdListItem.ReplaceAllString(answer, "$1- ")
+		answer = mdImage.ReplaceAllString(answer, "$1")
+		answer = mdLink.ReplaceAllString(answer, "$1 ($2)")
+		answer = mdEmphasis.ReplaceAllStringFunc(answer, func(s string) string {
+			m := mdEmphasis.FindStringSubmatch(s)
+			if m[1] != m[3] {
+				return s
+			}
+			return m[2]
+		})
+		answer = mdCode.ReplaceAllString(answer, "$1")
+		return answer, nil
+	}
+}
+
+// defaultAnswerDisclaimer 是 -answer-disclaimer 的默认值.
+const defaultAnswerDisclaimer = "以上推荐基于示例数据, 价格和营业时间请以餐厅实际为准."
+
+// AnswerProcessorOptions 是 -answer-processors 中需要参数的 processor 的参数.
+type AnswerProcessorOptions struct {
+	Disclaimer string
+	MaxLength  int
+}
+
+// parseAnswerProcessors 把 -answer-processors 的逗号分隔的名称转成按顺序执行的 processor, 名称为空时返回 nil.
+// 支持 trim、plain-text、max-length 和 disclaimer, 同一个名称可以出现多次.
+func parseAnswerProcessors(spec string, opts AnswerProcessorOptions) (AnswerProcessor, error) {
+	var processors []AnswerProcessor
+	for _, name := range strings.Split(spec, ",") {
+		switch name = strings.TrimSpace(name); name {
+		case "":
+		case "trim":
+			processors = append(processors, TrimAnswer())
+		case "plain-text":
+			processors = append(processors, PlainTextAnswer())
+		case "max-length":
+			if opts.MaxLength <= 0 {
+				return nil, fmt.Errorf("max-length needs a positive -answer-max-length, got %d", opts.MaxLength)
+			}
+			processors = append(processors, MaxLengthAnswer(opts.MaxLength))
+		case "disclaimer":
+			processors = append(processors, DisclaimerAnswer(opts.Disclaimer))
+		default:
+			return nil, fmt.Errorf("unknown answer processor %q, use trim, plain-text, max-length or disclaimer", name)
+		}
+	}
+	if len(processors) == 0 {
+		return nil, nil
+	}
+	return ChainAnswerProcessors(processors...), nil
+}
+
+// processForDisplay 用 process 加工要展示的回答. process 为空时原样返回; 出错时打印警告并返回原来的回答,
+// 加工失败不应该让用户看不到回答.
+func processForDisplay(process AnswerProcessor, answer string) string {
+	if process == nil {
+		return answer
+	}
+	processed, err := process(answer)
+	if err != nil {
+		fmt.Printf("[WARN] %v, showing the answer as is\n", err)
+		return answer
+	}
+	return processed
+}
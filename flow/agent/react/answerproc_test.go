@@ -0,0 +1,104 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAnswerProcessors(t *testing.T) {
+	res, err := TrimAnswer()("  推荐云边小馆\n\n")
+	assert.NoError(t, err)
+	assert.Equal(t, "推荐云边小馆", res)
+
+	disclaimer := DisclaimerAnswer("仅供参考")
+	res, err = disclaimer("推荐云边小馆\n")
+	assert.NoError(t, err)
+	assert.Equal(t, "推荐云边小馆\n\n仅供参考", res)
+	res, err = disclaimer(res)
+	assert.NoError(t, err)
+	assert.Equal(t, "推荐云边小馆\n\n仅供参考", res, "不重复追加")
+
+	// 按字符截断, 加上 ... 之后仍不超过上限
+	res, err = MaxLengthAnswer(6)("推荐云边小馆的红烧肉")
+	assert.NoError(t, err)
+	assert.Equal(t, "推荐云...", res)
+	res, err = MaxLengthAnswer(6)("推荐红烧肉")
+	assert.NoError(t, err)
+	assert.Equal(t, "推荐红烧肉", res)
+	_, err = MaxLengthAnswer(2)("推荐云边小馆")
+	assert.Error(t, err)
+
+	res, err = PlainTextAnswer()("## 推荐\n\n> 结果来自示例数据\n\n* **云边小馆**: `红烧肉`, 见 [地图](https://example.com/map)\n+ *聚福轩食府*\n- 3 * 4 = 12\n---\n![logo](logo.png)")
+	assert.NoError(t, err)
+	assert.Equal(t, "推荐\n\n结果来自示例数据\n\n- 云边小馆: 红烧肉, 见 地图 (https://example.com/map)\n- 聚福轩食府\n- 3 * 4 = 12\nlogo", res)
+}
+
+func TestChainAnswerProcessors(t *testing.T) {
+	var calls []string
+	record := func(name string, err error) AnswerProcessor {
+		return func(answer string) (string, error) {
+			calls = append(calls, name)
+			return answer + name, err
+		}
+	}
+
+	// 按顺序执行, 前一个的输出是后一个的输入
+	res, err := ChainAnswerProcessors(record("a", nil), record("b", nil))("x")
+	assert.NoError(t, err)
+	assert.Equal(t, "xab", res)
+
+	// 出错时后面的不再执行
+	calls = nil
+	boom := errors.New("boom")
+	_, err = ChainAnswerProcessors(record("a", nil), record("b", boom), record("c", nil))("x")
+	assert.ErrorIs(t, err, boom)
+	assert.ErrorContains(t, err, "#2")
+	assert.Equal(t, []string{"a", "b"}, calls)
+
+	// 出错时展示原来的回答
+	assert.Equal(t, "x", processForDisplay(record("a", boom), "x"))
+	assert.Equal(t, "x", processForDisplay(nil, "x"))
+}
+
+func TestParseAnswerProcessors(t *testing.T) {
+	opts := AnswerProcessorOptions{Disclaimer: "仅供参考", MaxLength: 100}
+	process, err := parseAnswerProcessors("", opts)
+	assert.NoError(t, err)
+	assert.Nil(t, process)
+
+	process, err = parseAnswerProcessors("plain-text, trim, disclaimer", opts)
+	assert.NoError(t, err)
+	res, err := process("  **推荐云边小馆**  ")
+	assert.NoError(t, err)
+	assert.Equal(t, "推荐云边小馆\n\n仅供参考", res)
+
+	// 顺序不同结果不同: 先截断再追加免责声明, 免责声明不会被截掉
+	process, err = parseAnswerProcessors("max-length,disclaimer", AnswerProcessorOptions{Disclaimer: "仅供参考", MaxLength: 5})
+	assert.NoError(t, err)
+	res, err = process("推荐云边小馆的红烧肉")
+	assert.NoError(t, err)
+	assert.Equal(t, "推荐...\n\n仅供参考", res)
+
+	_, err = parseAnswerProcessors("trim,shout", opts)
+	assert.ErrorContains(t, err, `unknown answer processor "shout"`)
+	_, err = parseAnswerProcessors("max-length", AnswerProcessorOptions{})
+	assert.ErrorContains(t, err, "-answer-max-length")
+}
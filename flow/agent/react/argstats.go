@@ -0,0 +1,167 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/cloudwego/eino/callbacks"
+	"github.com/cloudwego/eino/components"
+	"github.com/cloudwego/eino/components/tool"
+)
+
+// defaultMaxArgValues 是每个参数默认最多记录的不同取值个数.
+const defaultMaxArgValues = 20
+
+// ToolArgsCallback 记录每个 tool 的每个参数出现过哪些取值、各出现了几次 (比如模型查询过哪些 location),
+// 运行结束后用 Summary 打印, 用于分析模型为什么总是用某些参数调用 tool.
+// 每个参数最多记录 MaxValues 个不同的取值, 之后出现的新取值只计数不记录, 内存占用有上限. 可以并发使用.
+type ToolArgsCallback struct {
+	// Out 是输出, 为空时输出到 os.Stdout.
+	Out io.Writer
+	// MaxValues 是每个参数最多记录的不同取值个数, 为 0 时使用 defaultMaxArgValues.
+	MaxValues int
+
+	mu    sync.Mutex
+	tools map[string]*toolArgStats // tool 名 => 统计
+}
+
+type toolArgStats struct {
+	calls  int
+	fields map[string]*argValueStats // 参数名 => 取值统计
+}
+
+type argValueStats struct {
+	counts    map[string]int // 取值 (JSON) => 次数
+	untracked int            // 超过上限后没有记录的调用次数
+}
+
+func (c *ToolArgsCallback) handler() callbacks.Handler {
+	return callbacks.NewHandlerBuilder().OnStartFn(c.onStart).Build()
+}
+
+func (c *ToolArgsCallback) onStart(ctx context.Context, info *callbacks.RunInfo, input callbacks.CallbackInput) context.Context {
+	if info.Component != components.ComponentOfTool {
+		return ctx
+	}
+	tci := tool.ConvCallbackInput(input)
+	if tci == nil {
+		return ctx
+	}
+	c.record(info.Name, tci.ArgumentsInJSON)
+	return ctx
+}
+
+func (c *ToolArgsCallback) record(toolName, argumentsInJSON string) {
+	args := map[string]json.RawMessage{}
+	// 参数不是 JSON 对象时只统计调用次数
+	_ = json.Unmarshal([]byte(argumentsInJSON), &args)
+
+	limit := c.MaxValues
+	if limit <= 0 {
+		limit = defaultMaxArgValues
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.tools == nil {
+		c.tools = map[string]*toolArgStats{}
+	}
+	ts, ok := c.tools[toolName]
+	if !ok {
+		ts = &toolArgStats{fields: map[string]*argValueStats{}}
+		c.tools[toolName] = ts
+	}
+	ts.calls++
+
+	for field, raw := range args {
+		vs, ok := ts.fields[field]
+		if !ok {
+			vs = &argValueStats{counts: map[string]int{}}
+			ts.fields[field] = vs
+		}
+		value := compactJSON(raw)
+		if _, seen := vs.counts[value]; !seen && len(vs.counts) >= limit {
+			vs.untracked++
+			continue
+		}
+		vs.counts[value]++
+	}
+}
+
+// compactJSON 去掉空白, 让 "1001" 和 " \"1001\" " 这样只有格式不同的取值算作同一个.
+func compactJSON(raw json.RawMessage) string {
+	var v any
+	if err := json.Unmarshal(raw, &v); err != nil {
+		return string(raw)
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return string(raw)
+	}
+	return string(b)
+}
+
+// Summary 按 tool 名和参数名排序打印统计, 每个参数的取值按次数从多到少排列.
+func (c *ToolArgsCallback) Summary() {
+	out := c.Out
+	if out == nil {
+		out = os.Stdout
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.tools) == 0 {
+		_, _ = fmt.Fprintf(out, "[ARGS] no tool calls\n")
+		return
+	}
+
+	for _, name := range sortedKeys(c.tools) {
+		ts := c.tools[name]
+		_, _ = fmt.Fprintf(out, "[ARGS] %s: %d calls\n", name, ts.calls)
+		for _, field := range sortedKeys(ts.fields) {
+			vs := ts.fields[field]
+			values := sortedKeys(vs.counts)
+			sort.SliceStable(values, func(i, j int) bool { return vs.counts[values[i]] > vs.counts[values[j]] })
+
+			parts := make([]string, 0, len(values)+1)
+			for _, v := range values {
+				parts = append(parts, fmt.Sprintf("%s x%d", v, vs.counts[v]))
+			}
+			if vs.untracked > 0 {
+				parts = append(parts, fmt.Sprintf("(%d more calls with untracked values)", vs.untracked))
+			}
+			_, _ = fmt.Fprintf(out, "[ARGS]   %s: %s\n", field, strings.Join(parts, ", "))
+		}
+	}
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,81 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/cloudwego/eino/callbacks"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestToolArgsCallback(t *testing.T) {
+	out := &strings.Builder{}
+	c := &ToolArgsCallback{Out: out, MaxValues: 2}
+
+	for _, args := range []string{
+		`{"restaurant_id":"1001","topn":5}`,
+		`{"restaurant_id": "1001", "topn": 5}`,
+		`{"restaurant_id":"1002","topn":3}`,
+		`{"restaurant_id":"1003","topn":5}`,
+		`not json`,
+	} {
+		c.record("query_dishes", args)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			c.record("query_restaurants", `{"location":"北京"}`)
+		}()
+	}
+	wg.Wait()
+	c.Summary()
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	assert.Equal(t, "[ARGS] query_dishes: 5 calls", lines[0])
+	assert.Contains(t, lines[1], `restaurant_id: "1001" x2`)
+	assert.Contains(t, lines[1], "(1 more calls with untracked values)")
+	assert.Equal(t, "[ARGS]   topn: 5 x3, 3 x1", lines[2])
+	assert.Equal(t, "[ARGS] query_restaurants: 50 calls", lines[3])
+	assert.Equal(t, `[ARGS]   location: "北京" x50`, lines[4])
+}
+
+func TestToolArgsCallbackAgent(t *testing.T) {
+	ctx := context.Background()
+	out := &strings.Builder{}
+	c := &ToolArgsCallback{Out: out}
+
+	runner, err := NewAgentRunner(ctx, &AgentRunnerConfig{
+		ChatModel:  newScriptedModel(defaultMockScript()...),
+		PromptVars: map[string]string{"City": "北京"},
+		Handlers:   []callbacks.Handler{c.handler()},
+	})
+	assert.NoError(t, err)
+	defer runner.Close(ctx)
+
+	_, err = runner.Run(ctx, "我在北京，给我推荐一些辣的菜")
+	assert.NoError(t, err)
+	c.Summary()
+	assert.Contains(t, out.String(), "[ARGS] query_dishes: 2 calls\n")
+	assert.Contains(t, out.String(), `[ARGS]   location: "北京" x1`)
+}
@@ -0,0 +1,97 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cloudwego/eino-ext/components/model/ark"
+	"github.com/cloudwego/eino-ext/components/model/deepseek"
+	"github.com/cloudwego/eino-ext/components/model/openai"
+	"github.com/cloudwego/eino/components/model"
+)
+
+// providerEnv 是选择 provider 的环境变量, 优先级和其他环境变量一样高于配置文件、低于命令行上的 -mock.
+const providerEnv = "EINO_MODEL_PROVIDER"
+
+// modelProvider 描述一个真实的 chat model provider 从哪些环境变量读取 API key、model 和 base URL.
+type modelProvider struct {
+	APIKeyEnv  string
+	ModelEnv   string // 为空表示只能用 -model 或配置文件指定
+	BaseURLEnv string // 为空表示只使用 provider 默认的地址
+
+	DefaultModel string // 为空表示必须指定 model
+
+	New func(ctx context.Context, cfg *Config) (model.ToolCallingChatModel, error)
+}
+
+// modelProviders 是支持的真实 provider, 新增 provider 只需要在这里加一项. mock 不在其中, 见 Config.Mock.
+var modelProviders = map[string]modelProvider{
+	providerDeepSeek: {
+		APIKeyEnv:    "DEEPSEEK_API_KEY",
+		DefaultModel: "deepseek-chat",
+		New: func(ctx context.Context, cfg *Config) (model.ToolCallingChatModel, error) {
+			return deepseek.NewChatModel(ctx, &deepseek.ChatModelConfig{
+				APIKey:  cfg.APIKey,
+				Model:   cfg.Model,
+				BaseURL: cfg.BaseURL,
+			})
+		},
+	},
+	providerOpenAI: {
+		APIKeyEnv:  "OPENAI_API_KEY",
+		ModelEnv:   "OPENAI_MODEL_NAME",
+		BaseURLEnv: "OPENAI_BASE_URL",
+		New: func(ctx context.Context, cfg *Config) (model.ToolCallingChatModel, error) {
+			return openai.NewChatModel(ctx, &openai.ChatModelConfig{
+				APIKey:  cfg.APIKey,
+				Model:   cfg.Model,
+				BaseURL: cfg.BaseURL,
+			})
+		},
+	},
+	providerArk: {
+		APIKeyEnv:  "ARK_API_KEY",
+		ModelEnv:   "ARK_MODEL_NAME",
+		BaseURLEnv: "ARK_BASE_URL",
+		New: func(ctx context.Context, cfg *Config) (model.ToolCallingChatModel, error) {
+			return ark.NewChatModel(ctx, &ark.ChatModelConfig{
+				APIKey:  cfg.APIKey,
+				Model:   cfg.Model,
+				BaseURL: cfg.BaseURL,
+			})
+		},
+	},
+}
+
+// newChatModel 按 cfg.Provider 创建 chat model. 没有 API key 时返回的错误中带上应该设置的环境变量,
+// 不等到第一次请求时才被 provider 拒绝.
+func newChatModel(ctx context.Context, cfg *Config) (model.ToolCallingChatModel, error) {
+	p, ok := modelProviders[cfg.Provider]
+	if !ok {
+		return nil, fmt.Errorf("provider %q does not create a chat model", cfg.Provider)
+	}
+	if cfg.APIKey == "" {
+		return nil, fmt.Errorf("the %s provider needs an API key, set %s or api_key in the config", cfg.Provider, p.APIKeyEnv)
+	}
+	cm, err := p.New(ctx, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create %s chat model: %w", cfg.Provider, err)
+	}
+	return cm, nil
+}
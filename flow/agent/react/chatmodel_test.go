@@ -0,0 +1,92 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadConfigProvider(t *testing.T) {
+	env := func(vars map[string]string) func(string) string {
+		return func(key string) string { return vars[key] }
+	}
+
+	// 没有设置 EINO_MODEL_PROVIDER 时使用 deepseek
+	cfg, err := LoadConfig("", nil, env(map[string]string{"DEEPSEEK_API_KEY": "sk-deepseek", "OPENAI_API_KEY": "sk-openai"}))
+	assert.NoError(t, err)
+	assert.Equal(t, providerDeepSeek, cfg.Provider)
+	assert.Equal(t, "deepseek-chat", cfg.Model)
+	assert.Equal(t, "sk-deepseek", cfg.APIKey)
+
+	// 只读取所选 provider 的环境变量
+	cfg, err = LoadConfig("", nil, env(map[string]string{
+		providerEnv:         "openai",
+		"DEEPSEEK_API_KEY":  "sk-deepseek",
+		"OPENAI_API_KEY":    "sk-openai",
+		"OPENAI_MODEL_NAME": "gpt-4o-mini",
+		"OPENAI_BASE_URL":   "https://example.com/v1",
+	}))
+	assert.NoError(t, err)
+	assert.Equal(t, providerOpenAI, cfg.Provider)
+	assert.Equal(t, "gpt-4o-mini", cfg.Model)
+	assert.Equal(t, "sk-openai", cfg.APIKey)
+	assert.Equal(t, "https://example.com/v1", cfg.BaseURL)
+
+	// 环境变量覆盖配置文件中的 provider
+	path := writeConfig(t, "config.yaml", "provider: mock\n")
+	cfg, err = LoadConfig(path, nil, env(map[string]string{providerEnv: "ark", "ARK_MODEL_NAME": "ep-123"}))
+	assert.NoError(t, err)
+	assert.Equal(t, providerArk, cfg.Provider)
+	assert.Equal(t, "ep-123", cfg.Model)
+
+	// openai 和 ark 没有默认的 model
+	_, err = LoadConfig("", nil, env(map[string]string{providerEnv: "ark"}))
+	assert.ErrorContains(t, err, "ARK_MODEL_NAME")
+	_, err = LoadConfig("", nil, env(map[string]string{providerEnv: "gemini"}))
+	assert.ErrorContains(t, err, `unknown provider "gemini"`)
+
+	// 命令行上显式指定的 -model 优先于环境变量
+	setFlag(t, "model", "gpt-4o")
+	cfg, err = LoadConfig("", map[string]bool{"model": true}, env(map[string]string{providerEnv: "openai", "OPENAI_MODEL_NAME": "gpt-4o-mini"}))
+	assert.NoError(t, err)
+	assert.Equal(t, "gpt-4o", cfg.Model)
+}
+
+func TestNewChatModel(t *testing.T) {
+	ctx := context.Background()
+
+	// 没有 API key 时错误中带上应该设置的环境变量
+	for provider, env := range map[string]string{
+		providerDeepSeek: "DEEPSEEK_API_KEY",
+		providerOpenAI:   "OPENAI_API_KEY",
+		providerArk:      "ARK_API_KEY",
+	} {
+		_, err := newChatModel(ctx, &Config{Provider: provider, Model: "m"})
+		assert.ErrorContains(t, err, env)
+
+		// 创建 chat model 不会发送请求
+		cm, err := newChatModel(ctx, &Config{Provider: provider, Model: "m", APIKey: "sk-test"})
+		assert.NoError(t, err, provider)
+		assert.NotNil(t, cm)
+	}
+
+	_, err := newChatModel(ctx, &Config{Provider: providerMock})
+	assert.Error(t, err)
+}
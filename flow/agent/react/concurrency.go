@@ -0,0 +1,124 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sync/atomic"
+
+	"github.com/cloudwego/eino/callbacks"
+	"github.com/cloudwego/eino/components"
+	"github.com/cloudwego/eino/schema"
+)
+
+// ConcurrencyCallback 用原子计数器统计同时在执行的 ChatModel 和 Tool 调用数, 并记录各自的峰值,
+// 用来观察 agent 实际的并行程度, 比如模型一次返回多个 tool call 时 ToolsNode 是否并发执行了它们.
+// OnStart 时加一, OnEnd / OnError 时减一; 流式输出在 OnEndWithStreamOutput (stream 返回时) 减一, 不等 stream 读完.
+// 可以被多个并发的调用同时使用.
+type ConcurrencyCallback struct {
+	// Out 是输出, 为空时输出到 os.Stdout.
+	Out io.Writer
+
+	model, tool gauge
+}
+
+// gauge 是一个记录峰值的并发计数器.
+type gauge struct {
+	inFlight atomic.Int64
+	peak     atomic.Int64
+}
+
+func (g *gauge) inc() {
+	n := g.inFlight.Add(1)
+	for {
+		peak := g.peak.Load()
+		if n <= peak || g.peak.CompareAndSwap(peak, n) {
+			return
+		}
+	}
+}
+
+func (g *gauge) dec() {
+	g.inFlight.Add(-1)
+}
+
+func (c *ConcurrencyCallback) gauge(info *callbacks.RunInfo) *gauge {
+	switch info.Component {
+	case components.ComponentOfChatModel:
+		return &c.model
+	case components.ComponentOfTool:
+		return &c.tool
+	}
+	return nil
+}
+
+func (c *ConcurrencyCallback) OnStart(ctx context.Context, info *callbacks.RunInfo, input callbacks.CallbackInput) context.Context {
+	if g := c.gauge(info); g != nil {
+		g.inc()
+	}
+	return ctx
+}
+
+func (c *ConcurrencyCallback) OnEnd(ctx context.Context, info *callbacks.RunInfo, output callbacks.CallbackOutput) context.Context {
+	if g := c.gauge(info); g != nil {
+		g.dec()
+	}
+	return ctx
+}
+
+func (c *ConcurrencyCallback) OnError(ctx context.Context, info *callbacks.RunInfo, err error) context.Context {
+	if g := c.gauge(info); g != nil {
+		g.dec()
+	}
+	return ctx
+}
+
+func (c *ConcurrencyCallback) OnStartWithStreamInput(ctx context.Context, info *callbacks.RunInfo,
+	input *schema.StreamReader[callbacks.CallbackInput]) context.Context {
+	input.Close()
+	if g := c.gauge(info); g != nil {
+		g.inc()
+	}
+	return ctx
+}
+
+func (c *ConcurrencyCallback) OnEndWithStreamOutput(ctx context.Context, info *callbacks.RunInfo,
+	output *schema.StreamReader[callbacks.CallbackOutput]) context.Context {
+	output.Close()
+	if g := c.gauge(info); g != nil {
+		g.dec()
+	}
+	return ctx
+}
+
+// Peaks 返回 ChatModel 和 Tool 的并发峰值.
+func (c *ConcurrencyCallback) Peaks() (model, tool int64) {
+	return c.model.peak.Load(), c.tool.peak.Load()
+}
+
+// Summary 打印并发峰值.
+func (c *ConcurrencyCallback) Summary() {
+	out := c.Out
+	if out == nil {
+		out = os.Stdout
+	}
+	model, tool := c.Peaks()
+	_, _ = fmt.Fprintf(out, "[CONCURRENCY] peak in-flight: chat model %d, tool %d\n", model, tool)
+}
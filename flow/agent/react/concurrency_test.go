@@ -0,0 +1,85 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/cloudwego/eino/components/tool"
+	"github.com/cloudwego/eino/compose"
+	"github.com/cloudwego/eino/flow/agent"
+	"github.com/cloudwego/eino/schema"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConcurrencyCallback(t *testing.T) {
+	ctx := context.Background()
+
+	for _, stream := range []bool{false, true} {
+		// 一次发起三个并行的 tool 调用
+		calls := toolCallMessage("call_1", "sleepy", `{"sleep_ms": 50}`)
+		for _, id := range []string{"call_2", "call_3"} {
+			calls.ToolCalls = append(calls.ToolCalls, toolCallMessage(id, "sleepy", `{"sleep_ms": 50}`).ToolCalls...)
+		}
+		ragent, err := newAgent(ctx, newScriptedModel(calls, schema.AssistantMessage("done", nil)), []tool.BaseTool{&sleepyTool{}}, 0, 0)
+		assert.NoError(t, err)
+
+		out := &strings.Builder{}
+		c := &ConcurrencyCallback{Out: out}
+		opt := agent.WithComposeOptions(compose.WithCallbacks(c))
+		messages := []*schema.Message{schema.UserMessage("hi")}
+		if stream {
+			_, err = runStream(ctx, ragent, messages, &LoggerCallback{Out: &strings.Builder{}}, opt)
+		} else {
+			_, err = ragent.Generate(ctx, messages, opt)
+		}
+		assert.NoError(t, err)
+
+		model, tool := c.Peaks()
+		assert.Equal(t, int64(1), model, "stream=%v", stream)
+		assert.Equal(t, int64(3), tool, "stream=%v", stream)
+		assert.Zero(t, c.model.inFlight.Load())
+		assert.Zero(t, c.tool.inFlight.Load())
+
+		c.Summary()
+		assert.Equal(t, "[CONCURRENCY] peak in-flight: chat model 1, tool 3\n", out.String())
+	}
+}
+
+func TestGauge(t *testing.T) {
+	var g gauge
+	var wg sync.WaitGroup
+	start := make(chan struct{})
+	for i := 0; i < 64; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-start
+			g.inc()
+			g.dec()
+		}()
+	}
+	close(start)
+	wg.Wait()
+
+	assert.Zero(t, g.inFlight.Load())
+	assert.GreaterOrEqual(t, g.peak.Load(), int64(1))
+	assert.LessOrEqual(t, g.peak.Load(), int64(64))
+}
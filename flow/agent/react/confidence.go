@@ -0,0 +1,107 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/schema"
+)
+
+// confidencePrompt 是在最终回答之后追加的问题. 要求只输出 JSON 方便解析, 模型不照做时由 parseConfidence 尽量从文本中找出评分.
+const confidencePrompt = `请评估你对上面的推荐有多大把握: 推荐是否有工具返回的数据支持, 是否满足了用户的全部要求. ` +
+	`只输出一个 JSON 对象, 不要输出其他内容, 格式为 {"confidence": 0 到 1 之间的小数, "reason": "一句话的理由"}.`
+
+// Confidence 是模型对自己回答的评分.
+type Confidence struct {
+	// Value 在 0 到 1 之间, 越大越有把握
+	Value  float64
+	Reason string
+	// Parsed 为 false 表示模型没有按要求给出评分, Value 没有意义, Raw 是模型的原始输出
+	Parsed bool
+	Raw    string
+}
+
+func (c Confidence) String() string {
+	if !c.Parsed {
+		return fmt.Sprintf("unknown, the model did not give a rating: %q", c.Raw)
+	}
+	if c.Reason == "" {
+		return fmt.Sprintf("%.2f", c.Value)
+	}
+	return fmt.Sprintf("%.2f (%s)", c.Value, c.Reason)
+}
+
+// RateConfidence 把用户的问题和最终回答交给模型, 让它给自己的推荐打分. 这次调用不带 tool, 只评估已有的回答;
+// temperature 固定为 0, 同样的回答尽量得到同样的评分. 模型没有照做不是错误, 而是返回 Parsed 为 false 的结果.
+func (r *AgentRunner) RateConfidence(ctx context.Context, userMessage, answer string) (*Confidence, error) {
+	msg, err := r.chatModel.Generate(ctx, []*schema.Message{
+		schema.SystemMessage(r.systemPrompt),
+		schema.UserMessage(userMessage),
+		schema.AssistantMessage(answer, nil),
+		schema.UserMessage(confidencePrompt),
+	}, model.WithTemperature(0))
+	if err != nil {
+		return nil, fmt.Errorf("failed to rate the confidence: %w", err)
+	}
+	c := parseConfidence(msg.Content)
+	return &c, nil
+}
+
+// confidenceNumber 匹配回答中的第一个数字, 可以带百分号.
+var confidenceNumber = regexp.MustCompile(`(\d+(?:\.\d+)?)\s*(%?)`)
+
+// parseConfidence 先按要求的 JSON 解析 (允许前后有多余的文字或 markdown 代码块), 失败时退而取文本中的第一个数字,
+// 0 到 1 之间的小数或者百分数都可以. 超出 0 到 1 的评分视为没有给出.
+func parseConfidence(content string) Confidence {
+	c := Confidence{Raw: strings.TrimSpace(content)}
+
+	if start, end := strings.Index(content, "{"), strings.LastIndex(content, "}"); start >= 0 && end > start {
+		var rating struct {
+			Confidence *float64 `json:"confidence"`
+			Reason     string   `json:"reason"`
+		}
+		if err := json.Unmarshal([]byte(content[start:end+1]), &rating); err == nil && rating.Confidence != nil {
+			if v := *rating.Confidence; v >= 0 && v <= 1 {
+				c.Value, c.Reason, c.Parsed = v, strings.TrimSpace(rating.Reason), true
+			}
+			return c
+		}
+	}
+
+	m := confidenceNumber.FindStringSubmatch(content)
+	if m == nil {
+		return c
+	}
+	v, err := strconv.ParseFloat(m[1], 64)
+	if err != nil {
+		return c
+	}
+	if m[2] == "%" {
+		v /= 100
+	}
+	if v >= 0 && v <= 1 {
+		c.Value, c.Parsed = v, true
+	}
+	return c
+}
@@ -0,0 +1,86 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cloudwego/eino/schema"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseConfidence(t *testing.T) {
+	cases := []struct {
+		content string
+		value   float64
+		reason  string
+		parsed  bool
+	}{
+		{`{"confidence": 0.85, "reason": "有数据支持"}`, 0.85, "有数据支持", true},
+		{"```json\n{\"confidence\": 1}\n```", 1, "", true},
+		{`我的把握是 70%`, 0.7, "", true},
+		{`confidence: 0.6, 因为菜品信息不完整`, 0.6, "", true},
+		// 超出范围或者没有数字都视为没有给出评分
+		{`{"confidence": 8}`, 0, "", false},
+		{`大概 8 分`, 0, "", false},
+		{`我很有把握`, 0, "", false},
+		{``, 0, "", false},
+	}
+	for _, c := range cases {
+		got := parseConfidence(c.content)
+		assert.Equal(t, c.parsed, got.Parsed, c.content)
+		assert.Equal(t, c.value, got.Value, c.content)
+		assert.Equal(t, c.reason, got.Reason, c.content)
+	}
+
+	assert.Equal(t, "0.85 (有数据支持)", Confidence{Value: 0.85, Reason: "有数据支持", Parsed: true}.String())
+	assert.Equal(t, `unknown, the model did not give a rating: "我很有把握"`, parseConfidence("我很有把握").String())
+}
+
+func TestRateConfidence(t *testing.T) {
+	ctx := context.Background()
+	chatModel := newScriptedModel(
+		schema.AssistantMessage(`{"confidence": 0.9, "reason": "ok"}`, nil),
+		schema.AssistantMessage(`不好说`, nil),
+	)
+	runner, err := NewAgentRunner(ctx, &AgentRunnerConfig{
+		ChatModel:  chatModel,
+		PromptVars: map[string]string{"City": "北京"},
+	})
+	assert.NoError(t, err)
+	defer runner.Close(ctx)
+
+	c, err := runner.RateConfidence(ctx, "推荐北京的川菜", "推荐云边小馆")
+	assert.NoError(t, err)
+	assert.True(t, c.Parsed)
+	assert.Equal(t, 0.9, c.Value)
+
+	// 问题和回答都交给模型, 最后追加评分的要求
+	input := chatModel.inputs[0]
+	assert.Len(t, input, 4)
+	assert.Equal(t, "推荐云边小馆", input[2].Content)
+	assert.Equal(t, confidencePrompt, input[3].Content)
+
+	// 没有照做时不是错误
+	c, err = runner.RateConfidence(ctx, "推荐北京的川菜", "推荐云边小馆")
+	assert.NoError(t, err)
+	assert.False(t, c.Parsed)
+
+	_, err = runner.RateConfidence(ctx, "推荐北京的川菜", "推荐云边小馆")
+	assert.ErrorContains(t, err, "failed to rate the confidence")
+}
@@ -0,0 +1,278 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/cloudwego/eino/components/tool"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	providerDeepSeek = "deepseek"
+	providerOpenAI   = "openai"
+	providerArk      = "ark"
+	providerMock     = "mock"
+)
+
+// maxStepsEnv 覆盖配置文件中的 max_steps, 命令行上显式指定的 -max-steps 优先.
+const maxStepsEnv = "EINO_MAX_STEPS"
+
+// Config 汇总了 main 运行 agent 的主要设置, 可以用 -config 从 YAML 或 JSON 文件加载.
+// 优先级从低到高: flag 的默认值、配置文件、环境变量 (EINO_MODEL_PROVIDER、EINO_MAX_STEPS 和所选 provider 的 API key 等, 见 modelProviders)、
+// 命令行上显式指定的 flag.
+type Config struct {
+	// Provider 是 chat model 的来源: deepseek、openai、ark 或 mock (脚本模型, 不需要 API key).
+	Provider string `json:"provider" yaml:"provider"`
+	// Model 为空时使用 provider 的默认 model, openai 和 ark 没有默认值.
+	Model string `json:"model" yaml:"model"`
+	// APIKey 没有对应的 flag, 所选 provider 的环境变量 (如 DEEPSEEK_API_KEY) 优先. 打印配置时不显示.
+	APIKey string `json:"api_key" yaml:"api_key"`
+	// BaseURL 为空时使用 provider 默认的地址.
+	BaseURL string `json:"base_url" yaml:"base_url"`
+	// Temperature 为空时使用模型的默认值.
+	Temperature *float32 `json:"temperature" yaml:"temperature"`
+
+	// EnabledTools 是暴露给模型的 tool 名称, 为空表示全部.
+	EnabledTools []string `json:"enabled_tools" yaml:"enabled_tools"`
+	// FailureRate 是 query_restaurants 随机失败的概率, 见 tools.SetFailureRate.
+	FailureRate float64 `json:"failure_rate" yaml:"failure_rate"`
+	// MaxSteps 限制 ReAct 循环的步数, 0 表示使用 react 的默认值.
+	MaxSteps int `json:"max_steps" yaml:"max_steps"`
+
+	Logging LoggingConfig `json:"logging" yaml:"logging"`
+}
+
+type LoggingConfig struct {
+	RedactPII    bool   `json:"redact_pii" yaml:"redact_pii"`
+	Verbose      bool   `json:"verbose" yaml:"verbose"`
+	RunSummary   bool   `json:"run_summary" yaml:"run_summary"`
+	OTelExporter string `json:"otel_exporter" yaml:"otel_exporter"` // stdout 或 none
+}
+
+// Mock 表示是否使用脚本模型.
+func (c *Config) Mock() bool {
+	return c.Provider == providerMock
+}
+
+// effectiveConfig 是 main 加载的配置, 没有运行 main 时 (比如测试中) 为空.
+var effectiveConfig *Config
+
+// configFlags 把每个和 Config 字段对应的 flag 的值写进 cfg, key 是 flag 的名称.
+var configFlags = map[string]func(cfg *Config){
+	"mock": func(cfg *Config) {
+		cfg.Provider = providerDeepSeek
+		if *mockModel {
+			cfg.Provider = providerMock
+		}
+	},
+	"model": func(cfg *Config) { cfg.Model = *modelName },
+	"temperature": func(cfg *Config) {
+		cfg.Temperature = nil
+		if *temperature >= 0 {
+			t := float32(*temperature)
+			cfg.Temperature = &t
+		}
+	},
+	"tools":         func(cfg *Config) { cfg.EnabledTools = splitList(*enabledTools) },
+	"failure-rate":  func(cfg *Config) { cfg.FailureRate = *failureRate },
+	"max-steps":     func(cfg *Config) { cfg.MaxSteps = *maxSteps },
+	"redact-pii":    func(cfg *Config) { cfg.Logging.RedactPII = *redactPII },
+	"verbose":       func(cfg *Config) { cfg.Logging.Verbose = *verbose },
+	"run-summary":   func(cfg *Config) { cfg.Logging.RunSummary = *printRunSummary },
+	"otel-exporter": func(cfg *Config) { cfg.Logging.OTelExporter = *otelExporter },
+}
+
+// LoadConfig 从 flag 的当前值开始, 依次用 path 中的配置 (为空时跳过)、环境变量和 explicit 中的 flag 覆盖, 再检查结果.
+// explicit 是命令行上显式指定的 flag, 见 explicitFlags.
+func LoadConfig(path string, explicit map[string]bool, getenv func(string) string) (*Config, error) {
+	cfg := &Config{}
+	for _, apply := range configFlags {
+		apply(cfg)
+	}
+
+	if path != "" {
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read config: %w", err)
+		}
+		if err := decodeConfig(path, b, cfg); err != nil {
+			return nil, fmt.Errorf("invalid config %s: %w", path, err)
+		}
+	}
+	if provider := getenv(providerEnv); provider != "" {
+		cfg.Provider = provider
+	}
+	if v := getenv(maxStepsEnv); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s %q: %w", maxStepsEnv, v, err)
+		}
+		cfg.MaxSteps = n
+	}
+	for name := range explicit {
+		if apply, ok := configFlags[name]; ok {
+			apply(cfg)
+		}
+	}
+	// provider 确定之后才知道读哪些环境变量; 只有 model 有对应的 flag, 显式指定的 -model 优先
+	p := modelProviders[cfg.Provider]
+	for env, field := range map[string]*string{p.APIKeyEnv: &cfg.APIKey, p.ModelEnv: &cfg.Model, p.BaseURLEnv: &cfg.BaseURL} {
+		if env == "" || (field == &cfg.Model && explicit["model"]) {
+			continue
+		}
+		if v := getenv(env); v != "" {
+			*field = v
+		}
+	}
+	if cfg.Model == "" {
+		cfg.Model = modelProviders[cfg.Provider].DefaultModel
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// decodeConfig 按扩展名解析, .json 之外都按 YAML 解析. 不认识的字段是错误, 写错的字段名不会被悄悄忽略.
+// 文件中没有的字段保留 cfg 中原来的值.
+func decodeConfig(path string, b []byte, cfg *Config) error {
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		dec := json.NewDecoder(bytes.NewReader(b))
+		dec.DisallowUnknownFields()
+		return dec.Decode(cfg)
+	}
+	dec := yaml.NewDecoder(bytes.NewReader(b))
+	dec.KnownFields(true)
+	if err := dec.Decode(cfg); err != nil && !errors.Is(err, io.EOF) {
+		return err
+	}
+	return nil
+}
+
+// Validate 检查各个字段的取值范围, tool 名称由 selectTools 检查.
+func (c *Config) Validate() error {
+	if p, ok := modelProviders[c.Provider]; ok {
+		if c.Model == "" {
+			return fmt.Errorf("model is required for the %s provider, set %s or -model", c.Provider, cmp.Or(p.ModelEnv, "model in the config"))
+		}
+	} else if c.Provider != providerMock {
+		return fmt.Errorf("unknown provider %q, expected %s, %s, %s or %s", c.Provider, providerDeepSeek, providerOpenAI, providerArk, providerMock)
+	}
+	if c.Temperature != nil && (*c.Temperature < 0 || *c.Temperature > 2) {
+		return fmt.Errorf("temperature must be between 0 and 2, got %v", *c.Temperature)
+	}
+	if c.FailureRate < 0 || c.FailureRate > 1 {
+		return fmt.Errorf("failure_rate must be between 0 and 1, got %v", c.FailureRate)
+	}
+	if c.MaxSteps < 0 {
+		return fmt.Errorf("max_steps must not be negative, got %d", c.MaxSteps)
+	}
+	switch c.Logging.OTelExporter {
+	case "", "none", "stdout":
+	default:
+		return fmt.Errorf("unknown otel_exporter %q, expected stdout or none", c.Logging.OTelExporter)
+	}
+	return nil
+}
+
+// String 是启动时打印的生效配置, 不包含 API key 本身.
+func (c *Config) String() string {
+	apiKey := "not set"
+	if c.APIKey != "" {
+		apiKey = "set"
+	}
+	temperature := "default"
+	if c.Temperature != nil {
+		temperature = fmt.Sprint(*c.Temperature)
+	}
+	enabled := "all"
+	if len(c.EnabledTools) > 0 {
+		enabled = strings.Join(c.EnabledTools, ",")
+	}
+	maxSteps := "default"
+	if c.MaxSteps > 0 {
+		maxSteps = fmt.Sprint(c.MaxSteps)
+	}
+	otel := c.Logging.OTelExporter
+	if otel == "" {
+		otel = "none"
+	}
+	return fmt.Sprintf("provider=%s model=%s api_key=%s temperature=%s tools=%s failure_rate=%v max_steps=%s redact_pii=%t verbose=%t run_summary=%t otel_exporter=%s",
+		c.Provider, c.Model, apiKey, temperature, enabled, c.FailureRate, maxSteps,
+		c.Logging.RedactPII, c.Logging.Verbose, c.Logging.RunSummary, otel)
+}
+
+// explicitFlags 返回命令行上显式指定的 flag.
+func explicitFlags() map[string]bool {
+	set := map[string]bool{}
+	flag.Visit(func(f *flag.Flag) { set[f.Name] = true })
+	return set
+}
+
+// selectTools 按 names 的顺序从 all 中选出 tool, names 为空时返回 nil, 表示使用全部. 名称不存在时返回错误.
+func selectTools(ctx context.Context, all []tool.BaseTool, names []string) ([]tool.BaseTool, error) {
+	if len(names) == 0 {
+		return nil, nil
+	}
+	byName := map[string]tool.BaseTool{}
+	for _, t := range all {
+		info, err := t.Info(ctx)
+		if err != nil {
+			return nil, err
+		}
+		byName[info.Name] = t
+	}
+	res := make([]tool.BaseTool, 0, len(names))
+	for _, name := range names {
+		t, ok := byName[name]
+		if !ok {
+			known := make([]string, 0, len(byName))
+			for n := range byName {
+				known = append(known, n)
+			}
+			slices.Sort(known)
+			return nil, fmt.Errorf("unknown tool %q in enabled_tools, available: %s", name, strings.Join(known, ", "))
+		}
+		res = append(res, t)
+	}
+	return res, nil
+}
+
+func splitList(s string) []string {
+	var res []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			res = append(res, item)
+		}
+	}
+	return res
+}
@@ -0,0 +1,169 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"context"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func writeConfig(t *testing.T, name, content string) string {
+	path := filepath.Join(t.TempDir(), name)
+	assert.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	return path
+}
+
+func setFlag(t *testing.T, name, value string) {
+	old := flag.Lookup(name).Value.String()
+	assert.NoError(t, flag.Set(name, value))
+	t.Cleanup(func() { _ = flag.Set(name, old) })
+}
+
+func TestLoadConfig(t *testing.T) {
+	noEnv := func(string) string { return "" }
+
+	// 没有配置文件时就是 flag 的默认值
+	cfg, err := LoadConfig("", nil, noEnv)
+	assert.NoError(t, err)
+	assert.Equal(t, providerDeepSeek, cfg.Provider)
+	assert.Equal(t, "deepseek-chat", cfg.Model)
+	assert.Nil(t, cfg.Temperature)
+	assert.Equal(t, 0.5, cfg.FailureRate)
+	assert.True(t, cfg.Logging.RedactPII)
+
+	path := writeConfig(t, "config.yaml", `
+provider: mock
+api_key: from-file
+temperature: 0.3
+enabled_tools: [query_dishes]
+max_steps: 20
+logging:
+  verbose: true
+`)
+	cfg, err = LoadConfig(path, nil, noEnv)
+	assert.NoError(t, err)
+	assert.True(t, cfg.Mock())
+	assert.Equal(t, "from-file", cfg.APIKey)
+	assert.Equal(t, float32(0.3), *cfg.Temperature)
+	assert.Equal(t, []string{"query_dishes"}, cfg.EnabledTools)
+	assert.Equal(t, 20, cfg.MaxSteps)
+	assert.True(t, cfg.Logging.Verbose)
+	// 文件中没有的字段保留默认值, mock 没有默认的 model
+	assert.Empty(t, cfg.Model)
+	assert.True(t, cfg.Logging.RedactPII)
+
+	// EINO_MAX_STEPS 覆盖文件中的 max_steps
+	cfg, err = LoadConfig(path, nil, func(key string) string {
+		if key == maxStepsEnv {
+			return "8"
+		}
+		return ""
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 8, cfg.MaxSteps)
+	_, err = LoadConfig(path, nil, func(key string) string {
+		if key == maxStepsEnv {
+			return "many"
+		}
+		return ""
+	})
+	assert.ErrorContains(t, err, `invalid EINO_MAX_STEPS "many"`)
+
+	// 环境变量覆盖文件, 命令行上显式指定的 flag 覆盖两者
+	setFlag(t, "max-steps", "30")
+	setFlag(t, "mock", "false")
+	cfg, err = LoadConfig(path, map[string]bool{"max-steps": true, "mock": true}, func(key string) string {
+		switch key {
+		case "DEEPSEEK_API_KEY":
+			return "from-env"
+		case maxStepsEnv:
+			return "40"
+		}
+		return ""
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "from-env", cfg.APIKey)
+	assert.Equal(t, 30, cfg.MaxSteps)
+	assert.Equal(t, providerDeepSeek, cfg.Provider)
+	assert.Equal(t, []string{"query_dishes"}, cfg.EnabledTools)
+
+	// 写错的字段名是错误
+	_, err = LoadConfig(writeConfig(t, "config.json", `{"provider": "mock", "modle": "x"}`), nil, noEnv)
+	assert.ErrorContains(t, err, `unknown field "modle"`)
+	_, err = LoadConfig(writeConfig(t, "config.yml", "max_step: 3\n"), nil, noEnv)
+	assert.ErrorContains(t, err, "max_step")
+	_, err = LoadConfig(writeConfig(t, "config.json", `{"failure_rate": 2}`), nil, noEnv)
+	assert.ErrorContains(t, err, "failure_rate")
+	_, err = LoadConfig(filepath.Join(t.TempDir(), "missing.yaml"), nil, noEnv)
+	assert.ErrorContains(t, err, "failed to read config")
+
+	// 空文件等于没有配置
+	_, err = LoadConfig(writeConfig(t, "empty.yaml", ""), nil, noEnv)
+	assert.NoError(t, err)
+}
+
+func TestConfigValidate(t *testing.T) {
+	valid := func() *Config { return &Config{Provider: providerDeepSeek, Model: "deepseek-chat"} }
+	assert.NoError(t, valid().Validate())
+
+	hot := float32(2.5)
+	for _, mutate := range []func(c *Config){
+		func(c *Config) { c.Provider = "claude" },
+		func(c *Config) { c.Model = "" },
+		func(c *Config) { c.Temperature = &hot },
+		func(c *Config) { c.FailureRate = -0.1 },
+		func(c *Config) { c.MaxSteps = -1 },
+		func(c *Config) { c.Logging.OTelExporter = "jaeger" },
+	} {
+		c := valid()
+		mutate(c)
+		assert.Error(t, c.Validate())
+	}
+
+	// mock 不需要 model
+	assert.NoError(t, (&Config{Provider: providerMock}).Validate())
+}
+
+func TestConfigString(t *testing.T) {
+	cfg := &Config{Provider: providerDeepSeek, Model: "deepseek-chat", APIKey: "sk-secret", FailureRate: 0.5}
+	s := cfg.String()
+	assert.Contains(t, s, "provider=deepseek model=deepseek-chat api_key=set temperature=default tools=all failure_rate=0.5 max_steps=default")
+	assert.NotContains(t, s, "sk-secret")
+}
+
+func TestSelectTools(t *testing.T) {
+	ctx := context.Background()
+	all := defaultTools()
+
+	selected, err := selectTools(ctx, all, nil)
+	assert.NoError(t, err)
+	assert.Nil(t, selected)
+
+	selected, err = selectTools(ctx, all, []string{"query_dishes", "query_restaurants"})
+	assert.NoError(t, err)
+	names, err := toolNames(ctx, selected)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"query_dishes", "query_restaurants"}, names)
+
+	_, err = selectTools(ctx, all, []string{"query_dishes", "order_pizza"})
+	assert.ErrorContains(t, err, `unknown tool "order_pizza"`)
+}
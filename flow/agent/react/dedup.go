@@ -0,0 +1,50 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cloudwego/eino/callbacks"
+	"github.com/cloudwego/eino/compose"
+
+	"github.com/cloudwego/eino-examples/flow/agent/react/tools"
+)
+
+// dedupHandler 在每次进入 ToolsNode (模型的一条 assistant 消息里的全部 tool call) 时创建新的 tools.ToolCallDedup,
+// 配合 tools.DedupMiddleware 合并这一轮中完全相同的调用, 结束时打印合并了哪些调用.
+func dedupHandler() callbacks.Handler {
+	return callbacks.NewHandlerBuilder().
+		OnStartFn(func(ctx context.Context, info *callbacks.RunInfo, input callbacks.CallbackInput) context.Context {
+			if info.Component != compose.ComponentOfToolsNode {
+				return ctx
+			}
+			return tools.WithToolCallDedup(ctx)
+		}).
+		OnEndFn(func(ctx context.Context, info *callbacks.RunInfo, output callbacks.CallbackOutput) context.Context {
+			if info.Component != compose.ComponentOfToolsNode {
+				return ctx
+			}
+			if dedup := tools.ToolCallDedupFrom(ctx); dedup != nil {
+				for _, d := range dedup.Duplicates() {
+					fmt.Printf("[DEDUP] %s called %d times in one turn, executed once\n", d.Key, d.Calls)
+				}
+			}
+			return ctx
+		}).Build()
+}
@@ -0,0 +1,129 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// diagnostics 是致命错误时打印的诊断信息, 第一次运行示例时常见的问题 (没有 API key、key 无效、网络不通)
+// 都能从这里直接看出来, 不需要去翻日志.
+type diagnostics struct {
+	Err         error
+	Provider    string
+	Mock        bool
+	APIKeySet   bool
+	Tools       int
+	Class       string
+	Remediation string
+}
+
+// newDiagnostics 根据错误和运行配置分类. 分类在 classifyModelError 的基础上区分出两种最常见的配置问题:
+// 没有设置 API key (config) 和 key 被拒绝 (auth).
+func newDiagnostics(err error, mock, apiKeySet bool, tools int) diagnostics {
+	d := diagnostics{Err: err, Mock: mock, APIKeySet: apiKeySet, Tools: tools, Provider: "deepseek (deepseek-chat)"}
+	if mock {
+		d.Provider = "mock (scripted chat model)"
+	}
+
+	switch {
+	case !mock && !apiKeySet:
+		d.Class = "config"
+		d.Remediation = "set DEEPSEEK_API_KEY, or run with -mock to try the example without an API key"
+	case !mock && isAuthError(err):
+		d.Class = "auth"
+		d.Remediation = "the API key was rejected, check that DEEPSEEK_API_KEY is valid and has quota"
+	default:
+		d.Class = classifyModelError(err).String()
+		switch d.Class {
+		case "transient":
+			d.Remediation = "the provider or the network is temporarily unavailable, try again later or raise -model-retries"
+		case "canceled":
+			if errors.Is(err, context.DeadlineExceeded) {
+				d.Remediation = describeContextError(err) + ", the model provider or a tool was too slow; check the network and try again"
+			} else {
+				d.Remediation = describeContextError(err) + ", nothing to fix; run again to continue"
+			}
+		default:
+			d.Remediation = "see the error above; run with -mock to check whether the problem is in the model provider"
+		}
+	}
+	return d
+}
+
+// describeContextError 区分 ctx 被取消和超时: 前者通常是用户按了 Ctrl+C, 后者是某一步太慢, 原因和处理方法都不同.
+// err 不是 context 错误时返回空字符串.
+func describeContextError(err error) string {
+	switch {
+	case errors.Is(err, context.Canceled):
+		return "operation cancelled by user"
+	case errors.Is(err, context.DeadlineExceeded):
+		return "operation timed out"
+	}
+	return ""
+}
+
+// isAuthError 表示错误是否是 HTTP 401 或 403.
+func isAuthError(err error) bool {
+	if err == nil {
+		return false
+	}
+	var withStatus interface{ StatusCode() int }
+	if errors.As(err, &withStatus) {
+		code := withStatus.StatusCode()
+		return code == 401 || code == 403
+	}
+	if m := statusCodePattern.FindStringSubmatch(err.Error()); m != nil {
+		code, _ := strconv.Atoi(m[1])
+		return code == 401 || code == 403
+	}
+	return false
+}
+
+func (d diagnostics) String() string {
+	var sb strings.Builder
+	sb.WriteString("[DIAGNOSTICS] fatal error\n")
+	fmt.Fprintf(&sb, "  error:          %v\n", d.Err)
+	fmt.Fprintf(&sb, "  classification: %s\n", d.Class)
+	fmt.Fprintf(&sb, "  provider:       %s\n", d.Provider)
+	fmt.Fprintf(&sb, "  mock mode:      %t\n", d.Mock)
+	if !d.Mock {
+		fmt.Fprintf(&sb, "  api key set:    %t\n", d.APIKeySet)
+	}
+	fmt.Fprintf(&sb, "  tools:          %d registered\n", d.Tools)
+	fmt.Fprintf(&sb, "  remediation:    %s\n", d.Remediation)
+	return sb.String()
+}
+
+// dumpDiagnostics 把 err 的诊断信息打印到 stderr, 用于 main 中无法继续运行的错误.
+func dumpDiagnostics(err error) {
+	tools := len(defaultTools())
+	if *maxTools > 0 {
+		tools = min(tools, *maxTools)
+	}
+	mock, apiKeySet := *mockModel, os.Getenv("DEEPSEEK_API_KEY") != ""
+	if effectiveConfig != nil {
+		mock, apiKeySet = effectiveConfig.Mock(), effectiveConfig.APIKey != ""
+	}
+	d := newDiagnostics(err, mock, apiKeySet, tools)
+	fmt.Fprint(os.Stderr, d)
+}
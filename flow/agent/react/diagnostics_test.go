@@ -0,0 +1,80 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/cloudwego/eino/callbacks"
+	"github.com/cloudwego/eino/components"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewDiagnostics(t *testing.T) {
+	cases := []struct {
+		err       error
+		mock      bool
+		apiKeySet bool
+		class     string
+	}{
+		{err: errors.New("status code: 401, unauthorized"), class: "config"},
+		{err: errors.New("status code: 401, unauthorized"), apiKeySet: true, class: "auth"},
+		{err: errors.New("status code: 503, service unavailable"), apiKeySet: true, class: "transient"},
+		{err: context.Canceled, apiKeySet: true, class: "canceled"},
+		{err: errors.New("invalid prompt template"), mock: true, class: "permanent"},
+	}
+	for _, c := range cases {
+		d := newDiagnostics(c.err, c.mock, c.apiKeySet, 12)
+		assert.Equal(t, c.class, d.Class, c.err.Error())
+		assert.NotEmpty(t, d.Remediation)
+	}
+
+	d := newDiagnostics(fmt.Errorf("generate: %w", context.DeadlineExceeded), true, false, 12)
+	assert.Equal(t, "canceled", d.Class)
+	assert.Contains(t, d.Remediation, "operation timed out")
+	d = newDiagnostics(context.Canceled, true, false, 12)
+	assert.Contains(t, d.Remediation, "operation cancelled by user")
+
+	out := newDiagnostics(errors.New("boom"), true, false, 12).String()
+	assert.Contains(t, out, "[DIAGNOSTICS] fatal error\n")
+	assert.Contains(t, out, "error:          boom\n")
+	assert.Contains(t, out, "provider:       mock (scripted chat model)\n")
+	assert.Contains(t, out, "mock mode:      true\n")
+	assert.Contains(t, out, "tools:          12 registered\n")
+	assert.NotContains(t, out, "api key set")
+
+	out = newDiagnostics(errors.New("boom"), false, false, 12).String()
+	assert.Contains(t, out, "api key set:    false\n")
+	assert.Contains(t, out, "DEEPSEEK_API_KEY")
+}
+
+func TestDescribeContextError(t *testing.T) {
+	assert.Equal(t, "operation cancelled by user", describeContextError(fmt.Errorf("run: %w", context.Canceled)))
+	assert.Equal(t, "operation timed out", describeContextError(fmt.Errorf("run: %w", context.DeadlineExceeded)))
+	assert.Empty(t, describeContextError(errors.New("boom")))
+	assert.Empty(t, describeContextError(nil))
+
+	var buf strings.Builder
+	logger := &LoggerCallback{Out: &buf}
+	info := &callbacks.RunInfo{Component: components.ComponentOfChatModel, Type: "mock", Name: "model"}
+	logger.OnError(context.Background(), info, fmt.Errorf("generate: %w", context.DeadlineExceeded))
+	assert.Contains(t, buf.String(), `level=ERROR msg="component failed" component=ChatModel type=mock name=model error="generate: context deadline exceeded" reason="operation timed out"`)
+}
@@ -0,0 +1,256 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/cloudwego/eino/callbacks"
+	"github.com/cloudwego/eino/components"
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/components/tool"
+	"github.com/cloudwego/eino/schema"
+)
+
+// defaultEventBuffer 是 EventCallback 默认的 channel 缓冲大小.
+const defaultEventBuffer = 64
+
+// Event 是 EventCallback 发出的结构化事件, 具体类型为 ToolStarted、ToolFinished 或 ModelContentDelta.
+// 嵌入 agent 的程序用 type switch 处理事件, 不需要解析日志.
+type Event interface {
+	isEvent()
+}
+
+// ToolStarted 在 tool 开始执行时发出.
+type ToolStarted struct {
+	Tool      string
+	Arguments string
+	At        time.Time
+}
+
+// ToolFinished 在 tool 执行结束时发出, 执行出错时 Err 不为空.
+// safeTool 把错误转成了 content, 所以大多数 tool 的失败体现在 Result 中, 只有 -strict 下才会有 Err.
+type ToolFinished struct {
+	Tool     string
+	Result   string
+	Err      error
+	Duration time.Duration
+}
+
+// ModelContentDelta 是模型输出的一段回答. 流式调用每帧一个, 非流式调用整个回答一个.
+type ModelContentDelta struct {
+	Content string
+}
+
+func (ToolStarted) isEvent()       {}
+func (ToolFinished) isEvent()      {}
+func (ModelContentDelta) isEvent() {}
+
+// EventCallback 把 agent 执行过程中的事件发到一个带缓冲的 channel 中.
+// 发送永远不会阻塞 agent: channel 满了 (消费者太慢) 时直接丢弃新的事件并计入 Dropped,
+// 而不是无限增长缓冲, 所以消费者不能假设收到了每一帧, 需要完整回答时应使用 agent 的返回值.
+// 运行结束后调用 Close, 它会等所有流读完再关闭 channel, 消费者 range channel 即可读到最后一个事件.
+type EventCallback struct {
+	events  chan Event
+	dropped atomic.Int64
+
+	mu     sync.Mutex
+	closed bool
+	wg     sync.WaitGroup // 跟踪 OnEndWithStreamOutput 中启动的 goroutine
+}
+
+type eventStartKey struct{}
+
+// NewEventCallback 创建一个 channel 缓冲为 buffer 的 EventCallback, buffer <= 0 时使用 defaultEventBuffer.
+func NewEventCallback(buffer int) *EventCallback {
+	if buffer <= 0 {
+		buffer = defaultEventBuffer
+	}
+	return &EventCallback{events: make(chan Event, buffer)}
+}
+
+// Events 返回事件 channel, Close 之后会被关闭.
+func (c *EventCallback) Events() <-chan Event {
+	return c.events
+}
+
+// Dropped 返回因为 channel 已满而丢弃的事件数.
+func (c *EventCallback) Dropped() int64 {
+	return c.dropped.Load()
+}
+
+// Close 等待所有流读完后关闭 channel, 之后的事件会被忽略. 可以重复调用.
+func (c *EventCallback) Close() {
+	c.wg.Wait()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.closed {
+		c.closed = true
+		close(c.events)
+	}
+}
+
+func (c *EventCallback) emit(ev Event) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.closed {
+		return
+	}
+	select {
+	case c.events <- ev:
+	default:
+		c.dropped.Add(1)
+	}
+}
+
+func (c *EventCallback) OnStart(ctx context.Context, info *callbacks.RunInfo, input callbacks.CallbackInput) context.Context {
+	if info.Component != components.ComponentOfTool {
+		return ctx
+	}
+	ev := ToolStarted{Tool: info.Name, At: time.Now()}
+	if tci := tool.ConvCallbackInput(input); tci != nil {
+		ev.Arguments = tci.ArgumentsInJSON
+	}
+	c.emit(ev)
+	return context.WithValue(ctx, eventStartKey{}, ev.At)
+}
+
+func (c *EventCallback) OnEnd(ctx context.Context, info *callbacks.RunInfo, output callbacks.CallbackOutput) context.Context {
+	switch info.Component {
+	case components.ComponentOfTool:
+		ev := ToolFinished{Tool: info.Name, Duration: sinceStart(ctx)}
+		if tco := tool.ConvCallbackOutput(output); tco != nil {
+			ev.Result = tco.Response
+		}
+		c.emit(ev)
+	case components.ComponentOfChatModel:
+		if mco := model.ConvCallbackOutput(output); mco != nil && mco.Message != nil && mco.Message.Content != "" {
+			c.emit(ModelContentDelta{Content: mco.Message.Content})
+		}
+	}
+	return ctx
+}
+
+func (c *EventCallback) OnError(ctx context.Context, info *callbacks.RunInfo, err error) context.Context {
+	if info.Component == components.ComponentOfTool {
+		c.emit(ToolFinished{Tool: info.Name, Err: err, Duration: sinceStart(ctx)})
+	}
+	return ctx
+}
+
+func (c *EventCallback) OnStartWithStreamInput(ctx context.Context, info *callbacks.RunInfo,
+	input *schema.StreamReader[callbacks.CallbackInput]) context.Context {
+	input.Close()
+	return ctx
+}
+
+func (c *EventCallback) OnEndWithStreamOutput(ctx context.Context, info *callbacks.RunInfo,
+	output *schema.StreamReader[callbacks.CallbackOutput]) context.Context {
+	switch info.Component {
+	case components.ComponentOfChatModel:
+		c.wg.Add(1)
+		go func() {
+			defer c.wg.Done()
+			defer output.Close()
+			for {
+				frame, err := output.Recv()
+				if err != nil {
+					return
+				}
+				if mco := model.ConvCallbackOutput(frame); mco != nil && mco.Message != nil && mco.Message.Content != "" {
+					c.emit(ModelContentDelta{Content: mco.Message.Content})
+				}
+			}
+		}()
+	case components.ComponentOfTool:
+		// 流式 tool 的结果拼接完整后作为一个 ToolFinished 发出
+		c.wg.Add(1)
+		go func() {
+			defer c.wg.Done()
+			defer output.Close()
+			var sb strings.Builder
+			for {
+				frame, err := output.Recv()
+				if err != nil {
+					ev := ToolFinished{Tool: info.Name, Result: sb.String(), Duration: sinceStart(ctx)}
+					if !errors.Is(err, io.EOF) {
+						ev.Err = err
+					}
+					c.emit(ev)
+					return
+				}
+				if tco := tool.ConvCallbackOutput(frame); tco != nil {
+					sb.WriteString(tco.Response)
+				}
+			}
+		}()
+	default:
+		output.Close()
+	}
+	return ctx
+}
+
+func sinceStart(ctx context.Context) time.Duration {
+	if start, ok := ctx.Value(eventStartKey{}).(time.Time); ok {
+		return time.Since(start)
+	}
+	return 0
+}
+
+// eventSummary 是 main 中消费事件 channel 得到的实时汇总.
+type eventSummary struct {
+	started, finished, failed int
+	running                   map[string]int // tool 名 => 正在执行的调用数
+	contentBytes              int
+}
+
+func (s *eventSummary) apply(ev Event) {
+	if s.running == nil {
+		s.running = map[string]int{}
+	}
+	switch ev := ev.(type) {
+	case ToolStarted:
+		s.started++
+		s.running[ev.Tool]++
+	case ToolFinished:
+		s.finished++
+		if ev.Err != nil {
+			s.failed++
+		}
+		if s.running[ev.Tool]--; s.running[ev.Tool] <= 0 {
+			delete(s.running, ev.Tool)
+		}
+	case ModelContentDelta:
+		s.contentBytes += len(ev.Content)
+	}
+}
+
+func (s *eventSummary) String() string {
+	res := fmt.Sprintf("tools started %d, finished %d (%d failed), answer %d bytes", s.started, s.finished, s.failed, s.contentBytes)
+	if len(s.running) > 0 {
+		res += ", running: " + strings.Join(sortedKeys(s.running), ", ")
+	}
+	return res
+}
@@ -0,0 +1,104 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/cloudwego/eino/components/tool"
+	"github.com/cloudwego/eino/compose"
+	"github.com/cloudwego/eino/flow/agent"
+	"github.com/cloudwego/eino/schema"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEventCallback(t *testing.T) {
+	ctx := context.Background()
+
+	for _, stream := range []bool{false, true} {
+		ragent, err := newAgent(ctx, newScriptedModel(
+			toolCallMessage("call_1", "sleepy", `{"sleep_ms": 1}`),
+			schema.AssistantMessage("done", nil),
+		), []tool.BaseTool{&sleepyTool{}}, 0, 0)
+		assert.NoError(t, err)
+
+		c := NewEventCallback(0)
+		opt := agent.WithComposeOptions(compose.WithCallbacks(c))
+		messages := []*schema.Message{schema.UserMessage("hi")}
+		if stream {
+			_, err = runStream(ctx, ragent, messages, &LoggerCallback{Out: &strings.Builder{}}, opt)
+		} else {
+			_, err = ragent.Generate(ctx, messages, opt)
+		}
+		assert.NoError(t, err)
+		c.Close()
+
+		var got []Event
+		for ev := range c.Events() {
+			got = append(got, ev)
+		}
+		if !assert.Len(t, got, 3, "stream=%v", stream) {
+			continue
+		}
+		started, ok := got[0].(ToolStarted)
+		assert.True(t, ok)
+		assert.Equal(t, "sleepy", started.Tool)
+		assert.Equal(t, `{"sleep_ms": 1}`, started.Arguments)
+
+		finished, ok := got[1].(ToolFinished)
+		assert.True(t, ok)
+		assert.Equal(t, "sleepy", finished.Tool)
+		assert.NoError(t, finished.Err)
+		assert.Positive(t, finished.Duration)
+
+		assert.Equal(t, ModelContentDelta{Content: "done"}, got[2])
+		assert.Zero(t, c.Dropped())
+
+		// Close 之后的事件被忽略, 重复 Close 不会 panic
+		c.emit(ModelContentDelta{Content: "late"})
+		c.Close()
+	}
+}
+
+func TestEventCallbackDropsWhenFull(t *testing.T) {
+	c := NewEventCallback(1)
+	// 没有消费者时发送也不会阻塞
+	for i := 0; i < 3; i++ {
+		c.emit(ModelContentDelta{Content: "x"})
+	}
+	assert.Equal(t, int64(2), c.Dropped())
+
+	c.Close()
+	assert.Equal(t, ModelContentDelta{Content: "x"}, <-c.Events())
+	_, ok := <-c.Events()
+	assert.False(t, ok)
+}
+
+func TestEventSummary(t *testing.T) {
+	s := &eventSummary{}
+	s.apply(ToolStarted{Tool: "query_restaurants"})
+	s.apply(ToolStarted{Tool: "query_dishes"})
+	assert.Equal(t, "tools started 2, finished 0 (0 failed), answer 0 bytes, running: query_dishes, query_restaurants", s.String())
+
+	s.apply(ToolFinished{Tool: "query_restaurants"})
+	s.apply(ToolFinished{Tool: "query_dishes", Err: errors.New("boom")})
+	s.apply(ModelContentDelta{Content: "hello"})
+	assert.Equal(t, "tools started 2, finished 2 (1 failed), answer 5 bytes", s.String())
+}
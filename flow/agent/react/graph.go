@@ -0,0 +1,130 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/components/tool"
+	"github.com/cloudwego/eino/compose"
+	"github.com/cloudwego/eino/flow/agent"
+	"github.com/cloudwego/eino/schema"
+)
+
+// graphMaxSteps 限制 ReAct 循环中执行的节点数, 每轮 tool 调用占两步 (chat_model + tools), 超过后 Invoke 返回错误.
+const graphMaxSteps = 25
+
+const (
+	graphNodeChatModel = "chat_model"
+	graphNodeTools     = "tools"
+)
+
+// graphState 是整个 ReAct 循环共享的状态, 保存到目前为止的全部消息.
+type graphState struct {
+	Messages []*schema.Message
+}
+
+// newGraphAgent 用 compose.Graph 手动搭建一个和 react.NewAgent 等价的 ReAct 循环, 展示预置 agent 内部做了什么:
+//
+//	START -> chat_model -(有 tool call)-> tools -> chat_model -> ... -(没有 tool call)-> END
+//
+// chat_model 和 tools 两个节点通过 state pre handler 把各自的输入追加到 graphState 中,
+// 所以每次调用模型时看到的都是完整的对话, 而不只是上一个节点的输出.
+func newGraphAgent(ctx context.Context, chatModel model.ToolCallingChatModel, agentTools []tool.BaseTool) (compose.Runnable[[]*schema.Message, *schema.Message], error) {
+	infos := make([]*schema.ToolInfo, 0, len(agentTools))
+	for _, t := range agentTools {
+		info, err := t.Info(ctx)
+		if err != nil {
+			return nil, err
+		}
+		infos = append(infos, info)
+	}
+	boundModel, err := chatModel.WithTools(infos)
+	if err != nil {
+		return nil, fmt.Errorf("failed to bind tools: %w", err)
+	}
+	toolsNode, err := compose.NewToolNode(ctx, &compose.ToolsNodeConfig{Tools: agentTools})
+	if err != nil {
+		return nil, err
+	}
+
+	g := compose.NewGraph[[]*schema.Message, *schema.Message](compose.WithGenLocalState(func(ctx context.Context) *graphState {
+		return &graphState{}
+	}))
+
+	// 第一次输入是 system + user, 之后是 tools 节点返回的 tool 结果
+	err = g.AddChatModelNode(graphNodeChatModel, boundModel, compose.WithStatePreHandler(
+		func(ctx context.Context, input []*schema.Message, state *graphState) ([]*schema.Message, error) {
+			state.Messages = append(state.Messages, input...)
+			return state.Messages, nil
+		}))
+	if err != nil {
+		return nil, err
+	}
+	// 输入是模型返回的带 tool call 的 assistant 消息
+	err = g.AddToolsNode(graphNodeTools, toolsNode, compose.WithStatePreHandler(
+		func(ctx context.Context, input *schema.Message, state *graphState) (*schema.Message, error) {
+			state.Messages = append(state.Messages, input)
+			return input, nil
+		}))
+	if err != nil {
+		return nil, err
+	}
+
+	if err = g.AddEdge(compose.START, graphNodeChatModel); err != nil {
+		return nil, err
+	}
+	err = g.AddBranch(graphNodeChatModel, compose.NewGraphBranch(
+		func(ctx context.Context, msg *schema.Message) (string, error) {
+			if len(msg.ToolCalls) > 0 {
+				return graphNodeTools, nil
+			}
+			return compose.END, nil
+		}, map[string]bool{graphNodeTools: true, compose.END: true}))
+	if err != nil {
+		return nil, err
+	}
+	if err = g.AddEdge(graphNodeTools, graphNodeChatModel); err != nil {
+		return nil, err
+	}
+
+	// chat_model 有两个前驱 (START 和 tools), 任意一个完成就触发
+	return g.Compile(ctx,
+		compose.WithGraphName("GraphReActAgent"),
+		compose.WithMaxRunSteps(graphMaxSteps),
+		compose.WithNodeTriggerMode(compose.AnyPredecessor))
+}
+
+// RunGraph 和 Run 一样回答 userMessage, 但使用 newGraphAgent 手动搭建的 graph 而不是 react.NewAgent.
+func (r *AgentRunner) RunGraph(ctx context.Context, userMessage string) (string, error) {
+	runnable, err := newGraphAgent(ctx, r.chatModel, r.tools)
+	if err != nil {
+		return "", fmt.Errorf("failed to build graph: %w", err)
+	}
+
+	messages := []*schema.Message{schema.SystemMessage(r.systemPrompt), schema.UserMessage(userMessage)}
+	msg, err := runnable.Invoke(ctx, messages, agent.GetComposeOptions(r.opts...)...)
+	if err != nil {
+		return "", err
+	}
+	if isEmptyAnswer(msg) {
+		return "", errEmptyAnswer
+	}
+	return msg.Content, nil
+}
@@ -0,0 +1,45 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cloudwego/eino/callbacks"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAgentRunnerRunGraph(t *testing.T) {
+	ctx := context.Background()
+	script := defaultMockScript()
+
+	recorder := &toolRecorder{}
+	runner, err := NewAgentRunner(ctx, &AgentRunnerConfig{
+		ChatModel:  newScriptedModel(script...),
+		PromptVars: map[string]string{"City": "北京"},
+		Handlers:   []callbacks.Handler{recorder.handler()},
+	})
+	assert.NoError(t, err)
+	defer runner.Close(ctx)
+
+	// 和 react.NewAgent 一样: 依次执行剧本中的 3 个 tool call, 再返回最后的回答
+	content, err := runner.RunGraph(ctx, "我在北京，给我推荐一些辣的菜")
+	assert.NoError(t, err)
+	assert.Equal(t, script[len(script)-1].Content, content)
+	assert.Equal(t, []string{"query_restaurants", "query_dishes", "query_dishes"}, recorder.calls())
+}
@@ -0,0 +1,134 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package testutil 提供测试 tool 结果的断言: tool 返回的是 JSON 字符串, 按路径取字段比 strings.Contains 更准确,
+// 也不需要在每个测试里定义结构体再 json.Unmarshal.
+package testutil
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestingT 是断言需要的 *testing.T 的子集.
+type TestingT interface {
+	Errorf(format string, args ...any)
+	Helper()
+}
+
+// AssertJSONField 断言 JSON 结果 result 中 path 处的值等于 expected. path 由 "." 分隔的字段名和 "[i]" 形式的下标组成,
+// 比如 "dishes[0].name", 结果本身是列表时以下标开头, 比如 "[0].id".
+// expected 先按 JSON 序列化再解析, 所以 int 可以和 JSON 中的数字比较, 结构体按 json tag 比较.
+func AssertJSONField(t TestingT, result, path string, expected any, msgAndArgs ...any) bool {
+	t.Helper()
+
+	actual, err := lookup(result, path)
+	if err != nil {
+		return assert.Fail(t, err.Error(), msgAndArgs...)
+	}
+	b, err := json.Marshal(expected)
+	if err != nil {
+		return assert.Fail(t, fmt.Sprintf("cannot marshal expected value: %v", err), msgAndArgs...)
+	}
+	var want any
+	_ = json.Unmarshal(b, &want)
+	return assert.Equal(t, want, actual, append([]any{fmt.Sprintf("field %s", path)}, msgAndArgs...)...)
+}
+
+// AssertToolError 断言 result 是 tool 返回给模型的结构化错误 (JSON 对象, error 字段说明错误的类型, message 说明原因),
+// 并且 error 等于 wantType, 比如 "missing required argument" 或 "arguments rejected".
+func AssertToolError(t TestingT, result, wantType string, msgAndArgs ...any) bool {
+	t.Helper()
+
+	var e struct {
+		Error   *string `json:"error"`
+		Message string  `json:"message"`
+	}
+	if err := json.Unmarshal([]byte(result), &e); err != nil || e.Error == nil {
+		return assert.Fail(t, fmt.Sprintf("not a tool error: %s", result), msgAndArgs...)
+	}
+	if !assert.Equal(t, wantType, *e.Error, msgAndArgs...) {
+		return false
+	}
+	return assert.NotEmpty(t, e.Message, append([]any{"tool error without message"}, msgAndArgs...)...)
+}
+
+// lookup 按 path 在 JSON 中逐层查找, 找不到时的错误说明停在了哪一段.
+func lookup(result, path string) (any, error) {
+	var v any
+	if err := json.Unmarshal([]byte(result), &v); err != nil {
+		return nil, fmt.Errorf("result is not JSON: %v", err)
+	}
+	steps, err := parsePath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := ""
+	for _, step := range steps {
+		switch cur := v.(type) {
+		case map[string]any:
+			field, ok := cur[step]
+			if !ok {
+				return nil, fmt.Errorf("field %q not found at %q", step, seen)
+			}
+			v = field
+		case []any:
+			if !strings.HasPrefix(step, "[") {
+				return nil, fmt.Errorf("%q is a list, not an object with field %q", seen, step)
+			}
+			i, _ := strconv.Atoi(strings.Trim(step, "[]"))
+			if i < 0 || i >= len(cur) {
+				return nil, fmt.Errorf("index %d out of range at %q, the list has %d items", i, seen, len(cur))
+			}
+			v = cur[i]
+		default:
+			return nil, fmt.Errorf("%q is %v, cannot look up %q", seen, cur, step)
+		}
+		if seen != "" && !strings.HasPrefix(step, "[") {
+			seen += "."
+		}
+		seen += step
+	}
+	return v, nil
+}
+
+// parsePath 把 "dishes[0].name" 拆成 ["dishes", "[0]", "name"].
+func parsePath(path string) ([]string, error) {
+	var steps []string
+	for _, part := range strings.Split(path, ".") {
+		name, rest, _ := strings.Cut(part, "[")
+		if name != "" {
+			steps = append(steps, name)
+		}
+		for rest != "" {
+			idx, after, ok := strings.Cut(rest, "]")
+			if _, err := strconv.Atoi(idx); !ok || err != nil || (after != "" && after[0] != '[') {
+				return nil, fmt.Errorf("invalid path %q", path)
+			}
+			steps = append(steps, "["+idx+"]")
+			rest = strings.TrimPrefix(after, "[")
+		}
+		if name == "" && !strings.Contains(part, "[") {
+			return nil, fmt.Errorf("invalid path %q", path)
+		}
+	}
+	return steps, nil
+}
@@ -0,0 +1,87 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package testutil
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// recorder 记录断言失败的信息, 不让外层的测试失败.
+type recorder struct {
+	errs []string
+}
+
+func (r *recorder) Errorf(format string, args ...any) {
+	r.errs = append(r.errs, fmt.Sprintf(format, args...))
+}
+
+func (r *recorder) Helper() {}
+
+func TestAssertJSONField(t *testing.T) {
+	result := `{"restaurant_id":"1001","dishes":[{"name":"红烧肉","price":38,"tags":["meat"]}],"open":true}`
+
+	r := &recorder{}
+	assert.True(t, AssertJSONField(r, result, "restaurant_id", "1001"))
+	assert.True(t, AssertJSONField(r, result, "dishes[0].name", "红烧肉"))
+	assert.True(t, AssertJSONField(r, result, "dishes[0].price", 38))
+	assert.True(t, AssertJSONField(r, result, "dishes[0].tags", []string{"meat"}))
+	assert.True(t, AssertJSONField(r, result, "open", true))
+	assert.True(t, AssertJSONField(r, `[{"id":"1001"}]`, "[0].id", "1001"))
+	assert.Empty(t, r.errs)
+
+	for path, want := range map[string]string{
+		"dishes[0].price":  "Not equal",
+		"dishes[1].name":   `index 1 out of range at "dishes"`,
+		"dishes[0].chef":   `field "chef" not found at "dishes[0]"`,
+		"dishes.name":      `"dishes" is a list`,
+		"open.at":          `"open" is true`,
+		"dishes[x]":        "invalid path",
+		"restaurant_id..x": "invalid path",
+	} {
+		r := &recorder{}
+		assert.False(t, AssertJSONField(r, result, path, 40), path)
+		if assert.Len(t, r.errs, 1, path) {
+			assert.Contains(t, r.errs[0], want, path)
+		}
+	}
+
+	r = &recorder{}
+	assert.False(t, AssertJSONField(r, "not json", "a", 1))
+	assert.Contains(t, r.errs[0], "not JSON")
+}
+
+func TestAssertToolError(t *testing.T) {
+	r := &recorder{}
+	assert.True(t, AssertToolError(r, `{"error":"arguments rejected","message":"sql_injection","retry":"false"}`, "arguments rejected"))
+	assert.Empty(t, r.errs)
+
+	for result, want := range map[string]string{
+		`{"error":"service degraded","message":"paused"}`: "Not equal",
+		`{"error":"arguments rejected"}`:                  "without message",
+		`{"results":[]}`:                                  "not a tool error",
+		`boom`:                                            "not a tool error",
+	} {
+		r := &recorder{}
+		assert.False(t, AssertToolError(r, result, "arguments rejected"), result)
+		if assert.Len(t, r.errs, 1, result) {
+			assert.Contains(t, r.errs[0], want, result)
+		}
+	}
+}
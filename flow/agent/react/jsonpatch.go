@@ -0,0 +1,147 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"encoding/json"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// jsonPatchOp 是 RFC 6902 的一个操作, 这里只会生成 add、remove 和 replace. remove 没有 value.
+type jsonPatchOp struct {
+	Op    string `json:"op"`
+	Path  string `json:"path"`
+	Value any    `json:"value,omitempty"`
+}
+
+// sseDocument 是 json-patch 格式下前端维护的结果文档, 初始为 {}, 依次应用每个 patch 事件后得到当前的状态.
+type sseDocument struct {
+	Status string        `json:"status"` // running, done 或 error
+	Answer string        `json:"answer"`
+	Tools  []sseToolCall `json:"tools"`
+	Error  string        `json:"error,omitempty"`
+	// DroppedEvents 同 done 事件, 只在结束时出现
+	DroppedEvents int64 `json:"dropped_events,omitempty"`
+}
+
+type sseToolCall struct {
+	Tool       string `json:"tool"`
+	Arguments  string `json:"arguments"`
+	Status     string `json:"status"` // running, done 或 error
+	Summary    string `json:"summary,omitempty"`
+	DurationMs int64  `json:"duration_ms,omitempty"`
+	Error      string `json:"error,omitempty"`
+}
+
+// jsonPatchStream 记录上一次发出的文档, 每次修改后只发出两者之间的差异.
+type jsonPatchStream struct {
+	doc  sseDocument
+	last any // 上一次发出的文档, 解码成 map[string]any 等 JSON 的通用类型
+}
+
+func newJSONPatchStream() *jsonPatchStream {
+	return &jsonPatchStream{doc: sseDocument{Status: "running", Tools: []sseToolCall{}}, last: map[string]any{}}
+}
+
+// update 用 fn 修改文档, 返回从上一次的文档变到现在需要的操作, 没有变化时返回空.
+func (s *jsonPatchStream) update(fn func(doc *sseDocument)) []jsonPatchOp {
+	fn(&s.doc)
+	b, _ := json.Marshal(s.doc)
+	var next any
+	_ = json.Unmarshal(b, &next)
+	ops := jsonPatchDiff("", s.last, next)
+	s.last = next
+	return ops
+}
+
+// apply 把 Event 反映到文档上. ToolFinished 没有 call id, 对应同名的第一个还在运行的调用.
+func (s *jsonPatchStream) apply(ev Event) []jsonPatchOp {
+	return s.update(func(doc *sseDocument) {
+		switch ev := ev.(type) {
+		case ToolStarted:
+			doc.Tools = append(doc.Tools, sseToolCall{Tool: ev.Tool, Arguments: ev.Arguments, Status: "running"})
+		case ToolFinished:
+			for i := range doc.Tools {
+				call := &doc.Tools[i]
+				if call.Tool != ev.Tool || call.Status != "running" {
+					continue
+				}
+				call.Status = "done"
+				call.Summary = summarizeForSSE(ev.Result)
+				call.DurationMs = ev.Duration.Milliseconds()
+				if ev.Err != nil {
+					call.Status = "error"
+					call.Error = ev.Err.Error()
+				}
+				break
+			}
+		case ModelContentDelta:
+			doc.Answer += ev.Content
+		}
+	})
+}
+
+// jsonPatchDiff 生成把 from 变成 to 的操作, 两者都是 JSON 解码得到的通用类型.
+// 对象逐个 key 比较, 数组逐个下标比较, 多出来的元素追加在末尾, 少了的从后往前删除; 其余的值不同时整个 replace.
+// RFC 6902 没有追加字符串的操作, 所以回答每变一次都要 replace 整个 /answer.
+func jsonPatchDiff(path string, from, to any) []jsonPatchOp {
+	switch to := to.(type) {
+	case map[string]any:
+		if from, ok := from.(map[string]any); ok {
+			var ops []jsonPatchOp
+			for _, key := range sortedKeys(from) {
+				if _, ok := to[key]; !ok {
+					ops = append(ops, jsonPatchOp{Op: "remove", Path: path + "/" + escapeJSONPointer(key)})
+				}
+			}
+			for _, key := range sortedKeys(to) {
+				p := path + "/" + escapeJSONPointer(key)
+				if old, ok := from[key]; ok {
+					ops = append(ops, jsonPatchDiff(p, old, to[key])...)
+				} else {
+					ops = append(ops, jsonPatchOp{Op: "add", Path: p, Value: to[key]})
+				}
+			}
+			return ops
+		}
+	case []any:
+		if from, ok := from.([]any); ok {
+			var ops []jsonPatchOp
+			for i := 0; i < len(from) && i < len(to); i++ {
+				ops = append(ops, jsonPatchDiff(path+"/"+strconv.Itoa(i), from[i], to[i])...)
+			}
+			for i := len(from); i < len(to); i++ {
+				ops = append(ops, jsonPatchOp{Op: "add", Path: path + "/-", Value: to[i]})
+			}
+			for i := len(from) - 1; i >= len(to); i-- {
+				ops = append(ops, jsonPatchOp{Op: "remove", Path: path + "/" + strconv.Itoa(i)})
+			}
+			return ops
+		}
+	}
+	if reflect.DeepEqual(from, to) {
+		return nil
+	}
+	return []jsonPatchOp{{Op: "replace", Path: path, Value: to}}
+}
+
+// escapeJSONPointer 按 RFC 6901 转义 JSON Pointer 中的一段: ~ 写成 ~0, / 写成 ~1.
+func escapeJSONPointer(s string) string {
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(s)
+}
@@ -0,0 +1,219 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// applyJSONPatch 是前端的参考实现: 按 RFC 6902 把 ops 依次应用到 doc 上, 只支持 add、remove 和 replace.
+func applyJSONPatch(doc any, ops []jsonPatchOp) (any, error) {
+	for _, op := range ops {
+		var err error
+		if doc, err = applyJSONPatchOp(doc, splitJSONPointer(op.Path), op); err != nil {
+			return nil, fmt.Errorf("%s %s: %w", op.Op, op.Path, err)
+		}
+	}
+	return doc, nil
+}
+
+func applyJSONPatchOp(doc any, path []string, op jsonPatchOp) (any, error) {
+	if len(path) == 0 {
+		if op.Op == "remove" {
+			return nil, errors.New("cannot remove the root")
+		}
+		return op.Value, nil
+	}
+	key, last := path[0], len(path) == 1
+	switch node := doc.(type) {
+	case map[string]any:
+		child, ok := node[key]
+		if last {
+			switch {
+			case op.Op == "remove" && !ok, op.Op == "replace" && !ok:
+				return nil, fmt.Errorf("no member %q", key)
+			case op.Op == "remove":
+				delete(node, key)
+			default:
+				node[key] = op.Value
+			}
+			return node, nil
+		}
+		if !ok {
+			return nil, fmt.Errorf("no member %q", key)
+		}
+		child, err := applyJSONPatchOp(child, path[1:], op)
+		node[key] = child
+		return node, err
+	case []any:
+		if last && key == "-" && op.Op == "add" {
+			return append(node, op.Value), nil
+		}
+		i, err := strconv.Atoi(key)
+		if err != nil || i < 0 || i > len(node) || (i == len(node) && op.Op != "add") {
+			return nil, fmt.Errorf("bad index %q", key)
+		}
+		if !last {
+			node[i], err = applyJSONPatchOp(node[i], path[1:], op)
+			return node, err
+		}
+		switch op.Op {
+		case "add":
+			return append(node[:i], append([]any{op.Value}, node[i:]...)...), nil
+		case "remove":
+			return append(node[:i], node[i+1:]...), nil
+		default:
+			node[i] = op.Value
+			return node, nil
+		}
+	}
+	return nil, fmt.Errorf("cannot descend into %T", doc)
+}
+
+func splitJSONPointer(path string) []string {
+	if path == "" {
+		return nil
+	}
+	parts := strings.Split(strings.TrimPrefix(path, "/"), "/")
+	for i, p := range parts {
+		parts[i] = strings.NewReplacer("~1", "/", "~0", "~").Replace(p)
+	}
+	return parts
+}
+
+// roundTrip 把 ops 编码再解码, 和前端收到的一样
+func roundTrip(t *testing.T, ops []jsonPatchOp) []jsonPatchOp {
+	b, err := json.Marshal(ops)
+	assert.NoError(t, err)
+	var res []jsonPatchOp
+	assert.NoError(t, json.Unmarshal(b, &res))
+	return res
+}
+
+func TestJSONPatchDiff(t *testing.T) {
+	decode := func(s string) any {
+		var v any
+		assert.NoError(t, json.Unmarshal([]byte(s), &v))
+		return v
+	}
+	cases := []struct{ from, to string }{
+		{`{}`, `{"a":1,"b":[1,2],"c":{"d":"x"}}`},
+		{`{"a":1,"b":[1,2,3],"c":{"d":"x"}}`, `{"a":2,"b":[1],"c":{"e":true}}`},
+		{`{"list":[{"s":"running"}]}`, `{"list":[{"s":"done","n":3},{"s":"running"}]}`},
+		{`{"a/b":1,"m~n":2}`, `{"a/b":3}`},
+		{`{"a":[1,2]}`, `{"a":"text"}`},
+		{`[1,2]`, `{"a":1}`},
+	}
+	for _, c := range cases {
+		from, to := decode(c.from), decode(c.to)
+		ops := jsonPatchDiff("", from, to)
+		got, err := applyJSONPatch(decode(c.from), roundTrip(t, ops))
+		assert.NoError(t, err, c.from)
+		assert.Equal(t, to, got, "%s -> %s", c.from, c.to)
+	}
+
+	assert.Empty(t, jsonPatchDiff("", decode(`{"a":[1,{"b":2}]}`), decode(`{"a":[1,{"b":2}]}`)))
+	assert.Equal(t, []jsonPatchOp{{Op: "remove", Path: "/m~0n"}, {Op: "replace", Path: "/a~1b", Value: 3.0}},
+		jsonPatchDiff("", decode(`{"a/b":1,"m~n":2}`), decode(`{"a/b":3}`)))
+}
+
+func TestJSONPatchStream(t *testing.T) {
+	s := newJSONPatchStream()
+	var doc any = map[string]any{}
+	apply := func(ops []jsonPatchOp) {
+		var err error
+		doc, err = applyJSONPatch(doc, roundTrip(t, ops))
+		assert.NoError(t, err)
+	}
+
+	apply(s.update(func(doc *sseDocument) {}))
+	apply(s.apply(ToolStarted{Tool: "query_dishes", Arguments: `{"restaurant_id":"1001"}`}))
+	apply(s.apply(ToolStarted{Tool: "query_dishes", Arguments: `{"restaurant_id":"1002"}`}))
+	ops := s.apply(ToolFinished{Tool: "query_dishes", Result: "红烧肉", Duration: 3 * time.Millisecond})
+	// 只改变第一个调用
+	assert.Equal(t, []jsonPatchOp{
+		{Op: "add", Path: "/tools/0/duration_ms", Value: 3.0},
+		{Op: "replace", Path: "/tools/0/status", Value: "done"},
+		{Op: "add", Path: "/tools/0/summary", Value: "红烧肉"},
+	}, ops)
+	apply(ops)
+	apply(s.apply(ToolFinished{Tool: "query_dishes", Err: errors.New("boom")}))
+	apply(s.apply(ModelContentDelta{Content: "推荐"}))
+	apply(s.apply(ModelContentDelta{Content: "红烧肉"}))
+	assert.Empty(t, s.apply(ModelContentDelta{}))
+	apply(s.update(func(doc *sseDocument) { doc.Status = "done" }))
+
+	want := map[string]any{
+		"status": "done",
+		"answer": "推荐红烧肉",
+		"tools": []any{
+			map[string]any{"tool": "query_dishes", "arguments": `{"restaurant_id":"1001"}`, "status": "done", "summary": "红烧肉", "duration_ms": 3.0},
+			map[string]any{"tool": "query_dishes", "arguments": `{"restaurant_id":"1002"}`, "status": "error", "error": "boom"},
+		},
+	}
+	assert.Equal(t, want, doc)
+}
+
+func TestSSEServerJSONPatch(t *testing.T) {
+	server := &sseServer{newRunner: newMockSSERunner}
+
+	rec := httptest.NewRecorder()
+	server.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/chat?format=json-patch&query="+url.QueryEscape("推荐辣的菜"), nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	// 从 {} 开始依次应用每个 patch 事件, 得到和 agent 的回答一致的最终文档
+	var doc any = map[string]any{}
+	var n int
+	for _, block := range strings.Split(strings.TrimSpace(rec.Body.String()), "\n\n") {
+		lines := strings.Split(block, "\n")
+		assert.Equal(t, "event: "+ssePatch, lines[0])
+		var ops []jsonPatchOp
+		assert.NoError(t, json.Unmarshal([]byte(strings.TrimPrefix(lines[1], "data: ")), &ops))
+		assert.NotEmpty(t, ops)
+		var err error
+		doc, err = applyJSONPatch(doc, ops)
+		assert.NoError(t, err)
+		n++
+	}
+	assert.Greater(t, n, 3)
+
+	final, ok := doc.(map[string]any)
+	if assert.True(t, ok) {
+		assert.Equal(t, "done", final["status"])
+		assert.Contains(t, final["answer"], "聚福轩食府")
+		calls := final["tools"].([]any)
+		assert.NotEmpty(t, calls)
+		for _, call := range calls {
+			assert.Equal(t, "done", call.(map[string]any)["status"])
+		}
+	}
+
+	rec = httptest.NewRecorder()
+	server.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/chat?format=xml&query=x", nil))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
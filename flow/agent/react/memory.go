@@ -0,0 +1,239 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/schema"
+)
+
+// ConversationMemory 保存多轮对话的历史消息 (不包括 system prompt), 包括中间的 tool call 和 tool 结果,
+// 下一轮对话会带上这些历史. 指定文件时每轮结束后都会持久化, 下次运行可以接着上次的对话继续.
+type ConversationMemory struct {
+	path     string
+	rollover *MemoryRollover
+
+	mu       sync.Mutex
+	messages []*schema.Message
+}
+
+// defaultRolloverPrompt 是 MemoryRollover 默认的摘要 prompt.
+const defaultRolloverPrompt = `下面是用户和餐厅推荐助手之前的对话记录, 请把它压缩成一段简洁的摘要, 保留用户的需求和偏好、提到过的餐厅 id 和名字、推荐过的菜品, 以及还没有解决的问题.`
+
+// rolloverNotePrefix 是摘要消息的开头, 摘要本身是历史中的第一条 system 消息.
+const rolloverNotePrefix = "之前对话的摘要:\n"
+
+// MemoryRollover 控制 ConversationMemory 的长度: 历史消息的条数或估算的 token 数超过上限时,
+// 调用模型把较早的几轮对话压缩成一条 system 消息, 替换掉原来的消息. system prompt 不在 memory 中, 不受影响.
+type MemoryRollover struct {
+	// Model 用来生成摘要.
+	Model model.BaseChatModel
+	// MaxMessages 是历史消息条数的上限, 0 表示不限制.
+	MaxMessages int
+	// MaxTokens 是按 estimateTokens 估算的 token 数的上限, 0 表示不限制.
+	MaxTokens int
+	// Prompt 是摘要使用的 system prompt, 为空时使用 defaultRolloverPrompt.
+	Prompt string
+	// KeepTurns 是保留原样不摘要的最近几轮对话, 小于 1 时按 1 处理.
+	KeepTurns int
+}
+
+// SetRollover 开启超过长度上限时的摘要, 传 nil 关闭. 需要在开始对话之前调用.
+func (m *ConversationMemory) SetRollover(r *MemoryRollover) {
+	m.rollover = r
+}
+
+// NewConversationMemory 创建一个只保存在内存中的 ConversationMemory.
+func NewConversationMemory() *ConversationMemory {
+	return &ConversationMemory{}
+}
+
+// LoadConversationMemory 从 JSON 文件加载历史消息, 文件不存在时从空的对话开始, 第一次 Append 时创建文件.
+func LoadConversationMemory(path string) (*ConversationMemory, error) {
+	m := &ConversationMemory{path: path}
+
+	b, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return m, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read session file: %w", err)
+	}
+	if err := json.Unmarshal(b, &m.messages); err != nil {
+		return nil, fmt.Errorf("failed to parse session file %s: %w", path, err)
+	}
+	return m, nil
+}
+
+// Messages 返回历史消息的副本.
+func (m *ConversationMemory) Messages() []*schema.Message {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]*schema.Message(nil), m.messages...)
+}
+
+// Append 追加一轮对话的消息, 有文件时立即持久化.
+func (m *ConversationMemory) Append(msgs ...*schema.Message) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.messages = append(m.messages, msgs...)
+	if m.path == "" {
+		return nil
+	}
+	return m.save()
+}
+
+// save 先写临时文件再 rename, 避免进程中途退出留下半个文件. 调用方需要持有 mu.
+func (m *ConversationMemory) save() error {
+	b, err := json.MarshalIndent(m.messages, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(m.path), filepath.Base(m.path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(b); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), m.path)
+}
+
+// Rollover 在历史超过 MemoryRollover 的上限时, 把最近 KeepTurns 轮之前的消息 (包括上一次的摘要) 压缩成一条新的摘要,
+// 有文件时立即持久化. 返回是否做了摘要; 没有开启、没有超过上限或者没有可以摘要的旧消息时什么都不做.
+// 调用模型时不持有锁, 同一时间只应有一个 Rollover.
+func (m *ConversationMemory) Rollover(ctx context.Context) (bool, error) {
+	r := m.rollover
+	if r == nil || r.Model == nil {
+		return false, nil
+	}
+
+	m.mu.Lock()
+	exceeded := (r.MaxMessages > 0 && len(m.messages) > r.MaxMessages) ||
+		(r.MaxTokens > 0 && estimateTokens(m.messages) > r.MaxTokens)
+	cut := rolloverCut(m.messages, max(r.KeepTurns, 1))
+	old := append([]*schema.Message(nil), m.messages[:cut]...)
+	m.mu.Unlock()
+	if !exceeded || cut == 0 || (cut == 1 && isRolloverNote(old[0])) {
+		return false, nil
+	}
+
+	prompt := r.Prompt
+	if prompt == "" {
+		prompt = defaultRolloverPrompt
+	}
+	summary, err := r.Model.Generate(ctx, []*schema.Message{
+		schema.SystemMessage(prompt),
+		schema.UserMessage(transcript(old)),
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to summarize conversation: %w", err)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	// 摘要期间只会在末尾追加消息, 前 cut 条仍然是被摘要的那些
+	m.messages = append([]*schema.Message{schema.SystemMessage(rolloverNotePrefix + summary.Content)}, m.messages[cut:]...)
+	if m.path == "" {
+		return true, nil
+	}
+	return true, m.save()
+}
+
+// rolloverCut 返回倒数第 keep 轮对话开始的位置, 每轮从一条用户消息开始; 不足 keep 轮时返回 0.
+func rolloverCut(msgs []*schema.Message, keep int) int {
+	for i := len(msgs) - 1; i >= 0; i-- {
+		if msgs[i].Role != schema.User {
+			continue
+		}
+		if keep--; keep == 0 {
+			return i
+		}
+	}
+	return 0
+}
+
+func isRolloverNote(msg *schema.Message) bool {
+	return msg.Role == schema.System && strings.HasPrefix(msg.Content, rolloverNotePrefix)
+}
+
+// transcript 把消息写成交给摘要模型的纯文本, tool call 写成 "名称(参数)".
+func transcript(msgs []*schema.Message) string {
+	var sb strings.Builder
+	for _, msg := range msgs {
+		switch msg.Role {
+		case schema.System:
+			sb.WriteString(strings.TrimPrefix(msg.Content, rolloverNotePrefix))
+		case schema.User:
+			sb.WriteString("用户: " + msg.Content)
+		case schema.Tool:
+			sb.WriteString("工具 " + msg.ToolName + " 的结果: " + msg.Content)
+		default:
+			if len(msg.ToolCalls) == 0 {
+				sb.WriteString("助手: " + msg.Content)
+			}
+			for i, tc := range msg.ToolCalls {
+				if i > 0 {
+					sb.WriteString("\n")
+				}
+				sb.WriteString("助手调用 " + tc.Function.Name + "(" + tc.Function.Arguments + ")")
+			}
+		}
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+// estimateTokens 粗略估算消息的 token 数: 中文等非 ASCII 字符每个算一个 token, ASCII 字符每 4 个算一个.
+func estimateTokens(msgs []*schema.Message) int {
+	ascii, other := 0, 0
+	count := func(s string) {
+		n := utf8.RuneCountInString(s)
+		for i := 0; i < len(s); i++ {
+			if s[i] < utf8.RuneSelf {
+				ascii++
+				n--
+			}
+		}
+		other += n
+	}
+	for _, msg := range msgs {
+		count(msg.Content)
+		for _, tc := range msg.ToolCalls {
+			count(tc.Function.Name)
+			count(tc.Function.Arguments)
+		}
+	}
+	return other + (ascii+3)/4
+}
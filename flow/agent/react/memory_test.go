@@ -0,0 +1,166 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/cloudwego/eino/schema"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConversationMemorySession(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "session.json")
+
+	for name, answer := range map[string]func(*AgentRunner, context.Context, string) (string, error){
+		"run":    (*AgentRunner).Run,
+		"stream": (*AgentRunner).Stream,
+	} {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "session.json")
+			memory, err := LoadConversationMemory(path)
+			assert.NoError(t, err)
+			assert.Empty(t, memory.Messages())
+
+			runner, err := NewAgentRunner(ctx, &AgentRunnerConfig{
+				ChatModel:  newScriptedModel(defaultMockScript()...),
+				PromptVars: map[string]string{"City": "北京"},
+				Memory:     memory,
+			})
+			assert.NoError(t, err)
+			defer runner.Close(ctx)
+
+			final, err := answer(runner, ctx, "我在北京，给我推荐一些辣的菜")
+			assert.NoError(t, err)
+
+			// 用户消息 + 3 轮 tool call 和结果 + 最终回答, 重新加载后 tool call 仍然完整
+			reloaded, err := LoadConversationMemory(path)
+			assert.NoError(t, err)
+			msgs := reloaded.Messages()
+			assert.Len(t, msgs, 8)
+			assert.Equal(t, schema.User, msgs[0].Role)
+			assert.Equal(t, "query_restaurants", msgs[1].ToolCalls[0].Function.Name)
+			assert.Equal(t, msgs[1].ToolCalls[0].ID, msgs[2].ToolCallID)
+			assert.Equal(t, final, msgs[7].Content)
+		})
+	}
+
+	_, err := LoadConversationMemory(filepath.Join(filepath.Dir(path), "missing", "session.json"))
+	assert.NoError(t, err)
+}
+
+func TestConversationMemoryRollover(t *testing.T) {
+	ctx := context.Background()
+	turn := func(q, a string) []*schema.Message {
+		return []*schema.Message{schema.UserMessage(q), schema.AssistantMessage(a, nil)}
+	}
+
+	memory := NewConversationMemory()
+	summarizer := newScriptedModel(schema.AssistantMessage("用户在北京, 想吃辣的", nil))
+	memory.SetRollover(&MemoryRollover{Model: summarizer, MaxMessages: 3, Prompt: "压缩对话"})
+
+	assert.NoError(t, memory.Append(turn("我在北京", "好的")...))
+	rolled, err := memory.Rollover(ctx)
+	assert.NoError(t, err)
+	assert.False(t, rolled)
+
+	// 超过 3 条, 最近一轮之前的对话被摘要替换
+	assert.NoError(t, memory.Append(turn("想吃辣的", "推荐云边小馆")...))
+	rolled, err = memory.Rollover(ctx)
+	assert.NoError(t, err)
+	assert.True(t, rolled)
+	msgs := memory.Messages()
+	assert.Len(t, msgs, 3)
+	assert.Equal(t, schema.System, msgs[0].Role)
+	assert.Contains(t, msgs[0].Content, "用户在北京, 想吃辣的")
+	assert.Equal(t, "想吃辣的", msgs[1].Content)
+	assert.Equal(t, "压缩对话", summarizer.inputs[0][0].Content)
+	assert.Contains(t, summarizer.inputs[0][1].Content, "用户: 我在北京")
+	assert.NotContains(t, summarizer.inputs[0][1].Content, "想吃辣的")
+
+	// 只剩摘要和最近一轮时没有可以摘要的消息
+	rolled, err = memory.Rollover(ctx)
+	assert.NoError(t, err)
+	assert.False(t, rolled)
+
+	// 摘要失败时历史保持原样
+	assert.NoError(t, memory.Append(turn("不要太贵", "人均 50")...))
+	_, err = memory.Rollover(ctx)
+	assert.ErrorContains(t, err, "failed to summarize")
+	assert.Len(t, memory.Messages(), 5)
+}
+
+func TestConversationMemoryRolloverKeepsSystemPrompt(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "session.json")
+	memory, err := LoadConversationMemory(path)
+	assert.NoError(t, err)
+	summarizer := newScriptedModel(
+		schema.AssistantMessage("第一次摘要", nil),
+		schema.AssistantMessage("第二次摘要", nil),
+	)
+	memory.SetRollover(&MemoryRollover{Model: summarizer, MaxTokens: 10})
+
+	chatModel := newScriptedModel(
+		schema.AssistantMessage("第一个回答", nil),
+		schema.AssistantMessage("第二个回答", nil),
+		schema.AssistantMessage("第三个回答", nil),
+	)
+	runner, err := NewAgentRunner(ctx, &AgentRunnerConfig{
+		ChatModel:  chatModel,
+		PromptVars: map[string]string{"City": "北京"},
+		Memory:     memory,
+	})
+	assert.NoError(t, err)
+	defer runner.Close(ctx)
+
+	for _, q := range []string{"第一个问题", "第二个问题", "第三个问题"} {
+		_, err := runner.Run(ctx, q)
+		assert.NoError(t, err)
+	}
+
+	// 每次调用的第一条消息都是 system prompt, 之后的历史从摘要开始
+	for _, input := range chatModel.inputs {
+		assert.Equal(t, schema.System, input[0].Role)
+		assert.Equal(t, runner.systemPrompt, input[0].Content)
+	}
+	last := chatModel.inputs[2]
+	assert.Len(t, last, 5)
+	assert.Equal(t, rolloverNotePrefix+"第一次摘要", last[1].Content)
+	assert.Equal(t, "第二个问题", last[2].Content)
+	assert.Equal(t, "第三个问题", last[4].Content)
+
+	// 第二次摘要包含了第一次的摘要, 持久化后重新加载仍然是摘要加最近一轮
+	assert.Contains(t, summarizer.inputs[1][1].Content, "第一次摘要")
+	reloaded, err := LoadConversationMemory(path)
+	assert.NoError(t, err)
+	msgs := reloaded.Messages()
+	assert.Len(t, msgs, 3)
+	assert.Equal(t, rolloverNotePrefix+"第二次摘要", msgs[0].Content)
+	assert.Equal(t, "第三个问题", msgs[1].Content)
+}
+
+func TestEstimateTokens(t *testing.T) {
+	assert.Equal(t, 0, estimateTokens(nil))
+	assert.Equal(t, 4, estimateTokens([]*schema.Message{schema.UserMessage("北京餐厅")}))
+	assert.Equal(t, 3, estimateTokens([]*schema.Message{schema.UserMessage("hello world")}))
+	// "query_dishes" 和 "{}" 一共 14 个 ASCII 字符
+	assert.Equal(t, 2+4, estimateTokens([]*schema.Message{schema.UserMessage("辣的"), toolCallMessage("c", "query_dishes", "{}")}))
+}
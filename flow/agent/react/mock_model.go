@@ -0,0 +1,113 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/schema"
+)
+
+// scriptedModel 是一个按固定剧本回复的 ToolCallingChatModel, 每次调用依次返回下一条消息.
+// 它让整个 ReAct 流程可以在没有 API key 的情况下运行 (-mock), 也让测试的结果可以复现.
+type scriptedModel struct {
+	mu        sync.Mutex
+	responses []*schema.Message
+	calls     int
+	// inputs 记录每次调用收到的消息, 测试用来检查发给模型的上下文
+	inputs [][]*schema.Message
+}
+
+func newScriptedModel(responses ...*schema.Message) *scriptedModel {
+	return &scriptedModel{responses: responses}
+}
+
+func (m *scriptedModel) next(input []*schema.Message) (*schema.Message, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.inputs = append(m.inputs, append([]*schema.Message(nil), input...))
+
+	if m.calls >= len(m.responses) {
+		return nil, fmt.Errorf("scripted model ran out of responses after %d calls", m.calls)
+	}
+	msg := m.responses[m.calls]
+	m.calls++
+	return msg, nil
+}
+
+func (m *scriptedModel) Generate(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.Message, error) {
+	return m.next(input)
+}
+
+// Stream 把 content 按几个字符一帧切开, 模拟真实的流式输出; tool call 在第一帧一次性给出.
+func (m *scriptedModel) Stream(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.StreamReader[*schema.Message], error) {
+	msg, err := m.next(input)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(msg.ToolCalls) > 0 || msg.Content == "" {
+		return schema.StreamReaderFromArray([]*schema.Message{msg}), nil
+	}
+
+	const runesPerFrame = 8
+	content := []rune(msg.Content)
+	frames := make([]*schema.Message, 0, len(content)/runesPerFrame+1)
+	for i := 0; i < len(content); i += runesPerFrame {
+		end := min(i+runesPerFrame, len(content))
+		frames = append(frames, &schema.Message{Role: msg.Role, Content: string(content[i:end])})
+	}
+	// 和真实模型一样, token usage 等 ResponseMeta 放在最后一帧
+	frames[len(frames)-1].ResponseMeta = msg.ResponseMeta
+	return schema.StreamReaderFromArray(frames), nil
+}
+
+func (m *scriptedModel) WithTools(tools []*schema.ToolInfo) (model.ToolCallingChatModel, error) {
+	return m, nil
+}
+
+// toolCallMessage 构造一条只包含一个 tool call 的 assistant 消息.
+func toolCallMessage(id, name, arguments string) *schema.Message {
+	return &schema.Message{
+		Role: schema.Assistant,
+		ToolCalls: []schema.ToolCall{
+			{
+				ID:   id,
+				Type: "function",
+				Function: schema.FunctionCall{
+					Name:      name,
+					Arguments: arguments,
+				},
+			},
+		},
+	}
+}
+
+// defaultMockScript 先查询北京的餐厅, 再查询两家餐厅的菜品, 最后给出推荐.
+// 查询菜品的两家餐厅是 query_restaurants 按评分返回的前两家, 也就是模型真正看到过的餐厅.
+func defaultMockScript() []*schema.Message {
+	return []*schema.Message{
+		toolCallMessage("call_1", "query_restaurants", `{"location":"北京","topn":2}`),
+		toolCallMessage("call_2", "query_dishes", `{"restaurant_id":"1003","topn":5}`),
+		toolCallMessage("call_3", "query_dishes", `{"restaurant_id":"1002","topn":5}`),
+		schema.AssistantMessage("给你推荐两家北京的餐厅: 聚福轩食府的火辣辣的吻和辣椒拌皮蛋口味偏辣, 是店里的招牌菜; 花影食舍评分最高, 不过超级红烧肉和超级北京烤肉都不辣.", nil),
+	}
+}
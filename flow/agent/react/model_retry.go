@@ -0,0 +1,204 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/schema"
+)
+
+// modelErrorClass 是 ChatModel 错误的分类, 决定了是否值得重试.
+type modelErrorClass int
+
+const (
+	modelErrorPermanent modelErrorClass = iota // 参数错误、鉴权失败等, 重试也不会成功
+	modelErrorTransient                        // 5xx、429、超时、连接断开等, 稍后重试可能成功
+	modelErrorCanceled                         // ctx 被取消或超时, 不能再重试
+)
+
+func (c modelErrorClass) String() string {
+	switch c {
+	case modelErrorTransient:
+		return "transient"
+	case modelErrorCanceled:
+		return "canceled"
+	default:
+		return "permanent"
+	}
+}
+
+var statusCodePattern = regexp.MustCompile(`(?i)status(?:\s*code)?\s*[:=]?\s*(\d{3})\b`)
+
+// classifyModelError 根据错误判断 ChatModel 的失败是否是暂时性的.
+// 优先使用错误自带的 StatusCode() 方法, 否则从错误文本中解析 "status code: 503" 这样的 HTTP 状态码.
+func classifyModelError(err error) modelErrorClass {
+	if err == nil {
+		return modelErrorPermanent
+	}
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return modelErrorCanceled
+	}
+
+	code := 0
+	var withStatus interface{ StatusCode() int }
+	if errors.As(err, &withStatus) {
+		code = withStatus.StatusCode()
+	} else if m := statusCodePattern.FindStringSubmatch(err.Error()); m != nil {
+		code, _ = strconv.Atoi(m[1])
+	}
+	if code != 0 {
+		if code >= 500 || code == 429 {
+			return modelErrorTransient
+		}
+		return modelErrorPermanent
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return modelErrorTransient
+	}
+	if errors.Is(err, io.ErrUnexpectedEOF) || strings.Contains(err.Error(), "connection reset") {
+		return modelErrorTransient
+	}
+	return modelErrorPermanent
+}
+
+// ModelRetryConfig 控制 newRetryModel 的重试策略, 零值字段使用默认值.
+type ModelRetryConfig struct {
+	// MaxRetries 是第一次调用失败后最多重试的次数, 默认 2.
+	MaxRetries int
+	// BaseDelay 是退避的基数, 第 n 次重试前等待 BaseDelay * 2^(n-1), 默认 500ms.
+	BaseDelay time.Duration
+	// MaxDelay 是单次等待的上限, 默认 5s.
+	MaxDelay time.Duration
+}
+
+// retryModel 在 ChatModel 返回暂时性错误时按指数退避重试, 和 tool 层的重试互相独立.
+// Stream 只在还没有输出任何一帧时重试, 已经输出了部分内容后出错就直接把错误交给下游, 避免重复输出.
+type retryModel struct {
+	model.ToolCallingChatModel
+	config ModelRetryConfig
+}
+
+func newRetryModel(m model.ToolCallingChatModel, config ModelRetryConfig) *retryModel {
+	if config.MaxRetries <= 0 {
+		config.MaxRetries = 2
+	}
+	if config.BaseDelay <= 0 {
+		config.BaseDelay = 500 * time.Millisecond
+	}
+	if config.MaxDelay <= 0 {
+		config.MaxDelay = 5 * time.Second
+	}
+	return &retryModel{ToolCallingChatModel: m, config: config}
+}
+
+func (r *retryModel) Generate(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.Message, error) {
+	for retry := 0; ; retry++ {
+		out, err := r.ToolCallingChatModel.Generate(ctx, input, opts...)
+		if err == nil {
+			return out, nil
+		}
+		if werr := r.wait(ctx, retry, err); werr != nil {
+			return nil, werr
+		}
+	}
+}
+
+func (r *retryModel) Stream(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.StreamReader[*schema.Message], error) {
+	for retry := 0; ; retry++ {
+		sr, err := r.ToolCallingChatModel.Stream(ctx, input, opts...)
+		if err == nil {
+			// 读到第一帧之前出错仍然可以重试
+			var first *schema.Message
+			first, err = sr.Recv()
+			if err == nil {
+				return prependFrame(first, sr), nil
+			}
+			sr.Close()
+			if errors.Is(err, io.EOF) {
+				return schema.StreamReaderFromArray([]*schema.Message{}), nil
+			}
+		}
+		if werr := r.wait(ctx, retry, err); werr != nil {
+			return nil, werr
+		}
+	}
+}
+
+func (r *retryModel) WithTools(tools []*schema.ToolInfo) (model.ToolCallingChatModel, error) {
+	m, err := r.ToolCallingChatModel.WithTools(tools)
+	if err != nil {
+		return nil, err
+	}
+	return &retryModel{ToolCallingChatModel: m, config: r.config}, nil
+}
+
+// wait 判断第 retry 次重试是否应该进行, 应该重试时等待退避时长后返回 nil, 否则返回最终的错误.
+func (r *retryModel) wait(ctx context.Context, retry int, err error) error {
+	if class := classifyModelError(err); class != modelErrorTransient || retry >= r.config.MaxRetries {
+		if retry > 0 {
+			return fmt.Errorf("chat model failed after %d attempts: %w", retry+1, err)
+		}
+		return err
+	}
+
+	delay := min(r.config.BaseDelay<<retry, r.config.MaxDelay)
+	fmt.Printf("[MODEL] transient error, retry %d/%d in %v: %v\n", retry+1, r.config.MaxRetries, delay, err)
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// prependFrame 把已经读出的第一帧放回流的开头.
+func prependFrame(first *schema.Message, rest *schema.StreamReader[*schema.Message]) *schema.StreamReader[*schema.Message] {
+	sr, sw := schema.Pipe[*schema.Message](1)
+	go func() {
+		defer sw.Close()
+		defer rest.Close()
+
+		if sw.Send(first, nil) {
+			return
+		}
+		for {
+			msg, err := rest.Recv()
+			if errors.Is(err, io.EOF) {
+				return
+			}
+			if sw.Send(msg, err) || err != nil {
+				return
+			}
+		}
+	}()
+	return sr
+}
@@ -0,0 +1,142 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/schema"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClassifyModelError(t *testing.T) {
+	cases := map[error]modelErrorClass{
+		errors.New("error, status code: 503, message: service unavailable"): modelErrorTransient,
+		errors.New("status code: 429, rate limited"):                        modelErrorTransient,
+		errors.New("status code: 401, invalid api key"):                     modelErrorPermanent,
+		errors.New("status=400 bad request"):                                modelErrorPermanent,
+		fmt.Errorf("read: %w", io.ErrUnexpectedEOF):                         modelErrorTransient,
+		errors.New("read tcp: connection reset by peer"):                    modelErrorTransient,
+		fmt.Errorf("call: %w", context.Canceled):                            modelErrorCanceled,
+		errors.New("something else"):                                        modelErrorPermanent,
+	}
+	for err, want := range cases {
+		assert.Equal(t, want, classifyModelError(err), err.Error())
+	}
+}
+
+// flakyModel 前 failures 次调用返回 err, 之后交给 scriptedModel. midStream 为 true 时, 流式调用先输出一帧再返回 err.
+type flakyModel struct {
+	*scriptedModel
+	failures  int
+	err       error
+	midStream bool
+	calls     int
+}
+
+func (m *flakyModel) Generate(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.Message, error) {
+	m.calls++
+	if m.calls <= m.failures {
+		return nil, m.err
+	}
+	return m.scriptedModel.Generate(ctx, input, opts...)
+}
+
+func (m *flakyModel) Stream(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.StreamReader[*schema.Message], error) {
+	m.calls++
+	if m.calls <= m.failures {
+		if !m.midStream {
+			return nil, m.err
+		}
+		sr, sw := schema.Pipe[*schema.Message](2)
+		sw.Send(schema.AssistantMessage("部分", nil), nil)
+		sw.Send(nil, m.err)
+		sw.Close()
+		return sr, nil
+	}
+	return m.scriptedModel.Stream(ctx, input, opts...)
+}
+
+func readAll(t *testing.T, sr *schema.StreamReader[*schema.Message]) (string, error) {
+	t.Helper()
+	defer sr.Close()
+	content := ""
+	for {
+		msg, err := sr.Recv()
+		if errors.Is(err, io.EOF) {
+			return content, nil
+		}
+		if err != nil {
+			return content, err
+		}
+		content += msg.Content
+	}
+}
+
+func TestRetryModel(t *testing.T) {
+	ctx := context.Background()
+	transient := errors.New("status code: 503")
+	config := ModelRetryConfig{MaxRetries: 2, BaseDelay: time.Millisecond}
+
+	t.Run("generate recovers", func(t *testing.T) {
+		inner := &flakyModel{scriptedModel: newScriptedModel(schema.AssistantMessage("好的", nil)), failures: 2, err: transient}
+		out, err := newRetryModel(inner, config).Generate(ctx, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, "好的", out.Content)
+		assert.Equal(t, 3, inner.calls)
+	})
+
+	t.Run("generate gives up", func(t *testing.T) {
+		inner := &flakyModel{scriptedModel: newScriptedModel(), failures: 5, err: transient}
+		_, err := newRetryModel(inner, config).Generate(ctx, nil)
+		assert.ErrorContains(t, err, "after 3 attempts")
+		assert.Equal(t, 3, inner.calls)
+	})
+
+	t.Run("permanent error is not retried", func(t *testing.T) {
+		inner := &flakyModel{scriptedModel: newScriptedModel(), failures: 1, err: errors.New("status code: 401")}
+		_, err := newRetryModel(inner, config).Generate(ctx, nil)
+		assert.Error(t, err)
+		assert.Equal(t, 1, inner.calls)
+	})
+
+	t.Run("stream retries before the first frame", func(t *testing.T) {
+		inner := &flakyModel{scriptedModel: newScriptedModel(schema.AssistantMessage("你好, 给你推荐云边小馆", nil)), failures: 1, err: transient}
+		sr, err := newRetryModel(inner, config).Stream(ctx, nil)
+		assert.NoError(t, err)
+		content, err := readAll(t, sr)
+		assert.NoError(t, err)
+		assert.Equal(t, "你好, 给你推荐云边小馆", content)
+		assert.Equal(t, 2, inner.calls)
+	})
+
+	t.Run("stream does not retry after a frame", func(t *testing.T) {
+		inner := &flakyModel{scriptedModel: newScriptedModel(schema.AssistantMessage("不应该出现", nil)), failures: 1, err: transient, midStream: true}
+		sr, err := newRetryModel(inner, config).Stream(ctx, nil)
+		assert.NoError(t, err)
+		content, err := readAll(t, sr)
+		assert.ErrorIs(t, err, transient)
+		assert.Equal(t, "部分", content)
+		assert.Equal(t, 1, inner.calls)
+	})
+}
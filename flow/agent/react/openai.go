@@ -0,0 +1,139 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/cloudwego/eino/schema"
+)
+
+// openAIConversation 是 OpenAI chat completions 请求体中的 messages 部分, 导出的文件加上 model 就可以直接作为请求发送,
+// 也可以交给其他兼容 OpenAI 格式的工具回放.
+type openAIConversation struct {
+	Messages []openAIMessage `json:"messages"`
+}
+
+type openAIMessage struct {
+	Role string `json:"role"`
+	// Content 在只有 tool call 的 assistant 消息中为 null, 和 OpenAI 的格式一致
+	Content    *string          `json:"content"`
+	ToolCalls  []openAIToolCall `json:"tool_calls,omitempty"`
+	ToolCallID string           `json:"tool_call_id,omitempty"`
+}
+
+type openAIToolCall struct {
+	ID       string             `json:"id"`
+	Type     string             `json:"type"`
+	Function openAIFunctionCall `json:"function"`
+}
+
+type openAIFunctionCall struct {
+	Name      string `json:"name"`
+	Arguments string `json:"arguments"` // JSON 字符串, 不是对象
+}
+
+// toOpenAIMessages 把 eino 的消息转换成 OpenAI 的格式. 只支持纯文本的 content, 多模态消息返回错误.
+func toOpenAIMessages(msgs []*schema.Message) ([]openAIMessage, error) {
+	out := make([]openAIMessage, 0, len(msgs))
+	for i, msg := range msgs {
+		if len(msg.MultiContent) > 0 {
+			return nil, fmt.Errorf("message %d: multi-part content is not supported", i)
+		}
+
+		m := openAIMessage{Role: string(msg.Role)}
+		switch msg.Role {
+		case schema.System, schema.User:
+		case schema.Assistant:
+			for _, tc := range msg.ToolCalls {
+				typ := tc.Type
+				if typ == "" {
+					typ = "function"
+				}
+				m.ToolCalls = append(m.ToolCalls, openAIToolCall{
+					ID:       tc.ID,
+					Type:     typ,
+					Function: openAIFunctionCall{Name: tc.Function.Name, Arguments: tc.Function.Arguments},
+				})
+			}
+		case schema.Tool:
+			if msg.ToolCallID == "" {
+				return nil, fmt.Errorf("message %d: tool message without tool_call_id", i)
+			}
+			m.ToolCallID = msg.ToolCallID
+		default:
+			return nil, fmt.Errorf("message %d: unsupported role %q", i, msg.Role)
+		}
+
+		if msg.Content != "" || len(m.ToolCalls) == 0 {
+			content := msg.Content
+			m.Content = &content
+		}
+		out = append(out, m)
+	}
+	return out, nil
+}
+
+// fromOpenAIMessages 是 toOpenAIMessages 的逆过程. OpenAI 的 tool 消息没有 tool 名称, 按 tool_call_id 从前面的 tool call 中找回.
+func fromOpenAIMessages(msgs []openAIMessage) ([]*schema.Message, error) {
+	toolNames := map[string]string{} // tool call id => tool name
+	out := make([]*schema.Message, 0, len(msgs))
+	for i, m := range msgs {
+		msg := &schema.Message{Role: schema.RoleType(m.Role)}
+		if m.Content != nil {
+			msg.Content = *m.Content
+		}
+		switch msg.Role {
+		case schema.System, schema.User:
+		case schema.Assistant:
+			for _, tc := range m.ToolCalls {
+				toolNames[tc.ID] = tc.Function.Name
+				msg.ToolCalls = append(msg.ToolCalls, schema.ToolCall{
+					ID:       tc.ID,
+					Type:     tc.Type,
+					Function: schema.FunctionCall{Name: tc.Function.Name, Arguments: tc.Function.Arguments},
+				})
+			}
+		case schema.Tool:
+			name, ok := toolNames[m.ToolCallID]
+			if !ok {
+				return nil, fmt.Errorf("message %d: tool_call_id %q does not match any earlier tool call", i, m.ToolCallID)
+			}
+			msg.ToolCallID = m.ToolCallID
+			msg.ToolName = name
+		default:
+			return nil, fmt.Errorf("message %d: unsupported role %q", i, m.Role)
+		}
+		out = append(out, msg)
+	}
+	return out, nil
+}
+
+// ExportOpenAI 把整个对话 (包括 system prompt, tool call 和 tool 结果) 以 OpenAI 的 messages 格式写入 path.
+func ExportOpenAI(path string, msgs []*schema.Message) error {
+	converted, err := toOpenAIMessages(msgs)
+	if err != nil {
+		return fmt.Errorf("failed to convert the conversation: %w", err)
+	}
+	b, err := json.MarshalIndent(openAIConversation{Messages: converted}, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, b, 0o644)
+}
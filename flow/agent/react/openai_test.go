@@ -0,0 +1,80 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/cloudwego/eino/schema"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOpenAIRoundTrip(t *testing.T) {
+	calls := toolCallMessage("call_1", "query_restaurants", `{"location":"北京"}`)
+	calls.ToolCalls = append(calls.ToolCalls, toolCallMessage("call_2", "query_dishes", `{"restaurant_id":"1001"}`).ToolCalls...)
+	conversation := []*schema.Message{
+		schema.SystemMessage("你是一个助手"),
+		schema.UserMessage("推荐一些辣菜"),
+		calls,
+		schema.ToolMessage(`[{"id":"1001"}]`, "call_1", schema.WithToolName("query_restaurants")),
+		schema.ToolMessage(`[{"name":"红烧肉"}]`, "call_2", schema.WithToolName("query_dishes")),
+		schema.AssistantMessage("推荐云边小馆的红烧肉", nil),
+	}
+
+	path := filepath.Join(t.TempDir(), "conversation.json")
+	assert.NoError(t, ExportOpenAI(path, conversation))
+
+	b, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	// 只有 tool call 的 assistant 消息的 content 是 null, arguments 是字符串
+	var raw struct {
+		Messages []map[string]any `json:"messages"`
+	}
+	assert.NoError(t, json.Unmarshal(b, &raw))
+	assert.Len(t, raw.Messages, 6)
+	assert.Contains(t, raw.Messages[2], "content")
+	assert.Nil(t, raw.Messages[2]["content"])
+	fn := raw.Messages[2]["tool_calls"].([]any)[0].(map[string]any)["function"].(map[string]any)
+	assert.Equal(t, `{"location":"北京"}`, fn["arguments"])
+	assert.Equal(t, "call_2", raw.Messages[4]["tool_call_id"])
+	assert.NotContains(t, raw.Messages[4], "name")
+
+	var exported openAIConversation
+	assert.NoError(t, json.Unmarshal(b, &exported))
+	back, err := fromOpenAIMessages(exported.Messages)
+	assert.NoError(t, err)
+	assert.Equal(t, conversation, back)
+}
+
+func TestToOpenAIMessagesErrors(t *testing.T) {
+	_, err := toOpenAIMessages([]*schema.Message{{Role: schema.Tool, Content: "x"}})
+	assert.ErrorContains(t, err, "tool_call_id")
+
+	_, err = toOpenAIMessages([]*schema.Message{{Role: "developer", Content: "x"}})
+	assert.ErrorContains(t, err, "unsupported role")
+
+	_, err = toOpenAIMessages([]*schema.Message{{Role: schema.User, MultiContent: []schema.ChatMessagePart{{Type: schema.ChatMessagePartTypeText, Text: "x"}}}})
+	assert.ErrorContains(t, err, "multi-part")
+
+	// tool 结果找不到对应的 tool call
+	content := "x"
+	_, err = fromOpenAIMessages([]openAIMessage{{Role: "tool", Content: &content, ToolCallID: "call_9"}})
+	assert.ErrorContains(t, err, "does not match")
+}
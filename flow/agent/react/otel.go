@@ -0,0 +1,128 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/cloudwego/eino/callbacks"
+	"github.com/cloudwego/eino/components"
+	"github.com/cloudwego/eino/schema"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/cloudwego/eino-examples/flow/agent/react/tools"
+)
+
+// setupTracing 根据 exporter 创建 tracer, 返回的 shutdown 会 flush 尚未导出的 span.
+// exporter 为 "none" 时返回 nil tracer, 表示不启用 tracing.
+func setupTracing(exporter string) (trace.Tracer, func(context.Context) error, error) {
+	var exp sdktrace.SpanExporter
+	switch exporter {
+	case "", "none":
+		return nil, func(context.Context) error { return nil }, nil
+	case "stdout":
+		e, err := stdouttrace.New(stdouttrace.WithWriter(os.Stderr), stdouttrace.WithPrettyPrint())
+		if err != nil {
+			return nil, nil, fmt.Errorf("create stdout exporter: %w", err)
+		}
+		exp = e
+	default:
+		return nil, nil, fmt.Errorf("unknown otel exporter %q, expected stdout or none", exporter)
+	}
+
+	tp := sdktrace.NewTracerProvider(sdktrace.WithBatcher(exp))
+	return tp.Tracer("eino-examples/flow/agent/react"), tp.Shutdown, nil
+}
+
+// otelCallback 在 OnStart 时开启 span, 在 OnEnd / OnError 时结束.
+// span 会放进返回的 ctx 中, 由 agent graph 调起的 ChatModel、Tool 等组件拿到的 ctx 都派生自它,
+// 因此它们的 span 自动成为子 span, 整个 ReAct 循环形成一棵 trace 树.
+type otelCallback struct {
+	tracer trace.Tracer
+}
+
+func newOTelCallback(tracer trace.Tracer) callbacks.Handler {
+	cb := &otelCallback{tracer: tracer}
+	return callbacks.NewHandlerBuilder().
+		OnStartFn(cb.onStart).
+		OnEndFn(cb.onEnd).
+		OnErrorFn(cb.onError).
+		OnStartWithStreamInputFn(cb.onStartWithStreamInput).
+		OnEndWithStreamOutputFn(cb.onEndWithStreamOutput).
+		Build()
+}
+
+func (cb *otelCallback) start(ctx context.Context, info *callbacks.RunInfo) context.Context {
+	attrs := []attribute.KeyValue{
+		attribute.String("eino.component", string(info.Component)),
+		attribute.String("eino.type", info.Type),
+		attribute.String("eino.name", info.Name),
+	}
+	if info.Component == components.ComponentOfTool {
+		attrs = append(attrs, attribute.String("tool.name", info.Name))
+	}
+
+	ctx, _ = cb.tracer.Start(ctx, fmt.Sprintf("%s %s", info.Component, info.Name), trace.WithAttributes(attrs...))
+	return ctx
+}
+
+func (cb *otelCallback) end(ctx context.Context, info *callbacks.RunInfo) {
+	span := trace.SpanFromContext(ctx)
+	if info.Component == components.ComponentOfTool {
+		// ToolExecutionState 由 LoggerCallback 在 OnStart 时放入 ctx
+		if state := tools.GetToolState(ctx); state != nil {
+			span.SetAttributes(attribute.Bool("tool.success", state.Success))
+		}
+	}
+	span.End()
+}
+
+func (cb *otelCallback) onStart(ctx context.Context, info *callbacks.RunInfo, input callbacks.CallbackInput) context.Context {
+	return cb.start(ctx, info)
+}
+
+func (cb *otelCallback) onEnd(ctx context.Context, info *callbacks.RunInfo, output callbacks.CallbackOutput) context.Context {
+	cb.end(ctx, info)
+	return ctx
+}
+
+func (cb *otelCallback) onError(ctx context.Context, info *callbacks.RunInfo, err error) context.Context {
+	span := trace.SpanFromContext(ctx)
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+	span.End()
+	return ctx
+}
+
+func (cb *otelCallback) onStartWithStreamInput(ctx context.Context, info *callbacks.RunInfo,
+	input *schema.StreamReader[callbacks.CallbackInput]) context.Context {
+	input.Close()
+	return cb.start(ctx, info)
+}
+
+func (cb *otelCallback) onEndWithStreamOutput(ctx context.Context, info *callbacks.RunInfo,
+	output *schema.StreamReader[callbacks.CallbackOutput]) context.Context {
+	output.Close()
+	cb.end(ctx, info)
+	return ctx
+}
@@ -0,0 +1,87 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"text/template"
+
+	"github.com/cloudwego/eino/components/tool"
+)
+
+// defaultSystemPrompt 是默认的 system prompt 模板, 可通过 -prompt-file 替换.
+// 可用变量: {{.City}} 用户所在城市, {{.ToolNames}} 当前注册的 tool 名称列表.
+const defaultSystemPrompt = `# Character:
+你是一个帮助用户推荐餐厅和菜品的助手，根据用户的需要，查询餐厅信息并推荐，查询餐厅的菜品并推荐。
+用户当前所在城市: {{.City}}
+你可以使用的工具: {{.ToolNames}}
+如果某个工具返回了错误并且提示不要重试, 不要放弃回答, 基于已经获得的信息给出推荐, 并告诉用户缺少了哪些信息.
+推荐座位时先用 query_weather 查询当前天气: outdoor_seating_suitable 为 true 时可以建议室外或露台座位, 否则建议室内.
+
+# Scope:
+你只回答和餐厅、菜品、外卖、用餐相关的问题.
+如果用户的问题和这些无关 (比如写代码、单纯询问天气预报、新闻), 不要调用任何工具, 也不要编造信息, 礼貌地说明你只能帮忙推荐餐厅和菜品.
+`
+
+// renderPrompt 使用 text/template 渲染 prompt. 值为空的变量不会放入模板数据,
+// 配合 missingkey=error, 模板中引用了未提供的变量时会直接报错, 而不是渲染出 "<no value>".
+func renderPrompt(tmpl string, vars map[string]string) (string, error) {
+	t, err := template.New("system_prompt").Option("missingkey=error").Parse(tmpl)
+	if err != nil {
+		return "", fmt.Errorf("parse prompt template: %w", err)
+	}
+
+	data := make(map[string]string, len(vars))
+	for k, v := range vars {
+		if v != "" {
+			data[k] = v
+		}
+	}
+
+	var sb strings.Builder
+	if err := t.Execute(&sb, data); err != nil {
+		return "", fmt.Errorf("render prompt template: %w", err)
+	}
+	return sb.String(), nil
+}
+
+// wrapPrompt 按 prefix、base、suffix 的固定顺序拼接 prompt, 每一段去掉首尾空白后用空行分隔, 空的段落会被跳过.
+// 拼接发生在渲染之前, 所以 prefix 和 suffix 中也可以使用模板变量.
+func wrapPrompt(prefix, base, suffix string) string {
+	parts := make([]string, 0, 3)
+	for _, p := range []string{prefix, base, suffix} {
+		if p = strings.TrimSpace(p); p != "" {
+			parts = append(parts, p)
+		}
+	}
+	return strings.Join(parts, "\n\n") + "\n"
+}
+
+// toolNames 返回注册的 tool 名称, 渲染进 prompt 让模型清楚自己能调用什么.
+func toolNames(ctx context.Context, tools []tool.BaseTool) ([]string, error) {
+	names := make([]string, 0, len(tools))
+	for _, t := range tools {
+		info, err := t.Info(ctx)
+		if err != nil {
+			return nil, err
+		}
+		names = append(names, info.Name)
+	}
+	return names, nil
+}
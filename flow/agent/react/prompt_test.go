@@ -0,0 +1,62 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/cloudwego/eino-examples/flow/agent/react/tools"
+	"github.com/cloudwego/eino/components/tool"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRenderPrompt(t *testing.T) {
+	names, err := toolNames(context.Background(), []tool.BaseTool{tools.GetRestaurantTool(), tools.GetDishTool()})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"query_restaurants", "query_dishes"}, names)
+
+	out, err := renderPrompt(defaultSystemPrompt, map[string]string{
+		"City":      "北京",
+		"ToolNames": "query_restaurants, query_dishes",
+	})
+	assert.NoError(t, err)
+	assert.Contains(t, out, "用户当前所在城市: 北京")
+	assert.Contains(t, out, "query_restaurants, query_dishes")
+	assert.Contains(t, out, "outdoor_seating_suitable")
+
+	_, err = renderPrompt(defaultSystemPrompt, map[string]string{"ToolNames": "query_dishes"})
+	assert.ErrorContains(t, err, "City")
+
+	_, err = renderPrompt("{{.City", nil)
+	assert.ErrorContains(t, err, "parse prompt template")
+}
+
+func TestWrapPrompt(t *testing.T) {
+	assert.Equal(t, "safety\n\nbase\n\nformat\n", wrapPrompt("  safety\n", "\nbase\n\n", "format "))
+	assert.Equal(t, "base\n\nformat\n", wrapPrompt(" \n", "base", "format"))
+	assert.Equal(t, "safety\n\nbase\n", wrapPrompt("safety", "base", ""))
+
+	out, err := renderPrompt(wrapPrompt("只推荐 {{.City}} 的餐厅.", defaultSystemPrompt, "用 Markdown 列表输出."), map[string]string{
+		"City":      "上海",
+		"ToolNames": "query_restaurants",
+	})
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(out, "只推荐 上海 的餐厅.\n\n# Character:"))
+	assert.True(t, strings.HasSuffix(out, "礼貌地说明你只能帮忙推荐餐厅和菜品.\n\n用 Markdown 列表输出.\n"))
+}
@@ -19,12 +19,21 @@ package main
 import (
 	"context"
 	"errors"
+	"flag"
 	"fmt"
 	"io"
+	"log/slog"
+	"net"
 	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+	"unicode/utf8"
 
 	"github.com/cloudwego/eino-examples/flow/agent/react/tools"
-	"github.com/cloudwego/eino-ext/components/model/deepseek"
 	"github.com/cloudwego/eino/callbacks"
 	"github.com/cloudwego/eino/components"
 	"github.com/cloudwego/eino/components/model"
@@ -35,20 +44,580 @@ import (
 	"github.com/cloudwego/eino/schema"
 )
 
+var (
+	mode               = flag.String("mode", "stream", "how to run the agent: stream, generate, steps (generate and print the intermediate tool calls), vote (sample the final answer -samples times and vote), or graph (the same loop built with compose.Graph)")
+	backendLatency     = flag.Duration("backend-latency", 0, "simulated latency of the fake restaurant backend, e.g. 3s")
+	city               = flag.String("city", "北京", "the city of the user, rendered into the system prompt as {{.City}}")
+	promptFile         = flag.String("prompt-file", "", "path of a text/template file replacing the default system prompt")
+	mockModel          = flag.Bool("mock", false, "use a scripted chat model instead of deepseek, no API key required")
+	configFile         = flag.String("config", "", "load settings from this YAML or JSON file; flags given on the command line override it")
+	modelName          = flag.String("model", "", "the chat model to use, defaults to deepseek-chat for deepseek; openai and ark read OPENAI_MODEL_NAME and ARK_MODEL_NAME")
+	temperature        = flag.Float64("temperature", -1, "the temperature of the chat model, negative for the model's default")
+	enabledTools       = flag.String("tools", "", "comma-separated names of the tools exposed to the model, empty for all")
+	failureRate        = flag.Float64("failure-rate", tools.DefaultFailureRate, "the probability that query_restaurants fails with a retryable error")
+	maxSteps           = flag.Int("max-steps", 0, "the maximum number of steps of the ReAct loop, each round of tool calls takes two, 0 for the default")
+	maxToolArgBytes    = flag.Int("max-tool-args-bytes", tools.DefaultMaxArgumentBytes, "reject tool calls whose arguments exceed this many bytes")
+	toolTimeout        = flag.Duration("tool-timeout", tools.DefaultToolTimeout, "abandon a single tool call after this long and tell the model it timed out, 0 to disable")
+	summarizeThreshold = flag.Int("summarize-threshold", 8000, "summarize tool results with the model once they exceed this many bytes, 0 to disable")
+	flushInterval      = flag.Duration("flush-interval", 50*time.Millisecond, "buffer streamed answer content for this long before printing, 0 to print every frame")
+	flushBytes         = flag.Int("flush-bytes", 256, "print buffered answer content once this many bytes are buffered")
+	provenance         = flag.Bool("provenance", false, "prefix every tool result with [Source: <tool name>] to help the model ground its answer")
+	listTools          = flag.Bool("list-tools", false, "print all registered tools and their parameters as Markdown, then exit")
+	now                = flag.String("now", "", "fix the current time of the fake backend, in RFC3339, e.g. 2024-06-01T19:30:00+08:00")
+	cacheFile          = flag.String("cache-file", "", "cache successful tool results in this JSON file, so repeated runs reuse them")
+	cacheTTL           = flag.Duration("cache-ttl", 10*time.Minute, "how long a cached tool result stays valid")
+	redactPII          = flag.Bool("redact-pii", true, "redact phone numbers, emails and addresses in logged tool arguments and results")
+	maxTools           = flag.Int("max-tools", 0, "expose only the first N registered tools to the model, 0 for all")
+	query              = flag.String("query", "我在北京，给我推荐一些菜，需要有口味辣一点的菜，至少推荐有 2 家餐厅", "the message of the user")
+	session            = flag.String("session", "", "load the conversation history from this JSON file and save it back after the turn")
+	memoryMaxMessages  = flag.Int("memory-max-messages", 0, "summarize older turns of the -session history once it has more than this many messages, 0 for no limit")
+	memoryMaxTokens    = flag.Int("memory-max-tokens", 0, "summarize older turns of the -session history once it has more than this many estimated tokens, 0 for no limit")
+	memoryPrompt       = flag.String("memory-summary-prompt", "", "the prompt used to summarize older turns of the history, empty for the default")
+	printTTFT          = flag.Bool("ttft", false, "print the time-to-first-token of every streamed chat model call and a summary at the end")
+	userID             = flag.String("user", "", "the id of the current user for personalized tools, e.g. u1001 (likes spicy food) or u2002")
+	strict             = flag.Bool("strict", false, "return tool errors as Go errors that stop the agent, instead of handing them to the model")
+	otelExporter       = flag.String("otel-exporter", "none", "emit OpenTelemetry spans per component: stdout or none")
+	samples            = flag.Int("samples", 5, "how many final answers to sample in vote mode")
+	repeat             = flag.Int("repeat", 1, "run the same query this many times with temperature > 0 and report how consistent the recommended restaurants are, instead of -mode")
+	streamTools        = flag.Bool("stream-tools", false, "register format_menu and query_dishes as streamable tools that output the menu line by line and the dishes one by one")
+	argStats           = flag.Bool("arg-stats", false, "print the distinct argument values of every tool seen during the run")
+	printConcurrency   = flag.Bool("concurrency", false, "print the peak number of chat model and tool calls running at the same time")
+	printRunSummary    = flag.Bool("run-summary", false, "print a summary after the run: chat model and tool calls, failures, latency and token usage")
+	printToolErrors    = flag.Bool("tool-errors", false, "print every tool error after the run, including the ones the model recovered from")
+	promptPrefix       = flag.String("prompt-prefix", "", "text placed before the system prompt, e.g. safety guidelines; may use the same template variables")
+	promptSuffix       = flag.String("prompt-suffix", "", "text placed after the system prompt, e.g. output format instructions; may use the same template variables")
+	printEvents        = flag.Bool("events", false, "consume structured agent events from a channel and print a live summary of tool calls")
+	verbose            = flag.Bool("verbose", false, "narrate every step of the ReAct loop: thinking, calling tools, results and the final answer")
+	shuffleSeed        = flag.Int64("shuffle-seed", 0, "seed for shuffling restaurants with the same score, 0 for a different order every run")
+	modelRetries       = flag.Int("model-retries", 2, "retry the chat model this many times on transient errors (5xx, 429, timeouts), 0 to disable")
+	maxResults         = flag.Int("max-results", tools.DefaultMaxResults, "return at most this many items of every list in a tool result to the model, 0 for no limit")
+	resultFormat       = flag.String("result-format", "json", "format of tool results given to the model: json, yaml or kv (one path=value per line)")
+	answerProcessors   = flag.String("answer-processors", "", "comma separated processors applied in order to the final answer before it is shown: trim, plain-text, max-length, disclaimer")
+	answerMaxLength    = flag.Int("answer-max-length", 1000, "with -answer-processors max-length, truncate the shown answer to this many characters")
+	answerDisclaimer   = flag.String("answer-disclaimer", defaultAnswerDisclaimer, "with -answer-processors disclaimer, the text appended to the shown answer")
+	answerLang         = flag.String("answer-lang", "", "require the final answer in this language: en or zh, and warn when the answer looks like another language")
+	serveAddr          = flag.String("serve", "", "serve the agent over HTTP on this address, e.g. :8080, streaming tool calls and the answer as Server-Sent Events from /chat?query=...")
+	shutdownTimeout    = flag.Duration("shutdown-timeout", 30*time.Second, "with -serve, how long to wait for in-flight runs to finish on SIGINT/SIGTERM before cancelling them")
+	exportOpenAI       = flag.String("export-openai", "", "after the run, write the whole conversation, including tool calls and results, to this file as OpenAI chat completions messages JSON")
+	failTool           = flag.String("fail-tool", "", "make the tool with this name fail with a transient error on every call, to watch retries, the circuit breaker and degradation")
+	rateConfidence     = flag.Bool("confidence", false, "after the final answer, ask the model to rate its confidence in the recommendations from 0 to 1 and print it")
+	maxToolCalls       = flag.Int("max-tool-calls", 0, "refuse tool calls beyond this many per run with a \"tool budget exhausted\" message so the model must answer, 0 for no limit")
+)
+
 func main() {
-	ctx := context.Background()
+	flag.Parse()
+
+	// go run . diff a.json b.json: 对比两个 -session 保存的会话, 不运行 agent
+	if flag.Arg(0) == "diff" {
+		if err := runSessionDiff(flag.Args()[1:]); err != nil {
+			fmt.Printf("[ERROR] %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	cfg, err := LoadConfig(*configFile, explicitFlags(), os.Getenv)
+	if err != nil {
+		fmt.Printf("[ERROR] %v\n", err)
+		os.Exit(1)
+	}
+	effectiveConfig = cfg
+	fmt.Printf("[CONFIG] %s\n", cfg)
+
+	// 餐厅数据 (tools/data/restaurants.json) 有问题时直接退出, 否则每个查询类 tool 都会失败
+	if err := tools.DataError(); err != nil {
+		fmt.Printf("[ERROR] %v\n", err)
+		os.Exit(1)
+	}
+
+	// Ctrl+C 或 SIGTERM 取消 ctx, 正在执行的 tool 会立即返回取消信息, 而不是等到执行完成;
+	// -serve 时则是开始优雅退出, 见 serveSSE
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	tools.SetBackendLatency(*backendLatency)
+	if *shuffleSeed != 0 {
+		tools.SetShuffleSeed(*shuffleSeed)
+	}
+	tools.SetStrictMode(*strict)
+	tools.SetFailureRate(cfg.FailureRate)
+	tools.SetMaxResults(*maxResults)
+	if serializer, err := tools.ResultSerializerByName(*resultFormat); err != nil {
+		fmt.Printf("[ERROR] -result-format: %v\n", err)
+		os.Exit(1)
+	} else {
+		tools.SetResultSerializer(serializer)
+	}
+	if *failTool != "" {
+		// 故障在创建 tool 时注入, 创建一次才能发现写错的名称
+		tools.SetChaosTool(*failTool)
+		defaultTools()
+		if !tools.ChaosToolApplied() {
+			fmt.Printf("[ERROR] -fail-tool: no tool named %q calls the backend\n", *failTool)
+			os.Exit(1)
+		}
+		fmt.Printf("[CHAOS] %s will fail on every call\n", *failTool)
+	}
+	if *userID != "" {
+		ctx = tools.WithUserID(ctx, *userID)
+	} else {
+		// 匿名用户设置的饮食偏好只在这次运行中有效
+		ctx = tools.WithGuestSession(ctx)
+	}
+	if *now != "" {
+		t, err := time.Parse(time.RFC3339, *now)
+		if err != nil {
+			fmt.Printf("[ERROR] invalid -now: %v\n", err)
+			os.Exit(1)
+		}
+		tools.SetClock(tools.FixedClock{T: t})
+	}
+
+	if *cacheFile != "" {
+		cache, err := tools.NewResultCache(*cacheFile, *cacheTTL)
+		if err != nil {
+			fmt.Printf("[ERROR] %v\n", err)
+			os.Exit(1)
+		}
+		resultCache = cache
+	}
+
+	if *listTools {
+		doc, err := toolsMarkdown(ctx, defaultTools())
+		if err != nil {
+			fmt.Printf("[ERROR] failed to list tools: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Print(doc)
+		return
+	}
+
+	agentTools, err := selectTools(ctx, defaultTools(), cfg.EnabledTools)
+	if err != nil {
+		fmt.Printf("[ERROR] %v\n", err)
+		os.Exit(1)
+	}
+
+	var chatModel model.ToolCallingChatModel
+	if cfg.Mock() {
+		script := defaultMockScript()
+		if *rateConfidence {
+			script = append(script, schema.AssistantMessage(`{"confidence": 0.8, "reason": "两家餐厅和菜品都来自工具返回的数据"}`, nil))
+		}
+		chatModel = newScriptedModel(script...)
+	} else {
+		chatModel, err = newChatModel(ctx, cfg)
+		if err != nil {
+			dumpDiagnostics(err)
+			return
+		}
+	}
+	if *modelRetries > 0 {
+		chatModel = newRetryModel(chatModel, ModelRetryConfig{MaxRetries: *modelRetries})
+	}
+
+	promptTemplate := ""
+	if *promptFile != "" {
+		b, err := os.ReadFile(*promptFile)
+		if err != nil {
+			fmt.Printf("[ERROR] failed to read prompt file: %v\n", err)
+			return
+		}
+		promptTemplate = string(b)
+	}
+	if *promptPrefix != "" || *promptSuffix != "" {
+		if promptTemplate == "" {
+			promptTemplate = defaultSystemPrompt
+		}
+		promptTemplate = wrapPrompt(*promptPrefix, promptTemplate, *promptSuffix)
+	}
+	processAnswer, err := parseAnswerProcessors(*answerProcessors, AnswerProcessorOptions{Disclaimer: *answerDisclaimer, MaxLength: *answerMaxLength})
+	if err != nil {
+		fmt.Printf("[ERROR] -answer-processors: %v\n", err)
+		os.Exit(1)
+	}
+
+	if *answerLang != "" {
+		instruction, err := answerLangInstruction(*answerLang)
+		if err != nil {
+			fmt.Printf("[ERROR] %v\n", err)
+			return
+		}
+		if promptTemplate == "" {
+			promptTemplate = defaultSystemPrompt
+		}
+		// 放在最后, 在 -prompt-suffix 之后, 不会被其他输出格式的要求盖过
+		promptTemplate = wrapPrompt("", promptTemplate, instruction)
+	}
 
-	config := &deepseek.ChatModelConfig{
-		APIKey:  os.Getenv("DEEPSEEK_API_KEY"),
-		Model:   "deepseek-chat",
+	logger := &LoggerCallback{FlushInterval: *flushInterval, FlushBytes: *flushBytes, Stats: &tools.RunStats{}}
+	if cfg.Logging.RedactPII {
+		NewRedactingLogger(logger)
 	}
 
-	arkModel, err := deepseek.NewChatModel(ctx, config)
+	var memory *ConversationMemory
+	if *session != "" {
+		memory, err = LoadConversationMemory(*session)
+		if err != nil {
+			fmt.Printf("[ERROR] %v\n", err)
+			return
+		}
+		if n := len(memory.Messages()); n > 0 {
+			fmt.Printf("[SESSION] resumed %d messages from %s\n", n, *session)
+		}
+	}
+	if memory != nil && (*memoryMaxMessages > 0 || *memoryMaxTokens > 0) {
+		memory.SetRollover(&MemoryRollover{
+			Model:       chatModel,
+			MaxMessages: *memoryMaxMessages,
+			MaxTokens:   *memoryMaxTokens,
+			Prompt:      *memoryPrompt,
+		})
+	}
+	if memory == nil && *exportOpenAI != "" {
+		// 导出需要完整的对话, 没有 -session 时只在内存中记录
+		memory = NewConversationMemory()
+	}
+
+	var handlers []callbacks.Handler
+	ttft := &TTFTCallback{}
+	if *printTTFT {
+		handlers = append(handlers, ttft)
+	}
+	concurrency := &ConcurrencyCallback{}
+	if *printConcurrency {
+		handlers = append(handlers, concurrency)
+	}
+	runSummary := &RunSummaryCallback{}
+	if cfg.Logging.RunSummary {
+		handlers = append(handlers, runSummary)
+	}
+	toolErrors := &ToolErrorCallback{}
+	if *printToolErrors {
+		handlers = append(handlers, toolErrors)
+	}
+	args := &ToolArgsCallback{}
+	if *argStats {
+		handlers = append(handlers, args.handler())
+	}
+	narrator := &VerboseCallback{}
+	if cfg.Logging.Verbose {
+		handlers = append(handlers, narrator)
+	}
+	events := NewEventCallback(defaultEventBuffer)
+	eventsDone := make(chan struct{})
+	summary := &eventSummary{}
+	if *printEvents {
+		handlers = append(handlers, events)
+		// 只看结构化事件, 不解析日志; 回答的每一帧只计入汇总, tool 事件才打印一行
+		go func() {
+			defer close(eventsDone)
+			for ev := range events.Events() {
+				summary.apply(ev)
+				if _, ok := ev.(ModelContentDelta); !ok {
+					fmt.Printf("[EVENTS] %s\n", summary)
+				}
+			}
+		}()
+	}
+
+	if *serveAddr != "" {
+		server := &sseServer{userID: *userID, newRunner: func(ctx context.Context, handlers []callbacks.Handler) (*AgentRunner, error) {
+			m := chatModel
+			if cfg.Mock() {
+				// 脚本模型按顺序消耗脚本, 每个请求需要一个新的
+				m = newScriptedModel(defaultMockScript()...)
+			}
+			return NewAgentRunner(ctx, &AgentRunnerConfig{
+				ChatModel:          m,
+				Tools:              agentTools,
+				MaxTools:           *maxTools,
+				MaxSteps:           cfg.MaxSteps,
+				MaxToolCalls:       *maxToolCalls,
+				Temperature:        cfg.Temperature,
+				PromptTemplate:     promptTemplate,
+				PromptVars:         map[string]string{"City": *city},
+				SummarizeThreshold: *summarizeThreshold,
+				OTelExporter:       cfg.Logging.OTelExporter,
+				Handlers:           handlers,
+			})
+		}}
+		ln, err := net.Listen("tcp", *serveAddr)
+		if err != nil {
+			dumpDiagnostics(err)
+			return
+		}
+		fmt.Printf("[SSE] listening on %s, e.g. curl -N 'http://localhost%s/chat?query=...'\n", *serveAddr, *serveAddr)
+		if err := serveSSE(ctx, ln, server, *shutdownTimeout); err != nil {
+			dumpDiagnostics(err)
+		}
+		return
+	}
+
+	runner, err := NewAgentRunner(ctx, &AgentRunnerConfig{
+		ChatModel:          chatModel,
+		Tools:              agentTools,
+		MaxTools:           *maxTools,
+		MaxSteps:           cfg.MaxSteps,
+		MaxToolCalls:       *maxToolCalls,
+		Temperature:        cfg.Temperature,
+		PromptTemplate:     promptTemplate,
+		PromptVars:         map[string]string{"City": *city},
+		SummarizeThreshold: *summarizeThreshold,
+		Logger:             logger,
+		OTelExporter:       cfg.Logging.OTelExporter,
+		Memory:             memory,
+		Handlers:           handlers,
+	})
 	if err != nil {
-		fmt.Printf("[ERROR] failed to create chat model: %v\n", err)
+		dumpDiagnostics(err)
 		return
 	}
+	defer func() {
+		if err := runner.Close(context.Background()); err != nil {
+			fmt.Printf("[ERROR] failed to flush spans: %v\n", err)
+		}
+	}()
+
+	userMessage := *query
+	var final string
+	// answerShown 表示回答只在运行结束后打印, 打印的已经是加工后的版本
+	var answerShown bool
+	var steps []*schema.Message
+	runMode := *mode
+	if *repeat > 1 {
+		runMode = "repeat"
+	}
+	switch runMode {
+	case "repeat":
+		var report *ConsistencyReport
+		report, err = runner.RunRepeated(ctx, userMessage, *repeat)
+		if err == nil {
+			fmt.Println(report)
+			final = report.Answers[0]
+		}
+	case "generate":
+		final, err = runner.Run(ctx, userMessage)
+	case "steps":
+		final, steps, err = runner.RunWithSteps(ctx, []*schema.Message{schema.UserMessage(userMessage)})
+		for i, step := range steps {
+			if step.Role == schema.Tool {
+				fmt.Printf("[STEP %d] %s result: %s\n", i+1, step.ToolName, step.Content)
+				continue
+			}
+			for _, tc := range step.ToolCalls {
+				fmt.Printf("[STEP %d] call %s: %s\n", i+1, tc.Function.Name, tc.Function.Arguments)
+			}
+		}
+		if err == nil {
+			fmt.Printf("%v: %v\n", schema.Assistant, processForDisplay(processAnswer, final))
+			answerShown = true
+		}
+	case "graph":
+		final, err = runner.RunGraph(ctx, userMessage)
+		if err == nil {
+			fmt.Printf("%v: %v\n", schema.Assistant, processForDisplay(processAnswer, final))
+			answerShown = true
+		}
+	case "vote":
+		final, err = runner.RunWithVote(ctx, userMessage, *samples)
+		if err == nil {
+			fmt.Printf("%v: %v\n", schema.Assistant, processForDisplay(processAnswer, final))
+			answerShown = true
+		}
+	default:
+		final, err = runner.Stream(ctx, userMessage)
+		if *printTTFT {
+			ttft.Summary()
+		}
+		// Stream 返回时 logger 已经等到所有 tool 的 stream 读完
+		for line := range strings.Lines(logger.Stats.Report()) {
+			fmt.Printf("[STATS] %s", line)
+		}
+	}
+	if cfg.Logging.Verbose {
+		narrator.Wait()
+	}
+	// stream 和 generate 模式边生成边打印回答, 来不及加工, 加工后的版本在最后单独打印一次
+	if processAnswer != nil && !answerShown && err == nil {
+		fmt.Printf("[ANSWER] %s\n", processForDisplay(processAnswer, final))
+	}
+	if *argStats {
+		args.Summary()
+	}
+	if *printConcurrency {
+		concurrency.Summary()
+	}
+	if cfg.Logging.RunSummary {
+		runSummary.Print()
+	}
+	if *printToolErrors {
+		toolErrors.Print()
+	}
+	if *printEvents {
+		events.Close()
+		<-eventsDone
+		fmt.Printf("[EVENTS] final: %s, %d events dropped\n", summary, events.Dropped())
+	}
+	if *exportOpenAI != "" && err == nil {
+		var conversation []*schema.Message
+		switch runMode {
+		case "steps":
+			conversation = append([]*schema.Message{schema.SystemMessage(runner.systemPrompt), schema.UserMessage(userMessage)}, steps...)
+			conversation = append(conversation, schema.AssistantMessage(final, nil))
+		case "graph", "vote", "repeat":
+			// 这几种模式不记录中间的 tool call
+		default:
+			conversation = append([]*schema.Message{schema.SystemMessage(runner.systemPrompt)}, memory.Messages()...)
+		}
+		if conversation == nil {
+			fmt.Printf("[WARN] -export-openai is not supported in %s mode\n", runMode)
+		} else if err := ExportOpenAI(*exportOpenAI, conversation); err != nil {
+			fmt.Printf("[ERROR] failed to export the conversation: %v\n", err)
+		} else {
+			fmt.Printf("[EXPORT] wrote %d messages to %s\n", len(conversation), *exportOpenAI)
+		}
+	}
+	if *answerLang != "" && err == nil {
+		if warning := answerLangMismatch(*answerLang, final); warning != "" {
+			fmt.Printf("[WARN] %s\n", warning)
+		}
+	}
+	if *rateConfidence && err == nil {
+		if c, err := runner.RateConfidence(ctx, userMessage, final); err != nil {
+			fmt.Printf("[ERROR] %v\n", err)
+		} else {
+			fmt.Printf("[CONFIDENCE] %s\n", c)
+		}
+	}
+	logger.LogRunEnd(err, cfg.MaxSteps)
+	if errors.Is(err, errEmptyAnswer) || errors.Is(err, errNoFinalAnswer) {
+		fmt.Printf("[WARN] %v\n", err)
+	} else if errors.Is(err, compose.ErrExceedMaxSteps) {
+		fmt.Printf("[WARN] the agent hit the step limit after %d model calls without an answer (each round of tool calls takes two steps), raise -max-steps or %s to let it keep going\n",
+			logger.ModelCalls(), maxStepsEnv)
+	} else if err != nil {
+		dumpDiagnostics(err)
+	}
+}
+
+// resultCache 在指定 -cache-file 时缓存只读 tool 的结果.
+var resultCache *tools.ResultCache
+
+// defaultTools 返回注册给 agent 的全部 tool.
+func defaultTools() []tool.BaseTool {
+	// 所有 tool 共用的 middleware, 由外到内: 先检查参数大小, 再确认参数是合法的 JSON 对象 (流式输出拼出的参数偶尔不完整), 然后由 guardTool 拦截可疑参数,
+	// 比如 schema 之外的字段或者类似 SQL / 命令注入的字符串; 开启 -provenance 时再标注结果来源, 拒绝信息也会带上来源.
+	// dedup 合并同一轮中重复的调用, 重复的调用直接共享结果, 不再经过其他 middleware; 紧接着由 toolBudgetTool 拒绝超出 -max-tool-calls 的调用,
+	// 合并掉的调用不占用预算. 最外层只在 Info 中带上 tools.SchemaVersion.
+	// 最内层给每次调用单独的超时 (-tool-timeout), 被拒绝的调用不计时
+	middlewares := []tools.ToolMiddleware{
+		tools.ArgSizeLimitMiddleware(*maxToolArgBytes),
+		tools.JSONArgsMiddleware(),
+		tools.GuardMiddleware(),
+		tools.TimeoutMiddleware(*toolTimeout),
+	}
+	if *provenance {
+		middlewares = append([]tools.ToolMiddleware{tools.NewProvenanceTool}, middlewares...)
+	}
+	middlewares = append([]tools.ToolMiddleware{tools.DedupMiddleware(), tools.ToolBudgetMiddleware()}, middlewares...)
+	middlewares = append([]tools.ToolMiddleware{tools.SchemaVersionMiddleware()}, middlewares...)
+	// 只读的 tool 可以缓存, 会修改后端状态的 tool (比如 create_share_link) 每次都要请求后端
+	cached := tools.CacheMiddleware(resultCache)
 
+	// 流式的 format_menu 只实现了 StreamableTool, middleware 都只支持 InvokableTool, ApplyMiddleware 会原样保留它
+	formatMenu := func() tool.BaseTool {
+		t := tools.GetFormatMenuTool(*streamTools)
+		if it, ok := t.(tool.InvokableTool); ok {
+			return cached(it)
+		}
+		return t
+	}
+
+	// 流式的 query_dishes 按评分逐个输出菜品, 同样不经过 middleware
+	dishes := func() tool.BaseTool {
+		if *streamTools {
+			return tools.GetDishStreamTool()
+		}
+		// 结果按用户的饮食偏好筛选, 缓存的 key 里没有用户和偏好, 不缓存
+		return tools.GetDishTool()
+	}
+
+	return tools.ApplyMiddleware([]tool.BaseTool{
+		cached(tools.GetRestaurantTool()),
+		dishes(),
+		cached(tools.GetRestaurantStatsTool()),
+		cached(tools.GetDeliveryTool()),
+		cached(tools.GetFindRestaurantByNameTool()),
+		cached(tools.GetAllergensTool()),
+		cached(tools.GetIngredientsTool()),
+		tools.GetShareLinkTool(),
+		tools.GetMenuQRTool(),
+		tools.GetBookTableTool(),
+		cached(tools.GetChefTool()),
+		tools.GetComputeBillTool(),
+		tools.GetSplitBillTool(),
+		cached(tools.GetAmbianceTool()),
+		cached(tools.GetSimilarRestaurantsTool()),
+		cached(tools.GetMostReviewedTool()),
+		cached(tools.GetCategoryRankTool()),
+		cached(tools.GetCravingRecommendTool()),
+		cached(tools.GetBusyHoursTool()),
+		tools.GetValidateReservationTimeTool(), // 结果取决于当前时间, 不缓存
+		tools.GetTakeoutQueueTool(),            // 同上
+		tools.GetEventsTool(),                  // 同上
+		tools.GetTrendingDishTool(),            // 同上
+		tools.GetTableETATool(),                // 同上
+		tools.GetDishAvailabilityTool(),        // 同上
+		tools.GetNoiseLevelTool(),              // 同上
+		tools.GetSeasonalMenuTool(),            // 同上
+		tools.GetSuggestAlternativeTool(),      // 同上
+		tools.GetBestReservationTimeTool(),     // 同上
+		tools.GetWeeklyAvailabilityTool(),      // 同上
+		tools.GetWalkInPolicyTool(),            // 同上
+		cached(tools.GetPriceTierTool()),
+		cached(tools.GetBudgetSearchTool()),
+		cached(tools.GetRestaurantSummaryTool()),
+		cached(tools.GetBatchRestaurantInfoTool()),
+		cached(tools.GetNutritionTool()),
+		cached(tools.GetStaticMapTool()),
+		cached(tools.GetDirectionsTool()),
+		cached(tools.GetAccessibilityTool()),
+		cached(tools.GetMealDurationTool()),
+		cached(tools.GetTripCostTool()),
+		cached(tools.GetParkingCostTool()),
+		cached(tools.GetDeliveryVsDineInTool()),
+		cached(tools.GetWeatherTool()),
+		cached(tools.GetCertificationsTool()),
+		cached(tools.GetDishOfTheDayTool()),
+		cached(tools.GetMealNutritionTool()),
+		cached(tools.GetPortionInfoTool()),
+		cached(tools.GetCookingMethodTool()),
+		cached(tools.GetCheapestGroupMealTool()),
+		cached(tools.GetTastingMenuTool()),
+		cached(tools.GetCarbonFootprintTool()),
+		cached(tools.GetCompareDishTool()),
+		cached(tools.GetDrinkPairingTool()),
+		cached(tools.GetMenuInCurrencyTool()),
+		cached(tools.GetSocialMediaTool()),
+		cached(tools.GetContactInfoTool()),
+		tools.GetReportRestaurantTool(),
+		// 以下 tool 的结果因用户而异, 缓存的 key 里没有用户, 不缓存
+		tools.GetRecommendDishesTool(),
+		tools.GetLoyaltyInfoTool(),
+		tools.GetSaveRestaurantTool(),
+		tools.GetListSavedTool(),
+		tools.GetUnsaveRestaurantTool(),
+		tools.GetSetPreferenceTool(),
+		tools.GetPreferencesTool(),
+		tools.GetGreetingTool(),
+		formatMenu(),
+	}, middlewares...)
+}
+
+// newAgent 创建 ReAct agent, maxSteps 为 0 时使用 react 的默认值.
+func newAgent(ctx context.Context, chatModel model.ToolCallingChatModel, agentTools []tool.BaseTool,
+	summarizeThreshold, maxSteps int) (*react.Agent, error) {
 	toolCallChecker := func(ctx context.Context, sr *schema.StreamReader[*schema.Message]) (bool, error) {
 		defer sr.Close()
 		for {
@@ -65,67 +634,226 @@ func main() {
 		}
 	}
 
-	ragent, err := react.NewAgent(ctx, &react.AgentConfig{
-		ToolCallingModel:      arkModel,
+	return react.NewAgent(ctx, &react.AgentConfig{
+		ToolCallingModel:      chatModel,
 		StreamToolCallChecker: toolCallChecker,
+		MessageRewriter:       newToolResultSummarizer(chatModel, summarizeThreshold),
 		ToolsConfig: compose.ToolsNodeConfig{
-			Tools: []tool.BaseTool{
-				tools.GetRestaurantTool(),
-				tools.GetDishTool(),
-			},
+			Tools: agentTools,
 		},
+		MaxStep: maxSteps,
 	})
-	if err != nil {
-		fmt.Printf("[ERROR] failed to create agent: %v\n", err)
-		return
-	}
+}
 
-	sr, err := ragent.Stream(ctx, []*schema.Message{
-		{
-			Role:    schema.System,
-			Content: `# Character:
-你是一个帮助用户推荐餐厅和菜品的助手，根据用户的需要，查询餐厅信息并推荐，查询餐厅的菜品并推荐。
-`,
-		},
-		{
-			Role:    schema.User,
-			Content: "我在北京，给我推荐一些菜，需要有口味辣一点的菜，至少推荐有 2 家餐厅",
-		},
-	}, agent.WithComposeOptions(compose.WithCallbacks(&LoggerCallback{})))
+// emptyAnswerRetries 是最终回答为空时的重试次数.
+const emptyAnswerRetries = 1
+
+// runStream 流式运行 agent 并读完整个 stream. logger 不为空时, 会等它的流式输出全部打印完再返回.
+func runStream(ctx context.Context, ragent *react.Agent, messages []*schema.Message, logger *LoggerCallback,
+	opts ...agent.AgentOption) (*schema.Message, error) {
+	sr, err := ragent.Stream(ctx, messages, opts...)
 	if err != nil {
-		fmt.Printf("[ERROR] failed to stream: %v\n", err)
-		return
+		return nil, fmt.Errorf("failed to stream: %w", err)
 	}
 
 	defer sr.Close()
 
-	fmt.Printf("[STREAM] Start streaming...\n\n")
+	logger.log().Info("stream started", "component", "stream")
 
 	// Drain the stream to ensure all callbacks are executed and the stream completes
+	var chunks []*schema.Message
 	for {
-		_, err := sr.Recv()
+		chunk, err := sr.Recv()
 		if err != nil {
 			if errors.Is(err, io.EOF) {
 				break
 			}
-			fmt.Printf("[ERROR] failed to recv: %v\n", err)
-			return
+			return nil, fmt.Errorf("failed to recv: %w", err)
+		}
+		chunks = append(chunks, chunk)
+	}
+
+	if logger != nil {
+		logger.Wait()
+	}
+	logger.log().Info("stream finished", "component", "stream", "chunks", len(chunks))
+
+	return streamAnswer(chunks)
+}
+
+// streamAnswer 把 agent 输出的帧拼成最终回答. 帧里只有 tool call 而没有任何 content 时,
+// 说明 ReAct 循环在最终回答之前就结束了, 打印诊断信息并返回 errNoFinalAnswer, 而不是当作一个空的成功.
+// 完全没有帧时返回 nil, 由调用方按空响应处理.
+func streamAnswer(chunks []*schema.Message) (*schema.Message, error) {
+	if len(chunks) == 0 {
+		return nil, nil
+	}
+
+	var sawContent bool
+	toolCalls := 0
+	for _, chunk := range chunks {
+		if chunk.Content != "" {
+			sawContent = true
 		}
+		toolCalls += len(chunk.ToolCalls)
+	}
+	if !sawContent && toolCalls > 0 {
+		fmt.Printf("[WARN] the stream ended after %d chunks with %d tool call chunks and no content\n", len(chunks), toolCalls)
+		return nil, errNoFinalAnswer
+	}
+	return schema.ConcatMessages(chunks)
+}
+
+func runGenerate(ctx context.Context, ragent *react.Agent, messages []*schema.Message, opts ...agent.AgentOption) (*schema.Message, error) {
+	msg, err := ragent.Generate(ctx, messages, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate: %w", err)
 	}
+	if msg.Content != "" {
+		fmt.Printf("%v: %v\n", schema.Assistant, msg.Content)
+	}
+	return msg, nil
+}
 
-	fmt.Printf("\n[STREAM] Finished\n")
+// isEmptyAnswer 判断最终回答是否完全为空: 既没有 content, 也没有 tool call.
+func isEmptyAnswer(msg *schema.Message) bool {
+	return msg == nil || (strings.TrimSpace(msg.Content) == "" && len(msg.ToolCalls) == 0)
 }
 
+// defaultMaxResponseLen 是 LoggerCallback 日志中 tool 结果默认保留的字节数.
+const defaultMaxResponseLen = 200
+
 type LoggerCallback struct {
 	callbacks.HandlerBuilder
+
+	// Out 是流式回答的输出, 为空时输出到 os.Stdout.
+	Out io.Writer
+
+	// Logger 记录 tool 调用、流式 tool 的每一帧和错误, 每条记录带有 component、tool_name、args、success 等字段,
+	// 方便在测试中捕获或输出成机器可读的格式. 为空时用 text handler 写到 Out, Out 也为空时写到 os.Stderr, 和回答分开.
+	Logger *slog.Logger
+
+	// MaxResponseLen 是日志中 tool 结果最多保留的字节数, 超出的部分截断为 "...".
+	// 0 表示使用 defaultMaxResponseLen, 负数表示不截断.
+	MaxResponseLen int
+
+	// FlushInterval 和 FlushBytes 控制流式回答的缓冲: 攒够 FlushBytes 字节或经过 FlushInterval 才打印一次.
+	// FlushInterval 为 0 时每一帧都立即打印.
+	FlushInterval time.Duration
+	FlushBytes    int
+
+	// Stats 不为空时按 tool 名累计调用次数、成功次数和耗时, 是否成功以 ToolExecutionState.Success 为准.
+	// 流式 tool 在 stream 读完后才计入, 读取之前需要调用 Wait.
+	Stats *tools.RunStats
+
+	// redact 在打印 tool 的参数和结果之前对其脱敏, 由 NewRedactingLogger 设置, 为空时原样打印.
+	redact func(string) string
+
+	mu sync.Mutex     // 保护 Out, tool 回调和流式输出的 goroutine 会并发写入
+	wg sync.WaitGroup // 跟踪 OnEndWithStreamOutput 中启动的 goroutine
+
+	loggerOnce sync.Once
+	logger     *slog.Logger
+
+	modelCalls atomic.Int64 // ChatModel 调用结束的次数, 见 ModelCalls
+}
+
+func (cb *LoggerCallback) printf(format string, a ...any) {
+	out := cb.Out
+	if out == nil {
+		out = os.Stdout
+	}
+	_, _ = fmt.Fprintf(lockedWriter{cb: cb, w: out}, format, a...)
+}
+
+// log 返回记录日志的 logger, 见 Logger. cb 为空时也可以调用, 返回写到 os.Stderr 的 logger.
+func (cb *LoggerCallback) log() *slog.Logger {
+	if cb == nil {
+		return slog.New(slog.NewTextHandler(os.Stderr, nil))
+	}
+	cb.loggerOnce.Do(func() {
+		cb.logger = cb.Logger
+		if cb.logger == nil {
+			var out io.Writer = os.Stderr
+			if cb.Out != nil {
+				out = cb.Out
+			}
+			cb.logger = slog.New(slog.NewTextHandler(lockedWriter{cb: cb, w: out}, nil))
+		}
+	})
+	return cb.logger
+}
+
+// lockedWriter 在 cb.mu 的保护下写入 w, 日志和流式回答写到同一个 Out 时不会交错.
+type lockedWriter struct {
+	cb *LoggerCallback
+	w  io.Writer
+}
+
+func (w lockedWriter) Write(p []byte) (int, error) {
+	w.cb.mu.Lock()
+	defer w.cb.mu.Unlock()
+	return w.w.Write(p)
+}
+
+// truncateResponse 按 MaxResponseLen 截断 tool 结果, 不会截断在一个 UTF-8 字符的中间.
+func (cb *LoggerCallback) truncateResponse(s string) string {
+	limit := cb.MaxResponseLen
+	if limit == 0 {
+		limit = defaultMaxResponseLen
+	}
+	if limit < 0 || len(s) <= limit {
+		return s
+	}
+	for limit > 0 && !utf8.RuneStart(s[limit]) {
+		limit--
+	}
+	return s[:limit] + "..."
+}
+
+func (cb *LoggerCallback) redacted(s string) string {
+	if cb.redact == nil {
+		return s
+	}
+	return cb.redact(s)
+}
+
+// Wait 等待所有流式输出的 goroutine 结束. agent 的 stream 被读完时, 回调里的流可能还没打印完,
+// 在退出或打印结束信息之前调用 Wait, 才能保证完整的回答已经输出.
+func (cb *LoggerCallback) Wait() {
+	cb.wg.Wait()
+}
+
+// ModelCalls 返回到目前为止 ChatModel 调用结束的次数, 也就是模型思考了几轮.
+// 它和 -max-steps 的单位不同: max_steps 数的是 graph 的节点, 每轮 tool 调用占两步 (chat model + tools).
+// 只在同一次运行中使用时才是这次运行的次数, 回答的后处理等额外的模型调用也会计入.
+func (cb *LoggerCallback) ModelCalls() int {
+	return int(cb.modelCalls.Load())
+}
+
+// LogRunEnd 在运行结束后记录 ChatModel 的调用次数和结束的原因: 模型自然给出回答、因为达到 maxSteps 被终止, 或者出错.
+// maxSteps 是配置的步数上限 (graph 的步数, 见 ModelCalls), 0 表示使用 react 的默认值.
+func (cb *LoggerCallback) LogRunEnd(err error, maxSteps int) {
+	attrs := []any{"model_calls", cb.ModelCalls()}
+	if maxSteps > 0 {
+		attrs = append(attrs, "max_steps", maxSteps)
+	}
+	switch {
+	case errors.Is(err, compose.ErrExceedMaxSteps):
+		cb.log().Warn("agent terminated by the step limit", append(attrs, "stop_reason", "step_limit")...)
+	case err != nil:
+		cb.log().Error("agent failed", append(attrs, "stop_reason", "error", "error", err)...)
+	default:
+		cb.log().Info("agent finished", append(attrs, "stop_reason", "final_answer")...)
+	}
 }
 
 func (cb *LoggerCallback) OnStart(ctx context.Context, info *callbacks.RunInfo, input callbacks.CallbackInput) context.Context {
 	if info.Component == components.ComponentOfTool {
 		tci := tool.ConvCallbackInput(input)
 		if tci != nil {
-			fmt.Printf("[TOOL] %s: %s\n", info.Name, tci.ArgumentsInJSON)
-			
+			cb.log().Info("tool call", "component", info.Component, "tool_name", info.Name, "args", cb.redacted(tci.ArgumentsInJSON))
+
 			// 创建工具执行状态并存入 context
 			// 使用指针，这样在 InvokableRun 中修改后，OnEnd 中可以读取到修改后的值
 			state := &tools.ToolExecutionState{
@@ -133,37 +861,63 @@ func (cb *LoggerCallback) OnStart(ctx context.Context, info *callbacks.RunInfo,
 			}
 			ctx = tools.SetToolState(ctx, state)
 		}
+		if cb.Stats != nil {
+			ctx = context.WithValue(ctx, toolStartKey{}, time.Now())
+		}
 	}
 	return ctx
 }
 
+type toolStartKey struct{}
+
+// recordTool 把一次 tool 调用计入 Stats, 耗时从 OnStart 开始算. failed 为 true 时不看 ToolExecutionState.
+func (cb *LoggerCallback) recordTool(ctx context.Context, name string, failed bool) {
+	if cb.Stats == nil {
+		return
+	}
+	var elapsed time.Duration
+	if start, ok := ctx.Value(toolStartKey{}).(time.Time); ok {
+		elapsed = time.Since(start)
+	}
+	state := tools.GetToolState(ctx)
+	cb.Stats.Record(name, !failed && state != nil && state.Success, elapsed)
+}
+
 func (cb *LoggerCallback) OnEnd(ctx context.Context, info *callbacks.RunInfo, output callbacks.CallbackOutput) context.Context {
+	if info.Component == components.ComponentOfChatModel {
+		cb.modelCalls.Add(1)
+	}
 	if info.Component == components.ComponentOfTool {
 		tco := tool.ConvCallbackOutput(output)
 		if tco != nil {
-			responseStr := tco.Response
-			if len(responseStr) > 200 {
-				responseStr = responseStr[:200] + "..."
-			}
-			fmt.Printf("[TOOL] %s: result = %s\n", info.Name, responseStr)
-			
-			// 读取工具执行状态（在 OnStart 中创建，在 InvokableRun 中修改）
-			state := tools.GetToolState(ctx)
-			if state != nil {
-				// 判断工具调用是否成功
-				if state.Success {
-					fmt.Printf("[TOOL] %s: execution succeeded\n", info.Name)
-				} else {
-					fmt.Printf("[TOOL] %s: execution failed\n", info.Name)
+			attrs := []any{"component", info.Component, "tool_name", info.Name, "result", cb.truncateResponse(cb.redacted(tco.Response))}
+
+			// 读取工具执行状态（在 OnStart 中创建，在 InvokableRun 中修改）, 判断工具调用是否成功
+			if state := tools.GetToolState(ctx); state != nil {
+				attrs = append(attrs, "success", state.Success)
+				if state.ErrMsg != "" {
+					attrs = append(attrs, "error", state.ErrMsg)
+				}
+				if state.Attempts > 1 {
+					attrs = append(attrs, "attempts", state.Attempts, "retry_delay", state.RetryDelay)
 				}
 			}
+			cb.log().Info("tool result", attrs...)
 		}
+		cb.recordTool(ctx, info.Name, false)
 	}
 	return ctx
 }
 
 func (cb *LoggerCallback) OnError(ctx context.Context, info *callbacks.RunInfo, err error) context.Context {
-	fmt.Printf("[ERROR] [%s:%s:%s] %v\n", info.Component, info.Type, info.Name, err)
+	attrs := []any{"component", info.Component, "type", info.Type, "name", info.Name, "error", err}
+	if desc := describeContextError(err); desc != "" {
+		attrs = append(attrs, "reason", desc)
+	}
+	cb.log().Error("component failed", attrs...)
+	if info.Component == components.ComponentOfTool {
+		cb.recordTool(ctx, info.Name, true)
+	}
 	return ctx
 }
 
@@ -177,21 +931,76 @@ func (cb *LoggerCallback) OnEndWithStreamOutput(ctx context.Context, info *callb
 	output *schema.StreamReader[callbacks.CallbackOutput]) context.Context {
 	// Only handle ChatModel stream output to avoid blocking by toolCallChecker
 	if info.Component == components.ComponentOfChatModel {
+		cb.modelCalls.Add(1)
+		buffer := newStreamBuffer(func(content string) {
+			cb.printf("%v: %v\n", schema.Assistant, content)
+		}, cb.FlushInterval, cb.FlushBytes)
+
+		// Recv 不响应 ctx, 所以由单独的 goroutine 读 stream, 打印的 goroutine 在 ctx 被取消时直接返回,
+		// 不会因为一个不再结束的 stream 一直阻塞 Wait. 读取的 goroutine 在 Recv 返回后关闭 stream.
+		type recvResult struct {
+			frame callbacks.CallbackOutput
+			err   error
+		}
+		frames := make(chan recvResult)
 		go func() {
 			defer output.Close()
 			for {
 				frame, err := output.Recv()
+				select {
+				case frames <- recvResult{frame: frame, err: err}:
+				case <-ctx.Done():
+					return
+				}
 				if err != nil {
-					if errors.Is(err, io.EOF) {
-						break
+					return
+				}
+			}
+		}()
+
+		cb.wg.Add(1)
+		go func() {
+			defer cb.wg.Done()
+			// 无论是读到 EOF、出错还是 ctx 被取消, 都把缓冲区中剩余的内容输出
+			defer buffer.Flush()
+
+			for {
+				var res recvResult
+				select {
+				case <-ctx.Done():
+					return
+				case res = <-frames:
+				}
+				if res.err != nil {
+					if !errors.Is(res.err, io.EOF) {
+						cb.log().Error("failed to recv from stream", "component", info.Component, "name", info.Name, "error", res.err)
 					}
-					fmt.Printf("[ERROR] failed to recv from stream: %v\n", err)
 					return
 				}
-				if cbo := model.ConvCallbackOutput(frame); cbo != nil && cbo.Message != nil {
-					if cbo.Message.Content != "" {
-						fmt.Printf("%v: %v\n", schema.Assistant, cbo.Message.Content)
+				if cbo := model.ConvCallbackOutput(res.frame); cbo != nil && cbo.Message != nil {
+					buffer.Add(cbo.Message.Content)
+				}
+			}
+		}()
+	} else if info.Component == components.ComponentOfTool {
+		// 流式 tool (-stream-tools) 的结果不经过 OnEnd, 在这里逐帧打印; 必须读完并关闭 stream, 否则会阻塞 tool
+		cb.wg.Add(1)
+		go func() {
+			defer cb.wg.Done()
+			defer output.Close()
+			for {
+				frame, err := output.Recv()
+				if err != nil {
+					failed := !errors.Is(err, io.EOF)
+					if failed {
+						cb.log().Error("tool stream failed", "component", info.Component, "tool_name", info.Name, "error", err)
 					}
+					cb.recordTool(ctx, info.Name, failed)
+					return
+				}
+				if tco := tool.ConvCallbackOutput(frame); tco != nil {
+					cb.log().Info("tool stream frame", "component", info.Component, "tool_name", info.Name,
+						"frame", cb.redacted(strings.TrimRight(tco.Response, "\n")))
 				}
 			}
 		}()
@@ -0,0 +1,364 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cloudwego/eino-examples/flow/agent/react/tools"
+	"github.com/cloudwego/eino/callbacks"
+	"github.com/cloudwego/eino/components"
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/components/tool"
+	"github.com/cloudwego/eino/compose"
+	"github.com/cloudwego/eino/flow/agent"
+	"github.com/cloudwego/eino/schema"
+	"github.com/stretchr/testify/assert"
+)
+
+// toolRecorder 按调用顺序记录 tool 名称和返回结果, 并发的 tool 调用也是安全的.
+type toolRecorder struct {
+	mu      sync.Mutex
+	names   []string
+	results []string
+}
+
+func (r *toolRecorder) handler() callbacks.Handler {
+	return callbacks.NewHandlerBuilder().OnStartFn(r.onStart).OnEndFn(r.onEnd).Build()
+}
+
+func (r *toolRecorder) onEnd(ctx context.Context, info *callbacks.RunInfo, output callbacks.CallbackOutput) context.Context {
+	if info.Component == components.ComponentOfTool {
+		if tco := tool.ConvCallbackOutput(output); tco != nil {
+			r.mu.Lock()
+			r.results = append(r.results, tco.Response)
+			r.mu.Unlock()
+		}
+	}
+	return ctx
+}
+
+func (r *toolRecorder) onStart(ctx context.Context, info *callbacks.RunInfo, input callbacks.CallbackInput) context.Context {
+	if info.Component == components.ComponentOfTool {
+		r.mu.Lock()
+		r.names = append(r.names, info.Name)
+		r.mu.Unlock()
+	}
+	return ctx
+}
+
+func (r *toolRecorder) calls() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]string(nil), r.names...)
+}
+
+func TestAgentToolOrder(t *testing.T) {
+	tools.SetFailureRate(0)
+	defer tools.SetFailureRate(tools.DefaultFailureRate)
+	ctx := context.Background()
+	messages := []*schema.Message{schema.UserMessage("我在北京，给我推荐一些辣的菜")}
+	want := []string{"query_restaurants", "query_dishes", "query_dishes"}
+
+	t.Run("generate", func(t *testing.T) {
+		ragent, err := newAgent(ctx, newScriptedModel(defaultMockScript()...), defaultTools(), *summarizeThreshold, 0)
+		assert.NoError(t, err)
+
+		recorder := &toolRecorder{}
+		msg, err := ragent.Generate(ctx, messages, agent.WithComposeOptions(compose.WithCallbacks(recorder.handler())))
+		assert.NoError(t, err)
+		assert.NotEmpty(t, msg.Content)
+		assert.Equal(t, want, recorder.calls())
+
+		// 剧本只查询 query_restaurants 返回过的餐厅的菜品
+		if assert.Len(t, recorder.results, 3) {
+			for _, dish := range recorder.results[1:] {
+				assert.NotContains(t, dish, "not found")
+			}
+		}
+		for _, m := range defaultMockScript() {
+			for _, tc := range m.ToolCalls {
+				if tc.Function.Name != "query_dishes" {
+					continue
+				}
+				var args struct {
+					RestaurantID string `json:"restaurant_id"`
+				}
+				assert.NoError(t, json.Unmarshal([]byte(tc.Function.Arguments), &args))
+				assert.Contains(t, recorder.results[0], fmt.Sprintf(`"id":"%s"`, args.RestaurantID))
+			}
+		}
+	})
+
+	t.Run("stream", func(t *testing.T) {
+		ragent, err := newAgent(ctx, newScriptedModel(defaultMockScript()...), defaultTools(), *summarizeThreshold, 0)
+		assert.NoError(t, err)
+
+		recorder := &toolRecorder{}
+		msg, err := runStream(ctx, ragent, messages, nil, agent.WithComposeOptions(compose.WithCallbacks(recorder.handler())))
+		assert.NoError(t, err)
+		assert.False(t, isEmptyAnswer(msg))
+		assert.Equal(t, want, recorder.calls())
+	})
+}
+
+func TestAgentDegradesWithBrokenTool(t *testing.T) {
+	t.Setenv(tools.BrokenDishToolEnv, "true")
+	ctx := context.Background()
+
+	ragent, err := newAgent(ctx, newScriptedModel(defaultMockScript()...), defaultTools(), *summarizeThreshold, 0)
+	assert.NoError(t, err)
+
+	recorder := &toolRecorder{}
+	msg, err := ragent.Generate(ctx, []*schema.Message{schema.UserMessage("我在北京，给我推荐一些辣的菜")},
+		agent.WithComposeOptions(compose.WithCallbacks(recorder.handler())))
+	assert.NoError(t, err)
+	assert.False(t, isEmptyAnswer(msg))
+
+	var brokenResults int
+	for _, result := range recorder.results {
+		if strings.Contains(result, "service permanently unavailable") {
+			brokenResults++
+		}
+	}
+	assert.Equal(t, 2, brokenResults)
+}
+
+func TestRunStreamWaitsForCallbackOutput(t *testing.T) {
+	ctx := context.Background()
+	ragent, err := newAgent(ctx, newScriptedModel(defaultMockScript()...), defaultTools(), *summarizeThreshold, 0)
+	assert.NoError(t, err)
+
+	var buf bytes.Buffer
+	logger := &LoggerCallback{Out: &buf}
+	msg, err := runStream(ctx, ragent, []*schema.Message{schema.UserMessage("我在北京，给我推荐一些辣的菜")}, logger,
+		agent.WithComposeOptions(compose.WithCallbacks(logger)))
+	assert.NoError(t, err)
+
+	// runStream 返回时, 回调必须已经把完整的回答打印出来
+	var printed strings.Builder
+	for _, line := range strings.Split(buf.String(), "\n") {
+		if content, ok := strings.CutPrefix(line, string(schema.Assistant)+": "); ok {
+			printed.WriteString(content)
+		}
+	}
+	assert.Equal(t, msg.Content, printed.String())
+}
+
+type writerFunc func(p []byte) (int, error)
+
+func (f writerFunc) Write(p []byte) (int, error) {
+	return f(p)
+}
+
+func TestLoggerCallbackStreamStopsOnCancel(t *testing.T) {
+	baseline := runtime.NumGoroutine()
+
+	// 一个不会自己结束的 stream: 发出一帧之后一直阻塞, 直到 release 被关闭
+	sr, sw := schema.Pipe[callbacks.CallbackOutput](0)
+	release := make(chan struct{})
+	go func() {
+		defer sw.Close()
+		sw.Send(&model.CallbackOutput{Message: schema.AssistantMessage("你好", nil)}, nil)
+		<-release
+	}()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	printed := make(chan struct{})
+	logger := &LoggerCallback{Out: writerFunc(func(p []byte) (int, error) {
+		close(printed)
+		return len(p), nil
+	})}
+	logger.OnEndWithStreamOutput(ctx, &callbacks.RunInfo{Component: components.ComponentOfChatModel}, sr)
+
+	// 第一帧打印出来之后再取消, 这时打印的 goroutine 正在等下一帧
+	<-printed
+	cancel()
+	done := make(chan struct{})
+	go func() {
+		logger.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Wait still blocked after the context was cancelled")
+	}
+	// stream 结束后读取的 goroutine 也退出, 没有泄漏
+	close(release)
+	// assert.Eventually 自己会启动 goroutine, 这里手动轮询
+	deadline := time.Now().Add(time.Second)
+	for runtime.NumGoroutine() > baseline && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	assert.LessOrEqual(t, runtime.NumGoroutine(), baseline)
+}
+
+func TestLoggerCallbackStructuredLogs(t *testing.T) {
+	var buf bytes.Buffer
+	logger := &LoggerCallback{Logger: slog.New(slog.NewJSONHandler(&buf, nil)), MaxResponseLen: 12}
+
+	info := &callbacks.RunInfo{Name: "query_dishes", Component: components.ComponentOfTool}
+	ctx := logger.OnStart(context.Background(), info, &tool.CallbackInput{ArgumentsInJSON: `{"restaurant_id":"1001"}`})
+	tools.GetToolState(ctx).Success = true
+	logger.OnEnd(ctx, info, &tool.CallbackOutput{Response: `[{"name":"红烧肉","price":20}]`})
+	logger.OnError(context.Background(), info, errors.New("boom"))
+
+	var records []map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		var record map[string]any
+		assert.NoError(t, json.Unmarshal([]byte(line), &record))
+		records = append(records, record)
+	}
+	assert.Len(t, records, 3)
+
+	assert.Equal(t, "tool call", records[0]["msg"])
+	assert.Equal(t, "Tool", records[0]["component"])
+	assert.Equal(t, "query_dishes", records[0]["tool_name"])
+	assert.Equal(t, `{"restaurant_id":"1001"}`, records[0]["args"])
+
+	// 超过 MaxResponseLen 的结果被截断, 不会截断在汉字的中间
+	assert.Equal(t, "tool result", records[1]["msg"])
+	assert.Equal(t, "query_dishes", records[1]["tool_name"])
+	assert.Equal(t, true, records[1]["success"])
+	assert.Equal(t, `[{"name":"...`, records[1]["result"])
+
+	assert.Equal(t, "ERROR", records[2]["level"])
+	assert.Equal(t, "boom", records[2]["error"])
+
+	// 负数表示不截断
+	long := strings.Repeat("x", defaultMaxResponseLen+1)
+	assert.Equal(t, long[:defaultMaxResponseLen]+"...", (&LoggerCallback{}).truncateResponse(long))
+	assert.Equal(t, long, (&LoggerCallback{MaxResponseLen: -1}).truncateResponse(long))
+}
+
+func TestRunStreamWithStreamableTool(t *testing.T) {
+	ctx := context.Background()
+	script := []*schema.Message{
+		toolCallMessage("call_1", "format_menu", `{"restaurant_id":"1001"}`),
+		schema.AssistantMessage("云边小馆的菜单已经列出来了.", nil),
+	}
+	ragent, err := newAgent(ctx, newScriptedModel(script...), []tool.BaseTool{tools.GetFormatMenuTool(true)}, 0, 0)
+	assert.NoError(t, err)
+
+	var buf bytes.Buffer
+	logger := &LoggerCallback{Out: &buf}
+	recorder := &toolRecorder{}
+	_, err = runStream(ctx, ragent, []*schema.Message{schema.UserMessage("云边小馆有什么菜?")}, logger,
+		agent.WithComposeOptions(compose.WithCallbacks(logger, recorder.handler())))
+	assert.NoError(t, err)
+
+	// 流式 tool 的每一行都由 OnEndWithStreamOutput 打印
+	assert.Contains(t, buf.String(), `msg="tool stream frame" component=Tool tool_name=format_menu frame="云边小馆 菜单`)
+	assert.Contains(t, buf.String(), `msg="tool stream frame" component=Tool tool_name=format_menu frame="1. `)
+	assert.Equal(t, []string{"format_menu"}, recorder.calls())
+}
+
+func TestStreamAnswer(t *testing.T) {
+	msg, err := streamAnswer(nil)
+	assert.NoError(t, err)
+	assert.Nil(t, msg)
+
+	msg, err = streamAnswer([]*schema.Message{schema.AssistantMessage("你好", nil), schema.AssistantMessage(", 推荐云边小馆", nil)})
+	assert.NoError(t, err)
+	assert.Equal(t, "你好, 推荐云边小馆", msg.Content)
+
+	// 只有 tool call 的帧: 循环没有走到最终回答
+	_, err = streamAnswer([]*schema.Message{toolCallMessage("call_1", "query_restaurants", `{"location":"北京"}`)})
+	assert.ErrorIs(t, err, errNoFinalAnswer)
+
+	// 完全空的帧交给空响应的重试处理
+	msg, err = streamAnswer([]*schema.Message{schema.AssistantMessage("", nil)})
+	assert.NoError(t, err)
+	assert.True(t, isEmptyAnswer(msg))
+}
+
+// fragmentedModel 和 scriptedModel 一样按顺序返回脚本, 但流式输出时把 tool call 的参数按 n 个字节一帧切开,
+// 像真实的模型一样逐帧输出参数, 由 eino 按 Index 拼起来.
+type fragmentedModel struct {
+	*scriptedModel
+	n int
+}
+
+func (m *fragmentedModel) Stream(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.StreamReader[*schema.Message], error) {
+	msg, err := m.next(input)
+	if err != nil {
+		return nil, err
+	}
+
+	frames := []*schema.Message{{Role: msg.Role, Content: msg.Content}}
+	for i, tc := range msg.ToolCalls {
+		index := i
+		frames = append(frames, &schema.Message{Role: msg.Role, ToolCalls: []schema.ToolCall{
+			{Index: &index, ID: tc.ID, Type: tc.Type, Function: schema.FunctionCall{Name: tc.Function.Name}},
+		}})
+		for args := tc.Function.Arguments; args != ""; {
+			chunk := args[:min(m.n, len(args))]
+			args = args[len(chunk):]
+			frames = append(frames, &schema.Message{Role: msg.Role, ToolCalls: []schema.ToolCall{
+				{Index: &index, Function: schema.FunctionCall{Arguments: chunk}},
+			}})
+		}
+	}
+	return schema.StreamReaderFromArray(frames), nil
+}
+
+func (m *fragmentedModel) WithTools(tools []*schema.ToolInfo) (model.ToolCallingChatModel, error) {
+	return m, nil
+}
+
+func TestRunStreamWithMalformedToolArguments(t *testing.T) {
+	// strict 模式下 tool 的错误会中断 agent, 不合法的参数仍然作为提示交给模型
+	tools.SetStrictMode(true)
+	defer tools.SetStrictMode(false)
+
+	ctx := context.Background()
+	script := &fragmentedModel{n: 5, scriptedModel: newScriptedModel(
+		// 参数在输出中途被截断, 拼起来不是合法的 JSON
+		toolCallMessage("call_1", "query_dishes", `{"restaurant_id":"1001","topn":`),
+		toolCallMessage("call_2", "query_dishes", `{"restaurant_id":"1001","topn":2}`),
+		schema.AssistantMessage("推荐云边小馆的红烧肉.", nil),
+	)}
+	ragent, err := newAgent(ctx, script, defaultTools(), 0, 0)
+	assert.NoError(t, err)
+
+	recorder := &toolRecorder{}
+	msg, err := runStream(ctx, ragent, []*schema.Message{schema.UserMessage("云边小馆有什么菜?")}, nil,
+		agent.WithComposeOptions(compose.WithCallbacks(recorder.handler())))
+	assert.NoError(t, err)
+	assert.Equal(t, "推荐云边小馆的红烧肉.", msg.Content)
+
+	assert.Equal(t, []string{"query_dishes", "query_dishes"}, recorder.calls())
+	inputs := script.inputs
+	if assert.Len(t, inputs, 3) {
+		corrective := inputs[1][len(inputs[1])-1]
+		assert.Equal(t, schema.Tool, corrective.Role)
+		assert.Contains(t, corrective.Content, "your tool arguments were not valid JSON")
+		// 重新生成的参数逐帧拼起来是完整的
+		assert.Contains(t, inputs[2][len(inputs[2])-1].Content, "韩式辣白菜")
+	}
+}
@@ -0,0 +1,118 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"regexp"
+	"strings"
+)
+
+// RedactionRule 描述一条脱敏规则. Field 不为空时, JSON 中名为 Field 的字段的值整体替换为 [REDACTED:Name];
+// 否则把所有字符串中匹配 Pattern 的部分替换为 [REDACTED:Name].
+type RedactionRule struct {
+	Name    string
+	Field   string
+	Pattern *regexp.Regexp
+}
+
+// DefaultRedactionRules 返回默认的脱敏规则: 手机号、邮箱, query_delivery 的 address 字段,
+// 以及 query_contact_info 的 phone 和 call_link 字段 (tel: 链接中的号码不是常见的写法, Pattern 匹配不到).
+func DefaultRedactionRules() []RedactionRule {
+	return []RedactionRule{
+		{Name: "address", Field: "address"},
+		{Name: "phone", Field: "phone"},
+		{Name: "phone", Field: "call_link"},
+		{Name: "email", Pattern: regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`)},
+		{Name: "phone", Pattern: regexp.MustCompile(`(\+?86[ -]?)?1[3-9]\d{9}\b|\b\d{3,4}-\d{7,8}\b`)},
+	}
+}
+
+// RedactingLogger 是在打印之前对 tool 的参数和结果做脱敏的 LoggerCallback, 避免日志中泄露地址、手机号等个人信息.
+// 只影响日志, 传给 tool 和模型的内容不变.
+type RedactingLogger struct {
+	*LoggerCallback
+
+	rules []RedactionRule
+}
+
+// NewRedactingLogger 让 logger 在打印前脱敏. 没有传入 rules 时使用 DefaultRedactionRules.
+func NewRedactingLogger(logger *LoggerCallback, rules ...RedactionRule) *RedactingLogger {
+	if len(rules) == 0 {
+		rules = DefaultRedactionRules()
+	}
+	rl := &RedactingLogger{LoggerCallback: logger, rules: rules}
+	logger.redact = rl.Redact
+	return rl
+}
+
+// Redact 对一段 tool 参数或结果脱敏. 合法的 JSON 会按字段处理, 其他内容只应用 Pattern 规则.
+func (rl *RedactingLogger) Redact(s string) string {
+	var v any
+	if err := json.Unmarshal([]byte(s), &v); err != nil {
+		return rl.redactString(s)
+	}
+
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(rl.redactValue(v)); err != nil {
+		return rl.redactString(s)
+	}
+	return strings.TrimSuffix(buf.String(), "\n")
+}
+
+func (rl *RedactingLogger) redactValue(v any) any {
+	switch val := v.(type) {
+	case string:
+		return rl.redactString(val)
+	case []any:
+		for i, item := range val {
+			val[i] = rl.redactValue(item)
+		}
+		return val
+	case map[string]any:
+		for k, item := range val {
+			if name, ok := rl.fieldRule(k); ok {
+				val[k] = "[REDACTED:" + name + "]"
+				continue
+			}
+			val[k] = rl.redactValue(item)
+		}
+		return val
+	}
+	return v
+}
+
+func (rl *RedactingLogger) fieldRule(field string) (string, bool) {
+	for _, rule := range rl.rules {
+		if rule.Field != "" && rule.Field == field {
+			return rule.Name, true
+		}
+	}
+	return "", false
+}
+
+func (rl *RedactingLogger) redactString(s string) string {
+	for _, rule := range rl.rules {
+		if rule.Pattern != nil {
+			s = rule.Pattern.ReplaceAllString(s, "[REDACTED:"+rule.Name+"]")
+		}
+	}
+	return s
+}
@@ -0,0 +1,78 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/cloudwego/eino-examples/flow/agent/react/tools"
+	"github.com/cloudwego/eino/callbacks"
+	"github.com/cloudwego/eino/components"
+	"github.com/cloudwego/eino/components/tool"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRedactingLogger(t *testing.T) {
+	rl := NewRedactingLogger(&LoggerCallback{})
+
+	assert.Equal(t, `{"address":"[REDACTED:address]","restaurant_id":"1001"}`,
+		rl.Redact(`{"restaurant_id": "1001", "address": "朝阳区建国路 88 号"}`))
+	assert.Equal(t, `{"note":"call [REDACTED:phone] or mail [REDACTED:email]"}`,
+		rl.Redact(`{"note": "call 13812345678 or mail a.b@example.com"}`))
+	assert.Equal(t, "failed for [REDACTED:phone]", rl.Redact("failed for +86 13812345678"))
+	assert.Equal(t, `{"restaurant_id":"1001","topn":2}`, rl.Redact(`{"restaurant_id": "1001", "topn": 2}`))
+
+	custom := NewRedactingLogger(&LoggerCallback{}, RedactionRule{Name: "id", Pattern: regexp.MustCompile(`\d{4}`)})
+	assert.Equal(t, `{"restaurant_id":"[REDACTED:id]"}`, custom.Redact(`{"restaurant_id": "1001"}`))
+}
+
+func TestRedactingLoggerDeliveryAddress(t *testing.T) {
+	var buf bytes.Buffer
+	rl := NewRedactingLogger(&LoggerCallback{Out: &buf})
+
+	var handler callbacks.Handler = rl
+	info := &callbacks.RunInfo{Name: "query_delivery", Component: components.ComponentOfTool}
+	ctx := handler.OnStart(context.Background(), info, &tool.CallbackInput{ArgumentsInJSON: `{"restaurant_id":"1001","address":"朝阳区建国路 88 号"}`})
+	tools.GetToolState(ctx).Success = true
+	handler.OnEnd(ctx, info, &tool.CallbackOutput{Response: `{"restaurant_id":"1001","address":"朝阳区建国路 88 号","delivery_available":true}`})
+
+	assert.NotContains(t, buf.String(), "建国路")
+	assert.Contains(t, buf.String(), "[REDACTED:address]")
+}
+
+func TestRedactingLoggerContactInfo(t *testing.T) {
+	var buf bytes.Buffer
+	rl := NewRedactingLogger(&LoggerCallback{Out: &buf})
+
+	var handler callbacks.Handler = rl
+	info := &callbacks.RunInfo{Name: "query_contact_info", Component: components.ComponentOfTool}
+	ctx := handler.OnStart(context.Background(), info, &tool.CallbackInput{ArgumentsInJSON: `{"restaurant_id":"1001"}`})
+	tools.GetToolState(ctx).Success = true
+	res, err := tools.GetContactInfoTool().InvokableRun(ctx, `{"restaurant_id":"1001"}`)
+	assert.NoError(t, err)
+	handler.OnEnd(ctx, info, &tool.CallbackOutput{Response: res})
+
+	// 结果本身不变, 只有日志被脱敏
+	assert.Contains(t, res, "tel:+861065128888")
+	assert.NotContains(t, buf.String(), "65128888")
+	assert.NotContains(t, buf.String(), "yunbian.example.com")
+	assert.Contains(t, buf.String(), "[REDACTED:phone]")
+	assert.Contains(t, buf.String(), "[REDACTED:email]")
+}
@@ -0,0 +1,119 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/flow/agent/react"
+	"github.com/cloudwego/eino/schema"
+
+	"github.com/cloudwego/eino-examples/flow/agent/react/tools"
+)
+
+// repeatTemperature 是重复运行时使用的 temperature, 和 vote 模式一样需要大于 0, 才能看出回答的波动.
+const repeatTemperature = voteTemperature
+
+// ConsistencyReport 是同一个问题重复运行多次的结果. 和 vote 模式不同, 它不挑选回答, 只衡量回答之间有多一致.
+type ConsistencyReport struct {
+	Answers []string
+	// Picks 是从每个回答中识别出的推荐餐厅, 和 Answers 一一对应
+	Picks [][]string
+	// Score 是每两次运行推荐的餐厅集合的 Jaccard 相似度的平均值, 1 表示每次都推荐了同样的餐厅
+	Score float64
+}
+
+// String 打印一致性得分和每家餐厅在几次运行中被推荐.
+func (c *ConsistencyReport) String() string {
+	parts := make([]string, 0, len(c.Picks))
+	for _, v := range tallyVotes(c.Picks) {
+		parts = append(parts, fmt.Sprintf("%s=%d/%d", v.name, v.count, len(c.Picks)))
+	}
+	return fmt.Sprintf("[CONSISTENCY] score %.2f over %d runs: %s", c.Score, len(c.Answers), strings.Join(parts, ", "))
+}
+
+// RunRepeated 把同一个问题从头运行 n 次 (包括 tool 调用, temperature 为 repeatTemperature),
+// 用 extractRecommendations 识别每次推荐的餐厅, 计算它们之间的一致性. 每次运行都是独立的对话, 不读写 Memory.
+func (r *AgentRunner) RunRepeated(ctx context.Context, userMessage string, n int) (*ConsistencyReport, error) {
+	if n < 1 {
+		return nil, fmt.Errorf("repeat must be at least 1, got %d", n)
+	}
+
+	messages := []*schema.Message{schema.SystemMessage(r.systemPrompt), schema.UserMessage(userMessage)}
+	// 放在最后, 覆盖 AgentRunnerConfig.Temperature
+	opts := append(slices.Clone(r.opts), react.WithChatModelOptions(model.WithTemperature(repeatTemperature)))
+	known := tools.RestaurantNames()
+	report := &ConsistencyReport{}
+	for i := 0; i < n; i++ {
+		msg, err := r.agent.Generate(ctx, messages, opts...)
+		if err != nil {
+			return nil, fmt.Errorf("run %d failed: %w", i+1, err)
+		}
+		if isEmptyAnswer(msg) {
+			fmt.Printf("[REPEAT %d] no answer, skipped\n", i+1)
+			continue
+		}
+		names := extractRecommendations(msg.Content, known)
+		fmt.Printf("[REPEAT %d] recommends [%s]: %s\n", i+1, strings.Join(names, ", "), msg.Content)
+		report.Answers = append(report.Answers, msg.Content)
+		report.Picks = append(report.Picks, names)
+	}
+	if len(report.Answers) == 0 {
+		return nil, errEmptyAnswer
+	}
+	report.Score = consistencyScore(report.Picks)
+	return report, nil
+}
+
+// consistencyScore 是每两组推荐的 Jaccard 相似度的平均值. 两组都为空时算作一致, 只有一组时为 1.
+func consistencyScore(picks [][]string) float64 {
+	if len(picks) < 2 {
+		return 1
+	}
+	total, pairs := 0.0, 0
+	for i := range picks {
+		for j := i + 1; j < len(picks); j++ {
+			total += jaccard(picks[i], picks[j])
+			pairs++
+		}
+	}
+	return total / float64(pairs)
+}
+
+func jaccard(a, b []string) float64 {
+	set := map[string]int{}
+	for _, name := range a {
+		set[name] |= 1
+	}
+	for _, name := range b {
+		set[name] |= 2
+	}
+	if len(set) == 0 {
+		return 1
+	}
+	both := 0
+	for _, v := range set {
+		if v == 3 {
+			both++
+		}
+	}
+	return float64(both) / float64(len(set))
+}
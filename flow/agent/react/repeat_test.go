@@ -0,0 +1,62 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cloudwego/eino/schema"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConsistencyScore(t *testing.T) {
+	assert.Equal(t, 1.0, consistencyScore([][]string{{"云边小馆"}}))
+	assert.Equal(t, 1.0, consistencyScore([][]string{{"云边小馆", "花影食舍"}, {"花影食舍", "云边小馆"}}))
+	assert.Equal(t, 1.0, consistencyScore([][]string{{}, {}}))
+	assert.Equal(t, 0.0, consistencyScore([][]string{{"云边小馆"}, {"花影食舍"}}))
+	// 三对的相似度分别是 1/2, 1/2 和 0
+	assert.InDelta(t, 1.0/3, consistencyScore([][]string{{"云边小馆", "花影食舍"}, {"云边小馆"}, {"花影食舍"}}), 1e-9)
+}
+
+func TestAgentRunnerRunRepeated(t *testing.T) {
+	ctx := context.Background()
+
+	// 第一次运行完整地调用 tool, 之后两次直接回答, 其中一次换了一家餐厅
+	script := append(defaultMockScript(),
+		schema.AssistantMessage("推荐花影食舍和聚福轩食府.", nil),
+		schema.AssistantMessage("", nil),
+		schema.AssistantMessage("推荐聚福轩食府和云边小馆.", nil),
+	)
+	runner, err := NewAgentRunner(ctx, &AgentRunnerConfig{
+		ChatModel:  newScriptedModel(script...),
+		PromptVars: map[string]string{"City": "北京"},
+	})
+	assert.NoError(t, err)
+	defer runner.Close(ctx)
+
+	report, err := runner.RunRepeated(ctx, "我在北京，给我推荐一些辣的菜", 4)
+	assert.NoError(t, err)
+	assert.Len(t, report.Answers, 3)
+	assert.Equal(t, [][]string{{"聚福轩食府", "花影食舍"}, {"花影食舍", "聚福轩食府"}, {"聚福轩食府", "云边小馆"}}, report.Picks)
+	// 三对的相似度分别是 1, 1/3 和 1/3
+	assert.InDelta(t, 5.0/9, report.Score, 1e-9)
+	assert.Equal(t, "[CONSISTENCY] score 0.56 over 3 runs: 聚福轩食府=3/3, 花影食舍=2/3, 云边小馆=1/3", report.String())
+
+	_, err = runner.RunRepeated(ctx, "你好", 0)
+	assert.Error(t, err)
+}
@@ -0,0 +1,240 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/cloudwego/eino/callbacks"
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/components/tool"
+	"github.com/cloudwego/eino/compose"
+	"github.com/cloudwego/eino/flow/agent"
+	"github.com/cloudwego/eino/flow/agent/react"
+	"github.com/cloudwego/eino/schema"
+
+	"github.com/cloudwego/eino-examples/flow/agent/react/tools"
+)
+
+// errEmptyAnswer 表示重试之后模型仍然没有给出回答.
+var errEmptyAnswer = errors.New("the model returned no answer")
+
+// errNoFinalAnswer 表示流式输出结束时只有 tool call, 没有任何 content, ReAct 循环没有走到最终回答.
+var errNoFinalAnswer = errors.New("the stream ended with tool calls but no final answer")
+
+// AgentRunnerConfig 描述 AgentRunner 需要的全部依赖, 除 ChatModel 外都有默认值.
+type AgentRunnerConfig struct {
+	// ChatModel 是 agent 使用的模型, 必填.
+	ChatModel model.ToolCallingChatModel
+
+	// Tools 是注册给 agent 的 tool, 为空时使用 defaultTools().
+	Tools []tool.BaseTool
+
+	// MaxTools 大于 0 时只把前 MaxTools 个 tool 暴露给模型, 用于对比 tool 数量对模型选择 tool 的影响. 0 表示不限制.
+	MaxTools int
+
+	// PromptTemplate 是 system prompt 的 text/template, 为空时使用 defaultSystemPrompt.
+	// PromptVars 会渲染进模板, ToolNames 由 AgentRunner 根据 Tools 自动填充.
+	PromptTemplate string
+	PromptVars     map[string]string
+
+	// SummarizeThreshold 是 tool 结果的总字节数超过多少时交给模型摘要, 0 表示不摘要.
+	SummarizeThreshold int
+
+	// MaxSteps 限制 ReAct 循环的步数, 每轮 tool 调用占两步 (chat model + tools), 0 表示使用 react 的默认值.
+	MaxSteps int
+
+	// MaxToolCalls 限制每次 Run 或 Stream 中 tool 的总调用次数, 超出的调用返回 "tool budget exhausted" 而不执行,
+	// 需要 tool 包上 tools.ToolBudgetMiddleware (defaultTools 已经包上). 0 表示不限制.
+	MaxToolCalls int
+
+	// Temperature 不为空时作为每次调用 chat model 的 temperature. vote 和 repeat 模式仍然使用它们自己的 temperature.
+	Temperature *float32
+
+	// Logger 打印 tool 调用和流式回答, 为空时不打印.
+	Logger *LoggerCallback
+
+	// OTelExporter 是 span 的导出方式: stdout 或 none, 为空等同于 none.
+	OTelExporter string
+
+	// Handlers 是额外注册的 callback, 比如测试里记录 tool 调用顺序的 handler.
+	Handlers []callbacks.Handler
+
+	// Memory 保存多轮对话的历史, 每轮的用户消息、中间的 tool call 和最终回答都会追加进去. 为空时每次 Run 都是独立的对话.
+	Memory *ConversationMemory
+}
+
+// AgentRunner 封装了 agent、system prompt 和 callback 的创建与销毁, main 和测试都通过它来运行 agent.
+type AgentRunner struct {
+	agent        *react.Agent
+	chatModel    model.ToolCallingChatModel
+	tools        []tool.BaseTool
+	systemPrompt string
+	logger       *LoggerCallback
+	memory       *ConversationMemory
+	maxToolCalls int
+	opts         []agent.AgentOption
+	shutdown     func(context.Context) error
+}
+
+// NewAgentRunner 按 config 创建 agent. 使用完后需要调用 Close 来导出还未导出的 span.
+func NewAgentRunner(ctx context.Context, config *AgentRunnerConfig) (*AgentRunner, error) {
+	if config.ChatModel == nil {
+		return nil, errors.New("chat model is required")
+	}
+
+	agentTools := config.Tools
+	if len(agentTools) == 0 {
+		agentTools = defaultTools()
+	}
+	if config.MaxTools < 0 {
+		return nil, fmt.Errorf("max tools must be at least 1, got %d", config.MaxTools)
+	}
+	if config.MaxTools > 0 && config.MaxTools < len(agentTools) {
+		agentTools = agentTools[:config.MaxTools]
+	}
+
+	if config.MaxSteps < 0 {
+		return nil, fmt.Errorf("max steps must not be negative, got %d", config.MaxSteps)
+	}
+	if config.MaxToolCalls < 0 {
+		return nil, fmt.Errorf("max tool calls must not be negative, got %d", config.MaxToolCalls)
+	}
+	ragent, err := newAgent(ctx, config.ChatModel, agentTools, config.SummarizeThreshold, config.MaxSteps)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create agent: %w", err)
+	}
+
+	promptTemplate := config.PromptTemplate
+	if promptTemplate == "" {
+		promptTemplate = defaultSystemPrompt
+	}
+	names, err := toolNames(ctx, agentTools)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tool names: %w", err)
+	}
+	if config.MaxTools > 0 {
+		fmt.Printf("[TOOLS] %d active: %s\n", len(names), strings.Join(names, ", "))
+	}
+	vars := map[string]string{"ToolNames": strings.Join(names, ", ")}
+	for k, v := range config.PromptVars {
+		vars[k] = v
+	}
+	systemPrompt, err := renderPrompt(promptTemplate, vars)
+	if err != nil {
+		return nil, err
+	}
+
+	handlers := append([]callbacks.Handler{dedupHandler()}, config.Handlers...)
+	if config.Logger != nil {
+		handlers = append(handlers, config.Logger)
+	}
+	tracer, shutdown, err := setupTracing(config.OTelExporter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to setup tracing: %w", err)
+	}
+	if tracer != nil {
+		handlers = append(handlers, newOTelCallback(tracer))
+	}
+
+	opts := []agent.AgentOption{agent.WithComposeOptions(compose.WithCallbacks(handlers...))}
+	if config.Temperature != nil {
+		opts = append(opts, react.WithChatModelOptions(model.WithTemperature(*config.Temperature)))
+	}
+
+	return &AgentRunner{
+		agent:        ragent,
+		chatModel:    config.ChatModel,
+		tools:        agentTools,
+		systemPrompt: systemPrompt,
+		logger:       config.Logger,
+		memory:       config.Memory,
+		maxToolCalls: config.MaxToolCalls,
+		opts:         opts,
+		shutdown:     shutdown,
+	}, nil
+}
+
+// Run 以 generate 模式回答 userMessage.
+func (r *AgentRunner) Run(ctx context.Context, userMessage string) (string, error) {
+	return r.run(ctx, userMessage, func(ctx context.Context, messages []*schema.Message, opts []agent.AgentOption) (*schema.Message, error) {
+		return runGenerate(ctx, r.agent, messages, opts...)
+	})
+}
+
+// Stream 以 stream 模式回答 userMessage, 流式输出由 Logger 打印, 返回拼接后的完整回答.
+func (r *AgentRunner) Stream(ctx context.Context, userMessage string) (string, error) {
+	return r.run(ctx, userMessage, func(ctx context.Context, messages []*schema.Message, opts []agent.AgentOption) (*schema.Message, error) {
+		return runStream(ctx, r.agent, messages, r.logger, opts...)
+	})
+}
+
+// Close 导出还未导出的 span.
+func (r *AgentRunner) Close(ctx context.Context) error {
+	return r.shutdown(ctx)
+}
+
+func (r *AgentRunner) run(ctx context.Context, userMessage string,
+	answer func(ctx context.Context, messages []*schema.Message, opts []agent.AgentOption) (*schema.Message, error)) (string, error) {
+	user := schema.UserMessage(userMessage)
+	messages := []*schema.Message{schema.SystemMessage(r.systemPrompt)}
+	if r.memory != nil {
+		messages = append(messages, r.memory.Messages()...)
+	}
+	messages = append(messages, user)
+
+	// provider 偶尔会返回完全为空的响应（没有 content 也没有 tool call），此时重试一次，仍为空则明确提示用户
+	for attempt := 0; ; attempt++ {
+		counter := &toolCallCounter{}
+		recorder := &stepRecorder{}
+		opts := append([]agent.AgentOption{
+			agent.WithComposeOptions(compose.WithCallbacks(counter.handler(), recorder.handler())),
+		}, r.opts...)
+
+		// 重试是一次新的运行, tool 调用的预算重新计算
+		msg, err := answer(tools.WithToolBudget(ctx, r.maxToolCalls), messages, opts)
+		if err != nil {
+			return "", err
+		}
+		if !isEmptyAnswer(msg) {
+			if isOutOfScope(userMessage, counter.n.Load()) {
+				fmt.Printf("[SCOPE] declined out of scope: %q\n", userMessage)
+			}
+			if r.memory != nil {
+				turn := append([]*schema.Message{user}, recorder.wait()...)
+				turn = append(turn, schema.AssistantMessage(msg.Content, nil))
+				if err := r.memory.Append(turn...); err != nil {
+					return "", fmt.Errorf("failed to save session: %w", err)
+				}
+				// 摘要失败不影响这一轮的回答, 历史保持原样, 下一轮再试
+				if rolled, err := r.memory.Rollover(ctx); err != nil {
+					fmt.Printf("[ERROR] %v, keeping the history as is\n", err)
+				} else if rolled {
+					fmt.Printf("[MEMORY] summarized older turns, %d messages left\n", len(r.memory.Messages()))
+				}
+			}
+			return msg.Content, nil
+		}
+		if attempt >= emptyAnswerRetries {
+			return "", errEmptyAnswer
+		}
+		fmt.Printf("[WARN] the model returned an empty response, retrying...\n")
+	}
+}
@@ -0,0 +1,319 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/cloudwego/eino/callbacks"
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/components/tool"
+	"github.com/cloudwego/eino/schema"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/cloudwego/eino-examples/flow/agent/react/tools"
+)
+
+func TestAgentRunner(t *testing.T) {
+	ctx := context.Background()
+	want := []string{"query_restaurants", "query_dishes", "query_dishes"}
+
+	for name, answer := range map[string]func(*AgentRunner, context.Context, string) (string, error){
+		"run":    (*AgentRunner).Run,
+		"stream": (*AgentRunner).Stream,
+	} {
+		t.Run(name, func(t *testing.T) {
+			recorder := &toolRecorder{}
+			runner, err := NewAgentRunner(ctx, &AgentRunnerConfig{
+				ChatModel:  newScriptedModel(defaultMockScript()...),
+				PromptVars: map[string]string{"City": "北京"},
+				Handlers:   []callbacks.Handler{recorder.handler()},
+			})
+			assert.NoError(t, err)
+			defer runner.Close(ctx)
+
+			content, err := answer(runner, ctx, "我在北京，给我推荐一些辣的菜")
+			assert.NoError(t, err)
+			assert.NotEmpty(t, content)
+			assert.Equal(t, want, recorder.calls())
+		})
+	}
+}
+
+func TestAgentRunnerEmptyAnswer(t *testing.T) {
+	ctx := context.Background()
+
+	runner, err := NewAgentRunner(ctx, &AgentRunnerConfig{
+		ChatModel:  newScriptedModel(schema.AssistantMessage("", nil), schema.AssistantMessage("  ", nil)),
+		PromptVars: map[string]string{"City": "北京"},
+	})
+	assert.NoError(t, err)
+	defer runner.Close(ctx)
+
+	_, err = runner.Run(ctx, "你好")
+	assert.ErrorIs(t, err, errEmptyAnswer)
+
+	_, err = NewAgentRunner(ctx, &AgentRunnerConfig{})
+	assert.Error(t, err)
+}
+
+func TestAgentRunnerMaxTools(t *testing.T) {
+	ctx := context.Background()
+
+	runner, err := NewAgentRunner(ctx, &AgentRunnerConfig{
+		ChatModel:  newScriptedModel(defaultMockScript()...),
+		MaxTools:   1,
+		PromptVars: map[string]string{"City": "北京"},
+	})
+	assert.NoError(t, err)
+	defer runner.Close(ctx)
+	assert.Contains(t, runner.systemPrompt, "你可以使用的工具: query_restaurants\n")
+
+	_, err = NewAgentRunner(ctx, &AgentRunnerConfig{ChatModel: newScriptedModel(), MaxTools: -1})
+	assert.ErrorContains(t, err, "at least 1")
+}
+
+func TestAgentRunnerMaxSteps(t *testing.T) {
+	ctx := context.Background()
+
+	// 剧本中有多轮 tool 调用, 2 步只够一次 chat model 和一次 tools
+	runner, err := NewAgentRunner(ctx, &AgentRunnerConfig{
+		ChatModel:  newScriptedModel(defaultMockScript()...),
+		MaxSteps:   2,
+		PromptVars: map[string]string{"City": "北京"},
+	})
+	assert.NoError(t, err)
+	defer runner.Close(ctx)
+	_, err = runner.Run(ctx, "我在北京，给我推荐一些辣的菜")
+	assert.ErrorContains(t, err, "max step")
+
+	// LoggerCallback 记录 chat model 的调用次数, 区分被步数上限终止和自然结束
+	logs := func(maxSteps int) map[string]any {
+		var buf bytes.Buffer
+		logger := &LoggerCallback{Logger: slog.New(slog.NewJSONHandler(&buf, nil))}
+		runner, err := NewAgentRunner(ctx, &AgentRunnerConfig{
+			ChatModel:  newScriptedModel(defaultMockScript()...),
+			MaxSteps:   maxSteps,
+			PromptVars: map[string]string{"City": "北京"},
+			Logger:     logger,
+		})
+		assert.NoError(t, err)
+		defer runner.Close(ctx)
+		_, err = runner.Run(ctx, "我在北京，给我推荐一些辣的菜")
+		buf.Reset()
+		logger.LogRunEnd(err, maxSteps)
+		var record map[string]any
+		assert.NoError(t, json.Unmarshal(buf.Bytes(), &record))
+		return record
+	}
+	record := logs(2)
+	assert.Equal(t, "WARN", record["level"])
+	assert.Equal(t, "agent terminated by the step limit", record["msg"])
+	assert.Equal(t, "step_limit", record["stop_reason"])
+	// 2 步是一次 chat model 和一次 tools
+	assert.EqualValues(t, 1, record["model_calls"])
+	assert.EqualValues(t, 2, record["max_steps"])
+
+	record = logs(0)
+	assert.Equal(t, "agent finished", record["msg"])
+	assert.Equal(t, "final_answer", record["stop_reason"])
+	assert.EqualValues(t, len(defaultMockScript()), record["model_calls"])
+	assert.NotContains(t, record, "max_steps")
+
+	_, err = NewAgentRunner(ctx, &AgentRunnerConfig{ChatModel: newScriptedModel(), MaxSteps: -1})
+	assert.ErrorContains(t, err, "max steps")
+}
+
+// BenchmarkAgentRunCache 对比只读 tool 加不加 ResultCache 时一次 agent 运行的耗时.
+// 只使用没有随机失败的 query_dishes, 后端耗时固定为 2ms, 剧本中重复查询同一家餐厅, 结果可以复现.
+// 缓存在多次运行之间共享, 命中率作为自定义指标 hit-rate 输出.
+func BenchmarkAgentRunCache(b *testing.B) {
+	ctx := context.Background()
+	tools.SetBackendLatency(2 * time.Millisecond)
+	b.Cleanup(func() { tools.SetBackendLatency(0) })
+
+	script := func() []*schema.Message {
+		return []*schema.Message{
+			toolCallMessage("call_1", "query_dishes", `{"restaurant_id":"1001","topn":5}`),
+			toolCallMessage("call_2", "query_dishes", `{"restaurant_id":"1002","topn":5}`),
+			toolCallMessage("call_3", "query_dishes", `{"restaurant_id":"1001","topn":5}`),
+			schema.AssistantMessage("推荐云边小馆的韩式辣白菜和聚福轩食府的火辣辣的吻.", nil),
+		}
+	}
+
+	for _, withCache := range []bool{false, true} {
+		name := "uncached"
+		if withCache {
+			name = "cached"
+		}
+		b.Run(name, func(b *testing.B) {
+			cache, err := tools.NewResultCache("", time.Hour)
+			if err != nil {
+				b.Fatal(err)
+			}
+			dishTool := tools.GetDishTool()
+			if withCache {
+				dishTool = tools.NewCachedTool(dishTool, cache)
+			}
+
+			for i := 0; i < b.N; i++ {
+				b.StopTimer()
+				runner, err := NewAgentRunner(ctx, &AgentRunnerConfig{
+					ChatModel:  newScriptedModel(script()...),
+					Tools:      []tool.BaseTool{dishTool},
+					PromptVars: map[string]string{"City": "北京"},
+				})
+				if err != nil {
+					b.Fatal(err)
+				}
+				b.StartTimer()
+
+				if _, err := runner.Run(ctx, "我在北京，给我推荐一些辣的菜"); err != nil {
+					b.Fatal(err)
+				}
+
+				b.StopTimer()
+				runner.Close(ctx)
+				b.StartTimer()
+			}
+			b.ReportMetric(cache.Stats().HitRate(), "hit-rate")
+		})
+	}
+}
+
+func TestAgentRunnerDedupToolCalls(t *testing.T) {
+	ctx := context.Background()
+
+	// 模型在一条消息里两次调用同一个 tool, 参数相同
+	dup := toolCallMessage("call_1", "query_dishes", `{"restaurant_id":"1001","topn":5}`)
+	dup.ToolCalls = append(dup.ToolCalls, schema.ToolCall{
+		ID:       "call_2",
+		Type:     "function",
+		Function: schema.FunctionCall{Name: "query_dishes", Arguments: `{"topn":5,"restaurant_id":"1001"}`},
+	})
+	backend := &countingDishTool{InvokableTool: tools.GetDishTool()}
+	recorder := &stepRecorder{}
+	runner, err := NewAgentRunner(ctx, &AgentRunnerConfig{
+		ChatModel:  newScriptedModel(dup, schema.AssistantMessage("推荐云边小馆的韩式辣白菜.", nil)),
+		Tools:      tools.ApplyMiddleware([]tool.BaseTool{backend}, tools.DedupMiddleware()),
+		PromptVars: map[string]string{"City": "北京"},
+		Handlers:   []callbacks.Handler{recorder.handler()},
+	})
+	assert.NoError(t, err)
+	defer runner.Close(ctx)
+
+	_, err = runner.Run(ctx, "云边小馆有什么菜")
+	assert.NoError(t, err)
+	assert.Equal(t, int32(1), backend.calls.Load())
+
+	// 两个 call id 都拿到了同一个结果
+	var results []*schema.Message
+	for _, msg := range recorder.wait() {
+		if msg.Role == schema.Tool {
+			results = append(results, msg)
+		}
+	}
+	if assert.Len(t, results, 2) {
+		assert.ElementsMatch(t, []string{"call_1", "call_2"}, []string{results[0].ToolCallID, results[1].ToolCallID})
+		assert.Equal(t, results[0].Content, results[1].Content)
+	}
+}
+
+// countingDishTool 统计真正执行的次数.
+type countingDishTool struct {
+	tool.InvokableTool
+	calls atomic.Int32
+}
+
+func (c *countingDishTool) InvokableRun(ctx context.Context, argumentsInJSON string, opts ...tool.Option) (string, error) {
+	c.calls.Add(1)
+	return c.InvokableTool.InvokableRun(ctx, argumentsInJSON, opts...)
+}
+
+// toolHappyModel 每次都调用两个 tool, 直到看到 "tool budget exhausted" 才给出回答.
+type toolHappyModel struct {
+	calls atomic.Int32
+}
+
+func (m *toolHappyModel) Generate(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.Message, error) {
+	n := m.calls.Add(1)
+	// 同一轮的调用并发执行, 被拒绝的不一定是最后一条 tool 消息
+	for i := len(input) - 1; i >= 0 && input[i].Role == schema.Tool; i-- {
+		if strings.Contains(input[i].Content, "tool budget exhausted") {
+			return schema.AssistantMessage("推荐云边小馆的红烧肉.", nil), nil
+		}
+	}
+	msg := toolCallMessage(fmt.Sprintf("call_%d_a", n), "query_dishes", `{"restaurant_id":"1001"}`)
+	msg.ToolCalls = append(msg.ToolCalls, schema.ToolCall{
+		ID:       fmt.Sprintf("call_%d_b", n),
+		Type:     "function",
+		Function: schema.FunctionCall{Name: "query_dishes", Arguments: fmt.Sprintf(`{"restaurant_id":"1001","topn":%d}`, n)},
+	})
+	return msg, nil
+}
+
+func (m *toolHappyModel) Stream(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.StreamReader[*schema.Message], error) {
+	msg, err := m.Generate(ctx, input, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return schema.StreamReaderFromArray([]*schema.Message{msg}), nil
+}
+
+func (m *toolHappyModel) WithTools(tools []*schema.ToolInfo) (model.ToolCallingChatModel, error) {
+	return m, nil
+}
+
+func TestAgentRunnerMaxToolCalls(t *testing.T) {
+	ctx := context.Background()
+
+	backend := &countingDishTool{InvokableTool: tools.GetDishTool()}
+	agentTools := tools.ApplyMiddleware([]tool.BaseTool{backend}, tools.DedupMiddleware(), tools.ToolBudgetMiddleware())
+	happy := &toolHappyModel{}
+	runner, err := NewAgentRunner(ctx, &AgentRunnerConfig{
+		ChatModel:    happy,
+		Tools:        agentTools,
+		MaxToolCalls: 3,
+		PromptVars:   map[string]string{"City": "北京"},
+	})
+	assert.NoError(t, err)
+	defer runner.Close(ctx)
+
+	// 每轮两次调用: 第一轮 2 次, 第二轮只剩 1 次, 另一次被拒绝, 模型随后给出回答
+	answer, err := runner.Run(ctx, "云边小馆有什么菜")
+	assert.NoError(t, err)
+	assert.Equal(t, "推荐云边小馆的红烧肉.", answer)
+	assert.Equal(t, int32(3), backend.calls.Load())
+	assert.Equal(t, int32(3), happy.calls.Load())
+
+	// 每次运行的预算是独立的
+	backend.calls.Store(0)
+	_, err = runner.Run(ctx, "云边小馆有什么菜")
+	assert.NoError(t, err)
+	assert.Equal(t, int32(3), backend.calls.Load())
+
+	_, err = NewAgentRunner(ctx, &AgentRunnerConfig{ChatModel: newScriptedModel(), MaxToolCalls: -1})
+	assert.ErrorContains(t, err, "max tool calls")
+}
@@ -0,0 +1,61 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cloudwego/eino/components/tool"
+	"github.com/cloudwego/eino/compose"
+	"github.com/cloudwego/eino/flow/agent"
+	"github.com/cloudwego/eino/schema"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/cloudwego/eino-examples/flow/agent/react/tools"
+)
+
+func TestLoggerCallbackStats(t *testing.T) {
+	ctx := context.Background()
+
+	calls := toolCallMessage("call_1", "sleepy", `{"sleep_ms": 5}`)
+	calls.ToolCalls = append(calls.ToolCalls, toolCallMessage("call_2", "sleepy", `{"fail": true}`).ToolCalls...)
+	ragent, err := newAgent(ctx, newScriptedModel(calls, schema.AssistantMessage("好的", nil)), []tool.BaseTool{&sleepyTool{}}, 0, 0)
+	assert.NoError(t, err)
+
+	logger := &LoggerCallback{Out: &strings.Builder{}, Stats: &tools.RunStats{}}
+	_, err = runStream(ctx, ragent, []*schema.Message{schema.UserMessage("hi")}, logger,
+		agent.WithComposeOptions(compose.WithCallbacks(logger)))
+	assert.NoError(t, err)
+
+	// 失败的调用以 ToolExecutionState.Success 为准, safeTool 转成 content 的错误也不算成功
+	st := logger.Stats.Get("sleepy")
+	assert.Equal(t, 2, st.Calls)
+	assert.Equal(t, 1, st.Successes)
+	assert.GreaterOrEqual(t, st.Latency, 5*time.Millisecond)
+	assert.Contains(t, logger.Stats.Report(), "sleepy: 2 calls, 1 ok, avg ")
+
+	// 没有 Stats 时不统计
+	ragent, err = newAgent(ctx, newScriptedModel(calls, schema.AssistantMessage("好的", nil)), []tool.BaseTool{&sleepyTool{}}, 0, 0)
+	assert.NoError(t, err)
+	logger = &LoggerCallback{Out: &strings.Builder{}}
+	_, err = runStream(ctx, ragent, []*schema.Message{schema.UserMessage("hi")}, logger,
+		agent.WithComposeOptions(compose.WithCallbacks(logger)))
+	assert.NoError(t, err)
+}
@@ -0,0 +1,283 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cloudwego/eino/callbacks"
+	"github.com/cloudwego/eino/components"
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/schema"
+
+	"github.com/cloudwego/eino-examples/flow/agent/react/tools"
+)
+
+// RunSummary 是一次 agent 运行的汇总: 模型和 tool 各调用了多少次, 失败了多少次, 花了多长时间, 用了多少 token.
+type RunSummary struct {
+	ModelCalls    int
+	ModelFailures int
+	// Tools 按 tool 名统计调用情况
+	Tools map[string]ToolCallStats
+	// Latency 是从第一次调用开始到最后一次调用结束的时间
+	Latency time.Duration
+	// Usage 是所有模型调用的 token 用量之和, 模型没有返回用量时 UsageReported 为 false
+	Usage         schema.TokenUsage
+	UsageReported bool
+}
+
+type ToolCallStats struct {
+	Calls    int
+	Failures int
+	// Latency 是这个 tool 所有调用的耗时之和
+	Latency time.Duration
+}
+
+// AvgLatency 是每次调用的平均耗时, 没有调用时为 0.
+func (st ToolCallStats) AvgLatency() time.Duration {
+	if st.Calls == 0 {
+		return 0
+	}
+	return st.Latency / time.Duration(st.Calls)
+}
+
+// ToolCalls 返回所有 tool 的调用次数和失败次数之和.
+func (s RunSummary) ToolCalls() (calls, failures int) {
+	for _, st := range s.Tools {
+		calls += st.Calls
+		failures += st.Failures
+	}
+	return calls, failures
+}
+
+// String 把汇总格式化成几行 [SUMMARY] 开头的文本, tool 按名字排序.
+func (s RunSummary) String() string {
+	calls, failures := s.ToolCalls()
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "[SUMMARY] %d chat model calls (%d failed), %d tool calls (%d failed), total %v\n",
+		s.ModelCalls, s.ModelFailures, calls, failures, s.Latency.Round(time.Millisecond))
+
+	names := make([]string, 0, len(s.Tools))
+	for name := range s.Tools {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		st := s.Tools[name]
+		fmt.Fprintf(&sb, "[SUMMARY]   %s: %d calls, %d succeeded, %d failed, %v total, avg %v\n",
+			name, st.Calls, st.Calls-st.Failures, st.Failures, st.Latency.Round(time.Millisecond), st.AvgLatency().Round(time.Millisecond))
+	}
+
+	if s.UsageReported {
+		fmt.Fprintf(&sb, "[SUMMARY] tokens: prompt %d, completion %d, total %d\n",
+			s.Usage.PromptTokens, s.Usage.CompletionTokens, s.Usage.TotalTokens)
+	} else {
+		sb.WriteString("[SUMMARY] tokens: not reported by the chat model\n")
+	}
+	return sb.String()
+}
+
+// RunSummaryCallback 通过 callback 累计 RunSummary. tool 是否成功以 ToolExecutionState.Success 为准,
+// 所以 safeTool 转成 content 的错误也算失败; 返回 Go error 的调用同样算失败.
+// 流式输出在后台读完后才计入, 读取汇总之前需要调用 Summary 等待.
+type RunSummaryCallback struct {
+	// Out 是输出, 为空时输出到 os.Stdout.
+	Out io.Writer
+
+	mu      sync.Mutex
+	summary RunSummary
+	first   time.Time
+	last    time.Time
+
+	wg sync.WaitGroup // 跟踪 OnEndWithStreamOutput 中启动的 goroutine
+}
+
+type runSummaryStartKey struct{}
+
+func (c *RunSummaryCallback) OnStart(ctx context.Context, info *callbacks.RunInfo, input callbacks.CallbackInput) context.Context {
+	if info.Component != components.ComponentOfChatModel && info.Component != components.ComponentOfTool {
+		return ctx
+	}
+
+	now := time.Now()
+	c.mu.Lock()
+	if c.first.IsZero() {
+		c.first = now
+	}
+	if info.Component == components.ComponentOfChatModel {
+		c.summary.ModelCalls++
+	}
+	c.mu.Unlock()
+
+	if info.Component == components.ComponentOfTool && tools.GetToolState(ctx) == nil {
+		// 没有 LoggerCallback 时自己放一个, 让 tool 能报告是否成功
+		ctx = tools.SetToolState(ctx, &tools.ToolExecutionState{})
+	}
+	return context.WithValue(ctx, runSummaryStartKey{}, now)
+}
+
+func (c *RunSummaryCallback) OnEnd(ctx context.Context, info *callbacks.RunInfo, output callbacks.CallbackOutput) context.Context {
+	switch info.Component {
+	case components.ComponentOfChatModel:
+		c.finishModel(false, usageOf(output))
+	case components.ComponentOfTool:
+		state := tools.GetToolState(ctx)
+		c.finishTool(ctx, info.Name, state != nil && !state.Success)
+	}
+	return ctx
+}
+
+func (c *RunSummaryCallback) OnError(ctx context.Context, info *callbacks.RunInfo, err error) context.Context {
+	switch info.Component {
+	case components.ComponentOfChatModel:
+		c.finishModel(true, nil)
+	case components.ComponentOfTool:
+		c.finishTool(ctx, info.Name, true)
+	}
+	return ctx
+}
+
+func (c *RunSummaryCallback) OnStartWithStreamInput(ctx context.Context, info *callbacks.RunInfo,
+	input *schema.StreamReader[callbacks.CallbackInput]) context.Context {
+	input.Close()
+	return ctx
+}
+
+func (c *RunSummaryCallback) OnEndWithStreamOutput(ctx context.Context, info *callbacks.RunInfo,
+	output *schema.StreamReader[callbacks.CallbackOutput]) context.Context {
+	if info.Component != components.ComponentOfChatModel && info.Component != components.ComponentOfTool {
+		output.Close()
+		return ctx
+	}
+
+	c.wg.Add(1)
+	go func() {
+		defer c.wg.Done()
+		defer output.Close()
+
+		// 用量一般只在最后一帧, 取最后一个非空的
+		var usage *schema.TokenUsage
+		for {
+			frame, err := output.Recv()
+			if err != nil {
+				failed := !errors.Is(err, io.EOF)
+				if info.Component == components.ComponentOfChatModel {
+					c.finishModel(failed, usage)
+				} else {
+					state := tools.GetToolState(ctx)
+					c.finishTool(ctx, info.Name, failed || (state != nil && !state.Success))
+				}
+				return
+			}
+			if info.Component != components.ComponentOfChatModel {
+				continue
+			}
+			if u := usageOf(frame); u != nil {
+				usage = u
+			}
+		}
+	}()
+	return ctx
+}
+
+// usageOf 取出模型输出中的 token 用量. 实现了 callback 的模型放在 TokenUsage 中,
+// 没有实现的模型由 eino 把输出的 Message 包装成 CallbackOutput, 用量在 Message.ResponseMeta 中.
+func usageOf(output callbacks.CallbackOutput) *schema.TokenUsage {
+	mco := model.ConvCallbackOutput(output)
+	if mco == nil {
+		return nil
+	}
+	if u := mco.TokenUsage; u != nil {
+		return &schema.TokenUsage{PromptTokens: u.PromptTokens, CompletionTokens: u.CompletionTokens, TotalTokens: u.TotalTokens}
+	}
+	if mco.Message != nil && mco.Message.ResponseMeta != nil {
+		return mco.Message.ResponseMeta.Usage
+	}
+	return nil
+}
+
+func (c *RunSummaryCallback) finishModel(failed bool, usage *schema.TokenUsage) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.last = time.Now()
+	if failed {
+		c.summary.ModelFailures++
+	}
+	if usage != nil {
+		c.summary.UsageReported = true
+		c.summary.Usage.PromptTokens += usage.PromptTokens
+		c.summary.Usage.CompletionTokens += usage.CompletionTokens
+		c.summary.Usage.TotalTokens += usage.TotalTokens
+	}
+}
+
+func (c *RunSummaryCallback) finishTool(ctx context.Context, name string, failed bool) {
+	var elapsed time.Duration
+	if start, ok := ctx.Value(runSummaryStartKey{}).(time.Time); ok {
+		elapsed = time.Since(start)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.last = time.Now()
+	if c.summary.Tools == nil {
+		c.summary.Tools = make(map[string]ToolCallStats)
+	}
+	st := c.summary.Tools[name]
+	st.Calls++
+	st.Latency += elapsed
+	if failed {
+		st.Failures++
+	}
+	c.summary.Tools[name] = st
+}
+
+// Summary 等待所有流读完, 返回汇总的一份拷贝.
+func (c *RunSummaryCallback) Summary() RunSummary {
+	c.wg.Wait()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	s := c.summary
+	s.Tools = make(map[string]ToolCallStats, len(c.summary.Tools))
+	for name, st := range c.summary.Tools {
+		s.Tools[name] = st
+	}
+	if !c.first.IsZero() && c.last.After(c.first) {
+		s.Latency = c.last.Sub(c.first)
+	}
+	return s
+}
+
+// Print 打印汇总.
+func (c *RunSummaryCallback) Print() {
+	out := c.Out
+	if out == nil {
+		out = os.Stdout
+	}
+	_, _ = fmt.Fprint(out, c.Summary())
+}
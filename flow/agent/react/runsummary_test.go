@@ -0,0 +1,89 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cloudwego/eino/components/tool"
+	"github.com/cloudwego/eino/compose"
+	"github.com/cloudwego/eino/flow/agent"
+	"github.com/cloudwego/eino/schema"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunSummaryCallback(t *testing.T) {
+	ctx := context.Background()
+
+	for _, stream := range []bool{false, true} {
+		calls := toolCallMessage("call_1", "sleepy", `{"sleep_ms": 5}`)
+		calls.ToolCalls = append(calls.ToolCalls, toolCallMessage("call_2", "sleepy", `{"fail": true}`).ToolCalls...)
+		calls.ResponseMeta = &schema.ResponseMeta{Usage: &schema.TokenUsage{PromptTokens: 100, CompletionTokens: 20, TotalTokens: 120}}
+		answer := schema.AssistantMessage("这是一个足够长的回答, 会被切成好几帧输出", nil)
+		answer.ResponseMeta = &schema.ResponseMeta{Usage: &schema.TokenUsage{PromptTokens: 150, CompletionTokens: 30, TotalTokens: 180}}
+
+		ragent, err := newAgent(ctx, newScriptedModel(calls, answer), []tool.BaseTool{&sleepyTool{}}, 0, 0)
+		assert.NoError(t, err)
+
+		out := &strings.Builder{}
+		c := &RunSummaryCallback{Out: out}
+		opt := agent.WithComposeOptions(compose.WithCallbacks(c))
+		messages := []*schema.Message{schema.UserMessage("hi")}
+		if stream {
+			_, err = runStream(ctx, ragent, messages, &LoggerCallback{Out: &strings.Builder{}}, opt)
+		} else {
+			_, err = ragent.Generate(ctx, messages, opt)
+		}
+		assert.NoError(t, err)
+
+		s := c.Summary()
+		assert.Equal(t, 2, s.ModelCalls, "stream=%v", stream)
+		assert.Zero(t, s.ModelFailures)
+		assert.Equal(t, ToolCallStats{Calls: 2, Failures: 1, Latency: s.Tools["sleepy"].Latency}, s.Tools["sleepy"])
+		assert.GreaterOrEqual(t, s.Tools["sleepy"].Latency, 5*time.Millisecond)
+		assert.True(t, s.UsageReported, "stream=%v", stream)
+		assert.Equal(t, schema.TokenUsage{PromptTokens: 250, CompletionTokens: 50, TotalTokens: 300}, s.Usage)
+		assert.Positive(t, s.Latency)
+
+		c.Print()
+		assert.Contains(t, out.String(), "2 chat model calls (0 failed), 2 tool calls (1 failed)")
+		assert.Contains(t, out.String(), "sleepy: 2 calls, 1 succeeded, 1 failed")
+		assert.Contains(t, out.String(), "tokens: prompt 250, completion 50, total 300")
+	}
+}
+
+func TestRunSummaryString(t *testing.T) {
+	s := RunSummary{
+		ModelCalls: 1,
+		Tools: map[string]ToolCallStats{
+			"query_dishes":      {Calls: 1, Latency: time.Millisecond},
+			"query_restaurants": {Calls: 2, Failures: 2, Latency: 284 * time.Millisecond},
+		},
+		Latency: 1500 * time.Millisecond,
+	}
+	calls, failures := s.ToolCalls()
+	assert.Equal(t, 3, calls)
+	assert.Equal(t, 2, failures)
+	assert.Zero(t, ToolCallStats{}.AvgLatency())
+	assert.Equal(t, "[SUMMARY] 1 chat model calls (0 failed), 3 tool calls (2 failed), total 1.5s\n"+
+		"[SUMMARY]   query_dishes: 1 calls, 1 succeeded, 0 failed, 1ms total, avg 1ms\n"+
+		"[SUMMARY]   query_restaurants: 2 calls, 0 succeeded, 2 failed, 284ms total, avg 142ms\n"+
+		"[SUMMARY] tokens: not reported by the chat model\n", s.String())
+}
@@ -0,0 +1,78 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"context"
+	"strings"
+	"sync/atomic"
+	"unicode"
+
+	"github.com/cloudwego/eino/callbacks"
+	"github.com/cloudwego/eino/components"
+)
+
+// scopeKeywords 是判断一条消息和餐饮相关的关键词. 只要命中一个就认为在范围内.
+// 中文关键词按子串匹配; 英文关键词按单词前缀匹配, 避免 "weather" 命中 "eat".
+var scopeKeywords = []string{
+	"餐厅", "饭店", "馆", "菜", "吃", "饭", "餐", "外卖", "配送", "口味", "辣", "甜", "酸", "咸", "味",
+	"推荐", "好吃", "厨", "过敏", "忌口", "预订", "订位", "约会", "聚餐", "小费", "账单", "美食", "饿",
+	"restaurant", "food", "dish", "menu", "eat", "dinner", "lunch", "breakfast", "meal", "cuisine",
+	"spicy", "delivery", "chef", "allergen", "hungry",
+}
+
+// isOutOfScope 是一个刻意保持简单的启发式: agent 没有调用任何 tool, 并且用户消息里没有任何餐饮相关的关键词,
+// 就认为这是一个超出范围的问题, 模型 (按 system prompt 的要求) 拒绝了回答.
+// 只要调用过 tool, 就说明模型认为问题在范围内, 即使消息里没有关键词也不算.
+func isOutOfScope(userMessage string, toolCalls int64) bool {
+	if toolCalls > 0 {
+		return false
+	}
+	msg := strings.ToLower(userMessage)
+	words := strings.FieldsFunc(msg, func(r rune) bool {
+		return !unicode.IsLetter(r) || r > unicode.MaxASCII
+	})
+	for _, kw := range scopeKeywords {
+		if kw[0] > unicode.MaxASCII {
+			if strings.Contains(msg, kw) {
+				return false
+			}
+			continue
+		}
+		for _, w := range words {
+			if strings.HasPrefix(w, kw) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// toolCallCounter 统计一次运行中 tool 被调用的次数, 并发调用也是安全的.
+type toolCallCounter struct {
+	n atomic.Int64
+}
+
+func (c *toolCallCounter) handler() callbacks.Handler {
+	return callbacks.NewHandlerBuilder().OnStartFn(
+		func(ctx context.Context, info *callbacks.RunInfo, input callbacks.CallbackInput) context.Context {
+			if info.Component == components.ComponentOfTool {
+				c.n.Add(1)
+			}
+			return ctx
+		}).Build()
+}
@@ -0,0 +1,47 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cloudwego/eino/compose"
+	"github.com/cloudwego/eino/flow/agent"
+	"github.com/cloudwego/eino/schema"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsOutOfScope(t *testing.T) {
+	assert.True(t, isOutOfScope("帮我写一个快速排序", 0))
+	assert.True(t, isOutOfScope("What's the weather in Beijing?", 0))
+	assert.False(t, isOutOfScope("我在北京，给我推荐一些辣的菜", 0))
+	assert.False(t, isOutOfScope("Any good Restaurants nearby?", 0))
+	assert.False(t, isOutOfScope("帮我写一个快速排序", 1))
+}
+
+func TestToolCallCounter(t *testing.T) {
+	ctx := context.Background()
+	ragent, err := newAgent(ctx, newScriptedModel(defaultMockScript()...), defaultTools(), 0, 0)
+	assert.NoError(t, err)
+
+	counter := &toolCallCounter{}
+	_, err = ragent.Generate(ctx, []*schema.Message{schema.UserMessage("我在北京，给我推荐一些辣的菜")},
+		agent.WithComposeOptions(compose.WithCallbacks(counter.handler())))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), counter.n.Load())
+}
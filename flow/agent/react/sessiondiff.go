@@ -0,0 +1,167 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/cloudwego/eino/schema"
+)
+
+// sessionTurn 是 session 文件中的一轮对话: 用户的消息, 模型按顺序发起的 tool call, 以及最终回答.
+type sessionTurn struct {
+	User      string
+	ToolCalls []string // "<tool 名> <参数>", 参数去掉了空白
+	Answer    string
+}
+
+// splitTurns 按用户消息把 session 的历史切成一轮一轮, 第一条用户消息之前的消息忽略.
+func splitTurns(messages []*schema.Message) []sessionTurn {
+	var turns []sessionTurn
+	for _, msg := range messages {
+		if msg.Role == schema.User {
+			turns = append(turns, sessionTurn{User: msg.Content})
+			continue
+		}
+		if len(turns) == 0 || msg.Role != schema.Assistant {
+			continue
+		}
+		turn := &turns[len(turns)-1]
+		for _, tc := range msg.ToolCalls {
+			turn.ToolCalls = append(turn.ToolCalls, tc.Function.Name+" "+compactJSON(json.RawMessage(tc.Function.Arguments)))
+		}
+		if len(msg.ToolCalls) == 0 {
+			turn.Answer = msg.Content
+		}
+	}
+	return turns
+}
+
+// turnDiff 是两个 session 中同一轮的对比. 某个 session 的轮数更少时, 缺少的一边为 nil.
+type turnDiff struct {
+	Index int
+	A, B  *sessionTurn
+	// DivergedAt 是 tool call 序列第一个不同的位置 (从 0 开始), -1 表示完全相同
+	DivergedAt int
+}
+
+func (d turnDiff) same() bool {
+	return d.A != nil && d.B != nil && d.A.User == d.B.User && d.DivergedAt < 0 && d.A.Answer == d.B.Answer
+}
+
+// sessionDiff 逐轮对比两个 session, 用于评估改 prompt 或者换模型之后 agent 的行为在哪里发生了变化.
+type sessionDiff struct {
+	Turns []turnDiff
+}
+
+func diffSessions(a, b []*schema.Message) sessionDiff {
+	turnsA, turnsB := splitTurns(a), splitTurns(b)
+	var diff sessionDiff
+	for i := 0; i < max(len(turnsA), len(turnsB)); i++ {
+		d := turnDiff{Index: i, DivergedAt: -1}
+		if i < len(turnsA) {
+			d.A = &turnsA[i]
+		}
+		if i < len(turnsB) {
+			d.B = &turnsB[i]
+		}
+		if d.A != nil && d.B != nil {
+			d.DivergedAt = firstDifference(d.A.ToolCalls, d.B.ToolCalls)
+		}
+		diff.Turns = append(diff.Turns, d)
+	}
+	return diff
+}
+
+// firstDifference 返回两个序列第一个不同的位置, 一个是另一个的前缀时返回较短的长度, 完全相同时返回 -1.
+func firstDifference(a, b []string) int {
+	for i := 0; i < min(len(a), len(b)); i++ {
+		if a[i] != b[i] {
+			return i
+		}
+	}
+	if len(a) != len(b) {
+		return min(len(a), len(b))
+	}
+	return -1
+}
+
+// Report 返回简洁的对比报告: 相同的轮只占一行, 不同的轮列出分歧之后的 tool call 和两边的回答.
+func (d sessionDiff) Report() string {
+	var sb strings.Builder
+	differ := 0
+	for _, t := range d.Turns {
+		n := t.Index + 1
+		switch {
+		case t.B == nil:
+			differ++
+			fmt.Fprintf(&sb, "turn %d: only in A: %q\n", n, t.A.User)
+			continue
+		case t.A == nil:
+			differ++
+			fmt.Fprintf(&sb, "turn %d: only in B: %q\n", n, t.B.User)
+			continue
+		case t.same():
+			fmt.Fprintf(&sb, "turn %d: same (%d tool calls)\n", n, len(t.A.ToolCalls))
+			continue
+		}
+
+		differ++
+		fmt.Fprintf(&sb, "turn %d: differs\n", n)
+		if t.A.User != t.B.User {
+			fmt.Fprintf(&sb, "  user:\n    A: %q\n    B: %q\n", t.A.User, t.B.User)
+		}
+		if t.DivergedAt >= 0 {
+			fmt.Fprintf(&sb, "  tool calls diverged at call %d:\n", t.DivergedAt+1)
+			for _, call := range t.A.ToolCalls[t.DivergedAt:] {
+				fmt.Fprintf(&sb, "    - A: %s\n", call)
+			}
+			for _, call := range t.B.ToolCalls[t.DivergedAt:] {
+				fmt.Fprintf(&sb, "    + B: %s\n", call)
+			}
+		}
+		if t.A.Answer != t.B.Answer {
+			fmt.Fprintf(&sb, "  final answer:\n    A: %q\n    B: %q\n", t.A.Answer, t.B.Answer)
+		}
+	}
+	fmt.Fprintf(&sb, "%d of %d turns differ\n", differ, len(d.Turns))
+	return sb.String()
+}
+
+// runSessionDiff 实现 diff 子命令: 加载两个 -session 保存的文件, 打印对比报告.
+func runSessionDiff(args []string) error {
+	if len(args) != 2 {
+		return fmt.Errorf("usage: diff <session-a.json> <session-b.json>")
+	}
+	sessions := make([][]*schema.Message, 0, len(args))
+	for _, path := range args {
+		// LoadConversationMemory 把不存在的文件当作新的对话, 这里要明确报错
+		if _, err := os.Stat(path); err != nil {
+			return fmt.Errorf("failed to read session file: %w", err)
+		}
+		m, err := LoadConversationMemory(path)
+		if err != nil {
+			return err
+		}
+		sessions = append(sessions, m.Messages())
+	}
+	fmt.Print(diffSessions(sessions[0], sessions[1]).Report())
+	return nil
+}
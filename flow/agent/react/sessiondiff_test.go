@@ -0,0 +1,85 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/cloudwego/eino/schema"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffSessions(t *testing.T) {
+	turn := func(user, answer string, calls ...*schema.Message) []*schema.Message {
+		msgs := []*schema.Message{schema.UserMessage(user)}
+		for _, c := range calls {
+			msgs = append(msgs, c, schema.ToolMessage("{}", c.ToolCalls[0].ID))
+		}
+		return append(msgs, schema.AssistantMessage(answer, nil))
+	}
+	restaurants := toolCallMessage("call_1", "query_restaurants", `{"location": "北京"}`)
+
+	a := append(turn("推荐北京的辣菜", "云边小馆",
+		restaurants,
+		toolCallMessage("call_2", "query_dishes", `{"restaurant_id":"1001"}`)),
+		turn("第二家呢", "聚福轩食府")...)
+	b := append(turn("推荐北京的辣菜", "聚福轩食府",
+		toolCallMessage("call_1", "query_restaurants", `{"location":"北京"}`),
+		toolCallMessage("call_2", "query_dishes", `{"restaurant_id":"1002"}`)),
+		turn("第二家呢", "聚福轩食府")...)
+	b = append(b, turn("谢谢", "不客气")...)
+
+	diff := diffSessions(a, b)
+	assert.Len(t, diff.Turns, 3)
+
+	// 参数只有空白不同的 tool call 算作相同, 分歧从第二个 call 开始
+	assert.Equal(t, 1, diff.Turns[0].DivergedAt)
+	assert.False(t, diff.Turns[0].same())
+	assert.True(t, diff.Turns[1].same())
+	assert.Nil(t, diff.Turns[2].A)
+
+	report := diff.Report()
+	assert.Contains(t, report, "turn 1: differs\n")
+	assert.Contains(t, report, "tool calls diverged at call 2:\n")
+	assert.Contains(t, report, `- A: query_dishes {"restaurant_id":"1001"}`)
+	assert.Contains(t, report, `+ B: query_dishes {"restaurant_id":"1002"}`)
+	assert.Contains(t, report, "turn 2: same (0 tool calls)\n")
+	assert.Contains(t, report, `turn 3: only in B: "谢谢"`)
+	assert.Contains(t, report, "2 of 3 turns differ\n")
+	assert.NotContains(t, report, "user:")
+}
+
+func TestFirstDifference(t *testing.T) {
+	assert.Equal(t, -1, firstDifference([]string{"a", "b"}, []string{"a", "b"}))
+	assert.Equal(t, -1, firstDifference(nil, nil))
+	assert.Equal(t, 1, firstDifference([]string{"a", "b"}, []string{"a", "c"}))
+	// 一边多调用了 tool
+	assert.Equal(t, 1, firstDifference([]string{"a"}, []string{"a", "b"}))
+}
+
+func TestRunSessionDiff(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "a.json")
+	m, err := LoadConversationMemory(path)
+	assert.NoError(t, err)
+	assert.NoError(t, m.Append(schema.UserMessage("你好"), schema.AssistantMessage("你好", nil)))
+
+	assert.NoError(t, runSessionDiff([]string{path, path}))
+	assert.ErrorContains(t, runSessionDiff([]string{path, filepath.Join(dir, "missing.json")}), "failed to read session file")
+	assert.ErrorContains(t, runSessionDiff([]string{path}), "usage")
+}
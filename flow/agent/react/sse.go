@@ -0,0 +1,301 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/cloudwego/eino-examples/flow/agent/react/tools"
+	"github.com/cloudwego/eino/callbacks"
+)
+
+// SSE 的事件名, 前端按事件名分别处理, 比如 tool_started 时显示 "正在查询餐厅...", content 时追加回答.
+const (
+	sseToolStarted  = "tool_started"
+	sseToolFinished = "tool_finished"
+	sseContent      = "content"
+	sseDone         = "done"  // 最后一个事件, 带完整的回答
+	sseError        = "error" // agent 出错, 之后不会再有 done
+	// ssePatch 是 format=json-patch 时唯一的事件, 数据是一组 RFC 6902 操作, 见 sseDocument
+	ssePatch = "patch"
+)
+
+// sseResultLimit 是 tool_finished 中结果摘要的最大字节数, 完整的结果只给模型看, 前端展示进度用不到.
+const sseResultLimit = 200
+
+// sseServer 通过 Server-Sent Events 流式返回一次 agent 运行: 不只是回答的内容, 还有 tool 的调用和结果摘要,
+// 前端可以据此展示进度. 事件来自 EventCallback 的 channel, 和 -events 用的是同一套结构化事件.
+//
+// 和 -events 一样, channel 满了时 content 帧可能被丢弃, 所以 done 事件总是带上完整的回答, 前端应以它为准.
+// 请求带 format=json-patch 时不发以上事件, 而是把每个事件转成对结果文档 (sseDocument) 的 RFC 6902 patch.
+type sseServer struct {
+	// newRunner 为每个请求创建一个 AgentRunner, handlers 需要注册进去.
+	newRunner func(ctx context.Context, handlers []callbacks.Handler) (*AgentRunner, error)
+	// userID 不为空时作为登录用户传给个性化的 tool, 否则每个请求是一个匿名会话.
+	userID string
+
+	// mu 保护 draining 和 active; runs 跟踪进行中的 agent 运行, 只在 draining 为 false 时 Add, 所以 drain 之后可以安全地 Wait.
+	mu       sync.Mutex
+	draining bool
+	active   int
+	runs     sync.WaitGroup
+}
+
+// track 登记一次 agent 运行, 返回结束时调用的函数. 开始 drain 后返回 false, 请求应以 503 拒绝.
+func (s *sseServer) track() (func(), bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.draining {
+		return nil, false
+	}
+	s.active++
+	s.runs.Add(1)
+	return func() {
+		s.mu.Lock()
+		s.active--
+		s.mu.Unlock()
+		s.runs.Done()
+	}, true
+}
+
+// drain 让之后的请求返回 503, 返回此时进行中的运行数和一个在它们全部结束时关闭的 channel.
+func (s *sseServer) drain() (int, <-chan struct{}) {
+	s.mu.Lock()
+	s.draining = true
+	active := s.active
+	s.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		s.runs.Wait()
+		close(done)
+	}()
+	return active, done
+}
+
+func (s *sseServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	query := strings.TrimSpace(r.URL.Query().Get("query"))
+	if query == "" {
+		http.Error(w, "missing query parameter", http.StatusBadRequest)
+		return
+	}
+	var patches *jsonPatchStream
+	switch format := r.URL.Query().Get("format"); format {
+	case "", "events":
+	case "json-patch":
+		patches = newJSONPatchStream()
+	default:
+		http.Error(w, fmt.Sprintf("unknown format %q, use events or json-patch", format), http.StatusBadRequest)
+		return
+	}
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+	finish, ok := s.track()
+	if !ok {
+		w.Header().Set("Connection", "close")
+		http.Error(w, "server is shutting down", http.StatusServiceUnavailable)
+		return
+	}
+	defer finish()
+
+	ctx := r.Context()
+	if s.userID != "" {
+		ctx = tools.WithUserID(ctx, s.userID)
+	} else {
+		ctx = tools.WithGuestSession(ctx)
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+
+	// 客户端断开后写入会失败, 之后只读完 channel, 不再写; 请求的 ctx 同时被取消, agent 会尽快结束
+	var writeErr error
+	send := func(event string, payload any) {
+		if writeErr != nil {
+			return
+		}
+		data, err := json.Marshal(payload)
+		if err != nil {
+			data, _ = json.Marshal(map[string]string{"error": err.Error()})
+		}
+		if writeErr = writeSSE(w, event, data); writeErr == nil {
+			flusher.Flush()
+		}
+	}
+	// json-patch 格式下每个事件都转成对结果文档的修改, 没有变化时不发
+	sendPatch := func(ops []jsonPatchOp) {
+		if len(ops) > 0 {
+			send(ssePatch, ops)
+		}
+	}
+	fail := func(err error) {
+		if patches != nil {
+			sendPatch(patches.update(func(doc *sseDocument) { doc.Status, doc.Error = "error", err.Error() }))
+			return
+		}
+		send(sseError, map[string]string{"error": err.Error()})
+	}
+
+	events := NewEventCallback(defaultEventBuffer)
+	runner, err := s.newRunner(ctx, []callbacks.Handler{events})
+	if err != nil {
+		fail(err)
+		return
+	}
+	defer func() {
+		if err := runner.Close(context.Background()); err != nil {
+			fmt.Printf("[ERROR] failed to flush spans: %v\n", err)
+		}
+	}()
+
+	type result struct {
+		answer string
+		err    error
+	}
+	done := make(chan result, 1)
+	go func() {
+		answer, err := runner.Stream(ctx, query)
+		events.Close()
+		done <- result{answer: answer, err: err}
+	}()
+
+	if patches != nil {
+		sendPatch(patches.update(func(doc *sseDocument) {}))
+	}
+	for ev := range events.Events() {
+		if patches != nil {
+			sendPatch(patches.apply(ev))
+		} else if event, payload, ok := sseEventOf(ev); ok {
+			send(event, payload)
+		}
+	}
+
+	res := <-done
+	if res.err != nil {
+		fail(res.err)
+		return
+	}
+	if patches != nil {
+		// 和 done 事件一样以 agent 的返回值为准, 被丢弃的 content 帧在这里补齐
+		sendPatch(patches.update(func(doc *sseDocument) {
+			doc.Status, doc.Answer, doc.DroppedEvents = "done", res.answer, events.Dropped()
+		}))
+		return
+	}
+	send(sseDone, map[string]any{"answer": res.answer, "dropped_events": events.Dropped()})
+}
+
+// serveSSE 在 ln 上提供 /chat, 直到出错或 ctx 被取消 (SIGINT/SIGTERM). 取消后优雅退出: 新请求返回 503,
+// 关闭 listener 不再接受新连接, 进行中的 agent 运行不会被取消, 最多等 timeout 让它们跑完.
+// 超时后强制关闭所有连接, 这时还在运行的 agent 随请求的 ctx 一起被取消, 并返回错误.
+func serveSSE(ctx context.Context, ln net.Listener, server *sseServer, timeout time.Duration) error {
+	mux := http.NewServeMux()
+	mux.Handle("/chat", server)
+	hs := &http.Server{Handler: mux}
+
+	serveErr := make(chan error, 1)
+	go func() { serveErr <- hs.Serve(ln) }()
+	select {
+	case err := <-serveErr:
+		return err
+	case <-ctx.Done():
+	}
+
+	active, drained := server.drain()
+	fmt.Printf("[SSE] shutting down, waiting up to %s for %d in-flight run(s)\n", timeout, active)
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	err := hs.Shutdown(shutdownCtx)
+	if err == nil {
+		select {
+		case <-drained:
+		case <-shutdownCtx.Done():
+			err = shutdownCtx.Err()
+		}
+	}
+	if err != nil {
+		_ = hs.Close()
+		return fmt.Errorf("runs still in flight after %s were cancelled: %w", timeout, err)
+	}
+	if err := <-serveErr; !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	fmt.Println("[SSE] all in-flight runs finished")
+	return nil
+}
+
+// sseEventOf 把 Event 转成 SSE 的事件名和 JSON 数据.
+func sseEventOf(ev Event) (string, any, bool) {
+	switch ev := ev.(type) {
+	case ToolStarted:
+		return sseToolStarted, map[string]string{"tool": ev.Tool, "arguments": ev.Arguments}, true
+	case ToolFinished:
+		payload := map[string]any{
+			"tool":        ev.Tool,
+			"summary":     summarizeForSSE(ev.Result),
+			"duration_ms": ev.Duration.Milliseconds(),
+		}
+		if ev.Err != nil {
+			payload["error"] = ev.Err.Error()
+		}
+		return sseToolFinished, payload, true
+	case ModelContentDelta:
+		return sseContent, map[string]string{"content": ev.Content}, true
+	}
+	return "", nil, false
+}
+
+// summarizeForSSE 在字符边界把结果截断到 sseResultLimit 字节.
+func summarizeForSSE(result string) string {
+	result = strings.TrimSpace(result)
+	if len(result) <= sseResultLimit {
+		return result
+	}
+	n := sseResultLimit
+	for n > 0 && !utf8.RuneStart(result[n]) {
+		n--
+	}
+	return result[:n] + "..."
+}
+
+// writeSSE 按 SSE 的格式写一个事件: event 行, 每行数据一个 data 行, 最后一个空行结束这个事件.
+// data 中的换行必须拆成多个 data 行, 否则前端会把第二行当成一个新的字段.
+func writeSSE(w io.Writer, event string, data []byte) error {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "event: %s\n", event)
+	for _, line := range strings.Split(string(data), "\n") {
+		fmt.Fprintf(&sb, "data: %s\n", line)
+	}
+	sb.WriteString("\n")
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
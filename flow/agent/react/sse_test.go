@@ -0,0 +1,194 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cloudwego/eino/callbacks"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriteSSE(t *testing.T) {
+	var buf bytes.Buffer
+	assert.NoError(t, writeSSE(&buf, "content", []byte("line 1\nline 2")))
+	assert.Equal(t, "event: content\ndata: line 1\ndata: line 2\n\n", buf.String())
+
+	assert.Equal(t, "short", summarizeForSSE(" short "))
+	long := summarizeForSSE(strings.Repeat("辣", 100))
+	assert.LessOrEqual(t, len(long), sseResultLimit+len("..."))
+	assert.True(t, strings.HasSuffix(long, "辣..."))
+}
+
+type sseEvent struct {
+	name string
+	data map[string]any
+}
+
+// parseSSE 按空行拆分事件, 只处理本例用到的单行 event 和 data.
+func parseSSE(t *testing.T, body string) []sseEvent {
+	var events []sseEvent
+	for _, block := range strings.Split(strings.TrimSpace(body), "\n\n") {
+		ev := sseEvent{}
+		for _, line := range strings.Split(block, "\n") {
+			if name, ok := strings.CutPrefix(line, "event: "); ok {
+				ev.name = name
+			} else if data, ok := strings.CutPrefix(line, "data: "); ok {
+				assert.NoError(t, json.Unmarshal([]byte(data), &ev.data))
+			}
+		}
+		events = append(events, ev)
+	}
+	return events
+}
+
+// newMockSSERunner 用 defaultMockScript 回答每个请求.
+func newMockSSERunner(ctx context.Context, handlers []callbacks.Handler) (*AgentRunner, error) {
+	return NewAgentRunner(ctx, &AgentRunnerConfig{
+		ChatModel:  newScriptedModel(defaultMockScript()...),
+		PromptVars: map[string]string{"City": "北京"},
+		Handlers:   handlers,
+	})
+}
+
+func TestSSEServer(t *testing.T) {
+	server := &sseServer{newRunner: newMockSSERunner}
+
+	rec := httptest.NewRecorder()
+	server.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/chat?query="+url.QueryEscape("推荐辣的菜"), nil))
+	assert.Equal(t, "text/event-stream", rec.Header().Get("Content-Type"))
+
+	events := parseSSE(t, rec.Body.String())
+	var names []string
+	for _, ev := range events {
+		names = append(names, ev.name)
+	}
+	assert.Contains(t, names, sseToolStarted)
+	assert.Contains(t, names, sseToolFinished)
+	assert.Contains(t, names, sseContent)
+
+	// 先开始再结束, 最后一个事件是带完整回答的 done
+	assert.Equal(t, sseToolStarted, events[0].name)
+	last := events[len(events)-1]
+	assert.Equal(t, sseDone, last.name)
+	assert.Contains(t, last.data["answer"], "聚福轩食府")
+	for _, ev := range events {
+		if ev.name == sseToolFinished {
+			assert.NotEmpty(t, ev.data["tool"])
+			assert.LessOrEqual(t, len(ev.data["summary"].(string)), sseResultLimit+len("..."))
+		}
+	}
+
+	rec = httptest.NewRecorder()
+	server.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/chat", nil))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestServeSSEGracefulShutdown(t *testing.T) {
+	started, release := make(chan struct{}), make(chan struct{})
+	server := &sseServer{newRunner: func(ctx context.Context, handlers []callbacks.Handler) (*AgentRunner, error) {
+		close(started)
+		<-release
+		return newMockSSERunner(ctx, handlers)
+	}}
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	ctx, cancel := context.WithCancel(context.Background())
+	serveErr := make(chan error, 1)
+	go func() { serveErr <- serveSSE(ctx, ln, server, 5*time.Second) }()
+
+	chatURL := "http://" + ln.Addr().String() + "/chat?query=" + url.QueryEscape("推荐辣的菜")
+	body := make(chan string, 1)
+	go func() {
+		resp, err := http.Get(chatURL)
+		if !assert.NoError(t, err) {
+			body <- ""
+			return
+		}
+		defer resp.Body.Close()
+		data, _ := io.ReadAll(resp.Body)
+		body <- string(data)
+	}()
+	<-started
+
+	// 开始退出后不再接受新的请求, 但进行中的运行没有被取消
+	cancel()
+	assert.Eventually(t, func() bool {
+		server.mu.Lock()
+		defer server.mu.Unlock()
+		return server.draining
+	}, time.Second, 10*time.Millisecond)
+	rec := httptest.NewRecorder()
+	server.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/chat?query=q", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	select {
+	case err := <-serveErr:
+		t.Fatalf("serveSSE returned before the in-flight run finished: %v", err)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(release)
+	events := parseSSE(t, <-body)
+	assert.Equal(t, sseDone, events[len(events)-1].name)
+	assert.NoError(t, <-serveErr)
+	_, err = http.Get(chatURL)
+	assert.Error(t, err)
+}
+
+func TestServeSSEShutdownTimeout(t *testing.T) {
+	started, cancelled := make(chan struct{}), make(chan struct{})
+	server := &sseServer{newRunner: func(ctx context.Context, handlers []callbacks.Handler) (*AgentRunner, error) {
+		close(started)
+		<-ctx.Done()
+		close(cancelled)
+		return nil, ctx.Err()
+	}}
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	ctx, cancel := context.WithCancel(context.Background())
+	serveErr := make(chan error, 1)
+	go func() { serveErr <- serveSSE(ctx, ln, server, 50*time.Millisecond) }()
+
+	go func() {
+		if resp, err := http.Get("http://" + ln.Addr().String() + "/chat?query=q"); err == nil {
+			_, _ = io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+	}()
+	<-started
+	cancel()
+
+	// 超时后强制关闭连接, 还在运行的 agent 随请求的 ctx 被取消
+	err = <-serveErr
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.ErrorContains(t, err, "in flight")
+	select {
+	case <-cancelled:
+	case <-time.After(time.Second):
+		t.Fatal("the in-flight run was not cancelled")
+	}
+}
@@ -0,0 +1,108 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cloudwego/eino-examples/flow/agent/react/tools"
+	"github.com/cloudwego/eino/callbacks"
+	"github.com/cloudwego/eino/components"
+	"github.com/cloudwego/eino/components/tool"
+	"github.com/cloudwego/eino/compose"
+	"github.com/cloudwego/eino/flow/agent"
+	"github.com/cloudwego/eino/schema"
+	"github.com/stretchr/testify/assert"
+)
+
+// sleepyTool sleeps sleep_ms and then reports success or failure through ToolExecutionState, like safeTool does.
+type sleepyTool struct{}
+
+func (s *sleepyTool) Info(ctx context.Context) (*schema.ToolInfo, error) {
+	return &schema.ToolInfo{
+		Name: "sleepy",
+		ParamsOneOf: schema.NewParamsOneOfByParams(map[string]*schema.ParameterInfo{
+			"sleep_ms": {Type: schema.Integer},
+			"fail":     {Type: schema.Boolean},
+		}),
+	}, nil
+}
+
+func (s *sleepyTool) InvokableRun(ctx context.Context, argumentsInJSON string, opts ...tool.Option) (string, error) {
+	var args struct {
+		SleepMs int  `json:"sleep_ms"`
+		Fail    bool `json:"fail"`
+	}
+	if err := json.Unmarshal([]byte(argumentsInJSON), &args); err != nil {
+		return "", err
+	}
+	time.Sleep(time.Duration(args.SleepMs) * time.Millisecond)
+
+	if state := tools.GetToolState(ctx); state != nil {
+		state.Success = !args.Fail
+	}
+	return argumentsInJSON, nil
+}
+
+func TestToolExecutionStateIsolation(t *testing.T) {
+	ctx := context.Background()
+
+	// 一次发起两个并行的调用: 慢的成功, 快的失败. 如果 state 在两次调用间共享, 慢的调用结束时会读到快的调用写入的值
+	calls := toolCallMessage("call_slow", "sleepy", `{"sleep_ms": 100, "fail": false}`)
+	calls.ToolCalls = append(calls.ToolCalls, toolCallMessage("call_fast", "sleepy", `{"sleep_ms": 10, "fail": true}`).ToolCalls...)
+	chatModel := newScriptedModel(calls, schema.AssistantMessage("done", nil))
+
+	ragent, err := newAgent(ctx, chatModel, []tool.BaseTool{&sleepyTool{}}, 0, 0)
+	assert.NoError(t, err)
+
+	var (
+		mu      sync.Mutex
+		success = map[string]bool{}
+	)
+	checker := callbacks.NewHandlerBuilder().OnEndFn(
+		func(ctx context.Context, info *callbacks.RunInfo, output callbacks.CallbackOutput) context.Context {
+			if info.Component == components.ComponentOfTool {
+				state := tools.GetToolState(ctx)
+				assert.NotNil(t, state)
+				if state != nil {
+					mu.Lock()
+					success[tool.ConvCallbackOutput(output).Response] = state.Success
+					mu.Unlock()
+				}
+			}
+			return ctx
+		}).Build()
+
+	var buf bytes.Buffer
+	logger := &LoggerCallback{Out: &buf}
+	_, err = ragent.Generate(ctx, []*schema.Message{schema.UserMessage("hi")},
+		agent.WithComposeOptions(compose.WithCallbacks(logger, checker)))
+	assert.NoError(t, err)
+
+	assert.Equal(t, map[string]bool{
+		`{"sleep_ms": 100, "fail": false}`: true,
+		`{"sleep_ms": 10, "fail": true}`:   false,
+	}, success)
+	assert.Equal(t, 1, strings.Count(buf.String(), "success=true"))
+	assert.Equal(t, 1, strings.Count(buf.String(), "success=false"))
+}
@@ -0,0 +1,141 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"context"
+	"sync"
+
+	"github.com/cloudwego/eino/callbacks"
+	"github.com/cloudwego/eino/components"
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/components/tool"
+	"github.com/cloudwego/eino/compose"
+	"github.com/cloudwego/eino/flow/agent"
+	"github.com/cloudwego/eino/schema"
+)
+
+// stepRecorder 通过 callback 按发生顺序收集中间步骤: 模型发起的 tool call, 以及每个 tool 的结果.
+// 只记录带 tool call 的模型输出, 最终回答 (以及摘要之类的额外模型调用) 不算中间步骤.
+// generate 和 stream 模式都支持, stream 模式下读取 steps 之前需要先调用 wait.
+type stepRecorder struct {
+	mu    sync.Mutex
+	steps []*schema.Message
+
+	wg sync.WaitGroup // 跟踪 onEndWithStreamOutput 中启动的 goroutine
+}
+
+func (r *stepRecorder) handler() callbacks.Handler {
+	return callbacks.NewHandlerBuilder().OnEndFn(r.onEnd).OnEndWithStreamOutputFn(r.onEndWithStreamOutput).Build()
+}
+
+// wait 等待所有流式输出读完, 返回收集到的步骤.
+func (r *stepRecorder) wait() []*schema.Message {
+	r.wg.Wait()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	steps := make([]*schema.Message, 0, len(r.steps))
+	for _, step := range r.steps {
+		if step != nil {
+			steps = append(steps, step)
+		}
+	}
+	return steps
+}
+
+func (r *stepRecorder) onEndWithStreamOutput(ctx context.Context, info *callbacks.RunInfo,
+	output *schema.StreamReader[callbacks.CallbackOutput]) context.Context {
+	if info.Component != components.ComponentOfChatModel {
+		output.Close()
+		return ctx
+	}
+
+	// 流读完之前 tool 可能已经执行完了, 先占住位置, 保证 tool call 排在它的结果之前.
+	// 不带 tool call 的输出留下的空位在 wait 中去掉
+	r.mu.Lock()
+	slot := len(r.steps)
+	r.steps = append(r.steps, nil)
+	r.mu.Unlock()
+
+	r.wg.Add(1)
+	go func() {
+		defer r.wg.Done()
+		defer output.Close()
+
+		var frames []*schema.Message
+		for {
+			frame, err := output.Recv()
+			if err != nil {
+				break
+			}
+			if mco := model.ConvCallbackOutput(frame); mco != nil && mco.Message != nil {
+				frames = append(frames, mco.Message)
+			}
+		}
+		if len(frames) == 0 {
+			return
+		}
+		msg, err := schema.ConcatMessages(frames)
+		if err != nil || len(msg.ToolCalls) == 0 {
+			return
+		}
+
+		r.mu.Lock()
+		r.steps[slot] = msg
+		r.mu.Unlock()
+	}()
+	return ctx
+}
+
+func (r *stepRecorder) onEnd(ctx context.Context, info *callbacks.RunInfo, output callbacks.CallbackOutput) context.Context {
+	var step *schema.Message
+	switch info.Component {
+	case components.ComponentOfChatModel:
+		if mco := model.ConvCallbackOutput(output); mco != nil && mco.Message != nil && len(mco.Message.ToolCalls) > 0 {
+			step = mco.Message
+		}
+	case components.ComponentOfTool:
+		if tco := tool.ConvCallbackOutput(output); tco != nil {
+			step = schema.ToolMessage(tco.Response, compose.GetToolCallID(ctx), schema.WithToolName(info.Name))
+		}
+	}
+
+	if step != nil {
+		r.mu.Lock()
+		r.steps = append(r.steps, step)
+		r.mu.Unlock()
+	}
+	return ctx
+}
+
+// RunWithSteps 以 generate 模式运行 agent, 除了最终回答, 还返回中间的 tool call 和 tool 结果, 方便查看推理过程或做审计.
+// messages 的第一条不是 system 消息时, 会自动加上 AgentRunner 的 system prompt.
+func (r *AgentRunner) RunWithSteps(ctx context.Context, messages []*schema.Message) (string, []*schema.Message, error) {
+	if len(messages) == 0 || messages[0].Role != schema.System {
+		messages = append([]*schema.Message{schema.SystemMessage(r.systemPrompt)}, messages...)
+	}
+
+	recorder := &stepRecorder{}
+	opts := append([]agent.AgentOption{agent.WithComposeOptions(compose.WithCallbacks(recorder.handler()))}, r.opts...)
+	msg, err := r.agent.Generate(ctx, messages, opts...)
+	if err != nil {
+		return "", nil, err
+	}
+
+	return msg.Content, recorder.wait(), nil
+}
@@ -0,0 +1,51 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cloudwego/eino/schema"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunWithSteps(t *testing.T) {
+	ctx := context.Background()
+	runner, err := NewAgentRunner(ctx, &AgentRunnerConfig{
+		ChatModel:  newScriptedModel(defaultMockScript()...),
+		PromptVars: map[string]string{"City": "北京"},
+	})
+	assert.NoError(t, err)
+	defer runner.Close(ctx)
+
+	final, steps, err := runner.RunWithSteps(ctx, []*schema.Message{schema.UserMessage("我在北京，给我推荐一些辣的菜")})
+	assert.NoError(t, err)
+	assert.NotEmpty(t, final)
+
+	// 3 轮 tool call, 每轮一个模型输出和一个 tool 结果
+	assert.Len(t, steps, 6)
+	for i := 0; i < len(steps); i += 2 {
+		call, result := steps[i], steps[i+1]
+		assert.Equal(t, schema.Assistant, call.Role)
+		assert.Len(t, call.ToolCalls, 1)
+		assert.Equal(t, schema.Tool, result.Role)
+		assert.Equal(t, call.ToolCalls[0].ID, result.ToolCallID)
+		assert.Equal(t, call.ToolCalls[0].Function.Name, result.ToolName)
+		assert.NotEmpty(t, result.Content)
+	}
+}
@@ -0,0 +1,77 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"strings"
+	"sync"
+	"time"
+)
+
+// streamBuffer 把流式输出的 content 增量攒起来再统一输出, 减少逐帧打印带来的闪烁.
+// 缓冲区达到 maxBytes, 或者第一段内容写入后经过 interval, 就会 flush 一次; 流结束时需要调用 Flush 输出剩余内容.
+// interval <= 0 时不缓冲, 每段增量都立即输出.
+type streamBuffer struct {
+	write    func(string)
+	interval time.Duration
+	maxBytes int
+
+	mu    sync.Mutex
+	buf   strings.Builder
+	timer *time.Timer
+}
+
+func newStreamBuffer(write func(string), interval time.Duration, maxBytes int) *streamBuffer {
+	return &streamBuffer{write: write, interval: interval, maxBytes: maxBytes}
+}
+
+func (b *streamBuffer) Add(delta string) {
+	if delta == "" {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.buf.WriteString(delta)
+	if b.interval <= 0 || (b.maxBytes > 0 && b.buf.Len() >= b.maxBytes) {
+		b.flushLocked()
+		return
+	}
+	if b.timer == nil {
+		b.timer = time.AfterFunc(b.interval, b.Flush)
+	}
+}
+
+// Flush 立即输出缓冲区中的内容, 可以并发调用.
+func (b *streamBuffer) Flush() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.flushLocked()
+}
+
+func (b *streamBuffer) flushLocked() {
+	if b.timer != nil {
+		b.timer.Stop()
+		b.timer = nil
+	}
+	if b.buf.Len() == 0 {
+		return
+	}
+	b.write(b.buf.String())
+	b.buf.Reset()
+}
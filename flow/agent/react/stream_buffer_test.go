@@ -0,0 +1,74 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type chunkCollector struct {
+	mu     sync.Mutex
+	chunks []string
+}
+
+func (c *chunkCollector) write(s string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.chunks = append(c.chunks, s)
+}
+
+func (c *chunkCollector) get() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]string(nil), c.chunks...)
+}
+
+func TestStreamBuffer(t *testing.T) {
+	t.Run("unbuffered", func(t *testing.T) {
+		c := &chunkCollector{}
+		b := newStreamBuffer(c.write, 0, 0)
+		b.Add("a")
+		b.Add("b")
+		assert.Equal(t, []string{"a", "b"}, c.get())
+	})
+
+	t.Run("flush by size and on eof", func(t *testing.T) {
+		c := &chunkCollector{}
+		b := newStreamBuffer(c.write, time.Hour, 4)
+		for _, d := range []string{"ab", "cd", "ef", ""} {
+			b.Add(d)
+		}
+		assert.Equal(t, []string{"abcd"}, c.get())
+		b.Flush()
+		b.Flush()
+		assert.Equal(t, []string{"abcd", "ef"}, c.get())
+	})
+
+	t.Run("flush by interval", func(t *testing.T) {
+		c := &chunkCollector{}
+		b := newStreamBuffer(c.write, 20*time.Millisecond, 0)
+		b.Add("a")
+		b.Add("b")
+		assert.Empty(t, c.get())
+		assert.Eventually(t, func() bool { return len(c.get()) == 1 }, time.Second, 5*time.Millisecond)
+		assert.Equal(t, []string{"ab"}, c.get())
+	})
+}
@@ -0,0 +1,77 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/flow/agent/react"
+	"github.com/cloudwego/eino/schema"
+)
+
+const summarizePrompt = `下面是查询餐厅和菜品的工具返回的原始结果, 请在不丢失餐厅 id、名字、评分、菜品名、价格和口味信息的前提下, 把它们压缩成一段简洁的摘要.`
+
+// summarizedPlaceholder 替换已经被摘要的 tool 结果. tool 消息本身要保留, 否则 assistant 消息中的 tool call 会找不到对应的结果.
+const summarizedPlaceholder = "[此结果已被摘要, 见后续的工具结果摘要]"
+
+// newToolResultSummarizer 返回一个 MessageRewriter: 当累计的 tool 结果超过 threshold 字节时,
+// 调用模型把这些结果压缩成一段摘要, 再生成最终回答. MessageRewriter 会把改写后的消息写回 state,
+// 所以同一批结果只会被摘要一次. threshold <= 0 时不做任何处理.
+func newToolResultSummarizer(summarizer model.BaseChatModel, threshold int) react.MessageModifier {
+	return func(ctx context.Context, input []*schema.Message) []*schema.Message {
+		if threshold <= 0 {
+			return input
+		}
+
+		var sb strings.Builder
+		for _, msg := range input {
+			if msg.Role == schema.Tool && msg.Content != summarizedPlaceholder {
+				sb.WriteString(msg.Content)
+				sb.WriteString("\n")
+			}
+		}
+		if sb.Len() <= threshold {
+			return input
+		}
+
+		fmt.Printf("[SUMMARY] tool results reached %d bytes (threshold %d), summarizing\n", sb.Len(), threshold)
+		summary, err := summarizer.Generate(ctx, []*schema.Message{
+			schema.SystemMessage(summarizePrompt),
+			schema.UserMessage(sb.String()),
+		})
+		if err != nil {
+			fmt.Printf("[ERROR] failed to summarize tool results, keeping them as is: %v\n", err)
+			return input
+		}
+
+		output := make([]*schema.Message, 0, len(input)+1)
+		for _, msg := range input {
+			if msg.Role == schema.Tool {
+				replaced := *msg
+				replaced.Content = summarizedPlaceholder
+				msg = &replaced
+			}
+			output = append(output, msg)
+		}
+		output = append(output, schema.SystemMessage("工具结果摘要:\n"+summary.Content))
+		fmt.Printf("[SUMMARY] condensed to %d bytes\n", len(summary.Content))
+		return output
+	}
+}
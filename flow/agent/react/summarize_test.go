@@ -0,0 +1,53 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/cloudwego/eino/schema"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestToolResultSummarizer(t *testing.T) {
+	ctx := context.Background()
+	input := []*schema.Message{
+		schema.UserMessage("推荐餐厅"),
+		toolCallMessage("call_1", "query_restaurants", `{"location":"北京"}`),
+		schema.ToolMessage(strings.Repeat("餐厅", 50), "call_1"),
+	}
+
+	// 未超过阈值时原样返回, 不调用模型
+	summarizer := newScriptedModel()
+	assert.Equal(t, input, newToolResultSummarizer(summarizer, 1000)(ctx, input))
+	assert.Equal(t, input, newToolResultSummarizer(summarizer, 0)(ctx, input))
+
+	summarizer = newScriptedModel(schema.AssistantMessage("北京有两家餐厅", nil))
+	output := newToolResultSummarizer(summarizer, 100)(ctx, input)
+	assert.Len(t, output, 4)
+	assert.Equal(t, schema.Tool, output[2].Role)
+	assert.Equal(t, "call_1", output[2].ToolCallID)
+	assert.Equal(t, summarizedPlaceholder, output[2].Content)
+	assert.Contains(t, output[3].Content, "北京有两家餐厅")
+	// 原始消息不应被修改
+	assert.NotEqual(t, summarizedPlaceholder, input[2].Content)
+
+	// 已经摘要过的结果不会再次触发摘要
+	assert.Equal(t, output, newToolResultSummarizer(newScriptedModel(), 100)(ctx, output))
+}
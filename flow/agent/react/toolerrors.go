@@ -0,0 +1,166 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/cloudwego/eino/callbacks"
+	"github.com/cloudwego/eino/components"
+	"github.com/cloudwego/eino/components/tool"
+	"github.com/cloudwego/eino/compose"
+	"github.com/cloudwego/eino/schema"
+
+	"github.com/cloudwego/eino-examples/flow/agent/react/tools"
+)
+
+// ToolError 是一次 tool 调用遇到的错误.
+type ToolError struct {
+	Tool   string
+	CallID string
+	// Message 是错误的内容: 可恢复的错误是交给模型的 content, 致命的错误是 Go error 的文本
+	Message string
+	// Fatal 为 true 表示 tool 返回了 Go error (比如 -strict 或者流式 tool 中途出错), agent 会因此中断;
+	// 否则是 safeTool 转成 content 交给模型的错误, 模型可以据此重试或换一种做法, agent 能继续运行
+	Fatal bool
+}
+
+// ToolErrorCallback 收集一次运行中所有 tool 的错误, 包括模型已经处理掉的那些, 方便排查不稳定的后端.
+// 和 RunSummaryCallback 一样以 ToolExecutionState.Success 判断是否出错, 返回 Go error 的调用算作致命错误.
+// 流式输出在后台读完后才计入, 读取之前需要调用 Errors 等待.
+type ToolErrorCallback struct {
+	// Out 是输出, 为空时输出到 os.Stdout.
+	Out io.Writer
+
+	mu   sync.Mutex
+	errs []ToolError
+
+	wg sync.WaitGroup // 跟踪 OnEndWithStreamOutput 中启动的 goroutine
+}
+
+func (c *ToolErrorCallback) OnStart(ctx context.Context, info *callbacks.RunInfo, input callbacks.CallbackInput) context.Context {
+	if info.Component == components.ComponentOfTool && tools.GetToolState(ctx) == nil {
+		// 没有 LoggerCallback 时自己放一个, 让 tool 能报告是否成功
+		ctx = tools.SetToolState(ctx, &tools.ToolExecutionState{})
+	}
+	return ctx
+}
+
+func (c *ToolErrorCallback) OnEnd(ctx context.Context, info *callbacks.RunInfo, output callbacks.CallbackOutput) context.Context {
+	if info.Component != components.ComponentOfTool {
+		return ctx
+	}
+	if state := tools.GetToolState(ctx); state != nil && !state.Success {
+		var content string
+		if tco := tool.ConvCallbackOutput(output); tco != nil {
+			content = tco.Response
+		}
+		c.add(ctx, info.Name, content, false)
+	}
+	return ctx
+}
+
+func (c *ToolErrorCallback) OnError(ctx context.Context, info *callbacks.RunInfo, err error) context.Context {
+	if info.Component == components.ComponentOfTool {
+		c.add(ctx, info.Name, err.Error(), true)
+	}
+	return ctx
+}
+
+func (c *ToolErrorCallback) OnStartWithStreamInput(ctx context.Context, info *callbacks.RunInfo,
+	input *schema.StreamReader[callbacks.CallbackInput]) context.Context {
+	input.Close()
+	return ctx
+}
+
+func (c *ToolErrorCallback) OnEndWithStreamOutput(ctx context.Context, info *callbacks.RunInfo,
+	output *schema.StreamReader[callbacks.CallbackOutput]) context.Context {
+	if info.Component != components.ComponentOfTool {
+		output.Close()
+		return ctx
+	}
+
+	c.wg.Add(1)
+	go func() {
+		defer c.wg.Done()
+		defer output.Close()
+
+		var sb strings.Builder
+		for {
+			frame, err := output.Recv()
+			if errors.Is(err, io.EOF) {
+				if state := tools.GetToolState(ctx); state != nil && !state.Success {
+					c.add(ctx, info.Name, sb.String(), false)
+				}
+				return
+			}
+			if err != nil {
+				c.add(ctx, info.Name, err.Error(), true)
+				return
+			}
+			if tco := tool.ConvCallbackOutput(frame); tco != nil {
+				sb.WriteString(tco.Response)
+			}
+		}
+	}()
+	return ctx
+}
+
+func (c *ToolErrorCallback) add(ctx context.Context, name, message string, fatal bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.errs = append(c.errs, ToolError{Tool: name, CallID: compose.GetToolCallID(ctx), Message: message, Fatal: fatal})
+}
+
+// Errors 等待所有流读完, 按发生的顺序返回收集到的错误.
+func (c *ToolErrorCallback) Errors() []ToolError {
+	c.wg.Wait()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]ToolError(nil), c.errs...)
+}
+
+// Print 先打印可恢复和致命错误的数量, 再逐条打印; 没有错误时只打印一行.
+func (c *ToolErrorCallback) Print() {
+	out := c.Out
+	if out == nil {
+		out = os.Stdout
+	}
+
+	errs := c.Errors()
+	fatal := 0
+	for _, e := range errs {
+		if e.Fatal {
+			fatal++
+		}
+	}
+	_, _ = fmt.Fprintf(out, "[TOOL ERRORS] %d recoverable errors occurred, %d fatal\n", len(errs)-fatal, fatal)
+	for _, e := range errs {
+		kind := "recovered"
+		if e.Fatal {
+			kind = "fatal"
+		}
+		_, _ = fmt.Fprintf(out, "[TOOL ERRORS]   %s (%s) %s: %s\n", e.Tool, e.CallID, kind, e.Message)
+	}
+}
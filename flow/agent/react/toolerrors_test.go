@@ -0,0 +1,76 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/cloudwego/eino/components/tool"
+	"github.com/cloudwego/eino/compose"
+	"github.com/cloudwego/eino/flow/agent"
+	"github.com/cloudwego/eino/schema"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestToolErrorCallback(t *testing.T) {
+	ctx := context.Background()
+
+	for _, stream := range []bool{false, true} {
+		// 第一轮失败的调用由模型在第二轮重试成功, agent 最终给出回答
+		chatModel := newScriptedModel(
+			toolCallMessage("call_1", "sleepy", `{"fail": true}`),
+			toolCallMessage("call_2", "sleepy", `{"fail": false}`),
+			schema.AssistantMessage("done", nil),
+		)
+		ragent, err := newAgent(ctx, chatModel, []tool.BaseTool{&sleepyTool{}}, 0, 0)
+		assert.NoError(t, err)
+
+		out := &strings.Builder{}
+		c := &ToolErrorCallback{Out: out}
+		opt := agent.WithComposeOptions(compose.WithCallbacks(c))
+		messages := []*schema.Message{schema.UserMessage("hi")}
+		if stream {
+			_, err = runStream(ctx, ragent, messages, &LoggerCallback{Out: &strings.Builder{}}, opt)
+		} else {
+			_, err = ragent.Generate(ctx, messages, opt)
+		}
+		assert.NoError(t, err)
+
+		errs := c.Errors()
+		assert.Equal(t, []ToolError{{Tool: "sleepy", CallID: "call_1", Message: `{"fail": true}`}}, errs, "stream=%v", stream)
+
+		c.Print()
+		assert.Contains(t, out.String(), "[TOOL ERRORS] 1 recoverable errors occurred, 0 fatal")
+		assert.Contains(t, out.String(), "sleepy (call_1) recovered")
+	}
+
+	// tool 返回 Go error 时 agent 中断, 算作致命错误
+	chatModel := newScriptedModel(toolCallMessage("call_1", "sleepy", `not json`))
+	ragent, err := newAgent(ctx, chatModel, []tool.BaseTool{&sleepyTool{}}, 0, 0)
+	assert.NoError(t, err)
+	c := &ToolErrorCallback{Out: &strings.Builder{}}
+	_, err = ragent.Generate(ctx, []*schema.Message{schema.UserMessage("hi")}, agent.WithComposeOptions(compose.WithCallbacks(c)))
+	assert.Error(t, err)
+
+	errs := c.Errors()
+	if assert.Len(t, errs, 1) {
+		assert.True(t, errs[0].Fatal)
+		assert.Equal(t, "call_1", errs[0].CallID)
+	}
+}
@@ -0,0 +1,135 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tools
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/cloudwego/eino/components/tool"
+	"github.com/cloudwego/eino/schema"
+)
+
+func GetAccessibilityTool() tool.InvokableTool {
+	return safeTool{
+		InvokableTool: NewCancellableTool(&ToolAccessibility{
+			backService: restService,
+		}),
+	}
+}
+
+// ToolAccessibility 返回餐厅的无障碍设施信息, 每一项都是含义明确的布尔值.
+type ToolAccessibility struct {
+	backService *fakeService // fake service
+}
+
+func (t *ToolAccessibility) Info(ctx context.Context) (*schema.ToolInfo, error) {
+	return &schema.ToolInfo{
+		Name: "query_accessibility",
+		Desc: "Query the accessibility of a restaurant: wheelchair access, braille menu and step-free entry",
+		ParamsOneOf: schema.NewParamsOneOfByParams(map[string]*schema.ParameterInfo{
+			"restaurant_id": {
+				Type:     "string",
+				Desc:     "The id of one restaurant",
+				Required: true,
+			},
+		}),
+	}, nil
+}
+
+func (t *ToolAccessibility) InvokableRun(ctx context.Context, argumentsInJSON string, opts ...tool.Option) (string, error) {
+	// 解析参数
+	if err := checkRequired(ctx, t, argumentsInJSON); err != nil {
+		return "", err
+	}
+	p := &AccessibilityParam{}
+	err := json.Unmarshal([]byte(argumentsInJSON), p)
+	if err != nil {
+		return "", err
+	}
+
+	// 请求后端服务
+	info, err := t.backService.QueryAccessibility(ctx, p)
+	if err != nil {
+		return "", err
+	}
+
+	// 序列化结果
+	res, err := marshalResult(info)
+	if err != nil {
+		return "", err
+	}
+
+	return string(res), nil
+}
+
+type AccessibilityParam struct {
+	RestaurantID string `json:"restaurant_id"`
+}
+
+// Accessibility 是餐厅的无障碍设施.
+type Accessibility struct {
+	WheelchairAccessible bool `json:"wheelchair_accessible"`
+	BrailleMenu          bool `json:"braille_menu"`
+	StepFreeEntry        bool `json:"step_free_entry"`
+}
+
+type AccessibilityInfo struct {
+	RestaurantID string `json:"restaurant_id"`
+	Name         string `json:"name"`
+	// InfoAvailable 为 false 表示餐厅没有公开无障碍信息, 此时不能把各项当作 "没有"
+	InfoAvailable bool           `json:"info_available"`
+	Accessibility *Accessibility `json:"accessibility,omitempty"`
+	Message       string         `json:"message,omitempty"`
+}
+
+// QueryAccessibility 查询一家餐厅的无障碍设施.
+func (ft *fakeService) QueryAccessibility(ctx context.Context, in *AccessibilityParam) (*AccessibilityInfo, error) {
+	if err := ft.simulateLatency(ctx); err != nil {
+		return nil, err
+	}
+
+	rest, err := ft.repo.GetRestaurantByID(ctx, in.RestaurantID)
+	if err != nil {
+		return nil, err
+	}
+
+	info := &AccessibilityInfo{RestaurantID: rest.ID, Name: rest.Name}
+	if rest.Accessibility == nil {
+		info.Message = "this restaurant has not published accessibility information, suggest calling ahead"
+		return info, nil
+	}
+	info.InfoAvailable = true
+	info.Accessibility = toAccessibility(rest.Accessibility)
+	return info, nil
+}
+
+// isAccessible 轮椅可以进入并且入口无台阶才算无障碍, 没有公开信息的餐厅不算.
+func isAccessible(item *restaurantAccessibilityItem) bool {
+	return item != nil && item.WheelchairAccessible && item.StepFreeEntry
+}
+
+func toAccessibility(item *restaurantAccessibilityItem) *Accessibility {
+	if item == nil {
+		return nil
+	}
+	return &Accessibility{
+		WheelchairAccessible: item.WheelchairAccessible,
+		BrailleMenu:          item.BrailleMenu,
+		StepFreeEntry:        item.StepFreeEntry,
+	}
+}
@@ -0,0 +1,66 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tools
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQueryAccessibility(t *testing.T) {
+	ctx := context.Background()
+
+	info, err := restService.QueryAccessibility(ctx, &AccessibilityParam{RestaurantID: "1003"})
+	assert.NoError(t, err)
+	assert.True(t, info.InfoAvailable)
+	assert.Equal(t, &Accessibility{WheelchairAccessible: true, BrailleMenu: true, StepFreeEntry: true}, info.Accessibility)
+
+	info, err = restService.QueryAccessibility(ctx, &AccessibilityParam{RestaurantID: "2010"})
+	assert.NoError(t, err)
+	assert.False(t, info.InfoAvailable)
+	assert.Nil(t, info.Accessibility)
+	assert.NotEmpty(t, info.Message)
+
+	_, err = restService.QueryAccessibility(ctx, &AccessibilityParam{RestaurantID: "404"})
+	assert.Error(t, err)
+}
+
+func TestQueryRestaurantsAccessibleOnly(t *testing.T) {
+	ctx := context.Background()
+
+	// 上海的第一家 2001 入口有台阶, 先筛选再取 topn 才能得到 2002
+	rests, err := restService.QueryRestaurants(ctx, &QueryRestaurantsParam{Location: "上海", Topn: 1, AccessibleOnly: true})
+	assert.NoError(t, err)
+	assert.Len(t, rests, 1)
+	assert.Equal(t, "2002", rests[0].ID)
+	assert.True(t, rests[0].Accessibility.WheelchairAccessible)
+	assert.True(t, rests[0].Accessibility.StepFreeEntry)
+
+	rests, err = restService.QueryRestaurants(ctx, &QueryRestaurantsParam{Location: "北京", Topn: 5, AccessibleOnly: true})
+	assert.NoError(t, err)
+	var ids []string
+	for _, rest := range rests {
+		ids = append(ids, rest.ID)
+	}
+	assert.Equal(t, []string{"1003", "1001"}, ids)
+
+	all, err := restService.QueryRestaurants(ctx, &QueryRestaurantsParam{Location: "北京", Topn: 5})
+	assert.NoError(t, err)
+	assert.Len(t, all, 3)
+}
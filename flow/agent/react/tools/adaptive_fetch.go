@@ -0,0 +1,54 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tools
+
+import "context"
+
+// maxAdaptiveFetch 是 fetchFiltered 一次最多向后端请求的条数, 条件再严格也不会为了凑满 topn 把整张表拉下来.
+const maxAdaptiveFetch = 64
+
+// fetchFiltered 先向后端请求 topn 条, 筛选后不够 topn 条时把请求的条数翻倍再请求一次, 直到凑满 topn、
+// 后端没有更多数据, 或者请求的条数达到 maxAdaptiveFetch. 达到上限时返回已经筛选出的部分, 可能少于 topn 条.
+// fetch 的 limit 总是从头开始算, 后端每次按同样的顺序返回前 limit 条.
+func fetchFiltered[T any](ctx context.Context, topn int, fetch func(ctx context.Context, limit int) ([]T, error), keep func(T) bool) ([]T, error) {
+	if topn <= 0 {
+		return nil, nil
+	}
+
+	limit := topn
+	for {
+		items, err := fetch(ctx, limit)
+		if err != nil {
+			return nil, err
+		}
+
+		res := make([]T, 0, min(topn, len(items)))
+		for _, item := range items {
+			if len(res) >= topn {
+				break
+			}
+			if keep(item) {
+				res = append(res, item)
+			}
+		}
+
+		if len(res) >= topn || len(items) < limit || limit >= maxAdaptiveFetch {
+			return res, nil
+		}
+		limit = min(limit*2, maxAdaptiveFetch)
+	}
+}
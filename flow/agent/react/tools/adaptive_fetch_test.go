@@ -0,0 +1,90 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tools
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFetchFiltered(t *testing.T) {
+	ctx := context.Background()
+	items := make([]int, 200)
+	for i := range items {
+		items[i] = i
+	}
+	var limits []int
+	fetch := func(ctx context.Context, limit int) ([]int, error) {
+		limits = append(limits, limit)
+		return items[:min(limit, len(items))], nil
+	}
+
+	// 够 topn 条时只请求一次
+	res, err := fetchFiltered(ctx, 3, fetch, func(int) bool { return true })
+	assert.NoError(t, err)
+	assert.Equal(t, []int{0, 1, 2}, res)
+	assert.Equal(t, []int{3}, limits)
+
+	// 只有 10 的倍数能通过筛选, 翻倍请求直到凑满
+	limits = nil
+	res, err = fetchFiltered(ctx, 3, fetch, func(i int) bool { return i%10 == 0 })
+	assert.NoError(t, err)
+	assert.Equal(t, []int{0, 10, 20}, res)
+	assert.Equal(t, []int{3, 6, 12, 24}, limits)
+
+	// 请求的条数不超过 maxAdaptiveFetch, 凑不满时返回已有的部分
+	limits = nil
+	res, err = fetchFiltered(ctx, 5, fetch, func(i int) bool { return i%30 == 0 })
+	assert.NoError(t, err)
+	assert.Equal(t, []int{0, 30, 60}, res)
+	assert.Equal(t, []int{5, 10, 20, 40, maxAdaptiveFetch}, limits)
+
+	// 后端没有更多数据时停止
+	limits = nil
+	items = items[:7]
+	res, err = fetchFiltered(ctx, 3, fetch, func(i int) bool { return i > 4 })
+	assert.NoError(t, err)
+	assert.Equal(t, []int{5, 6}, res)
+	assert.Equal(t, []int{3, 6, 12}, limits)
+}
+
+// 严格的筛选条件下, 只取 topn 家再筛选会得到太少的结果.
+func TestQueryRestaurantsWidensFetch(t *testing.T) {
+	ctx := context.Background()
+	rests := make([]restaurantDataItem, 0, 12)
+	for i := 0; i < 12; i++ {
+		rest := restaurantDataItem{ID: string(rune('a' + i)), Score: 12 - i}
+		if i >= 8 {
+			rest.Certifications = &restaurantCertificationsItem{HygieneGrade: "A"}
+		}
+		rests = append(rests, rest)
+	}
+	svc := &fakeService{repo: &restaurantDatabase{
+		restaurantByID:        map[string]restaurantDataItem{},
+		restaurantsByLocation: map[string][]restaurantDataItem{"杭州": rests},
+	}}
+
+	res, err := svc.QueryRestaurants(ctx, &QueryRestaurantsParam{Location: "杭州", Topn: 3, MinHygieneGrade: "A"})
+	assert.NoError(t, err)
+	ids := make([]string, 0, len(res))
+	for _, rest := range res {
+		ids = append(ids, rest.ID)
+	}
+	assert.Equal(t, []string{"i", "j", "k"}, ids)
+}
@@ -0,0 +1,159 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/cloudwego/eino/components/tool"
+	"github.com/cloudwego/eino/schema"
+)
+
+func GetAllergensTool() tool.InvokableTool {
+	return safeTool{
+		InvokableTool: NewCancellableTool(&ToolQueryAllergens{
+			backService: restService,
+		}),
+	}
+}
+
+// ToolQueryAllergens 返回菜品的过敏原标签, 让模型可以提醒有过敏的用户.
+type ToolQueryAllergens struct {
+	backService *fakeService // fake service
+}
+
+func (t *ToolQueryAllergens) Info(ctx context.Context) (*schema.ToolInfo, error) {
+	return &schema.ToolInfo{
+		Name: "query_allergens",
+		Desc: "Query the allergens (nuts, dairy, gluten, shellfish, egg, fish) of the dishes in one restaurant",
+		ParamsOneOf: schema.NewParamsOneOfByParams(map[string]*schema.ParameterInfo{
+			"restaurant_id": {
+				Type:     "string",
+				Desc:     "The id of one restaurant",
+				Required: true,
+			},
+			"dish_name": {
+				Type: "string",
+				Desc: "Only query this dish, leave empty for all dishes of the restaurant",
+			},
+			"exclude_allergen": {
+				Type: "string",
+				Desc: "Leave out dishes containing this allergen",
+				Enum: allergenTags,
+			},
+		}),
+	}, nil
+}
+
+func (t *ToolQueryAllergens) InvokableRun(ctx context.Context, argumentsInJSON string, opts ...tool.Option) (string, error) {
+	// 解析参数
+	if err := checkRequired(ctx, t, argumentsInJSON); err != nil {
+		return "", err
+	}
+	p := &QueryAllergensParam{}
+	err := json.Unmarshal([]byte(argumentsInJSON), p)
+	if err != nil {
+		return "", err
+	}
+
+	// 请求后端服务
+	allergens, err := t.backService.QueryAllergens(ctx, p)
+	if err != nil {
+		return "", err
+	}
+
+	// 序列化结果
+	res, err := marshalResult(allergens)
+	if err != nil {
+		return "", err
+	}
+
+	return string(res), nil
+}
+
+var allergenTags = []string{"nuts", "dairy", "gluten", "shellfish", "egg", "fish"}
+
+type QueryAllergensParam struct {
+	RestaurantID    string `json:"restaurant_id"`
+	DishName        string `json:"dish_name"`
+	ExcludeAllergen string `json:"exclude_allergen"`
+}
+
+type DishAllergens struct {
+	Name      string   `json:"name"`
+	Allergens []string `json:"allergens"` // 没有过敏原时为空数组, 而不是 null
+}
+
+type AllergenInfo struct {
+	RestaurantID     string          `json:"restaurant_id"`
+	Dishes           []DishAllergens `json:"dishes"`
+	ExcludedAllergen string          `json:"excluded_allergen,omitempty"`
+	ExcludedDishes   []string        `json:"excluded_dishes,omitempty"` // 因含有 excluded_allergen 被过滤掉的菜
+}
+
+// QueryAllergens 查询一家餐厅菜品的过敏原.
+func (ft *fakeService) QueryAllergens(ctx context.Context, in *QueryAllergensParam) (*AllergenInfo, error) {
+	if err := ft.simulateLatency(ctx); err != nil {
+		return nil, err
+	}
+
+	exclude := strings.ToLower(strings.TrimSpace(in.ExcludeAllergen))
+	if exclude != "" && !slices.Contains(allergenTags, exclude) {
+		return nil, fmt.Errorf("unknown allergen %q, expected one of %s", in.ExcludeAllergen, strings.Join(allergenTags, ", "))
+	}
+
+	rest, err := ft.repo.GetRestaurantByID(ctx, in.RestaurantID)
+	if err != nil {
+		return nil, err
+	}
+
+	dishes := rest.Dishes
+	if in.DishName != "" {
+		dish, ok := findDish(rest.Dishes, in.DishName)
+		if !ok {
+			return nil, fmt.Errorf("dish %s not found in restaurant %s", in.DishName, in.RestaurantID)
+		}
+		dishes = []restaurantDishDataItem{dish}
+	}
+
+	return filterAllergens(in.RestaurantID, dishes, exclude), nil
+}
+
+// filterAllergens 列出菜品的过敏原, exclude 不为空时过滤掉含有该过敏原的菜.
+func filterAllergens(restaurantID string, dishes []restaurantDishDataItem, exclude string) *AllergenInfo {
+	info := &AllergenInfo{
+		RestaurantID:     restaurantID,
+		Dishes:           make([]DishAllergens, 0, len(dishes)),
+		ExcludedAllergen: exclude,
+	}
+	for _, dish := range dishes {
+		if exclude != "" && slices.Contains(dish.Allergens, exclude) {
+			info.ExcludedDishes = append(info.ExcludedDishes, dish.Name)
+			continue
+		}
+		allergens := dish.Allergens
+		if allergens == nil {
+			allergens = []string{}
+		}
+		info.Dishes = append(info.Dishes, DishAllergens{Name: dish.Name, Allergens: allergens})
+	}
+	return info
+}
@@ -0,0 +1,51 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tools
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQueryAllergens(t *testing.T) {
+	ctx := context.Background()
+
+	info, err := restService.QueryAllergens(ctx, &QueryAllergensParam{RestaurantID: "2010"})
+	assert.NoError(t, err)
+	assert.Len(t, info.Dishes, 2)
+	assert.Equal(t, []string{"shellfish"}, info.Dishes[0].Allergens)
+
+	info, err = restService.QueryAllergens(ctx, &QueryAllergensParam{RestaurantID: "1001", ExcludeAllergen: "Gluten"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"清泉牛肉"}, info.ExcludedDishes)
+	for _, dish := range info.Dishes {
+		assert.NotContains(t, dish.Allergens, "gluten")
+		assert.NotNil(t, dish.Allergens)
+	}
+
+	info, err = restService.QueryAllergens(ctx, &QueryAllergensParam{RestaurantID: "1002", DishName: "皮蛋"})
+	assert.NoError(t, err)
+	assert.Equal(t, []DishAllergens{{Name: "辣椒拌皮蛋", Allergens: []string{"egg"}}}, info.Dishes)
+
+	_, err = restService.QueryAllergens(ctx, &QueryAllergensParam{RestaurantID: "1002", DishName: "不存在的菜"})
+	assert.ErrorContains(t, err, "not found")
+
+	_, err = restService.QueryAllergens(ctx, &QueryAllergensParam{RestaurantID: "1002", ExcludeAllergen: "sugar"})
+	assert.ErrorContains(t, err, "unknown allergen")
+}
@@ -0,0 +1,241 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/cloudwego/eino/components/tool"
+	"github.com/cloudwego/eino/schema"
+)
+
+func GetSuggestAlternativeTool() tool.InvokableTool {
+	return safeTool{
+		InvokableTool: NewCancellableTool(&ToolSuggestAlternative{
+			backService: restService,
+		}),
+	}
+}
+
+// ToolSuggestAlternative 在预订前检查餐厅在这个时间能不能坐下这么多人, 不能时从同一个地方的相似餐厅 (见 similarity)
+// 中挑出能坐下的, 让模型在一次调用中得到 "订不到怎么办" 的答案, 而不是先 validate_reservation_time 再逐家 query_table_eta.
+type ToolSuggestAlternative struct {
+	backService *fakeService // fake service
+}
+
+func (t *ToolSuggestAlternative) Info(ctx context.Context) (*schema.ToolInfo, error) {
+	return &schema.ToolInfo{
+		Name: "suggest_alternative",
+		Desc: "Check whether a restaurant can seat a party at a time, and if not, e.g. when it is fully booked, " +
+			"suggest similar restaurants nearby that can, each with the reason and its availability",
+		ParamsOneOf: schema.NewParamsOneOfByParams(map[string]*schema.ParameterInfo{
+			"restaurant_id": {
+				Type:     "string",
+				Desc:     "The id of the restaurant the user wants to book",
+				Required: true,
+			},
+			"time": {
+				Type:     "string",
+				Desc:     "The time of the reservation, in the format 2006-01-02 15:04, or 15:04 for today",
+				Required: true,
+			},
+			"party_size": {
+				Type:     "integer",
+				Desc:     "How many people are coming",
+				Required: true,
+			},
+		}),
+	}, nil
+}
+
+func (t *ToolSuggestAlternative) InvokableRun(ctx context.Context, argumentsInJSON string, opts ...tool.Option) (string, error) {
+	// 解析参数
+	if err := checkRequired(ctx, t, argumentsInJSON); err != nil {
+		return "", err
+	}
+	p := &SuggestAlternativeParam{}
+	err := json.Unmarshal([]byte(argumentsInJSON), p)
+	if err != nil {
+		return "", err
+	}
+
+	// 请求后端服务
+	suggestion, err := t.backService.SuggestAlternative(ctx, p)
+	if err != nil {
+		return "", err
+	}
+
+	// 序列化结果
+	res, err := marshalResult(suggestion)
+	if err != nil {
+		return "", err
+	}
+
+	return string(res), nil
+}
+
+type SuggestAlternativeParam struct {
+	RestaurantID string `json:"restaurant_id"`
+	Time         string `json:"time"`
+	PartySize    int    `json:"party_size"`
+}
+
+// AlternativeSuggestion 中 available 为 true 时不需要备选, alternatives 为空;
+// 为 false 时 reason 说明原因, next_available 是原来的餐厅当天之后第一个能坐下的整点, 没有时为空.
+type AlternativeSuggestion struct {
+	RestaurantID  string                  `json:"restaurant_id"`
+	Time          string                  `json:"time"` // 2006-01-02 15:04
+	PartySize     int                     `json:"party_size"`
+	Available     bool                    `json:"available"`
+	Reason        string                  `json:"reason"`
+	NextAvailable string                  `json:"next_available,omitempty"` // 2006-01-02 15:04
+	Alternatives  []AlternativeRestaurant `json:"alternatives"`
+	Message       string                  `json:"message,omitempty"`
+}
+
+type AlternativeRestaurant struct {
+	ID         string  `json:"id"`
+	Name       string  `json:"name"`
+	Cuisine    string  `json:"cuisine,omitempty"`
+	Score      int     `json:"score"`
+	Similarity float64 `json:"similarity"` // 0 - 1
+	Busyness   int     `json:"busyness"`   // 预订时间的繁忙程度, 0 - 100
+	Reason     string  `json:"reason"`
+}
+
+const (
+	// fullyBookedBusyness 及以上餐厅的座位都已经订满
+	fullyBookedBusyness = 95
+	// maxAlternatives 是最多返回的备选餐厅数量
+	maxAlternatives = 3
+)
+
+// SuggestAlternative 检查 in.RestaurantID 能不能在 in.Time 坐下 in.PartySize 人, 不能时按相似度返回同一个地方能坐下的餐厅.
+// 原来的餐厅能坐下不是错误, 而是 available 为 true 并说明不需要备选.
+func (ft *fakeService) SuggestAlternative(ctx context.Context, in *SuggestAlternativeParam) (*AlternativeSuggestion, error) {
+	if err := ft.simulateLatency(ctx); err != nil {
+		return nil, err
+	}
+
+	if in.PartySize <= 0 || in.PartySize > maxPartySize {
+		return nil, fmt.Errorf("party_size must be between 1 and %d, got %d", maxPartySize, in.PartySize)
+	}
+	target, err := ft.repo.GetRestaurantByID(ctx, in.RestaurantID)
+	if err != nil {
+		return nil, err
+	}
+	now := ft.now()
+	at, err := parseReservationTime(in.Time, now)
+	if err != nil {
+		return nil, err
+	}
+
+	out := &AlternativeSuggestion{
+		RestaurantID: target.ID,
+		Time:         at.Format(reservationTimeLayout),
+		PartySize:    in.PartySize,
+		Alternatives: []AlternativeRestaurant{},
+	}
+	// 时间本身不可预订时换一家也没有用
+	if problem := reservationTimeProblem(at, now); problem != "" {
+		out.Reason = problem
+		out.Message = "no restaurant can be booked at this time, choose another time"
+		return out, nil
+	}
+
+	out.Reason = unavailableReason(&target, at, in.PartySize)
+	if out.Reason == "" {
+		out.Available = true
+		out.Reason = "a table is available"
+		out.Message = "no alternative is needed, the restaurant can be booked"
+		return out, nil
+	}
+	for next := at.Truncate(time.Hour).Add(time.Hour); next.Hour() <= busyLastHour && next.Day() == at.Day(); next = next.Add(time.Hour) {
+		if unavailableReason(&target, next, in.PartySize) == "" {
+			out.NextAvailable = next.Format(reservationTimeLayout)
+			break
+		}
+	}
+
+	rests, err := ft.repo.GetRestaurants(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+	for _, similar := range rankSimilar(&target, rests, len(rests)) {
+		if len(out.Alternatives) == maxAlternatives {
+			break
+		}
+		rest, err := ft.repo.GetRestaurantByID(ctx, similar.ID)
+		if err != nil || rest.Place != target.Place || unavailableReason(&rest, at, in.PartySize) != "" {
+			continue
+		}
+		out.Alternatives = append(out.Alternatives, AlternativeRestaurant{
+			ID:         rest.ID,
+			Name:       rest.Name,
+			Cuisine:    rest.Cuisine,
+			Score:      rest.Score,
+			Similarity: similar.Similarity,
+			Busyness:   busyness(rest.ID, at.Weekday(), at.Hour()),
+			Reason:     alternativeReason(&target, &rest),
+		})
+	}
+	if len(out.Alternatives) == 0 {
+		out.Message = fmt.Sprintf("no similar restaurant in %s can seat %d people at this time", target.Place, in.PartySize)
+	}
+	return out, nil
+}
+
+// unavailableReason 返回餐厅在 at 坐不下 partySize 人的原因, 能坐下时返回空字符串.
+// 只检查桌子大小和 at 那个小时的繁忙程度, 营业时间由 reservationTimeProblem 检查.
+func unavailableReason(rest *restaurantDataItem, at time.Time, partySize int) string {
+	maxTable := rest.MaxTable
+	if maxTable <= 0 {
+		maxTable = defaultMaxTable
+	}
+	if partySize > maxTable {
+		return fmt.Sprintf("the biggest table seats %d, the party of %d does not fit", maxTable, partySize)
+	}
+	if busy := busyness(rest.ID, at.Weekday(), at.Hour()); busy >= fullyBookedBusyness {
+		return fmt.Sprintf("fully booked at %02d:00 (busyness %d)", at.Hour(), busy)
+	}
+	return ""
+}
+
+// alternativeReason 说明备选餐厅和原来的餐厅有什么相同之处.
+func alternativeReason(target, rest *restaurantDataItem) string {
+	var reasons []string
+	if rest.Cuisine != "" && rest.Cuisine == target.Cuisine {
+		reasons = append(reasons, "same cuisine ("+rest.Cuisine+")")
+	}
+	if math.Abs(float64(rest.Score-target.Score)) <= 1 {
+		reasons = append(reasons, fmt.Sprintf("similar score (%d vs %d)", rest.Score, target.Score))
+	} else if rest.Score > target.Score {
+		reasons = append(reasons, fmt.Sprintf("higher score (%d vs %d)", rest.Score, target.Score))
+	}
+	if rest.Ambiance != nil && target.Ambiance != nil && jaccard(rest.Ambiance.Tags, target.Ambiance.Tags) > 0 {
+		reasons = append(reasons, "similar ambiance")
+	}
+	if len(reasons) == 0 {
+		return "a table is available nearby"
+	}
+	return strings.Join(reasons, ", ")
+}
@@ -0,0 +1,80 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tools
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSuggestAlternative(t *testing.T) {
+	ctx := context.Background()
+	// 2024-06-01 是周六, 19 点是晚高峰
+	svc := &fakeService{repo: database, clock: FixedClock{T: time.Date(2024, 6, 1, 10, 0, 0, 0, time.FixedZone("CST", 8*3600))}}
+
+	s, err := svc.SuggestAlternative(ctx, &SuggestAlternativeParam{RestaurantID: "1001", Time: "12:00", PartySize: 2})
+	assert.NoError(t, err)
+	assert.True(t, s.Available)
+	assert.Empty(t, s.Alternatives)
+	assert.Contains(t, s.Message, "no alternative is needed")
+
+	// 最大的桌子坐不下, 换一家同在上海、氛围相似的餐厅
+	s, err = svc.SuggestAlternative(ctx, &SuggestAlternativeParam{RestaurantID: "1003", Time: "12:00", PartySize: 8})
+	assert.NoError(t, err)
+	assert.False(t, s.Available)
+	assert.Contains(t, s.Reason, "the biggest table seats 6")
+	assert.Empty(t, s.NextAvailable)
+	if assert.Len(t, s.Alternatives, 1) {
+		alt := s.Alternatives[0]
+		assert.Equal(t, "2001", alt.ID)
+		assert.Equal(t, "similar ambiance", alt.Reason)
+		assert.Greater(t, alt.Similarity, 0.0)
+		assert.Less(t, alt.Busyness, fullyBookedBusyness)
+	}
+
+	// 周六晚高峰附近的餐厅都订满了, 给出原来的餐厅下一个能订的时间
+	s, err = svc.SuggestAlternative(ctx, &SuggestAlternativeParam{RestaurantID: "1001", Time: "2024-06-01 19:30", PartySize: 2})
+	assert.NoError(t, err)
+	assert.False(t, s.Available)
+	assert.Contains(t, s.Reason, "fully booked at 19:00")
+	assert.Equal(t, "2024-06-01 20:00", s.NextAvailable)
+	assert.Empty(t, s.Alternatives)
+	assert.Contains(t, s.Message, "no similar restaurant in 北京")
+
+	// 时间本身不可预订
+	s, err = svc.SuggestAlternative(ctx, &SuggestAlternativeParam{RestaurantID: "1001", Time: "09:00", PartySize: 2})
+	assert.NoError(t, err)
+	assert.False(t, s.Available)
+	assert.Equal(t, "the time is in the past", s.Reason)
+	assert.Contains(t, s.Message, "choose another time")
+
+	_, err = svc.SuggestAlternative(ctx, &SuggestAlternativeParam{RestaurantID: "1001", Time: "12:00", PartySize: 0})
+	assert.ErrorContains(t, err, "party_size")
+	_, err = svc.SuggestAlternative(ctx, &SuggestAlternativeParam{RestaurantID: "1001", Time: "noon", PartySize: 2})
+	assert.ErrorContains(t, err, "invalid time")
+}
+
+func TestAlternativeReason(t *testing.T) {
+	a := &restaurantDataItem{Cuisine: "川菜", Score: 5}
+	b := &restaurantDataItem{Cuisine: "川菜", Score: 6}
+	assert.Equal(t, "same cuisine (川菜), similar score (6 vs 5)", alternativeReason(a, b))
+	assert.Equal(t, "higher score (9 vs 5)", alternativeReason(a, &restaurantDataItem{Score: 9}))
+	assert.Equal(t, "a table is available nearby", alternativeReason(a, &restaurantDataItem{Score: 1}))
+}
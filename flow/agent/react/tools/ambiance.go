@@ -0,0 +1,192 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/cloudwego/eino/components/tool"
+	"github.com/cloudwego/eino/schema"
+)
+
+func GetAmbianceTool() tool.InvokableTool {
+	return safeTool{
+		InvokableTool: NewCancellableTool(&ToolQueryAmbiance{
+			backService: restService,
+		}),
+	}
+}
+
+// ToolQueryAmbiance 返回餐厅的氛围标签和噪音等级, 传入 occasion 时由服务端判断是否适合这个场合,
+// 这样模型可以按 "约会"、"带孩子" 之类的场合来推荐.
+type ToolQueryAmbiance struct {
+	backService *fakeService // fake service
+}
+
+func (t *ToolQueryAmbiance) Info(ctx context.Context) (*schema.ToolInfo, error) {
+	return &schema.ToolInfo{
+		Name: "query_ambiance",
+		Desc: "Query the ambiance of a restaurant: tags like romantic, family-friendly or casual, and a noise level from 1 (quiet) to 5 (loud). " +
+			"Pass occasion to check whether the restaurant suits it",
+		ParamsOneOf: schema.NewParamsOneOfByParams(map[string]*schema.ParameterInfo{
+			"restaurant_id": {
+				Type:     "string",
+				Desc:     "The id of one restaurant",
+				Required: true,
+			},
+			"occasion": {
+				Type: "string",
+				Desc: "Optional occasion of the meal, e.g. date night, family, business, friends",
+			},
+		}),
+	}, nil
+}
+
+func (t *ToolQueryAmbiance) InvokableRun(ctx context.Context, argumentsInJSON string, opts ...tool.Option) (string, error) {
+	// 解析参数
+	if err := checkRequired(ctx, t, argumentsInJSON); err != nil {
+		return "", err
+	}
+	p := &QueryAmbianceParam{}
+	err := json.Unmarshal([]byte(argumentsInJSON), p)
+	if err != nil {
+		return "", err
+	}
+
+	// 请求后端服务
+	info, err := t.backService.QueryAmbiance(ctx, p)
+	if err != nil {
+		return "", err
+	}
+
+	// 序列化结果
+	res, err := marshalResult(info)
+	if err != nil {
+		return "", err
+	}
+
+	return string(res), nil
+}
+
+type QueryAmbianceParam struct {
+	RestaurantID string `json:"restaurant_id"`
+	Occasion     string `json:"occasion"`
+}
+
+// AmbianceInfo 只有传入 occasion 时才有 suits_occasion, reasoning 说明判断的依据.
+type AmbianceInfo struct {
+	RestaurantID  string   `json:"restaurant_id"`
+	Tags          []string `json:"tags"`
+	NoiseLevel    int      `json:"noise_level"`
+	Occasion      string   `json:"occasion,omitempty"`
+	SuitsOccasion *bool    `json:"suits_occasion,omitempty"`
+	MatchedTags   []string `json:"matched_tags,omitempty"`
+	Reasoning     string   `json:"reasoning,omitempty"`
+}
+
+// occasionTags 把场合的关键词映射到适合它的氛围标签.
+var occasionTags = []struct {
+	keywords []string
+	tags     []string
+}{
+	{keywords: []string{"date", "romantic", "anniversary", "约会", "纪念日", "情侣"}, tags: []string{"romantic", "quiet"}},
+	{keywords: []string{"family", "kid", "child", "家庭", "家人", "孩子", "老人"}, tags: []string{"family-friendly"}},
+	{keywords: []string{"business", "client", "商务", "宴请", "客户"}, tags: []string{"upscale", "quiet"}},
+	{keywords: []string{"friend", "party", "group", "朋友", "聚会", "聚餐"}, tags: []string{"lively", "casual"}},
+}
+
+// QueryAmbiance 查询一家餐厅的氛围.
+func (ft *fakeService) QueryAmbiance(ctx context.Context, in *QueryAmbianceParam) (*AmbianceInfo, error) {
+	if err := ft.simulateLatency(ctx); err != nil {
+		return nil, err
+	}
+
+	rest, err := ft.repo.GetRestaurantByID(ctx, in.RestaurantID)
+	if err != nil {
+		return nil, err
+	}
+
+	out := &AmbianceInfo{RestaurantID: in.RestaurantID, Tags: []string{}}
+	if rest.Ambiance != nil {
+		out.Tags = rest.Ambiance.Tags
+		out.NoiseLevel = rest.Ambiance.NoiseLevel
+	}
+	if in.Occasion != "" {
+		matchOccasion(out, in.Occasion)
+	}
+	return out, nil
+}
+
+// matchOccasion 根据 occasion 填充 suits_occasion、matched_tags 和 reasoning.
+func matchOccasion(info *AmbianceInfo, occasion string) {
+	info.Occasion = occasion
+
+	wanted := relevantTags(occasion)
+	if len(wanted) == 0 {
+		info.Reasoning = fmt.Sprintf("occasion %q is not recognized, judge from the tags and noise level yourself", occasion)
+		return
+	}
+
+	for _, tag := range info.Tags {
+		for _, w := range wanted {
+			if tag == w {
+				info.MatchedTags = append(info.MatchedTags, tag)
+			}
+		}
+	}
+	suits := len(info.MatchedTags) > 0
+	info.SuitsOccasion = &suits
+	if suits {
+		info.Reasoning = fmt.Sprintf("occasion %q calls for %s, the restaurant is %s",
+			occasion, strings.Join(wanted, " or "), strings.Join(info.MatchedTags, ", "))
+	} else {
+		info.Reasoning = fmt.Sprintf("occasion %q calls for %s, none of which the restaurant has (noise level %d)",
+			occasion, strings.Join(wanted, " or "), info.NoiseLevel)
+	}
+}
+
+// relevantTags 返回 occasion 对应的氛围标签, 不识别时返回空.
+func relevantTags(occasion string) []string {
+	occasion = strings.ToLower(occasion)
+	var tags []string
+	seen := map[string]bool{}
+	for _, o := range occasionTags {
+		for _, kw := range o.keywords {
+			if !strings.Contains(occasion, kw) {
+				continue
+			}
+			for _, tag := range o.tags {
+				if !seen[tag] {
+					seen[tag] = true
+					tags = append(tags, tag)
+				}
+			}
+			break
+		}
+	}
+	return tags
+}
+
+func toAmbiance(item *restaurantAmbianceItem) *Ambiance {
+	if item == nil {
+		return nil
+	}
+	return &Ambiance{Tags: item.Tags, NoiseLevel: item.NoiseLevel}
+}
@@ -0,0 +1,52 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tools
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQueryAmbiance(t *testing.T) {
+	ctx := context.Background()
+
+	out, err := restService.QueryAmbiance(ctx, &QueryAmbianceParam{RestaurantID: "1003"})
+	assert.NoError(t, err)
+	assert.Contains(t, out.Tags, "romantic")
+	assert.Equal(t, 2, out.NoiseLevel)
+	assert.Nil(t, out.SuitsOccasion)
+
+	out, err = restService.QueryAmbiance(ctx, &QueryAmbianceParam{RestaurantID: "1003", Occasion: "Date night"})
+	assert.NoError(t, err)
+	assert.True(t, *out.SuitsOccasion)
+	assert.Equal(t, []string{"romantic", "quiet"}, out.MatchedTags)
+
+	out, err = restService.QueryAmbiance(ctx, &QueryAmbianceParam{RestaurantID: "2010", Occasion: "约会"})
+	assert.NoError(t, err)
+	assert.False(t, *out.SuitsOccasion)
+	assert.Contains(t, out.Reasoning, "noise level 5")
+
+	out, err = restService.QueryAmbiance(ctx, &QueryAmbianceParam{RestaurantID: "1001", Occasion: "随便吃点"})
+	assert.NoError(t, err)
+	assert.Nil(t, out.SuitsOccasion)
+	assert.Contains(t, out.Reasoning, "not recognized")
+
+	_, err = restService.QueryAmbiance(ctx, &QueryAmbianceParam{RestaurantID: "404"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,86 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tools
+
+import (
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// BackoffStrategy 决定第 attempt 次失败之后等待多久再重试, attempt 从 1 开始, 返回 0 表示立即重试.
+// 通过 RetryConfig.Backoff 注入到 NewRetryTool 中, 测试中可以换成固定的等待时间.
+type BackoffStrategy interface {
+	Next(attempt int) time.Duration
+}
+
+// ConstantBackoff 每次都等待相同的时长.
+type ConstantBackoff struct {
+	Delay time.Duration
+}
+
+func (b ConstantBackoff) Next(attempt int) time.Duration {
+	return b.Delay
+}
+
+// ExponentialBackoff 第 n 次失败后等待 Base * 2^(n-1), 不超过 Max. Max 为 0 表示不设上限.
+type ExponentialBackoff struct {
+	Base time.Duration
+	Max  time.Duration
+}
+
+func (b ExponentialBackoff) Next(attempt int) time.Duration {
+	return exponentialCeiling(b.Base, b.Max, attempt)
+}
+
+// JitteredBackoff 是 full jitter 的指数退避: 每次等待 [0, ExponentialBackoff 的时长) 之间的随机时长,
+// 避免大量调用同时失败后又同时重试. 可以被多个 goroutine 同时使用.
+type JitteredBackoff struct {
+	Base time.Duration
+	Max  time.Duration
+
+	mu   sync.Mutex // *rand.Rand 不是并发安全的
+	rand *rand.Rand
+}
+
+// NewJitteredBackoff 创建 JitteredBackoff, r 为空时使用当前时间做种子.
+func NewJitteredBackoff(base, limit time.Duration, r *rand.Rand) *JitteredBackoff {
+	if r == nil {
+		r = rand.New(rand.NewSource(time.Now().UnixNano()))
+	}
+	return &JitteredBackoff{Base: base, Max: limit, rand: r}
+}
+
+func (b *JitteredBackoff) Next(attempt int) time.Duration {
+	ceiling := exponentialCeiling(b.Base, b.Max, attempt)
+	if ceiling <= 0 {
+		return 0
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return time.Duration(b.rand.Int63n(int64(ceiling)))
+}
+
+// exponentialCeiling 返回 min(limit, base*2^(attempt-1)), limit 为 0 表示不设上限. 移位最多 32 次, 避免溢出.
+func exponentialCeiling(base, limit time.Duration, attempt int) time.Duration {
+	delay := base << min(max(attempt-1, 0), 32)
+	if limit > 0 && delay > limit {
+		return limit
+	}
+	return delay
+}
@@ -0,0 +1,92 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tools
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func sequence(b BackoffStrategy, n int) []time.Duration {
+	res := make([]time.Duration, 0, n)
+	for attempt := 1; attempt <= n; attempt++ {
+		res = append(res, b.Next(attempt))
+	}
+	return res
+}
+
+func TestConstantBackoff(t *testing.T) {
+	ms := time.Millisecond
+	assert.Equal(t, []time.Duration{5 * ms, 5 * ms, 5 * ms}, sequence(ConstantBackoff{Delay: 5 * ms}, 3))
+}
+
+func TestExponentialBackoff(t *testing.T) {
+	ms := time.Millisecond
+	assert.Equal(t, []time.Duration{ms, 2 * ms, 4 * ms, 8 * ms, 10 * ms, 10 * ms},
+		sequence(ExponentialBackoff{Base: ms, Max: 10 * ms}, 6))
+
+	// 没有上限时也不会因为移位溢出变成负数
+	assert.Equal(t, time.Duration(1)<<32, ExponentialBackoff{Base: 1}.Next(100))
+}
+
+func TestJitteredBackoff(t *testing.T) {
+	ms := time.Millisecond
+	seq := sequence(NewJitteredBackoff(ms, 4*ms, rand.New(rand.NewSource(1))), 5)
+	for i, d := range seq {
+		ceiling := min(ms<<i, 4*ms)
+		assert.GreaterOrEqual(t, d, time.Duration(0))
+		assert.Less(t, d, ceiling)
+	}
+
+	// 相同的种子得到相同的序列
+	assert.Equal(t, seq, sequence(NewJitteredBackoff(ms, 4*ms, rand.New(rand.NewSource(1))), 5))
+
+	assert.Equal(t, time.Duration(0), NewJitteredBackoff(0, 0, nil).Next(1))
+}
+
+func TestRetryToolBackoff(t *testing.T) {
+	state := &ToolExecutionState{}
+	flaky := &flakyTool{failures: 3, err: errors.New("temporary")}
+	rt := NewRetryTool(flaky, RetryConfig{MaxAttempts: 4, Backoff: ExponentialBackoff{Base: time.Millisecond}})
+
+	out, err := rt.InvokableRun(SetToolState(context.Background(), state), `{}`)
+	assert.NoError(t, err)
+	assert.Equal(t, "done", out)
+	assert.Equal(t, 4, state.Attempts)
+	assert.Equal(t, 7*time.Millisecond, state.RetryDelay)
+
+	// 等待 0 时立即重试, 而不是当作预算用完
+	state = &ToolExecutionState{}
+	flaky = &flakyTool{failures: 2, err: errors.New("temporary")}
+	out, err = NewRetryTool(flaky, RetryConfig{Backoff: ConstantBackoff{}}).InvokableRun(SetToolState(context.Background(), state), `{}`)
+	assert.NoError(t, err)
+	assert.Equal(t, "done", out)
+	assert.Equal(t, 3, state.Attempts)
+	assert.Zero(t, state.RetryDelay)
+
+	// 累计等待的预算用完后不再重试
+	flaky = &flakyTool{failures: 3, err: errors.New("temporary")}
+	_, err = NewRetryTool(flaky, RetryConfig{MaxAttempts: 4, MaxTotalDelay: time.Millisecond, Backoff: ConstantBackoff{Delay: time.Millisecond}}).
+		InvokableRun(context.Background(), `{}`)
+	assert.Error(t, err)
+	assert.Equal(t, 2, flaky.calls)
+}
@@ -0,0 +1,181 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/cloudwego/eino/components/tool"
+	"github.com/cloudwego/eino/schema"
+)
+
+func GetBatchRestaurantInfoTool() tool.InvokableTool {
+	return safeTool{
+		InvokableTool: NewCancellableTool(&ToolBatchRestaurantInfo{
+			backService: restService,
+		}),
+	}
+}
+
+// ToolBatchRestaurantInfo 一次查询多家餐厅的详情. 后端只支持按 id 逐个查询, tool 内部并发请求,
+// 用 semaphore 限制同时进行的请求数, 比模型在一轮里发起多个 tool call 更省 token, 也不会把后端打垮.
+type ToolBatchRestaurantInfo struct {
+	backService *fakeService // fake service
+}
+
+func (t *ToolBatchRestaurantInfo) Info(ctx context.Context) (*schema.ToolInfo, error) {
+	return &schema.ToolInfo{
+		Name: "batch_restaurant_info",
+		Desc: fmt.Sprintf("Get the details of several restaurants at once, at most %d ids per call. "+
+			"Returns a map from restaurant id to its details, or to an error when the id is not found", maxBatchRestaurantIDs),
+		ParamsOneOf: schema.NewParamsOneOfByParams(map[string]*schema.ParameterInfo{
+			"restaurant_ids": {
+				Type:     "array",
+				Desc:     "The ids of the restaurants",
+				ElemInfo: &schema.ParameterInfo{Type: "string"},
+				Required: true,
+			},
+		}),
+	}, nil
+}
+
+func (t *ToolBatchRestaurantInfo) InvokableRun(ctx context.Context, argumentsInJSON string, opts ...tool.Option) (string, error) {
+	// 解析参数
+	if err := checkRequired(ctx, t, argumentsInJSON); err != nil {
+		return "", err
+	}
+	p := &BatchRestaurantInfoParam{}
+	err := json.Unmarshal([]byte(argumentsInJSON), p)
+	if err != nil {
+		return "", err
+	}
+
+	// 请求后端服务
+	batch, err := t.backService.BatchRestaurantInfo(ctx, p)
+	if err != nil {
+		return "", err
+	}
+
+	// 序列化结果
+	res, err := marshalResult(batch)
+	if err != nil {
+		return "", err
+	}
+
+	return string(res), nil
+}
+
+type BatchRestaurantInfoParam struct {
+	RestaurantIDs []string `json:"restaurant_ids"`
+}
+
+// BatchRestaurantInfo 中每个 id 对应 restaurant 和 error 之一, 一个 id 查询失败不影响其他 id.
+type BatchRestaurantInfo struct {
+	Results map[string]BatchRestaurantResult `json:"results"`
+	Found   int                              `json:"found"`
+	Failed  int                              `json:"failed"`
+}
+
+type BatchRestaurantResult struct {
+	Restaurant *Restaurant `json:"restaurant,omitempty"`
+	Error      string      `json:"error,omitempty"`
+}
+
+const (
+	maxBatchRestaurantIDs = 20
+	// batchConcurrency 是同时请求后端的数量上限
+	batchConcurrency = 4
+)
+
+// BatchRestaurantInfo 并发查询 in.RestaurantIDs 的详情, 同时最多 batchConcurrency 个请求, 重复的 id 只查一次.
+// ctx 被取消时不再发起新的请求, 等已经发出的请求返回后整体返回 ctx 的错误, 不会留下还在运行的 goroutine.
+func (ft *fakeService) BatchRestaurantInfo(ctx context.Context, in *BatchRestaurantInfoParam) (*BatchRestaurantInfo, error) {
+	if len(in.RestaurantIDs) == 0 {
+		return nil, errors.New("restaurant_ids must not be empty")
+	}
+	if len(in.RestaurantIDs) > maxBatchRestaurantIDs {
+		return nil, fmt.Errorf("at most %d restaurant_ids per call, got %d", maxBatchRestaurantIDs, len(in.RestaurantIDs))
+	}
+
+	out := &BatchRestaurantInfo{Results: make(map[string]BatchRestaurantResult, len(in.RestaurantIDs))}
+	var (
+		mu  sync.Mutex
+		wg  sync.WaitGroup
+		sem = make(chan struct{}, batchConcurrency)
+	)
+	seen := map[string]bool{}
+	for _, id := range in.RestaurantIDs {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			break
+		}
+
+		wg.Add(1)
+		go func(id string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			rest, err := ft.restaurantInfo(ctx, id)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				out.Results[id] = BatchRestaurantResult{Error: err.Error()}
+				return
+			}
+			out.Results[id] = BatchRestaurantResult{Restaurant: rest}
+		}(id)
+	}
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	for _, r := range out.Results {
+		if r.Restaurant != nil {
+			out.Found++
+		} else {
+			out.Failed++
+		}
+	}
+	return out, nil
+}
+
+// restaurantInfo 查询一家餐厅的详情, 每次查询都有一次后端耗时.
+func (ft *fakeService) restaurantInfo(ctx context.Context, id string) (*Restaurant, error) {
+	if err := ft.simulateLatency(ctx); err != nil {
+		return nil, err
+	}
+
+	rest, err := ft.repo.GetRestaurantByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	r := toRestaurant(rest)
+	return &r, nil
+}
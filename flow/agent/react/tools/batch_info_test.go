@@ -0,0 +1,71 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tools
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBatchRestaurantInfo(t *testing.T) {
+	ctx := context.Background()
+	svc := &fakeService{repo: database}
+
+	batch, err := svc.BatchRestaurantInfo(ctx, &BatchRestaurantInfoParam{RestaurantIDs: []string{"1001", "404", "2001", "1001"}})
+	assert.NoError(t, err)
+	assert.Len(t, batch.Results, 3)
+	assert.Equal(t, 2, batch.Found)
+	assert.Equal(t, 1, batch.Failed)
+	assert.Equal(t, "1001", batch.Results["1001"].Restaurant.ID)
+	assert.Nil(t, batch.Results["404"].Restaurant)
+	assert.Contains(t, batch.Results["404"].Error, "not found")
+
+	_, err = svc.BatchRestaurantInfo(ctx, &BatchRestaurantInfoParam{})
+	assert.Error(t, err)
+	_, err = svc.BatchRestaurantInfo(ctx, &BatchRestaurantInfoParam{RestaurantIDs: make([]string, maxBatchRestaurantIDs+1)})
+	assert.ErrorContains(t, err, "at most")
+}
+
+func TestBatchRestaurantInfoConcurrency(t *testing.T) {
+	const latency = 30 * time.Millisecond
+	repo := &restaurantDatabase{restaurantByID: map[string]restaurantDataItem{}}
+	var ids []string
+	for i := 0; i < 2*batchConcurrency; i++ {
+		id := string(rune('a' + i))
+		repo.restaurantByID[id] = restaurantDataItem{ID: id}
+		ids = append(ids, id)
+	}
+	svc := &fakeService{repo: repo, latency: latency}
+
+	// 并发受 batchConcurrency 限制, 至少要两轮, 但远少于逐个查询的耗时
+	start := time.Now()
+	batch, err := svc.BatchRestaurantInfo(context.Background(), &BatchRestaurantInfoParam{RestaurantIDs: ids})
+	elapsed := time.Since(start)
+	assert.NoError(t, err)
+	assert.Equal(t, len(ids), batch.Found)
+	assert.GreaterOrEqual(t, elapsed, 2*latency)
+	assert.Less(t, elapsed, time.Duration(len(ids)-2)*latency)
+
+	// 取消后整体返回 ctx 的错误
+	ctx, cancel := context.WithTimeout(context.Background(), latency/3)
+	defer cancel()
+	_, err = svc.BatchRestaurantInfo(ctx, &BatchRestaurantInfoParam{RestaurantIDs: ids})
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
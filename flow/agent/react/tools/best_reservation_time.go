@@ -0,0 +1,210 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/cloudwego/eino/components/tool"
+	"github.com/cloudwego/eino/schema"
+)
+
+func GetBestReservationTimeTool() tool.InvokableTool {
+	return safeTool{
+		InvokableTool: NewCancellableTool(&ToolBestReservationTime{
+			backService: restService,
+		}),
+	}
+}
+
+// ToolBestReservationTime 在 query_busy_hours 的数据上做一步分析: 找出一天中最不拥挤的连续时段,
+// 并把整条曲线概括成最忙、最闲和平均值, 模型不需要自己比较十几个小时的数字.
+type ToolBestReservationTime struct {
+	backService *fakeService // fake service
+}
+
+func (t *ToolBestReservationTime) Info(ctx context.Context) (*schema.ToolInfo, error) {
+	return &schema.ToolInfo{
+		Name: "query_best_reservation_time",
+		Desc: "Recommend the least crowded time window to book a table at a restaurant on a date, " +
+			"with a summary of how busy the restaurant is during that day",
+		ParamsOneOf: schema.NewParamsOneOfByParams(map[string]*schema.ParameterInfo{
+			"restaurant_id": {
+				Type:     "string",
+				Desc:     "The id of one restaurant",
+				Required: true,
+			},
+			"date": {
+				Type: "string",
+				Desc: "The date in the format 2006-01-02, default today",
+			},
+		}),
+	}, nil
+}
+
+func (t *ToolBestReservationTime) InvokableRun(ctx context.Context, argumentsInJSON string, opts ...tool.Option) (string, error) {
+	// 解析参数
+	if err := checkRequired(ctx, t, argumentsInJSON); err != nil {
+		return "", err
+	}
+	p := &BestReservationTimeParam{}
+	err := json.Unmarshal([]byte(argumentsInJSON), p)
+	if err != nil {
+		return "", err
+	}
+
+	// 请求后端服务
+	best, err := t.backService.BestReservationTime(ctx, p)
+	if err != nil {
+		return "", err
+	}
+
+	// 序列化结果
+	res, err := marshalResult(best)
+	if err != nil {
+		return "", err
+	}
+
+	return string(res), nil
+}
+
+type BestReservationTimeParam struct {
+	RestaurantID string `json:"restaurant_id"`
+	Date         string `json:"date"`
+}
+
+// BestReservationTime 中 open 为 false 表示餐厅这一天休息, 没有 window 和 curve.
+// 查询今天时只考虑还没开始的整点, 今天剩下的时间不够一个时段时也没有 window, 原因在 message 中.
+type BestReservationTime struct {
+	RestaurantID string         `json:"restaurant_id"`
+	Date         string         `json:"date"`
+	Day          string         `json:"day"`
+	Open         bool           `json:"open"`
+	Window       *BusyWindow    `json:"window,omitempty"`
+	Curve        *BusynessCurve `json:"curve,omitempty"`
+	Message      string         `json:"message"`
+}
+
+// BusyWindow 是 [start, end) 的时段, busyness 是其中每个小时的平均繁忙程度.
+type BusyWindow struct {
+	Start    string `json:"start"` // 15:04
+	End      string `json:"end"`   // 15:04
+	Busyness int    `json:"busyness"`
+}
+
+// BusynessCurve 概括一整天 (busyFirstHour 到 busyLastHour) 的繁忙程度, 同样忙时取较早的小时.
+type BusynessCurve struct {
+	QuietestHour int `json:"quietest_hour"`
+	Quietest     int `json:"quietest"`
+	BusiestHour  int `json:"busiest_hour"`
+	Busiest      int `json:"busiest"`
+	Average      int `json:"average"`
+}
+
+// reservationWindowHours 是推荐时段的长度, 大致是一顿饭的时间.
+const reservationWindowHours = 2
+
+const reservationDateLayout = "2006-01-02"
+
+// BestReservationTime 用 busyness 算出 in.Date 每个小时的繁忙程度, 在营业时段内找平均最闲的连续 reservationWindowHours 小时,
+// 同样闲时取较早的时段. 日期以 fake 后端的 clock 为准, 不能是过去的日期.
+func (ft *fakeService) BestReservationTime(ctx context.Context, in *BestReservationTimeParam) (*BestReservationTime, error) {
+	if err := ft.simulateLatency(ctx); err != nil {
+		return nil, err
+	}
+
+	rest, err := ft.repo.GetRestaurantByID(ctx, in.RestaurantID)
+	if err != nil {
+		return nil, err
+	}
+	now := ft.now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	date := today
+	if s := strings.TrimSpace(in.Date); s != "" && s != "today" {
+		date, err = time.ParseInLocation(reservationDateLayout, s, now.Location())
+		if err != nil {
+			return nil, fmt.Errorf("invalid date %q, use the format 2006-01-02", in.Date)
+		}
+		if date.Before(today) {
+			return nil, fmt.Errorf("the date %s is in the past, today is %s", in.Date, today.Format(reservationDateLayout))
+		}
+	}
+
+	out := &BestReservationTime{
+		RestaurantID: rest.ID,
+		Date:         date.Format(reservationDateLayout),
+		Day:          strings.ToLower(date.Weekday().String()),
+	}
+	if slices.Contains(rest.ClosedOn, date.Weekday()) {
+		out.Message = fmt.Sprintf("the restaurant is closed on %s", out.Day)
+		return out, nil
+	}
+	out.Open = true
+
+	hourly := make([]int, 0, busyLastHour-busyFirstHour+1)
+	curve := &BusynessCurve{Quietest: math.MaxInt, Busiest: -1}
+	sum := 0
+	for hour := busyFirstHour; hour <= busyLastHour; hour++ {
+		busy := busyness(rest.ID, date.Weekday(), hour)
+		hourly = append(hourly, busy)
+		sum += busy
+		if busy < curve.Quietest {
+			curve.QuietestHour, curve.Quietest = hour, busy
+		}
+		if busy > curve.Busiest {
+			curve.BusiestHour, curve.Busiest = hour, busy
+		}
+	}
+	curve.Average = int(math.Round(float64(sum) / float64(len(hourly))))
+	out.Curve = curve
+
+	// 今天只能从下一个整点开始
+	first := busyFirstHour
+	if date.Equal(today) && now.Hour()+1 > first {
+		first = now.Hour() + 1
+	}
+	bestStart, bestSum := -1, math.MaxInt
+	for start := first; start+reservationWindowHours-1 <= busyLastHour; start++ {
+		s := 0
+		for h := start; h < start+reservationWindowHours; h++ {
+			s += hourly[h-busyFirstHour]
+		}
+		if s < bestSum {
+			bestStart, bestSum = start, s
+		}
+	}
+	if bestStart < 0 {
+		out.Message = fmt.Sprintf("no %d-hour window left today, the last one starts at %02d:00, try another date",
+			reservationWindowHours, busyLastHour-reservationWindowHours+1)
+		return out, nil
+	}
+
+	out.Window = &BusyWindow{
+		Start:    fmt.Sprintf("%02d:00", bestStart),
+		End:      fmt.Sprintf("%02d:00", bestStart+reservationWindowHours),
+		Busyness: int(math.Round(float64(bestSum) / reservationWindowHours)),
+	}
+	out.Message = fmt.Sprintf("%s-%s is the least crowded, about %d%% busy versus %d%% at the %02d:00 peak",
+		out.Window.Start, out.Window.End, out.Window.Busyness, curve.Busiest, curve.BusiestHour)
+	return out, nil
+}
@@ -0,0 +1,73 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tools
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBestReservationTime(t *testing.T) {
+	ctx := context.Background()
+	loc := time.FixedZone("CST", 8*3600)
+	// 2024-06-01 是星期六
+	at := func(hour int) *fakeService {
+		return &fakeService{repo: database, clock: FixedClock{T: time.Date(2024, 6, 1, hour, 30, 0, 0, loc)}}
+	}
+
+	best, err := at(9).BestReservationTime(ctx, &BestReservationTimeParam{RestaurantID: "1001"})
+	assert.NoError(t, err)
+	assert.Equal(t, "2024-06-01", best.Date)
+	assert.Equal(t, "saturday", best.Day)
+	assert.True(t, best.Open)
+	// 午餐和晚餐两个高峰之间最闲
+	assert.Equal(t, &BusyWindow{Start: "15:00", End: "17:00", Busyness: 15}, best.Window)
+	assert.Equal(t, 19, best.Curve.BusiestHour)
+	assert.Equal(t, 15, best.Curve.QuietestHour)
+	assert.Less(t, best.Curve.Quietest, best.Curve.Average)
+	assert.Contains(t, best.Message, "15:00-17:00 is the least crowded")
+
+	// 今天晚上只剩下还没开始的时段
+	best, err = at(20).BestReservationTime(ctx, &BestReservationTimeParam{RestaurantID: "1001", Date: "2024-06-01"})
+	assert.NoError(t, err)
+	assert.Equal(t, "21:00", best.Window.Start)
+	best, err = at(21).BestReservationTime(ctx, &BestReservationTimeParam{RestaurantID: "1001"})
+	assert.NoError(t, err)
+	assert.Nil(t, best.Window)
+	assert.NotNil(t, best.Curve)
+	assert.Contains(t, best.Message, "no 2-hour window left today")
+
+	// 1002 每周一休息
+	best, err = at(9).BestReservationTime(ctx, &BestReservationTimeParam{RestaurantID: "1002", Date: "2024-06-03"})
+	assert.NoError(t, err)
+	assert.False(t, best.Open)
+	assert.Nil(t, best.Window)
+	assert.Equal(t, "the restaurant is closed on monday", best.Message)
+	best, err = at(9).BestReservationTime(ctx, &BestReservationTimeParam{RestaurantID: "1002", Date: "2024-06-04"})
+	assert.NoError(t, err)
+	assert.True(t, best.Open)
+
+	_, err = at(9).BestReservationTime(ctx, &BestReservationTimeParam{RestaurantID: "1001", Date: "2024-05-31"})
+	assert.ErrorContains(t, err, "in the past")
+	_, err = at(9).BestReservationTime(ctx, &BestReservationTimeParam{RestaurantID: "1001", Date: "6/2"})
+	assert.ErrorContains(t, err, "invalid date")
+	_, err = at(9).BestReservationTime(ctx, &BestReservationTimeParam{RestaurantID: "404"})
+	assert.Error(t, err)
+}
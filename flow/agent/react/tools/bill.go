@@ -0,0 +1,127 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+
+	"github.com/cloudwego/eino/components/tool"
+	"github.com/cloudwego/eino/schema"
+)
+
+func GetComputeBillTool() tool.InvokableTool {
+	return safeTool{
+		InvokableTool: &ToolComputeBill{},
+	}
+}
+
+// ToolComputeBill 是一个纯计算的 tool, 不请求后端服务: 根据菜品价格和小费比例算出小计、小费和总价,
+// 这样模型不用自己做容易出错的算术.
+type ToolComputeBill struct{}
+
+func (t *ToolComputeBill) Info(ctx context.Context) (*schema.ToolInfo, error) {
+	return &schema.ToolInfo{
+		Name: "compute_bill",
+		Desc: "Compute the subtotal, tip and total of a bill from the prices of the ordered dishes",
+		ParamsOneOf: schema.NewParamsOneOfByParams(map[string]*schema.ParameterInfo{
+			"prices": {
+				Type:     schema.Array,
+				Desc:     "The prices of the ordered dishes in yuan, one item per dish",
+				ElemInfo: &schema.ParameterInfo{Type: schema.Number},
+				Required: true,
+			},
+			"tip_percent": {
+				Type: schema.Number,
+				Desc: "The tip as a percentage of the subtotal, from 0 to 100, default 0",
+			},
+		}),
+	}, nil
+}
+
+func (t *ToolComputeBill) InvokableRun(ctx context.Context, argumentsInJSON string, opts ...tool.Option) (string, error) {
+	// 解析参数
+	if err := checkRequired(ctx, t, argumentsInJSON); err != nil {
+		return "", err
+	}
+	p := &ComputeBillParam{}
+	err := json.Unmarshal([]byte(argumentsInJSON), p)
+	if err != nil {
+		return "", err
+	}
+
+	// 计算账单
+	bill, err := computeBill(p)
+	if err != nil {
+		return "", err
+	}
+
+	// 序列化结果
+	res, err := marshalResult(bill)
+	if err != nil {
+		return "", err
+	}
+
+	return string(res), nil
+}
+
+type ComputeBillParam struct {
+	Prices     []float64 `json:"prices"`
+	TipPercent float64   `json:"tip_percent"`
+}
+
+type BillLineItem struct {
+	Index int     `json:"index"`
+	Price float64 `json:"price"`
+}
+
+type Bill struct {
+	LineItems  []BillLineItem `json:"line_items"`
+	Subtotal   float64        `json:"subtotal"`
+	TipPercent float64        `json:"tip_percent"`
+	Tip        float64        `json:"tip"`
+	Total      float64        `json:"total"`
+}
+
+// computeBill 按分计算, 避免浮点数累加的误差, 小费四舍五入到分.
+func computeBill(in *ComputeBillParam) (*Bill, error) {
+	if len(in.Prices) == 0 {
+		return nil, fmt.Errorf("prices must contain at least one dish price")
+	}
+	if in.TipPercent < 0 || in.TipPercent > 100 {
+		return nil, fmt.Errorf("tip_percent must be between 0 and 100, got %v", in.TipPercent)
+	}
+
+	bill := &Bill{TipPercent: in.TipPercent}
+	var subtotalCents int64
+	for i, price := range in.Prices {
+		if price < 0 {
+			return nil, fmt.Errorf("prices[%d] must be non-negative, got %v", i, price)
+		}
+		cents := int64(math.Round(price * 100))
+		subtotalCents += cents
+		bill.LineItems = append(bill.LineItems, BillLineItem{Index: i, Price: float64(cents) / 100})
+	}
+
+	tipCents := int64(math.Round(float64(subtotalCents) * in.TipPercent / 100))
+	bill.Subtotal = float64(subtotalCents) / 100
+	bill.Tip = float64(tipCents) / 100
+	bill.Total = float64(subtotalCents+tipCents) / 100
+	return bill, nil
+}
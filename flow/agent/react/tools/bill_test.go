@@ -0,0 +1,46 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tools
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestComputeBill(t *testing.T) {
+	bill, err := computeBill(&ComputeBillParam{Prices: []float64{43, 40, 0.1, 0.2}, TipPercent: 15})
+	assert.NoError(t, err)
+	assert.Len(t, bill.LineItems, 4)
+	assert.Equal(t, 83.3, bill.Subtotal)
+	assert.Equal(t, 12.5, bill.Tip)
+	assert.Equal(t, 95.8, bill.Total)
+
+	bill, err = computeBill(&ComputeBillParam{Prices: []float64{20}})
+	assert.NoError(t, err)
+	assert.Equal(t, 0.0, bill.Tip)
+	assert.Equal(t, 20.0, bill.Total)
+
+	_, err = computeBill(&ComputeBillParam{Prices: []float64{20}, TipPercent: 120})
+	assert.ErrorContains(t, err, "tip_percent")
+
+	_, err = computeBill(&ComputeBillParam{Prices: []float64{20, -1}})
+	assert.ErrorContains(t, err, "prices[1]")
+
+	_, err = computeBill(&ComputeBillParam{})
+	assert.Error(t, err)
+}
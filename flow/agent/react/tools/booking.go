@@ -0,0 +1,184 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/cloudwego/eino/components/tool"
+	"github.com/cloudwego/eino/schema"
+)
+
+func GetBookTableTool() tool.InvokableTool {
+	return safeTool{
+		InvokableTool: NewCancellableTool(&ToolBookTable{
+			backService: restService,
+		}),
+	}
+}
+
+// ToolBookTable 预订餐厅的座位. 预订是写操作, 模型在超时后可能把同一次预订再发一遍,
+// 带上 idempotency_key 时相同的 key 只会预订一次, 之后的调用返回第一次的确认.
+type ToolBookTable struct {
+	backService *fakeService // fake service
+}
+
+func (t *ToolBookTable) Info(ctx context.Context) (*schema.ToolInfo, error) {
+	return &schema.ToolInfo{
+		Name: "book_table",
+		Desc: "Book a table in a restaurant. Returns a confirmation id. " +
+			"Pass an idempotency_key, e.g. a random string chosen once per booking, and reuse it when retrying the same booking, " +
+			"so a retry returns the original confirmation instead of booking twice",
+		ParamsOneOf: schema.NewParamsOneOfByParams(map[string]*schema.ParameterInfo{
+			"restaurant_id": {
+				Type:     "string",
+				Desc:     "The id of one restaurant",
+				Required: true,
+			},
+			"time": {
+				Type:     "string",
+				Desc:     "The time of the reservation, in the format 2006-01-02 15:04, or 15:04 for today",
+				Required: true,
+			},
+			"party_size": {
+				Type:     "integer",
+				Desc:     "How many people are coming",
+				Required: true,
+			},
+			"idempotency_key": {
+				Type: "string",
+				Desc: "A key identifying this booking, repeated calls with the same key do not book again",
+			},
+		}),
+	}, nil
+}
+
+func (t *ToolBookTable) InvokableRun(ctx context.Context, argumentsInJSON string, opts ...tool.Option) (string, error) {
+	// 解析参数
+	if err := checkRequired(ctx, t, argumentsInJSON); err != nil {
+		return "", err
+	}
+	p := &BookTableParam{}
+	err := json.Unmarshal([]byte(argumentsInJSON), p)
+	if err != nil {
+		return "", err
+	}
+
+	// 请求后端服务
+	booking, err := t.backService.BookTable(ctx, p)
+	if err != nil {
+		return "", err
+	}
+
+	// 序列化结果
+	res, err := marshalResult(booking)
+	if err != nil {
+		return "", err
+	}
+
+	return string(res), nil
+}
+
+type BookTableParam struct {
+	RestaurantID   string `json:"restaurant_id"`
+	Time           string `json:"time"`
+	PartySize      int    `json:"party_size"`
+	IdempotencyKey string `json:"idempotency_key"`
+}
+
+type Booking struct {
+	ConfirmationID string `json:"confirmation_id"`
+	RestaurantID   string `json:"restaurant_id"`
+	Time           string `json:"time"` // 2006-01-02 15:04
+	PartySize      int    `json:"party_size"`
+	// Replayed 为 true 表示 idempotency_key 之前已经用过, 这是第一次预订的确认, 没有新建预订
+	Replayed bool   `json:"replayed,omitempty"`
+	Message  string `json:"message"`
+}
+
+// errUnknownRestaurant 是预订数据中没有的餐厅时交给模型的错误, 提示它先查询餐厅, 而不是换一个猜出来的 id 重试.
+// id 由模型给出, 用 json.Marshal 生成, 其中的引号等字符不会破坏 JSON.
+func errUnknownRestaurant(restaurantID string) error {
+	res, _ := json.Marshal(map[string]string{
+		"error":   "restaurant not found",
+		"message": fmt.Sprintf("there is no restaurant with id %s, use query_restaurants to find a valid restaurant_id before booking", restaurantID),
+		"retry":   "false",
+	})
+	return errors.New(string(res))
+}
+
+var errIdempotencyKeyReused = errors.New(`{"error":"idempotency key reused","message":"the idempotency_key was already used for a different booking, use a new key for a new booking","retry":"false"}`)
+
+// BookTable 预订座位. in.IdempotencyKey 按用户隔离: 同一个用户用相同的 key 重复调用时返回第一次的预订,
+// 参数和第一次不同时返回错误, 而不是悄悄忽略新的参数.
+func (ft *fakeService) BookTable(ctx context.Context, in *BookTableParam) (*Booking, error) {
+	if err := ft.simulateLatency(ctx); err != nil {
+		return nil, err
+	}
+
+	if _, err := ft.repo.GetRestaurantByID(ctx, in.RestaurantID); err != nil {
+		if ft.repo.err != nil {
+			return nil, err
+		}
+		return nil, errUnknownRestaurant(in.RestaurantID)
+	}
+	if in.PartySize <= 0 || in.PartySize > maxPartySize {
+		return nil, fmt.Errorf("party_size must be between 1 and %d, got %d", maxPartySize, in.PartySize)
+	}
+	now := ft.now()
+	at, err := parseReservationTime(in.Time, now)
+	if err != nil {
+		return nil, err
+	}
+	if problem := reservationTimeProblem(at, now); problem != "" {
+		return nil, fmt.Errorf("cannot book %s: %s", at.Format(reservationTimeLayout), problem)
+	}
+
+	booking := Booking{RestaurantID: in.RestaurantID, Time: at.Format(reservationTimeLayout), PartySize: in.PartySize}
+
+	ft.mu.Lock()
+	defer ft.mu.Unlock()
+
+	key := strings.TrimSpace(in.IdempotencyKey)
+	if key != "" {
+		key = UserIDFrom(ctx) + "\x00" + key
+		if i, ok := ft.bookingIdx[key]; ok {
+			prev := ft.bookings[i]
+			if prev.RestaurantID != booking.RestaurantID || prev.Time != booking.Time || prev.PartySize != booking.PartySize {
+				return nil, errIdempotencyKeyReused
+			}
+			prev.Replayed = true
+			prev.Message = "this booking was already made with the same idempotency_key, no new booking was created"
+			return &prev, nil
+		}
+	}
+
+	booking.ConfirmationID = fmt.Sprintf("B%04d", len(ft.bookings)+1)
+	booking.Message = "booked"
+	ft.bookings = append(ft.bookings, booking)
+	if key != "" {
+		if ft.bookingIdx == nil {
+			ft.bookingIdx = make(map[string]int)
+		}
+		ft.bookingIdx[key] = len(ft.bookings) - 1
+	}
+	return &booking, nil
+}
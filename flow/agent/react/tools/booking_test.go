@@ -0,0 +1,98 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBookTable(t *testing.T) {
+	ctx := context.Background()
+	loc := time.FixedZone("CST", 8*3600)
+	svc := &fakeService{repo: restService.repo, clock: FixedClock{T: time.Date(2024, 6, 1, 15, 30, 0, 0, loc)}}
+
+	first, err := svc.BookTable(ctx, &BookTableParam{RestaurantID: "1001", Time: "19:00", PartySize: 4, IdempotencyKey: "k1"})
+	assert.NoError(t, err)
+	assert.Equal(t, "B0001", first.ConfirmationID)
+	assert.Equal(t, "2024-06-01 19:00", first.Time)
+	assert.False(t, first.Replayed)
+
+	// 相同的 key 返回第一次的确认, 不会重复预订
+	again, err := svc.BookTable(ctx, &BookTableParam{RestaurantID: "1001", Time: "2024-06-01 19:00", PartySize: 4, IdempotencyKey: "k1"})
+	assert.NoError(t, err)
+	assert.Equal(t, first.ConfirmationID, again.ConfirmationID)
+	assert.True(t, again.Replayed)
+	assert.Len(t, svc.bookings, 1)
+
+	// 相同的 key 用于不同的预订是错误
+	_, err = svc.BookTable(ctx, &BookTableParam{RestaurantID: "1001", Time: "19:00", PartySize: 6, IdempotencyKey: "k1"})
+	assert.ErrorIs(t, err, errIdempotencyKeyReused)
+
+	// key 按用户隔离
+	other, err := svc.BookTable(WithUserID(ctx, "u1001"), &BookTableParam{RestaurantID: "1001", Time: "19:00", PartySize: 4, IdempotencyKey: "k1"})
+	assert.NoError(t, err)
+	assert.Equal(t, "B0002", other.ConfirmationID)
+	assert.False(t, other.Replayed)
+
+	// 不带 key 时每次都是新的预订
+	for _, want := range []string{"B0003", "B0004"} {
+		b, err := svc.BookTable(ctx, &BookTableParam{RestaurantID: "1001", Time: "19:00", PartySize: 4})
+		assert.NoError(t, err)
+		assert.Equal(t, want, b.ConfirmationID)
+	}
+
+	_, err = svc.BookTable(ctx, &BookTableParam{RestaurantID: "1001", Time: "2024-06-02 09:00", PartySize: 4})
+	assert.ErrorContains(t, err, "opens at 10:00")
+	_, err = svc.BookTable(ctx, &BookTableParam{RestaurantID: "1001", Time: "19:00", PartySize: 0})
+	assert.Error(t, err)
+	_, err = svc.BookTable(ctx, &BookTableParam{RestaurantID: "9999", Time: "19:00", PartySize: 2})
+	assert.ErrorContains(t, err, `"error":"restaurant not found"`)
+	assert.Len(t, svc.bookings, 4)
+}
+
+func TestBookTableTool(t *testing.T) {
+	ctx := context.Background()
+	loc := time.FixedZone("CST", 8*3600)
+	svc := &fakeService{repo: restService.repo, clock: FixedClock{T: time.Date(2024, 6, 1, 15, 30, 0, 0, loc)}}
+	book := safeTool{InvokableTool: &ToolBookTable{backService: svc}}
+
+	out, err := book.InvokableRun(ctx, `{"restaurant_id": "1001", "time": "19:00", "party_size": 2}`)
+	assert.NoError(t, err)
+	b := &Booking{}
+	assert.NoError(t, json.Unmarshal([]byte(out), b))
+	assert.NotEmpty(t, b.ConfirmationID)
+	assert.Equal(t, "booked", b.Message)
+
+	// 不存在的餐厅: 模型看到的是可以解析的 JSON 错误, 提示不要重试
+	out, err = book.InvokableRun(ctx, `{"restaurant_id": "9999", "time": "19:00", "party_size": 2}`)
+	assert.NoError(t, err)
+	var res map[string]string
+	assert.NoError(t, json.Unmarshal([]byte(out), &res))
+	assert.Equal(t, "restaurant not found", res["error"])
+	assert.Contains(t, res["message"], "query_restaurants")
+	assert.Equal(t, "false", res["retry"])
+
+	out, err = book.InvokableRun(ctx, `{"restaurant_id": "1001", "time": "19:00", "party_size": 0}`)
+	assert.NoError(t, err)
+	assert.Contains(t, out, "party_size must be between 1")
+	assert.Len(t, svc.bookings, 1)
+}
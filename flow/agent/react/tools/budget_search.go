@@ -0,0 +1,169 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/cloudwego/eino/components/tool"
+	"github.com/cloudwego/eino/schema"
+)
+
+func GetBudgetSearchTool() tool.InvokableTool {
+	return safeTool{
+		InvokableTool: NewCancellableTool(&ToolBudgetSearch{
+			backService: restService,
+		}),
+	}
+}
+
+// ToolBudgetSearch 回答 "人均 50 以内有什么好吃的" 这类问题. 数据中没有人均价格这个字段,
+// 按 query_price_tier 同样的方法 (priceTier) 用菜品的平均价格推算.
+type ToolBudgetSearch struct {
+	backService *fakeService // fake service
+}
+
+func (t *ToolBudgetSearch) Info(ctx context.Context) (*schema.ToolInfo, error) {
+	return &schema.ToolInfo{
+		Name: "search_by_budget",
+		Desc: "Find restaurants in a location whose average dish price fits a budget per person, ranked by score. " +
+			"When nothing fits, returns the closest options above the budget instead",
+		ParamsOneOf: schema.NewParamsOneOfByParams(map[string]*schema.ParameterInfo{
+			"budget_per_person": {
+				Type:     "number",
+				Desc:     "The budget per person in CNY, e.g. 50 for 'around 50 yuan each'",
+				Required: true,
+			},
+			"location": {
+				Type:     "string",
+				Desc:     "The location of the restaurants",
+				Required: true,
+			},
+		}),
+	}, nil
+}
+
+func (t *ToolBudgetSearch) InvokableRun(ctx context.Context, argumentsInJSON string, opts ...tool.Option) (string, error) {
+	// 解析参数
+	if err := checkRequired(ctx, t, argumentsInJSON); err != nil {
+		return "", err
+	}
+	p := &BudgetSearchParam{}
+	err := json.Unmarshal([]byte(argumentsInJSON), p)
+	if err != nil {
+		return "", err
+	}
+
+	// 请求后端服务
+	found, err := t.backService.BudgetSearch(ctx, p)
+	if err != nil {
+		return "", err
+	}
+
+	// 序列化结果
+	res, err := marshalResult(found)
+	if err != nil {
+		return "", err
+	}
+
+	return string(res), nil
+}
+
+type BudgetSearchParam struct {
+	BudgetPerPerson float64 `json:"budget_per_person"`
+	Location        string  `json:"location"`
+}
+
+// BudgetSearch 没有符合预算的餐厅时 results 为空, closest 中是超出预算最少的几家.
+type BudgetSearch struct {
+	Location        string             `json:"location"`
+	BudgetPerPerson float64            `json:"budget_per_person"`
+	Results         []BudgetRestaurant `json:"results"`
+	Closest         []BudgetRestaurant `json:"closest,omitempty"`
+	Message         string             `json:"message,omitempty"`
+}
+
+type BudgetRestaurant struct {
+	ID           string  `json:"id"`
+	Name         string  `json:"name"`
+	Score        int     `json:"score"`
+	AveragePrice float64 `json:"average_price"`
+	// OverBudget 是平均价格超出预算的部分, 只有 closest 中的餐厅有
+	OverBudget float64 `json:"over_budget,omitempty"`
+}
+
+// budgetClosestOptions 是没有符合预算的餐厅时最多给出的备选数量.
+const budgetClosestOptions = 2
+
+// BudgetSearch 筛选出 in.Location 中平均价格不超过 in.BudgetPerPerson 的餐厅, 按评分从高到低排序, 同分时保持后端的顺序.
+// 没有菜品的餐厅算不出平均价格, 不参与筛选.
+func (ft *fakeService) BudgetSearch(ctx context.Context, in *BudgetSearchParam) (*BudgetSearch, error) {
+	if err := ft.simulateLatency(ctx); err != nil {
+		return nil, err
+	}
+
+	if in.BudgetPerPerson <= 0 {
+		return nil, fmt.Errorf("budget_per_person must be positive, got %v", in.BudgetPerPerson)
+	}
+	rests, err := ft.repo.GetRestaurantsByLocation(ctx, in.Location, math.MaxInt)
+	if err != nil {
+		return nil, err
+	}
+
+	out := &BudgetSearch{Location: in.Location, BudgetPerPerson: in.BudgetPerPerson, Results: []BudgetRestaurant{}}
+	var over []BudgetRestaurant
+	for _, rest := range rests {
+		if len(rest.Dishes) == 0 {
+			continue
+		}
+		dishes := make([]Dish, 0, len(rest.Dishes))
+		for _, d := range rest.Dishes {
+			dishes = append(dishes, toDish(d))
+		}
+		_, avg := priceTier(dishes)
+
+		item := BudgetRestaurant{ID: rest.ID, Name: rest.Name, Score: rest.Score, AveragePrice: avg}
+		if avg <= in.BudgetPerPerson {
+			out.Results = append(out.Results, item)
+			continue
+		}
+		item.OverBudget = roundYuan(avg - in.BudgetPerPerson)
+		over = append(over, item)
+	}
+	sort.SliceStable(out.Results, func(i, j int) bool { return out.Results[i].Score > out.Results[j].Score })
+
+	switch {
+	case len(out.Results) > 0:
+		return out, nil
+	case len(over) == 0:
+		out.Message = fmt.Sprintf("no restaurants with a known price found in %s", in.Location)
+		return out, nil
+	}
+
+	sort.SliceStable(over, func(i, j int) bool { return over[i].AveragePrice < over[j].AveragePrice })
+	if len(over) > budgetClosestOptions {
+		over = over[:budgetClosestOptions]
+	}
+	out.Closest = over
+	out.Message = fmt.Sprintf("no restaurants in %s average %.0f yuan or less per person, the closest is %s at %.1f",
+		in.Location, in.BudgetPerPerson, over[0].Name, over[0].AveragePrice)
+	return out, nil
+}
@@ -0,0 +1,57 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tools
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBudgetSearch(t *testing.T) {
+	ctx := context.Background()
+	svc := &fakeService{repo: database}
+
+	// 北京: 云边小馆 18.3, 聚福轩食府 39.5, 花影食舍 32.7; 按评分排序
+	found, err := svc.BudgetSearch(ctx, &BudgetSearchParam{BudgetPerPerson: 35, Location: "北京"})
+	assert.NoError(t, err)
+	assert.Equal(t, []BudgetRestaurant{
+		{ID: "1003", Name: "花影食舍", Score: 10, AveragePrice: 32.7},
+		{ID: "1001", Name: "云边小馆", Score: 3, AveragePrice: 18.3},
+	}, found.Results)
+	assert.Empty(t, found.Closest)
+
+	// 上海没有人均 50 以内的餐厅, 给出超出最少的两家
+	found, err = svc.BudgetSearch(ctx, &BudgetSearchParam{BudgetPerPerson: 50, Location: "上海"})
+	assert.NoError(t, err)
+	assert.Empty(t, found.Results)
+	if assert.Len(t, found.Closest, 2) {
+		assert.Equal(t, "2002", found.Closest[0].ID)
+		assert.Equal(t, 34.0, found.Closest[0].OverBudget)
+		assert.Equal(t, "2001", found.Closest[1].ID)
+	}
+	assert.Contains(t, found.Message, "the closest is")
+
+	found, err = svc.BudgetSearch(ctx, &BudgetSearchParam{BudgetPerPerson: 50, Location: "火星"})
+	assert.NoError(t, err)
+	assert.Empty(t, found.Results)
+	assert.Contains(t, found.Message, "no restaurants with a known price")
+
+	_, err = svc.BudgetSearch(ctx, &BudgetSearchParam{BudgetPerPerson: 0, Location: "北京"})
+	assert.ErrorContains(t, err, "budget_per_person")
+}
@@ -0,0 +1,173 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cloudwego/eino/components/tool"
+	"github.com/cloudwego/eino/schema"
+)
+
+func GetBusyHoursTool() tool.InvokableTool {
+	return safeTool{
+		InvokableTool: NewCancellableTool(&ToolBusyHours{
+			backService: restService,
+		}),
+	}
+}
+
+// ToolBusyHours 返回餐厅某一天每个小时的繁忙程度, 结果是一个按小时索引的数值结构,
+// 需要模型自己总结 (比如 "晚上 7 点最忙").
+type ToolBusyHours struct {
+	backService *fakeService // fake service
+}
+
+func (t *ToolBusyHours) Info(ctx context.Context) (*schema.ToolInfo, error) {
+	return &schema.ToolInfo{
+		Name: "query_busy_hours",
+		Desc: "Query how busy a restaurant is at each hour of a day, as percentages from 0 (empty) to 100 (full)",
+		ParamsOneOf: schema.NewParamsOneOfByParams(map[string]*schema.ParameterInfo{
+			"restaurant_id": {
+				Type:     "string",
+				Desc:     "The id of one restaurant",
+				Required: true,
+			},
+			"day": {
+				Type: "string",
+				Desc: "The day of week in English, e.g. monday or saturday, default today",
+				Enum: []string{"monday", "tuesday", "wednesday", "thursday", "friday", "saturday", "sunday", "today"},
+			},
+		}),
+	}, nil
+}
+
+func (t *ToolBusyHours) InvokableRun(ctx context.Context, argumentsInJSON string, opts ...tool.Option) (string, error) {
+	// 解析参数
+	if err := checkRequired(ctx, t, argumentsInJSON); err != nil {
+		return "", err
+	}
+	p := &BusyHoursParam{}
+	err := json.Unmarshal([]byte(argumentsInJSON), p)
+	if err != nil {
+		return "", err
+	}
+
+	// 请求后端服务
+	busy, err := t.backService.BusyHours(ctx, p)
+	if err != nil {
+		return "", err
+	}
+
+	// 序列化结果
+	res, err := marshalResult(busy)
+	if err != nil {
+		return "", err
+	}
+
+	return string(res), nil
+}
+
+type BusyHoursParam struct {
+	RestaurantID string `json:"restaurant_id"`
+	Day          string `json:"day"`
+}
+
+// BusyHours 的 hourly 按小时 (10 - 22, 24 小时制) 索引, 值是繁忙程度的百分比.
+type BusyHours struct {
+	RestaurantID string         `json:"restaurant_id"`
+	Day          string         `json:"day"`
+	Hourly       map[string]int `json:"hourly"`
+	BusiestHour  int            `json:"busiest_hour"`
+}
+
+// 统计的营业时段
+const (
+	busyFirstHour = 10
+	busyLastHour  = 22
+)
+
+// BusyHours 查询一家餐厅某一天的繁忙程度, 相同的餐厅和日期总是得到相同的结果.
+func (ft *fakeService) BusyHours(ctx context.Context, in *BusyHoursParam) (*BusyHours, error) {
+	if err := ft.simulateLatency(ctx); err != nil {
+		return nil, err
+	}
+
+	day, err := parseWeekday(in.Day, ft.now())
+	if err != nil {
+		return nil, err
+	}
+	if _, err := ft.repo.GetRestaurantByID(ctx, in.RestaurantID); err != nil {
+		return nil, err
+	}
+
+	out := &BusyHours{
+		RestaurantID: in.RestaurantID,
+		Day:          strings.ToLower(day.String()),
+		Hourly:       make(map[string]int, busyLastHour-busyFirstHour+1),
+	}
+	best := -1
+	for hour := busyFirstHour; hour <= busyLastHour; hour++ {
+		busy := busyness(in.RestaurantID, day, hour)
+		out.Hourly[strconv.Itoa(hour)] = busy
+		if busy > best {
+			best, out.BusiestHour = busy, hour
+		}
+	}
+	return out, nil
+}
+
+// parseWeekday 解析英文的星期, 为空或 today 时使用 now 所在的那一天.
+func parseWeekday(day string, now time.Time) (time.Weekday, error) {
+	day = strings.ToLower(strings.TrimSpace(day))
+	if day == "" || day == "today" {
+		return now.Weekday(), nil
+	}
+	for d := time.Sunday; d <= time.Saturday; d++ {
+		if strings.ToLower(d.String()) == day {
+			return d, nil
+		}
+	}
+	return 0, fmt.Errorf("invalid day %q, use an English day of week like monday, or today", day)
+}
+
+// busyness 是午餐 (12 点) 和晚餐 (19 点) 两个高峰叠加的曲线, 周末整体更忙,
+// 再由餐厅 id 决定一个固定的偏移, 让不同餐厅的结果有所区别.
+func busyness(restaurantID string, day time.Weekday, hour int) int {
+	peak := func(center, width float64) float64 {
+		d := (float64(hour) - center) / width
+		return math.Exp(-d * d)
+	}
+	v := 35*peak(12, 1.2) + 60*peak(19, 1.5) + 10
+
+	if day == time.Saturday || day == time.Sunday {
+		v *= 1.3
+	}
+
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(restaurantID))
+	v *= 0.8 + float64(h.Sum32()%30)/100
+
+	return int(math.Min(100, math.Round(v)))
+}
@@ -0,0 +1,55 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tools
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBusyHours(t *testing.T) {
+	ctx := context.Background()
+	// 2024-06-01 是周六
+	svc := &fakeService{repo: database, clock: FixedClock{T: time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)}}
+
+	out, err := svc.BusyHours(ctx, &BusyHoursParam{RestaurantID: "1001", Day: "Monday"})
+	assert.NoError(t, err)
+	assert.Equal(t, "monday", out.Day)
+	assert.Len(t, out.Hourly, busyLastHour-busyFirstHour+1)
+	assert.Equal(t, 19, out.BusiestHour)
+	for _, busy := range out.Hourly {
+		assert.GreaterOrEqual(t, busy, 0)
+		assert.LessOrEqual(t, busy, 100)
+	}
+
+	again, err := svc.BusyHours(ctx, &BusyHoursParam{RestaurantID: "1001", Day: "monday"})
+	assert.NoError(t, err)
+	assert.Equal(t, out.Hourly, again.Hourly)
+
+	today, err := svc.BusyHours(ctx, &BusyHoursParam{RestaurantID: "1001"})
+	assert.NoError(t, err)
+	assert.Equal(t, "saturday", today.Day)
+	assert.Greater(t, today.Hourly["19"], out.Hourly["19"])
+
+	_, err = svc.BusyHours(ctx, &BusyHoursParam{RestaurantID: "1001", Day: "someday"})
+	assert.ErrorContains(t, err, "invalid day")
+	_, err = svc.BusyHours(ctx, &BusyHoursParam{RestaurantID: "404", Day: "monday"})
+	assert.Error(t, err)
+}
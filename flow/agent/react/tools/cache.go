@@ -0,0 +1,201 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/cloudwego/eino/components/tool"
+)
+
+// ResultCache 按 tool 名称 + 参数缓存 tool 结果, 每条结果在 ttl 之后过期.
+// 指定 path 时缓存会持久化到这个 JSON 文件, 多次运行 demo 可以复用结果, 避免后端随机失败带来的差异.
+type ResultCache struct {
+	path string
+	ttl  time.Duration
+	now  func() time.Time
+
+	mu      sync.Mutex // 保护 entries、统计和文件的读写
+	entries map[string]cacheEntry
+	stats   CacheStats
+}
+
+// CacheStats 是缓存从创建以来的命中统计.
+type CacheStats struct {
+	Hits   int
+	Misses int
+}
+
+// HitRate 返回命中率, 没有任何查询时为 0.
+func (s CacheStats) HitRate() float64 {
+	if s.Hits+s.Misses == 0 {
+		return 0
+	}
+	return float64(s.Hits) / float64(s.Hits+s.Misses)
+}
+
+type cacheEntry struct {
+	Result    string    `json:"result"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// NewResultCache 创建缓存, path 为空时只缓存在内存中. path 指向的文件不存在时会在第一次写入时创建.
+func NewResultCache(path string, ttl time.Duration) (*ResultCache, error) {
+	c := &ResultCache{
+		path:    path,
+		ttl:     ttl,
+		now:     time.Now,
+		entries: map[string]cacheEntry{},
+	}
+	if path == "" {
+		return c, nil
+	}
+
+	b, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return c, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read cache file: %w", err)
+	}
+	if err := json.Unmarshal(b, &c.entries); err != nil {
+		return nil, fmt.Errorf("failed to parse cache file %s: %w", path, err)
+	}
+	return c, nil
+}
+
+func (c *ResultCache) get(key string) (string, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e, ok := c.entries[key]
+	if ok && !c.now().Before(e.ExpiresAt) {
+		delete(c.entries, key)
+		ok = false
+	}
+	if !ok {
+		c.stats.Misses++
+		return "", false
+	}
+	c.stats.Hits++
+	return e.Result, true
+}
+
+// Stats 返回当前的命中统计.
+func (c *ResultCache) Stats() CacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.stats
+}
+
+func (c *ResultCache) put(key, result string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entries[key] = cacheEntry{Result: result, ExpiresAt: c.now().Add(c.ttl)}
+	if c.path == "" {
+		return nil
+	}
+	return c.save()
+}
+
+// save 先写临时文件再 rename, 避免进程中途退出留下半个文件. 调用方需要持有 mu.
+func (c *ResultCache) save() error {
+	now := c.now()
+	for k, e := range c.entries {
+		if !now.Before(e.ExpiresAt) {
+			delete(c.entries, k)
+		}
+	}
+
+	b, err := json.MarshalIndent(c.entries, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(c.path), filepath.Base(c.path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(b); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), c.path)
+}
+
+// cachedTool 命中缓存时直接返回缓存的结果, 不请求后端.
+// 只缓存成功的调用: 失败 (包括模拟的随机失败和被拒绝的参数) 都不缓存, 下次调用仍然会请求后端.
+// 它应该包装在 safeTool 之外, 通过 ToolExecutionState 判断调用是否成功.
+type cachedTool struct {
+	tool.InvokableTool
+	cache *ResultCache
+}
+
+func NewCachedTool(t tool.InvokableTool, cache *ResultCache) tool.InvokableTool {
+	return &cachedTool{InvokableTool: t, cache: cache}
+}
+
+func (c *cachedTool) InvokableRun(ctx context.Context, argumentsInJSON string, opts ...tool.Option) (string, error) {
+	info, err := c.InvokableTool.Info(ctx)
+	if err != nil {
+		return "", err
+	}
+	key := cacheKey(info.Name, argumentsInJSON)
+
+	state := GetToolState(ctx)
+	if state == nil {
+		// 没有 callback 创建 state 时自己创建一个, 否则无法知道调用是否成功
+		state = &ToolExecutionState{}
+		ctx = SetToolState(ctx, state)
+	}
+
+	if out, ok := c.cache.get(key); ok {
+		state.Success, state.ErrMsg = true, ""
+		return out, nil
+	}
+
+	out, err := c.InvokableTool.InvokableRun(ctx, argumentsInJSON, opts...)
+	if err != nil || !state.Success {
+		return out, err
+	}
+	// 写文件失败不影响本次的结果, 内存中的缓存仍然有效
+	_ = c.cache.put(key, out)
+	return out, nil
+}
+
+// cacheKey 把参数重新序列化一遍, 这样字段顺序和空白不同但内容相同的参数会命中同一条缓存.
+func cacheKey(toolName, argumentsInJSON string) string {
+	var v any
+	if err := json.Unmarshal([]byte(argumentsInJSON), &v); err == nil {
+		if b, err := json.Marshal(v); err == nil {
+			argumentsInJSON = string(b)
+		}
+	}
+	return toolName + " " + argumentsInJSON
+}
@@ -0,0 +1,73 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tools
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCachedTool(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "cache.json")
+
+	cache, err := NewResultCache(path, time.Minute)
+	assert.NoError(t, err)
+	flaky := &flakyTool{failures: 1, err: errors.New("temporary")}
+	cached := NewCachedTool(safeTool{InvokableTool: flaky}, cache)
+
+	// 失败的结果不缓存
+	out, err := cached.InvokableRun(ctx, `{"a": 1}`)
+	assert.NoError(t, err)
+	assert.Equal(t, "temporary", out)
+
+	out, err = cached.InvokableRun(ctx, `{"a": 1}`)
+	assert.NoError(t, err)
+	assert.Equal(t, "done", out)
+	assert.Equal(t, 2, flaky.calls)
+
+	// 内容相同的参数命中缓存
+	state := &ToolExecutionState{}
+	out, err = cached.InvokableRun(SetToolState(ctx, state), `{ "a":1 }`)
+	assert.NoError(t, err)
+	assert.Equal(t, "done", out)
+	assert.True(t, state.Success)
+	assert.Equal(t, 2, flaky.calls)
+
+	// 重新加载文件后仍然命中
+	reloaded, err := NewResultCache(path, time.Minute)
+	assert.NoError(t, err)
+	_, err = NewCachedTool(safeTool{InvokableTool: flaky}, reloaded).InvokableRun(ctx, `{"a": 1}`)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, flaky.calls)
+
+	// 过期后重新请求
+	reloaded.now = func() time.Time { return time.Now().Add(2 * time.Minute) }
+	_, err = NewCachedTool(safeTool{InvokableTool: flaky}, reloaded).InvokableRun(ctx, `{"a": 1}`)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, flaky.calls)
+
+	assert.Equal(t, CacheStats{Hits: 1, Misses: 2}, cache.Stats())
+	assert.Equal(t, CacheStats{Hits: 1, Misses: 1}, reloaded.Stats())
+	assert.InDelta(t, 1.0/3, cache.Stats().HitRate(), 1e-9)
+	assert.Zero(t, CacheStats{}.HitRate())
+}
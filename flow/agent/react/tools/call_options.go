@@ -0,0 +1,50 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tools
+
+import (
+	"github.com/cloudwego/eino/components/tool"
+)
+
+// queryOptions 是 query_restaurants 和 query_dishes 的调用选项, 由程序调用时通过 tool.Option 传入, 模型看不到.
+type queryOptions struct {
+	maxResults int
+	minScore   int
+}
+
+// WithMaxResults 覆盖这次调用的 topn, 不传或 n <= 0 时仍用模型给出的 topn.
+func WithMaxResults(n int) tool.Option {
+	return tool.WrapImplSpecificOptFn(func(o *queryOptions) {
+		o.maxResults = n
+	})
+}
+
+// WithMinScore 在序列化之前筛掉评分低于 score 的结果. 筛选和其他条件一样在取 topn 之前进行, 会尽量凑满 topn 条.
+func WithMinScore(score int) tool.Option {
+	return tool.WrapImplSpecificOptFn(func(o *queryOptions) {
+		o.minScore = score
+	})
+}
+
+// applyQueryOptions 把调用选项合并到参数中, 返回 WithMinScore 设置的最低评分.
+func applyQueryOptions(topn *int, opts ...tool.Option) (minScore int) {
+	o := tool.GetImplSpecificOptions(&queryOptions{}, opts...)
+	if o.maxResults > 0 {
+		*topn = o.maxResults
+	}
+	return o.minScore
+}
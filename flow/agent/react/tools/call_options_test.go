@@ -0,0 +1,108 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/cloudwego/eino/components/tool"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQueryDishesCallOptions(t *testing.T) {
+	ctx := context.Background()
+	dishes := &ToolQueryDishes{backService: restService}
+
+	query := func(args string, opts ...tool.Option) []Dish {
+		out, err := dishes.InvokableRun(ctx, args, opts...)
+		assert.NoError(t, err)
+		var res []Dish
+		assert.NoError(t, json.Unmarshal([]byte(out), &res))
+		return res
+	}
+	scores := func(res []Dish) []int {
+		var s []int
+		for _, d := range res {
+			s = append(s, d.Score)
+		}
+		return s
+	}
+
+	// 不传选项时用模型给出的 topn
+	assert.Len(t, query(`{"restaurant_id": "1001", "topn": 2}`), 2)
+	assert.Len(t, query(`{"restaurant_id": "1001"}`), 5)
+
+	// WithMaxResults 覆盖 topn
+	assert.Len(t, query(`{"restaurant_id": "1001", "topn": 2}`, WithMaxResults(4)), 4)
+	assert.Len(t, query(`{"restaurant_id": "1001"}`, WithMaxResults(1)), 1)
+	assert.Len(t, query(`{"restaurant_id": "1001", "topn": 2}`, WithMaxResults(0)), 2)
+
+	// 按评分从高到低, WithMinScore 筛掉评分低的菜
+	assert.Equal(t, []int{9, 9, 8, 8, 5}, scores(query(`{"restaurant_id": "1001"}`)))
+	assert.Equal(t, []int{9, 9, 8, 8}, scores(query(`{"restaurant_id": "1001"}`, WithMinScore(8))))
+	assert.Equal(t, []int{9, 9, 8}, scores(query(`{"restaurant_id": "1001", "topn": 3}`, WithMinScore(8))))
+	assert.Equal(t, []int{9, 9}, scores(query(`{"restaurant_id": "1001"}`, WithMinScore(8), WithMaxResults(2))))
+
+	// 分页查询同样筛选
+	out, err := dishes.InvokableRun(ctx, `{"restaurant_id": "1001", "page": 1, "page_size": 10}`, WithMinScore(9))
+	assert.NoError(t, err)
+	page := &DishPage{}
+	assert.NoError(t, json.Unmarshal([]byte(out), page))
+	assert.Equal(t, []int{9, 9}, scores(page.Results))
+
+	// 全部筛掉时返回空结果
+	out, err = dishes.InvokableRun(ctx, `{"restaurant_id": "1001"}`, WithMinScore(100))
+	assert.NoError(t, err)
+	assert.JSONEq(t, emptyResult("dishes"), out)
+}
+
+func TestQueryRestaurantsCallOptions(t *testing.T) {
+	ctx := context.Background()
+	restaurants := &ToolQueryRestaurants{backService: restService}
+
+	query := func(args string, opts ...tool.Option) []Restaurant {
+		out, err := restaurants.InvokableRun(ctx, args, opts...)
+		assert.NoError(t, err)
+		var res []Restaurant
+		assert.NoError(t, json.Unmarshal([]byte(out), &res))
+		return res
+	}
+
+	ids := func(res []Restaurant) []string {
+		var s []string
+		for _, r := range res {
+			s = append(s, r.ID)
+		}
+		return s
+	}
+
+	// 北京有 1001 (3 分)、1002 (5 分) 和 1003 (10 分) 三家
+	assert.Equal(t, []string{"1003", "1002", "1001"}, ids(query(`{"location": "北京"}`)))
+	assert.Equal(t, []string{"1003"}, ids(query(`{"location": "北京"}`, WithMaxResults(1))))
+	assert.Equal(t, []string{"1003", "1002"}, ids(query(`{"location": "北京"}`, WithMinScore(4))))
+	assert.Equal(t, []string{"1003"}, ids(query(`{"location": "北京", "topn": 1}`, WithMinScore(4))))
+	assert.Equal(t, []string{"1003", "1002"}, ids(query(`{"location": "北京", "topn": 2}`, WithMinScore(4))))
+
+	out, err := restaurants.InvokableRun(ctx, `{"location": "北京"}`, WithMinScore(100))
+	assert.NoError(t, err)
+	assert.JSONEq(t, emptyResult("restaurants"), out)
+
+	// 其他 tool 的选项不影响结果
+	assert.Len(t, query(`{"location": "北京", "topn": 1}`, tool.WrapImplSpecificOptFn(func(*struct{}) {})), 1)
+}
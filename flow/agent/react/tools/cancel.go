@@ -0,0 +1,64 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tools
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cloudwego/eino/components/tool"
+)
+
+// cancellableTool makes a tool return as soon as its context is cancelled, even if the wrapped tool
+// ignores ctx and keeps running. The abandoned call finishes in the background and its result is dropped.
+// Wrap it with safeTool so the cancellation reaches the model as a message instead of an error.
+type cancellableTool struct {
+	tool.InvokableTool
+}
+
+func NewCancellableTool(t tool.InvokableTool) tool.InvokableTool {
+	return &cancellableTool{InvokableTool: withChaos(t)}
+}
+
+type toolResult struct {
+	out string
+	err error
+}
+
+func (c *cancellableTool) InvokableRun(ctx context.Context, argumentsInJSON string, opts ...tool.Option) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", cancelledError(err)
+	}
+
+	// buffered, so the goroutine can always exit even after we stop waiting for it
+	done := make(chan toolResult, 1)
+	go func() {
+		out, err := c.InvokableTool.InvokableRun(ctx, argumentsInJSON, opts...)
+		done <- toolResult{out: out, err: err}
+	}()
+
+	select {
+	case <-ctx.Done():
+		return "", cancelledError(ctx.Err())
+	case res := <-done:
+		return res.out, res.err
+	}
+}
+
+func cancelledError(err error) error {
+	return fmt.Errorf(`{"error":"cancelled","message":"the tool call was cancelled before it completed: %v","retry":"false"}`, err)
+}
@@ -0,0 +1,72 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tools
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/cloudwego/eino/components/tool"
+	"github.com/cloudwego/eino/schema"
+	"github.com/stretchr/testify/assert"
+)
+
+// stubbornTool sleeps without looking at ctx.
+type stubbornTool struct {
+	sleep time.Duration
+}
+
+func (s *stubbornTool) Info(ctx context.Context) (*schema.ToolInfo, error) {
+	return &schema.ToolInfo{Name: "stubborn"}, nil
+}
+
+func (s *stubbornTool) InvokableRun(ctx context.Context, argumentsInJSON string, opts ...tool.Option) (string, error) {
+	time.Sleep(s.sleep)
+	return "done", nil
+}
+
+func TestCancellableTool(t *testing.T) {
+	wrapped := safeTool{InvokableTool: NewCancellableTool(&stubbornTool{sleep: 5 * time.Second})}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	state := &ToolExecutionState{}
+	ctx = SetToolState(ctx, state)
+	time.AfterFunc(50*time.Millisecond, cancel)
+
+	start := time.Now()
+	out, err := wrapped.InvokableRun(ctx, `{}`)
+	assert.NoError(t, err)
+	assert.Less(t, time.Since(start), time.Second)
+	assert.Contains(t, out, "cancelled")
+	assert.False(t, state.Success)
+
+	out, err = NewCancellableTool(&stubbornTool{}).InvokableRun(context.Background(), `{}`)
+	assert.NoError(t, err)
+	assert.Equal(t, "done", out)
+}
+
+func TestFakeServiceRespectsContext(t *testing.T) {
+	svc := &fakeService{repo: database, latency: 5 * time.Second}
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err := svc.QueryDishes(ctx, &QueryDishesParam{RestaurantID: "1001", Topn: 1})
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Less(t, time.Since(start), time.Second)
+}
@@ -0,0 +1,164 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+
+	"github.com/cloudwego/eino/components/tool"
+	"github.com/cloudwego/eino/schema"
+)
+
+func GetCarbonFootprintTool() tool.InvokableTool {
+	return safeTool{
+		InvokableTool: NewCancellableTool(&ToolCarbonFootprint{
+			backService: restService,
+		}),
+	}
+}
+
+// ToolCarbonFootprint 估算一顿饭的碳排放, 和 ToolMealNutrition 一样按菜汇总, 只是换了一个维度,
+// 适合在意环保的用户比较 "牛肉还是蔬菜" 这类选择.
+type ToolCarbonFootprint struct {
+	backService *fakeService // fake service
+}
+
+func (t *ToolCarbonFootprint) Info(ctx context.Context) (*schema.ToolInfo, error) {
+	return &schema.ToolInfo{
+		Name: "query_carbon_footprint",
+		Desc: "Estimate the carbon footprint (kg CO2e) of a list of dishes ordered together in one restaurant. " +
+			"Returns the estimate of every dish and the total, each rated low, medium or high; dishes not on the menu are listed as skipped",
+		ParamsOneOf: schema.NewParamsOneOfByParams(map[string]*schema.ParameterInfo{
+			"restaurant_id": {
+				Type:     "string",
+				Desc:     "The id of one restaurant",
+				Required: true,
+			},
+			"dish_names": {
+				Type:     "array",
+				Desc:     "The names of the dishes in the meal",
+				ElemInfo: &schema.ParameterInfo{Type: "string"},
+				Required: true,
+			},
+		}),
+	}, nil
+}
+
+func (t *ToolCarbonFootprint) InvokableRun(ctx context.Context, argumentsInJSON string, opts ...tool.Option) (string, error) {
+	// 解析参数
+	if err := checkRequired(ctx, t, argumentsInJSON); err != nil {
+		return "", err
+	}
+	p := &CarbonFootprintParam{}
+	err := json.Unmarshal([]byte(argumentsInJSON), p)
+	if err != nil {
+		return "", err
+	}
+
+	// 请求后端服务
+	footprint, err := t.backService.QueryCarbonFootprint(ctx, p)
+	if err != nil {
+		return "", err
+	}
+
+	// 序列化结果
+	res, err := marshalResult(footprint)
+	if err != nil {
+		return "", err
+	}
+
+	return string(res), nil
+}
+
+type CarbonFootprintParam struct {
+	RestaurantID string   `json:"restaurant_id"`
+	DishNames    []string `json:"dish_names"`
+}
+
+type CarbonFootprint struct {
+	RestaurantID string       `json:"restaurant_id"`
+	Dishes       []DishCarbon `json:"dishes"`
+	TotalKg      float64      `json:"total_kg"`
+	// Rating 按平均每道菜的排放评级, 点的菜多不会让一顿素菜也变成 high
+	Rating string `json:"rating"`
+	// Skipped 是菜单上找不到或者没有排放数据的菜名
+	Skipped []string `json:"skipped,omitempty"`
+}
+
+type DishCarbon struct {
+	Name     string  `json:"name"`
+	CarbonKg float64 `json:"carbon_kg"`
+	Rating   string  `json:"rating"`
+}
+
+// 每道菜的评级阈值, kg CO2e: 蔬菜和主食通常在 0.5 以下, 红肉很容易超过 2.
+const (
+	carbonLowKg    = 0.5
+	carbonMediumKg = 2
+)
+
+func carbonRating(kg float64) string {
+	switch {
+	case kg < carbonLowKg:
+		return "low"
+	case kg < carbonMediumKg:
+		return "medium"
+	default:
+		return "high"
+	}
+}
+
+// QueryCarbonFootprint 汇总在 in.RestaurantID 点 in.DishNames 这些菜的碳排放, 找不到的菜跳过而不是报错.
+func (ft *fakeService) QueryCarbonFootprint(ctx context.Context, in *CarbonFootprintParam) (*CarbonFootprint, error) {
+	if err := ft.simulateLatency(ctx); err != nil {
+		return nil, err
+	}
+
+	rest, err := ft.repo.GetRestaurantByID(ctx, in.RestaurantID)
+	if err != nil {
+		return nil, err
+	}
+
+	footprint := sumCarbon(rest.Dishes, in.DishNames)
+	footprint.RestaurantID = rest.ID
+	return footprint, nil
+}
+
+func sumCarbon(menu []restaurantDishDataItem, names []string) *CarbonFootprint {
+	footprint := &CarbonFootprint{Dishes: make([]DishCarbon, 0, len(names))}
+	for _, name := range names {
+		dish, ok := findDish(menu, name)
+		if !ok || dish.CarbonKg <= 0 {
+			footprint.Skipped = append(footprint.Skipped, name)
+			continue
+		}
+		footprint.Dishes = append(footprint.Dishes, DishCarbon{
+			Name:     dish.Name,
+			CarbonKg: dish.CarbonKg,
+			Rating:   carbonRating(dish.CarbonKg),
+		})
+		footprint.TotalKg += dish.CarbonKg
+	}
+	// 浮点数累加会出现 0.30000000000000004 这样的结果, 保留两位小数
+	footprint.TotalKg = math.Round(footprint.TotalKg*100) / 100
+	if len(footprint.Dishes) > 0 {
+		footprint.Rating = carbonRating(footprint.TotalKg / float64(len(footprint.Dishes)))
+	}
+	return footprint
+}
@@ -0,0 +1,60 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tools
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQueryCarbonFootprint(t *testing.T) {
+	ctx := context.Background()
+
+	footprint, err := restService.QueryCarbonFootprint(ctx, &CarbonFootprintParam{
+		RestaurantID: "1001",
+		DishNames:    []string{"清泉牛肉", "清炒小南瓜", "佛跳墙"},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "1001", footprint.RestaurantID)
+	assert.Equal(t, []DishCarbon{
+		{Name: "清泉牛肉", CarbonKg: 6.5, Rating: "high"},
+		{Name: "清炒小南瓜", CarbonKg: 0.3, Rating: "low"},
+	}, footprint.Dishes)
+	assert.Equal(t, 6.8, footprint.TotalKg)
+	assert.Equal(t, "high", footprint.Rating)
+	assert.Equal(t, []string{"佛跳墙"}, footprint.Skipped)
+
+	_, err = restService.QueryCarbonFootprint(ctx, &CarbonFootprintParam{RestaurantID: "404", DishNames: []string{"红烧肉"}})
+	assert.Error(t, err)
+}
+
+func TestSumCarbon(t *testing.T) {
+	menu := []restaurantDishDataItem{{Name: "a", CarbonKg: 0.1}, {Name: "b", CarbonKg: 0.2}, {Name: "c"}}
+
+	// 总量按平均每道菜评级, 没有数据的菜跳过
+	footprint := sumCarbon(menu, []string{"a", "b", "a", "c"})
+	assert.Len(t, footprint.Dishes, 3)
+	assert.Equal(t, 0.4, footprint.TotalKg)
+	assert.Equal(t, "low", footprint.Rating)
+	assert.Equal(t, []string{"c"}, footprint.Skipped)
+
+	footprint = sumCarbon(menu, nil)
+	assert.Empty(t, footprint.Dishes)
+	assert.Empty(t, footprint.Rating)
+}
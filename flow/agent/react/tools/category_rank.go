@@ -0,0 +1,159 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/cloudwego/eino/components/tool"
+	"github.com/cloudwego/eino/schema"
+)
+
+func GetCategoryRankTool() tool.InvokableTool {
+	return safeTool{
+		InvokableTool: NewCancellableTool(&ToolCategoryRank{
+			backService: restService,
+		}),
+	}
+}
+
+// ToolCategoryRank 给出餐厅在同一个地方、同一个菜系的餐厅中的排名, 比如 "上海 2 家本帮菜中排第 1",
+// 模型推荐时可以用它说明一家餐厅的相对位置.
+type ToolCategoryRank struct {
+	backService *fakeService // fake service
+}
+
+func (t *ToolCategoryRank) Info(ctx context.Context) (*schema.ToolInfo, error) {
+	return &schema.ToolInfo{
+		Name: "query_category_rank",
+		Desc: "Query the rank of a restaurant by score among the restaurants of the same cuisine in the same location, " +
+			"e.g. #2 of 7 Sichuan restaurants in Beijing. Returns the rank, the total and the restaurants compared",
+		ParamsOneOf: schema.NewParamsOneOfByParams(map[string]*schema.ParameterInfo{
+			"restaurant_id": {
+				Type:     "string",
+				Desc:     "The id of one restaurant",
+				Required: true,
+			},
+		}),
+	}, nil
+}
+
+func (t *ToolCategoryRank) InvokableRun(ctx context.Context, argumentsInJSON string, opts ...tool.Option) (string, error) {
+	// 解析参数
+	if err := checkRequired(ctx, t, argumentsInJSON); err != nil {
+		return "", err
+	}
+	p := &CategoryRankParam{}
+	err := json.Unmarshal([]byte(argumentsInJSON), p)
+	if err != nil {
+		return "", err
+	}
+
+	// 请求后端服务
+	rank, err := t.backService.QueryCategoryRank(ctx, p)
+	if err != nil {
+		return "", err
+	}
+
+	// 序列化结果
+	res, err := marshalResult(rank)
+	if err != nil {
+		return "", err
+	}
+
+	return string(res), nil
+}
+
+type CategoryRankParam struct {
+	RestaurantID string `json:"restaurant_id"`
+}
+
+// CategoryRank 中 rank 从 1 开始, 同分的餐厅排名相同; 餐厅没有菜系数据时 rank 为 0, message 说明原因.
+type CategoryRank struct {
+	RestaurantID string             `json:"restaurant_id"`
+	Cuisine      string             `json:"cuisine,omitempty"`
+	Place        string             `json:"place"`
+	Rank         int                `json:"rank"`
+	Total        int                `json:"total"`
+	Compared     []CategoryRankItem `json:"compared"`
+	Message      string             `json:"message"`
+}
+
+type CategoryRankItem struct {
+	Rank  int    `json:"rank"`
+	ID    string `json:"id"`
+	Name  string `json:"name"`
+	Score int    `json:"score"`
+}
+
+// QueryCategoryRank 在 in.RestaurantID 所在地方的同菜系餐厅中排名.
+func (ft *fakeService) QueryCategoryRank(ctx context.Context, in *CategoryRankParam) (*CategoryRank, error) {
+	if err := ft.simulateLatency(ctx); err != nil {
+		return nil, err
+	}
+
+	target, err := ft.repo.GetRestaurantByID(ctx, in.RestaurantID)
+	if err != nil {
+		return nil, err
+	}
+	out := &CategoryRank{RestaurantID: target.ID, Cuisine: target.Cuisine, Place: target.Place, Compared: []CategoryRankItem{}}
+	if target.Cuisine == "" {
+		out.Message = fmt.Sprintf("%s has no cuisine data, it cannot be ranked", target.Name)
+		return out, nil
+	}
+
+	rests, err := ft.repo.GetRestaurants(ctx, target.Place)
+	if err != nil {
+		return nil, err
+	}
+	out.Compared = rankInCategory(target.Cuisine, target.Place, rests)
+	out.Total = len(out.Compared)
+	for _, item := range out.Compared {
+		if item.ID == target.ID {
+			out.Rank = item.Rank
+		}
+	}
+	out.Message = fmt.Sprintf("#%d of %d %s restaurants in %s", out.Rank, out.Total, target.Cuisine, target.Place)
+	return out, nil
+}
+
+// rankInCategory 按评分从高到低给 place 中菜系为 cuisine 的餐厅排名, 同分时排名相同 (1, 1, 3), 按 id 排序保证结果稳定.
+func rankInCategory(cuisine, place string, rests []restaurantDataItem) []CategoryRankItem {
+	var res []CategoryRankItem
+	for _, rest := range rests {
+		if rest.Cuisine == cuisine && rest.Place == place {
+			res = append(res, CategoryRankItem{ID: rest.ID, Name: rest.Name, Score: rest.Score})
+		}
+	}
+	sort.Slice(res, func(i, j int) bool {
+		if res[i].Score != res[j].Score {
+			return res[i].Score > res[j].Score
+		}
+		return res[i].ID < res[j].ID
+	})
+	for i := range res {
+		if i > 0 && res[i].Score == res[i-1].Score {
+			res[i].Rank = res[i-1].Rank
+		} else {
+			res[i].Rank = i + 1
+		}
+	}
+	return res
+}
@@ -0,0 +1,72 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tools
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRankInCategory(t *testing.T) {
+	rests := []restaurantDataItem{
+		{ID: "a", Place: "北京", Cuisine: "川菜", Score: 7},
+		{ID: "b", Place: "北京", Cuisine: "川菜", Score: 9},
+		{ID: "c", Place: "北京", Cuisine: "湘菜", Score: 10},
+		{ID: "d", Place: "北京", Cuisine: "川菜", Score: 7},
+		{ID: "e", Place: "上海", Cuisine: "川菜", Score: 10},
+		{ID: "f", Place: "北京", Cuisine: "川菜", Score: 5},
+	}
+	ranked := rankInCategory("川菜", "北京", rests)
+	var ids []string
+	var ranks []int
+	for _, item := range ranked {
+		ids = append(ids, item.ID)
+		ranks = append(ranks, item.Rank)
+	}
+	assert.Equal(t, []string{"b", "a", "d", "f"}, ids)
+	assert.Equal(t, []int{1, 2, 2, 4}, ranks)
+	assert.Empty(t, rankInCategory("粤菜", "北京", rests))
+}
+
+func TestQueryCategoryRank(t *testing.T) {
+	ctx := context.Background()
+	svc := &fakeService{repo: database}
+
+	rank, err := svc.QueryCategoryRank(ctx, &CategoryRankParam{RestaurantID: "2001"})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, rank.Rank)
+	assert.Equal(t, 2, rank.Total)
+	assert.Equal(t, "#2 of 2 本帮菜 restaurants in 上海", rank.Message)
+	if assert.Len(t, rank.Compared, 2) {
+		assert.Equal(t, "2002", rank.Compared[0].ID)
+	}
+
+	rank, err = svc.QueryCategoryRank(ctx, &CategoryRankParam{RestaurantID: "1001"})
+	assert.NoError(t, err)
+	assert.Equal(t, "#1 of 1 家常菜 restaurants in 北京", rank.Message)
+
+	noCuisine := &fakeService{repo: &restaurantDatabase{restaurantByID: map[string]restaurantDataItem{"9001": {ID: "9001", Name: "无名小店"}}}}
+	rank, err = noCuisine.QueryCategoryRank(ctx, &CategoryRankParam{RestaurantID: "9001"})
+	assert.NoError(t, err)
+	assert.Zero(t, rank.Rank)
+	assert.Contains(t, rank.Message, "no cuisine data")
+
+	_, err = svc.QueryCategoryRank(ctx, &CategoryRankParam{RestaurantID: "9999"})
+	assert.Error(t, err)
+}
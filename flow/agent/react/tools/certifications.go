@@ -0,0 +1,150 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tools
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/cloudwego/eino/components/tool"
+	"github.com/cloudwego/eino/schema"
+)
+
+func GetCertificationsTool() tool.InvokableTool {
+	return safeTool{
+		InvokableTool: NewCancellableTool(&ToolCertifications{
+			backService: restService,
+		}),
+	}
+}
+
+// ToolCertifications 返回餐厅的卫生等级和获得过的奖项.
+type ToolCertifications struct {
+	backService *fakeService // fake service
+}
+
+func (t *ToolCertifications) Info(ctx context.Context) (*schema.ToolInfo, error) {
+	return &schema.ToolInfo{
+		Name: "query_certifications",
+		Desc: "Query the hygiene grade (A is the best, C the worst) and the awards of a restaurant, each award with the year it was received",
+		ParamsOneOf: schema.NewParamsOneOfByParams(map[string]*schema.ParameterInfo{
+			"restaurant_id": {
+				Type:     "string",
+				Desc:     "The id of one restaurant",
+				Required: true,
+			},
+		}),
+	}, nil
+}
+
+func (t *ToolCertifications) InvokableRun(ctx context.Context, argumentsInJSON string, opts ...tool.Option) (string, error) {
+	// 解析参数
+	if err := checkRequired(ctx, t, argumentsInJSON); err != nil {
+		return "", err
+	}
+	p := &CertificationsParam{}
+	err := json.Unmarshal([]byte(argumentsInJSON), p)
+	if err != nil {
+		return "", err
+	}
+
+	// 请求后端服务
+	info, err := t.backService.QueryCertifications(ctx, p)
+	if err != nil {
+		return "", err
+	}
+
+	// 序列化结果
+	res, err := marshalResult(info)
+	if err != nil {
+		return "", err
+	}
+
+	return string(res), nil
+}
+
+type CertificationsParam struct {
+	RestaurantID string `json:"restaurant_id"`
+}
+
+// Certifications 是餐厅的卫生等级和奖项.
+type Certifications struct {
+	HygieneGrade string  `json:"hygiene_grade"`
+	Awards       []Award `json:"awards"`
+}
+
+type Award struct {
+	Name string `json:"name"`
+	Year int    `json:"year"`
+}
+
+type CertificationsInfo struct {
+	RestaurantID string `json:"restaurant_id"`
+	Name         string `json:"name"`
+	// InfoAvailable 为 false 表示餐厅没有公开卫生等级, 不能当作卫生不合格
+	InfoAvailable  bool            `json:"info_available"`
+	Certifications *Certifications `json:"certifications,omitempty"`
+	Message        string          `json:"message,omitempty"`
+}
+
+// QueryCertifications 查询一家餐厅的卫生等级和奖项.
+func (ft *fakeService) QueryCertifications(ctx context.Context, in *CertificationsParam) (*CertificationsInfo, error) {
+	if err := ft.simulateLatency(ctx); err != nil {
+		return nil, err
+	}
+
+	rest, err := ft.repo.GetRestaurantByID(ctx, in.RestaurantID)
+	if err != nil {
+		return nil, err
+	}
+
+	info := &CertificationsInfo{RestaurantID: rest.ID, Name: rest.Name}
+	if rest.Certifications == nil {
+		info.Message = "this restaurant has not published its hygiene grade or awards"
+		return info, nil
+	}
+	info.InfoAvailable = true
+	info.Certifications = toCertifications(rest.Certifications)
+	return info, nil
+}
+
+// hygieneGradeRank 数值越小等级越好.
+var hygieneGradeRank = map[string]int{"A": 0, "B": 1, "C": 2}
+
+func validHygieneGrade(grade string) bool {
+	_, ok := hygieneGradeRank[grade]
+	return ok
+}
+
+// meetsHygieneGrade 卫生等级不低于 minGrade 才算满足, 没有公开等级的餐厅不算.
+func meetsHygieneGrade(item *restaurantCertificationsItem, minGrade string) bool {
+	if item == nil || !validHygieneGrade(item.HygieneGrade) {
+		return false
+	}
+	return hygieneGradeRank[item.HygieneGrade] <= hygieneGradeRank[minGrade]
+}
+
+func toCertifications(item *restaurantCertificationsItem) *Certifications {
+	if item == nil {
+		return nil
+	}
+	awards := make([]Award, 0, len(item.Awards))
+	for _, a := range item.Awards {
+		awards = append(awards, Award{Name: a.Name, Year: a.Year})
+	}
+	return &Certifications{HygieneGrade: item.HygieneGrade, Awards: awards}
+}
@@ -0,0 +1,77 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tools
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQueryCertifications(t *testing.T) {
+	ctx := context.Background()
+
+	info, err := restService.QueryCertifications(ctx, &CertificationsParam{RestaurantID: "1003"})
+	assert.NoError(t, err)
+	assert.True(t, info.InfoAvailable)
+	assert.Equal(t, "A", info.Certifications.HygieneGrade)
+	assert.Equal(t, []Award{{Name: "米其林一星", Year: 2022}, {Name: "米其林一星", Year: 2023}, {Name: "黑珍珠一钻", Year: 2024}}, info.Certifications.Awards)
+
+	// 没有奖项时 awards 是空数组而不是 null
+	out, err := GetCertificationsTool().InvokableRun(ctx, `{"restaurant_id": "1001"}`)
+	assert.NoError(t, err)
+	assert.Contains(t, out, `"certifications":{"hygiene_grade":"B","awards":[]}`)
+
+	info, err = restService.QueryCertifications(ctx, &CertificationsParam{RestaurantID: "2010"})
+	assert.NoError(t, err)
+	assert.False(t, info.InfoAvailable)
+	assert.Nil(t, info.Certifications)
+	assert.NotEmpty(t, info.Message)
+
+	_, err = restService.QueryCertifications(ctx, &CertificationsParam{RestaurantID: "404"})
+	assert.Error(t, err)
+}
+
+func TestQueryRestaurantsMinHygieneGrade(t *testing.T) {
+	ctx := context.Background()
+
+	ids := func(rests []Restaurant) []string {
+		var res []string
+		for _, rest := range rests {
+			res = append(res, rest.ID)
+		}
+		return res
+	}
+
+	// 北京的 1001 是 B, 被筛掉, 其余的按评分从高到低
+	rests, err := restService.QueryRestaurants(ctx, &QueryRestaurantsParam{Location: "北京", Topn: 5, MinHygieneGrade: "A"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"1003", "1002"}, ids(rests))
+
+	// 2010 没有公开卫生等级, 即使要求最低的 C 也不返回
+	rests, err = restService.QueryRestaurants(ctx, &QueryRestaurantsParam{Location: "上海", Topn: 5, MinHygieneGrade: "C"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"2002", "2001"}, ids(rests))
+
+	rests, err = restService.QueryRestaurants(ctx, &QueryRestaurantsParam{Location: "上海", Topn: 5, MinHygieneGrade: "B"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"2002"}, ids(rests))
+
+	_, err = restService.QueryRestaurants(ctx, &QueryRestaurantsParam{Location: "上海", Topn: 5, MinHygieneGrade: "D"})
+	assert.Error(t, err)
+}
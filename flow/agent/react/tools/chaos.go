@@ -0,0 +1,111 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tools
+
+import (
+	"context"
+	"errors"
+	"math"
+	"sync"
+	"sync/atomic"
+
+	"github.com/cloudwego/eino/components/tool"
+)
+
+// errChaos 和 query_restaurants 随机返回的错误一样是可以重试的, 重试、熔断和降级都会按真实的故障处理它.
+var errChaos = errors.New(`{"error":"service temporarily unavailable","message":"The service is temporarily unavailable. Please retry later.","retry":"true"}`)
+
+// chaosTool 每次调用都返回 errChaos, 不调用被包装的 tool.
+type chaosTool struct {
+	tool.InvokableTool
+}
+
+func (c *chaosTool) InvokableRun(ctx context.Context, argumentsInJSON string, opts ...tool.Option) (string, error) {
+	return "", errChaos
+}
+
+// ChaosMiddleware 只包装名为 name 的 tool, 让它每次调用都失败, 其他 tool 原样返回.
+// 和 query_restaurants 的随机失败相比, 结果是确定的, 适合演示和测试重试、熔断这些包装.
+func ChaosMiddleware(name string) ToolMiddleware {
+	return func(t tool.InvokableTool) tool.InvokableTool {
+		if name == "" || toolName(t) != name {
+			return t
+		}
+		return &chaosTool{InvokableTool: t}
+	}
+}
+
+func toolName(t tool.InvokableTool) string {
+	info, err := t.Info(context.Background())
+	if err != nil {
+		return ""
+	}
+	return info.Name
+}
+
+var (
+	chaosMu      sync.Mutex
+	chaosName    string
+	chaosApplied atomic.Bool
+)
+
+// SetChaosTool 让之后创建的名为 name 的 tool 每次调用都失败, 为空时关闭. 需要在创建 tool 之前调用.
+// 故障注入在 NewCancellableTool 中, 也就是请求后端的位置, 各个 tool 自己的重试、熔断和 safeTool 都在它外面,
+// 所以只对请求后端的 tool 生效, compute_bill 这样纯计算的 tool 不受影响.
+func SetChaosTool(name string) {
+	chaosMu.Lock()
+	defer chaosMu.Unlock()
+	chaosName = name
+	chaosApplied.Store(false)
+}
+
+// ChaosToolApplied 表示 SetChaosTool 指定的 tool 是否已经被创建并注入了故障, 用来发现写错的 tool 名称.
+func ChaosToolApplied() bool {
+	return chaosApplied.Load()
+}
+
+// withChaos 在 t 是 SetChaosTool 指定的 tool 时注入故障.
+func withChaos(t tool.InvokableTool) tool.InvokableTool {
+	chaosMu.Lock()
+	name := chaosName
+	chaosMu.Unlock()
+
+	if name == "" || toolName(t) != name {
+		return t
+	}
+	chaosApplied.Store(true)
+	return ChaosMiddleware(name)(t)
+}
+
+// DefaultFailureRate 是 query_restaurants 随机失败的默认概率.
+const DefaultFailureRate = 0.5
+
+// failureRate 保存 float64 的位, 用来在 tool 调用的同时安全地修改.
+var failureRate atomic.Uint64
+
+func init() {
+	failureRate.Store(math.Float64bits(DefaultFailureRate))
+}
+
+// SetFailureRate 设置 query_restaurants 随机返回可重试错误的概率, 限制在 0 到 1 之间, 0 表示从不失败.
+func SetFailureRate(rate float64) {
+	failureRate.Store(math.Float64bits(math.Min(1, math.Max(0, rate))))
+}
+
+func currentFailureRate() float64 {
+	return math.Float64frombits(failureRate.Load())
+}
@@ -0,0 +1,121 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tools
+
+import (
+	"context"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChaosMiddleware(t *testing.T) {
+	ctx := context.Background()
+
+	// 只包装同名的 tool
+	flaky := &flakyTool{}
+	_, err := ChaosMiddleware("flaky")(flaky).InvokableRun(ctx, `{}`)
+	assert.ErrorIs(t, err, errChaos)
+	assert.Equal(t, 0, flaky.calls)
+
+	out, err := ChaosMiddleware("other")(flaky).InvokableRun(ctx, `{}`)
+	assert.NoError(t, err)
+	assert.Equal(t, "done", out)
+}
+
+func TestSetChaosTool(t *testing.T) {
+	ctx := context.Background()
+	SetChaosTool("query_dishes")
+	defer SetChaosTool("")
+
+	GetComputeBillTool()
+	assert.False(t, ChaosToolApplied())
+
+	// 故障在熔断器里面: 连续失败之后熔断器打开, 返回 service degraded
+	dishes := GetDishTool()
+	assert.True(t, ChaosToolApplied())
+	for i := 0; i < 3; i++ {
+		out, err := dishes.InvokableRun(ctx, `{"restaurant_id": "1001"}`)
+		assert.NoError(t, err)
+		assert.Contains(t, out, "temporarily unavailable")
+	}
+	out, err := dishes.InvokableRun(ctx, `{"restaurant_id": "1001"}`)
+	assert.NoError(t, err)
+	assert.Contains(t, out, "service degraded")
+
+	// 其他 tool 不受影响
+	out, err = GetDeliveryTool().InvokableRun(ctx, `{"restaurant_id": "1001"}`)
+	assert.NoError(t, err)
+	assert.NotContains(t, out, "temporarily unavailable")
+}
+
+func TestSetFailureRate(t *testing.T) {
+	ctx := context.Background()
+	defer SetFailureRate(DefaultFailureRate)
+	restaurants := &ToolQueryRestaurants{backService: restService, globalRate: true}
+
+	SetFailureRate(1)
+	_, err := restaurants.InvokableRun(ctx, `{"location": "北京"}`)
+	assert.ErrorContains(t, err, "temporarily unavailable")
+
+	SetFailureRate(0)
+	for i := 0; i < 10; i++ {
+		_, err = restaurants.InvokableRun(ctx, `{"location": "北京"}`)
+		assert.NoError(t, err)
+	}
+
+	SetFailureRate(3)
+	assert.Equal(t, 1.0, currentFailureRate())
+}
+
+func TestQueryRestaurantsFailureRate(t *testing.T) {
+	ctx := context.Background()
+
+	// FailureRate 为 1 时每次都返回可以重试的错误, 经过 safeTool 后作为 content 交给模型
+	always := NewSafeTool(&ToolQueryRestaurants{backService: restService, FailureRate: 1})
+	for i := 0; i < 5; i++ {
+		out, err := always.InvokableRun(ctx, `{"location": "北京"}`)
+		assert.NoError(t, err)
+		assert.JSONEq(t, `{"error":"service temporarily unavailable","message":"The restaurant service is temporarily unavailable. Please retry later.","retry":"true"}`, out)
+	}
+
+	// FailureRate 为 0 (零值) 时从不失败, 不受全局设置的影响
+	SetFailureRate(1)
+	defer SetFailureRate(DefaultFailureRate)
+	never := &ToolQueryRestaurants{backService: restService}
+	for i := 0; i < 10; i++ {
+		out, err := never.InvokableRun(ctx, `{"location": "北京"}`)
+		assert.NoError(t, err)
+		assert.Contains(t, out, "云边小馆")
+	}
+
+	// 相同的种子得到相同的失败序列
+	failures := func(seed int64) []bool {
+		restaurants := &ToolQueryRestaurants{backService: restService, FailureRate: 0.5, Rand: rand.New(rand.NewSource(seed))}
+		var res []bool
+		for i := 0; i < 20; i++ {
+			_, err := restaurants.InvokableRun(ctx, `{"location": "北京"}`)
+			res = append(res, err != nil)
+		}
+		return res
+	}
+	first := failures(42)
+	assert.Equal(t, first, failures(42))
+	assert.Contains(t, first, true)
+	assert.Contains(t, first, false)
+}
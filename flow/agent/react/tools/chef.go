@@ -0,0 +1,119 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tools
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/cloudwego/eino/components/tool"
+	"github.com/cloudwego/eino/schema"
+)
+
+func GetChefTool() tool.InvokableTool {
+	return safeTool{
+		InvokableTool: NewCancellableTool(&ToolQueryChef{
+			backService: restService,
+		}),
+	}
+}
+
+// ToolQueryChef 返回餐厅主厨的信息, 让推荐多一些故事性.
+type ToolQueryChef struct {
+	backService *fakeService // fake service
+}
+
+func (t *ToolQueryChef) Info(ctx context.Context) (*schema.ToolInfo, error) {
+	return &schema.ToolInfo{
+		Name: "query_chef",
+		Desc: "Query the head chef of a restaurant: name, specialty and years of experience",
+		ParamsOneOf: schema.NewParamsOneOfByParams(map[string]*schema.ParameterInfo{
+			"restaurant_id": {
+				Type:     "string",
+				Desc:     "The id of one restaurant",
+				Required: true,
+			},
+		}),
+	}, nil
+}
+
+func (t *ToolQueryChef) InvokableRun(ctx context.Context, argumentsInJSON string, opts ...tool.Option) (string, error) {
+	// 解析参数
+	if err := checkRequired(ctx, t, argumentsInJSON); err != nil {
+		return "", err
+	}
+	p := &QueryChefParam{}
+	err := json.Unmarshal([]byte(argumentsInJSON), p)
+	if err != nil {
+		return "", err
+	}
+
+	// 请求后端服务
+	chef, err := t.backService.QueryChef(ctx, p)
+	if err != nil {
+		return "", err
+	}
+
+	// 序列化结果
+	res, err := marshalResult(chef)
+	if err != nil {
+		return "", err
+	}
+
+	return string(res), nil
+}
+
+type QueryChefParam struct {
+	RestaurantID string `json:"restaurant_id"`
+}
+
+// ChefInfo 在没有主厨信息时, chef_info_available 为 false 并带上说明, 而不是返回一堆空字段.
+type ChefInfo struct {
+	RestaurantID      string `json:"restaurant_id"`
+	ChefInfoAvailable bool   `json:"chef_info_available"`
+	Name              string `json:"name,omitempty"`
+	Specialty         string `json:"specialty,omitempty"`
+	YearsOfExperience int    `json:"years_of_experience,omitempty"`
+	Message           string `json:"message,omitempty"`
+}
+
+// QueryChef 查询一家餐厅的主厨.
+func (ft *fakeService) QueryChef(ctx context.Context, in *QueryChefParam) (*ChefInfo, error) {
+	if err := ft.simulateLatency(ctx); err != nil {
+		return nil, err
+	}
+
+	rest, err := ft.repo.GetRestaurantByID(ctx, in.RestaurantID)
+	if err != nil {
+		return nil, err
+	}
+
+	if rest.Chef == nil {
+		return &ChefInfo{
+			RestaurantID: in.RestaurantID,
+			Message:      "information unavailable: this restaurant has not published its chef",
+		}, nil
+	}
+
+	return &ChefInfo{
+		RestaurantID:      in.RestaurantID,
+		ChefInfoAvailable: true,
+		Name:              rest.Chef.Name,
+		Specialty:         rest.Chef.Specialty,
+		YearsOfExperience: rest.Chef.YearsOfExperience,
+	}, nil
+}
@@ -0,0 +1,194 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/cloudwego/eino/components/tool"
+)
+
+// CircuitState 是熔断器的状态.
+type CircuitState string
+
+const (
+	// CircuitClosed 是正常状态, 所有调用都会请求后端.
+	CircuitClosed CircuitState = "closed"
+	// CircuitOpen 表示后端连续失败, 冷却期内的调用直接返回, 不请求后端.
+	CircuitOpen CircuitState = "open"
+	// CircuitHalfOpen 表示冷却期已过, 放行一次试探调用, 成功则恢复, 失败则重新打开.
+	CircuitHalfOpen CircuitState = "half-open"
+)
+
+// CircuitTransition 描述一次状态变化.
+type CircuitTransition struct {
+	Tool     string
+	From, To CircuitState
+	Reason   string
+}
+
+// CircuitBreakerConfig 控制 NewCircuitBreakerTool, 零值字段使用默认值.
+type CircuitBreakerConfig struct {
+	// FailureThreshold 是连续失败多少次后打开, 默认 3.
+	FailureThreshold int
+	// Cooldown 是打开之后多久进入 half-open, 默认 30s.
+	Cooldown time.Duration
+	// Clock 提供当前时间, 测试中可以注入可控的时钟. 为空时使用 RealClock.
+	Clock Clock
+	// OnStateChange 在每次状态变化时调用, 为空时打印一行 [CIRCUIT] 日志.
+	OnStateChange func(CircuitTransition)
+}
+
+// circuitBreakerTool 在后端连续失败 FailureThreshold 次后打开, 冷却期内直接返回 "service degraded",
+// 不再请求后端, 给后端恢复的时间, 也避免模型在一个坏掉的服务上反复重试. 它和 retryTool 互补:
+// retryTool 处理单次调用中偶发的失败, 熔断器处理一段时间内持续的故障, 所以应该包装在 retryTool 之外, safeTool 之内.
+// 只有标记了 "retry":"true" 的错误 (见 RetryHinted) 才算失败, 被取消的调用和其他错误不计入成功或失败. 每个被包装的 tool 有自己的状态.
+type circuitBreakerTool struct {
+	tool.InvokableTool
+	config CircuitBreakerConfig
+
+	mu       sync.Mutex
+	state    CircuitState
+	failures int       // closed 状态下连续失败的次数
+	openedAt time.Time // 最近一次打开的时间
+	probing  bool      // half-open 状态下是否已经有一个试探调用在执行
+}
+
+func NewCircuitBreakerTool(t tool.InvokableTool, config CircuitBreakerConfig) tool.InvokableTool {
+	if config.FailureThreshold <= 0 {
+		config.FailureThreshold = 3
+	}
+	if config.Cooldown <= 0 {
+		config.Cooldown = 30 * time.Second
+	}
+	if config.Clock == nil {
+		config.Clock = RealClock{}
+	}
+	if config.OnStateChange == nil {
+		config.OnStateChange = func(tr CircuitTransition) {
+			fmt.Printf("[CIRCUIT] %s: %s -> %s (%s)\n", tr.Tool, tr.From, tr.To, tr.Reason)
+		}
+	}
+	return &circuitBreakerTool{InvokableTool: t, config: config, state: CircuitClosed}
+}
+
+func (c *circuitBreakerTool) InvokableRun(ctx context.Context, argumentsInJSON string, opts ...tool.Option) (string, error) {
+	info, err := c.InvokableTool.Info(ctx)
+	if err != nil {
+		return "", err
+	}
+	if retryAfter, ok := c.allow(info.Name); !ok {
+		return "", degradedError(info.Name, retryAfter)
+	}
+
+	out, err := c.InvokableTool.InvokableRun(ctx, argumentsInJSON, opts...)
+	if err != nil && (ctx.Err() != nil || !RetryHinted(err)) {
+		// 被取消的调用, 以及模型自己的错误 (缺少参数、参数不合法、不是合法的 JSON) 或者查不到的结果
+		// 都说明不了后端的状况, 只释放试探的名额; 只有标记了可以重试的暂时性错误才算后端失败
+		c.mu.Lock()
+		c.probing = false
+		c.mu.Unlock()
+		return out, err
+	}
+	c.record(info.Name, err == nil)
+	return out, err
+}
+
+// State 返回当前状态, 冷却期已过但还没有调用时仍然是 open.
+func (c *circuitBreakerTool) State() CircuitState {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.state
+}
+
+// allow 判断这次调用能否请求后端, 不能时返回距离冷却结束还有多久.
+func (c *circuitBreakerTool) allow(name string) (time.Duration, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	switch c.state {
+	case CircuitOpen:
+		elapsed := c.config.Clock.Now().Sub(c.openedAt)
+		if elapsed < c.config.Cooldown {
+			return c.config.Cooldown - elapsed, false
+		}
+		c.transition(name, CircuitHalfOpen, "cooldown elapsed, probing the backend")
+		c.probing = true
+		return 0, true
+	case CircuitHalfOpen:
+		// 同一时间只放行一个试探调用
+		if c.probing {
+			return 0, false
+		}
+		c.probing = true
+		return 0, true
+	}
+	return 0, true
+}
+
+func (c *circuitBreakerTool) record(name string, success bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	switch c.state {
+	case CircuitHalfOpen:
+		c.probing = false
+		if success {
+			c.failures = 0
+			c.transition(name, CircuitClosed, "probe succeeded")
+			return
+		}
+		c.openedAt = c.config.Clock.Now()
+		c.transition(name, CircuitOpen, "probe failed")
+	case CircuitClosed:
+		if success {
+			c.failures = 0
+			return
+		}
+		c.failures++
+		if c.failures >= c.config.FailureThreshold {
+			c.openedAt = c.config.Clock.Now()
+			c.transition(name, CircuitOpen, fmt.Sprintf("%d consecutive failures", c.failures))
+		}
+	}
+	// open 状态下不会有调用到达这里, 除了打开之前就已经在执行的调用, 它们的结果不改变状态
+}
+
+// transition 修改状态并通知 OnStateChange. 调用方需要持有 mu.
+func (c *circuitBreakerTool) transition(name string, to CircuitState, reason string) {
+	from := c.state
+	c.state = to
+	c.config.OnStateChange(CircuitTransition{Tool: name, From: from, To: to, Reason: reason})
+}
+
+func degradedError(name string, retryAfter time.Duration) error {
+	when := "shortly"
+	if retryAfter > 0 {
+		when = "in " + retryAfter.Round(time.Second).String()
+	}
+	msg, _ := json.Marshal(map[string]string{
+		"error": "service degraded",
+		"message": fmt.Sprintf("%s has failed repeatedly and is paused to let it recover, the backend was not called. "+
+			"Continue with the information you already have, it may work again %s", name, when),
+		"retry": "false",
+	})
+	return fmt.Errorf("%s", msg)
+}
@@ -0,0 +1,163 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tools
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// errTemporary 是标记了可以重试的暂时性错误, 熔断器只把这类错误算作后端失败.
+var errTemporary = errors.New(`{"error":"temporary","retry":"true"}`)
+
+// manualClock 只在测试中手动前进.
+type manualClock struct {
+	t time.Time
+}
+
+func (c *manualClock) Now() time.Time {
+	return c.t
+}
+
+func TestCircuitBreakerTool(t *testing.T) {
+	ctx := context.Background()
+	clock := &manualClock{t: time.Date(2024, 6, 1, 19, 0, 0, 0, time.UTC)}
+	var transitions []CircuitTransition
+	flaky := &flakyTool{failures: 100, err: errTemporary}
+	breaker := NewCircuitBreakerTool(flaky, CircuitBreakerConfig{
+		FailureThreshold: 2,
+		Cooldown:         time.Minute,
+		Clock:            clock,
+		OnStateChange:    func(tr CircuitTransition) { transitions = append(transitions, tr) },
+	}).(*circuitBreakerTool)
+
+	// closed: 连续失败 2 次后打开
+	for i := 0; i < 2; i++ {
+		_, err := breaker.InvokableRun(ctx, `{}`)
+		assert.ErrorContains(t, err, "temporary")
+	}
+	assert.Equal(t, CircuitOpen, breaker.State())
+	assert.Equal(t, 2, flaky.calls)
+
+	// open: 冷却期内不请求后端
+	_, err := breaker.InvokableRun(ctx, `{}`)
+	assert.ErrorContains(t, err, "service degraded")
+	assert.ErrorContains(t, err, `"retry":"false"`)
+	assert.Equal(t, 2, flaky.calls)
+
+	// half-open: 冷却期过后放行一次试探, 失败则重新打开
+	clock.t = clock.t.Add(time.Minute)
+	_, err = breaker.InvokableRun(ctx, `{}`)
+	assert.ErrorContains(t, err, "temporary")
+	assert.Equal(t, 3, flaky.calls)
+	assert.Equal(t, CircuitOpen, breaker.State())
+	_, err = breaker.InvokableRun(ctx, `{}`)
+	assert.ErrorContains(t, err, "service degraded")
+
+	// 后端恢复后, 试探成功则关闭
+	flaky.failures = 0
+	clock.t = clock.t.Add(time.Minute)
+	out, err := breaker.InvokableRun(ctx, `{}`)
+	assert.NoError(t, err)
+	assert.Equal(t, "done", out)
+	assert.Equal(t, CircuitClosed, breaker.State())
+
+	var states []string
+	for _, tr := range transitions {
+		assert.Equal(t, "flaky", tr.Tool)
+		states = append(states, string(tr.From)+"->"+string(tr.To))
+	}
+	assert.Equal(t, []string{"closed->open", "open->half-open", "half-open->open", "open->half-open", "half-open->closed"}, states)
+}
+
+func TestCircuitBreakerResetsOnSuccess(t *testing.T) {
+	ctx := context.Background()
+	flaky := &flakyTool{failures: 1, err: errTemporary}
+	breaker := NewCircuitBreakerTool(flaky, CircuitBreakerConfig{FailureThreshold: 2, OnStateChange: func(CircuitTransition) {}}).(*circuitBreakerTool)
+
+	// 失败之间有成功, 不算连续失败
+	_, _ = breaker.InvokableRun(ctx, `{}`)
+	_, _ = breaker.InvokableRun(ctx, `{}`)
+	flaky.failures, flaky.calls = 1, 0
+	_, _ = breaker.InvokableRun(ctx, `{}`)
+	assert.Equal(t, CircuitClosed, breaker.State())
+
+	// 被取消的调用不计入失败
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	b := NewCircuitBreakerTool(NewCancellableTool(flaky), CircuitBreakerConfig{FailureThreshold: 1}).(*circuitBreakerTool)
+	_, err := b.InvokableRun(cancelled, `{}`)
+	assert.ErrorContains(t, err, "cancelled")
+	assert.Equal(t, CircuitClosed, b.State())
+}
+
+func TestCircuitBreakerIgnoresCallerErrors(t *testing.T) {
+	ctx := context.Background()
+	var transitions []CircuitTransition
+	onChange := func(tr CircuitTransition) { transitions = append(transitions, tr) }
+	restaurants := NewCircuitBreakerTool(&ToolQueryRestaurants{backService: restService},
+		CircuitBreakerConfig{OnStateChange: onChange}).(*circuitBreakerTool)
+	dishes := NewCircuitBreakerTool(&ToolQueryDishes{backService: restService},
+		CircuitBreakerConfig{OnStateChange: onChange}).(*circuitBreakerTool)
+
+	// 缺少参数、参数不合法、不是合法的 JSON 和查不到的餐厅都是模型自己的问题, 不会打开熔断器
+	for _, c := range []struct {
+		breaker *circuitBreakerTool
+		args    string
+	}{
+		{restaurants, `{"topn": 2}`},
+		{restaurants, `{"topn": 2}`},
+		{restaurants, `{"topn": 2}`},
+		{restaurants, `{"location": "北京", "topn": -1}`},
+		{restaurants, `{"location": "北京", "topn": "two"}`},
+		{restaurants, `{"location": "北京"`},
+		{dishes, `{"restaurant_id": "404"}`},
+		{dishes, `{"restaurant_id": "404"}`},
+		{dishes, `{"restaurant_id": "404"}`},
+	} {
+		_, err := c.breaker.InvokableRun(ctx, c.args)
+		assert.Error(t, err, c.args)
+		assert.NotContains(t, err.Error(), "service degraded", c.args)
+	}
+	assert.Equal(t, CircuitClosed, restaurants.State())
+	assert.Equal(t, CircuitClosed, dishes.State())
+	assert.Empty(t, transitions)
+
+	// 之后合法的调用照常请求后端
+	_, err := dishes.InvokableRun(ctx, `{"restaurant_id": "1001"}`)
+	assert.NoError(t, err)
+
+	// half-open 时模型自己的错误不算试探失败, 释放名额让下一次调用继续试探
+	clock := &manualClock{t: time.Date(2024, 6, 1, 19, 0, 0, 0, time.UTC)}
+	flaky := &flakyTool{failures: 1, err: errTemporary}
+	breaker := NewCircuitBreakerTool(flaky, CircuitBreakerConfig{FailureThreshold: 1, Cooldown: time.Minute, Clock: clock, OnStateChange: func(CircuitTransition) {}}).(*circuitBreakerTool)
+	_, _ = breaker.InvokableRun(ctx, `{}`)
+	assert.Equal(t, CircuitOpen, breaker.State())
+	clock.t = clock.t.Add(time.Minute)
+	flaky.err, flaky.failures = missingArgumentError("flaky", "location"), 2
+	_, err = breaker.InvokableRun(ctx, `{}`)
+	assert.ErrorContains(t, err, "missing required argument")
+	assert.Equal(t, CircuitHalfOpen, breaker.State())
+	out, err := breaker.InvokableRun(ctx, `{}`)
+	assert.NoError(t, err)
+	assert.Equal(t, "done", out)
+	assert.Equal(t, CircuitClosed, breaker.State())
+}
@@ -0,0 +1,54 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tools
+
+import "time"
+
+// Clock 提供当前时间. 和时间相关的 tool (营业时间、限时特价、排队时长等) 都通过 fakeService 的 clock 取 "现在",
+// 测试中注入 FixedClock 就可以得到确定的结果.
+type Clock interface {
+	Now() time.Time
+}
+
+// RealClock 返回系统时间, 是 fakeService 的默认时钟.
+type RealClock struct{}
+
+func (RealClock) Now() time.Time {
+	return time.Now()
+}
+
+// FixedClock 总是返回同一个时间.
+type FixedClock struct {
+	T time.Time
+}
+
+func (c FixedClock) Now() time.Time {
+	return c.T
+}
+
+// SetClock 替换 fake service 的时钟, 传 nil 恢复为 RealClock.
+func SetClock(c Clock) {
+	restService.clock = c
+}
+
+// now 返回 clock 的当前时间, 没有设置 clock 时使用 RealClock.
+func (ft *fakeService) now() time.Time {
+	if ft.clock == nil {
+		return RealClock{}.Now()
+	}
+	return ft.clock.Now()
+}
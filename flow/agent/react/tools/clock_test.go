@@ -0,0 +1,33 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tools
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClock(t *testing.T) {
+	svc := &fakeService{repo: database}
+	assert.WithinDuration(t, time.Now(), svc.now(), time.Second)
+
+	fixed := time.Date(2024, 6, 1, 12, 0, 0, 0, time.Local)
+	svc.clock = FixedClock{T: fixed}
+	assert.Equal(t, fixed, svc.now())
+}
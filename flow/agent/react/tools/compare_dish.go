@@ -0,0 +1,161 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/cloudwego/eino/components/tool"
+	"github.com/cloudwego/eino/schema"
+)
+
+func GetCompareDishTool() tool.InvokableTool {
+	return safeTool{
+		InvokableTool: NewCancellableTool(&ToolCompareDish{
+			backService: restService,
+		}),
+	}
+}
+
+// ToolCompareDish 在多家餐厅中查找同一道菜, 返回每家的价格和评分, 适合 "哪家的红烧肉最便宜" 这类比较的问题.
+type ToolCompareDish struct {
+	backService *fakeService // fake service
+}
+
+func (t *ToolCompareDish) Info(ctx context.Context) (*schema.ToolInfo, error) {
+	return &schema.ToolInfo{
+		Name: "compare_dish",
+		Desc: "Compare the price and score of one dish across several restaurants, e.g. to find where a dish is the cheapest. " +
+			"Restaurants that do not serve the dish are marked as unavailable",
+		ParamsOneOf: schema.NewParamsOneOfByParams(map[string]*schema.ParameterInfo{
+			"dish_name": {
+				Type:     "string",
+				Desc:     "The name of the dish, a partial name also matches",
+				Required: true,
+			},
+			"restaurant_ids": {
+				Type:     "array",
+				Desc:     "The ids of the restaurants to compare",
+				ElemInfo: &schema.ParameterInfo{Type: "string"},
+				Required: true,
+			},
+		}),
+	}, nil
+}
+
+func (t *ToolCompareDish) InvokableRun(ctx context.Context, argumentsInJSON string, opts ...tool.Option) (string, error) {
+	// 解析参数
+	if err := checkRequired(ctx, t, argumentsInJSON); err != nil {
+		return "", err
+	}
+	p := &CompareDishParam{}
+	err := json.Unmarshal([]byte(argumentsInJSON), p)
+	if err != nil {
+		return "", err
+	}
+
+	// 请求后端服务
+	cmp, err := t.backService.CompareDish(ctx, p)
+	if err != nil {
+		return "", err
+	}
+
+	// 序列化结果
+	res, err := marshalResult(cmp)
+	if err != nil {
+		return "", err
+	}
+
+	return string(res), nil
+}
+
+type CompareDishParam struct {
+	DishName      string   `json:"dish_name"`
+	RestaurantIDs []string `json:"restaurant_ids"`
+}
+
+type DishComparison struct {
+	DishName string `json:"dish_name"`
+	// Rows 和 restaurant_ids 的顺序相同, 每家餐厅一行
+	Rows []DishComparisonRow `json:"rows"`
+	// Cheapest 和 BestRated 是餐厅 id, 没有任何一家有这道菜时为空
+	Cheapest  string `json:"cheapest,omitempty"`
+	BestRated string `json:"best_rated,omitempty"`
+}
+
+// DishComparisonRow 中 available 为 false 时没有价格和评分, 不能当作 0 元.
+type DishComparisonRow struct {
+	RestaurantID   string `json:"restaurant_id"`
+	RestaurantName string `json:"restaurant_name,omitempty"`
+	Available      bool   `json:"available"`
+	// MatchedDish 是菜单上实际匹配到的菜名, 可能和 dish_name 不完全相同
+	MatchedDish string `json:"matched_dish,omitempty"`
+	Price       int    `json:"price,omitempty"`
+	Score       int    `json:"score,omitempty"`
+	Reason      string `json:"reason,omitempty"`
+}
+
+// CompareDish 依次在每家餐厅的菜单中查找 in.DishName. 找不到的餐厅或菜只标记为 unavailable, 不影响其他餐厅.
+func (ft *fakeService) CompareDish(ctx context.Context, in *CompareDishParam) (*DishComparison, error) {
+	if err := ft.simulateLatency(ctx); err != nil {
+		return nil, err
+	}
+
+	if len(in.RestaurantIDs) == 0 {
+		return nil, errors.New("restaurant_ids must not be empty")
+	}
+
+	cmp := &DishComparison{DishName: in.DishName, Rows: make([]DishComparisonRow, 0, len(in.RestaurantIDs))}
+	var cheapest, bestRated *DishComparisonRow
+	for _, id := range in.RestaurantIDs {
+		row := DishComparisonRow{RestaurantID: id}
+		rest, err := ft.repo.GetRestaurantByID(ctx, id)
+		if err != nil {
+			row.Reason = "restaurant not found"
+			cmp.Rows = append(cmp.Rows, row)
+			continue
+		}
+		row.RestaurantName = rest.Name
+
+		dish, ok := findDish(rest.Dishes, in.DishName)
+		if !ok {
+			row.Reason = "this restaurant does not serve the dish"
+			cmp.Rows = append(cmp.Rows, row)
+			continue
+		}
+		row.Available = true
+		row.MatchedDish = dish.Name
+		row.Price = dish.Price
+		row.Score = dish.Score
+		cmp.Rows = append(cmp.Rows, row)
+
+		// 相同时保留先出现的餐厅
+		if cheapest == nil || row.Price < cheapest.Price {
+			cheapest = &row
+		}
+		if bestRated == nil || row.Score > bestRated.Score {
+			bestRated = &row
+		}
+	}
+	if cheapest != nil {
+		cmp.Cheapest = cheapest.RestaurantID
+		cmp.BestRated = bestRated.RestaurantID
+	}
+	return cmp, nil
+}
@@ -0,0 +1,52 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tools
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompareDish(t *testing.T) {
+	ctx := context.Background()
+
+	cmp, err := restService.CompareDish(ctx, &CompareDishParam{
+		DishName:      "红烧肉",
+		RestaurantIDs: []string{"1003", "1002", "1001", "404"},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []DishComparisonRow{
+		{RestaurantID: "1003", RestaurantName: "花影食舍", Available: true, MatchedDish: "超级红烧肉", Price: 30, Score: 9},
+		{RestaurantID: "1002", RestaurantName: "聚福轩食府", Reason: "this restaurant does not serve the dish"},
+		{RestaurantID: "1001", RestaurantName: "云边小馆", Available: true, MatchedDish: "红烧肉", Price: 20, Score: 8},
+		{RestaurantID: "404", Reason: "restaurant not found"},
+	}, cmp.Rows)
+	assert.Equal(t, "1001", cmp.Cheapest)
+	assert.Equal(t, "1003", cmp.BestRated)
+
+	// 没有一家有这道菜
+	cmp, err = restService.CompareDish(ctx, &CompareDishParam{DishName: "佛跳墙", RestaurantIDs: []string{"1001", "2001"}})
+	assert.NoError(t, err)
+	assert.Len(t, cmp.Rows, 2)
+	assert.Empty(t, cmp.Cheapest)
+	assert.Empty(t, cmp.BestRated)
+
+	_, err = restService.CompareDish(ctx, &CompareDishParam{DishName: "红烧肉"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,127 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+
+	"github.com/cloudwego/eino/components/tool"
+	"github.com/cloudwego/eino/schema"
+)
+
+func GetContactInfoTool() tool.InvokableTool {
+	return safeTool{
+		InvokableTool: NewCancellableTool(&ToolContactInfo{
+			backService: restService,
+		}),
+	}
+}
+
+// ToolContactInfo 返回餐厅的电话、邮箱和可以直接拨号的 tel: 链接, 模型可以主动提出帮用户打电话订座.
+// 电话和邮箱是餐厅公开的信息, 但开启日志脱敏时同样不会以明文出现在日志中, 见 DefaultRedactionRules.
+type ToolContactInfo struct {
+	backService *fakeService // fake service
+}
+
+func (t *ToolContactInfo) Info(ctx context.Context) (*schema.ToolInfo, error) {
+	return &schema.ToolInfo{
+		Name: "query_contact_info",
+		Desc: "Query the phone number and email of a restaurant, with a tel: link the user can tap to call it, e.g. to book a table by phone",
+		ParamsOneOf: schema.NewParamsOneOfByParams(map[string]*schema.ParameterInfo{
+			"restaurant_id": {
+				Type:     "string",
+				Desc:     "The id of one restaurant",
+				Required: true,
+			},
+		}),
+	}, nil
+}
+
+func (t *ToolContactInfo) InvokableRun(ctx context.Context, argumentsInJSON string, opts ...tool.Option) (string, error) {
+	// 解析参数
+	if err := checkRequired(ctx, t, argumentsInJSON); err != nil {
+		return "", err
+	}
+	p := &ContactInfoParam{}
+	err := json.Unmarshal([]byte(argumentsInJSON), p)
+	if err != nil {
+		return "", err
+	}
+
+	// 请求后端服务
+	contact, err := t.backService.QueryContactInfo(ctx, p)
+	if err != nil {
+		return "", err
+	}
+
+	// 序列化结果
+	res, err := marshalResult(contact)
+	if err != nil {
+		return "", err
+	}
+
+	return string(res), nil
+}
+
+type ContactInfoParam struct {
+	RestaurantID string `json:"restaurant_id"`
+}
+
+// ContactInfo 在餐厅没有公开联系方式时只有 message.
+type ContactInfo struct {
+	RestaurantID string `json:"restaurant_id"`
+	Name         string `json:"name"`
+	Phone        string `json:"phone,omitempty"`
+	Email        string `json:"email,omitempty"`
+	CallLink     string `json:"call_link,omitempty"` // tel: 链接, 国际格式
+	Message      string `json:"message,omitempty"`
+}
+
+// QueryContactInfo 查询一家餐厅的联系方式.
+func (ft *fakeService) QueryContactInfo(ctx context.Context, in *ContactInfoParam) (*ContactInfo, error) {
+	if err := ft.simulateLatency(ctx); err != nil {
+		return nil, err
+	}
+
+	rest, err := ft.repo.GetRestaurantByID(ctx, in.RestaurantID)
+	if err != nil {
+		return nil, err
+	}
+
+	out := &ContactInfo{RestaurantID: rest.ID, Name: rest.Name}
+	if rest.Contact == nil {
+		out.Message = "this restaurant has no public contact information"
+		return out, nil
+	}
+	out.Phone = rest.Contact.Phone
+	out.Email = rest.Contact.Email
+	out.CallLink = telLink(rest.Contact.Phone)
+	return out, nil
+}
+
+// telLink 把国内的电话号码转成 RFC 3966 的 tel: 链接: 去掉分隔符和区号前的 0, 加上 +86.
+func telLink(phone string) string {
+	digits := strings.Map(func(r rune) rune {
+		if r >= '0' && r <= '9' {
+			return r
+		}
+		return -1
+	}, phone)
+	return "tel:+86" + strings.TrimPrefix(digits, "0")
+}
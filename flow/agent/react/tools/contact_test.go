@@ -0,0 +1,50 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tools
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQueryContactInfo(t *testing.T) {
+	ctx := context.Background()
+
+	contact, err := restService.QueryContactInfo(ctx, &ContactInfoParam{RestaurantID: "1001"})
+	assert.NoError(t, err)
+	assert.Equal(t, "010-65128888", contact.Phone)
+	assert.Equal(t, "hello@yunbian.example.com", contact.Email)
+	assert.Equal(t, "tel:+861065128888", contact.CallLink)
+	assert.Empty(t, contact.Message)
+
+	// 手机号没有区号, 也没有邮箱
+	contact, err = restService.QueryContactInfo(ctx, &ContactInfoParam{RestaurantID: "2001"})
+	assert.NoError(t, err)
+	assert.Equal(t, "tel:+8613816602001", contact.CallLink)
+	assert.Empty(t, contact.Email)
+
+	contact, err = restService.QueryContactInfo(ctx, &ContactInfoParam{RestaurantID: "2010"})
+	assert.NoError(t, err)
+	assert.Empty(t, contact.Phone)
+	assert.Empty(t, contact.CallLink)
+	assert.Contains(t, contact.Message, "no public contact")
+
+	_, err = restService.QueryContactInfo(ctx, &ContactInfoParam{RestaurantID: "404"})
+	assert.Error(t, err)
+}
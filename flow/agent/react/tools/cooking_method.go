@@ -0,0 +1,166 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/cloudwego/eino/components/tool"
+	"github.com/cloudwego/eino/schema"
+)
+
+func GetCookingMethodTool() tool.InvokableTool {
+	return safeTool{
+		InvokableTool: NewCancellableTool(&ToolCookingMethod{
+			backService: restService,
+		}),
+	}
+}
+
+// ToolCookingMethod 回答 "有没有清蒸的菜" 这类问题: 按烹饪方式筛选一家餐厅的菜,
+// 和 dietary、cuisine 的筛选一起, 可以照顾不吃油炸之类的偏好.
+type ToolCookingMethod struct {
+	backService *fakeService // fake service
+}
+
+func (t *ToolCookingMethod) Info(ctx context.Context) (*schema.ToolInfo, error) {
+	return &schema.ToolInfo{
+		Name: "query_dishes_by_cooking_method",
+		Desc: "Query the dishes of a restaurant prepared with one cooking method, e.g. steamed or stir-fried. " +
+			"If no dish matches, the available cooking methods of the restaurant are listed",
+		ParamsOneOf: schema.NewParamsOneOfByParams(map[string]*schema.ParameterInfo{
+			"restaurant_id": {
+				Type:     "string",
+				Desc:     "The id of one restaurant",
+				Required: true,
+			},
+			"method": {
+				Type:     "string",
+				Desc:     "The cooking method, like grilled, steamed, fried, stir-fried, braised, boiled, roasted or cold",
+				Required: true,
+			},
+		}),
+	}, nil
+}
+
+func (t *ToolCookingMethod) InvokableRun(ctx context.Context, argumentsInJSON string, opts ...tool.Option) (string, error) {
+	// 解析参数
+	if err := checkRequired(ctx, t, argumentsInJSON); err != nil {
+		return "", err
+	}
+	p := &CookingMethodParam{}
+	err := json.Unmarshal([]byte(argumentsInJSON), p)
+	if err != nil {
+		return "", err
+	}
+
+	// 请求后端服务
+	dishes, err := t.backService.QueryDishesByCookingMethod(ctx, p)
+	if err != nil {
+		return "", err
+	}
+
+	// 序列化结果
+	res, err := marshalResult(dishes)
+	if err != nil {
+		return "", err
+	}
+
+	return string(res), nil
+}
+
+type CookingMethodParam struct {
+	RestaurantID string `json:"restaurant_id"`
+	Method       string `json:"method"`
+}
+
+// CookingMethodDishes 没有匹配的菜时 dishes 为空, message 中列出这家餐厅有哪些烹饪方式.
+type CookingMethodDishes struct {
+	RestaurantID string `json:"restaurant_id"`
+	Method       string `json:"method"`
+	Dishes       []Dish `json:"dishes"`
+	Message      string `json:"message,omitempty"`
+}
+
+// cookingMethodAliases 把常见的其他说法对应到数据中的烹饪方式.
+var cookingMethodAliases = map[string]string{
+	"stir fried": "stir-fried",
+	"stirfried":  "stir-fried",
+	"deep-fried": "fried",
+	"deep fried": "fried",
+	"stewed":     "braised",
+	"barbecued":  "grilled",
+	"bbq":        "grilled",
+	"baked":      "roasted",
+	"raw":        "cold",
+	"炒":          "stir-fried",
+	"炸":          "fried",
+	"蒸":          "steamed",
+	"烤":          "roasted",
+	"煮":          "boiled",
+	"红烧":         "braised",
+	"凉拌":         "cold",
+	"腌":          "pickled",
+}
+
+// normalizeCookingMethod 把 method 转成小写并替换别名.
+func normalizeCookingMethod(method string) string {
+	method = strings.ToLower(strings.TrimSpace(method))
+	if alias, ok := cookingMethodAliases[method]; ok {
+		return alias
+	}
+	return method
+}
+
+// QueryDishesByCookingMethod 返回 in.RestaurantID 中烹饪方式为 in.Method 的菜, 保持菜单上的顺序.
+// 没有烹饪方式数据的菜不会被匹配到.
+func (ft *fakeService) QueryDishesByCookingMethod(ctx context.Context, in *CookingMethodParam) (*CookingMethodDishes, error) {
+	if err := ft.simulateLatency(ctx); err != nil {
+		return nil, err
+	}
+
+	method := normalizeCookingMethod(in.Method)
+	if method == "" {
+		return nil, fmt.Errorf("method must not be empty")
+	}
+	rest, err := ft.repo.GetRestaurantByID(ctx, in.RestaurantID)
+	if err != nil {
+		return nil, err
+	}
+
+	out := &CookingMethodDishes{RestaurantID: rest.ID, Method: method, Dishes: []Dish{}}
+	available := map[string]bool{}
+	for _, dish := range rest.Dishes {
+		if dish.CookingMethod == method {
+			out.Dishes = append(out.Dishes, toDish(dish))
+		}
+		if dish.CookingMethod != "" {
+			available[dish.CookingMethod] = true
+		}
+	}
+	if len(out.Dishes) == 0 {
+		if len(available) == 0 {
+			out.Message = fmt.Sprintf("no cooking method data for restaurant %s", rest.ID)
+		} else {
+			out.Message = fmt.Sprintf("no %s dishes in restaurant %s, available cooking methods: %s", method, rest.ID, strings.Join(sortedStrings(available), ", "))
+		}
+	}
+	return out, nil
+}
@@ -0,0 +1,76 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tools
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQueryDishesByCookingMethod(t *testing.T) {
+	ctx := context.Background()
+	svc := &fakeService{repo: database}
+
+	names := func(dishes []Dish) []string {
+		res := make([]string, 0, len(dishes))
+		for _, d := range dishes {
+			res = append(res, d.Name)
+		}
+		return res
+	}
+
+	out, err := svc.QueryDishesByCookingMethod(ctx, &CookingMethodParam{RestaurantID: "1001", Method: "stir-fried"})
+	assert.NoError(t, err)
+	assert.Equal(t, "stir-fried", out.Method)
+	assert.Equal(t, []string{"酸辣土豆丝", "清炒小南瓜"}, names(out.Dishes))
+	assert.Equal(t, "stir-fried", out.Dishes[0].CookingMethod)
+	assert.Empty(t, out.Message)
+
+	// 大小写、空格和别名
+	out, err = svc.QueryDishesByCookingMethod(ctx, &CookingMethodParam{RestaurantID: "1001", Method: " Stewed "})
+	assert.NoError(t, err)
+	assert.Equal(t, "braised", out.Method)
+	assert.Equal(t, []string{"红烧肉"}, names(out.Dishes))
+
+	out, err = svc.QueryDishesByCookingMethod(ctx, &CookingMethodParam{RestaurantID: "1003", Method: "烤"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"超级北京烤肉"}, names(out.Dishes))
+
+	// 没有匹配时列出这家餐厅有的烹饪方式
+	out, err = svc.QueryDishesByCookingMethod(ctx, &CookingMethodParam{RestaurantID: "1001", Method: "grilled"})
+	assert.NoError(t, err)
+	assert.NotNil(t, out.Dishes)
+	assert.Empty(t, out.Dishes)
+	assert.Equal(t, "no grilled dishes in restaurant 1001, available cooking methods: boiled, braised, pickled, stir-fried", out.Message)
+
+	// 没有烹饪方式数据
+	repo := &restaurantDatabase{restaurantByID: map[string]restaurantDataItem{
+		"9000": {ID: "9000", Dishes: []restaurantDishDataItem{{Name: "神秘菜"}}},
+	}}
+	out, err = (&fakeService{repo: repo}).QueryDishesByCookingMethod(ctx, &CookingMethodParam{RestaurantID: "9000", Method: "steamed"})
+	assert.NoError(t, err)
+	assert.Empty(t, out.Dishes)
+	assert.Contains(t, out.Message, "no cooking method data")
+
+	_, err = svc.QueryDishesByCookingMethod(ctx, &CookingMethodParam{RestaurantID: "1001", Method: " "})
+	assert.ErrorContains(t, err, "method")
+
+	_, err = svc.QueryDishesByCookingMethod(ctx, &CookingMethodParam{RestaurantID: "404", Method: "steamed"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,207 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/cloudwego/eino/components/tool"
+	"github.com/cloudwego/eino/schema"
+)
+
+func GetCravingRecommendTool() tool.InvokableTool {
+	return safeTool{
+		InvokableTool: NewCancellableTool(&ToolCravingRecommend{
+			backService: restService,
+		}),
+	}
+}
+
+// ToolCravingRecommend 是 "我想吃川菜" 这类常见问题的专用 tool: 一次调用按菜系筛选、按评分排序并给出理由,
+// 模型不需要先 query_restaurants 再逐家比较菜系.
+type ToolCravingRecommend struct {
+	backService *fakeService // fake service
+}
+
+func (t *ToolCravingRecommend) Info(ctx context.Context) (*schema.ToolInfo, error) {
+	return &schema.ToolInfo{
+		Name: "recommend_by_cuisine",
+		Desc: "Recommend the best restaurants of one cuisine in a location in a single call, e.g. when the user craves Sichuan food. " +
+			"Returns restaurants ranked by score, each with a short reason",
+		ParamsOneOf: schema.NewParamsOneOfByParams(map[string]*schema.ParameterInfo{
+			"cuisine": {
+				Type:     "string",
+				Desc:     "The cuisine, in Chinese like 川菜 or in English like sichuan",
+				Required: true,
+			},
+			"location": {
+				Type:     "string",
+				Desc:     "The location of the restaurants",
+				Required: true,
+			},
+			"topn": {
+				Type: "number",
+				Desc: "How many restaurants to return, default 3",
+			},
+		}),
+	}, nil
+}
+
+func (t *ToolCravingRecommend) InvokableRun(ctx context.Context, argumentsInJSON string, opts ...tool.Option) (string, error) {
+	// 解析参数
+	if err := checkRequired(ctx, t, argumentsInJSON); err != nil {
+		return "", err
+	}
+	p := &CravingRecommendParam{}
+	err := json.Unmarshal([]byte(argumentsInJSON), p)
+	if err != nil {
+		return "", err
+	}
+	if p.Topn == 0 {
+		p.Topn = 3
+	}
+
+	// 请求后端服务
+	recs, err := t.backService.CravingRecommend(ctx, p)
+	if err != nil {
+		return "", err
+	}
+
+	// 序列化结果
+	res, err := marshalResult(recs)
+	if err != nil {
+		return "", err
+	}
+
+	return string(res), nil
+}
+
+type CravingRecommendParam struct {
+	Cuisine  string `json:"cuisine"`
+	Location string `json:"location"`
+	Topn     int    `json:"topn"`
+}
+
+// CravingRecommendations 没有匹配的餐厅时 results 为空, message 中列出这个 location 有哪些菜系.
+type CravingRecommendations struct {
+	Cuisine string              `json:"cuisine"`
+	Results []CravingRestaurant `json:"results"`
+	Message string              `json:"message,omitempty"`
+}
+
+type CravingRestaurant struct {
+	ID      string `json:"id"`
+	Name    string `json:"name"`
+	Cuisine string `json:"cuisine"`
+	Score   int    `json:"score"`
+	Reason  string `json:"reason"`
+}
+
+// cuisineAliases 把常见的英文说法对应到数据中的中文菜系.
+var cuisineAliases = map[string]string{
+	"sichuan":      "川菜",
+	"szechuan":     "川菜",
+	"hunan":        "湘菜",
+	"beijing":      "京菜",
+	"peking":       "京菜",
+	"shanghai":     "本帮菜",
+	"shanghainese": "本帮菜",
+	"home-style":   "家常菜",
+	"homestyle":    "家常菜",
+}
+
+// matchesCuisine 判断餐厅的菜系是否满足 craving: 先把英文别名换成中文, 再按包含关系匹配,
+// 所以 "川" 和 "川菜" 都能匹配到川菜.
+func matchesCuisine(cuisine, craving string) bool {
+	craving = strings.ToLower(strings.TrimSpace(craving))
+	if alias, ok := cuisineAliases[craving]; ok {
+		craving = alias
+	}
+	if cuisine == "" || craving == "" {
+		return false
+	}
+	return strings.Contains(cuisine, craving) || strings.Contains(craving, cuisine)
+}
+
+// CravingRecommend 筛选出 in.Location 中菜系匹配 in.Cuisine 的餐厅, 按评分从高到低取前 in.Topn 家, 同分时保持后端的顺序.
+func (ft *fakeService) CravingRecommend(ctx context.Context, in *CravingRecommendParam) (*CravingRecommendations, error) {
+	if err := ft.simulateLatency(ctx); err != nil {
+		return nil, err
+	}
+
+	rests, err := ft.repo.GetRestaurantsByLocation(ctx, in.Location, math.MaxInt)
+	if err != nil {
+		return nil, err
+	}
+
+	var matched []restaurantDataItem
+	available := map[string]bool{}
+	for _, rest := range rests {
+		if matchesCuisine(rest.Cuisine, in.Cuisine) {
+			matched = append(matched, rest)
+		}
+		if rest.Cuisine != "" {
+			available[rest.Cuisine] = true
+		}
+	}
+	sort.SliceStable(matched, func(i, j int) bool { return matched[i].Score > matched[j].Score })
+	if len(matched) > in.Topn {
+		matched = matched[:in.Topn]
+	}
+
+	out := &CravingRecommendations{Cuisine: in.Cuisine, Results: make([]CravingRestaurant, 0, len(matched))}
+	for i, rest := range matched {
+		out.Results = append(out.Results, CravingRestaurant{
+			ID:      rest.ID,
+			Name:    rest.Name,
+			Cuisine: rest.Cuisine,
+			Score:   rest.Score,
+			Reason:  cravingReason(i+1, rest),
+		})
+	}
+	if len(out.Results) == 0 {
+		if len(available) == 0 {
+			out.Message = fmt.Sprintf("no restaurants found in %s", in.Location)
+		} else {
+			out.Message = fmt.Sprintf("no %s restaurants in %s, available cuisines: %s", in.Cuisine, in.Location, strings.Join(sortedStrings(available), ", "))
+		}
+	}
+	return out, nil
+}
+
+// cravingReason 说明排名, 有菜品时带上评分最高的菜 (同 recommendByScore).
+func cravingReason(rank int, rest restaurantDataItem) string {
+	reason := fmt.Sprintf("#%d %s restaurant by score (%d)", rank, rest.Cuisine, rest.Score)
+	if top := recommendByScore(rest.Dishes, 1); len(top) > 0 {
+		reason += fmt.Sprintf(", known for %s", top[0].Name)
+	}
+	return reason
+}
+
+func sortedStrings(set map[string]bool) []string {
+	res := make([]string, 0, len(set))
+	for s := range set {
+		res = append(res, s)
+	}
+	sort.Strings(res)
+	return res
+}
@@ -0,0 +1,62 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tools
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatchesCuisine(t *testing.T) {
+	assert.True(t, matchesCuisine("川菜", "川菜"))
+	assert.True(t, matchesCuisine("川菜", "川"))
+	assert.True(t, matchesCuisine("川菜", " Sichuan "))
+	assert.True(t, matchesCuisine("本帮菜", "shanghainese"))
+	assert.False(t, matchesCuisine("湘菜", "川菜"))
+	assert.False(t, matchesCuisine("", "川菜"))
+	assert.False(t, matchesCuisine("川菜", ""))
+}
+
+func TestCravingRecommend(t *testing.T) {
+	ctx := context.Background()
+
+	// 按评分从高到低
+	out, err := restService.CravingRecommend(ctx, &CravingRecommendParam{Cuisine: "shanghainese", Location: "上海", Topn: 3})
+	assert.NoError(t, err)
+	assert.Len(t, out.Results, 2)
+	assert.Equal(t, "2002", out.Results[0].ID)
+	assert.Equal(t, "2001", out.Results[1].ID)
+	assert.Contains(t, out.Results[0].Reason, "#1 本帮菜 restaurant")
+	assert.Empty(t, out.Message)
+
+	out, err = restService.CravingRecommend(ctx, &CravingRecommendParam{Cuisine: "本帮菜", Location: "上海", Topn: 1})
+	assert.NoError(t, err)
+	assert.Len(t, out.Results, 1)
+
+	// 没有这个菜系时列出有哪些
+	out, err = restService.CravingRecommend(ctx, &CravingRecommendParam{Cuisine: "sushi", Location: "上海", Topn: 3})
+	assert.NoError(t, err)
+	assert.Empty(t, out.Results)
+	assert.Contains(t, out.Message, "no sushi restaurants in 上海, available cuisines: ")
+	assert.Contains(t, out.Message, "本帮菜")
+
+	out, err = restService.CravingRecommend(ctx, &CravingRecommendParam{Cuisine: "川菜", Location: "火星", Topn: 3})
+	assert.NoError(t, err)
+	assert.Contains(t, out.Message, "no restaurants found")
+}
@@ -0,0 +1,172 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/cloudwego/eino/components/tool"
+	"github.com/cloudwego/eino/schema"
+)
+
+func GetMenuInCurrencyTool() tool.InvokableTool {
+	return safeTool{
+		InvokableTool: NewCancellableTool(&ToolMenuInCurrency{
+			backService: restService,
+		}),
+	}
+}
+
+// ToolMenuInCurrency 返回一家餐厅的菜单, 价格按固定的汇率表换算成目标货币, 展示 tool 对查询结果再做一次转换:
+// 换算交给 tool 而不是模型, 结果可以复现, 也不会算错. 返回值带上使用的汇率, 模型可以向用户说明.
+type ToolMenuInCurrency struct {
+	backService *fakeService // fake service
+}
+
+func (t *ToolMenuInCurrency) Info(ctx context.Context) (*schema.ToolInfo, error) {
+	return &schema.ToolInfo{
+		Name: "query_menu_in_currency",
+		Desc: "Query the dishes of a restaurant with prices converted from CNY to another currency, for travellers. " +
+			"Returns the exchange rate used together with the converted prices",
+		ParamsOneOf: schema.NewParamsOneOfByParams(map[string]*schema.ParameterInfo{
+			"restaurant_id": {
+				Type:     "string",
+				Desc:     "The id of one restaurant",
+				Required: true,
+			},
+			"target_currency": {
+				Type:     "string",
+				Desc:     "The ISO 4217 code of the currency to convert the prices to",
+				Enum:     supportedCurrencies(),
+				Required: true,
+			},
+		}),
+	}, nil
+}
+
+func (t *ToolMenuInCurrency) InvokableRun(ctx context.Context, argumentsInJSON string, opts ...tool.Option) (string, error) {
+	// 解析参数
+	if err := checkRequired(ctx, t, argumentsInJSON); err != nil {
+		return "", err
+	}
+	p := &MenuInCurrencyParam{}
+	err := json.Unmarshal([]byte(argumentsInJSON), p)
+	if err != nil {
+		return "", err
+	}
+
+	// 请求后端服务
+	menu, err := t.backService.QueryMenuInCurrency(ctx, p)
+	if err != nil {
+		return "", err
+	}
+
+	// 序列化结果
+	res, err := marshalResult(menu)
+	if err != nil {
+		return "", err
+	}
+
+	return string(res), nil
+}
+
+type MenuInCurrencyParam struct {
+	RestaurantID   string `json:"restaurant_id"`
+	TargetCurrency string `json:"target_currency"`
+}
+
+type MenuInCurrency struct {
+	RestaurantID string `json:"restaurant_id"`
+	BaseCurrency string `json:"base_currency"`
+	Currency     string `json:"currency"`
+	// Rate 是 1 单位 BaseCurrency 换算成多少 Currency
+	Rate   float64               `json:"rate"`
+	Dishes []DishPriceInCurrency `json:"dishes"`
+}
+
+type DishPriceInCurrency struct {
+	Name     string  `json:"name"`
+	PriceCNY int     `json:"price_cny"`
+	Price    float64 `json:"price"`
+}
+
+// baseCurrency 是 fake 数据中价格的货币.
+const baseCurrency = "CNY"
+
+type currencyRate struct {
+	rate     float64 // 1 CNY = rate 单位的这种货币
+	decimals int     // 换算后保留的小数位数
+}
+
+// exchangeRates 是固定的假汇率表, 只用于演示, 不代表真实汇率.
+var exchangeRates = map[string]currencyRate{
+	"CNY": {rate: 1, decimals: 2},
+	"USD": {rate: 0.14, decimals: 2},
+	"EUR": {rate: 0.13, decimals: 2},
+	"GBP": {rate: 0.11, decimals: 2},
+	"HKD": {rate: 1.09, decimals: 2},
+	"JPY": {rate: 21.5, decimals: 0},
+	"KRW": {rate: 190, decimals: 0},
+}
+
+// supportedCurrencies 按固定顺序返回汇率表中的货币, 基础货币排在最前.
+func supportedCurrencies() []string {
+	return []string{"CNY", "USD", "EUR", "GBP", "HKD", "JPY", "KRW"}
+}
+
+// QueryMenuInCurrency 返回 in.RestaurantID 的菜单, 价格换算成 in.TargetCurrency. 不支持的货币返回错误.
+func (ft *fakeService) QueryMenuInCurrency(ctx context.Context, in *MenuInCurrencyParam) (*MenuInCurrency, error) {
+	if err := ft.simulateLatency(ctx); err != nil {
+		return nil, err
+	}
+
+	code := strings.ToUpper(strings.TrimSpace(in.TargetCurrency))
+	rate, ok := exchangeRates[code]
+	if !ok {
+		return nil, fmt.Errorf("unsupported currency %q, supported currencies are %s", in.TargetCurrency, strings.Join(supportedCurrencies(), ", "))
+	}
+
+	rest, err := ft.repo.GetRestaurantByID(ctx, in.RestaurantID)
+	if err != nil {
+		return nil, err
+	}
+
+	menu := &MenuInCurrency{
+		RestaurantID: rest.ID,
+		BaseCurrency: baseCurrency,
+		Currency:     code,
+		Rate:         rate.rate,
+		Dishes:       make([]DishPriceInCurrency, 0, len(rest.Dishes)),
+	}
+	for _, dish := range rest.Dishes {
+		menu.Dishes = append(menu.Dishes, DishPriceInCurrency{
+			Name:     dish.Name,
+			PriceCNY: dish.Price,
+			Price:    convertPrice(dish.Price, rate),
+		})
+	}
+	return menu, nil
+}
+
+func convertPrice(priceCNY int, rate currencyRate) float64 {
+	scale := math.Pow10(rate.decimals)
+	return math.Round(float64(priceCNY)*rate.rate*scale) / scale
+}
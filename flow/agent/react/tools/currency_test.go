@@ -0,0 +1,56 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tools
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQueryMenuInCurrency(t *testing.T) {
+	ctx := context.Background()
+
+	menu, err := restService.QueryMenuInCurrency(ctx, &MenuInCurrencyParam{RestaurantID: "1001", TargetCurrency: "usd"})
+	assert.NoError(t, err)
+	assert.Equal(t, "CNY", menu.BaseCurrency)
+	assert.Equal(t, "USD", menu.Currency)
+	assert.Equal(t, 0.14, menu.Rate)
+	assert.Equal(t, DishPriceInCurrency{Name: "韩式辣白菜", PriceCNY: 20, Price: 2.8}, menu.Dishes[0])
+
+	// 日元没有小数
+	menu, err = restService.QueryMenuInCurrency(ctx, &MenuInCurrencyParam{RestaurantID: "1001", TargetCurrency: "JPY"})
+	assert.NoError(t, err)
+	assert.Equal(t, 430.0, menu.Dishes[0].Price)
+
+	_, err = restService.QueryMenuInCurrency(ctx, &MenuInCurrencyParam{RestaurantID: "1001", TargetCurrency: "BTC"})
+	assert.ErrorContains(t, err, `unsupported currency "BTC"`)
+	assert.ErrorContains(t, err, "USD")
+
+	_, err = restService.QueryMenuInCurrency(ctx, &MenuInCurrencyParam{RestaurantID: "404", TargetCurrency: "USD"})
+	assert.Error(t, err)
+}
+
+func TestSupportedCurrencies(t *testing.T) {
+	assert.Len(t, supportedCurrencies(), len(exchangeRates))
+	for _, code := range supportedCurrencies() {
+		assert.Contains(t, exchangeRates, code)
+	}
+	assert.Equal(t, baseCurrency, supportedCurrencies()[0])
+	assert.Equal(t, 1.0, exchangeRates[baseCurrency].rate)
+}
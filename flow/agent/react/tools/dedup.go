@@ -0,0 +1,134 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tools
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/cloudwego/eino/components/tool"
+)
+
+// ToolCallDedup 记录一轮 tool call (模型的一条 assistant 消息) 中已经执行的调用, 名称和参数都相同的调用只执行一次,
+// 其余的等它完成后共享同一个结果. 每个 call id 仍然各自得到一条 tool 消息, 对话保持完整.
+// 不同轮之间不共享, 那是 ResultCache 的工作: 后端状态可能已经变化, 模型再次查询通常是有意的.
+type ToolCallDedup struct {
+	mu    sync.Mutex
+	calls map[string]*dedupCall
+}
+
+type dedupCall struct {
+	done  chan struct{}
+	out   string
+	err   error
+	state ToolExecutionState
+	calls int // 包括第一次在内, 共享这个结果的调用次数
+}
+
+// DedupStat 是一组相同的调用.
+type DedupStat struct {
+	Key   string // tool 名称和参数, 同 cacheKey
+	Calls int
+}
+
+type toolCallDedupKey struct{}
+
+// WithToolCallDedup 为新的一轮 tool call 创建 ToolCallDedup, 通常在 ToolsNode 的 OnStart callback 中调用.
+func WithToolCallDedup(ctx context.Context) context.Context {
+	return context.WithValue(ctx, toolCallDedupKey{}, &ToolCallDedup{calls: map[string]*dedupCall{}})
+}
+
+// ToolCallDedupFrom 返回 ctx 中这一轮的 ToolCallDedup, 没有时返回 nil.
+func ToolCallDedupFrom(ctx context.Context) *ToolCallDedup {
+	d, _ := ctx.Value(toolCallDedupKey{}).(*ToolCallDedup)
+	return d
+}
+
+// Duplicates 返回这一轮中被调用了不止一次的 tool 和参数, 按 key 排序.
+func (d *ToolCallDedup) Duplicates() []DedupStat {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	var res []DedupStat
+	for key, call := range d.calls {
+		if call.calls > 1 {
+			res = append(res, DedupStat{Key: key, Calls: call.calls})
+		}
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].Key < res[j].Key })
+	return res
+}
+
+// begin 返回 key 对应的调用, leader 为 true 表示这是第一次调用, 需要由调用方执行并 finish.
+func (d *ToolCallDedup) begin(key string) (call *dedupCall, leader bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if call, ok := d.calls[key]; ok {
+		call.calls++
+		return call, false
+	}
+	call = &dedupCall{done: make(chan struct{}), calls: 1}
+	d.calls[key] = call
+	return call, true
+}
+
+// dedupTool 在 ctx 中有 ToolCallDedup 时合并同一轮中相同的调用, 没有时直接调用被包装的 tool.
+// 它应该包装在最外层: 重复的调用不再经过参数检查、重试等, 并且把第一次调用的 ToolExecutionState 复制过来,
+// 日志和缓存看到的成功与否和真正执行的那次一致.
+type dedupTool struct {
+	tool.InvokableTool
+}
+
+func NewDedupTool(t tool.InvokableTool) tool.InvokableTool {
+	return &dedupTool{InvokableTool: t}
+}
+
+func (d *dedupTool) InvokableRun(ctx context.Context, argumentsInJSON string, opts ...tool.Option) (string, error) {
+	dedup := ToolCallDedupFrom(ctx)
+	if dedup == nil {
+		return d.InvokableTool.InvokableRun(ctx, argumentsInJSON, opts...)
+	}
+	info, err := d.InvokableTool.Info(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	call, leader := dedup.begin(cacheKey(info.Name, argumentsInJSON))
+	if !leader {
+		select {
+		case <-call.done:
+		case <-ctx.Done():
+			return "", ctx.Err()
+		}
+		if state := GetToolState(ctx); state != nil {
+			*state = call.state
+		}
+		return call.out, call.err
+	}
+
+	state := GetToolState(ctx)
+	if state == nil {
+		state = &ToolExecutionState{}
+		ctx = SetToolState(ctx, state)
+	}
+	call.out, call.err = d.InvokableTool.InvokableRun(ctx, argumentsInJSON, opts...)
+	call.state = *state
+	close(call.done)
+	return call.out, call.err
+}
@@ -0,0 +1,86 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tools
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/cloudwego/eino/components/tool"
+	"github.com/cloudwego/eino/schema"
+	"github.com/stretchr/testify/assert"
+)
+
+// countingTool counts its calls and echoes the arguments after a short delay.
+type countingTool struct {
+	calls atomic.Int32
+}
+
+func (c *countingTool) Info(ctx context.Context) (*schema.ToolInfo, error) {
+	return &schema.ToolInfo{Name: "counting"}, nil
+}
+
+func (c *countingTool) InvokableRun(ctx context.Context, argumentsInJSON string, opts ...tool.Option) (string, error) {
+	c.calls.Add(1)
+	time.Sleep(10 * time.Millisecond)
+	return argumentsInJSON, nil
+}
+
+func TestDedupTool(t *testing.T) {
+	inner := &countingTool{}
+	dedup := NewDedupTool(safeTool{InvokableTool: inner})
+
+	// 没有 ToolCallDedup 时不合并
+	ctx := context.Background()
+	_, _ = dedup.InvokableRun(ctx, `{"restaurant_id":"1001"}`)
+	_, _ = dedup.InvokableRun(ctx, `{"restaurant_id":"1001"}`)
+	assert.Equal(t, int32(2), inner.calls.Load())
+
+	// 同一轮中并发的相同调用只执行一次, 字段顺序和空白不影响
+	inner.calls.Store(0)
+	turn := WithToolCallDedup(ctx)
+	args := []string{`{"restaurant_id":"1001","topn":5}`, `{"topn": 5, "restaurant_id": "1001"}`, `{"restaurant_id":"1002"}`}
+	outs := make([]string, len(args))
+	states := make([]*ToolExecutionState, len(args))
+	var wg sync.WaitGroup
+	for i, a := range args {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			states[i] = &ToolExecutionState{}
+			out, err := dedup.InvokableRun(SetToolState(turn, states[i]), a)
+			assert.NoError(t, err)
+			outs[i] = out
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, int32(2), inner.calls.Load())
+	assert.Equal(t, outs[0], outs[1])
+	for _, s := range states {
+		assert.True(t, s.Success)
+	}
+	assert.Equal(t, []DedupStat{{Key: `counting {"restaurant_id":"1001","topn":5}`, Calls: 2}},
+		ToolCallDedupFrom(turn).Duplicates())
+
+	// 新的一轮重新执行
+	_, _ = dedup.InvokableRun(WithToolCallDedup(ctx), args[0])
+	assert.Equal(t, int32(3), inner.calls.Load())
+}
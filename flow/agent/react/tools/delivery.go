@@ -0,0 +1,147 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"hash/fnv"
+	"math"
+
+	"github.com/cloudwego/eino/components/tool"
+	"github.com/cloudwego/eino/schema"
+)
+
+func GetDeliveryTool() tool.InvokableTool {
+	return safeTool{
+		InvokableTool: NewCancellableTool(&ToolQueryDelivery{
+			backService: restService,
+		}),
+	}
+}
+
+// ToolQueryDelivery 结合餐厅和收货地址两个输入, 计算出是否可以配送、预计耗时和配送费.
+type ToolQueryDelivery struct {
+	backService *fakeService // fake service
+}
+
+func (t *ToolQueryDelivery) Info(ctx context.Context) (*schema.ToolInfo, error) {
+	return &schema.ToolInfo{
+		Name: "query_delivery",
+		Desc: "Query whether a restaurant delivers to an address, with the estimated delivery time and fee",
+		ParamsOneOf: schema.NewParamsOneOfByParams(map[string]*schema.ParameterInfo{
+			"restaurant_id": {
+				Type:     "string",
+				Desc:     "The id of one restaurant",
+				Required: true,
+			},
+			"address": {
+				Type:     "string",
+				Desc:     "The delivery address of the user",
+				Required: true,
+			},
+		}),
+	}, nil
+}
+
+func (t *ToolQueryDelivery) InvokableRun(ctx context.Context, argumentsInJSON string, opts ...tool.Option) (string, error) {
+	// 解析参数
+	if err := checkRequired(ctx, t, argumentsInJSON); err != nil {
+		return "", err
+	}
+	p := &QueryDeliveryParam{}
+	err := json.Unmarshal([]byte(argumentsInJSON), p)
+	if err != nil {
+		return "", err
+	}
+
+	// 请求后端服务
+	option, err := t.backService.QueryDelivery(ctx, p)
+	if err != nil {
+		return "", err
+	}
+
+	// 序列化结果
+	res, err := marshalResult(option)
+	if err != nil {
+		return "", err
+	}
+
+	return string(res), nil
+}
+
+type QueryDeliveryParam struct {
+	RestaurantID string `json:"restaurant_id"`
+	Address      string `json:"address"`
+}
+
+// DeliveryOption 不可配送时不返回 fee, 而是通过 delivery_available 和 message 明确告知, 避免模型把 0 理解成免配送费.
+type DeliveryOption struct {
+	RestaurantID      string  `json:"restaurant_id"`
+	Address           string  `json:"address"`
+	DeliveryAvailable bool    `json:"delivery_available"`
+	DistanceKm        float64 `json:"distance_km,omitempty"`
+	EstimatedMinutes  int     `json:"estimated_minutes,omitempty"`
+	FeeYuan           *int    `json:"fee_yuan,omitempty"`
+	Message           string  `json:"message"`
+}
+
+// QueryDelivery 查询一家餐厅到一个地址的配送信息.
+func (ft *fakeService) QueryDelivery(ctx context.Context, in *QueryDeliveryParam) (*DeliveryOption, error) {
+	if err := ft.simulateLatency(ctx); err != nil {
+		return nil, err
+	}
+
+	rest, err := ft.repo.GetRestaurantByID(ctx, in.RestaurantID)
+	if err != nil {
+		return nil, err
+	}
+
+	out := &DeliveryOption{
+		RestaurantID: in.RestaurantID,
+		Address:      in.Address,
+	}
+	if rest.Delivery == nil {
+		out.Message = "this restaurant does not offer delivery"
+		return out, nil
+	}
+
+	out.DistanceKm = fakeDistanceKm(in.RestaurantID, in.Address)
+	if out.DistanceKm > rest.Delivery.MaxDistanceKm {
+		out.Message = "the address is out of the delivery range of this restaurant"
+		return out, nil
+	}
+
+	fee := deliveryFee(rest.Delivery, out.DistanceKm)
+	out.DeliveryAvailable = true
+	out.FeeYuan = &fee
+	out.EstimatedMinutes = rest.Delivery.PrepMinutes + int(math.Ceil(out.DistanceKm*4))
+	out.Message = "delivery available"
+	return out, nil
+}
+
+// fakeDistanceKm 根据餐厅和地址算出一个确定的假距离, 范围 0.5 - 12 km, 同样的输入总是得到同样的距离.
+func fakeDistanceKm(restaurantID, address string) float64 {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(restaurantID + "|" + address))
+	return 0.5 + float64(h.Sum32()%116)/10
+}
+
+// deliveryFee 起送费 + 每公里费用, 不足一公里按一公里算.
+func deliveryFee(d *restaurantDeliveryItem, distanceKm float64) int {
+	return d.BaseFee + int(math.Ceil(distanceKm))*d.FeePerKm
+}
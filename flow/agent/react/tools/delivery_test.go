@@ -0,0 +1,52 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tools
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQueryDelivery(t *testing.T) {
+	ctx := context.Background()
+
+	assert.Equal(t, fakeDistanceKm("1001", "朝阳区"), fakeDistanceKm("1001", "朝阳区"))
+	assert.Equal(t, 9, deliveryFee(&restaurantDeliveryItem{BaseFee: 5, FeePerKm: 2}, 1.2))
+
+	out, err := restService.QueryDelivery(ctx, &QueryDeliveryParam{RestaurantID: "2010", Address: "朝阳区"})
+	assert.NoError(t, err)
+	assert.False(t, out.DeliveryAvailable)
+	assert.Nil(t, out.FeeYuan)
+	assert.Contains(t, out.Message, "does not offer delivery")
+
+	for _, address := range []string{"朝阳区", "海淀区", "东城区", "西城区", "丰台区"} {
+		out, err = restService.QueryDelivery(ctx, &QueryDeliveryParam{RestaurantID: "1001", Address: address})
+		assert.NoError(t, err)
+		if out.DeliveryAvailable {
+			assert.NotNil(t, out.FeeYuan)
+			assert.Positive(t, out.EstimatedMinutes)
+		} else {
+			assert.Greater(t, out.DistanceKm, 8.0)
+			assert.Nil(t, out.FeeYuan)
+		}
+	}
+
+	_, err = restService.QueryDelivery(ctx, &QueryDeliveryParam{RestaurantID: "404", Address: "朝阳区"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,203 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+
+	"github.com/cloudwego/eino/components/tool"
+	"github.com/cloudwego/eino/schema"
+)
+
+func GetDeliveryVsDineInTool() tool.InvokableTool {
+	return safeTool{
+		InvokableTool: NewCancellableTool(&ToolDeliveryVsDineIn{
+			backService: restService,
+		}),
+	}
+}
+
+// ToolDeliveryVsDineIn 回答 "点外卖还是去店里吃" 这类问题: 对同一份菜, 分别算出堂食 (加服务费) 和外卖
+// (加配送费, 不够起送金额时补足) 的总价, 并给出更便宜的一种. 和 estimate_trip_cost 不同, 这里按实际点的菜计算, 不含交通费.
+type ToolDeliveryVsDineIn struct {
+	backService *fakeService // fake service
+}
+
+func (t *ToolDeliveryVsDineIn) Info(ctx context.Context) (*schema.ToolInfo, error) {
+	return &schema.ToolInfo{
+		Name: "compare_delivery_dine_in",
+		Desc: "Compare the total cost in CNY of the same dishes ordered for delivery versus eaten in the restaurant, " +
+			"including the dine-in service fee, the delivery fee and the minimum order. Returns both totals side by side and a recommendation",
+		ParamsOneOf: schema.NewParamsOneOfByParams(map[string]*schema.ParameterInfo{
+			"restaurant_id": {
+				Type:     "string",
+				Desc:     "The id of one restaurant",
+				Required: true,
+			},
+			"party_size": {
+				Type:     "integer",
+				Desc:     "How many people are eating",
+				Required: true,
+			},
+			"dish_names": {
+				Type:     "array",
+				Desc:     "The names of the dishes in the meal",
+				ElemInfo: &schema.ParameterInfo{Type: "string"},
+				Required: true,
+			},
+			"address": {
+				Type: "string",
+				Desc: "The delivery address of the user, used to estimate the delivery fee",
+			},
+		}),
+	}, nil
+}
+
+func (t *ToolDeliveryVsDineIn) InvokableRun(ctx context.Context, argumentsInJSON string, opts ...tool.Option) (string, error) {
+	// 解析参数
+	if err := checkRequired(ctx, t, argumentsInJSON); err != nil {
+		return "", err
+	}
+	p := &DeliveryVsDineInParam{}
+	err := json.Unmarshal([]byte(argumentsInJSON), p)
+	if err != nil {
+		return "", err
+	}
+
+	// 请求后端服务
+	cmp, err := t.backService.CompareDeliveryDineIn(ctx, p)
+	if err != nil {
+		return "", err
+	}
+
+	// 序列化结果
+	res, err := marshalResult(cmp)
+	if err != nil {
+		return "", err
+	}
+
+	return string(res), nil
+}
+
+type DeliveryVsDineInParam struct {
+	RestaurantID string   `json:"restaurant_id"`
+	PartySize    int      `json:"party_size"`
+	DishNames    []string `json:"dish_names"`
+	Address      string   `json:"address"`
+}
+
+// MealCostOption 是一种用餐方式的花费, 金额单位是元. available 为 false 时没有 items 和 total, 原因在 message 中.
+type MealCostOption struct {
+	Available bool           `json:"available"`
+	Items     []TripCostItem `json:"items,omitempty"`
+	Total     float64        `json:"total,omitempty"`
+	PerPerson float64        `json:"per_person,omitempty"`
+	Message   string         `json:"message,omitempty"`
+}
+
+// DeliveryVsDineIn 中 recommendation 是 dine_in 或 delivery, 外卖不可用时总是 dine_in.
+type DeliveryVsDineIn struct {
+	RestaurantID   string         `json:"restaurant_id"`
+	PartySize      int            `json:"party_size"`
+	Food           float64        `json:"food"`
+	DineIn         MealCostOption `json:"dine_in"`
+	Delivery       MealCostOption `json:"delivery"`
+	Recommendation string         `json:"recommendation"`
+	Message        string         `json:"message"`
+	// Skipped 是菜单上找不到的菜名, 没有计入 food
+	Skipped []string `json:"skipped,omitempty"`
+}
+
+// CompareDeliveryDineIn 按 in.DishNames 的菜价算出餐费, 堂食加 serviceFeePercent 的服务费, 外卖加 deliveryFee,
+// 餐费不够起送金额时把差价计入外卖的花费. 距离和 estimate_trip_cost 一样用 fakeDistanceKm 估算.
+func (ft *fakeService) CompareDeliveryDineIn(ctx context.Context, in *DeliveryVsDineInParam) (*DeliveryVsDineIn, error) {
+	if err := ft.simulateLatency(ctx); err != nil {
+		return nil, err
+	}
+
+	if in.PartySize <= 0 || in.PartySize > maxPartySize {
+		return nil, fmt.Errorf("party_size must be between 1 and %d, got %d", maxPartySize, in.PartySize)
+	}
+	rest, err := ft.repo.GetRestaurantByID(ctx, in.RestaurantID)
+	if err != nil {
+		return nil, err
+	}
+
+	out := &DeliveryVsDineIn{RestaurantID: rest.ID, PartySize: in.PartySize}
+	food := 0
+	for _, name := range in.DishNames {
+		dish, ok := findDish(rest.Dishes, name)
+		if !ok {
+			out.Skipped = append(out.Skipped, name)
+			continue
+		}
+		food += dish.Price
+	}
+	if food == 0 {
+		return nil, fmt.Errorf("none of the dishes %v are on the menu of restaurant %s", in.DishNames, rest.ID)
+	}
+	out.Food = float64(food)
+
+	out.DineIn = mealCostOption(in.PartySize, []TripCostItem{
+		{Item: "food", Amount: out.Food, Note: fmt.Sprintf("%d dish(es)", len(in.DishNames)-len(out.Skipped))},
+		{Item: "service fee", Amount: roundYuan(out.Food * serviceFeePercent / 100), Note: fmt.Sprintf("%d%% of the food", serviceFeePercent)},
+	})
+
+	km := fakeDistanceKm(rest.ID, in.Address)
+	switch {
+	case rest.Delivery == nil:
+		out.Delivery.Message = "this restaurant does not offer delivery, dine-in only"
+	case km > rest.Delivery.MaxDistanceKm:
+		out.Delivery.Message = fmt.Sprintf("the address is %.1f km away, out of the delivery range of %.0f km, dine-in only", km, rest.Delivery.MaxDistanceKm)
+	default:
+		items := []TripCostItem{
+			{Item: "food", Amount: out.Food, Note: fmt.Sprintf("%d dish(es)", len(in.DishNames)-len(out.Skipped))},
+		}
+		if short := rest.Delivery.MinOrder - food; short > 0 {
+			items = append(items, TripCostItem{Item: "minimum order top-up", Amount: float64(short), Note: fmt.Sprintf("the minimum order is %d yuan", rest.Delivery.MinOrder)})
+		}
+		items = append(items, TripCostItem{Item: "delivery fee", Amount: float64(deliveryFee(rest.Delivery, km)), Note: fmt.Sprintf("%.1f km", km)})
+		out.Delivery = mealCostOption(in.PartySize, items)
+	}
+
+	switch {
+	case !out.Delivery.Available:
+		out.Recommendation = "dine_in"
+		out.Message = out.Delivery.Message
+	case out.Delivery.Total < out.DineIn.Total:
+		out.Recommendation = "delivery"
+		out.Message = fmt.Sprintf("delivery is %.2f yuan cheaper", out.DineIn.Total-out.Delivery.Total)
+	default:
+		// 一样贵时推荐堂食, 菜是现做的
+		out.Recommendation = "dine_in"
+		out.Message = fmt.Sprintf("dine-in is %.2f yuan cheaper", out.Delivery.Total-out.DineIn.Total)
+	}
+	return out, nil
+}
+
+// mealCostOption 按分累加 items (同 EstimateTripCost).
+func mealCostOption(partySize int, items []TripCostItem) MealCostOption {
+	var totalCents int64
+	for _, it := range items {
+		totalCents += int64(math.Round(it.Amount * 100))
+	}
+	total := float64(totalCents) / 100
+	return MealCostOption{Available: true, Items: items, Total: total, PerPerson: roundYuan(total / float64(partySize))}
+}
@@ -0,0 +1,67 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tools
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompareDeliveryDineIn(t *testing.T) {
+	ctx := context.Background()
+	svc := &fakeService{repo: database}
+
+	// 2001 到空地址 2.5 km, 配送费 6+3*2=12, 没有起送金额; 堂食的 10% 服务费更贵
+	cmp, err := svc.CompareDeliveryDineIn(ctx, &DeliveryVsDineInParam{RestaurantID: "2001", PartySize: 2, DishNames: []string{"糖渍🐟", "糖醋西红柿"}})
+	assert.NoError(t, err)
+	assert.Equal(t, 179.0, cmp.Food)
+	assert.Equal(t, 196.9, cmp.DineIn.Total)
+	assert.Equal(t, 98.45, cmp.DineIn.PerPerson)
+	assert.True(t, cmp.Delivery.Available)
+	assert.Equal(t, 191.0, cmp.Delivery.Total)
+	assert.Equal(t, "delivery", cmp.Recommendation)
+	assert.Equal(t, "delivery is 5.90 yuan cheaper", cmp.Message)
+
+	// 1001 到人民广场 3.7 km, 配送费 5+4*2=13; 20 元不够 30 元的起送金额, 补足 10 元
+	cmp, err = svc.CompareDeliveryDineIn(ctx, &DeliveryVsDineInParam{RestaurantID: "1001", PartySize: 1, DishNames: []string{"红烧肉", "不存在的菜"}, Address: "人民广场"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"不存在的菜"}, cmp.Skipped)
+	assert.Equal(t, 22.0, cmp.DineIn.Total)
+	assert.Equal(t, 43.0, cmp.Delivery.Total)
+	assert.Equal(t, "minimum order top-up", cmp.Delivery.Items[1].Item)
+	assert.Equal(t, 10.0, cmp.Delivery.Items[1].Amount)
+	assert.Equal(t, "dine_in", cmp.Recommendation)
+
+	// 不提供外卖, 或者超出配送范围时只能堂食
+	cmp, err = svc.CompareDeliveryDineIn(ctx, &DeliveryVsDineInParam{RestaurantID: "1003", PartySize: 2, DishNames: []string{"超级红烧肉"}})
+	assert.NoError(t, err)
+	assert.False(t, cmp.Delivery.Available)
+	assert.Equal(t, "dine_in", cmp.Recommendation)
+	assert.Contains(t, cmp.Message, "does not offer delivery")
+
+	cmp, err = svc.CompareDeliveryDineIn(ctx, &DeliveryVsDineInParam{RestaurantID: "1001", PartySize: 2, DishNames: []string{"红烧肉"}})
+	assert.NoError(t, err)
+	assert.False(t, cmp.Delivery.Available)
+	assert.Contains(t, cmp.Message, "out of the delivery range")
+
+	_, err = svc.CompareDeliveryDineIn(ctx, &DeliveryVsDineInParam{RestaurantID: "1001", PartySize: 2, DishNames: []string{"不存在的菜"}})
+	assert.ErrorContains(t, err, "none of the dishes")
+	_, err = svc.CompareDeliveryDineIn(ctx, &DeliveryVsDineInParam{RestaurantID: "1001", PartySize: 0, DishNames: []string{"红烧肉"}})
+	assert.ErrorContains(t, err, "party_size")
+}
@@ -0,0 +1,196 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+
+	"github.com/cloudwego/eino/components/tool"
+	"github.com/cloudwego/eino/schema"
+)
+
+func GetDirectionsTool() tool.InvokableTool {
+	return safeTool{
+		InvokableTool: NewCancellableTool(&ToolDirections{
+			backService: restService,
+		}),
+	}
+}
+
+// ToolDirections 根据两家餐厅的坐标给出距离和一段逐步的路线描述, 把结构化的数据组织成模型可以直接转述的文字.
+// 路线是按直线距离和方位编出来的, 只用于演示.
+type ToolDirections struct {
+	backService *fakeService // fake service
+}
+
+func (t *ToolDirections) Info(ctx context.Context) (*schema.ToolInfo, error) {
+	return &schema.ToolInfo{
+		Name: "get_directions",
+		Desc: "Get the distance and step by step directions from one restaurant to another, e.g. for dinner followed by dessert somewhere else",
+		ParamsOneOf: schema.NewParamsOneOfByParams(map[string]*schema.ParameterInfo{
+			"from_restaurant_id": {
+				Type:     "string",
+				Desc:     "The id of the restaurant to start from",
+				Required: true,
+			},
+			"to_restaurant_id": {
+				Type:     "string",
+				Desc:     "The id of the restaurant to go to",
+				Required: true,
+			},
+		}),
+	}, nil
+}
+
+func (t *ToolDirections) InvokableRun(ctx context.Context, argumentsInJSON string, opts ...tool.Option) (string, error) {
+	// 解析参数
+	if err := checkRequired(ctx, t, argumentsInJSON); err != nil {
+		return "", err
+	}
+	p := &DirectionsParam{}
+	err := json.Unmarshal([]byte(argumentsInJSON), p)
+	if err != nil {
+		return "", err
+	}
+
+	// 请求后端服务
+	d, err := t.backService.GetDirections(ctx, p)
+	if err != nil {
+		return "", err
+	}
+
+	// 序列化结果
+	res, err := marshalResult(d)
+	if err != nil {
+		return "", err
+	}
+
+	return string(res), nil
+}
+
+type DirectionsParam struct {
+	FromRestaurantID string `json:"from_restaurant_id"`
+	ToRestaurantID   string `json:"to_restaurant_id"`
+}
+
+type Directions struct {
+	From       string  `json:"from"`
+	To         string  `json:"to"`
+	DistanceKm float64 `json:"distance_km"`
+	// Mode 是建议的出行方式: walk, drive 或 intercity (不在同一个城市)
+	Mode             string   `json:"mode,omitempty"`
+	EstimatedMinutes int      `json:"estimated_minutes,omitempty"`
+	Steps            []string `json:"steps"`
+	Message          string   `json:"message,omitempty"`
+}
+
+const (
+	walkMaxKm  = 1.5 // 超过这个距离建议打车
+	driveMaxKm = 50  // 超过这个距离认为不在同一个城市
+)
+
+// GetDirections 返回从 in.FromRestaurantID 到 in.ToRestaurantID 的路线, 两家餐厅相同时直接告诉模型已经到了.
+func (ft *fakeService) GetDirections(ctx context.Context, in *DirectionsParam) (*Directions, error) {
+	if err := ft.simulateLatency(ctx); err != nil {
+		return nil, err
+	}
+
+	from, err := ft.repo.GetRestaurantByID(ctx, in.FromRestaurantID)
+	if err != nil {
+		return nil, err
+	}
+	to, err := ft.repo.GetRestaurantByID(ctx, in.ToRestaurantID)
+	if err != nil {
+		return nil, err
+	}
+	if from.ID == to.ID {
+		return &Directions{
+			From:    from.Name,
+			To:      to.Name,
+			Steps:   []string{},
+			Message: "you're already here: both ids are the same restaurant",
+		}, nil
+	}
+	for _, rest := range []restaurantDataItem{from, to} {
+		if rest.Geo == nil {
+			return nil, fmt.Errorf("restaurant %s has no published location", rest.ID)
+		}
+	}
+
+	km := haversineKm(from.Geo.Lat, from.Geo.Lng, to.Geo.Lat, to.Geo.Lng)
+	d := &Directions{From: from.Name, To: to.Name, DistanceKm: math.Round(km*10) / 10}
+	heading := compassDirection(bearing(from.Geo.Lat, from.Geo.Lng, to.Geo.Lat, to.Geo.Lng))
+	switch {
+	case km <= walkMaxKm:
+		d.Mode = "walk"
+		d.EstimatedMinutes = int(math.Ceil(km * 12)) // 步行约 5 km/h
+		d.Steps = []string{
+			fmt.Sprintf("从%s (%s) 出门", from.Name, from.Place),
+			fmt.Sprintf("向%s步行约 %d 米", heading, int(math.Round(km*1000/10))*10),
+			fmt.Sprintf("到达%s (%s)", to.Name, to.Place),
+		}
+	case km <= driveMaxKm:
+		d.Mode = "drive"
+		d.EstimatedMinutes = int(math.Ceil(km*2)) + 5 // 市区约 30 km/h, 另加等车的时间
+		d.Steps = []string{
+			fmt.Sprintf("在%s (%s) 门口打车", from.Name, from.Place),
+			fmt.Sprintf("向%s行驶约 %.1f 公里", heading, d.DistanceKm),
+			fmt.Sprintf("在%s附近下车, 到达%s", to.Place, to.Name),
+		}
+	default:
+		d.Mode = "intercity"
+		d.Steps = []string{
+			fmt.Sprintf("%s在%s, %s在%s, 两地相距约 %.0f 公里", from.Name, from.Place, to.Name, to.Place, km),
+			"建议乘坐高铁或飞机前往",
+		}
+		d.Message = "the restaurants are in different cities"
+	}
+	return d, nil
+}
+
+const earthRadiusKm = 6371
+
+// haversineKm 返回两个坐标之间的球面距离.
+func haversineKm(lat1, lng1, lat2, lng2 float64) float64 {
+	dLat := radians(lat2 - lat1)
+	dLng := radians(lng2 - lng1)
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(radians(lat1))*math.Cos(radians(lat2))*math.Sin(dLng/2)*math.Sin(dLng/2)
+	return 2 * earthRadiusKm * math.Asin(math.Sqrt(a))
+}
+
+// bearing 返回从第一个坐标到第二个坐标的初始方位角, 正北为 0, 顺时针 0 - 360 度.
+func bearing(lat1, lng1, lat2, lng2 float64) float64 {
+	dLng := radians(lng2 - lng1)
+	y := math.Sin(dLng) * math.Cos(radians(lat2))
+	x := math.Cos(radians(lat1))*math.Sin(radians(lat2)) - math.Sin(radians(lat1))*math.Cos(radians(lat2))*math.Cos(dLng)
+	deg := math.Atan2(y, x) * 180 / math.Pi
+	return math.Mod(deg+360, 360)
+}
+
+var compassDirections = []string{"北", "东北", "东", "东南", "南", "西南", "西", "西北"}
+
+func compassDirection(deg float64) string {
+	return compassDirections[int(math.Round(deg/45))%len(compassDirections)]
+}
+
+func radians(deg float64) float64 {
+	return deg * math.Pi / 180
+}
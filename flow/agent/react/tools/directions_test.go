@@ -0,0 +1,65 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tools
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetDirections(t *testing.T) {
+	ctx := context.Background()
+
+	d, err := restService.GetDirections(ctx, &DirectionsParam{FromRestaurantID: "1001", ToRestaurantID: "1002"})
+	assert.NoError(t, err)
+	assert.Equal(t, "云边小馆", d.From)
+	assert.Equal(t, "drive", d.Mode)
+	assert.InDelta(t, 5.5, d.DistanceKm, 0.2)
+	assert.Greater(t, d.EstimatedMinutes, 0)
+	assert.Len(t, d.Steps, 3)
+	assert.Contains(t, d.Steps[1], "东北")
+
+	d, err = restService.GetDirections(ctx, &DirectionsParam{FromRestaurantID: "1001", ToRestaurantID: "2001"})
+	assert.NoError(t, err)
+	assert.Equal(t, "intercity", d.Mode)
+	assert.Greater(t, d.DistanceKm, 1000.0)
+
+	d, err = restService.GetDirections(ctx, &DirectionsParam{FromRestaurantID: "1002", ToRestaurantID: "1002"})
+	assert.NoError(t, err)
+	assert.Contains(t, d.Message, "already here")
+	assert.Zero(t, d.DistanceKm)
+	assert.Empty(t, d.Steps)
+
+	// 没有坐标的餐厅
+	_, err = restService.GetDirections(ctx, &DirectionsParam{FromRestaurantID: "1001", ToRestaurantID: "2010"})
+	assert.ErrorContains(t, err, "no published location")
+	_, err = restService.GetDirections(ctx, &DirectionsParam{FromRestaurantID: "404", ToRestaurantID: "1001"})
+	assert.Error(t, err)
+}
+
+func TestHaversine(t *testing.T) {
+	assert.Zero(t, haversineKm(39.9, 116.4, 39.9, 116.4))
+	// 经度相差 1 度, 在赤道上约 111 km
+	assert.InDelta(t, 111.2, haversineKm(0, 0, 0, 1), 0.1)
+
+	assert.Equal(t, "东", compassDirection(bearing(0, 0, 0, 1)))
+	assert.Equal(t, "北", compassDirection(bearing(0, 0, 1, 0)))
+	assert.Equal(t, "北", compassDirection(359))
+	assert.Equal(t, "西南", compassDirection(bearing(1, 1, 0, 0)))
+}
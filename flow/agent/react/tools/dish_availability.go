@@ -0,0 +1,153 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"time"
+
+	"github.com/cloudwego/eino/components/tool"
+	"github.com/cloudwego/eino/schema"
+)
+
+func GetDishAvailabilityTool() tool.InvokableTool {
+	return safeTool{
+		InvokableTool: NewCancellableTool(&ToolDishAvailability{
+			backService: restService,
+		}),
+	}
+}
+
+// ToolDishAvailability 查询一道菜现在是否还有, 避免模型推荐已经卖完的菜. 库存是按小时变化的 fake 数据 (见 dishInStock),
+// "现在" 来自 fake 后端的 clock. query_dishes 的 in_stock_only 使用同一套数据.
+type ToolDishAvailability struct {
+	backService *fakeService // fake service
+}
+
+func (t *ToolDishAvailability) Info(ctx context.Context) (*schema.ToolInfo, error) {
+	return &schema.ToolInfo{
+		Name: "query_dish_availability",
+		Desc: "Check whether a dish of a restaurant is in stock right now, and when it is expected to be back if it sold out",
+		ParamsOneOf: schema.NewParamsOneOfByParams(map[string]*schema.ParameterInfo{
+			"restaurant_id": {
+				Type:     "string",
+				Desc:     "The id of one restaurant",
+				Required: true,
+			},
+			"dish_name": {
+				Type:     "string",
+				Desc:     "The name of the dish as returned by query_dishes",
+				Required: true,
+			},
+		}),
+	}, nil
+}
+
+func (t *ToolDishAvailability) InvokableRun(ctx context.Context, argumentsInJSON string, opts ...tool.Option) (string, error) {
+	// 解析参数
+	if err := checkRequired(ctx, t, argumentsInJSON); err != nil {
+		return "", err
+	}
+	p := &DishAvailabilityParam{}
+	err := json.Unmarshal([]byte(argumentsInJSON), p)
+	if err != nil {
+		return "", err
+	}
+
+	// 请求后端服务
+	availability, err := t.backService.DishAvailability(ctx, p)
+	if err != nil {
+		return "", err
+	}
+
+	// 序列化结果
+	res, err := marshalResult(availability)
+	if err != nil {
+		return "", err
+	}
+
+	return string(res), nil
+}
+
+type DishAvailabilityParam struct {
+	RestaurantID string `json:"restaurant_id"`
+	DishName     string `json:"dish_name"`
+}
+
+// DishAvailability 中 restock_at 只在卖完时出现, 是预计重新供应的时间.
+type DishAvailability struct {
+	RestaurantID string `json:"restaurant_id"`
+	Dish         string `json:"dish"`
+	InStock      bool   `json:"in_stock"`
+	RestockAt    string `json:"restock_at,omitempty"` // RFC3339
+	Message      string `json:"message,omitempty"`
+}
+
+// restockSearchHours 是向后查找重新供应时间的范围, 超过时认为今天不会再有.
+const restockSearchHours = 24
+
+// DishAvailability 查询一道菜在当前小时是否有货, 卖完时向后逐小时查找第一个有货的小时作为 restock_at.
+func (ft *fakeService) DishAvailability(ctx context.Context, in *DishAvailabilityParam) (*DishAvailability, error) {
+	if err := ft.simulateLatency(ctx); err != nil {
+		return nil, err
+	}
+
+	rest, err := ft.repo.GetRestaurantByID(ctx, in.RestaurantID)
+	if err != nil {
+		return nil, err
+	}
+	dish, ok := findDish(rest.Dishes, in.DishName)
+	if !ok {
+		return nil, fmt.Errorf("dish %q not found in restaurant %s", in.DishName, in.RestaurantID)
+	}
+
+	now := ft.now()
+	out := &DishAvailability{RestaurantID: rest.ID, Dish: dish.Name, InStock: dishInStock(rest.ID, dish.Name, now)}
+	if out.InStock {
+		return out, nil
+	}
+	hour := now.Truncate(time.Hour)
+	for i := 1; i <= restockSearchHours; i++ {
+		if at := hour.Add(time.Duration(i) * time.Hour); dishInStock(rest.ID, dish.Name, at) {
+			out.RestockAt = at.Format(time.RFC3339)
+			out.Message = "sold out for now, recommend another dish or suggest coming back after restock_at"
+			return out, nil
+		}
+	}
+	out.Message = fmt.Sprintf("sold out, not expected back within %d hours", restockSearchHours)
+	return out, nil
+}
+
+// dishInStock 按餐厅、菜名和 at 所在的小时决定一道菜是否卖完, 同一个小时内结果不变.
+// 餐厅越忙越容易卖完: 卖完的概率是 5% 加上繁忙程度的五分之一.
+func dishInStock(restaurantID, dishName string, at time.Time) bool {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(restaurantID + "\x00" + dishName + "\x00" + at.Format("2006-01-02 15")))
+	soldOutPercent := 5 + busyness(restaurantID, at.Weekday(), at.Hour())/5
+	return int(h.Sum32()%100) >= soldOutPercent
+}
+
+// inStock 返回判断 restaurantID 的一道菜现在是否有货的函数, 供 query_dishes 的 in_stock_only 使用.
+func (ft *fakeService) inStock(restaurantID string) func(restaurantDishDataItem) bool {
+	now := ft.now()
+	return func(dish restaurantDishDataItem) bool {
+		return dishInStock(restaurantID, dish.Name, now)
+	}
+}
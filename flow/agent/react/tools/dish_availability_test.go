@@ -0,0 +1,85 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tools
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDishAvailability(t *testing.T) {
+	ctx := context.Background()
+	loc := time.FixedZone("CST", 8*3600)
+	// 2024-06-01 是周六, 清泉牛肉在 19 点和 20 点卖完
+	svc := &fakeService{repo: restService.repo, clock: FixedClock{T: time.Date(2024, 6, 1, 19, 30, 0, 0, loc)}}
+
+	out, err := svc.DishAvailability(ctx, &DishAvailabilityParam{RestaurantID: "1001", DishName: "清泉牛肉"})
+	assert.NoError(t, err)
+	assert.False(t, out.InStock)
+	assert.Equal(t, time.Date(2024, 6, 1, 21, 0, 0, 0, loc).Format(time.RFC3339), out.RestockAt)
+	assert.Contains(t, out.Message, "sold out")
+
+	out, err = svc.DishAvailability(ctx, &DishAvailabilityParam{RestaurantID: "1001", DishName: "红烧肉"})
+	assert.NoError(t, err)
+	assert.True(t, out.InStock)
+	assert.Empty(t, out.RestockAt)
+
+	_, err = svc.DishAvailability(ctx, &DishAvailabilityParam{RestaurantID: "1001", DishName: "佛跳墙"})
+	assert.ErrorContains(t, err, "not found")
+	_, err = svc.DishAvailability(ctx, &DishAvailabilityParam{RestaurantID: "404", DishName: "红烧肉"})
+	assert.Error(t, err)
+}
+
+func TestDishInStockPerHour(t *testing.T) {
+	loc := time.FixedZone("CST", 8*3600)
+	// 同一个小时内结果不变
+	assert.Equal(t,
+		dishInStock("1001", "清泉牛肉", time.Date(2024, 6, 1, 19, 0, 0, 0, loc)),
+		dishInStock("1001", "清泉牛肉", time.Date(2024, 6, 1, 19, 59, 0, 0, loc)))
+	assert.False(t, dishInStock("1001", "清泉牛肉", time.Date(2024, 6, 1, 19, 0, 0, 0, loc)))
+	assert.True(t, dishInStock("1001", "清泉牛肉", time.Date(2024, 6, 1, 21, 0, 0, 0, loc)))
+}
+
+func TestQueryDishesInStockOnly(t *testing.T) {
+	ctx := context.Background()
+	loc := time.FixedZone("CST", 8*3600)
+	svc := &fakeService{repo: restService.repo, clock: FixedClock{T: time.Date(2024, 6, 1, 19, 30, 0, 0, loc)}}
+	names := func(dishes []Dish) []string {
+		var res []string
+		for _, d := range dishes {
+			res = append(res, d.Name)
+		}
+		return res
+	}
+
+	all, err := svc.QueryDishes(ctx, &QueryDishesParam{RestaurantID: "1001", Topn: 10})
+	assert.NoError(t, err)
+	assert.Contains(t, names(all), "清泉牛肉")
+
+	inStock, err := svc.QueryDishes(ctx, &QueryDishesParam{RestaurantID: "1001", Topn: 10, InStockOnly: true})
+	assert.NoError(t, err)
+	assert.Len(t, inStock, len(all)-1)
+	assert.NotContains(t, names(inStock), "清泉牛肉")
+
+	page, err := svc.QueryDishesPage(ctx, &QueryDishesParam{RestaurantID: "1001", Page: 1, PageSize: 10, InStockOnly: true})
+	assert.NoError(t, err)
+	assert.Equal(t, len(all)-1, page.Total)
+	assert.NotContains(t, names(page.Results), "清泉牛肉")
+}
@@ -0,0 +1,176 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/fnv"
+
+	"github.com/cloudwego/eino/components/tool"
+	"github.com/cloudwego/eino/schema"
+)
+
+func GetDishOfTheDayTool() tool.InvokableTool {
+	return safeTool{
+		InvokableTool: NewCancellableTool(&ToolDishOfTheDay{
+			backService: restService,
+		}),
+	}
+}
+
+// ToolDishOfTheDay 返回今日推荐的菜, 由 fake 后端的当前日期决定, 同一天内多次调用结果相同.
+type ToolDishOfTheDay struct {
+	backService *fakeService // fake service
+}
+
+func (t *ToolDishOfTheDay) Info(ctx context.Context) (*schema.ToolInfo, error) {
+	return &schema.ToolInfo{
+		Name: "get_dish_of_the_day",
+		Desc: "Get today's featured dish with the reason it is featured, the same for the whole day. Without restaurant_id the dish is picked across all restaurants",
+		ParamsOneOf: schema.NewParamsOneOfByParams(map[string]*schema.ParameterInfo{
+			"restaurant_id": {
+				Type: "string",
+				Desc: "The id of one restaurant, optional",
+			},
+		}),
+	}, nil
+}
+
+func (t *ToolDishOfTheDay) InvokableRun(ctx context.Context, argumentsInJSON string, opts ...tool.Option) (string, error) {
+	// 解析参数
+	p := &DishOfTheDayParam{}
+	err := json.Unmarshal([]byte(argumentsInJSON), p)
+	if err != nil {
+		return "", err
+	}
+
+	// 请求后端服务
+	featured, err := t.backService.GetDishOfTheDay(ctx, p)
+	if err != nil {
+		return "", err
+	}
+
+	// 序列化结果
+	res, err := marshalResult(featured)
+	if err != nil {
+		return "", err
+	}
+
+	return string(res), nil
+}
+
+type DishOfTheDayParam struct {
+	RestaurantID string `json:"restaurant_id"`
+}
+
+type DishOfTheDay struct {
+	Date           string `json:"date"`
+	RestaurantID   string `json:"restaurant_id"`
+	RestaurantName string `json:"restaurant_name"`
+	Dish           Dish   `json:"dish"`
+	WhyFeatured    string `json:"why_featured"`
+}
+
+// featuredMinScore 以上的菜才会被选为今日推荐, 都不满足时从全部菜中选.
+const featuredMinScore = 8
+
+type featuredCandidate struct {
+	rest restaurantDataItem
+	dish restaurantDishDataItem
+}
+
+// GetDishOfTheDay 以当前日期和 restaurant_id 为种子选出今日推荐, 日期来自 fake 后端的 clock.
+func (ft *fakeService) GetDishOfTheDay(ctx context.Context, in *DishOfTheDayParam) (*DishOfTheDay, error) {
+	if err := ft.simulateLatency(ctx); err != nil {
+		return nil, err
+	}
+
+	var rests []restaurantDataItem
+	if in.RestaurantID != "" {
+		rest, err := ft.repo.GetRestaurantByID(ctx, in.RestaurantID)
+		if err != nil {
+			return nil, err
+		}
+		rests = []restaurantDataItem{rest}
+	} else {
+		all, err := ft.repo.GetRestaurants(ctx, "")
+		if err != nil {
+			return nil, err
+		}
+		rests = all
+	}
+
+	candidates := featuredCandidates(rests)
+	if len(candidates) == 0 {
+		return nil, errors.New("no dishes to feature")
+	}
+
+	date := ft.now().Format("2006-01-02")
+	c := candidates[daySeed(date, in.RestaurantID)%uint32(len(candidates))]
+
+	return &DishOfTheDay{
+		Date:           date,
+		RestaurantID:   c.rest.ID,
+		RestaurantName: c.rest.Name,
+		Dish: Dish{
+			Name:      c.dish.Name,
+			Desc:      c.dish.Desc,
+			Price:     c.dish.Price,
+			Score:     c.dish.Score,
+			Allergens: c.dish.Allergens,
+			Nutrition: toNutrition(c.dish.Nutrition),
+
+			PrepMinutes: c.dish.PrepMinutes,
+		},
+		WhyFeatured: whyFeatured(c.rest, c.dish),
+	}, nil
+}
+
+// featuredCandidates 按餐厅和菜单的顺序返回可以被推荐的菜, 顺序固定才能保证同一个种子选出同一道菜.
+func featuredCandidates(rests []restaurantDataItem) []featuredCandidate {
+	var good, all []featuredCandidate
+	for _, rest := range rests {
+		for _, dish := range rest.Dishes {
+			c := featuredCandidate{rest: rest, dish: dish}
+			all = append(all, c)
+			if dish.Score >= featuredMinScore {
+				good = append(good, c)
+			}
+		}
+	}
+	if len(good) > 0 {
+		return good
+	}
+	return all
+}
+
+func daySeed(date, restaurantID string) uint32 {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(date + "|" + restaurantID))
+	return h.Sum32()
+}
+
+func whyFeatured(rest restaurantDataItem, dish restaurantDishDataItem) string {
+	reason := fmt.Sprintf("today's pick at %s: %s, rated %d/10 for %d yuan", rest.Name, dish.Desc, dish.Score, dish.Price)
+	if dish.PrepMinutes > 0 {
+		reason += fmt.Sprintf(", ready in about %d minutes", dish.PrepMinutes)
+	}
+	return reason
+}
@@ -0,0 +1,68 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tools
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetDishOfTheDay(t *testing.T) {
+	ctx := context.Background()
+	svc := &fakeService{repo: database, clock: FixedClock{T: time.Date(2025, 6, 1, 9, 0, 0, 0, time.Local)}}
+
+	// 同一天内结果不变
+	morning, err := svc.GetDishOfTheDay(ctx, &DishOfTheDayParam{RestaurantID: "1001"})
+	assert.NoError(t, err)
+	assert.Equal(t, "2025-06-01", morning.Date)
+	assert.Equal(t, "1001", morning.RestaurantID)
+	assert.GreaterOrEqual(t, morning.Dish.Score, featuredMinScore)
+	assert.Contains(t, morning.WhyFeatured, "云边小馆")
+
+	svc.clock = FixedClock{T: time.Date(2025, 6, 1, 21, 0, 0, 0, time.Local)}
+	evening, err := svc.GetDishOfTheDay(ctx, &DishOfTheDayParam{RestaurantID: "1001"})
+	assert.NoError(t, err)
+	assert.Equal(t, morning, evening)
+
+	// 不指定餐厅时从所有餐厅中选, 换一天会换一道菜
+	picked := map[string]bool{}
+	for day := 1; day <= 14; day++ {
+		svc.clock = FixedClock{T: time.Date(2025, 6, day, 12, 0, 0, 0, time.Local)}
+		featured, err := svc.GetDishOfTheDay(ctx, &DishOfTheDayParam{})
+		assert.NoError(t, err)
+		picked[featured.RestaurantID+"/"+featured.Dish.Name] = true
+	}
+	assert.Greater(t, len(picked), 1)
+
+	_, err = svc.GetDishOfTheDay(ctx, &DishOfTheDayParam{RestaurantID: "404"})
+	assert.Error(t, err)
+}
+
+func TestFeaturedCandidates(t *testing.T) {
+	rests := []restaurantDataItem{{ID: "1", Dishes: []restaurantDishDataItem{{Name: "a", Score: 9}, {Name: "b", Score: 5}}}}
+	candidates := featuredCandidates(rests)
+	assert.Len(t, candidates, 1)
+	assert.Equal(t, "a", candidates[0].dish.Name)
+
+	// 没有高分的菜时从全部菜中选
+	rests[0].Dishes[0].Score = 3
+	assert.Len(t, featuredCandidates(rests), 2)
+	assert.Empty(t, featuredCandidates(nil))
+}
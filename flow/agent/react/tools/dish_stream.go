@@ -0,0 +1,89 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tools
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/cloudwego/eino/components/tool"
+	"github.com/cloudwego/eino/schema"
+)
+
+// GetDishStreamTool 返回流式的 query_dishes: 参数和筛选与 GetDishTool 相同 (不支持分页), 但按评分从高到低每道菜输出一帧,
+// 每帧是一个 JSON 编码的 Dish 加换行, 下游可以边收边展示. 它用来替换 GetDishTool, 名称相同, 不要同时注册.
+//
+// 外面包装的是 NewSafeStreamTool: 开始输出之前的错误 (参数错误、后端失败) 转成只有一帧的 stream,
+// 输出过程中的错误转成最后一帧, 之前的菜保留. 和 format_menu 一样, 只支持 InvokableRun 的包装 (缓存、guardTool 等) 都用不上.
+func GetDishStreamTool() tool.StreamableTool {
+	return NewSafeStreamTool(&ToolQueryDishesStream{backService: restService})
+}
+
+// ToolQueryDishesStream 是 ToolQueryDishes 的流式版本, 见 GetDishStreamTool.
+type ToolQueryDishesStream struct {
+	backService *fakeService // fake service
+}
+
+func (t *ToolQueryDishesStream) Info(ctx context.Context) (*schema.ToolInfo, error) {
+	params := queryDishesParams()
+	delete(params, "page")
+	delete(params, "page_size")
+	return &schema.ToolInfo{
+		Name:        "query_dishes",
+		Desc:        "查询一家餐厅有哪些菜品, 按评分从高到低逐个返回",
+		ParamsOneOf: schema.NewParamsOneOfByParams(params),
+	}, nil
+}
+
+// StreamableRun 在开始输出之前查出全部的菜, 后端已经按评分从高到低排好并取了 topn.
+// 之后每一帧之间都会检查 ctx, 被取消时以 ctx 的错误结束 stream. 没有查到菜时只输出一帧, 内容和 ToolQueryDishes 的空结果相同.
+func (t *ToolQueryDishesStream) StreamableRun(ctx context.Context, argumentsInJSON string, opts ...tool.Option) (*schema.StreamReader[string], error) {
+	// 解析参数
+	p, err := parseQueryDishes(ctx, t, argumentsInJSON, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	// 请求后端服务
+	dishes, err := t.backService.QueryDishes(ctx, p)
+	if err != nil {
+		return nil, err
+	}
+	if len(dishes) == 0 {
+		return schema.StreamReaderFromArray([]string{noDishesResult(ctx, t.backService, p)}), nil
+	}
+
+	sr, sw := schema.Pipe[string](1)
+	go func() {
+		defer sw.Close()
+		for _, dish := range dishes {
+			if err := t.backService.simulateLatency(ctx); err != nil {
+				sw.Send("", err)
+				return
+			}
+			b, err := json.Marshal(dish)
+			if err != nil {
+				sw.Send("", err)
+				return
+			}
+			if closed := sw.Send(string(b)+"\n", nil); closed {
+				return
+			}
+		}
+	}()
+	return sr, nil
+}
@@ -0,0 +1,93 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/cloudwego/eino/components/tool"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDishStreamTool(t *testing.T) {
+	ctx := context.Background()
+	collect := func(args string) ([]string, *ToolExecutionState) {
+		state := &ToolExecutionState{}
+		sr, err := GetDishStreamTool().StreamableRun(SetToolState(ctx, state), args)
+		assert.NoError(t, err)
+		defer sr.Close()
+		var frames []string
+		for {
+			frame, err := sr.Recv()
+			if errors.Is(err, io.EOF) {
+				return frames, state
+			}
+			assert.NoError(t, err)
+			frames = append(frames, frame)
+		}
+	}
+
+	// 每帧一道菜, 按评分从高到低, 评分相同的保持后端返回的顺序
+	frames, state := collect(`{"restaurant_id":"1001"}`)
+	assert.True(t, state.Success)
+	var dishes []Dish
+	for _, frame := range frames {
+		var dish Dish
+		assert.NoError(t, json.Unmarshal([]byte(frame), &dish), frame)
+		dishes = append(dishes, dish)
+	}
+	var scores []int
+	for _, dish := range dishes {
+		scores = append(scores, dish.Score)
+	}
+	assert.Equal(t, []int{9, 9, 8, 8, 5}, scores)
+
+	// 和非流式的 query_dishes 是同样的菜, 顺序也相同
+	out, err := (&ToolQueryDishes{backService: restService}).InvokableRun(ctx, `{"restaurant_id":"1001"}`)
+	assert.NoError(t, err)
+	var want []Dish
+	assert.NoError(t, json.Unmarshal([]byte(out), &want))
+	assert.Equal(t, want, dishes)
+
+	// topn 取的是评分最高的几道菜, 不是随便几道中评分最高的
+	frames, _ = collect(`{"restaurant_id":"1001","topn":2}`)
+	if assert.Len(t, frames, 2) {
+		for i, frame := range frames {
+			var dish Dish
+			assert.NoError(t, json.Unmarshal([]byte(frame), &dish), frame)
+			assert.Equal(t, dishes[i], dish)
+			assert.Equal(t, 9, dish.Score)
+		}
+	}
+
+	// 开始输出之前的错误经过 NewSafeStreamTool 变成只有一帧的 stream
+	frames, state = collect(`{"restaurant_id":"1001","topn":"two"}`)
+	assert.Len(t, frames, 1)
+	assert.Contains(t, frames[0], "malformed arguments")
+	assert.False(t, state.Success)
+
+	// 只实现了 StreamableTool, 没有分页参数
+	_, invokable := GetDishStreamTool().(tool.InvokableTool)
+	assert.False(t, invokable)
+	fields, err := argumentFields(ctx, GetDishStreamTool())
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"restaurant_id (required)", "ignore_preferences", "in_stock_only", "topn"}, fields)
+}
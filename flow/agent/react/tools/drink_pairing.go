@@ -0,0 +1,187 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/cloudwego/eino/components/tool"
+	"github.com/cloudwego/eino/schema"
+)
+
+func GetDrinkPairingTool() tool.InvokableTool {
+	return safeTool{
+		InvokableTool: NewCancellableTool(&ToolDrinkPairing{
+			backService: restService,
+		}),
+	}
+}
+
+// ToolDrinkPairing 按菜的口味特征 (见 dishFeatures) 和餐厅的菜系, 从一张固定的搭配表中查出推荐的饮品.
+type ToolDrinkPairing struct {
+	backService *fakeService // fake service
+}
+
+func (t *ToolDrinkPairing) Info(ctx context.Context) (*schema.ToolInfo, error) {
+	return &schema.ToolInfo{
+		Name: "query_drink_pairing",
+		Desc: "Suggest wines and other drinks that pair well with a dish of a restaurant, each with the reason",
+		ParamsOneOf: schema.NewParamsOneOfByParams(map[string]*schema.ParameterInfo{
+			"restaurant_id": {
+				Type:     "string",
+				Desc:     "The id of one restaurant",
+				Required: true,
+			},
+			"dish_name": {
+				Type:     "string",
+				Desc:     "The name of the dish as returned by query_dishes",
+				Required: true,
+			},
+		}),
+	}, nil
+}
+
+func (t *ToolDrinkPairing) InvokableRun(ctx context.Context, argumentsInJSON string, opts ...tool.Option) (string, error) {
+	// 解析参数
+	if err := checkRequired(ctx, t, argumentsInJSON); err != nil {
+		return "", err
+	}
+	p := &DrinkPairingParam{}
+	err := json.Unmarshal([]byte(argumentsInJSON), p)
+	if err != nil {
+		return "", err
+	}
+
+	// 请求后端服务
+	pairing, err := t.backService.QueryDrinkPairing(ctx, p)
+	if err != nil {
+		return "", err
+	}
+
+	// 序列化结果
+	res, err := marshalResult(pairing)
+	if err != nil {
+		return "", err
+	}
+
+	return string(res), nil
+}
+
+type DrinkPairingParam struct {
+	RestaurantID string `json:"restaurant_id"`
+	DishName     string `json:"dish_name"`
+}
+
+// DrinkPairing 中 generic 为 true 表示搭配表中没有这道菜的特征和菜系, 返回的是通用的建议.
+type DrinkPairing struct {
+	RestaurantID string         `json:"restaurant_id"`
+	Dish         string         `json:"dish"`
+	Pairings     []DrinkSuggest `json:"pairings"`
+	Generic      bool           `json:"generic"`
+}
+
+type DrinkSuggest struct {
+	Drink  string `json:"drink"`
+	Reason string `json:"reason"`
+}
+
+// drinkPairingsByFeature 是按口味特征的搭配表, 按顺序匹配, 排在前面的特征更能决定搭配.
+var drinkPairingsByFeature = []struct {
+	feature string
+	drinks  []DrinkSuggest
+}{
+	{"spicy", []DrinkSuggest{
+		{Drink: "off-dry Riesling", Reason: "a touch of sweetness and low alcohol cool down the heat"},
+		{Drink: "iced plum juice (酸梅汤)", Reason: "sour and cold, a classic way to tame spicy food"},
+	}},
+	{"sour", []DrinkSuggest{
+		{Drink: "Sauvignon Blanc", Reason: "its crisp acidity matches sour flavors instead of clashing with them"},
+	}},
+	{"sweet", []DrinkSuggest{
+		{Drink: "Gewürztraminer", Reason: "aromatic and slightly sweet, it stands up to sweet and sour sauces"},
+	}},
+	{"braised", []DrinkSuggest{
+		{Drink: "aged Shaoxing rice wine", Reason: "the same wine is often used in the braise, its nutty depth echoes the sauce"},
+	}},
+	{"seafood", []DrinkSuggest{
+		{Drink: "Chablis", Reason: "a lean, mineral white that lets the seafood shine"},
+	}},
+	{"meat", []DrinkSuggest{
+		{Drink: "Pinot Noir", Reason: "soft tannins and red fruit go with rich meat without overpowering it"},
+	}},
+	{"vegetable", []DrinkSuggest{
+		{Drink: "green tea", Reason: "light and fresh, it keeps the delicate vegetable flavors clean"},
+	}},
+}
+
+// drinkPairingsByCuisine 是按菜系的补充搭配.
+var drinkPairingsByCuisine = map[string]DrinkSuggest{
+	"川菜":  {Drink: "cold lager", Reason: "a Sichuan restaurant classic, the bubbles refresh the palate between numbing bites"},
+	"湘菜":  {Drink: "cold lager", Reason: "a Hunan restaurant classic, cold and light against the chili"},
+	"本帮菜": {Drink: "warm Shaoxing rice wine", Reason: "the traditional companion of rich, sweet Shanghainese cooking"},
+	"京菜":  {Drink: "jasmine tea", Reason: "the everyday tea of Beijing, it cuts through roasted and fatty dishes"},
+}
+
+// genericDrinkPairing 是搭配表中什么都没有匹配到时的建议.
+var genericDrinkPairing = DrinkSuggest{Drink: "jasmine tea", Reason: "a neutral choice that goes with almost any Chinese dish"}
+
+// maxDrinkPairings 限制返回的搭配数量, 按特征的顺序优先.
+const maxDrinkPairings = 3
+
+// QueryDrinkPairing 先按菜的口味特征, 再按餐厅的菜系查搭配表, 相同的饮品只保留第一次出现的理由.
+func (ft *fakeService) QueryDrinkPairing(ctx context.Context, in *DrinkPairingParam) (*DrinkPairing, error) {
+	if err := ft.simulateLatency(ctx); err != nil {
+		return nil, err
+	}
+
+	rest, err := ft.repo.GetRestaurantByID(ctx, in.RestaurantID)
+	if err != nil {
+		return nil, err
+	}
+	dish, ok := findDish(rest.Dishes, in.DishName)
+	if !ok {
+		return nil, fmt.Errorf("dish %q not found in restaurant %s", in.DishName, in.RestaurantID)
+	}
+
+	out := &DrinkPairing{RestaurantID: rest.ID, Dish: dish.Name}
+	seen := map[string]bool{}
+	add := func(s DrinkSuggest) {
+		if !seen[s.Drink] && len(out.Pairings) < maxDrinkPairings {
+			seen[s.Drink] = true
+			out.Pairings = append(out.Pairings, s)
+		}
+	}
+
+	features := dishFeatures(dish)
+	for _, p := range drinkPairingsByFeature {
+		if features[p.feature] {
+			for _, s := range p.drinks {
+				add(s)
+			}
+		}
+	}
+	if s, ok := drinkPairingsByCuisine[rest.Cuisine]; ok {
+		add(s)
+	}
+	if len(out.Pairings) == 0 {
+		out.Generic = true
+		add(genericDrinkPairing)
+	}
+	return out, nil
+}
@@ -0,0 +1,61 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tools
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQueryDrinkPairing(t *testing.T) {
+	ctx := context.Background()
+	drinks := func(p *DrinkPairing) []string {
+		var res []string
+		for _, s := range p.Pairings {
+			res = append(res, s.Drink)
+		}
+		return res
+	}
+
+	// 酸辣: 先按特征的顺序, 最多 3 个
+	out, err := restService.QueryDrinkPairing(ctx, &DrinkPairingParam{RestaurantID: "1001", DishName: "酸辣土豆丝"})
+	assert.NoError(t, err)
+	assert.Equal(t, "酸辣土豆丝", out.Dish)
+	assert.Equal(t, []string{"off-dry Riesling", "iced plum juice (酸梅汤)", "Sauvignon Blanc"}, drinks(out))
+	assert.False(t, out.Generic)
+
+	// 没有特征也没有菜系的搭配时返回通用的建议
+	repo := &restaurantDatabase{restaurantByID: map[string]restaurantDataItem{
+		"9001": {ID: "9001", Cuisine: "粤菜", Dishes: []restaurantDishDataItem{{Name: "白粥"}}},
+	}}
+	out, err = (&fakeService{repo: repo}).QueryDrinkPairing(ctx, &DrinkPairingParam{RestaurantID: "9001", DishName: "白粥"})
+	assert.NoError(t, err)
+	assert.True(t, out.Generic)
+	assert.Equal(t, []DrinkSuggest{genericDrinkPairing}, out.Pairings)
+
+	// 只有菜系的搭配
+	repo.restaurantByID["9002"] = restaurantDataItem{ID: "9002", Cuisine: "京菜", Dishes: []restaurantDishDataItem{{Name: "白粥"}}}
+	out, err = (&fakeService{repo: repo}).QueryDrinkPairing(ctx, &DrinkPairingParam{RestaurantID: "9002", DishName: "白粥"})
+	assert.NoError(t, err)
+	assert.False(t, out.Generic)
+	assert.Equal(t, []string{"jasmine tea"}, drinks(out))
+
+	_, err = restService.QueryDrinkPairing(ctx, &DrinkPairingParam{RestaurantID: "1001", DishName: "佛跳墙"})
+	assert.ErrorContains(t, err, "not found")
+}
@@ -0,0 +1,204 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/cloudwego/eino/components/tool"
+	"github.com/cloudwego/eino/schema"
+)
+
+func GetEventsTool() tool.InvokableTool {
+	return safeTool{
+		InvokableTool: NewCancellableTool(&ToolQueryEvents{
+			backService: restService,
+		}),
+	}
+}
+
+// ToolQueryEvents 查询餐厅接下来的活动, 比如驻唱和特价日. 活动按周重复, 在查询的日期范围内展开成具体的日期,
+// 已经开始的活动不再返回.
+type ToolQueryEvents struct {
+	backService *fakeService // fake service
+}
+
+func (t *ToolQueryEvents) Info(ctx context.Context) (*schema.ToolInfo, error) {
+	return &schema.ToolInfo{
+		Name: "query_events",
+		Desc: "Query the upcoming events of a restaurant, like live music or special offer nights, with their dates and times",
+		ParamsOneOf: schema.NewParamsOneOfByParams(map[string]*schema.ParameterInfo{
+			"restaurant_id": {
+				Type:     "string",
+				Desc:     "The id of one restaurant",
+				Required: true,
+			},
+			"from": {
+				Type: "string",
+				Desc: "The first date to query, in the format 2006-01-02, default today",
+			},
+			"to": {
+				Type: "string",
+				Desc: fmt.Sprintf("The last date to query, in the format 2006-01-02, default %d days after from", defaultEventDays-1),
+			},
+		}),
+	}, nil
+}
+
+func (t *ToolQueryEvents) InvokableRun(ctx context.Context, argumentsInJSON string, opts ...tool.Option) (string, error) {
+	// 解析参数
+	if err := checkRequired(ctx, t, argumentsInJSON); err != nil {
+		return "", err
+	}
+	p := &QueryEventsParam{}
+	err := json.Unmarshal([]byte(argumentsInJSON), p)
+	if err != nil {
+		return "", err
+	}
+
+	// 请求后端服务
+	events, err := t.backService.QueryEvents(ctx, p)
+	if err != nil {
+		return "", err
+	}
+
+	// 序列化结果
+	res, err := marshalResult(events)
+	if err != nil {
+		return "", err
+	}
+
+	return string(res), nil
+}
+
+type QueryEventsParam struct {
+	RestaurantID string `json:"restaurant_id"`
+	From         string `json:"from"`
+	To           string `json:"to"`
+}
+
+// RestaurantEvents 中的日期和时间都是 fake 后端 clock 所在的时区.
+type RestaurantEvents struct {
+	RestaurantID string            `json:"restaurant_id"`
+	From         string            `json:"from"` // 2006-01-02
+	To           string            `json:"to"`   // 2006-01-02
+	Events       []RestaurantEvent `json:"events"`
+	Message      string            `json:"message,omitempty"`
+}
+
+type RestaurantEvent struct {
+	Name    string `json:"name"`
+	Desc    string `json:"desc"`
+	Date    string `json:"date"` // 2006-01-02
+	Weekday string `json:"weekday"`
+	Time    string `json:"time"` // 15:04
+}
+
+const (
+	eventDateLayout  = "2006-01-02"
+	defaultEventDays = 7  // 没有指定 to 时查询一周
+	maxEventDays     = 31 // 一次最多查询的天数
+)
+
+// QueryEvents 把 in.RestaurantID 的每周活动展开到 [in.From, in.To] 的每一天, 按时间排序.
+// from 早于今天时从今天开始, 今天已经开始的活动不返回.
+func (ft *fakeService) QueryEvents(ctx context.Context, in *QueryEventsParam) (*RestaurantEvents, error) {
+	if err := ft.simulateLatency(ctx); err != nil {
+		return nil, err
+	}
+
+	rest, err := ft.repo.GetRestaurantByID(ctx, in.RestaurantID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := ft.now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	from, err := parseEventDate("from", in.From, today)
+	if err != nil {
+		return nil, err
+	}
+	if from.Before(today) {
+		from = today
+	}
+	to, err := parseEventDate("to", in.To, from.AddDate(0, 0, defaultEventDays-1))
+	if err != nil {
+		return nil, err
+	}
+	if to.Before(from) {
+		return nil, fmt.Errorf("to (%s) is before from (%s), the range must not end in the past", to.Format(eventDateLayout), from.Format(eventDateLayout))
+	}
+	if days := int(to.Sub(from).Hours()/24) + 1; days > maxEventDays {
+		return nil, fmt.Errorf("the range covers %d days, at most %d days can be queried at once", days, maxEventDays)
+	}
+
+	out := &RestaurantEvents{
+		RestaurantID: rest.ID,
+		From:         from.Format(eventDateLayout),
+		To:           to.Format(eventDateLayout),
+		Events:       []RestaurantEvent{},
+	}
+	for day := from; !day.After(to); day = day.AddDate(0, 0, 1) {
+		var todays []RestaurantEvent
+		for _, ev := range rest.Events {
+			if ev.Weekday != day.Weekday() {
+				continue
+			}
+			start, err := time.ParseInLocation(eventDateLayout+" 15:04", day.Format(eventDateLayout)+" "+ev.Time, now.Location())
+			if err != nil || start.Before(now) {
+				continue
+			}
+			todays = append(todays, RestaurantEvent{
+				Name:    ev.Name,
+				Desc:    ev.Desc,
+				Date:    day.Format(eventDateLayout),
+				Weekday: strings.ToLower(day.Weekday().String()),
+				Time:    ev.Time,
+			})
+		}
+		// 同一天的活动按开始时间排序, 时间格式固定为 15:04, 可以直接比较字符串
+		sort.SliceStable(todays, func(i, j int) bool { return todays[i].Time < todays[j].Time })
+		out.Events = append(out.Events, todays...)
+	}
+
+	if len(out.Events) == 0 {
+		if len(rest.Events) == 0 {
+			out.Message = "this restaurant does not host any events"
+		} else {
+			out.Message = fmt.Sprintf("no upcoming events between %s and %s, try a later range", out.From, out.To)
+		}
+	}
+	return out, nil
+}
+
+// parseEventDate 解析 2006-01-02 格式的日期, 为空时返回 def.
+func parseEventDate(field, s string, def time.Time) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return def, nil
+	}
+	d, err := time.ParseInLocation(eventDateLayout, s, def.Location())
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid %s %q, use the format 2006-01-02", field, s)
+	}
+	return d, nil
+}
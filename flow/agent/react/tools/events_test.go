@@ -0,0 +1,69 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tools
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQueryEvents(t *testing.T) {
+	ctx := context.Background()
+	loc := time.FixedZone("CST", 8*3600)
+	// 2024-06-07 是周五, 民谣之夜 20:00 还没开始
+	svc := &fakeService{repo: restService.repo, clock: FixedClock{T: time.Date(2024, 6, 7, 19, 0, 0, 0, loc)}}
+
+	out, err := svc.QueryEvents(ctx, &QueryEventsParam{RestaurantID: "1001"})
+	assert.NoError(t, err)
+	assert.Equal(t, "2024-06-07", out.From)
+	assert.Equal(t, "2024-06-13", out.To)
+	assert.Equal(t, []RestaurantEvent{
+		{Name: "民谣之夜", Desc: "驻唱歌手弹唱民谣", Date: "2024-06-07", Weekday: "friday", Time: "20:00"},
+		{Name: "周末家宴特价", Desc: "红烧肉第二份半价", Date: "2024-06-09", Weekday: "sunday", Time: "11:00"},
+	}, out.Events)
+
+	// 已经开始的活动不返回, from 早于今天时从今天开始
+	svc.clock = FixedClock{T: time.Date(2024, 6, 7, 21, 0, 0, 0, loc)}
+	out, err = svc.QueryEvents(ctx, &QueryEventsParam{RestaurantID: "1001", From: "2024-06-01", To: "2024-06-15"})
+	assert.NoError(t, err)
+	assert.Equal(t, "2024-06-07", out.From)
+	var dates []string
+	for _, ev := range out.Events {
+		dates = append(dates, ev.Date)
+	}
+	assert.Equal(t, []string{"2024-06-09", "2024-06-14"}, dates)
+
+	out, err = svc.QueryEvents(ctx, &QueryEventsParam{RestaurantID: "1002", From: "2024-06-08", To: "2024-06-11"})
+	assert.NoError(t, err)
+	assert.Empty(t, out.Events)
+	assert.Contains(t, out.Message, "no upcoming events")
+
+	out, err = svc.QueryEvents(ctx, &QueryEventsParam{RestaurantID: "2010"})
+	assert.NoError(t, err)
+	assert.Equal(t, []RestaurantEvent{}, out.Events)
+	assert.Contains(t, out.Message, "does not host any events")
+
+	_, err = svc.QueryEvents(ctx, &QueryEventsParam{RestaurantID: "1001", To: "2024-06-01"})
+	assert.ErrorContains(t, err, "before from")
+	_, err = svc.QueryEvents(ctx, &QueryEventsParam{RestaurantID: "1001", To: "2024-09-01"})
+	assert.ErrorContains(t, err, "at most 31 days")
+	_, err = svc.QueryEvents(ctx, &QueryEventsParam{RestaurantID: "1001", From: "6/8"})
+	assert.ErrorContains(t, err, "invalid from")
+}
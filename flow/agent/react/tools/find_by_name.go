@@ -0,0 +1,200 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/cloudwego/eino/components/tool"
+	"github.com/cloudwego/eino/schema"
+)
+
+func GetFindRestaurantByNameTool() tool.InvokableTool {
+	return safeTool{
+		InvokableTool: NewCancellableTool(&ToolFindRestaurantByName{
+			backService: restService,
+		}),
+	}
+}
+
+// ToolFindRestaurantByName 用户直接说出餐厅名字时, 按名字模糊匹配找到餐厅的 id.
+type ToolFindRestaurantByName struct {
+	backService *fakeService // fake service
+}
+
+func (t *ToolFindRestaurantByName) Info(ctx context.Context) (*schema.ToolInfo, error) {
+	return &schema.ToolInfo{
+		Name: "find_restaurant_by_name",
+		Desc: "Find restaurants by (part of) their name, returns the best matches with their ids and a confidence between 0 and 1",
+		ParamsOneOf: schema.NewParamsOneOfByParams(map[string]*schema.ParameterInfo{
+			"name": {
+				Type:     "string",
+				Desc:     "The name of the restaurant, may be partial or misspelled",
+				Required: true,
+			},
+			"topn": {
+				Type: "number",
+				Desc: "top n matches sorted by confidence",
+			},
+		}),
+	}, nil
+}
+
+func (t *ToolFindRestaurantByName) InvokableRun(ctx context.Context, argumentsInJSON string, opts ...tool.Option) (string, error) {
+	// 解析参数
+	if err := checkRequired(ctx, t, argumentsInJSON); err != nil {
+		return "", err
+	}
+	p := &FindRestaurantByNameParam{}
+	err := json.Unmarshal([]byte(argumentsInJSON), p)
+	if err != nil {
+		return "", err
+	}
+	if p.Topn == 0 {
+		p.Topn = 3
+	}
+
+	// 请求后端服务
+	matches, err := t.backService.FindRestaurantByName(ctx, p)
+	if err != nil {
+		return "", err
+	}
+	if len(matches) == 0 {
+		return emptyResult("restaurants"), nil
+	}
+
+	// 序列化结果
+	res, err := marshalResult(matches)
+	if err != nil {
+		return "", err
+	}
+
+	return string(res), nil
+}
+
+type FindRestaurantByNameParam struct {
+	Name string `json:"name"`
+	Topn int    `json:"topn"`
+}
+
+type RestaurantMatch struct {
+	ID         string  `json:"id"`
+	Name       string  `json:"name"`
+	Place      string  `json:"place"`
+	Score      int     `json:"score"`
+	Confidence float64 `json:"confidence"` // 0 - 1
+}
+
+// FindRestaurantByName 在全部餐厅中按名字模糊匹配.
+func (ft *fakeService) FindRestaurantByName(ctx context.Context, in *FindRestaurantByNameParam) ([]RestaurantMatch, error) {
+	if err := ft.simulateLatency(ctx); err != nil {
+		return nil, err
+	}
+
+	rests, err := ft.repo.GetRestaurants(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+
+	return rankByName(in.Name, rests, in.Topn), nil
+}
+
+// minNameConfidence 低于这个置信度的匹配不返回, 避免把毫不相关的餐厅推给模型.
+const minNameConfidence = 0.3
+
+// rankByName 按名字相似度给餐厅排序, 置信度相同时按 id 排序, 保证结果稳定.
+func rankByName(name string, rests []restaurantDataItem, topn int) []RestaurantMatch {
+	query := strings.ToLower(strings.TrimSpace(name))
+	matches := make([]RestaurantMatch, 0, len(rests))
+	for _, rest := range rests {
+		confidence := nameConfidence(query, strings.ToLower(rest.Name))
+		if confidence < minNameConfidence {
+			continue
+		}
+		matches = append(matches, RestaurantMatch{
+			ID:         rest.ID,
+			Name:       rest.Name,
+			Place:      rest.Place,
+			Score:      rest.Score,
+			Confidence: math.Round(confidence*100) / 100,
+		})
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		if matches[i].Confidence != matches[j].Confidence {
+			return matches[i].Confidence > matches[j].Confidence
+		}
+		return matches[i].ID < matches[j].ID
+	})
+	if topn > 0 && len(matches) > topn {
+		matches = matches[:topn]
+	}
+	return matches
+}
+
+// nameConfidence 完全相同为 1, 互为子串时在 0.8 - 1 之间, 否则按编辑距离占较长名字的比例计算.
+func nameConfidence(query, name string) float64 {
+	q, n := []rune(query), []rune(name)
+	if len(q) == 0 || len(n) == 0 {
+		return 0
+	}
+	if query == name {
+		return 1
+	}
+
+	longer := max(len(q), len(n))
+	if strings.Contains(name, query) || strings.Contains(query, name) {
+		return 0.8 + 0.2*float64(min(len(q), len(n)))/float64(longer)
+	}
+	return 1 - float64(levenshtein(q, n))/float64(longer)
+}
+
+// levenshtein 计算两个字符串 (按 rune) 的编辑距离.
+func levenshtein(a, b []rune) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(b)]
+}
+
+// RestaurantNames 返回 fake 后端中全部餐厅的名字 (按 id 排序), 供 agent 从自由文本的回答中识别推荐了哪些餐厅.
+func RestaurantNames() []string {
+	rests, _ := database.GetRestaurants(context.Background(), "")
+	sort.Slice(rests, func(i, j int) bool { return rests[i].ID < rests[j].ID })
+	names := make([]string, 0, len(rests))
+	for _, rest := range rests {
+		names = append(names, rest.Name)
+	}
+	return names
+}
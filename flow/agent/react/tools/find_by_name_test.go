@@ -0,0 +1,57 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tools
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLevenshtein(t *testing.T) {
+	assert.Equal(t, 0, levenshtein([]rune("云边小馆"), []rune("云边小馆")))
+	assert.Equal(t, 1, levenshtein([]rune("云边小馆"), []rune("云边小店")))
+	assert.Equal(t, 3, levenshtein([]rune("kitten"), []rune("sitting")))
+	assert.Equal(t, 4, levenshtein(nil, []rune("abcd")))
+}
+
+func TestRankByName(t *testing.T) {
+	rests := append(database.restaurantsByLocation["北京"], database.restaurantsByLocation["上海"]...)
+
+	matches := rankByName("云边小馆", rests, 3)
+	assert.Equal(t, "1001", matches[0].ID)
+	assert.Equal(t, 1.0, matches[0].Confidence)
+
+	matches = rankByName("聚福轩", rests, 3)
+	assert.Equal(t, "1002", matches[0].ID)
+	assert.Greater(t, matches[0].Confidence, 0.8)
+
+	matches = rankByName("云边小店", rests, 3)
+	assert.Equal(t, "1001", matches[0].ID)
+
+	matches = rankByName("JIOJIO", rests, 1)
+	assert.Len(t, matches, 1)
+	assert.Equal(t, "2010", matches[0].ID)
+
+	assert.Empty(t, rankByName("完全不相关的名字啊", rests, 3))
+}
+
+func TestRestaurantNames(t *testing.T) {
+	names := RestaurantNames()
+	assert.Len(t, names, 6)
+	assert.Equal(t, "云边小馆", names[0])
+}
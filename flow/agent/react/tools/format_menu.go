@@ -0,0 +1,145 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/cloudwego/eino/components/tool"
+	"github.com/cloudwego/eino/schema"
+)
+
+// GetFormatMenuTool 返回 format_menu tool. streaming 为 true 时注册的是只实现了 tool.StreamableTool 的版本, 菜单逐行输出;
+// 否则是和其他 tool 一样、只实现了 tool.InvokableTool 的版本, 菜单一次性返回.
+//
+// ToolFormatMenu 同时实现了两个接口, 但 ToolsNode 会按 graph 的运行方式 (Invoke / Stream) 自动选择其中一个,
+// 所以要在注册时通过只暴露一个接口的包装来选择. 两种方式的取舍:
+//   - 流式: 下游 (callback、前端) 可以边生成边展示, 适合很长的结果; 但 safeTool、guardTool、缓存等包装都只支持 InvokableRun,
+//     不能复用, 错误也只能在开始输出之前转换成 content. callback 收到的是 OnEndWithStreamOutput, 而不是 OnEnd,
+//     必须读完并关闭 stream, 否则会阻塞 tool 的输出.
+//   - 非流式: 可以复用全部包装, callback 在 OnEnd 中直接拿到完整结果; 但只有全部生成完成后才能看到结果.
+//
+// 无论哪种方式, 交给模型的都是完整的菜单: ToolsNode 会在调用模型前把 stream 拼接起来.
+func GetFormatMenuTool(streaming bool) tool.BaseTool {
+	t := &ToolFormatMenu{backService: restService}
+	if streaming {
+		return streamOnlyTool{StreamableTool: t}
+	}
+	return safeTool{
+		InvokableTool: NewCancellableTool(t),
+	}
+}
+
+// streamOnlyTool 只暴露 StreamableRun, 让 ToolsNode 在 Invoke 和 Stream 两种模式下都使用流式的实现.
+type streamOnlyTool struct {
+	tool.StreamableTool
+}
+
+// ToolFormatMenu 把一家餐厅的菜品整理成人类可读的文本菜单.
+type ToolFormatMenu struct {
+	backService *fakeService // fake service
+}
+
+func (t *ToolFormatMenu) Info(ctx context.Context) (*schema.ToolInfo, error) {
+	return &schema.ToolInfo{
+		Name: "format_menu",
+		Desc: "Get the full menu of a restaurant as readable text, one dish per line with price and score",
+		ParamsOneOf: schema.NewParamsOneOfByParams(map[string]*schema.ParameterInfo{
+			"restaurant_id": {
+				Type:     "string",
+				Desc:     "The id of one restaurant",
+				Required: true,
+			},
+		}),
+	}, nil
+}
+
+func (t *ToolFormatMenu) InvokableRun(ctx context.Context, argumentsInJSON string, opts ...tool.Option) (string, error) {
+	lines, err := t.menuLines(ctx, argumentsInJSON)
+	if err != nil {
+		return "", err
+	}
+	return strings.Join(lines, ""), nil
+}
+
+// StreamableRun 每道菜一帧. 开始输出之前的错误 (比如参数错误、餐厅不存在) 和 safeTool 一样转换成 content,
+// 之后每一行之间都会检查 ctx, 被取消时以 ctx 的错误结束 stream.
+func (t *ToolFormatMenu) StreamableRun(ctx context.Context, argumentsInJSON string, opts ...tool.Option) (*schema.StreamReader[string], error) {
+	lines, err := t.menuLines(ctx, argumentsInJSON)
+	if err != nil {
+		return schema.StreamReaderFromArray([]string{err.Error()}), nil
+	}
+
+	sr, sw := schema.Pipe[string](1)
+	go func() {
+		defer sw.Close()
+		for _, line := range lines {
+			if err := t.backService.simulateLatency(ctx); err != nil {
+				sw.Send("", err)
+				return
+			}
+			if closed := sw.Send(line, nil); closed {
+				return
+			}
+		}
+	}()
+	return sr, nil
+}
+
+func (t *ToolFormatMenu) menuLines(ctx context.Context, argumentsInJSON string) ([]string, error) {
+	// 解析参数
+	if err := checkRequired(ctx, t, argumentsInJSON); err != nil {
+		return nil, err
+	}
+	p := &FormatMenuParam{}
+	err := json.Unmarshal([]byte(argumentsInJSON), p)
+	if err != nil {
+		return nil, err
+	}
+
+	// 请求后端服务
+	return t.backService.FormatMenu(ctx, p)
+}
+
+type FormatMenuParam struct {
+	RestaurantID string `json:"restaurant_id"`
+}
+
+// FormatMenu 返回菜单的每一行, 每行以换行结尾, 第一行是标题.
+func (ft *fakeService) FormatMenu(ctx context.Context, in *FormatMenuParam) ([]string, error) {
+	if err := ft.simulateLatency(ctx); err != nil {
+		return nil, err
+	}
+
+	rest, err := ft.repo.GetRestaurantByID(ctx, in.RestaurantID)
+	if err != nil {
+		return nil, err
+	}
+	return formatMenu(rest), nil
+}
+
+func formatMenu(rest restaurantDataItem) []string {
+	lines := make([]string, 0, len(rest.Dishes)+1)
+	lines = append(lines, fmt.Sprintf("%s 菜单 (共 %d 道菜)\n", rest.Name, len(rest.Dishes)))
+	for i, dish := range rest.Dishes {
+		lines = append(lines, fmt.Sprintf("%d. %s ¥%d 评分 %d/10: %s\n", i+1, dish.Name, dish.Price, dish.Score, dish.Desc))
+	}
+	return lines
+}
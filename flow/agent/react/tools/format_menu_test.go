@@ -0,0 +1,71 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tools
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/cloudwego/eino/components/tool"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormatMenu(t *testing.T) {
+	ctx := context.Background()
+	menu := &ToolFormatMenu{backService: restService}
+
+	full, err := menu.InvokableRun(ctx, `{"restaurant_id":"1001"}`)
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(full, "云边小馆 菜单"))
+
+	// 流式输出每行一帧, 拼起来和非流式的结果相同
+	sr, err := menu.StreamableRun(ctx, `{"restaurant_id":"1001"}`)
+	assert.NoError(t, err)
+	var frames []string
+	for {
+		frame, err := sr.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		assert.NoError(t, err)
+		frames = append(frames, frame)
+	}
+	assert.Greater(t, len(frames), 1)
+	assert.Equal(t, full, strings.Join(frames, ""))
+
+	// 开始输出之前的错误作为 content 返回
+	sr, err = menu.StreamableRun(ctx, `{"restaurant_id":"404"}`)
+	assert.NoError(t, err)
+	frame, err := sr.Recv()
+	assert.NoError(t, err)
+	assert.Contains(t, frame, "not found")
+}
+
+func TestGetFormatMenuTool(t *testing.T) {
+	_, invokable := GetFormatMenuTool(false).(tool.InvokableTool)
+	_, streamable := GetFormatMenuTool(false).(tool.StreamableTool)
+	assert.True(t, invokable)
+	assert.False(t, streamable)
+
+	_, invokable = GetFormatMenuTool(true).(tool.InvokableTool)
+	_, streamable = GetFormatMenuTool(true).(tool.StreamableTool)
+	assert.False(t, invokable)
+	assert.True(t, streamable)
+}
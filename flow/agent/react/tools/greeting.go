@@ -0,0 +1,95 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tools
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cloudwego/eino/components/tool"
+	"github.com/cloudwego/eino/schema"
+)
+
+func GetGreetingTool() tool.InvokableTool {
+	return safeTool{
+		InvokableTool: NewCancellableTool(&ToolGreeting{
+			backService: restService,
+		}),
+	}
+}
+
+// ToolGreeting 没有参数, 完全根据 context 中的用户 id 和这个用户的历史订单生成欢迎语:
+// 有历史订单时提到上一次点的菜, 匿名用户或者没有订单的用户返回通用的欢迎语.
+type ToolGreeting struct {
+	backService *fakeService // fake service
+}
+
+func (t *ToolGreeting) Info(ctx context.Context) (*schema.ToolInfo, error) {
+	return &schema.ToolInfo{
+		Name:        "get_greeting",
+		Desc:        "Get a welcome message for the current user, mentioning their last order when there is one. Call it at the start of a conversation",
+		ParamsOneOf: schema.NewParamsOneOfByParams(map[string]*schema.ParameterInfo{}),
+	}, nil
+}
+
+func (t *ToolGreeting) InvokableRun(ctx context.Context, argumentsInJSON string, opts ...tool.Option) (string, error) {
+	// 请求后端服务
+	greeting, err := t.backService.GetGreeting(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	// 序列化结果
+	res, err := marshalResult(greeting)
+	if err != nil {
+		return "", err
+	}
+
+	return string(res), nil
+}
+
+// Greeting 中 personalized 为 false 表示是通用的欢迎语.
+type Greeting struct {
+	Greeting     string `json:"greeting"`
+	Personalized bool   `json:"personalized"`
+}
+
+const genericGreeting = "Welcome! Tell me where you are and what you feel like eating, and I will find a restaurant for you."
+
+// GetGreeting 取当前用户最近的一条历史订单, 订单中的餐厅已经不存在时退回通用的欢迎语.
+func (ft *fakeService) GetGreeting(ctx context.Context) (*Greeting, error) {
+	if err := ft.simulateLatency(ctx); err != nil {
+		return nil, err
+	}
+
+	ft.mu.Lock()
+	history := ft.orders[UserIDFrom(ctx)]
+	ft.mu.Unlock()
+
+	if len(history) == 0 {
+		return &Greeting{Greeting: genericGreeting}, nil
+	}
+	last := history[len(history)-1]
+	rest, err := ft.repo.GetRestaurantByID(ctx, last.RestaurantID)
+	if err != nil {
+		return &Greeting{Greeting: genericGreeting}, nil
+	}
+	return &Greeting{
+		Greeting:     fmt.Sprintf("Welcome back! Last time you loved the %s at %s.", last.DishName, rest.Name),
+		Personalized: true,
+	}, nil
+}
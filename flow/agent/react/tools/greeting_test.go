@@ -0,0 +1,53 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tools
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetGreeting(t *testing.T) {
+	svc := &fakeService{repo: database, orders: defaultOrderHistory()}
+
+	greeting, err := svc.GetGreeting(WithUserID(context.Background(), "u2002"))
+	assert.NoError(t, err)
+	assert.True(t, greeting.Personalized)
+	assert.Contains(t, greeting.Greeting, "Welcome back!")
+	assert.Contains(t, greeting.Greeting, "糖醋西瓜瓤")
+
+	// 匿名用户和没有订单的用户
+	for _, ctx := range []context.Context{context.Background(), WithUserID(context.Background(), "u9999")} {
+		greeting, err = svc.GetGreeting(ctx)
+		assert.NoError(t, err)
+		assert.False(t, greeting.Personalized)
+		assert.Equal(t, genericGreeting, greeting.Greeting)
+	}
+
+	// 订单中的餐厅已经不存在
+	svc.orders = map[string][]pastOrder{"u1": {{RestaurantID: "9999", DishName: "x"}}}
+	greeting, err = svc.GetGreeting(WithUserID(context.Background(), "u1"))
+	assert.NoError(t, err)
+	assert.False(t, greeting.Personalized)
+
+	// 没有参数, 空的 arguments 也能调用
+	res, err := (&ToolGreeting{backService: svc}).InvokableRun(context.Background(), "{}")
+	assert.NoError(t, err)
+	assert.Contains(t, res, `"personalized":false`)
+}
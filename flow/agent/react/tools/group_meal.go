@@ -0,0 +1,239 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+
+	"github.com/cloudwego/eino/components/tool"
+	"github.com/cloudwego/eino/schema"
+)
+
+func GetCheapestGroupMealTool() tool.InvokableTool {
+	return safeTool{
+		InvokableTool: NewCancellableTool(&ToolCheapestGroupMeal{
+			backService: restService,
+		}),
+	}
+}
+
+// ToolCheapestGroupMeal 在一家餐厅的菜单中选出最便宜、又够 party_size 个人吃的一组菜. 这是一个组合优化问题,
+// 模型自己挑很难保证是最便宜的, 由 tool 按 query_portion_info 同样的分量估算做动态规划.
+type ToolCheapestGroupMeal struct {
+	backService *fakeService // fake service
+}
+
+func (t *ToolCheapestGroupMeal) Info(ctx context.Context) (*schema.ToolInfo, error) {
+	return &schema.ToolInfo{
+		Name: "plan_cheapest_group_meal",
+		Desc: "Pick the cheapest combination of dishes of a restaurant that is enough food for the whole party, based on the portion sizes. " +
+			"Returns the dishes with quantities, the total cost and the cost per person in CNY",
+		ParamsOneOf: schema.NewParamsOneOfByParams(map[string]*schema.ParameterInfo{
+			"restaurant_id": {
+				Type:     "string",
+				Desc:     "The id of one restaurant",
+				Required: true,
+			},
+			"party_size": {
+				Type:     "integer",
+				Desc:     "How many people are eating",
+				Required: true,
+			},
+		}),
+	}, nil
+}
+
+func (t *ToolCheapestGroupMeal) InvokableRun(ctx context.Context, argumentsInJSON string, opts ...tool.Option) (string, error) {
+	// 解析参数
+	if err := checkRequired(ctx, t, argumentsInJSON); err != nil {
+		return "", err
+	}
+	p := &CheapestGroupMealParam{}
+	err := json.Unmarshal([]byte(argumentsInJSON), p)
+	if err != nil {
+		return "", err
+	}
+
+	// 请求后端服务
+	meal, err := t.backService.CheapestGroupMeal(ctx, p)
+	if err != nil {
+		return "", err
+	}
+
+	// 序列化结果
+	res, err := marshalResult(meal)
+	if err != nil {
+		return "", err
+	}
+
+	return string(res), nil
+}
+
+type CheapestGroupMealParam struct {
+	RestaurantID string `json:"restaurant_id"`
+	PartySize    int    `json:"party_size"`
+}
+
+// GroupMeal 的金额单位是元. 菜单上所有的菜加起来都不够吃时没有 dishes, 原因在 message 中.
+type GroupMeal struct {
+	RestaurantID string          `json:"restaurant_id"`
+	PartySize    int             `json:"party_size"`
+	Dishes       []GroupMealDish `json:"dishes,omitempty"`
+	TotalServes  int             `json:"total_serves"`
+	Total        float64         `json:"total"`
+	PerPerson    float64         `json:"per_person"`
+	Message      string          `json:"message"`
+}
+
+type GroupMealDish struct {
+	Name     string `json:"name"`
+	Quantity int    `json:"quantity"`
+	Price    int    `json:"price"`
+	// Serves 是这道菜 quantity 份加起来够几个人吃
+	Serves int `json:"serves"`
+}
+
+// groupMealItem 是动态规划中的一件物品: 一份菜.
+type groupMealItem struct {
+	dish   int // 在菜单中的下标
+	price  int
+	serves int
+}
+
+// CheapestGroupMeal 选出总价最低并且够 in.PartySize 个人吃的一组菜, 同样便宜时选菜更少的一组.
+// 共享的菜每道最多点一份, 保证菜色不重复; 一人一份的菜 (见 dishPortion) 最多每人一份.
+func (ft *fakeService) CheapestGroupMeal(ctx context.Context, in *CheapestGroupMealParam) (*GroupMeal, error) {
+	if err := ft.simulateLatency(ctx); err != nil {
+		return nil, err
+	}
+
+	if in.PartySize <= 0 || in.PartySize > maxPartySize {
+		return nil, fmt.Errorf("party_size must be between 1 and %d, got %d", maxPartySize, in.PartySize)
+	}
+	rest, err := ft.repo.GetRestaurantByID(ctx, in.RestaurantID)
+	if err != nil {
+		return nil, err
+	}
+
+	var items []groupMealItem
+	for i, dish := range rest.Dishes {
+		copies := 1
+		if !dish.Shareable && dish.Portion != "" {
+			copies = in.PartySize
+		}
+		serves := dishPortion(dish, in.PartySize).Serves
+		for c := 0; c < copies; c++ {
+			items = append(items, groupMealItem{dish: i, price: dish.Price, serves: serves})
+		}
+	}
+
+	out := &GroupMeal{RestaurantID: rest.ID, PartySize: in.PartySize}
+	picked, ok := cheapestCover(items, in.PartySize)
+	if !ok {
+		total := 0
+		for _, it := range items {
+			total += it.serves
+		}
+		out.TotalServes = total
+		out.Message = fmt.Sprintf("the whole menu only serves about %d, not enough for %d people", total, in.PartySize)
+		return out, nil
+	}
+
+	byDish := map[int]int{} // 菜单下标 => 在 out.Dishes 中的下标
+	cost := 0
+	for _, it := range picked {
+		dish := rest.Dishes[it.dish]
+		j, seen := byDish[it.dish]
+		if !seen {
+			j = len(out.Dishes)
+			byDish[it.dish] = j
+			out.Dishes = append(out.Dishes, GroupMealDish{Name: dish.Name, Price: dish.Price})
+		}
+		out.Dishes[j].Quantity++
+		out.Dishes[j].Serves += it.serves
+		out.TotalServes += it.serves
+		cost += it.price
+	}
+	out.Total = float64(cost)
+	out.PerPerson = roundYuan(out.Total / float64(in.PartySize))
+	out.Message = fmt.Sprintf("%d dish(es) serving about %d for %.2f yuan, %.2f per person", len(picked), out.TotalServes, out.Total, out.PerPerson)
+	return out, nil
+}
+
+// cheapestCover 是 0/1 背包的变形: 从 items 中选出 serves 之和至少为 need、price 之和最小的子集, 同样便宜时件数更少.
+// best[i][j] 是只用前 i 件物品、serves 之和 (超过 need 的部分按 need 算) 为 j 时的最优解. 凑不够 need 时 ok 为 false.
+func cheapestCover(items []groupMealItem, need int) (picked []groupMealItem, ok bool) {
+	type cell struct {
+		cost, count int
+	}
+	unreachable := cell{cost: math.MaxInt, count: math.MaxInt}
+	better := func(a, b cell) bool {
+		return a.cost < b.cost || (a.cost == b.cost && a.count < b.count)
+	}
+
+	best := make([][]cell, len(items)+1)
+	for i := range best {
+		best[i] = make([]cell, need+1)
+		for j := range best[i] {
+			best[i][j] = unreachable
+		}
+	}
+	best[0][0] = cell{}
+	for i, it := range items {
+		for j := 0; j <= need; j++ {
+			if better(best[i][j], best[i+1][j]) {
+				best[i+1][j] = best[i][j]
+			}
+			if best[i][j] == unreachable {
+				continue
+			}
+			k := min(need, j+it.serves)
+			if c := (cell{cost: best[i][j].cost + it.price, count: best[i][j].count + 1}); better(c, best[i+1][k]) {
+				best[i+1][k] = c
+			}
+		}
+	}
+	if best[len(items)][need] == unreachable {
+		return nil, false
+	}
+
+	// 从后往前找出每一步是怎么来的
+	for i, j := len(items), need; i > 0; i-- {
+		if best[i][j] == best[i-1][j] {
+			continue
+		}
+		it := items[i-1]
+		for prev := 0; prev <= j; prev++ {
+			if best[i-1][prev] == unreachable || min(need, prev+it.serves) != j {
+				continue
+			}
+			if best[i-1][prev].cost+it.price == best[i][j].cost && best[i-1][prev].count+1 == best[i][j].count {
+				picked = append(picked, it)
+				j = prev
+				break
+			}
+		}
+	}
+	// 按菜单的顺序返回
+	for l, r := 0, len(picked)-1; l < r; l, r = l+1, r-1 {
+		picked[l], picked[r] = picked[r], picked[l]
+	}
+	return picked, true
+}
@@ -0,0 +1,85 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tools
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheapestGroupMeal(t *testing.T) {
+	ctx := context.Background()
+	svc := &fakeService{repo: database}
+
+	// 4 个人: 清炒小南瓜 5 + 酸辣土豆丝 10 和 清炒小南瓜 5 + 两份酸辣粉 10 一样便宜, 选菜少的
+	meal, err := svc.CheapestGroupMeal(ctx, &CheapestGroupMealParam{RestaurantID: "1001", PartySize: 4})
+	assert.NoError(t, err)
+	assert.Equal(t, []GroupMealDish{
+		{Name: "酸辣土豆丝", Quantity: 1, Price: 10, Serves: 2},
+		{Name: "清炒小南瓜", Quantity: 1, Price: 5, Serves: 2},
+	}, meal.Dishes)
+	assert.Equal(t, 4, meal.TotalServes)
+	assert.Equal(t, 15.0, meal.Total)
+	assert.Equal(t, 3.75, meal.PerPerson)
+
+	// 共享的菜不重复点, 一人一份的酸辣粉可以点多份
+	meal, err = svc.CheapestGroupMeal(ctx, &CheapestGroupMealParam{RestaurantID: "1001", PartySize: 6})
+	assert.NoError(t, err)
+	assert.Equal(t, GroupMealDish{Name: "酸辣粉", Quantity: 2, Price: 5, Serves: 2}, meal.Dishes[2])
+	assert.Equal(t, 25.0, meal.Total)
+	assert.Equal(t, 4.17, meal.PerPerson)
+
+	// 一道 large 比 medium + small 便宜, 即使吃不完
+	meal, err = svc.CheapestGroupMeal(ctx, &CheapestGroupMealParam{RestaurantID: "1002", PartySize: 2})
+	assert.NoError(t, err)
+	assert.Equal(t, []GroupMealDish{{Name: "大刀回锅肉", Quantity: 1, Price: 40, Serves: 3}}, meal.Dishes)
+	assert.Equal(t, 3, meal.TotalServes)
+
+	// 整个菜单都不够吃
+	meal, err = svc.CheapestGroupMeal(ctx, &CheapestGroupMealParam{RestaurantID: "2001", PartySize: 6})
+	assert.NoError(t, err)
+	assert.Empty(t, meal.Dishes)
+	assert.Equal(t, 5, meal.TotalServes)
+	assert.Contains(t, meal.Message, "not enough for 6 people")
+
+	_, err = svc.CheapestGroupMeal(ctx, &CheapestGroupMealParam{RestaurantID: "1001", PartySize: 0})
+	assert.ErrorContains(t, err, "party_size")
+	_, err = svc.CheapestGroupMeal(ctx, &CheapestGroupMealParam{RestaurantID: "9999", PartySize: 2})
+	assert.Error(t, err)
+}
+
+func TestCheapestCover(t *testing.T) {
+	items := []groupMealItem{
+		{dish: 0, price: 10, serves: 3},
+		{dish: 1, price: 4, serves: 1},
+		{dish: 2, price: 4, serves: 1},
+		{dish: 3, price: 4, serves: 1},
+	}
+	// 贪心按单价 (每人份的价格) 选会先选 4 元的菜, 凑齐 3 人份要 12 元; 最优是 10 元的一道
+	picked, ok := cheapestCover(items, 3)
+	assert.True(t, ok)
+	assert.Equal(t, []groupMealItem{items[0]}, picked)
+
+	picked, ok = cheapestCover(items, 5)
+	assert.True(t, ok)
+	assert.Equal(t, []groupMealItem{items[0], items[1], items[2]}, picked)
+
+	_, ok = cheapestCover(items, 7)
+	assert.False(t, ok)
+}
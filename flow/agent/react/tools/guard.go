@@ -0,0 +1,152 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+
+	"github.com/cloudwego/eino/components/tool"
+)
+
+// GuardRule 描述一条参数检测规则, Pattern 命中任意一个字符串参数即视为可疑.
+type GuardRule struct {
+	Name    string
+	Pattern *regexp.Regexp
+}
+
+// DefaultGuardRules 返回默认的检测规则, 覆盖常见的 SQL 注入、命令注入、路径穿越和提示词注入写法.
+func DefaultGuardRules() []GuardRule {
+	return []GuardRule{
+		{Name: "sql_injection", Pattern: regexp.MustCompile(`(?i)(\b(select|insert|update|delete|drop|union)\b.+\b(from|into|table|select|set)\b|'\s*(or|and)\s+\S+\s*=|;\s*--)`)},
+		{Name: "command_injection", Pattern: regexp.MustCompile("(?i)(;|&&|\\|\\||\\$\\(|`)\\s*(rm|curl|wget|cat|sh|bash|nc)\\b")},
+		{Name: "path_traversal", Pattern: regexp.MustCompile(`\.\.[/\\]`)},
+		{Name: "prompt_injection", Pattern: regexp.MustCompile(`(?i)ignore\s+(all\s+)?(previous|prior|above)\s+instructions`)},
+	}
+}
+
+// guardTool inspects the arguments of a tool call before they reach the wrapped tool.
+// Arguments naming fields that are not declared in the tool's schema, or string values matching any GuardRule,
+// are rejected with a structured refusal returned as content, so the backend is never called with them.
+type guardTool struct {
+	tool.InvokableTool
+	rules []GuardRule
+}
+
+// NewGuardTool wraps t with argument inspection. When no rules are given, DefaultGuardRules is used.
+func NewGuardTool(t tool.InvokableTool, rules ...GuardRule) tool.InvokableTool {
+	if len(rules) == 0 {
+		rules = DefaultGuardRules()
+	}
+	return &guardTool{InvokableTool: t, rules: rules}
+}
+
+func (g *guardTool) InvokableRun(ctx context.Context, argumentsInJSON string, opts ...tool.Option) (string, error) {
+	args := map[string]any{}
+	if err := json.Unmarshal([]byte(argumentsInJSON), &args); err != nil {
+		// 非法 JSON 交给被包装的 tool 自己处理
+		return g.InvokableTool.InvokableRun(ctx, argumentsInJSON, opts...)
+	}
+
+	reason, err := g.inspect(ctx, args)
+	if err != nil {
+		return "", err
+	}
+	if reason != "" {
+		return refuse(ctx, reason), nil
+	}
+
+	return g.InvokableTool.InvokableRun(ctx, argumentsInJSON, opts...)
+}
+
+// inspect returns a non-empty reason when args should be rejected.
+func (g *guardTool) inspect(ctx context.Context, args map[string]any) (string, error) {
+	info, err := g.InvokableTool.Info(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	declared := map[string]bool{}
+	if info.ParamsOneOf != nil {
+		js, err := info.ParamsOneOf.ToJSONSchema()
+		if err != nil {
+			return "", err
+		}
+		if js != nil && js.Properties != nil {
+			for pair := js.Properties.Oldest(); pair != nil; pair = pair.Next() {
+				declared[pair.Key] = true
+			}
+		}
+	}
+
+	keys := make([]string, 0, len(args))
+	for k := range args {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		if !declared[k] {
+			return fmt.Sprintf("field %q is not part of the %s schema", k, info.Name), nil
+		}
+		for _, s := range collectStrings(args[k]) {
+			for _, rule := range g.rules {
+				if rule.Pattern.MatchString(s) {
+					return fmt.Sprintf("field %q looks like %s", k, rule.Name), nil
+				}
+			}
+		}
+	}
+
+	return "", nil
+}
+
+// refuse 把一次 tool 调用标记为失败, 返回给模型一个结构化的拒绝信息, 而不调用被包装的 tool.
+func refuse(ctx context.Context, reason string) string {
+	if state := GetToolState(ctx); state != nil {
+		state.Success = false
+	}
+	refusal, _ := json.Marshal(map[string]string{
+		"error":   "arguments rejected",
+		"message": reason,
+		"retry":   "false",
+	})
+	return string(refusal)
+}
+
+func collectStrings(v any) []string {
+	switch val := v.(type) {
+	case string:
+		return []string{val}
+	case []any:
+		var res []string
+		for _, item := range val {
+			res = append(res, collectStrings(item)...)
+		}
+		return res
+	case map[string]any:
+		var res []string
+		for _, item := range val {
+			res = append(res, collectStrings(item)...)
+		}
+		return res
+	}
+	return nil
+}
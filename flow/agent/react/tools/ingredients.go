@@ -0,0 +1,176 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/cloudwego/eino/components/tool"
+	"github.com/cloudwego/eino/schema"
+)
+
+func GetIngredientsTool() tool.InvokableTool {
+	return safeTool{
+		InvokableTool: NewCancellableTool(&ToolIngredients{
+			backService: restService,
+		}),
+	}
+}
+
+// ToolIngredients 返回菜品的主要食材, 和过敏原, 营养成分一起帮助模型回答 "这道菜里有没有香菜" 这类饮食问题.
+type ToolIngredients struct {
+	backService *fakeService // fake service
+}
+
+func (t *ToolIngredients) Info(ctx context.Context) (*schema.ToolInfo, error) {
+	return &schema.ToolInfo{
+		Name: "query_ingredients",
+		Desc: "Query the main ingredients of the dishes in one restaurant. " +
+			"Ingredient names are in Chinese as on the menu, e.g. 猪肉, 香菜, 花生",
+		ParamsOneOf: schema.NewParamsOneOfByParams(map[string]*schema.ParameterInfo{
+			"restaurant_id": {
+				Type:     "string",
+				Desc:     "The id of one restaurant",
+				Required: true,
+			},
+			"dish_name": {
+				Type: "string",
+				Desc: "Only query this dish, leave empty for all dishes of the restaurant",
+			},
+			"excludes": {
+				Type:     "array",
+				Desc:     "Ingredients the user does not want, dishes containing any of them are flagged",
+				ElemInfo: &schema.ParameterInfo{Type: "string"},
+			},
+		}),
+	}, nil
+}
+
+func (t *ToolIngredients) InvokableRun(ctx context.Context, argumentsInJSON string, opts ...tool.Option) (string, error) {
+	// 解析参数
+	if err := checkRequired(ctx, t, argumentsInJSON); err != nil {
+		return "", err
+	}
+	p := &IngredientsParam{}
+	err := json.Unmarshal([]byte(argumentsInJSON), p)
+	if err != nil {
+		return "", err
+	}
+
+	// 请求后端服务
+	info, err := t.backService.QueryIngredients(ctx, p)
+	if err != nil {
+		return "", err
+	}
+
+	// 序列化结果
+	res, err := marshalResult(info)
+	if err != nil {
+		return "", err
+	}
+
+	return string(res), nil
+}
+
+type IngredientsParam struct {
+	RestaurantID string   `json:"restaurant_id"`
+	DishName     string   `json:"dish_name"`
+	Excludes     []string `json:"excludes"`
+}
+
+type DishIngredients struct {
+	Name        string   `json:"name"`
+	Ingredients []string `json:"ingredients"` // 没有数据时为空数组, 而不是 null
+	// IngredientsAvailable 为 false 表示餐厅没有提供这道菜的食材, 不能据此判断是否含有 excludes 中的食材
+	IngredientsAvailable bool `json:"ingredients_available"`
+	// Unwanted 是这道菜中命中 excludes 的食材
+	Unwanted []string `json:"unwanted,omitempty"`
+	Message  string   `json:"message,omitempty"`
+}
+
+type IngredientInfo struct {
+	RestaurantID string            `json:"restaurant_id"`
+	Dishes       []DishIngredients `json:"dishes"`
+	// Menu 在 dish_name 没有找到时列出餐厅的所有菜名, 方便模型换一个名字再查
+	Menu    []string `json:"menu,omitempty"`
+	Message string   `json:"message,omitempty"`
+}
+
+// QueryIngredients 查询一家餐厅菜品的食材. 找不到 in.DishName 时不返回错误, 而是在结果里列出菜单.
+func (ft *fakeService) QueryIngredients(ctx context.Context, in *IngredientsParam) (*IngredientInfo, error) {
+	if err := ft.simulateLatency(ctx); err != nil {
+		return nil, err
+	}
+
+	rest, err := ft.repo.GetRestaurantByID(ctx, in.RestaurantID)
+	if err != nil {
+		return nil, err
+	}
+
+	dishes := rest.Dishes
+	if in.DishName != "" {
+		dish, ok := findDish(rest.Dishes, in.DishName)
+		if !ok {
+			out := &IngredientInfo{
+				RestaurantID: rest.ID,
+				Dishes:       []DishIngredients{},
+				Message:      fmt.Sprintf("dish %s not found in restaurant %s, see menu for the available dishes", in.DishName, rest.ID),
+			}
+			for _, d := range rest.Dishes {
+				out.Menu = append(out.Menu, d.Name)
+			}
+			return out, nil
+		}
+		dishes = []restaurantDishDataItem{dish}
+	}
+
+	out := &IngredientInfo{RestaurantID: rest.ID, Dishes: make([]DishIngredients, 0, len(dishes))}
+	for _, dish := range dishes {
+		out.Dishes = append(out.Dishes, dishIngredients(dish, in.Excludes))
+	}
+	return out, nil
+}
+
+func dishIngredients(dish restaurantDishDataItem, excludes []string) DishIngredients {
+	d := DishIngredients{Name: dish.Name, Ingredients: dish.Ingredients, IngredientsAvailable: len(dish.Ingredients) > 0}
+	if !d.IngredientsAvailable {
+		d.Ingredients = []string{}
+		d.Message = "ingredients unavailable, ask the restaurant before ordering if the user avoids some ingredients"
+		return d
+	}
+	for _, ingredient := range dish.Ingredients {
+		if containsIngredient(ingredient, excludes) {
+			d.Unwanted = append(d.Unwanted, ingredient)
+		}
+	}
+	return d
+}
+
+// containsIngredient 判断 ingredient 是否命中 excludes 中的任意一项. 按包含关系匹配, 所以 "猪" 能命中 "猪五花肉".
+func containsIngredient(ingredient string, excludes []string) bool {
+	ingredient = strings.ToLower(ingredient)
+	for _, ex := range excludes {
+		ex = strings.ToLower(strings.TrimSpace(ex))
+		if ex != "" && strings.Contains(ingredient, ex) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,67 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tools
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQueryIngredients(t *testing.T) {
+	ctx := context.Background()
+
+	out, err := restService.QueryIngredients(ctx, &IngredientsParam{RestaurantID: "1001", DishName: "酸辣粉", Excludes: []string{"香菜", " 花生 "}})
+	assert.NoError(t, err)
+	assert.Len(t, out.Dishes, 1)
+	assert.True(t, out.Dishes[0].IngredientsAvailable)
+	assert.Contains(t, out.Dishes[0].Ingredients, "红薯粉")
+	assert.Equal(t, []string{"花生", "香菜"}, out.Dishes[0].Unwanted)
+
+	// 按包含关系匹配, 没有命中的菜不标记
+	out, err = restService.QueryIngredients(ctx, &IngredientsParam{RestaurantID: "1001", Excludes: []string{"猪"}})
+	assert.NoError(t, err)
+	assert.Len(t, out.Dishes, 6)
+	var flagged []string
+	for _, d := range out.Dishes {
+		if len(d.Unwanted) > 0 {
+			flagged = append(flagged, d.Name)
+		}
+	}
+	assert.Equal(t, []string{"红烧肉"}, flagged)
+
+	// 没有食材数据的菜明确标记
+	out, err = restService.QueryIngredients(ctx, &IngredientsParam{RestaurantID: "1003", Excludes: []string{"猪肉"}})
+	assert.NoError(t, err)
+	for _, d := range out.Dishes {
+		assert.False(t, d.IngredientsAvailable)
+		assert.Equal(t, []string{}, d.Ingredients)
+		assert.Empty(t, d.Unwanted)
+		assert.Contains(t, d.Message, "unavailable")
+	}
+
+	// 找不到的菜不报错, 返回菜单
+	out, err = restService.QueryIngredients(ctx, &IngredientsParam{RestaurantID: "1001", DishName: "不存在的菜"})
+	assert.NoError(t, err)
+	assert.Empty(t, out.Dishes)
+	assert.Len(t, out.Menu, 6)
+	assert.Contains(t, out.Message, "not found")
+
+	_, err = restService.QueryIngredients(ctx, &IngredientsParam{RestaurantID: "9999"})
+	assert.Error(t, err)
+}
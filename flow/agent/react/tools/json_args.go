@@ -0,0 +1,76 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/cloudwego/eino/components/tool"
+)
+
+// jsonArgsTool 在反序列化之前确认参数是一个 JSON 对象. 流式输出时 tool call 的参数分成很多帧, 拼起来以后偶尔不是合法的 JSON
+// (比如输出被截断、引号没有闭合); 这时返回一条提示, 让模型重新生成参数, 而不是把 JSON 的解析错误交给 tool,
+// 在 -strict 下这类错误也不会中断 agent.
+type jsonArgsTool struct {
+	tool.InvokableTool
+}
+
+// NewJSONArgsTool 包装 t, 参数不是 JSON 对象时直接返回一条纠正提示, 不调用 t.
+// 空参数原样交给 t, 有的模型调用没有参数的 tool 时会发送空字符串.
+func NewJSONArgsTool(t tool.InvokableTool) tool.InvokableTool {
+	return &jsonArgsTool{InvokableTool: t}
+}
+
+func (j *jsonArgsTool) InvokableRun(ctx context.Context, argumentsInJSON string, opts ...tool.Option) (string, error) {
+	if strings.TrimSpace(argumentsInJSON) == "" {
+		return j.InvokableTool.InvokableRun(ctx, argumentsInJSON, opts...)
+	}
+
+	var args map[string]any
+	err := json.Unmarshal([]byte(argumentsInJSON), &args)
+	if err == nil && args != nil {
+		return j.InvokableTool.InvokableRun(ctx, argumentsInJSON, opts...)
+	}
+
+	reason := "expected a JSON object"
+	if err != nil {
+		reason = err.Error()
+	}
+	name := "the tool"
+	if info, infoErr := j.InvokableTool.Info(ctx); infoErr == nil {
+		name = info.Name
+	}
+	if state := GetToolState(ctx); state != nil {
+		state.Success = false
+	}
+	return malformedArgumentsError(fmt.Sprintf("your tool arguments were not valid JSON (%s), call %s again with a single JSON object matching its parameters", reason, name)).Error(), nil
+}
+
+// malformedArgumentsError 是参数无法解析时的结构化错误, jsonArgsTool 和 decodeArguments 共用, 同一个错误码的 retry 也相同.
+// 用同样的参数重试还是无法解析, 所以 retry 为 false, 内层的 retryTool 不会重试, 模型按 message 改正参数后再调用.
+func malformedArgumentsError(message string) error {
+	msg, _ := json.Marshal(map[string]string{
+		"error":   "malformed arguments",
+		"message": message,
+		"retry":   "false",
+	})
+	return errors.New(string(msg))
+}
@@ -0,0 +1,50 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tools
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJSONArgsTool(t *testing.T) {
+	ctx := context.Background()
+	checked := NewJSONArgsTool(&ToolQueryDishes{backService: restService})
+
+	out, err := checked.InvokableRun(ctx, `{"restaurant_id": "1001", "topn": 1}`)
+	assert.NoError(t, err)
+	assert.NotContains(t, out, "malformed arguments")
+
+	// 流式输出被截断, 或者不是一个对象
+	for _, args := range []string{`{"restaurant_id": "1001", "topn":`, `{"restaurant_id": "1001}`, `["1001"]`, `null`} {
+		state := &ToolExecutionState{Success: true}
+		out, err = checked.InvokableRun(SetToolState(ctx, state), args)
+		assert.NoError(t, err, args)
+		assert.Contains(t, out, "malformed arguments", args)
+		assert.Contains(t, out, "your tool arguments were not valid JSON", args)
+		assert.Contains(t, out, "call query_dishes again", args)
+		assert.Contains(t, out, `"retry":"false"`, args)
+		assert.False(t, state.Success, args)
+	}
+
+	// 没有参数的 tool 可能收到空字符串, 原样交给 tool
+	out, err = NewJSONArgsTool(&ToolListSaved{backService: restService}).InvokableRun(WithUserID(ctx, "u1001"), "")
+	assert.NoError(t, err)
+	assert.NotContains(t, out, "malformed arguments")
+}
@@ -0,0 +1,135 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tools
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/cloudwego/eino/components/tool"
+	"github.com/cloudwego/eino/schema"
+)
+
+func GetLoyaltyInfoTool() tool.InvokableTool {
+	return safeTool{
+		InvokableTool: NewCancellableTool(&ToolLoyaltyInfo{
+			backService: restService,
+		}),
+	}
+}
+
+// ToolLoyaltyInfo 同时包含餐厅的状态 (有没有会员积分计划) 和用户的状态 (当前的积分), 用户 id 从 context 中读取.
+type ToolLoyaltyInfo struct {
+	backService *fakeService // fake service
+}
+
+func (t *ToolLoyaltyInfo) Info(ctx context.Context) (*schema.ToolInfo, error) {
+	return &schema.ToolInfo{
+		Name: "query_loyalty_info",
+		Desc: "Query the loyalty program of a restaurant and the points the current user has there",
+		ParamsOneOf: schema.NewParamsOneOfByParams(map[string]*schema.ParameterInfo{
+			"restaurant_id": {
+				Type:     "string",
+				Desc:     "The id of one restaurant",
+				Required: true,
+			},
+		}),
+	}, nil
+}
+
+func (t *ToolLoyaltyInfo) InvokableRun(ctx context.Context, argumentsInJSON string, opts ...tool.Option) (string, error) {
+	// 解析参数
+	if err := checkRequired(ctx, t, argumentsInJSON); err != nil {
+		return "", err
+	}
+	p := &LoyaltyInfoParam{}
+	err := json.Unmarshal([]byte(argumentsInJSON), p)
+	if err != nil {
+		return "", err
+	}
+
+	// 请求后端服务
+	info, err := t.backService.QueryLoyaltyInfo(ctx, p)
+	if err != nil {
+		return "", err
+	}
+
+	// 序列化结果
+	res, err := marshalResult(info)
+	if err != nil {
+		return "", err
+	}
+
+	return string(res), nil
+}
+
+type LoyaltyInfoParam struct {
+	RestaurantID string `json:"restaurant_id"`
+}
+
+type LoyaltyInfo struct {
+	RestaurantID  string  `json:"restaurant_id"`
+	HasProgram    bool    `json:"has_program"`
+	ProgramName   string  `json:"program_name,omitempty"`
+	PointsPerYuan float64 `json:"points_per_yuan,omitempty"`
+	// Guest 为 true 表示当前是匿名用户, 此时没有 Points
+	Guest  bool `json:"guest"`
+	Points *int `json:"points,omitempty"`
+	// Message 说明没有积分计划或者需要登录等情况
+	Message string `json:"message,omitempty"`
+}
+
+// defaultLoyaltyPoints 是 fake service 预置的用户积分, user id => restaurant id => 积分.
+func defaultLoyaltyPoints() map[string]map[string]int {
+	return map[string]map[string]int{
+		"u1001": {"1001": 320, "1002": 1280},
+		"u2002": {"2001": 600},
+	}
+}
+
+// QueryLoyaltyInfo 查询餐厅的积分计划和 context 中用户的积分. 用户没有积分记录时积分为 0.
+func (ft *fakeService) QueryLoyaltyInfo(ctx context.Context, in *LoyaltyInfoParam) (*LoyaltyInfo, error) {
+	if err := ft.simulateLatency(ctx); err != nil {
+		return nil, err
+	}
+
+	rest, err := ft.repo.GetRestaurantByID(ctx, in.RestaurantID)
+	if err != nil {
+		return nil, err
+	}
+
+	userID := UserIDFrom(ctx)
+	info := &LoyaltyInfo{RestaurantID: rest.ID, Guest: userID == ""}
+	if rest.Loyalty == nil {
+		info.Message = "this restaurant has no loyalty program"
+		return info, nil
+	}
+	info.HasProgram = true
+	info.ProgramName = rest.Loyalty.ProgramName
+	info.PointsPerYuan = rest.Loyalty.PointsPerYuan
+
+	if info.Guest {
+		info.Message = "the user is not signed in, points are only available to signed-in users"
+		return info, nil
+	}
+
+	ft.mu.Lock()
+	points := ft.points[userID][rest.ID]
+	ft.mu.Unlock()
+	info.Points = &points
+	return info, nil
+}
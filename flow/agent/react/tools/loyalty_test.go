@@ -0,0 +1,54 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tools
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQueryLoyaltyInfo(t *testing.T) {
+	ctx := context.Background()
+
+	info, err := restService.QueryLoyaltyInfo(WithUserID(ctx, "u1001"), &LoyaltyInfoParam{RestaurantID: "1002"})
+	assert.NoError(t, err)
+	assert.True(t, info.HasProgram)
+	assert.Equal(t, "聚福卡", info.ProgramName)
+	assert.Equal(t, 2.0, info.PointsPerYuan)
+	assert.False(t, info.Guest)
+	assert.Equal(t, 1280, *info.Points)
+
+	// 有积分计划但是没有积分记录
+	info, err = restService.QueryLoyaltyInfo(WithUserID(ctx, "u2002"), &LoyaltyInfoParam{RestaurantID: "1001"})
+	assert.NoError(t, err)
+	assert.Equal(t, 0, *info.Points)
+
+	info, err = restService.QueryLoyaltyInfo(ctx, &LoyaltyInfoParam{RestaurantID: "1001"})
+	assert.NoError(t, err)
+	assert.True(t, info.HasProgram)
+	assert.True(t, info.Guest)
+	assert.Nil(t, info.Points)
+	assert.Contains(t, info.Message, "not signed in")
+
+	info, err = restService.QueryLoyaltyInfo(WithUserID(ctx, "u1001"), &LoyaltyInfoParam{RestaurantID: "1003"})
+	assert.NoError(t, err)
+	assert.False(t, info.HasProgram)
+	assert.Nil(t, info.Points)
+	assert.Contains(t, info.Message, "no loyalty program")
+}
@@ -0,0 +1,154 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/cloudwego/eino/components/tool"
+	"github.com/cloudwego/eino/schema"
+)
+
+func GetMealDurationTool() tool.InvokableTool {
+	return safeTool{
+		InvokableTool: NewCancellableTool(&ToolMealDuration{
+			backService: restService,
+		}),
+	}
+}
+
+// ToolMealDuration 根据每道菜的制作时间估算一顿饭从点菜到吃完的总时长, 适合 "吃完能不能赶上 8 点的电影" 这类规划问题.
+type ToolMealDuration struct {
+	backService *fakeService // fake service
+}
+
+func (t *ToolMealDuration) Info(ctx context.Context) (*schema.ToolInfo, error) {
+	return &schema.ToolInfo{
+		Name: "estimate_meal_duration",
+		Desc: "Estimate how long a meal takes in a restaurant, from ordering until finishing eating, for a list of dishes. " +
+			"Returns the waiting time, the eating time and the prep time of every dish",
+		ParamsOneOf: schema.NewParamsOneOfByParams(map[string]*schema.ParameterInfo{
+			"restaurant_id": {
+				Type:     "string",
+				Desc:     "The id of one restaurant",
+				Required: true,
+			},
+			"dish_names": {
+				Type:     "array",
+				Desc:     "The names of the dishes to order",
+				ElemInfo: &schema.ParameterInfo{Type: "string"},
+				Required: true,
+			},
+		}),
+	}, nil
+}
+
+func (t *ToolMealDuration) InvokableRun(ctx context.Context, argumentsInJSON string, opts ...tool.Option) (string, error) {
+	// 解析参数
+	if err := checkRequired(ctx, t, argumentsInJSON); err != nil {
+		return "", err
+	}
+	p := &MealDurationParam{}
+	err := json.Unmarshal([]byte(argumentsInJSON), p)
+	if err != nil {
+		return "", err
+	}
+
+	// 请求后端服务
+	d, err := t.backService.EstimateMealDuration(ctx, p)
+	if err != nil {
+		return "", err
+	}
+
+	// 序列化结果
+	res, err := marshalResult(d)
+	if err != nil {
+		return "", err
+	}
+
+	return string(res), nil
+}
+
+type MealDurationParam struct {
+	RestaurantID string   `json:"restaurant_id"`
+	DishNames    []string `json:"dish_names"`
+}
+
+type MealDuration struct {
+	RestaurantID string             `json:"restaurant_id"`
+	Dishes       []DishPrepDuration `json:"dishes"`
+	// WaitMinutes 是等菜的时间: 厨房同时做所有的菜, 所以取决于最慢的那道
+	WaitMinutes int `json:"wait_minutes"`
+	// EatMinutes 按每道菜 eatMinutesPerDish 分钟估算
+	EatMinutes   int `json:"eat_minutes"`
+	TotalMinutes int `json:"total_minutes"`
+}
+
+type DishPrepDuration struct {
+	Name        string `json:"name"`
+	PrepMinutes int    `json:"prep_minutes"`
+	// Estimated 为 true 表示餐厅没有这道菜的制作时间, 使用了 defaultPrepMinutes
+	Estimated bool `json:"estimated,omitempty"`
+}
+
+const (
+	defaultPrepMinutes = 15
+	eatMinutesPerDish  = 10
+)
+
+// EstimateMealDuration 估算在 in.RestaurantID 点 in.DishNames 这些菜的用餐时长.
+func (ft *fakeService) EstimateMealDuration(ctx context.Context, in *MealDurationParam) (*MealDuration, error) {
+	if err := ft.simulateLatency(ctx); err != nil {
+		return nil, err
+	}
+
+	rest, err := ft.repo.GetRestaurantByID(ctx, in.RestaurantID)
+	if err != nil {
+		return nil, err
+	}
+
+	dishes := make([]restaurantDishDataItem, 0, len(in.DishNames))
+	for _, name := range in.DishNames {
+		dish, ok := findDish(rest.Dishes, name)
+		if !ok {
+			return nil, fmt.Errorf("dish %s not found in restaurant %s", name, in.RestaurantID)
+		}
+		dishes = append(dishes, dish)
+	}
+
+	d := mealDuration(dishes)
+	d.RestaurantID = rest.ID
+	return d, nil
+}
+
+func mealDuration(dishes []restaurantDishDataItem) *MealDuration {
+	d := &MealDuration{Dishes: make([]DishPrepDuration, 0, len(dishes))}
+	for _, dish := range dishes {
+		prep := DishPrepDuration{Name: dish.Name, PrepMinutes: dish.PrepMinutes}
+		if prep.PrepMinutes <= 0 {
+			prep.PrepMinutes = defaultPrepMinutes
+			prep.Estimated = true
+		}
+		d.Dishes = append(d.Dishes, prep)
+		d.WaitMinutes = max(d.WaitMinutes, prep.PrepMinutes)
+	}
+	d.EatMinutes = eatMinutesPerDish * len(dishes)
+	d.TotalMinutes = d.WaitMinutes + d.EatMinutes
+	return d
+}
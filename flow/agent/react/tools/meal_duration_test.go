@@ -0,0 +1,53 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tools
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMealDuration(t *testing.T) {
+	d := mealDuration([]restaurantDishDataItem{
+		{Name: "a", PrepMinutes: 30},
+		{Name: "b", PrepMinutes: 5},
+		{Name: "c"},
+	})
+	assert.Equal(t, 30, d.WaitMinutes)
+	assert.Equal(t, 30, d.EatMinutes)
+	assert.Equal(t, 60, d.TotalMinutes)
+	assert.Equal(t, DishPrepDuration{Name: "c", PrepMinutes: defaultPrepMinutes, Estimated: true}, d.Dishes[2])
+
+	empty := mealDuration(nil)
+	assert.Zero(t, empty.TotalMinutes)
+	assert.NotNil(t, empty.Dishes)
+}
+
+func TestEstimateMealDuration(t *testing.T) {
+	ctx := context.Background()
+
+	d, err := restService.EstimateMealDuration(ctx, &MealDurationParam{RestaurantID: "1001", DishNames: []string{"红烧肉", "酸辣土豆丝"}})
+	assert.NoError(t, err)
+	assert.Equal(t, "1001", d.RestaurantID)
+	assert.Equal(t, 35, d.WaitMinutes)
+	assert.Equal(t, 55, d.TotalMinutes)
+
+	_, err = restService.EstimateMealDuration(ctx, &MealDurationParam{RestaurantID: "1001", DishNames: []string{"不存在的菜"}})
+	assert.ErrorContains(t, err, "not found")
+}
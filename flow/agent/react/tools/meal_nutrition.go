@@ -0,0 +1,145 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tools
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/cloudwego/eino/components/tool"
+	"github.com/cloudwego/eino/schema"
+)
+
+func GetMealNutritionTool() tool.InvokableTool {
+	return safeTool{
+		InvokableTool: NewCancellableTool(&ToolMealNutrition{
+			backService: restService,
+		}),
+	}
+}
+
+// ToolMealNutrition 汇总一顿饭中每道菜的营养成分, 适合 "这几道菜加起来热量多少" 这类控制饮食的问题.
+type ToolMealNutrition struct {
+	backService *fakeService // fake service
+}
+
+func (t *ToolMealNutrition) Info(ctx context.Context) (*schema.ToolInfo, error) {
+	return &schema.ToolInfo{
+		Name: "query_meal_nutrition",
+		Desc: "Sum the calories, protein, carbs and fat of a list of dishes ordered together in one restaurant. " +
+			"Returns the nutrition of every dish and the total; dishes not on the menu are listed as skipped",
+		ParamsOneOf: schema.NewParamsOneOfByParams(map[string]*schema.ParameterInfo{
+			"restaurant_id": {
+				Type:     "string",
+				Desc:     "The id of one restaurant",
+				Required: true,
+			},
+			"dish_names": {
+				Type:     "array",
+				Desc:     "The names of the dishes in the meal",
+				ElemInfo: &schema.ParameterInfo{Type: "string"},
+				Required: true,
+			},
+		}),
+	}, nil
+}
+
+func (t *ToolMealNutrition) InvokableRun(ctx context.Context, argumentsInJSON string, opts ...tool.Option) (string, error) {
+	// 解析参数
+	if err := checkRequired(ctx, t, argumentsInJSON); err != nil {
+		return "", err
+	}
+	p := &MealNutritionParam{}
+	err := json.Unmarshal([]byte(argumentsInJSON), p)
+	if err != nil {
+		return "", err
+	}
+
+	// 请求后端服务
+	meal, err := t.backService.QueryMealNutrition(ctx, p)
+	if err != nil {
+		return "", err
+	}
+
+	// 序列化结果
+	res, err := marshalResult(meal)
+	if err != nil {
+		return "", err
+	}
+
+	return string(res), nil
+}
+
+type MealNutritionParam struct {
+	RestaurantID string   `json:"restaurant_id"`
+	DishNames    []string `json:"dish_names"`
+}
+
+type MealNutrition struct {
+	RestaurantID string          `json:"restaurant_id"`
+	Dishes       []DishNutrition `json:"dishes"`
+	Total        Nutrition       `json:"total"`
+	// TotalIsPartial 为 true 表示有的菜没有营养数据, 没有计入 total, 实际的热量会更高
+	TotalIsPartial bool `json:"total_is_partial"`
+	// Skipped 是菜单上找不到的菜名
+	Skipped []string `json:"skipped,omitempty"`
+}
+
+// QueryMealNutrition 汇总在 in.RestaurantID 点 in.DishNames 这些菜的营养成分, 找不到的菜跳过而不是报错.
+func (ft *fakeService) QueryMealNutrition(ctx context.Context, in *MealNutritionParam) (*MealNutrition, error) {
+	if err := ft.simulateLatency(ctx); err != nil {
+		return nil, err
+	}
+
+	rest, err := ft.repo.GetRestaurantByID(ctx, in.RestaurantID)
+	if err != nil {
+		return nil, err
+	}
+
+	meal := sumNutrition(rest.Dishes, in.DishNames)
+	meal.RestaurantID = rest.ID
+	return meal, nil
+}
+
+func sumNutrition(menu []restaurantDishDataItem, names []string) *MealNutrition {
+	meal := &MealNutrition{Dishes: make([]DishNutrition, 0, len(names))}
+	for _, name := range names {
+		dish, ok := findDish(menu, name)
+		if !ok {
+			meal.Skipped = append(meal.Skipped, name)
+			continue
+		}
+		if dish.Nutrition == nil {
+			meal.TotalIsPartial = true
+			meal.Dishes = append(meal.Dishes, DishNutrition{
+				Name:    dish.Name,
+				Message: "nutrition unavailable: not included in the total",
+			})
+			continue
+		}
+		meal.Dishes = append(meal.Dishes, DishNutrition{
+			Name:               dish.Name,
+			NutritionAvailable: true,
+			Nutrition:          toNutrition(dish.Nutrition),
+		})
+		meal.Total.Calories += dish.Nutrition.Calories
+		meal.Total.ProteinG += dish.Nutrition.ProteinG
+		meal.Total.CarbsG += dish.Nutrition.CarbsG
+		meal.Total.FatG += dish.Nutrition.FatG
+	}
+	return meal
+}
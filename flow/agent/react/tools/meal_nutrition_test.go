@@ -0,0 +1,62 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tools
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQueryMealNutrition(t *testing.T) {
+	ctx := context.Background()
+
+	meal, err := restService.QueryMealNutrition(ctx, &MealNutritionParam{
+		RestaurantID: "1001",
+		DishNames:    []string{"红烧肉", "清炒小南瓜", "佛跳墙"},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "1001", meal.RestaurantID)
+	assert.Len(t, meal.Dishes, 2)
+	assert.Equal(t, Nutrition{Calories: 830, ProteinG: 31, CarbsG: 44, FatG: 60}, meal.Total)
+	assert.False(t, meal.TotalIsPartial)
+	assert.Equal(t, []string{"佛跳墙"}, meal.Skipped)
+
+	_, err = restService.QueryMealNutrition(ctx, &MealNutritionParam{RestaurantID: "404", DishNames: []string{"红烧肉"}})
+	assert.Error(t, err)
+}
+
+func TestSumNutrition(t *testing.T) {
+	menu := []restaurantDishDataItem{
+		{Name: "a", Nutrition: &restaurantNutritionItem{Calories: 100, ProteinG: 1.5, CarbsG: 2, FatG: 3}},
+		{Name: "b"},
+	}
+
+	// 没有营养数据的菜列出来, 但不计入 total
+	meal := sumNutrition(menu, []string{"a", "b", "a"})
+	assert.Len(t, meal.Dishes, 3)
+	assert.False(t, meal.Dishes[1].NutritionAvailable)
+	assert.NotEmpty(t, meal.Dishes[1].Message)
+	assert.Equal(t, Nutrition{Calories: 200, ProteinG: 3, CarbsG: 4, FatG: 6}, meal.Total)
+	assert.True(t, meal.TotalIsPartial)
+	assert.Empty(t, meal.Skipped)
+
+	meal = sumNutrition(menu, nil)
+	assert.Empty(t, meal.Dishes)
+	assert.Zero(t, meal.Total)
+}
@@ -0,0 +1,164 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tools
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+
+	"github.com/cloudwego/eino/components/tool"
+	"github.com/cloudwego/eino/schema"
+)
+
+func GetMenuQRTool() tool.InvokableTool {
+	return safeTool{
+		InvokableTool: NewCancellableTool(&ToolMenuQR{
+			backService: restService,
+		}),
+	}
+}
+
+// ToolMenuQR 和 create_share_link 一样会产生持久状态: 把餐厅当前的菜单保存成一份快照,
+// 返回指向快照的链接和生成二维码要编码的字符串, 扫码看到的是生成时的菜单.
+type ToolMenuQR struct {
+	backService *fakeService // fake service
+}
+
+func (t *ToolMenuQR) Info(ctx context.Context) (*schema.ToolInfo, error) {
+	return &schema.ToolInfo{
+		Name: "create_menu_qr",
+		Desc: "Save a snapshot of the current menu of a restaurant and return a link to it, " +
+			"with the exact string to encode in a QR code the user can print or show",
+		ParamsOneOf: schema.NewParamsOneOfByParams(map[string]*schema.ParameterInfo{
+			"restaurant_id": {
+				Type:     "string",
+				Desc:     "The id of one restaurant",
+				Required: true,
+			},
+		}),
+	}, nil
+}
+
+func (t *ToolMenuQR) InvokableRun(ctx context.Context, argumentsInJSON string, opts ...tool.Option) (string, error) {
+	// 解析参数
+	if err := checkRequired(ctx, t, argumentsInJSON); err != nil {
+		return "", err
+	}
+	p := &MenuQRParam{}
+	err := json.Unmarshal([]byte(argumentsInJSON), p)
+	if err != nil {
+		return "", err
+	}
+
+	// 请求后端服务
+	qr, err := t.backService.CreateMenuQR(ctx, p)
+	if err != nil {
+		return "", err
+	}
+
+	// 序列化结果
+	res, err := marshalResult(qr)
+	if err != nil {
+		return "", err
+	}
+
+	return string(res), nil
+}
+
+type MenuQRParam struct {
+	RestaurantID string `json:"restaurant_id"`
+}
+
+// MenuQR 中 qr_payload 是二维码要编码的内容, 原样交给任意一个二维码库即可, 不要再做修改.
+type MenuQR struct {
+	RestaurantID string `json:"restaurant_id"`
+	URL          string `json:"url"`
+	Token        string `json:"token"`
+	QRPayload    string `json:"qr_payload"`
+	DishCount    int    `json:"dish_count"`
+}
+
+// MenuSnapshot 是生成二维码时保存的菜单.
+type MenuSnapshot struct {
+	RestaurantID string `json:"restaurant_id"`
+	Name         string `json:"name"`
+	Dishes       []Dish `json:"dishes"`
+}
+
+const menuQRBaseURL = "https://eino.example.com/menu/"
+
+// CreateMenuQR 保存 in.RestaurantID 现在的菜单. token 由餐厅和菜单的内容决定, 菜单没变时重复生成得到同一个链接,
+// 菜单变了则得到新的链接, 旧的链接仍然指向旧的快照.
+func (ft *fakeService) CreateMenuQR(ctx context.Context, in *MenuQRParam) (*MenuQR, error) {
+	if err := ft.simulateLatency(ctx); err != nil {
+		return nil, err
+	}
+
+	rest, err := ft.repo.GetRestaurantByID(ctx, in.RestaurantID)
+	if err != nil {
+		return nil, err
+	}
+	if len(rest.Dishes) == 0 {
+		return nil, fmt.Errorf("restaurant %s has no menu to share", rest.ID)
+	}
+
+	snap := MenuSnapshot{RestaurantID: rest.ID, Name: rest.Name, Dishes: make([]Dish, 0, len(rest.Dishes))}
+	for _, d := range rest.Dishes {
+		snap.Dishes = append(snap.Dishes, toDish(d))
+	}
+	token, err := menuQRToken(snap)
+	if err != nil {
+		return nil, err
+	}
+
+	ft.mu.Lock()
+	defer ft.mu.Unlock()
+	if ft.menuQRs == nil {
+		ft.menuQRs = make(map[string]MenuSnapshot)
+	}
+	ft.menuQRs[token] = snap
+
+	url := menuQRBaseURL + token
+	return &MenuQR{
+		RestaurantID: rest.ID,
+		URL:          url,
+		Token:        token,
+		QRPayload:    url,
+		DishCount:    len(snap.Dishes),
+	}, nil
+}
+
+// MenuSnapshotOf 返回 token 对应的菜单快照, 用于调试和测试.
+func (ft *fakeService) MenuSnapshotOf(token string) (MenuSnapshot, bool) {
+	ft.mu.Lock()
+	defer ft.mu.Unlock()
+	snap, ok := ft.menuQRs[token]
+	return snap, ok
+}
+
+// menuQRToken 由快照的内容决定, 保证可复现 (同 shareToken).
+func menuQRToken(snap MenuSnapshot) (string, error) {
+	b, err := json.Marshal(snap)
+	if err != nil {
+		return "", err
+	}
+	sum := sha1.Sum(b)
+	return hex.EncodeToString(sum[:])[:12], nil
+}
@@ -0,0 +1,66 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tools
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateMenuQR(t *testing.T) {
+	ctx := context.Background()
+	svc := &fakeService{repo: database}
+
+	qr, err := svc.CreateMenuQR(ctx, &MenuQRParam{RestaurantID: "1003"})
+	assert.NoError(t, err)
+	assert.Len(t, qr.Token, 12)
+	assert.Equal(t, menuQRBaseURL+qr.Token, qr.URL)
+	assert.Equal(t, qr.URL, qr.QRPayload)
+	assert.Equal(t, 3, qr.DishCount)
+
+	snap, ok := svc.MenuSnapshotOf(qr.Token)
+	assert.True(t, ok)
+	assert.Equal(t, "1003", snap.RestaurantID)
+	assert.Len(t, snap.Dishes, 3)
+
+	// 菜单没变时 token 不变, 不同餐厅的 token 不同
+	again, err := svc.CreateMenuQR(ctx, &MenuQRParam{RestaurantID: "1003"})
+	assert.NoError(t, err)
+	assert.Equal(t, qr.Token, again.Token)
+	other, err := svc.CreateMenuQR(ctx, &MenuQRParam{RestaurantID: "1001"})
+	assert.NoError(t, err)
+	assert.NotEqual(t, qr.Token, other.Token)
+
+	// 菜单变了得到新的 token, 旧的快照保持不变
+	repo := &restaurantDatabase{restaurantByID: map[string]restaurantDataItem{
+		"1003": {ID: "1003", Name: "新菜单", Dishes: []restaurantDishDataItem{{Name: "新菜", Price: 10}}},
+		"9000": {ID: "9000", Name: "没有菜单"},
+	}}
+	svc.repo = repo
+	changed, err := svc.CreateMenuQR(ctx, &MenuQRParam{RestaurantID: "1003"})
+	assert.NoError(t, err)
+	assert.NotEqual(t, qr.Token, changed.Token)
+	snap, _ = svc.MenuSnapshotOf(qr.Token)
+	assert.Len(t, snap.Dishes, 3)
+
+	_, err = svc.CreateMenuQR(ctx, &MenuQRParam{RestaurantID: "9000"})
+	assert.ErrorContains(t, err, "no menu")
+	_, err = svc.CreateMenuQR(ctx, &MenuQRParam{RestaurantID: "404"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,122 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tools
+
+import (
+	"time"
+
+	"github.com/cloudwego/eino/components/tool"
+)
+
+// ToolMiddleware 包装一个 tool, 在调用前后加上某种能力 (错误处理、重试、缓存、参数检查等).
+// 本包的 safeTool、retryTool、cachedTool、guardTool 等包装都可以表示为 ToolMiddleware, 用 Chain 按需要的顺序组合.
+type ToolMiddleware func(tool.InvokableTool) tool.InvokableTool
+
+// Chain 把多个 middleware 合成一个, 第一个在最外层: Chain(a, b)(t) 等价于 a(b(t)), 调用时先经过 a 再经过 b.
+func Chain(middlewares ...ToolMiddleware) ToolMiddleware {
+	return func(t tool.InvokableTool) tool.InvokableTool {
+		for i := len(middlewares) - 1; i >= 0; i-- {
+			t = middlewares[i](t)
+		}
+		return t
+	}
+}
+
+// ApplyMiddleware 用 Chain(middlewares...) 包装每个 tool, 返回新的列表, 不修改 ts.
+// middleware 只支持 InvokableTool, 只实现了 StreamableTool 的 tool 原样保留.
+func ApplyMiddleware(ts []tool.BaseTool, middlewares ...ToolMiddleware) []tool.BaseTool {
+	chain := Chain(middlewares...)
+	res := make([]tool.BaseTool, 0, len(ts))
+	for _, t := range ts {
+		if it, ok := t.(tool.InvokableTool); ok {
+			res = append(res, chain(it))
+			continue
+		}
+		res = append(res, t)
+	}
+	return res
+}
+
+// SafeMiddleware 把 tool 的错误转成 content 交给模型, 见 NewSafeTool.
+func SafeMiddleware() ToolMiddleware {
+	return NewSafeTool
+}
+
+// RetryMiddleware 见 NewRetryTool.
+func RetryMiddleware(config RetryConfig) ToolMiddleware {
+	return func(t tool.InvokableTool) tool.InvokableTool {
+		return NewRetryTool(t, config)
+	}
+}
+
+// CacheMiddleware 见 NewCachedTool. cache 为空时不缓存, 原样返回 tool.
+func CacheMiddleware(cache *ResultCache) ToolMiddleware {
+	return func(t tool.InvokableTool) tool.InvokableTool {
+		if cache == nil {
+			return t
+		}
+		return NewCachedTool(t, cache)
+	}
+}
+
+// GuardMiddleware 见 NewGuardTool.
+func GuardMiddleware(rules ...GuardRule) ToolMiddleware {
+	return func(t tool.InvokableTool) tool.InvokableTool {
+		return NewGuardTool(t, rules...)
+	}
+}
+
+// ArgSizeLimitMiddleware 见 NewArgSizeLimitTool.
+func ArgSizeLimitMiddleware(maxBytes int) ToolMiddleware {
+	return func(t tool.InvokableTool) tool.InvokableTool {
+		return NewArgSizeLimitTool(t, maxBytes)
+	}
+}
+
+// DedupMiddleware 见 NewDedupTool.
+func DedupMiddleware() ToolMiddleware {
+	return NewDedupTool
+}
+
+// CircuitBreakerMiddleware 见 NewCircuitBreakerTool.
+func CircuitBreakerMiddleware(config CircuitBreakerConfig) ToolMiddleware {
+	return func(t tool.InvokableTool) tool.InvokableTool {
+		return NewCircuitBreakerTool(t, config)
+	}
+}
+
+// TimeoutMiddleware 见 NewTimeoutTool.
+func TimeoutMiddleware(timeout time.Duration) ToolMiddleware {
+	return func(t tool.InvokableTool) tool.InvokableTool {
+		return NewTimeoutTool(t, timeout)
+	}
+}
+
+// JSONArgsMiddleware 见 NewJSONArgsTool.
+func JSONArgsMiddleware() ToolMiddleware {
+	return NewJSONArgsTool
+}
+
+// SchemaVersionMiddleware 见 NewSchemaVersionTool.
+func SchemaVersionMiddleware() ToolMiddleware {
+	return NewSchemaVersionTool
+}
+
+// ToolBudgetMiddleware 见 NewToolBudgetTool.
+func ToolBudgetMiddleware() ToolMiddleware {
+	return NewToolBudgetTool
+}
@@ -0,0 +1,90 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tools
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/cloudwego/eino/components/tool"
+	"github.com/stretchr/testify/assert"
+)
+
+// tagTool 在被包装的 tool 的结果前加上 tag, 用于观察 middleware 的顺序.
+type tagTool struct {
+	tool.InvokableTool
+	tag string
+}
+
+func (t tagTool) InvokableRun(ctx context.Context, argumentsInJSON string, opts ...tool.Option) (string, error) {
+	out, err := t.InvokableTool.InvokableRun(ctx, argumentsInJSON, opts...)
+	return t.tag + out, err
+}
+
+func tagMiddleware(tag string) ToolMiddleware {
+	return func(t tool.InvokableTool) tool.InvokableTool {
+		return tagTool{InvokableTool: t, tag: tag}
+	}
+}
+
+func TestChain(t *testing.T) {
+	ctx := context.Background()
+
+	// 第一个 middleware 在最外层
+	out, err := Chain(tagMiddleware("a:"), tagMiddleware("b:"))(&flakyTool{}).InvokableRun(ctx, `{}`)
+	assert.NoError(t, err)
+	assert.Equal(t, "a:b:done", out)
+
+	out, err = Chain()(&flakyTool{}).InvokableRun(ctx, `{}`)
+	assert.NoError(t, err)
+	assert.Equal(t, "done", out)
+
+	// safeTool 在 retryTool 外层时, 重试用完后错误才转成 content
+	flaky := &flakyTool{failures: 5, err: errors.New("temporary")}
+	out, err = Chain(SafeMiddleware(), RetryMiddleware(RetryConfig{MaxAttempts: 2}))(flaky).InvokableRun(ctx, `{}`)
+	assert.NoError(t, err)
+	assert.Equal(t, "temporary", out)
+	assert.Equal(t, 2, flaky.calls)
+}
+
+func TestApplyMiddleware(t *testing.T) {
+	ctx := context.Background()
+
+	streaming := GetFormatMenuTool(true)
+	ts := []tool.BaseTool{&flakyTool{}, streaming}
+	wrapped := ApplyMiddleware(ts, tagMiddleware("a:"), GuardMiddleware(), ArgSizeLimitMiddleware(8))
+
+	assert.Len(t, wrapped, 2)
+	_, unchanged := ts[0].(*flakyTool)
+	assert.True(t, unchanged, "the input list is not modified")
+	assert.Equal(t, streaming, wrapped[1])
+
+	out, err := wrapped[0].(tool.InvokableTool).InvokableRun(ctx, `{}`)
+	assert.NoError(t, err)
+	assert.Equal(t, "a:done", out)
+
+	// 内层的 middleware 拒绝的调用不会到达 tool
+	out, err = wrapped[0].(tool.InvokableTool).InvokableRun(ctx, `{"a": "0123456789"}`)
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(out, "a:{"), out)
+	assert.Equal(t, 1, ts[0].(*flakyTool).calls)
+
+	// 没有 cache 时 CacheMiddleware 原样返回
+	assert.Equal(t, ts[0], CacheMiddleware(nil)(ts[0].(tool.InvokableTool)))
+}
@@ -0,0 +1,152 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"sort"
+
+	"github.com/cloudwego/eino/components/tool"
+	"github.com/cloudwego/eino/schema"
+)
+
+func GetMostReviewedTool() tool.InvokableTool {
+	return safeTool{
+		InvokableTool: NewCancellableTool(&ToolMostReviewed{
+			backService: restService,
+		}),
+	}
+}
+
+// ToolMostReviewed 按评价数量而不是评分给餐厅排名, 回答 "哪家最火" 这类问题: 评分高的餐厅不一定去的人多.
+type ToolMostReviewed struct {
+	backService *fakeService // fake service
+}
+
+func (t *ToolMostReviewed) Info(ctx context.Context) (*schema.ToolInfo, error) {
+	return &schema.ToolInfo{
+		Name: "query_most_reviewed",
+		Desc: "Rank restaurants by how many user reviews they have, to answer what is popular. " +
+			"Returns the review count and the average rating of every restaurant",
+		ParamsOneOf: schema.NewParamsOneOfByParams(map[string]*schema.ParameterInfo{
+			"location": {
+				Type: "string",
+				Desc: "The location of the restaurants, leave empty for all locations",
+			},
+			"topn": {
+				Type: "number",
+				Desc: "How many restaurants to return, default 3",
+			},
+		}),
+	}, nil
+}
+
+func (t *ToolMostReviewed) InvokableRun(ctx context.Context, argumentsInJSON string, opts ...tool.Option) (string, error) {
+	// 解析参数
+	p := &MostReviewedParam{}
+	err := json.Unmarshal([]byte(argumentsInJSON), p)
+	if err != nil {
+		return "", err
+	}
+	if p.Topn == 0 {
+		p.Topn = 3
+	}
+
+	// 请求后端服务
+	ranking, err := t.backService.QueryMostReviewed(ctx, p)
+	if err != nil {
+		return "", err
+	}
+
+	// 序列化结果
+	res, err := marshalResult(ranking)
+	if err != nil {
+		return "", err
+	}
+
+	return string(res), nil
+}
+
+type MostReviewedParam struct {
+	Location string `json:"location"`
+	Topn     int    `json:"topn"`
+}
+
+type MostReviewed struct {
+	Results []ReviewedRestaurant `json:"results"`
+}
+
+type ReviewedRestaurant struct {
+	Rank        int    `json:"rank"`
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Place       string `json:"place"`
+	Score       int    `json:"score"`
+	ReviewCount int    `json:"review_count"`
+	// AverageRating 是评价的平均星级 (1 - 5), 保留一位小数, 没有评价时为 0
+	AverageRating float64 `json:"average_rating"`
+}
+
+// QueryMostReviewed 把 in.Location 的餐厅按评价数量从多到少排序, 数量相同时评分高的在前, 取前 in.Topn 家.
+func (ft *fakeService) QueryMostReviewed(ctx context.Context, in *MostReviewedParam) (*MostReviewed, error) {
+	if err := ft.simulateLatency(ctx); err != nil {
+		return nil, err
+	}
+
+	rests, err := ft.repo.GetRestaurants(ctx, in.Location)
+	if err != nil {
+		return nil, err
+	}
+
+	ranked := append([]restaurantDataItem(nil), rests...)
+	sort.SliceStable(ranked, func(i, j int) bool {
+		if len(ranked[i].Reviews) != len(ranked[j].Reviews) {
+			return len(ranked[i].Reviews) > len(ranked[j].Reviews)
+		}
+		return ranked[i].Score > ranked[j].Score
+	})
+	if in.Topn > 0 && len(ranked) > in.Topn {
+		ranked = ranked[:in.Topn]
+	}
+
+	out := &MostReviewed{Results: make([]ReviewedRestaurant, 0, len(ranked))}
+	for i, rest := range ranked {
+		out.Results = append(out.Results, ReviewedRestaurant{
+			Rank:          i + 1,
+			ID:            rest.ID,
+			Name:          rest.Name,
+			Place:         rest.Place,
+			Score:         rest.Score,
+			ReviewCount:   len(rest.Reviews),
+			AverageRating: averageRating(rest.Reviews),
+		})
+	}
+	return out, nil
+}
+
+func averageRating(reviews []restaurantReviewItem) float64 {
+	if len(reviews) == 0 {
+		return 0
+	}
+	sum := 0
+	for _, r := range reviews {
+		sum += r.Rating
+	}
+	return math.Round(float64(sum)/float64(len(reviews))*10) / 10
+}
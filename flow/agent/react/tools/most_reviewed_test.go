@@ -0,0 +1,52 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tools
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQueryMostReviewed(t *testing.T) {
+	ctx := context.Background()
+
+	ranking, err := restService.QueryMostReviewed(ctx, &MostReviewedParam{Topn: 3})
+	assert.NoError(t, err)
+	var ids []string
+	for i, r := range ranking.Results {
+		ids = append(ids, r.ID)
+		assert.Equal(t, i+1, r.Rank)
+	}
+	assert.Equal(t, []string{"1002", "2002", "1001"}, ids)
+	assert.Equal(t, 6, ranking.Results[0].ReviewCount)
+	assert.Equal(t, 4.2, ranking.Results[0].AverageRating)
+
+	// 评价数量和评分是不同的排名: 评分最高的 2010 评价最少
+	ranking, err = restService.QueryMostReviewed(ctx, &MostReviewedParam{Location: "上海", Topn: 10})
+	assert.NoError(t, err)
+	ids = nil
+	for _, r := range ranking.Results {
+		ids = append(ids, r.ID)
+	}
+	assert.Equal(t, []string{"2002", "2001", "2010"}, ids)
+	assert.Equal(t, 10, ranking.Results[2].Score)
+
+	_, err = restService.QueryMostReviewed(ctx, &MostReviewedParam{Location: "火星"})
+	assert.Error(t, err)
+}
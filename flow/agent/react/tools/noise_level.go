@@ -0,0 +1,151 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+
+	"github.com/cloudwego/eino/components/tool"
+	"github.com/cloudwego/eino/schema"
+)
+
+func GetNoiseLevelTool() tool.InvokableTool {
+	return safeTool{
+		InvokableTool: NewCancellableTool(&ToolNoiseLevel{
+			backService: restService,
+		}),
+	}
+}
+
+// ToolNoiseLevel 估算餐厅现在有多吵、能不能轻松聊天. 分贝由两部分组成: query_ambiance 的 noise_level 决定空座时的基础噪音,
+// query_busy_hours 同一条繁忙程度曲线决定人多时增加的噪音, "现在" 来自 fake 后端的 clock.
+type ToolNoiseLevel struct {
+	backService *fakeService // fake service
+}
+
+func (t *ToolNoiseLevel) Info(ctx context.Context) (*schema.ToolInfo, error) {
+	return &schema.ToolInfo{
+		Name: "query_noise_level",
+		Desc: "Estimate how loud a restaurant is right now in decibels and whether it is easy to have a conversation there, " +
+			"e.g. for a business meeting or a date. Returns the reasoning behind the estimate",
+		ParamsOneOf: schema.NewParamsOneOfByParams(map[string]*schema.ParameterInfo{
+			"restaurant_id": {
+				Type:     "string",
+				Desc:     "The id of one restaurant",
+				Required: true,
+			},
+		}),
+	}, nil
+}
+
+func (t *ToolNoiseLevel) InvokableRun(ctx context.Context, argumentsInJSON string, opts ...tool.Option) (string, error) {
+	// 解析参数
+	if err := checkRequired(ctx, t, argumentsInJSON); err != nil {
+		return "", err
+	}
+	p := &NoiseLevelParam{}
+	err := json.Unmarshal([]byte(argumentsInJSON), p)
+	if err != nil {
+		return "", err
+	}
+
+	// 请求后端服务
+	noise, err := t.backService.NoiseLevel(ctx, p)
+	if err != nil {
+		return "", err
+	}
+
+	// 序列化结果
+	res, err := marshalResult(noise)
+	if err != nil {
+		return "", err
+	}
+
+	return string(res), nil
+}
+
+type NoiseLevelParam struct {
+	RestaurantID string `json:"restaurant_id"`
+}
+
+// NoiseLevel 在不营业的时段 open 为 false, 没有分贝的估算.
+type NoiseLevel struct {
+	RestaurantID         string `json:"restaurant_id"`
+	Open                 bool   `json:"open"`
+	Decibels             int    `json:"decibels,omitempty"`
+	ConversationFriendly bool   `json:"conversation_friendly"`
+	Reasoning            string `json:"reasoning"`
+}
+
+const (
+	// defaultNoiseLevel 是没有氛围数据的餐厅按中等 (1 - 5 的 3) 计算的 noise_level
+	defaultNoiseLevel = 3
+	// conversationMaxDecibels 以下可以用正常的音量交谈, 再高就要提高嗓门
+	conversationMaxDecibels = 65
+	// crowdMaxDecibels 是满座 (繁忙程度 100) 时比空座多出的噪音
+	crowdMaxDecibels = 12
+)
+
+// NoiseLevel 按 noise_level 和当前小时的 busyness 估算分贝, 同一时刻总是得到相同的结果.
+func (ft *fakeService) NoiseLevel(ctx context.Context, in *NoiseLevelParam) (*NoiseLevel, error) {
+	if err := ft.simulateLatency(ctx); err != nil {
+		return nil, err
+	}
+
+	rest, err := ft.repo.GetRestaurantByID(ctx, in.RestaurantID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := ft.now()
+	out := &NoiseLevel{RestaurantID: rest.ID}
+	if now.Hour() < busyFirstHour || now.Hour() > busyLastHour {
+		out.Reasoning = fmt.Sprintf("the restaurant is closed, it is open from %02d:00 to %02d:59", busyFirstHour, busyLastHour)
+		return out, nil
+	}
+
+	level, source := defaultNoiseLevel, "no ambiance data, assuming an average noise level 3 of 5"
+	if rest.Ambiance != nil && rest.Ambiance.NoiseLevel > 0 {
+		level, source = rest.Ambiance.NoiseLevel, fmt.Sprintf("noise level %d of 5", rest.Ambiance.NoiseLevel)
+	}
+	busy := busyness(rest.ID, now.Weekday(), now.Hour())
+	base, crowd := baseDecibels(level), crowdDecibels(busy)
+
+	out.Open = true
+	out.Decibels = base + crowd
+	out.ConversationFriendly = out.Decibels <= conversationMaxDecibels
+	verdict := "easy to talk at a normal volume"
+	if !out.ConversationFriendly {
+		verdict = "expect to raise your voice to be heard"
+	}
+	out.Reasoning = fmt.Sprintf("%s is about %d dB when empty, %d%% busy at %02d:00 adds %d dB, %d dB in total: %s (up to %d dB is comfortable)",
+		source, base, busy, now.Hour(), crowd, out.Decibels, verdict, conversationMaxDecibels)
+	return out, nil
+}
+
+// baseDecibels 把 1 (安静) - 5 (嘈杂) 的 noise_level 换算成空座时的分贝, 每级 6 dB, 1 级约等于安静的办公室.
+func baseDecibels(level int) int {
+	return 44 + 6*level
+}
+
+// crowdDecibels 随繁忙程度线性增加, 满座时是 crowdMaxDecibels.
+func crowdDecibels(busy int) int {
+	return int(math.Round(float64(crowdMaxDecibels) * float64(busy) / 100))
+}
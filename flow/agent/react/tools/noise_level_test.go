@@ -0,0 +1,75 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tools
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNoiseLevel(t *testing.T) {
+	ctx := context.Background()
+	loc := time.FixedZone("CST", 8*3600)
+	at := func(hour int) *fakeService {
+		// 2024-06-01 是周六
+		return &fakeService{repo: restService.repo, clock: FixedClock{T: time.Date(2024, 6, 1, hour, 0, 0, 0, loc)}}
+	}
+
+	// 安静的餐厅在下午适合聊天, 晚高峰人多了就会变吵
+	quiet, err := at(15).NoiseLevel(ctx, &NoiseLevelParam{RestaurantID: "1003"})
+	assert.NoError(t, err)
+	assert.True(t, quiet.Open)
+	assert.Equal(t, baseDecibels(2)+crowdDecibels(busyness("1003", time.Saturday, 15)), quiet.Decibels)
+	assert.True(t, quiet.ConversationFriendly)
+	assert.Contains(t, quiet.Reasoning, "noise level 2 of 5")
+
+	peak, err := at(19).NoiseLevel(ctx, &NoiseLevelParam{RestaurantID: "1003"})
+	assert.NoError(t, err)
+	assert.Greater(t, peak.Decibels, quiet.Decibels)
+
+	// 嘈杂的餐厅空座时也不适合聊天
+	loud, err := at(15).NoiseLevel(ctx, &NoiseLevelParam{RestaurantID: "1002"})
+	assert.NoError(t, err)
+	assert.False(t, loud.ConversationFriendly)
+	assert.Contains(t, loud.Reasoning, "raise your voice")
+
+	// 没有氛围数据时按中等的 noise_level 计算
+	repo := &restaurantDatabase{restaurantByID: map[string]restaurantDataItem{"9001": {ID: "9001"}}}
+	svc := &fakeService{repo: repo, clock: FixedClock{T: time.Date(2024, 6, 1, 15, 0, 0, 0, loc)}}
+	unknown, err := svc.NoiseLevel(ctx, &NoiseLevelParam{RestaurantID: "9001"})
+	assert.NoError(t, err)
+	assert.Contains(t, unknown.Reasoning, "no ambiance data")
+	assert.Equal(t, baseDecibels(defaultNoiseLevel)+crowdDecibels(busyness("9001", time.Saturday, 15)), unknown.Decibels)
+
+	closed, err := at(8).NoiseLevel(ctx, &NoiseLevelParam{RestaurantID: "1003"})
+	assert.NoError(t, err)
+	assert.False(t, closed.Open)
+	assert.Zero(t, closed.Decibels)
+	assert.Contains(t, closed.Reasoning, "closed")
+
+	_, err = at(15).NoiseLevel(ctx, &NoiseLevelParam{RestaurantID: "404"})
+	assert.Error(t, err)
+}
+
+func TestCrowdDecibels(t *testing.T) {
+	assert.Equal(t, 0, crowdDecibels(0))
+	assert.Equal(t, 6, crowdDecibels(50))
+	assert.Equal(t, crowdMaxDecibels, crowdDecibels(100))
+}
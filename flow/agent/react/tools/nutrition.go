@@ -0,0 +1,171 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/cloudwego/eino/components/tool"
+	"github.com/cloudwego/eino/schema"
+)
+
+func GetNutritionTool() tool.InvokableTool {
+	return safeTool{
+		InvokableTool: NewCancellableTool(&ToolNutrition{
+			backService: restService,
+		}),
+	}
+}
+
+// ToolNutrition 返回菜品的营养成分 (热量、蛋白质、碳水、脂肪), 可以按 max_calories 筛选出低热量的菜.
+type ToolNutrition struct {
+	backService *fakeService // fake service
+}
+
+func (t *ToolNutrition) Info(ctx context.Context) (*schema.ToolInfo, error) {
+	return &schema.ToolInfo{
+		Name: "query_nutrition",
+		Desc: "Query the nutrition facts per serving (calories, protein, carbs, fat) of the dishes in a restaurant. " +
+			"Pass dish_name for a single dish, or max_calories to list only dishes within the calorie limit",
+		ParamsOneOf: schema.NewParamsOneOfByParams(map[string]*schema.ParameterInfo{
+			"restaurant_id": {
+				Type:     "string",
+				Desc:     "The id of one restaurant",
+				Required: true,
+			},
+			"dish_name": {
+				Type: "string",
+				Desc: "Optional name of one dish, leave empty for all dishes",
+			},
+			"max_calories": {
+				Type: "number",
+				Desc: "Optional calorie limit in kcal, only dishes with known nutrition within the limit are returned",
+			},
+		}),
+	}, nil
+}
+
+func (t *ToolNutrition) InvokableRun(ctx context.Context, argumentsInJSON string, opts ...tool.Option) (string, error) {
+	// 解析参数
+	if err := checkRequired(ctx, t, argumentsInJSON); err != nil {
+		return "", err
+	}
+	p := &NutritionParam{}
+	err := json.Unmarshal([]byte(argumentsInJSON), p)
+	if err != nil {
+		return "", err
+	}
+
+	// 请求后端服务
+	info, err := t.backService.QueryNutrition(ctx, p)
+	if err != nil {
+		return "", err
+	}
+
+	// 序列化结果
+	res, err := marshalResult(info)
+	if err != nil {
+		return "", err
+	}
+
+	return string(res), nil
+}
+
+type NutritionParam struct {
+	RestaurantID string `json:"restaurant_id"`
+	DishName     string `json:"dish_name"`
+	MaxCalories  int    `json:"max_calories"`
+}
+
+type NutritionInfo struct {
+	RestaurantID string          `json:"restaurant_id"`
+	MaxCalories  int             `json:"max_calories,omitempty"`
+	Dishes       []DishNutrition `json:"dishes"`
+	// UnknownNutrition 是按 max_calories 筛选时, 因为没有营养数据而无法判断的菜
+	UnknownNutrition []string `json:"unknown_nutrition,omitempty"`
+}
+
+// DishNutrition 没有营养数据时 nutrition_available 为 false 并带上说明, 而不是返回一堆 0.
+type DishNutrition struct {
+	Name               string     `json:"name"`
+	NutritionAvailable bool       `json:"nutrition_available"`
+	Nutrition          *Nutrition `json:"nutrition,omitempty"`
+	Message            string     `json:"message,omitempty"`
+}
+
+// QueryNutrition 查询一家餐厅 (或其中一道菜) 的营养成分.
+func (ft *fakeService) QueryNutrition(ctx context.Context, in *NutritionParam) (*NutritionInfo, error) {
+	if err := ft.simulateLatency(ctx); err != nil {
+		return nil, err
+	}
+
+	if in.MaxCalories < 0 {
+		return nil, fmt.Errorf("max_calories must be positive, got %d", in.MaxCalories)
+	}
+
+	rest, err := ft.repo.GetRestaurantByID(ctx, in.RestaurantID)
+	if err != nil {
+		return nil, err
+	}
+
+	dishes := rest.Dishes
+	if in.DishName != "" {
+		dish, ok := findDish(rest.Dishes, in.DishName)
+		if !ok {
+			return nil, fmt.Errorf("dish %s not found in restaurant %s", in.DishName, in.RestaurantID)
+		}
+		dishes = []restaurantDishDataItem{dish}
+	}
+
+	return filterNutrition(in.RestaurantID, dishes, in.MaxCalories), nil
+}
+
+// filterNutrition maxCalories 为 0 时不筛选, 返回所有菜; 否则只返回有营养数据并且热量不超过 maxCalories 的菜.
+func filterNutrition(restaurantID string, dishes []restaurantDishDataItem, maxCalories int) *NutritionInfo {
+	out := &NutritionInfo{RestaurantID: restaurantID, MaxCalories: maxCalories, Dishes: []DishNutrition{}}
+	for _, dish := range dishes {
+		if dish.Nutrition == nil {
+			if maxCalories > 0 {
+				out.UnknownNutrition = append(out.UnknownNutrition, dish.Name)
+				continue
+			}
+			out.Dishes = append(out.Dishes, DishNutrition{
+				Name:    dish.Name,
+				Message: "nutrition unavailable: this restaurant has not published nutrition facts for this dish",
+			})
+			continue
+		}
+		if maxCalories > 0 && dish.Nutrition.Calories > maxCalories {
+			continue
+		}
+		out.Dishes = append(out.Dishes, DishNutrition{
+			Name:               dish.Name,
+			NutritionAvailable: true,
+			Nutrition:          toNutrition(dish.Nutrition),
+		})
+	}
+	return out
+}
+
+func toNutrition(item *restaurantNutritionItem) *Nutrition {
+	if item == nil {
+		return nil
+	}
+	return &Nutrition{Calories: item.Calories, ProteinG: item.ProteinG, CarbsG: item.CarbsG, FatG: item.FatG}
+}
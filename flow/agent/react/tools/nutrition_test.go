@@ -0,0 +1,62 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tools
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQueryNutrition(t *testing.T) {
+	ctx := context.Background()
+
+	out, err := restService.QueryNutrition(ctx, &NutritionParam{RestaurantID: "1001", DishName: "红烧肉"})
+	assert.NoError(t, err)
+	assert.Len(t, out.Dishes, 1)
+	assert.True(t, out.Dishes[0].NutritionAvailable)
+	assert.Equal(t, 650, out.Dishes[0].Nutrition.Calories)
+
+	out, err = restService.QueryNutrition(ctx, &NutritionParam{RestaurantID: "1001", MaxCalories: 250})
+	assert.NoError(t, err)
+	var names []string
+	for _, d := range out.Dishes {
+		names = append(names, d.Name)
+		assert.LessOrEqual(t, d.Nutrition.Calories, 250)
+	}
+	assert.ElementsMatch(t, []string{"清炒小南瓜", "韩式辣白菜", "酸辣土豆丝"}, names)
+
+	// 没有营养数据的菜明确标记, 而不是返回 0
+	out, err = restService.QueryNutrition(ctx, &NutritionParam{RestaurantID: "1003"})
+	assert.NoError(t, err)
+	for _, d := range out.Dishes {
+		assert.False(t, d.NutritionAvailable)
+		assert.Nil(t, d.Nutrition)
+		assert.Contains(t, d.Message, "unavailable")
+	}
+
+	out, err = restService.QueryNutrition(ctx, &NutritionParam{RestaurantID: "1003", MaxCalories: 500})
+	assert.NoError(t, err)
+	assert.Empty(t, out.Dishes)
+	assert.Len(t, out.UnknownNutrition, 3)
+
+	_, err = restService.QueryNutrition(ctx, &NutritionParam{RestaurantID: "1001", MaxCalories: -1})
+	assert.Error(t, err)
+	_, err = restService.QueryNutrition(ctx, &NutritionParam{RestaurantID: "1001", DishName: "不存在的菜"})
+	assert.ErrorContains(t, err, "not found")
+}
@@ -0,0 +1,160 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tools
+
+import (
+	"context"
+	"fmt"
+	"math"
+)
+
+// PageInfo 是分页查询的结果中除了列表之外的部分, 模型带上 next_page 再查一次就能拿到下一页.
+type PageInfo struct {
+	Page     int `json:"page"` // 从 1 开始
+	PageSize int `json:"page_size"`
+	Total    int `json:"total"` // 筛选之后的总条数
+	// NextPage 为 0 表示已经是最后一页
+	NextPage int `json:"next_page,omitempty"`
+}
+
+type RestaurantPage struct {
+	Results []Restaurant `json:"results"`
+	PageInfo
+}
+
+type DishPage struct {
+	Results []Dish `json:"results"`
+	PageInfo
+}
+
+// pageDesc 和 pageSizeDesc 是 query_restaurants 和 query_dishes 共用的分页参数说明.
+const (
+	pageDesc     = "Page number starting from 1. Set page or page_size to page through all results in a stable order; the result contains the total count and next_page when more pages follow"
+	pageSizeDesc = "How many results per page, defaults to topn"
+)
+
+// paged 表示调用方指定了 page 或 page_size, 需要返回分页的结果, 否则仍然按 topn 返回列表.
+func paged(page, pageSize int) bool {
+	return page > 0 || pageSize > 0
+}
+
+// paginate 取 items 的第 page 页. page 为 0 时为第一页, pageSize 为 0 时为 defaultSize;
+// pageSize 不超过 SetMaxResults 的上限, 否则一页的结果会被 marshalResult 截断, 下一页又从截断之后开始, 中间的结果就丢了.
+// items 的顺序必须每次都相同, 不同的页之间才不会重叠.
+func paginate[T any](items []T, page, pageSize, defaultSize int) ([]T, PageInfo, error) {
+	if page < 0 || pageSize < 0 {
+		return nil, PageInfo{}, fmt.Errorf("page and page_size must be positive, got page %d, page_size %d", page, pageSize)
+	}
+	if page == 0 {
+		page = 1
+	}
+	if pageSize == 0 {
+		pageSize = defaultSize
+	}
+	if limit := int(maxResults.Load()); limit > 0 && pageSize > limit {
+		pageSize = limit
+	}
+
+	info := PageInfo{Page: page, PageSize: pageSize, Total: len(items)}
+	pages := int(math.Ceil(float64(len(items)) / float64(pageSize)))
+	if page > max(pages, 1) {
+		return nil, PageInfo{}, fmt.Errorf("page %d is out of range, there are %d pages of %d results", page, pages, pageSize)
+	}
+
+	start := (page - 1) * pageSize
+	end := min(start+pageSize, len(items))
+	if end < len(items) {
+		info.NextPage = page + 1
+	}
+	return items[start:end], info, nil
+}
+
+// QueryRestaurantsPage 是 QueryRestaurants 的分页版本. 为了让不同的页不重叠, 同分的餐厅不打乱, 总是按后端的顺序返回.
+func (ft *fakeService) QueryRestaurantsPage(ctx context.Context, in *QueryRestaurantsParam) (*RestaurantPage, error) {
+	if err := ft.simulateLatency(ctx); err != nil {
+		return nil, err
+	}
+
+	if in.MinHygieneGrade != "" && !validHygieneGrade(in.MinHygieneGrade) {
+		return nil, fmt.Errorf("min_hygiene_grade must be one of A, B, C, got %q", in.MinHygieneGrade)
+	}
+
+	rests, err := ft.repo.GetRestaurantsByLocation(ctx, in.Location, math.MaxInt)
+	if err != nil {
+		return nil, err
+	}
+	matched := make([]restaurantDataItem, 0, len(rests))
+	for _, rest := range rests {
+		if in.matches(rest) {
+			matched = append(matched, rest)
+		}
+	}
+
+	items, info, err := paginate(matched, in.Page, in.PageSize, in.Topn)
+	if err != nil {
+		return nil, err
+	}
+	out := &RestaurantPage{Results: make([]Restaurant, 0, len(items)), PageInfo: info}
+	for _, rest := range items {
+		out.Results = append(out.Results, toRestaurant(rest))
+	}
+	return out, nil
+}
+
+// QueryDishesPage 是 QueryDishes 的分页版本, 同样先按用户的饮食偏好 (和 in_stock_only) 筛选, total 是筛选之后的条数.
+func (ft *fakeService) QueryDishesPage(ctx context.Context, in *QueryDishesParam) (*DishPage, error) {
+	if err := ft.simulateLatency(ctx); err != nil {
+		return nil, err
+	}
+
+	dishes, err := ft.repo.GetDishesByRestaurant(ctx, in.RestaurantID, math.MaxInt)
+	if err != nil {
+		return nil, err
+	}
+	if !in.IgnorePreferences {
+		dishes = ft.preferencesFor(ctx).filter(dishes)
+	}
+	if in.InStockOnly {
+		inStock := ft.inStock(in.RestaurantID)
+		var available []restaurantDishDataItem
+		for _, dish := range dishes {
+			if inStock(dish) {
+				available = append(available, dish)
+			}
+		}
+		dishes = available
+	}
+	if in.minScore > 0 {
+		var scored []restaurantDishDataItem
+		for _, dish := range dishes {
+			if dish.Score >= in.minScore {
+				scored = append(scored, dish)
+			}
+		}
+		dishes = scored
+	}
+
+	items, info, err := paginate(dishes, in.Page, in.PageSize, in.Topn)
+	if err != nil {
+		return nil, err
+	}
+	out := &DishPage{Results: make([]Dish, 0, len(items)), PageInfo: info}
+	for _, dish := range items {
+		out.Results = append(out.Results, toDish(dish))
+	}
+	return out, nil
+}
@@ -0,0 +1,112 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPaginate(t *testing.T) {
+	items := []int{1, 2, 3, 4, 5, 6, 7}
+
+	// 按 next_page 一页页取下去, 每条结果正好出现一次
+	var all []int
+	page := 1
+	for page != 0 {
+		res, info, err := paginate(items, page, 3, 5)
+		assert.NoError(t, err)
+		assert.Equal(t, 7, info.Total)
+		all = append(all, res...)
+		page = info.NextPage
+	}
+	assert.Equal(t, items, all)
+
+	res, info, err := paginate(items, 0, 0, 5)
+	assert.NoError(t, err)
+	assert.Equal(t, []int{1, 2, 3, 4, 5}, res)
+	assert.Equal(t, PageInfo{Page: 1, PageSize: 5, Total: 7, NextPage: 2}, info)
+
+	// 空列表只有第一页
+	res, info, err = paginate([]int{}, 1, 3, 5)
+	assert.NoError(t, err)
+	assert.Empty(t, res)
+	assert.Zero(t, info.NextPage)
+
+	_, _, err = paginate(items, 4, 3, 5)
+	assert.ErrorContains(t, err, "out of range")
+	_, _, err = paginate(items, -1, 3, 5)
+	assert.Error(t, err)
+
+	// page_size 不超过 SetMaxResults 的上限, 一页的结果不会被截断
+	SetMaxResults(2)
+	defer SetMaxResults(DefaultMaxResults)
+	_, info, err = paginate(items, 1, 3, 5)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, info.PageSize)
+}
+
+// 同分的餐厅在分页查询中不打乱, 每次查询第一页都得到同样的结果.
+func TestQueryRestaurantsPageStable(t *testing.T) {
+	ctx := context.Background()
+	repo := &restaurantDatabase{
+		restaurantByID: map[string]restaurantDataItem{},
+		restaurantsByLocation: map[string][]restaurantDataItem{
+			"杭州": {{ID: "1", Score: 8}, {ID: "2", Score: 8}, {ID: "3", Score: 8}, {ID: "4", Score: 6}},
+		},
+	}
+
+	for seed := int64(0); seed < 5; seed++ {
+		svc := &fakeService{repo: repo, rand: rand.New(rand.NewSource(seed))}
+		first, err := svc.QueryRestaurantsPage(ctx, &QueryRestaurantsParam{Location: "杭州", Page: 1, PageSize: 2})
+		assert.NoError(t, err)
+		second, err := svc.QueryRestaurantsPage(ctx, &QueryRestaurantsParam{Location: "杭州", Page: first.NextPage, PageSize: 2})
+		assert.NoError(t, err)
+
+		assert.Equal(t, "1", first.Results[0].ID)
+		assert.Equal(t, "2", first.Results[1].ID)
+		assert.Equal(t, "3", second.Results[0].ID)
+		assert.Equal(t, "4", second.Results[1].ID)
+		assert.Equal(t, 4, second.Total)
+		assert.Zero(t, second.NextPage)
+	}
+}
+
+func TestQueryDishesPaged(t *testing.T) {
+	ctx := context.Background()
+	dishes := &ToolQueryDishes{backService: restService}
+
+	out, err := dishes.InvokableRun(ctx, `{"restaurant_id": "1001", "page": 2, "page_size": 4}`)
+	assert.NoError(t, err)
+	page := &DishPage{}
+	assert.NoError(t, json.Unmarshal([]byte(out), page))
+	assert.Equal(t, 2, page.Page)
+	assert.Equal(t, 6, page.Total)
+	assert.Len(t, page.Results, 2)
+	assert.Zero(t, page.NextPage)
+
+	// 不分页时仍然返回列表
+	out, err = dishes.InvokableRun(ctx, `{"restaurant_id": "1001", "topn": 2}`)
+	assert.NoError(t, err)
+	var list []Dish
+	assert.NoError(t, json.Unmarshal([]byte(out), &list))
+	assert.Len(t, list, 2)
+}
@@ -0,0 +1,181 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+
+	"github.com/cloudwego/eino/components/tool"
+	"github.com/cloudwego/eino/schema"
+)
+
+func GetParkingCostTool() tool.InvokableTool {
+	return safeTool{
+		InvokableTool: NewCancellableTool(&ToolParkingCost{
+			backService: restService,
+		}),
+	}
+}
+
+// ToolParkingCost 按餐厅的坐标找到最近的停车场 (见 parkingLots), 按它的收费标准估算停车费,
+// 开车去的用户可以把它和 estimate_trip_cost 的结果加在一起.
+type ToolParkingCost struct {
+	backService *fakeService // fake service
+}
+
+func (t *ToolParkingCost) Info(ctx context.Context) (*schema.ToolInfo, error) {
+	return &schema.ToolInfo{
+		Name: "estimate_parking_cost",
+		Desc: "Estimate the parking cost in CNY near a restaurant for a given duration. " +
+			"Returns the nearest parking lot, its distance to the restaurant and the cost",
+		ParamsOneOf: schema.NewParamsOneOfByParams(map[string]*schema.ParameterInfo{
+			"restaurant_id": {
+				Type:     "string",
+				Desc:     "The id of one restaurant",
+				Required: true,
+			},
+			"duration_hours": {
+				Type:     "number",
+				Desc:     "How long the car is parked, in hours, e.g. 1.5, at most 24",
+				Required: true,
+			},
+		}),
+	}, nil
+}
+
+func (t *ToolParkingCost) InvokableRun(ctx context.Context, argumentsInJSON string, opts ...tool.Option) (string, error) {
+	// 解析参数
+	if err := checkRequired(ctx, t, argumentsInJSON); err != nil {
+		return "", err
+	}
+	p := &ParkingCostParam{}
+	err := json.Unmarshal([]byte(argumentsInJSON), p)
+	if err != nil {
+		return "", err
+	}
+
+	// 请求后端服务
+	cost, err := t.backService.EstimateParkingCost(ctx, p)
+	if err != nil {
+		return "", err
+	}
+
+	// 序列化结果
+	res, err := marshalResult(cost)
+	if err != nil {
+		return "", err
+	}
+
+	return string(res), nil
+}
+
+type ParkingCostParam struct {
+	RestaurantID  string  `json:"restaurant_id"`
+	DurationHours float64 `json:"duration_hours"`
+}
+
+// ParkingCost 中的金额单位是元. 最近的停车场也比较远时 message 给出提醒.
+type ParkingCost struct {
+	RestaurantID  string  `json:"restaurant_id"`
+	Lot           string  `json:"lot"`
+	DistanceM     int     `json:"distance_m"` // 停车场到餐厅的直线距离
+	DurationHours float64 `json:"duration_hours"`
+	BilledHours   int     `json:"billed_hours"` // 不满一小时按一小时计
+	Cost          int     `json:"cost"`
+	Rate          string  `json:"rate"`
+	Message       string  `json:"message,omitempty"`
+}
+
+// parkingLot 是 fake 的停车场数据, 首小时和之后每小时分别计费, 一天最多收 DailyCap.
+type parkingLot struct {
+	Name      string
+	Lat, Lng  float64
+	FirstHour int
+	Hourly    int
+	DailyCap  int
+}
+
+var parkingLots = []parkingLot{
+	{Name: "南池子地下停车场", Lat: 39.9102, Lng: 116.4012, FirstHour: 15, Hourly: 10, DailyCap: 120},
+	{Name: "工体北路公共停车场", Lat: 39.9350, Lng: 116.4490, FirstHour: 10, Hourly: 8, DailyCap: 80},
+	{Name: "南京东路停车库", Lat: 31.2370, Lng: 121.4800, FirstHour: 20, Hourly: 15, DailyCap: 150},
+	{Name: "陆家嘴滨江停车场", Lat: 31.2405, Lng: 121.5030, FirstHour: 15, Hourly: 12, DailyCap: 120},
+	{Name: "江苏路公共停车场", Lat: 31.2180, Lng: 121.4340, FirstHour: 10, Hourly: 6, DailyCap: 60},
+}
+
+const (
+	// maxParkingHours 是支持估算的最长停车时间, 超过后要按天计费
+	maxParkingHours = 24
+	// parkingWalkWarnMeters 以上提醒用户停车场离餐厅比较远
+	parkingWalkWarnMeters = 1000
+)
+
+// EstimateParkingCost 找到离 in.RestaurantID 最近的停车场, 按 in.DurationHours 估算停车费.
+func (ft *fakeService) EstimateParkingCost(ctx context.Context, in *ParkingCostParam) (*ParkingCost, error) {
+	if err := ft.simulateLatency(ctx); err != nil {
+		return nil, err
+	}
+
+	if in.DurationHours <= 0 || in.DurationHours > maxParkingHours {
+		return nil, fmt.Errorf("duration_hours must be greater than 0 and at most %d, got %v", maxParkingHours, in.DurationHours)
+	}
+	rest, err := ft.repo.GetRestaurantByID(ctx, in.RestaurantID)
+	if err != nil {
+		return nil, err
+	}
+	if rest.Geo == nil {
+		return nil, fmt.Errorf("restaurant %s has no published location", rest.ID)
+	}
+
+	lot, km := nearestParkingLot(rest.Geo.Lat, rest.Geo.Lng)
+	hours := int(math.Ceil(in.DurationHours))
+	out := &ParkingCost{
+		RestaurantID:  rest.ID,
+		Lot:           lot.Name,
+		DistanceM:     int(math.Round(km * 1000)),
+		DurationHours: in.DurationHours,
+		BilledHours:   hours,
+		Cost:          parkingFee(lot, hours),
+		Rate:          fmt.Sprintf("%d yuan for the first hour, then %d yuan per hour, at most %d yuan a day", lot.FirstHour, lot.Hourly, lot.DailyCap),
+	}
+	if out.DistanceM > parkingWalkWarnMeters {
+		out.Message = fmt.Sprintf("the nearest parking lot is %d m away from the restaurant, consider a taxi", out.DistanceM)
+	}
+	return out, nil
+}
+
+// nearestParkingLot 返回离坐标最近的停车场和距离 (km).
+func nearestParkingLot(lat, lng float64) (parkingLot, float64) {
+	best, bestKm := parkingLots[0], math.Inf(1)
+	for _, lot := range parkingLots {
+		if km := haversineKm(lat, lng, lot.Lat, lot.Lng); km < bestKm {
+			best, bestKm = lot, km
+		}
+	}
+	return best, bestKm
+}
+
+// parkingFee 是停 hours 个小时 (不超过 maxParkingHours) 的费用.
+func parkingFee(lot parkingLot, hours int) int {
+	if hours <= 0 {
+		return 0
+	}
+	return min(lot.FirstHour+(hours-1)*lot.Hourly, lot.DailyCap)
+}
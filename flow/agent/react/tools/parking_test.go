@@ -0,0 +1,67 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tools
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEstimateParkingCost(t *testing.T) {
+	ctx := context.Background()
+	svc := &fakeService{repo: database}
+
+	cost, err := svc.EstimateParkingCost(ctx, &ParkingCostParam{RestaurantID: "1001", DurationHours: 1.5})
+	assert.NoError(t, err)
+	assert.Equal(t, "南池子地下停车场", cost.Lot)
+	assert.Equal(t, 2, cost.BilledHours)
+	assert.Equal(t, 25, cost.Cost)
+	assert.Less(t, cost.DistanceM, parkingWalkWarnMeters)
+	assert.Empty(t, cost.Message)
+
+	// 一天最多收 DailyCap
+	cost, err = svc.EstimateParkingCost(ctx, &ParkingCostParam{RestaurantID: "1001", DurationHours: 24})
+	assert.NoError(t, err)
+	assert.Equal(t, 120, cost.Cost)
+
+	// 最近的停车场也很远
+	far := &fakeService{repo: &restaurantDatabase{restaurantByID: map[string]restaurantDataItem{
+		"9001": {ID: "9001", Geo: &restaurantGeoItem{Lat: 40.0, Lng: 116.3}},
+		"9002": {ID: "9002"},
+	}}}
+	cost, err = far.EstimateParkingCost(ctx, &ParkingCostParam{RestaurantID: "9001", DurationHours: 1})
+	assert.NoError(t, err)
+	assert.Greater(t, cost.DistanceM, parkingWalkWarnMeters)
+	assert.Contains(t, cost.Message, "consider a taxi")
+	_, err = far.EstimateParkingCost(ctx, &ParkingCostParam{RestaurantID: "9002", DurationHours: 1})
+	assert.ErrorContains(t, err, "no published location")
+
+	for _, hours := range []float64{0, -1, 25} {
+		_, err = svc.EstimateParkingCost(ctx, &ParkingCostParam{RestaurantID: "1001", DurationHours: hours})
+		assert.ErrorContains(t, err, "duration_hours")
+	}
+}
+
+func TestParkingFee(t *testing.T) {
+	lot := parkingLot{FirstHour: 10, Hourly: 8, DailyCap: 50}
+	assert.Equal(t, 0, parkingFee(lot, 0))
+	assert.Equal(t, 10, parkingFee(lot, 1))
+	assert.Equal(t, 26, parkingFee(lot, 3))
+	assert.Equal(t, 50, parkingFee(lot, 10))
+}
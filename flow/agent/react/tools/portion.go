@@ -0,0 +1,188 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/cloudwego/eino/components/tool"
+	"github.com/cloudwego/eino/schema"
+)
+
+func GetPortionInfoTool() tool.InvokableTool {
+	return safeTool{
+		InvokableTool: NewCancellableTool(&ToolPortionInfo{
+			backService: restService,
+		}),
+	}
+}
+
+// ToolPortionInfo 回答 "这些菜够不够我们几个人吃": 给出每道菜的分量和是否适合分着吃,
+// 再按分量估算这些菜够几个人吃, 不够时建议再点几道.
+type ToolPortionInfo struct {
+	backService *fakeService // fake service
+}
+
+func (t *ToolPortionInfo) Info(ctx context.Context) (*schema.ToolInfo, error) {
+	return &schema.ToolInfo{
+		Name: "query_portion_info",
+		Desc: "Query the portion size (small, medium or large) of dishes of a restaurant and whether they are meant to be shared, " +
+			"and estimate whether they are enough for the party and how many more dishes to order",
+		ParamsOneOf: schema.NewParamsOneOfByParams(map[string]*schema.ParameterInfo{
+			"restaurant_id": {
+				Type:     "string",
+				Desc:     "The id of one restaurant",
+				Required: true,
+			},
+			"dish_names": {
+				Type:     "array",
+				Desc:     "The names of the dishes the party plans to order",
+				ElemInfo: &schema.ParameterInfo{Type: "string"},
+				Required: true,
+			},
+			"party_size": {
+				Type:     "integer",
+				Desc:     "How many people are eating",
+				Required: true,
+			},
+		}),
+	}, nil
+}
+
+func (t *ToolPortionInfo) InvokableRun(ctx context.Context, argumentsInJSON string, opts ...tool.Option) (string, error) {
+	// 解析参数
+	if err := checkRequired(ctx, t, argumentsInJSON); err != nil {
+		return "", err
+	}
+	p := &PortionInfoParam{}
+	err := json.Unmarshal([]byte(argumentsInJSON), p)
+	if err != nil {
+		return "", err
+	}
+
+	// 请求后端服务
+	info, err := t.backService.QueryPortionInfo(ctx, p)
+	if err != nil {
+		return "", err
+	}
+
+	// 序列化结果
+	res, err := marshalResult(info)
+	if err != nil {
+		return "", err
+	}
+
+	return string(res), nil
+}
+
+type PortionInfoParam struct {
+	RestaurantID string   `json:"restaurant_id"`
+	DishNames    []string `json:"dish_names"`
+	PartySize    int      `json:"party_size"`
+}
+
+// PortionInfo 中 total_serves 是这些菜大约够几个人吃, suggested_dish_count 是建议一共点几道菜 (包括已经选的).
+type PortionInfo struct {
+	RestaurantID       string        `json:"restaurant_id"`
+	PartySize          int           `json:"party_size"`
+	Dishes             []DishPortion `json:"dishes"`
+	TotalServes        int           `json:"total_serves"`
+	Enough             bool          `json:"enough"`
+	SuggestedDishCount int           `json:"suggested_dish_count"`
+	Message            string        `json:"message"`
+	// Skipped 是菜单上找不到的菜名
+	Skipped []string `json:"skipped,omitempty"`
+}
+
+type DishPortion struct {
+	Name      string `json:"name"`
+	Portion   string `json:"portion"`
+	Shareable bool   `json:"shareable"`
+	// Serves 是这道菜大约够几个人吃, 一人一份的菜总是 1
+	Serves int    `json:"serves"`
+	Note   string `json:"note,omitempty"`
+}
+
+// portionServes 是每种分量的共享菜大约够几个人吃, 没有分量数据的菜按 medium 估算.
+var portionServes = map[string]int{
+	"small":  1,
+	"medium": 2,
+	"large":  3,
+}
+
+// QueryPortionInfo 估算 in.DishNames 够几个人吃: 共享的菜按 portionServes, 一人一份的菜算 1 个人.
+// 不够 in.PartySize 时按 medium 的共享菜补足, 多出一倍并且至少多 3 人份时提示可能点多了.
+func (ft *fakeService) QueryPortionInfo(ctx context.Context, in *PortionInfoParam) (*PortionInfo, error) {
+	if err := ft.simulateLatency(ctx); err != nil {
+		return nil, err
+	}
+
+	if in.PartySize <= 0 || in.PartySize > maxPartySize {
+		return nil, fmt.Errorf("party_size must be between 1 and %d, got %d", maxPartySize, in.PartySize)
+	}
+	rest, err := ft.repo.GetRestaurantByID(ctx, in.RestaurantID)
+	if err != nil {
+		return nil, err
+	}
+
+	out := &PortionInfo{RestaurantID: rest.ID, PartySize: in.PartySize, Dishes: make([]DishPortion, 0, len(in.DishNames))}
+	for _, name := range in.DishNames {
+		dish, ok := findDish(rest.Dishes, name)
+		if !ok {
+			out.Skipped = append(out.Skipped, name)
+			continue
+		}
+		out.Dishes = append(out.Dishes, dishPortion(dish, in.PartySize))
+	}
+	for _, d := range out.Dishes {
+		out.TotalServes += d.Serves
+	}
+
+	short := in.PartySize - out.TotalServes
+	out.Enough = short <= 0
+	out.SuggestedDishCount = len(out.Dishes)
+	switch {
+	case short > 0:
+		more := (short + portionServes["medium"] - 1) / portionServes["medium"]
+		out.SuggestedDishCount += more
+		out.Message = fmt.Sprintf("the dishes serve about %d, order about %d more medium shared dish(es) for %d people", out.TotalServes, more, in.PartySize)
+	case out.TotalServes >= 2*in.PartySize && -short >= 3:
+		out.Message = fmt.Sprintf("the dishes serve about %d, probably too much food for %d people", out.TotalServes, in.PartySize)
+	default:
+		out.Message = fmt.Sprintf("the dishes serve about %d, enough for %d people", out.TotalServes, in.PartySize)
+	}
+	return out, nil
+}
+
+func dishPortion(dish restaurantDishDataItem, partySize int) DishPortion {
+	p := DishPortion{Name: dish.Name, Portion: dish.Portion, Shareable: dish.Shareable}
+	switch {
+	case !dish.Shareable && dish.Portion != "":
+		p.Serves = 1
+		if partySize > 1 {
+			p.Note = "one per person, order one for everyone who wants it"
+		}
+	case dish.Portion == "":
+		p.Serves = portionServes["medium"]
+		p.Note = "portion unknown, estimated as medium"
+	default:
+		p.Serves = portionServes[dish.Portion]
+	}
+	return p
+}
@@ -0,0 +1,66 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tools
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQueryPortionInfo(t *testing.T) {
+	ctx := context.Background()
+	svc := &fakeService{repo: database}
+
+	// 4 个人: 红烧肉 (medium) 2 + 酸辣粉 (一人一份) 1 = 3, 还差 1 人份, 再点一道 medium
+	info, err := svc.QueryPortionInfo(ctx, &PortionInfoParam{RestaurantID: "1001", DishNames: []string{"红烧肉", "酸辣粉", "不存在的菜"}, PartySize: 4})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"不存在的菜"}, info.Skipped)
+	assert.Equal(t, DishPortion{Name: "红烧肉", Portion: "medium", Shareable: true, Serves: 2}, info.Dishes[0])
+	assert.Equal(t, 1, info.Dishes[1].Serves)
+	assert.False(t, info.Dishes[1].Shareable)
+	assert.Contains(t, info.Dishes[1].Note, "one per person")
+	assert.Equal(t, 3, info.TotalServes)
+	assert.False(t, info.Enough)
+	assert.Equal(t, 3, info.SuggestedDishCount)
+	assert.Contains(t, info.Message, "order about 1 more")
+
+	info, err = svc.QueryPortionInfo(ctx, &PortionInfoParam{RestaurantID: "1001", DishNames: []string{"清泉牛肉", "红烧肉"}, PartySize: 4})
+	assert.NoError(t, err)
+	assert.True(t, info.Enough)
+	assert.Equal(t, 2, info.SuggestedDishCount)
+	assert.Contains(t, info.Message, "enough for 4 people")
+
+	// 两道 large 给一个人吃太多了
+	info, err = svc.QueryPortionInfo(ctx, &PortionInfoParam{RestaurantID: "1003", DishNames: []string{"超级红烧肉", "超级北京烤肉"}, PartySize: 1})
+	assert.NoError(t, err)
+	assert.Equal(t, 6, info.TotalServes)
+	assert.Contains(t, info.Message, "too much")
+
+	// 没有分量数据的菜按 medium 估算
+	repo := &restaurantDatabase{restaurantByID: map[string]restaurantDataItem{
+		"9000": {ID: "9000", Dishes: []restaurantDishDataItem{{Name: "神秘菜"}}},
+	}}
+	info, err = (&fakeService{repo: repo}).QueryPortionInfo(ctx, &PortionInfoParam{RestaurantID: "9000", DishNames: []string{"神秘菜"}, PartySize: 2})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, info.Dishes[0].Serves)
+	assert.Contains(t, info.Dishes[0].Note, "portion unknown")
+
+	_, err = svc.QueryPortionInfo(ctx, &PortionInfoParam{RestaurantID: "1001", DishNames: []string{"红烧肉"}, PartySize: 0})
+	assert.ErrorContains(t, err, "party_size")
+}
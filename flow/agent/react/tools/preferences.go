@@ -0,0 +1,295 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/cloudwego/eino/components/tool"
+	"github.com/cloudwego/eino/schema"
+)
+
+func GetSetPreferenceTool() tool.InvokableTool {
+	return safeTool{
+		InvokableTool: NewCancellableTool(&ToolSetPreference{
+			backService: restService,
+		}),
+	}
+}
+
+func GetPreferencesTool() tool.InvokableTool {
+	return safeTool{
+		InvokableTool: NewCancellableTool(&ToolGetPreferences{
+			backService: restService,
+		}),
+	}
+}
+
+// ToolSetPreference 和 ToolGetPreferences 读写当前用户的饮食偏好. 偏好保存之后, query_dishes 和 recommend_dishes
+// 会自动按偏好筛选菜品, 模型不需要每次都记得带上条件.
+// 登录用户的偏好保存在 fake service 中, 匿名用户的偏好只保存在 WithGuestSession 创建的会话里, 会话结束就丢弃.
+type ToolSetPreference struct {
+	backService *fakeService // fake service
+}
+
+func (t *ToolSetPreference) Info(ctx context.Context) (*schema.ToolInfo, error) {
+	return &schema.ToolInfo{
+		Name: "set_preference",
+		Desc: "Remember a dietary preference of the current user. Dish queries and recommendations respect the stored preferences automatically. " +
+			"Only the given fields are changed",
+		ParamsOneOf: schema.NewParamsOneOfByParams(map[string]*schema.ParameterInfo{
+			"vegetarian": {
+				Type: "boolean",
+				Desc: "Only show dishes without meat and seafood",
+			},
+			"max_spice": {
+				Type: "number",
+				Desc: "The highest spice level the user accepts, from 0 (not spicy) to 5 (extremely spicy), -1 to remove the limit",
+			},
+		}),
+	}, nil
+}
+
+func (t *ToolSetPreference) InvokableRun(ctx context.Context, argumentsInJSON string, opts ...tool.Option) (string, error) {
+	// 解析参数
+	if err := checkRequired(ctx, t, argumentsInJSON); err != nil {
+		return "", err
+	}
+	p := &SetPreferenceParam{}
+	err := json.Unmarshal([]byte(argumentsInJSON), p)
+	if err != nil {
+		return "", err
+	}
+
+	// 请求后端服务
+	ack, err := t.backService.SetPreference(ctx, p)
+	if err != nil {
+		return "", err
+	}
+
+	// 序列化结果
+	res, err := marshalResult(ack)
+	if err != nil {
+		return "", err
+	}
+
+	return string(res), nil
+}
+
+type ToolGetPreferences struct {
+	backService *fakeService // fake service
+}
+
+func (t *ToolGetPreferences) Info(ctx context.Context) (*schema.ToolInfo, error) {
+	return &schema.ToolInfo{
+		Name:        "get_preferences",
+		Desc:        "Get the dietary preferences stored for the current user",
+		ParamsOneOf: schema.NewParamsOneOfByParams(map[string]*schema.ParameterInfo{}),
+	}, nil
+}
+
+func (t *ToolGetPreferences) InvokableRun(ctx context.Context, argumentsInJSON string, opts ...tool.Option) (string, error) {
+	// 请求后端服务
+	prefs, err := t.backService.GetPreferences(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	// 序列化结果
+	res, err := marshalResult(prefs)
+	if err != nil {
+		return "", err
+	}
+
+	return string(res), nil
+}
+
+// SetPreferenceParam 的字段都是指针, 为空表示这次不修改.
+type SetPreferenceParam struct {
+	Vegetarian *bool `json:"vegetarian"`
+	MaxSpice   *int  `json:"max_spice"`
+}
+
+// DietaryPreferences 是一个用户的饮食偏好.
+type DietaryPreferences struct {
+	Vegetarian bool `json:"vegetarian"`
+	// MaxSpice 为空表示不限制辣度
+	MaxSpice *int `json:"max_spice,omitempty"`
+}
+
+type UserPreferences struct {
+	Preferences DietaryPreferences `json:"preferences"`
+	// Ephemeral 为 true 表示当前是匿名用户, 偏好只在这次会话中有效
+	Ephemeral bool   `json:"ephemeral"`
+	Message   string `json:"message,omitempty"`
+}
+
+const maxSpiceLevel = 5
+
+var errNoPreferenceSession = errors.New(`{"error":"preferences unavailable","message":"the user is not signed in and has no session to keep preferences in, ask the user to sign in","retry":"false"}`)
+
+// guestPreferences 是匿名用户的一次会话, 由 WithGuestSession 放进 context.
+type guestPreferences struct {
+	prefs DietaryPreferences
+}
+
+type guestPreferencesKey struct{}
+
+// WithGuestSession 为匿名用户创建一个会话, 会话中设置的饮食偏好只保存在返回的 context 里,
+// 不会写进后端, 也不会被其他匿名用户看到. 登录用户 (见 WithUserID) 不使用会话.
+func WithGuestSession(ctx context.Context) context.Context {
+	return context.WithValue(ctx, guestPreferencesKey{}, &guestPreferences{})
+}
+
+func guestSessionFrom(ctx context.Context) *guestPreferences {
+	guest, _ := ctx.Value(guestPreferencesKey{}).(*guestPreferences)
+	return guest
+}
+
+// SetPreference 修改 context 中用户的饮食偏好, 返回修改后的偏好.
+func (ft *fakeService) SetPreference(ctx context.Context, in *SetPreferenceParam) (*UserPreferences, error) {
+	if err := ft.simulateLatency(ctx); err != nil {
+		return nil, err
+	}
+
+	if in.Vegetarian == nil && in.MaxSpice == nil {
+		return nil, errors.New("at least one of vegetarian and max_spice is required")
+	}
+	if in.MaxSpice != nil && (*in.MaxSpice < -1 || *in.MaxSpice > maxSpiceLevel) {
+		return nil, fmt.Errorf("max_spice must be between 0 and %d, or -1 to remove the limit, got %d", maxSpiceLevel, *in.MaxSpice)
+	}
+
+	apply := func(prefs *DietaryPreferences) {
+		if in.Vegetarian != nil {
+			prefs.Vegetarian = *in.Vegetarian
+		}
+		if in.MaxSpice != nil {
+			if *in.MaxSpice < 0 {
+				prefs.MaxSpice = nil
+			} else {
+				maxSpice := *in.MaxSpice
+				prefs.MaxSpice = &maxSpice
+			}
+		}
+	}
+
+	ft.mu.Lock()
+	defer ft.mu.Unlock()
+
+	userID := UserIDFrom(ctx)
+	if userID == "" {
+		guest := guestSessionFrom(ctx)
+		if guest == nil {
+			return nil, errNoPreferenceSession
+		}
+		apply(&guest.prefs)
+		return &UserPreferences{
+			Preferences: guest.prefs,
+			Ephemeral:   true,
+			Message:     "saved for this session only, sign in to keep the preferences",
+		}, nil
+	}
+
+	if ft.prefs == nil {
+		ft.prefs = make(map[string]DietaryPreferences)
+	}
+	prefs := ft.prefs[userID]
+	apply(&prefs)
+	ft.prefs[userID] = prefs
+	return &UserPreferences{Preferences: prefs, Message: "saved"}, nil
+}
+
+// GetPreferences 返回 context 中用户的饮食偏好, 没有设置过时返回零值.
+func (ft *fakeService) GetPreferences(ctx context.Context) (*UserPreferences, error) {
+	if err := ft.simulateLatency(ctx); err != nil {
+		return nil, err
+	}
+
+	out := &UserPreferences{Preferences: ft.preferencesFor(ctx), Ephemeral: UserIDFrom(ctx) == ""}
+	if out.Ephemeral && guestSessionFrom(ctx) == nil {
+		out.Message = "the user is not signed in, no preferences are stored"
+	}
+	return out, nil
+}
+
+// preferencesFor 返回 context 中用户的饮食偏好, 查询类的方法用它筛选菜品.
+func (ft *fakeService) preferencesFor(ctx context.Context) DietaryPreferences {
+	ft.mu.Lock()
+	defer ft.mu.Unlock()
+
+	userID := UserIDFrom(ctx)
+	if userID != "" {
+		return ft.prefs[userID]
+	}
+	if guest := guestSessionFrom(ctx); guest != nil {
+		return guest.prefs
+	}
+	return DietaryPreferences{}
+}
+
+// active 表示是否设置了会影响筛选的偏好.
+func (p DietaryPreferences) active() bool {
+	return p.Vegetarian || p.MaxSpice != nil
+}
+
+func (p DietaryPreferences) allows(dish restaurantDishDataItem) bool {
+	if p.Vegetarian && !dish.Vegetarian {
+		return false
+	}
+	if p.MaxSpice != nil && dish.SpiceLevel > *p.MaxSpice {
+		return false
+	}
+	return true
+}
+
+func (p DietaryPreferences) filter(dishes []restaurantDishDataItem) []restaurantDishDataItem {
+	if !p.active() {
+		return dishes
+	}
+	res := make([]restaurantDishDataItem, 0, len(dishes))
+	for _, dish := range dishes {
+		if p.allows(dish) {
+			res = append(res, dish)
+		}
+	}
+	return res
+}
+
+// String 用于告诉模型哪些偏好过滤掉了结果.
+func (p DietaryPreferences) String() string {
+	var parts []string
+	if p.Vegetarian {
+		parts = append(parts, "vegetarian")
+	}
+	if p.MaxSpice != nil {
+		parts = append(parts, fmt.Sprintf("max spice %d", *p.MaxSpice))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// emptyResultFilteredBy 是按偏好筛选后没有剩下任何菜时返回的内容, 说明原因, 模型可以决定是否带上 ignore_preferences 再查一次.
+func emptyResultFilteredBy(prefs DietaryPreferences) string {
+	res, _ := marshalResult(map[string]any{
+		"results": []any{},
+		"message": fmt.Sprintf("no dishes match the user's dietary preferences (%s), set ignore_preferences to see all dishes", prefs),
+	})
+	return string(res)
+}
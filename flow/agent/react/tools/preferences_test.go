@@ -0,0 +1,129 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tools
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPreferences(t *testing.T) {
+	svc := &fakeService{repo: database}
+	alice := WithUserID(context.Background(), "u1001")
+	bob := WithUserID(context.Background(), "u2002")
+
+	vegetarian, maxSpice := true, 2
+	ack, err := svc.SetPreference(alice, &SetPreferenceParam{Vegetarian: &vegetarian})
+	assert.NoError(t, err)
+	assert.False(t, ack.Ephemeral)
+	assert.True(t, ack.Preferences.Vegetarian)
+	assert.Nil(t, ack.Preferences.MaxSpice)
+
+	// 只修改给出的字段
+	ack, err = svc.SetPreference(alice, &SetPreferenceParam{MaxSpice: &maxSpice})
+	assert.NoError(t, err)
+	assert.True(t, ack.Preferences.Vegetarian)
+	assert.Equal(t, 2, *ack.Preferences.MaxSpice)
+
+	prefs, err := svc.GetPreferences(alice)
+	assert.NoError(t, err)
+	assert.Equal(t, ack.Preferences, prefs.Preferences)
+
+	// 不同用户的偏好互不影响
+	prefs, err = svc.GetPreferences(bob)
+	assert.NoError(t, err)
+	assert.False(t, prefs.Preferences.Vegetarian)
+
+	invalid := 6
+	_, err = svc.SetPreference(alice, &SetPreferenceParam{MaxSpice: &invalid})
+	assert.ErrorContains(t, err, "max_spice")
+	_, err = svc.SetPreference(alice, &SetPreferenceParam{})
+	assert.Error(t, err)
+
+	noLimit := -1
+	ack, err = svc.SetPreference(alice, &SetPreferenceParam{MaxSpice: &noLimit})
+	assert.NoError(t, err)
+	assert.Nil(t, ack.Preferences.MaxSpice)
+}
+
+func TestGuestPreferences(t *testing.T) {
+	svc := &fakeService{repo: database}
+	vegetarian := true
+
+	_, err := svc.SetPreference(context.Background(), &SetPreferenceParam{Vegetarian: &vegetarian})
+	assert.ErrorIs(t, err, errNoPreferenceSession)
+
+	guest := WithGuestSession(context.Background())
+	ack, err := svc.SetPreference(guest, &SetPreferenceParam{Vegetarian: &vegetarian})
+	assert.NoError(t, err)
+	assert.True(t, ack.Ephemeral)
+
+	prefs, err := svc.GetPreferences(guest)
+	assert.NoError(t, err)
+	assert.True(t, prefs.Preferences.Vegetarian)
+
+	// 新的会话没有之前的偏好, 后端也没有保存
+	prefs, err = svc.GetPreferences(WithGuestSession(context.Background()))
+	assert.NoError(t, err)
+	assert.False(t, prefs.Preferences.Vegetarian)
+	assert.Empty(t, svc.prefs)
+}
+
+func TestQueryDishesRespectsPreferences(t *testing.T) {
+	svc := &fakeService{repo: database}
+	ctx := WithUserID(context.Background(), "u1001")
+	vegetarian, maxSpice := true, 2
+	_, err := svc.SetPreference(ctx, &SetPreferenceParam{Vegetarian: &vegetarian, MaxSpice: &maxSpice})
+	assert.NoError(t, err)
+
+	dishes, err := svc.QueryDishes(ctx, &QueryDishesParam{RestaurantID: "1001", Topn: 5})
+	assert.NoError(t, err)
+	var names []string
+	for _, dish := range dishes {
+		assert.True(t, dish.Vegetarian)
+		assert.LessOrEqual(t, dish.SpiceLevel, 2)
+		names = append(names, dish.Name)
+	}
+	assert.Equal(t, []string{"韩式辣白菜", "酸辣土豆丝", "清炒小南瓜"}, names)
+
+	all, err := svc.QueryDishes(ctx, &QueryDishesParam{RestaurantID: "1001", Topn: 5, IgnorePreferences: true})
+	assert.NoError(t, err)
+	assert.Len(t, all, 5)
+
+	// 价格档位会被缓存, 不随用户的偏好变化
+	tier, err := svc.PriceTier(ctx, &PriceTierParam{RestaurantID: "1001"})
+	assert.NoError(t, err)
+	anonymous, err := svc.PriceTier(context.Background(), &PriceTierParam{RestaurantID: "1001"})
+	assert.NoError(t, err)
+	assert.Equal(t, anonymous, tier)
+	assert.Greater(t, tier.DishCount, len(dishes))
+
+	rec, err := svc.RecommendDishes(ctx, &RecommendDishesParam{RestaurantID: "1002"})
+	assert.NoError(t, err)
+	for _, r := range rec.Recommendations {
+		assert.NotEqual(t, "火辣辣的吻", r.Name)
+	}
+
+	// 全部被偏好过滤掉时说明原因
+	tl := &ToolQueryDishes{backService: svc}
+	out, err := tl.InvokableRun(ctx, `{"restaurant_id": "2010"}`)
+	assert.NoError(t, err)
+	assert.Contains(t, out, "vegetarian, max spice 2")
+	assert.Contains(t, out, "ignore_preferences")
+}
@@ -0,0 +1,131 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+
+	"github.com/cloudwego/eino/components/tool"
+	"github.com/cloudwego/eino/schema"
+)
+
+func GetPriceTierTool() tool.InvokableTool {
+	return safeTool{
+		InvokableTool: NewCancellableTool(&ToolPriceTier{
+			backService: restService,
+		}),
+	}
+}
+
+// ToolPriceTier 从菜品的平均价格推导出 $ - $$$$ 的价格档位, 方便模型回答 "贵不贵".
+type ToolPriceTier struct {
+	backService *fakeService // fake service
+}
+
+func (t *ToolPriceTier) Info(ctx context.Context) (*schema.ToolInfo, error) {
+	return &schema.ToolInfo{
+		Name: "query_price_tier",
+		Desc: "Query the price tier of a restaurant, from $ (cheap) to $$$$ (expensive), with the average dish price it is derived from",
+		ParamsOneOf: schema.NewParamsOneOfByParams(map[string]*schema.ParameterInfo{
+			"restaurant_id": {
+				Type:     "string",
+				Desc:     "The id of one restaurant",
+				Required: true,
+			},
+		}),
+	}, nil
+}
+
+func (t *ToolPriceTier) InvokableRun(ctx context.Context, argumentsInJSON string, opts ...tool.Option) (string, error) {
+	// 解析参数
+	if err := checkRequired(ctx, t, argumentsInJSON); err != nil {
+		return "", err
+	}
+	p := &PriceTierParam{}
+	err := json.Unmarshal([]byte(argumentsInJSON), p)
+	if err != nil {
+		return "", err
+	}
+
+	// 请求后端服务
+	tier, err := t.backService.PriceTier(ctx, p)
+	if err != nil {
+		return "", err
+	}
+
+	// 序列化结果
+	res, err := marshalResult(tier)
+	if err != nil {
+		return "", err
+	}
+
+	return string(res), nil
+}
+
+type PriceTierParam struct {
+	RestaurantID string `json:"restaurant_id"`
+}
+
+// PriceTier 没有菜品时 tier 为 unknown, 不返回平均价格.
+type PriceTier struct {
+	RestaurantID string   `json:"restaurant_id"`
+	Tier         string   `json:"tier"`
+	AveragePrice *float64 `json:"average_price,omitempty"`
+	DishCount    int      `json:"dish_count"`
+}
+
+// PriceTier 基于 QueryDishes 返回的全部菜品计算价格档位. 不按用户的饮食偏好筛选, 同一家餐厅对所有用户的档位相同.
+func (ft *fakeService) PriceTier(ctx context.Context, in *PriceTierParam) (*PriceTier, error) {
+	dishes, err := ft.QueryDishes(ctx, &QueryDishesParam{RestaurantID: in.RestaurantID, Topn: math.MaxInt32, IgnorePreferences: true})
+	if err != nil {
+		return nil, err
+	}
+
+	tier, avg := priceTier(dishes)
+	out := &PriceTier{RestaurantID: in.RestaurantID, Tier: tier, DishCount: len(dishes)}
+	if len(dishes) > 0 {
+		out.AveragePrice = &avg
+	}
+	return out, nil
+}
+
+// priceTier 按人均价格 (元) 划分: 30 以下 $, 30 - 60 $$, 60 - 100 $$$, 100 及以上 $$$$.
+// 平均价格保留一位小数, 没有菜品时返回 unknown.
+func priceTier(dishes []Dish) (string, float64) {
+	if len(dishes) == 0 {
+		return "unknown", 0
+	}
+
+	var total int
+	for _, d := range dishes {
+		total += d.Price
+	}
+	avg := math.Round(float64(total)/float64(len(dishes))*10) / 10
+
+	switch {
+	case avg < 30:
+		return "$", avg
+	case avg < 60:
+		return "$$", avg
+	case avg < 100:
+		return "$$$", avg
+	default:
+		return "$$$$", avg
+	}
+}
@@ -0,0 +1,62 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tools
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPriceTier(t *testing.T) {
+	cases := []struct {
+		prices []int
+		tier   string
+		avg    float64
+	}{
+		{prices: nil, tier: "unknown"},
+		{prices: []int{10, 20}, tier: "$", avg: 15},
+		{prices: []int{30}, tier: "$$", avg: 30},
+		{prices: []int{50, 80, 99}, tier: "$$$", avg: 76.3},
+		{prices: []int{198, 199}, tier: "$$$$", avg: 198.5},
+	}
+	for _, c := range cases {
+		var dishes []Dish
+		for _, p := range c.prices {
+			dishes = append(dishes, Dish{Price: p})
+		}
+		tier, avg := priceTier(dishes)
+		assert.Equal(t, c.tier, tier, c.prices)
+		assert.Equal(t, c.avg, avg, c.prices)
+	}
+
+	ctx := context.Background()
+	out, err := restService.PriceTier(ctx, &PriceTierParam{RestaurantID: "1001"})
+	assert.NoError(t, err)
+	assert.NotNil(t, out.AveragePrice)
+	assert.Equal(t, 6, out.DishCount)
+
+	emptyDB := &restaurantDatabase{restaurantByID: map[string]restaurantDataItem{"9001": {ID: "9001"}}}
+	out, err = (&fakeService{repo: emptyDB}).PriceTier(ctx, &PriceTierParam{RestaurantID: "9001"})
+	assert.NoError(t, err)
+	assert.Equal(t, "unknown", out.Tier)
+	assert.Nil(t, out.AveragePrice)
+
+	_, err = restService.PriceTier(ctx, &PriceTierParam{RestaurantID: "404"})
+	assert.Error(t, err)
+}
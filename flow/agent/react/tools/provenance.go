@@ -0,0 +1,65 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/cloudwego/eino/components/tool"
+)
+
+// provenanceTool 在 tool 结果前加一行来源标注, 比如 "[Source: query_restaurants]",
+// 方便模型在回答里区分哪些信息来自 tool, 减少编造.
+// 标注单独占一行, 放在 JSON 之外, 用 StripProvenance 去掉后剩下的仍是原始 JSON.
+type provenanceTool struct {
+	tool.InvokableTool
+}
+
+// NewProvenanceTool wraps t so that every result is prefixed with the name of the tool that produced it.
+func NewProvenanceTool(t tool.InvokableTool) tool.InvokableTool {
+	return &provenanceTool{InvokableTool: t}
+}
+
+func (p *provenanceTool) InvokableRun(ctx context.Context, argumentsInJSON string, opts ...tool.Option) (string, error) {
+	info, err := p.InvokableTool.Info(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	out, err := p.InvokableTool.InvokableRun(ctx, argumentsInJSON, opts...)
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("[Source: %s]\n%s", info.Name, out), nil
+}
+
+// StripProvenance splits a result produced by NewProvenanceTool into the tool name and the original content.
+// Content without provenance is returned unchanged with an empty source.
+func StripProvenance(content string) (source, body string) {
+	header, rest, ok := strings.Cut(content, "\n")
+	if !ok {
+		return "", content
+	}
+	name, ok := strings.CutPrefix(header, "[Source: ")
+	if !ok || !strings.HasSuffix(name, "]") {
+		return "", content
+	}
+	return strings.TrimSuffix(name, "]"), rest
+}
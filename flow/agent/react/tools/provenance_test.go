@@ -0,0 +1,44 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProvenanceTool(t *testing.T) {
+	ctx := context.Background()
+	annotated := NewProvenanceTool(&ToolQueryDishes{backService: restService})
+
+	out, err := annotated.InvokableRun(ctx, `{"restaurant_id": "1001", "topn": 2}`)
+	assert.NoError(t, err)
+	assert.Contains(t, out, "[Source: query_dishes]\n")
+
+	source, body := StripProvenance(out)
+	assert.Equal(t, "query_dishes", source)
+	var dishes []Dish
+	assert.NoError(t, json.Unmarshal([]byte(body), &dishes))
+	assert.Len(t, dishes, 2)
+
+	source, body = StripProvenance(`{"error":"x"}`)
+	assert.Empty(t, source)
+	assert.Equal(t, `{"error":"x"}`, body)
+}
@@ -0,0 +1,271 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/cloudwego/eino/components/tool"
+	"github.com/cloudwego/eino/schema"
+)
+
+func GetRecommendDishesTool() tool.InvokableTool {
+	return safeTool{
+		InvokableTool: NewCancellableTool(&ToolRecommendDishes{
+			backService: restService,
+		}),
+	}
+}
+
+// ToolRecommendDishes 根据当前用户 (见 WithUserID) 的历史订单推荐一家餐厅的菜:
+// 和以前喜欢的菜口味、食材越接近越靠前. 没有历史订单的新用户退回到按评分推荐. 不符合用户饮食偏好 (见 set_preference) 的菜不会被推荐.
+type ToolRecommendDishes struct {
+	backService *fakeService // fake service
+}
+
+func (t *ToolRecommendDishes) Info(ctx context.Context) (*schema.ToolInfo, error) {
+	return &schema.ToolInfo{
+		Name: "recommend_dishes",
+		Desc: "Recommend dishes of a restaurant personalized by the current user's previous orders, " +
+			"falling back to the top rated dishes for new users. Each recommendation comes with its reasoning",
+		ParamsOneOf: schema.NewParamsOneOfByParams(map[string]*schema.ParameterInfo{
+			"restaurant_id": {
+				Type:     "string",
+				Desc:     "The id of one restaurant",
+				Required: true,
+			},
+			"topn": {
+				Type: "number",
+				Desc: "top n recommended dishes, default 3",
+			},
+		}),
+	}, nil
+}
+
+func (t *ToolRecommendDishes) InvokableRun(ctx context.Context, argumentsInJSON string, opts ...tool.Option) (string, error) {
+	// 解析参数
+	if err := checkRequired(ctx, t, argumentsInJSON); err != nil {
+		return "", err
+	}
+	p := &RecommendDishesParam{}
+	err := json.Unmarshal([]byte(argumentsInJSON), p)
+	if err != nil {
+		return "", err
+	}
+
+	// 请求后端服务
+	rec, err := t.backService.RecommendDishes(ctx, p)
+	if err != nil {
+		return "", err
+	}
+
+	// 序列化结果
+	res, err := marshalResult(rec)
+	if err != nil {
+		return "", err
+	}
+
+	return string(res), nil
+}
+
+type RecommendDishesParam struct {
+	RestaurantID string `json:"restaurant_id"`
+	Topn         int    `json:"topn"`
+}
+
+type DishRecommendations struct {
+	RestaurantID    string               `json:"restaurant_id"`
+	Personalized    bool                 `json:"personalized"`
+	Recommendations []DishRecommendation `json:"recommendations"`
+}
+
+type DishRecommendation struct {
+	Name          string  `json:"name"`
+	Price         int     `json:"price"`
+	Score         int     `json:"score"`
+	Similarity    float64 `json:"similarity,omitempty"` // 0 - 1, 和历史订单的相似度
+	OrderedBefore bool    `json:"ordered_before,omitempty"`
+	Reasoning     string  `json:"reasoning"`
+}
+
+// pastOrder 是一条历史订单中的菜.
+type pastOrder struct {
+	RestaurantID string
+	DishName     string
+}
+
+// defaultOrderHistory 是 fake service 预置的用户历史订单, user id => 点过的菜.
+func defaultOrderHistory() map[string][]pastOrder {
+	return map[string][]pastOrder{
+		"u1001": { // 喜欢吃辣
+			{RestaurantID: "1001", DishName: "酸辣土豆丝"},
+			{RestaurantID: "1002", DishName: "火辣辣的吻"},
+			{RestaurantID: "2010", DishName: "超级大火锅🍲"},
+		},
+		"u2002": { // 喜欢酸甜口
+			{RestaurantID: "2001", DishName: "糖醋西红柿"},
+			{RestaurantID: "2002", DishName: "糖醋西瓜瓤"},
+		},
+	}
+}
+
+// RecommendDishes 为 context 中的用户推荐 in.RestaurantID 的菜.
+func (ft *fakeService) RecommendDishes(ctx context.Context, in *RecommendDishesParam) (*DishRecommendations, error) {
+	if err := ft.simulateLatency(ctx); err != nil {
+		return nil, err
+	}
+
+	rest, err := ft.repo.GetRestaurantByID(ctx, in.RestaurantID)
+	if err != nil {
+		return nil, err
+	}
+
+	topn := in.Topn
+	if topn <= 0 {
+		topn = 3
+	}
+
+	ft.mu.Lock()
+	history := ft.orders[UserIDFrom(ctx)]
+	ft.mu.Unlock()
+
+	var favorites []restaurantDishDataItem
+	for _, order := range history {
+		if r, err := ft.repo.GetRestaurantByID(ctx, order.RestaurantID); err == nil {
+			if dish, ok := findDish(r.Dishes, order.DishName); ok {
+				favorites = append(favorites, dish)
+			}
+		}
+	}
+
+	// 不符合饮食偏好的菜不推荐, 但历史订单仍然用来判断口味
+	dishes := ft.preferencesFor(ctx).filter(rest.Dishes)
+
+	out := &DishRecommendations{RestaurantID: in.RestaurantID, Personalized: len(favorites) > 0}
+	if out.Personalized {
+		out.Recommendations = recommendByHistory(dishes, favorites, history, in.RestaurantID, topn)
+	} else {
+		out.Recommendations = recommendByScore(dishes, topn)
+	}
+	return out, nil
+}
+
+// recommendByHistory 按和历史订单的相似度排序, 相同时按评分排序. 完全不相似的菜不推荐.
+func recommendByHistory(dishes, favorites []restaurantDishDataItem, history []pastOrder, restaurantID string, topn int) []DishRecommendation {
+	liked := map[string]bool{}
+	for _, fav := range favorites {
+		for f := range dishFeatures(fav) {
+			liked[f] = true
+		}
+	}
+
+	var res []DishRecommendation
+	for _, dish := range dishes {
+		features := dishFeatures(dish)
+		var shared []string
+		for f := range features {
+			if liked[f] {
+				shared = append(shared, f)
+			}
+		}
+		if len(shared) == 0 {
+			continue
+		}
+		sort.Strings(shared)
+
+		ordered := false
+		for _, order := range history {
+			if order.RestaurantID == restaurantID && order.DishName == dish.Name {
+				ordered = true
+			}
+		}
+
+		reasoning := fmt.Sprintf("similar to your previous orders: %s", strings.Join(shared, ", "))
+		if ordered {
+			reasoning = "you ordered this before, " + reasoning
+		}
+		res = append(res, DishRecommendation{
+			Name:          dish.Name,
+			Price:         dish.Price,
+			Score:         dish.Score,
+			Similarity:    math.Round(float64(len(shared))/float64(len(features)+len(liked)-len(shared))*100) / 100,
+			OrderedBefore: ordered,
+			Reasoning:     reasoning,
+		})
+	}
+
+	sort.SliceStable(res, func(i, j int) bool {
+		if res[i].Similarity != res[j].Similarity {
+			return res[i].Similarity > res[j].Similarity
+		}
+		return res[i].Score > res[j].Score
+	})
+	if len(res) > topn {
+		res = res[:topn]
+	}
+	return res
+}
+
+// recommendByScore 是新用户的推荐: 评分最高的 topn 道菜.
+func recommendByScore(dishes []restaurantDishDataItem, topn int) []DishRecommendation {
+	sorted := append([]restaurantDishDataItem(nil), dishes...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Score > sorted[j].Score })
+	if len(sorted) > topn {
+		sorted = sorted[:topn]
+	}
+
+	res := make([]DishRecommendation, 0, len(sorted))
+	for _, dish := range sorted {
+		res = append(res, DishRecommendation{
+			Name:      dish.Name,
+			Price:     dish.Price,
+			Score:     dish.Score,
+			Reasoning: fmt.Sprintf("no order history yet, one of the top rated dishes (score %d)", dish.Score),
+		})
+	}
+	return res
+}
+
+// dishFeatureKeywords 从菜名和描述中提取口味和食材特征.
+var dishFeatureKeywords = map[string][]string{
+	"spicy":     {"辣", "椒"},
+	"sour":      {"酸", "醋"},
+	"sweet":     {"甜", "糖"},
+	"braised":   {"红烧"},
+	"meat":      {"肉", "排骨", "猪", "鸭"},
+	"vegetable": {"菜", "瓜", "土豆", "西红柿"},
+	"seafood":   {"鱼", "虾", "🐟", "🦞"},
+}
+
+func dishFeatures(dish restaurantDishDataItem) map[string]bool {
+	text := dish.Name + dish.Desc
+	features := map[string]bool{}
+	for feature, keywords := range dishFeatureKeywords {
+		for _, kw := range keywords {
+			if strings.Contains(text, kw) {
+				features[feature] = true
+				break
+			}
+		}
+	}
+	return features
+}
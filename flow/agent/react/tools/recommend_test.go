@@ -0,0 +1,55 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tools
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecommendDishes(t *testing.T) {
+	ctx := context.Background()
+
+	// 喜欢吃辣的用户在 1001 得到的都是辣菜, 点过的酸辣土豆丝会被标记出来
+	out, err := restService.RecommendDishes(WithUserID(ctx, "u1001"), &RecommendDishesParam{RestaurantID: "1001", Topn: 10})
+	assert.NoError(t, err)
+	assert.True(t, out.Personalized)
+	assert.NotEmpty(t, out.Recommendations)
+	var orderedBefore bool
+	for _, rec := range out.Recommendations {
+		assert.Contains(t, rec.Reasoning, "similar to your previous orders")
+		orderedBefore = orderedBefore || (rec.Name == "酸辣土豆丝" && rec.OrderedBefore)
+	}
+	assert.True(t, orderedBefore)
+	assert.Equal(t, "酸辣土豆丝", out.Recommendations[0].Name)
+
+	// 新用户按评分推荐
+	out, err = restService.RecommendDishes(ctx, &RecommendDishesParam{RestaurantID: "1001"})
+	assert.NoError(t, err)
+	assert.False(t, out.Personalized)
+	assert.Len(t, out.Recommendations, 3)
+	assert.Equal(t, 9, out.Recommendations[0].Score)
+	assert.Contains(t, out.Recommendations[0].Reasoning, "no order history")
+
+	_, err = restService.RecommendDishes(ctx, &RecommendDishesParam{RestaurantID: "404"})
+	assert.Error(t, err)
+
+	assert.Equal(t, "u1", UserIDFrom(WithUserID(ctx, "u1")))
+	assert.Empty(t, UserIDFrom(ctx))
+}
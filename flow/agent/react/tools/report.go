@@ -0,0 +1,153 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/cloudwego/eino/components/tool"
+	"github.com/cloudwego/eino/schema"
+)
+
+func GetReportRestaurantTool() tool.InvokableTool {
+	return safeTool{
+		InvokableTool: NewCancellableTool(&ToolReportRestaurant{
+			backService: restService,
+		}),
+	}
+}
+
+// ToolReportRestaurant 是一个写操作的 tool: 把用户对餐厅的反馈或举报追加到后端, 返回一个确认.
+type ToolReportRestaurant struct {
+	backService *fakeService // fake service
+}
+
+func (t *ToolReportRestaurant) Info(ctx context.Context) (*schema.ToolInfo, error) {
+	return &schema.ToolInfo{
+		Name: "report_restaurant",
+		Desc: "Report a problem with a restaurant on behalf of the user, e.g. wrong information, hygiene or service issues",
+		ParamsOneOf: schema.NewParamsOneOfByParams(map[string]*schema.ParameterInfo{
+			"restaurant_id": {
+				Type:     "string",
+				Desc:     "The id of the reported restaurant",
+				Required: true,
+			},
+			"reason": {
+				Type:     "string",
+				Desc:     "What is wrong with the restaurant, in the user's own words",
+				Required: true,
+			},
+		}),
+	}, nil
+}
+
+func (t *ToolReportRestaurant) InvokableRun(ctx context.Context, argumentsInJSON string, opts ...tool.Option) (string, error) {
+	// 解析参数
+	if err := checkRequired(ctx, t, argumentsInJSON); err != nil {
+		return "", err
+	}
+	p := &ReportRestaurantParam{}
+	err := json.Unmarshal([]byte(argumentsInJSON), p)
+	if err != nil {
+		return "", err
+	}
+
+	// 请求后端服务
+	ack, err := t.backService.ReportRestaurant(ctx, p)
+	if err != nil {
+		return "", err
+	}
+
+	// 序列化结果
+	res, err := marshalResult(ack)
+	if err != nil {
+		return "", err
+	}
+
+	return string(res), nil
+}
+
+type ReportRestaurantParam struct {
+	RestaurantID string `json:"restaurant_id"`
+	Reason       string `json:"reason"`
+}
+
+// RestaurantReport 是保存在后端的一条举报.
+type RestaurantReport struct {
+	ID           string    `json:"id"`
+	RestaurantID string    `json:"restaurant_id"`
+	Reason       string    `json:"reason"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+type ReportAck struct {
+	ReportID     string `json:"report_id"`
+	RestaurantID string `json:"restaurant_id"`
+	Status       string `json:"status"`
+	Message      string `json:"message"`
+}
+
+// maxReportReasonRunes 是 reason 的长度上限, 超过的部分会被截断.
+const maxReportReasonRunes = 500
+
+// ReportRestaurant 追加一条举报. reason 是自由文本, 去掉首尾空白后不能为空.
+func (ft *fakeService) ReportRestaurant(ctx context.Context, in *ReportRestaurantParam) (*ReportAck, error) {
+	if err := ft.simulateLatency(ctx); err != nil {
+		return nil, err
+	}
+
+	reason := strings.TrimSpace(in.Reason)
+	if reason == "" {
+		return nil, errors.New("reason must not be empty, ask the user what is wrong with the restaurant")
+	}
+	if utf8.RuneCountInString(reason) > maxReportReasonRunes {
+		reason = string([]rune(reason)[:maxReportReasonRunes])
+	}
+	if _, err := ft.repo.GetRestaurantByID(ctx, in.RestaurantID); err != nil {
+		return nil, err
+	}
+
+	ft.mu.Lock()
+	defer ft.mu.Unlock()
+	report := RestaurantReport{
+		ID:           fmt.Sprintf("R-%04d", len(ft.reports)+1),
+		RestaurantID: in.RestaurantID,
+		Reason:       reason,
+		CreatedAt:    ft.now(),
+	}
+	ft.reports = append(ft.reports, report)
+
+	return &ReportAck{
+		ReportID:     report.ID,
+		RestaurantID: report.RestaurantID,
+		Status:       "received",
+		Message:      "thanks, the report has been received and will be reviewed",
+	}, nil
+}
+
+// Reports 返回所有举报的副本, 用于调试和测试.
+func (ft *fakeService) Reports() []RestaurantReport {
+	ft.mu.Lock()
+	defer ft.mu.Unlock()
+	return append([]RestaurantReport(nil), ft.reports...)
+}
@@ -0,0 +1,53 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tools
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReportRestaurant(t *testing.T) {
+	ctx := context.Background()
+	fixed := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	svc := &fakeService{repo: database, clock: FixedClock{T: fixed}}
+
+	ack, err := svc.ReportRestaurant(ctx, &ReportRestaurantParam{RestaurantID: "1001", Reason: "  菜单上的价格和实际不符 "})
+	assert.NoError(t, err)
+	assert.Equal(t, "R-0001", ack.ReportID)
+	assert.Equal(t, "received", ack.Status)
+
+	_, err = svc.ReportRestaurant(ctx, &ReportRestaurantParam{RestaurantID: "1002", Reason: strings.Repeat("脏", 600)})
+	assert.NoError(t, err)
+
+	reports := svc.Reports()
+	assert.Len(t, reports, 2)
+	assert.Equal(t, "菜单上的价格和实际不符", reports[0].Reason)
+	assert.Equal(t, fixed, reports[0].CreatedAt)
+	assert.Equal(t, maxReportReasonRunes, utf8.RuneCountInString(reports[1].Reason))
+
+	_, err = svc.ReportRestaurant(ctx, &ReportRestaurantParam{RestaurantID: "1001", Reason: "   "})
+	assert.ErrorContains(t, err, "reason must not be empty")
+	_, err = svc.ReportRestaurant(ctx, &ReportRestaurantParam{RestaurantID: "404", Reason: "closed"})
+	assert.Error(t, err)
+	assert.Len(t, svc.Reports(), 2)
+}
@@ -0,0 +1,84 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/cloudwego/eino/components/tool"
+)
+
+// checkRequired 检查参数是否包含 t 的 schema 中所有 Required 的字段, 缺少时返回一个指明字段名的结构化错误, 让模型补上参数重新调用.
+// 字段不存在、为 null、空字符串或空数组都算缺少. 参数不是合法的 JSON 时不检查, 交给后面的反序列化报错.
+func checkRequired(ctx context.Context, t tool.BaseTool, argumentsInJSON string) error {
+	args := map[string]any{}
+	if err := json.Unmarshal([]byte(argumentsInJSON), &args); err != nil {
+		return nil
+	}
+
+	info, err := t.Info(ctx)
+	if err != nil {
+		return err
+	}
+	if info.ParamsOneOf == nil {
+		return nil
+	}
+	js, err := info.ParamsOneOf.ToJSONSchema()
+	if err != nil {
+		return err
+	}
+	if js == nil {
+		return nil
+	}
+
+	required := append([]string(nil), js.Required...)
+	sort.Strings(required)
+	for _, field := range required {
+		if isMissing(args[field]) {
+			return missingArgumentError(info.Name, field)
+		}
+	}
+	return nil
+}
+
+func isMissing(v any) bool {
+	switch val := v.(type) {
+	case nil:
+		return true
+	case string:
+		return val == ""
+	case []any:
+		return len(val) == 0
+	}
+	return false
+}
+
+// missingArgumentError 是缺少必填参数时的结构化错误. 用同样的参数重试还是缺少, 所以 retry 为 false,
+// 内层的 retryTool 不会重试, 模型补上参数后再调用.
+func missingArgumentError(toolName, field string) error {
+	msg, _ := json.Marshal(map[string]string{
+		"error":   "missing required argument",
+		"field":   field,
+		"message": fmt.Sprintf("%s requires %q, call it again with %q set", toolName, field, field),
+		"retry":   "false",
+	})
+	return errors.New(string(msg))
+}
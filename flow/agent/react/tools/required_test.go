@@ -0,0 +1,157 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/cloudwego/eino/components/tool"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckRequired(t *testing.T) {
+	ctx := context.Background()
+	all := []tool.InvokableTool{
+		&ToolQueryRestaurants{backService: restService},
+		&ToolQueryDishes{backService: restService},
+		&ToolRestaurantStats{backService: restService},
+		&ToolQueryDelivery{backService: restService},
+		&ToolFindRestaurantByName{backService: restService},
+		&ToolQueryAllergens{backService: restService},
+		&ToolIngredients{backService: restService},
+		&ToolCreateShareLink{backService: restService},
+		&ToolMenuQR{backService: restService},
+		&ToolBookTable{backService: restService},
+		&ToolQueryChef{backService: restService},
+		&ToolComputeBill{},
+		&ToolSplitBill{},
+		&ToolQueryAmbiance{backService: restService},
+		&ToolNoiseLevel{backService: restService},
+		&ToolSeasonalMenu{backService: restService},
+		&ToolSuggestAlternative{backService: restService},
+		&ToolSimilarRestaurants{backService: restService},
+		&ToolMostReviewed{backService: restService},
+		&ToolCategoryRank{backService: restService},
+		&ToolCravingRecommend{backService: restService},
+		&ToolBusyHours{backService: restService},
+		&ToolValidateReservationTime{backService: restService},
+		&ToolBestReservationTime{backService: restService},
+		&ToolWeeklyAvailability{backService: restService},
+		&ToolWalkInPolicy{backService: restService},
+		&ToolTakeoutQueue{backService: restService},
+		&ToolTrendingDish{backService: restService},
+		&ToolTableETA{backService: restService},
+		&ToolQueryEvents{backService: restService},
+		&ToolPriceTier{backService: restService},
+		&ToolBudgetSearch{backService: restService},
+		&ToolRestaurantSummary{backService: restService},
+		&ToolBatchRestaurantInfo{backService: restService},
+		&ToolNutrition{backService: restService},
+		&ToolStaticMap{backService: restService},
+		&ToolDirections{backService: restService},
+		&ToolAccessibility{backService: restService},
+		&ToolMealDuration{backService: restService},
+		&ToolTripCost{backService: restService},
+		&ToolParkingCost{backService: restService},
+		&ToolDeliveryVsDineIn{backService: restService},
+		&ToolFormatMenu{backService: restService},
+		&ToolQueryWeather{backService: restService},
+		&ToolSaveRestaurant{backService: restService},
+		&ToolListSaved{backService: restService},
+		newUnsaveRestaurantTool(restService),
+		&ToolSetPreference{backService: restService},
+		&ToolGetPreferences{backService: restService},
+		&ToolGreeting{backService: restService},
+		&ToolCertifications{backService: restService},
+		&ToolMealNutrition{backService: restService},
+		&ToolPortionInfo{backService: restService},
+		&ToolCookingMethod{backService: restService},
+		&ToolTastingMenu{backService: restService},
+		&ToolCheapestGroupMeal{backService: restService},
+		&ToolCarbonFootprint{backService: restService},
+		&ToolCompareDish{backService: restService},
+		&ToolDrinkPairing{backService: restService},
+		&ToolDishAvailability{backService: restService},
+		&ToolMenuInCurrency{backService: restService},
+		&ToolSocialMedia{backService: restService},
+		&ToolContactInfo{backService: restService},
+		&ToolReportRestaurant{backService: restService},
+		&ToolRecommendDishes{backService: restService},
+		&ToolLoyaltyInfo{backService: restService},
+	}
+
+	for _, it := range all {
+		info, err := it.Info(ctx)
+		assert.NoError(t, err)
+		js, err := info.ParamsOneOf.ToJSONSchema()
+		assert.NoError(t, err)
+
+		// 每次去掉一个 required 字段, 其余的填上类型正确的占位值
+		for _, missing := range js.Required {
+			args := map[string]any{}
+			for _, field := range js.Required {
+				if field == missing {
+					continue
+				}
+				prop, _ := js.Properties.Get(field)
+				args[field] = placeholder(prop.Type)
+			}
+			b, _ := json.Marshal(args)
+			_, err := it.InvokableRun(ctx, string(b))
+			assert.ErrorContains(t, err, `"field":"`+missing+`"`, info.Name)
+			assert.ErrorContains(t, err, `missing required argument`, info.Name)
+			assert.False(t, RetryHinted(err), info.Name)
+
+			prop, _ := js.Properties.Get(missing)
+			if prop.Type == "string" {
+				args[missing] = ""
+				b, _ = json.Marshal(args)
+				_, err = it.InvokableRun(ctx, string(b))
+				assert.ErrorContains(t, err, `"field":"`+missing+`"`, info.Name+" empty string")
+			}
+		}
+	}
+}
+
+func TestMissingArgumentIsNotRetried(t *testing.T) {
+	ctx := context.Background()
+
+	// 缺少参数不会因为重试而变好, 只调用一次就交给模型
+	state := &ToolExecutionState{}
+	out, err := NewSafeToolWithRetry(&ToolQueryRestaurants{backService: restService}, 2, 0).
+		InvokableRun(SetToolState(ctx, state), `{"topn": 2}`)
+	assert.NoError(t, err)
+	assert.Contains(t, out, `"retry":"false"`)
+	assert.False(t, state.Success)
+	assert.Equal(t, 1, state.Attempts)
+}
+
+func placeholder(typ string) any {
+	switch typ {
+	case "string":
+		return "x"
+	case "number", "integer":
+		return 1
+	case "boolean":
+		return true
+	case "array":
+		return []string{"x"}
+	}
+	return map[string]any{}
+}
@@ -0,0 +1,168 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/cloudwego/eino/components/tool"
+	"github.com/cloudwego/eino/schema"
+)
+
+func GetValidateReservationTimeTool() tool.InvokableTool {
+	return safeTool{
+		InvokableTool: NewCancellableTool(&ToolValidateReservationTime{
+			backService: restService,
+		}),
+	}
+}
+
+// ToolValidateReservationTime 在预订之前检查时间是否在营业时段内, 营业时段和 query_busy_hours 用的是同一份数据.
+// 检查放在服务端, 模型不需要自己推算, 预订的 tool 也不会收到不可能的时间.
+type ToolValidateReservationTime struct {
+	backService *fakeService // fake service
+}
+
+func (t *ToolValidateReservationTime) Info(ctx context.Context) (*schema.ToolInfo, error) {
+	return &schema.ToolInfo{
+		Name: "validate_reservation_time",
+		Desc: "Check whether a reservation time falls within the opening hours of a restaurant before booking. " +
+			"Returns whether it is valid, the reason, and the window in which reservations are accepted",
+		ParamsOneOf: schema.NewParamsOneOfByParams(map[string]*schema.ParameterInfo{
+			"restaurant_id": {
+				Type:     "string",
+				Desc:     "The id of one restaurant",
+				Required: true,
+			},
+			"time": {
+				Type:     "string",
+				Desc:     "The reservation time, in the format 2006-01-02 15:04, or 15:04 for today",
+				Required: true,
+			},
+		}),
+	}, nil
+}
+
+func (t *ToolValidateReservationTime) InvokableRun(ctx context.Context, argumentsInJSON string, opts ...tool.Option) (string, error) {
+	// 解析参数
+	if err := checkRequired(ctx, t, argumentsInJSON); err != nil {
+		return "", err
+	}
+	p := &ValidateReservationTimeParam{}
+	err := json.Unmarshal([]byte(argumentsInJSON), p)
+	if err != nil {
+		return "", err
+	}
+
+	// 请求后端服务
+	check, err := t.backService.ValidateReservationTime(ctx, p)
+	if err != nil {
+		return "", err
+	}
+
+	// 序列化结果
+	res, err := marshalResult(check)
+	if err != nil {
+		return "", err
+	}
+
+	return string(res), nil
+}
+
+type ValidateReservationTimeParam struct {
+	RestaurantID string `json:"restaurant_id"`
+	Time         string `json:"time"`
+}
+
+// ReservationTimeCheck 中的时间都是 fake 后端 clock 所在的时区.
+type ReservationTimeCheck struct {
+	RestaurantID string            `json:"restaurant_id"`
+	Time         string            `json:"time"` // 2006-01-02 15:04
+	Valid        bool              `json:"valid"`
+	Reason       string            `json:"reason"`
+	Window       ReservationWindow `json:"window"`
+}
+
+// ReservationWindow 是一天中可以预订的时段, 包含首尾.
+type ReservationWindow struct {
+	Opens           string `json:"opens"`            // 15:04
+	LastReservation string `json:"last_reservation"` // 15:04
+}
+
+const reservationTimeLayout = "2006-01-02 15:04"
+
+// ValidateReservationTime 检查预订时间是否在营业时段 (busyFirstHour 到 busyLastHour) 内, 并且不早于现在.
+// 时间格式不对是错误, 时间不可预订不是错误, 而是 valid 为 false 并说明原因.
+func (ft *fakeService) ValidateReservationTime(ctx context.Context, in *ValidateReservationTimeParam) (*ReservationTimeCheck, error) {
+	if err := ft.simulateLatency(ctx); err != nil {
+		return nil, err
+	}
+
+	if _, err := ft.repo.GetRestaurantByID(ctx, in.RestaurantID); err != nil {
+		return nil, err
+	}
+	now := ft.now()
+	at, err := parseReservationTime(in.Time, now)
+	if err != nil {
+		return nil, err
+	}
+
+	out := &ReservationTimeCheck{
+		RestaurantID: in.RestaurantID,
+		Time:         at.Format(reservationTimeLayout),
+		Window: ReservationWindow{
+			Opens:           fmt.Sprintf("%02d:00", busyFirstHour),
+			LastReservation: fmt.Sprintf("%02d:00", busyLastHour),
+		},
+	}
+	out.Reason = reservationTimeProblem(at, now)
+	if out.Reason == "" {
+		out.Valid = true
+		out.Reason = "within opening hours"
+	}
+	return out, nil
+}
+
+// reservationTimeProblem 返回 at 不可预订的原因, 可以预订时返回空字符串.
+func reservationTimeProblem(at, now time.Time) string {
+	minutes := at.Hour()*60 + at.Minute()
+	switch {
+	case at.Before(now):
+		return "the time is in the past"
+	case minutes < busyFirstHour*60:
+		return fmt.Sprintf("the restaurant opens at %02d:00", busyFirstHour)
+	case minutes > busyLastHour*60:
+		return fmt.Sprintf("the last reservation is at %02d:00", busyLastHour)
+	}
+	return ""
+}
+
+// parseReservationTime 解析 2006-01-02 15:04 或者 15:04 (now 所在的那一天), 使用 now 的时区.
+func parseReservationTime(s string, now time.Time) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	if at, err := time.ParseInLocation(reservationTimeLayout, s, now.Location()); err == nil {
+		return at, nil
+	}
+	if clock, err := time.ParseInLocation("15:04", s, now.Location()); err == nil {
+		return time.Date(now.Year(), now.Month(), now.Day(), clock.Hour(), clock.Minute(), 0, 0, now.Location()), nil
+	}
+	return time.Time{}, fmt.Errorf("invalid time %q, use the format 2006-01-02 15:04 or 15:04", s)
+}
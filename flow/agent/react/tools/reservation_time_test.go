@@ -0,0 +1,60 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tools
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateReservationTime(t *testing.T) {
+	ctx := context.Background()
+	loc := time.FixedZone("CST", 8*3600)
+	svc := &fakeService{repo: restService.repo, clock: FixedClock{T: time.Date(2024, 6, 1, 15, 30, 0, 0, loc)}}
+
+	cases := []struct {
+		time   string
+		valid  bool
+		reason string
+	}{
+		{time: "19:00", valid: true, reason: "within opening hours"},
+		{time: "2024-06-02 10:00", valid: true, reason: "within opening hours"},
+		{time: "2024-06-02 22:00", valid: true, reason: "within opening hours"},
+		{time: "2024-06-02 22:30", reason: "last reservation is at 22:00"},
+		{time: "2024-06-02 09:45", reason: "opens at 10:00"},
+		{time: "12:00", reason: "in the past"},
+	}
+	for _, c := range cases {
+		out, err := svc.ValidateReservationTime(ctx, &ValidateReservationTimeParam{RestaurantID: "1001", Time: c.time})
+		assert.NoError(t, err, c.time)
+		assert.Equal(t, c.valid, out.Valid, c.time)
+		assert.Contains(t, out.Reason, c.reason, c.time)
+		assert.Equal(t, ReservationWindow{Opens: "10:00", LastReservation: "22:00"}, out.Window)
+	}
+
+	out, err := svc.ValidateReservationTime(ctx, &ValidateReservationTimeParam{RestaurantID: "1001", Time: "19:05"})
+	assert.NoError(t, err)
+	assert.Equal(t, "2024-06-01 19:05", out.Time)
+
+	_, err = svc.ValidateReservationTime(ctx, &ValidateReservationTimeParam{RestaurantID: "1001", Time: "tonight"})
+	assert.ErrorContains(t, err, "invalid time")
+	_, err = svc.ValidateReservationTime(ctx, &ValidateReservationTimeParam{RestaurantID: "404", Time: "19:00"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,156 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/cloudwego/eino/components/tool"
+	"github.com/cloudwego/eino/schema"
+)
+
+func GetRestaurantSummaryTool() tool.InvokableTool {
+	return safeTool{
+		InvokableTool: NewCancellableTool(&ToolRestaurantSummary{
+			backService: restService,
+		}),
+	}
+}
+
+// ToolRestaurantSummary 在服务端把评分、菜系、招牌菜和价格档位拼成一段可以直接展示的介绍,
+// 模型不需要先调用好几个 tool 再自己组织语言. 拼接用到的字段也一并返回, 方便模型回答追问.
+type ToolRestaurantSummary struct {
+	backService *fakeService // fake service
+}
+
+func (t *ToolRestaurantSummary) Info(ctx context.Context) (*schema.ToolInfo, error) {
+	return &schema.ToolInfo{
+		Name: "query_restaurant_summary",
+		Desc: "Get a ready-to-present one-paragraph summary of a restaurant, covering its score, cuisine, signature dish and price tier, " +
+			"together with the fields the summary is built from",
+		ParamsOneOf: schema.NewParamsOneOfByParams(map[string]*schema.ParameterInfo{
+			"restaurant_id": {
+				Type:     "string",
+				Desc:     "The id of one restaurant",
+				Required: true,
+			},
+		}),
+	}, nil
+}
+
+func (t *ToolRestaurantSummary) InvokableRun(ctx context.Context, argumentsInJSON string, opts ...tool.Option) (string, error) {
+	// 解析参数
+	if err := checkRequired(ctx, t, argumentsInJSON); err != nil {
+		return "", err
+	}
+	p := &RestaurantSummaryParam{}
+	err := json.Unmarshal([]byte(argumentsInJSON), p)
+	if err != nil {
+		return "", err
+	}
+
+	// 请求后端服务
+	summary, err := t.backService.RestaurantSummary(ctx, p)
+	if err != nil {
+		return "", err
+	}
+
+	// 序列化结果
+	res, err := marshalResult(summary)
+	if err != nil {
+		return "", err
+	}
+
+	return string(res), nil
+}
+
+type RestaurantSummaryParam struct {
+	RestaurantID string `json:"restaurant_id"`
+}
+
+// RestaurantSummary 没有菜品时 SignatureDish 为空, PriceTier 为 unknown.
+type RestaurantSummary struct {
+	RestaurantID  string   `json:"restaurant_id"`
+	Summary       string   `json:"summary"`
+	Name          string   `json:"name"`
+	Place         string   `json:"place"`
+	Score         int      `json:"score"`
+	Cuisine       string   `json:"cuisine,omitempty"`
+	SignatureDish *Dish    `json:"signature_dish,omitempty"`
+	PriceTier     string   `json:"price_tier"`
+	AveragePrice  *float64 `json:"average_price,omitempty"`
+}
+
+// RestaurantSummary 的招牌菜是评分最高的菜 (同 recommendByScore), 价格档位同 priceTier, 都基于餐厅的全部菜品,
+// 不按用户的饮食偏好筛选: 介绍的是餐厅本身.
+func (ft *fakeService) RestaurantSummary(ctx context.Context, in *RestaurantSummaryParam) (*RestaurantSummary, error) {
+	if err := ft.simulateLatency(ctx); err != nil {
+		return nil, err
+	}
+
+	rest, err := ft.repo.GetRestaurantByID(ctx, in.RestaurantID)
+	if err != nil {
+		return nil, err
+	}
+
+	out := &RestaurantSummary{
+		RestaurantID: rest.ID,
+		Name:         rest.Name,
+		Place:        rest.Place,
+		Score:        rest.Score,
+		Cuisine:      rest.Cuisine,
+	}
+	if top := recommendByScore(rest.Dishes, 1); len(top) > 0 {
+		out.SignatureDish = &Dish{Name: top[0].Name, Price: top[0].Price, Score: top[0].Score}
+	}
+
+	dishes := make([]Dish, 0, len(rest.Dishes))
+	for _, dish := range rest.Dishes {
+		dishes = append(dishes, Dish{Name: dish.Name, Price: dish.Price})
+	}
+	tier, avg := priceTier(dishes)
+	out.PriceTier = tier
+	if len(dishes) > 0 {
+		out.AveragePrice = &avg
+	}
+
+	out.Summary = summarizeRestaurant(out)
+	return out, nil
+}
+
+// summarizeRestaurant 只用 RestaurantSummary 中已有的字段, 缺少的部分直接略过, 不编造.
+func summarizeRestaurant(s *RestaurantSummary) string {
+	var sb strings.Builder
+	if s.Cuisine != "" {
+		fmt.Fprintf(&sb, "%s is a %s restaurant at %s, rated %d out of 10.", s.Name, s.Cuisine, s.Place, s.Score)
+	} else {
+		fmt.Fprintf(&sb, "%s is a restaurant at %s, rated %d out of 10.", s.Name, s.Place, s.Score)
+	}
+	if s.SignatureDish != nil {
+		fmt.Fprintf(&sb, " Its signature dish is %s (%d yuan, rated %d).",
+			s.SignatureDish.Name, s.SignatureDish.Price, s.SignatureDish.Score)
+	}
+	if s.AveragePrice != nil {
+		fmt.Fprintf(&sb, " Price tier %s, with an average dish price of %.1f yuan.", s.PriceTier, *s.AveragePrice)
+	} else {
+		sb.WriteString(" No menu is available yet.")
+	}
+	return sb.String()
+}
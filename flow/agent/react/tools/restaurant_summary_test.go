@@ -0,0 +1,47 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tools
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRestaurantSummary(t *testing.T) {
+	ctx := context.Background()
+	out, err := restService.RestaurantSummary(ctx, &RestaurantSummaryParam{RestaurantID: "1001"})
+	assert.NoError(t, err)
+	assert.Equal(t, "云边小馆", out.Name)
+	assert.Equal(t, "家常菜", out.Cuisine)
+	assert.Equal(t, "韩式辣白菜", out.SignatureDish.Name)
+	assert.NotNil(t, out.AveragePrice)
+	assert.Contains(t, out.Summary, "云边小馆 is a 家常菜 restaurant")
+	assert.Contains(t, out.Summary, "signature dish is 韩式辣白菜")
+	assert.Contains(t, out.Summary, "Price tier "+out.PriceTier)
+
+	emptyDB := &restaurantDatabase{restaurantByID: map[string]restaurantDataItem{"9001": {ID: "9001", Name: "新店", Place: "杭州", Score: 7}}}
+	out, err = (&fakeService{repo: emptyDB}).RestaurantSummary(ctx, &RestaurantSummaryParam{RestaurantID: "9001"})
+	assert.NoError(t, err)
+	assert.Nil(t, out.SignatureDish)
+	assert.Equal(t, "unknown", out.PriceTier)
+	assert.Equal(t, "新店 is a restaurant at 杭州, rated 7 out of 10. No menu is available yet.", out.Summary)
+
+	_, err = restService.RestaurantSummary(ctx, &RestaurantSummaryParam{RestaurantID: "404"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,97 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tools
+
+import (
+	"encoding/json"
+	"sync/atomic"
+)
+
+// DefaultMaxResults 是一个 tool 结果中每个列表最多保留的条数.
+const DefaultMaxResults = 20
+
+// maxResults 是全局的上限, 和各个 tool 自己的 topn 默认值无关, 用来控制 tool 结果占用的上下文. 0 表示不限制.
+var maxResults atomic.Int64
+
+func init() {
+	maxResults.Store(DefaultMaxResults)
+}
+
+// SetMaxResults 设置每个 tool 结果中每个列表最多返回给模型的条数, 0 表示不限制.
+func SetMaxResults(n int) {
+	maxResults.Store(int64(max(n, 0)))
+}
+
+// marshalResult 是各个 tool 统一的结果序列化, 按 SetMaxResults 截断过长的列表:
+//   - 结果本身是列表时, 改为 {"results": [...], "truncated": true, "total_available": N};
+//   - 结果是对象时, 截断其中的列表字段, 并加上 "truncated": true 和 "total_available": {"字段": N}.
+//
+// 没有截断时结果和 json.Marshal 完全相同. 只处理最外面一层, 嵌套更深的列表不截断.
+// 截断之后再由 SetResultSerializer 设置的 ResultSerializer 转成最终的格式, 默认是 JSON.
+func marshalResult(v any) ([]byte, error) {
+	res, err := limitResult(v)
+	if err != nil {
+		return nil, err
+	}
+	return currentSerializer().Serialize(res)
+}
+
+func limitResult(v any) ([]byte, error) {
+	res, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	limit := int(maxResults.Load())
+	if limit <= 0 {
+		return res, nil
+	}
+
+	var list []json.RawMessage
+	if json.Unmarshal(res, &list) == nil {
+		if len(list) <= limit {
+			return res, nil
+		}
+		return json.Marshal(map[string]any{
+			"results":         list[:limit],
+			"truncated":       true,
+			"total_available": len(list),
+		})
+	}
+
+	var obj map[string]json.RawMessage
+	if json.Unmarshal(res, &obj) != nil {
+		return res, nil
+	}
+	totals := map[string]int{}
+	for key, raw := range obj {
+		if json.Unmarshal(raw, &list) != nil || len(list) <= limit {
+			continue
+		}
+		truncated, err := json.Marshal(list[:limit])
+		if err != nil {
+			return nil, err
+		}
+		obj[key] = truncated
+		totals[key] = len(list)
+	}
+	if len(totals) == 0 {
+		return res, nil
+	}
+	obj["truncated"], _ = json.Marshal(true)
+	obj["total_available"], _ = json.Marshal(totals)
+	return json.Marshal(obj)
+}
@@ -0,0 +1,59 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tools
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMarshalResult(t *testing.T) {
+	SetMaxResults(2)
+	defer SetMaxResults(DefaultMaxResults)
+
+	res, err := marshalResult([]int{1, 2, 3})
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"results":[1,2],"truncated":true,"total_available":3}`, string(res))
+
+	res, err = marshalResult(map[string]any{"name": "x", "items": []int{1, 2, 3}, "tags": []string{"a"}})
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"name":"x","items":[1,2],"tags":["a"],"truncated":true,"total_available":{"items":3}}`, string(res))
+
+	// 没有超过上限时和 json.Marshal 一样, 字段顺序也不变
+	res, err = marshalResult(Restaurant{ID: "1001", Name: "云边小馆"})
+	assert.NoError(t, err)
+	assert.Equal(t, `{"id":"1001","name":"云边小馆","place":"","desc":"","score":0,"walk_ins":false}`, string(res))
+
+	SetMaxResults(0)
+	res, err = marshalResult([]int{1, 2, 3})
+	assert.NoError(t, err)
+	assert.Equal(t, `[1,2,3]`, string(res))
+}
+
+func TestQueryDishesMaxResults(t *testing.T) {
+	SetMaxResults(2)
+	defer SetMaxResults(DefaultMaxResults)
+
+	// topn 大于上限时仍然只返回上限条数, 并告诉模型一共有多少
+	dishes := &ToolQueryDishes{backService: restService}
+	out, err := dishes.InvokableRun(context.Background(), `{"restaurant_id": "1001", "topn": 5}`)
+	assert.NoError(t, err)
+	assert.Contains(t, out, `"truncated":true`)
+	assert.Contains(t, out, `"total_available":5`)
+}
@@ -0,0 +1,140 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"math/rand"
+	"strings"
+	"time"
+
+	"github.com/cloudwego/eino/components/tool"
+)
+
+// RetryConfig 控制 NewRetryTool 的重试策略, 零值字段使用默认值.
+type RetryConfig struct {
+	// MaxAttempts 是包括第一次在内的总尝试次数, 默认 3.
+	MaxAttempts int
+	// BaseDelay 是退避的基数, 第 n 次重试的等待上限为 BaseDelay * 2^(n-1), 默认 100ms.
+	BaseDelay time.Duration
+	// MaxDelay 是单次等待的上限, 默认 1s.
+	MaxDelay time.Duration
+	// MaxTotalDelay 是所有重试累计等待的上限, 默认 5s, 用完后直接返回最后一次的错误.
+	MaxTotalDelay time.Duration
+	// Rand 用于生成 jitter, 测试中可以注入固定种子的 *rand.Rand 得到确定的等待时间. 为空时使用当前时间做种子.
+	Rand *rand.Rand
+	// Backoff 决定每次重试前等待多久. 为空时使用由 BaseDelay, MaxDelay 和 Rand 构造的 JitteredBackoff.
+	Backoff BackoffStrategy
+	// Retryable 判断一个错误是否值得重试. 为空时除了标记了 "retry":"false" 的错误都重试, 见 RetryHinted.
+	// ctx 被取消后无论如何都不再重试.
+	Retryable func(err error) bool
+}
+
+// retryTool 在被包装的 tool 返回错误时, 按 config.Backoff 等待后重试, 默认是 full jitter 的指数退避.
+// 取消错误和 config.Retryable 判断为不可重试的错误 (默认是标记了 "retry":"false" 的错误) 不会重试.
+// 重试次数和累计等待时长会记录到 ToolExecutionState 中.
+type retryTool struct {
+	tool.InvokableTool
+	config RetryConfig
+}
+
+func NewRetryTool(t tool.InvokableTool, config RetryConfig) tool.InvokableTool {
+	if config.MaxAttempts <= 0 {
+		config.MaxAttempts = 3
+	}
+	if config.BaseDelay <= 0 {
+		config.BaseDelay = 100 * time.Millisecond
+	}
+	if config.MaxDelay <= 0 {
+		config.MaxDelay = time.Second
+	}
+	if config.MaxTotalDelay <= 0 {
+		config.MaxTotalDelay = 5 * time.Second
+	}
+	if config.Backoff == nil {
+		config.Backoff = NewJitteredBackoff(config.BaseDelay, config.MaxDelay, config.Rand)
+	}
+	if config.Retryable == nil {
+		config.Retryable = func(err error) bool { return !strings.Contains(err.Error(), `"retry":"false"`) }
+	}
+	return &retryTool{InvokableTool: t, config: config}
+}
+
+func (r *retryTool) InvokableRun(ctx context.Context, argumentsInJSON string, opts ...tool.Option) (string, error) {
+	state := GetToolState(ctx)
+	// 同一个 state 被多次调用时在之前的计数上累加
+	var prevAttempts int
+	var prevDelay time.Duration
+	if state != nil {
+		prevAttempts, prevDelay = state.Attempts, state.RetryDelay
+	}
+
+	var totalDelay time.Duration
+	for attempt := 1; ; attempt++ {
+		out, err := r.InvokableTool.InvokableRun(ctx, argumentsInJSON, opts...)
+		if state != nil {
+			state.Attempts = prevAttempts + attempt
+			state.RetryDelay = prevDelay + totalDelay
+		}
+		if err == nil || attempt >= r.config.MaxAttempts || ctx.Err() != nil || !r.config.Retryable(err) {
+			return out, err
+		}
+
+		// 只有累计等待的预算用完才停止重试, Backoff 返回 0 表示立即重试
+		remaining := r.config.MaxTotalDelay - totalDelay
+		if remaining <= 0 {
+			return out, err
+		}
+		delay := min(max(r.config.Backoff.Next(attempt), 0), remaining)
+		if delay == 0 {
+			continue
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return "", cancelledError(ctx.Err())
+		case <-timer.C:
+		}
+		totalDelay += delay
+	}
+}
+
+// RetryHinted 判断错误是否是 tool 明确标记了可以重试的结构化错误, 也就是错误信息是带 "retry":"true" 的 JSON 对象,
+// 比如 query_restaurants 模拟的 service temporarily unavailable. 和 RetryConfig 默认的判断相比更保守, 没有标记的错误不重试.
+func RetryHinted(err error) bool {
+	var body struct {
+		Retry any `json:"retry"`
+	}
+	if json.Unmarshal([]byte(err.Error()), &body) != nil {
+		return false
+	}
+	return body.Retry == "true" || body.Retry == true
+}
+
+// NewSafeToolWithRetry 是带重试的 NewSafeTool: 错误标记了 "retry":"true" (见 RetryHinted) 时在 tool 内部最多重试 maxRetries 次,
+// 第 n 次重试前最多等待 baseDelay * 2^(n-1) (full jitter), 省掉一次让模型自己重试的往返. 等待时 ctx 被取消则立即返回取消信息.
+// 重试用完后和 NewSafeTool 一样把最后一次的错误作为 content 交给模型, 实际的调用次数记录在 ToolExecutionState.Attempts 中.
+func NewSafeToolWithRetry(t tool.InvokableTool, maxRetries int, baseDelay time.Duration) tool.InvokableTool {
+	return NewSafeTool(NewRetryTool(t, RetryConfig{
+		MaxAttempts: max(maxRetries, 0) + 1,
+		BaseDelay:   baseDelay,
+		Retryable:   RetryHinted,
+	}))
+}
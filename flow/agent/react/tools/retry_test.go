@@ -0,0 +1,177 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tools
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/cloudwego/eino/components/tool"
+	"github.com/cloudwego/eino/schema"
+	"github.com/stretchr/testify/assert"
+)
+
+// flakyTool fails the first failures calls with err.
+type flakyTool struct {
+	failures int
+	err      error
+	calls    int
+}
+
+func (f *flakyTool) Info(ctx context.Context) (*schema.ToolInfo, error) {
+	return &schema.ToolInfo{Name: "flaky"}, nil
+}
+
+func (f *flakyTool) InvokableRun(ctx context.Context, argumentsInJSON string, opts ...tool.Option) (string, error) {
+	f.calls++
+	if f.calls <= f.failures {
+		return "", f.err
+	}
+	return "done", nil
+}
+
+func TestRetryTool(t *testing.T) {
+	run := func(flaky *flakyTool, config RetryConfig) (*ToolExecutionState, string, error) {
+		state := &ToolExecutionState{}
+		out, err := NewRetryTool(flaky, config).InvokableRun(SetToolState(context.Background(), state), `{}`)
+		return state, out, err
+	}
+	config := func(seed int64) RetryConfig {
+		return RetryConfig{BaseDelay: time.Millisecond, MaxDelay: 4 * time.Millisecond, Rand: rand.New(rand.NewSource(seed))}
+	}
+
+	state, out, err := run(&flakyTool{failures: 2, err: errors.New("temporary")}, config(1))
+	assert.NoError(t, err)
+	assert.Equal(t, "done", out)
+	assert.Equal(t, 3, state.Attempts)
+
+	// 相同的种子得到相同的等待时间
+	again, _, _ := run(&flakyTool{failures: 2, err: errors.New("temporary")}, config(1))
+	assert.Equal(t, state.RetryDelay, again.RetryDelay)
+	assert.Less(t, state.RetryDelay, 3*time.Millisecond)
+
+	flaky := &flakyTool{failures: 5, err: errors.New("temporary")}
+	state, _, err = run(flaky, config(1))
+	assert.Error(t, err)
+	assert.Equal(t, 3, flaky.calls)
+
+	// 不可重试的错误只调用一次
+	flaky = &flakyTool{failures: 5, err: errors.New(`{"error":"down","retry":"false"}`)}
+	state, _, err = run(flaky, config(1))
+	assert.Error(t, err)
+	assert.Equal(t, 1, flaky.calls)
+	assert.Equal(t, time.Duration(0), state.RetryDelay)
+
+	// 累计等待不超过 MaxTotalDelay
+	flaky = &flakyTool{failures: 5, err: errors.New("temporary")}
+	state, _, err = run(flaky, RetryConfig{
+		MaxAttempts:   5,
+		BaseDelay:     time.Hour,
+		MaxDelay:      time.Hour,
+		MaxTotalDelay: 5 * time.Millisecond,
+		Rand:          rand.New(rand.NewSource(1)),
+	})
+	assert.Error(t, err)
+	assert.Equal(t, 5*time.Millisecond, state.RetryDelay)
+	assert.Equal(t, 2, flaky.calls)
+}
+
+func TestRetryHinted(t *testing.T) {
+	assert.True(t, RetryHinted(errors.New(`{"error":"service temporarily unavailable","retry":"true"}`)))
+	assert.True(t, RetryHinted(errors.New(`{"error":"busy","retry":true}`)))
+	assert.False(t, RetryHinted(errors.New(`{"error":"not found","retry":"false"}`)))
+	assert.False(t, RetryHinted(errors.New(`{"error":"not found"}`)))
+	assert.False(t, RetryHinted(errors.New(`connection reset, "retry":"true"`)))
+}
+
+func TestNewSafeToolWithRetry(t *testing.T) {
+	retryable := errors.New(`{"error":"service temporarily unavailable","retry":"true"}`)
+	run := func(ctx context.Context, flaky *flakyTool, maxRetries int) (*ToolExecutionState, string) {
+		state := &ToolExecutionState{}
+		out, err := NewSafeToolWithRetry(flaky, maxRetries, time.Millisecond).InvokableRun(SetToolState(ctx, state), `{}`)
+		assert.NoError(t, err)
+		return state, out
+	}
+
+	// 标记了可以重试的错误在 tool 内部重试, 模型只看到最后的结果
+	flaky := &flakyTool{failures: 2, err: retryable}
+	state, out := run(context.Background(), flaky, 3)
+	assert.Equal(t, "done", out)
+	assert.True(t, state.Success)
+	assert.Equal(t, 3, state.Attempts)
+
+	// 重试用完后把最后一次的错误作为 content 返回
+	flaky = &flakyTool{failures: 10, err: retryable}
+	state, out = run(context.Background(), flaky, 2)
+	assert.Equal(t, retryable.Error(), out)
+	assert.False(t, state.Success)
+	assert.Equal(t, 3, flaky.calls)
+	assert.Equal(t, 3, state.Attempts)
+
+	// 没有标记可以重试的错误不重试
+	flaky = &flakyTool{failures: 1, err: errors.New("boom")}
+	state, out = run(context.Background(), flaky, 3)
+	assert.Equal(t, "boom", out)
+	assert.Equal(t, 1, flaky.calls)
+	assert.Equal(t, 1, state.Attempts)
+
+	// maxRetries 为 0 时和 NewSafeTool 一样
+	flaky = &flakyTool{failures: 1, err: retryable}
+	_, out = run(context.Background(), flaky, 0)
+	assert.Equal(t, retryable.Error(), out)
+	assert.Equal(t, 1, flaky.calls)
+
+	// 等待重试时 ctx 被取消, 立即返回取消信息
+	ctx, cancel := context.WithCancel(context.Background())
+	flaky = &flakyTool{failures: 10, err: retryable}
+	state = &ToolExecutionState{}
+	wrapped := NewSafeToolWithRetry(flaky, 5, time.Hour)
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		cancel()
+	}()
+	start := time.Now()
+	out, err := wrapped.InvokableRun(SetToolState(ctx, state), `{}`)
+	assert.NoError(t, err)
+	assert.Contains(t, out, `"error":"cancelled"`)
+	assert.False(t, state.Success)
+	assert.Less(t, time.Since(start), 900*time.Millisecond)
+}
+
+func TestRestaurantToolRetriesOnlyHintedErrors(t *testing.T) {
+	SetFailureRate(0)
+	defer SetFailureRate(DefaultFailureRate)
+
+	// 参数不合法时重试没有意义, 只调用一次
+	state := &ToolExecutionState{}
+	out, err := GetRestaurantTool().InvokableRun(SetToolState(context.Background(), state), `{"location": "北京", "min_hygiene_grade": "Z"}`)
+	assert.NoError(t, err)
+	assert.Contains(t, out, "min_hygiene_grade")
+	assert.False(t, state.Success)
+	assert.Equal(t, 1, state.Attempts)
+
+	// 模拟的后端错误标记了 "retry":"true", 会在内部重试
+	SetFailureRate(1)
+	state = &ToolExecutionState{}
+	out, err = GetRestaurantTool().InvokableRun(SetToolState(context.Background(), state), `{"location": "北京"}`)
+	assert.NoError(t, err)
+	assert.Contains(t, out, "temporarily unavailable")
+	assert.Equal(t, 3, state.Attempts)
+}
@@ -0,0 +1,99 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tools
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RunStats 按 tool 名统计一次运行中每个 tool 的调用次数、成功次数和耗时.
+// 零值可以直接使用; tool 的回调会并发触发, 所有方法都可以并发调用.
+type RunStats struct {
+	mu    sync.Mutex
+	tools map[string]*ToolRunStats
+}
+
+// ToolRunStats 是一个 tool 的统计, 见 RunStats.
+type ToolRunStats struct {
+	Calls     int
+	Successes int
+	// Latency 是这个 tool 所有调用的耗时之和
+	Latency time.Duration
+}
+
+// AvgLatency 是每次调用的平均耗时, 没有调用时为 0.
+func (st ToolRunStats) AvgLatency() time.Duration {
+	if st.Calls == 0 {
+		return 0
+	}
+	return st.Latency / time.Duration(st.Calls)
+}
+
+// Record 记录 name 的一次调用. success 一般取自 ToolExecutionState.Success.
+func (s *RunStats) Record(name string, success bool, latency time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.tools == nil {
+		s.tools = make(map[string]*ToolRunStats)
+	}
+	st := s.tools[name]
+	if st == nil {
+		st = &ToolRunStats{}
+		s.tools[name] = st
+	}
+	st.Calls++
+	if success {
+		st.Successes++
+	}
+	st.Latency += latency
+}
+
+// Get 返回 name 的统计的一份拷贝, 没有调用过时为零值.
+func (s *RunStats) Get(name string) ToolRunStats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if st := s.tools[name]; st != nil {
+		return *st
+	}
+	return ToolRunStats{}
+}
+
+// Report 把统计格式化成每个 tool 一行, 按 tool 名排序, 如 "query_restaurants: 3 calls, 2 ok, avg 142ms".
+// 没有任何调用时返回空字符串.
+func (s *RunStats) Report() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	names := make([]string, 0, len(s.tools))
+	for name := range s.tools {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var sb strings.Builder
+	for _, name := range names {
+		st := s.tools[name]
+		fmt.Fprintf(&sb, "%s: %d calls, %d ok, avg %v\n", name, st.Calls, st.Successes, st.AvgLatency().Round(time.Millisecond))
+	}
+	return sb.String()
+}
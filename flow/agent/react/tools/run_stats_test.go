@@ -0,0 +1,51 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tools
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunStats(t *testing.T) {
+	s := &RunStats{}
+	assert.Empty(t, s.Report())
+
+	// tool 的回调会并发触发
+	var wg sync.WaitGroup
+	for i := 0; i < 3; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s.Record("query_restaurants", i != 0, time.Duration(100+i*42)*time.Millisecond)
+		}()
+	}
+	wg.Wait()
+	s.Record("query_dishes", true, time.Millisecond)
+
+	assert.Equal(t, ToolRunStats{Calls: 3, Successes: 2, Latency: 426 * time.Millisecond}, s.Get("query_restaurants"))
+	assert.Equal(t, 142*time.Millisecond, s.Get("query_restaurants").AvgLatency())
+	assert.Zero(t, s.Get("unknown"))
+	assert.Zero(t, ToolRunStats{}.AvgLatency())
+
+	// 按 tool 名排序
+	assert.Equal(t, "query_dishes: 1 calls, 1 ok, avg 1ms\n"+
+		"query_restaurants: 3 calls, 2 ok, avg 142ms\n", s.Report())
+}
@@ -0,0 +1,198 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"slices"
+
+	"github.com/cloudwego/eino/components/tool"
+	"github.com/cloudwego/eino/schema"
+)
+
+func GetSaveRestaurantTool() tool.InvokableTool {
+	return safeTool{
+		InvokableTool: NewCancellableTool(&ToolSaveRestaurant{
+			backService: restService,
+		}),
+	}
+}
+
+func GetListSavedTool() tool.InvokableTool {
+	return safeTool{
+		InvokableTool: NewCancellableTool(&ToolListSaved{
+			backService: restService,
+		}),
+	}
+}
+
+// ToolSaveRestaurant 和 ToolListSaved 是一对读写同一份按用户区分的状态的 tool: 收藏餐厅, 以及列出收藏的餐厅.
+// 用户 id 从 context 中读取, 匿名用户不能收藏.
+type ToolSaveRestaurant struct {
+	backService *fakeService // fake service
+}
+
+func (t *ToolSaveRestaurant) Info(ctx context.Context) (*schema.ToolInfo, error) {
+	return &schema.ToolInfo{
+		Name: "save_restaurant",
+		Desc: "Save a restaurant to the current user's favorites so they can find it later. Saving the same restaurant again has no effect",
+		ParamsOneOf: schema.NewParamsOneOfByParams(map[string]*schema.ParameterInfo{
+			"restaurant_id": {
+				Type:     "string",
+				Desc:     "The id of the restaurant to save",
+				Required: true,
+			},
+		}),
+	}, nil
+}
+
+func (t *ToolSaveRestaurant) InvokableRun(ctx context.Context, argumentsInJSON string, opts ...tool.Option) (string, error) {
+	// 解析参数
+	if err := checkRequired(ctx, t, argumentsInJSON); err != nil {
+		return "", err
+	}
+	p := &SaveRestaurantParam{}
+	err := json.Unmarshal([]byte(argumentsInJSON), p)
+	if err != nil {
+		return "", err
+	}
+
+	// 请求后端服务
+	ack, err := t.backService.SaveRestaurant(ctx, p)
+	if err != nil {
+		return "", err
+	}
+
+	// 序列化结果
+	res, err := marshalResult(ack)
+	if err != nil {
+		return "", err
+	}
+
+	return string(res), nil
+}
+
+type ToolListSaved struct {
+	backService *fakeService // fake service
+}
+
+func (t *ToolListSaved) Info(ctx context.Context) (*schema.ToolInfo, error) {
+	return &schema.ToolInfo{
+		Name:        "list_saved_restaurants",
+		Desc:        "List the restaurants the current user has saved to favorites, in the order they were saved",
+		ParamsOneOf: schema.NewParamsOneOfByParams(map[string]*schema.ParameterInfo{}),
+	}, nil
+}
+
+func (t *ToolListSaved) InvokableRun(ctx context.Context, argumentsInJSON string, opts ...tool.Option) (string, error) {
+	// 请求后端服务
+	saved, err := t.backService.ListSaved(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	// 序列化结果
+	res, err := marshalResult(saved)
+	if err != nil {
+		return "", err
+	}
+
+	return string(res), nil
+}
+
+type SaveRestaurantParam struct {
+	RestaurantID string `json:"restaurant_id"`
+}
+
+type SaveAck struct {
+	RestaurantID string `json:"restaurant_id"`
+	// AlreadySaved 为 true 表示之前已经收藏过, 这次没有任何改变
+	AlreadySaved bool   `json:"already_saved"`
+	SavedCount   int    `json:"saved_count"`
+	Message      string `json:"message"`
+}
+
+type SavedRestaurants struct {
+	Restaurants []Restaurant `json:"restaurants"`
+}
+
+var errGuestUser = errors.New(`{"error":"sign in required","message":"favorites are only available to signed-in users, ask the user to sign in","retry":"false"}`)
+
+// SaveRestaurant 把餐厅加入 context 中用户的收藏, 重复收藏是幂等的.
+func (ft *fakeService) SaveRestaurant(ctx context.Context, in *SaveRestaurantParam) (*SaveAck, error) {
+	if err := ft.simulateLatency(ctx); err != nil {
+		return nil, err
+	}
+
+	userID := UserIDFrom(ctx)
+	if userID == "" {
+		return nil, errGuestUser
+	}
+	if _, err := ft.repo.GetRestaurantByID(ctx, in.RestaurantID); err != nil {
+		return nil, err
+	}
+
+	ft.mu.Lock()
+	defer ft.mu.Unlock()
+	if ft.saved == nil {
+		ft.saved = make(map[string][]string)
+	}
+	ack := &SaveAck{RestaurantID: in.RestaurantID}
+	if slices.Contains(ft.saved[userID], in.RestaurantID) {
+		ack.AlreadySaved = true
+		ack.Message = "the restaurant was already in the favorites"
+	} else {
+		ft.saved[userID] = append(ft.saved[userID], in.RestaurantID)
+		ack.Message = "saved to favorites"
+	}
+	ack.SavedCount = len(ft.saved[userID])
+	return ack, nil
+}
+
+// ListSaved 返回 context 中用户收藏的餐厅.
+func (ft *fakeService) ListSaved(ctx context.Context) (*SavedRestaurants, error) {
+	if err := ft.simulateLatency(ctx); err != nil {
+		return nil, err
+	}
+
+	userID := UserIDFrom(ctx)
+	if userID == "" {
+		return nil, errGuestUser
+	}
+
+	ft.mu.Lock()
+	ids := append([]string(nil), ft.saved[userID]...)
+	ft.mu.Unlock()
+
+	out := &SavedRestaurants{Restaurants: make([]Restaurant, 0, len(ids))}
+	for _, id := range ids {
+		rest, err := ft.repo.GetRestaurantByID(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		out.Restaurants = append(out.Restaurants, Restaurant{
+			ID:      rest.ID,
+			Name:    rest.Name,
+			Place:   rest.Place,
+			Score:   rest.Score,
+			Cuisine: rest.Cuisine,
+		})
+	}
+	return out, nil
+}
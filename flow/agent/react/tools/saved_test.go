@@ -0,0 +1,101 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tools
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSaveRestaurant(t *testing.T) {
+	svc := &fakeService{repo: database}
+	alice := WithUserID(context.Background(), "u1001")
+	bob := WithUserID(context.Background(), "u2002")
+
+	ack, err := svc.SaveRestaurant(alice, &SaveRestaurantParam{RestaurantID: "1002"})
+	assert.NoError(t, err)
+	assert.False(t, ack.AlreadySaved)
+	assert.Equal(t, 1, ack.SavedCount)
+
+	_, err = svc.SaveRestaurant(alice, &SaveRestaurantParam{RestaurantID: "2001"})
+	assert.NoError(t, err)
+
+	// 重复收藏不改变列表
+	ack, err = svc.SaveRestaurant(alice, &SaveRestaurantParam{RestaurantID: "1002"})
+	assert.NoError(t, err)
+	assert.True(t, ack.AlreadySaved)
+	assert.Equal(t, 2, ack.SavedCount)
+
+	saved, err := svc.ListSaved(alice)
+	assert.NoError(t, err)
+	assert.Len(t, saved.Restaurants, 2)
+	assert.Equal(t, "1002", saved.Restaurants[0].ID)
+	assert.Equal(t, "2001", saved.Restaurants[1].ID)
+
+	// 不同用户的收藏互不影响
+	saved, err = svc.ListSaved(bob)
+	assert.NoError(t, err)
+	assert.Empty(t, saved.Restaurants)
+	assert.NotNil(t, saved.Restaurants)
+
+	_, err = svc.SaveRestaurant(alice, &SaveRestaurantParam{RestaurantID: "404"})
+	assert.Error(t, err)
+
+	_, err = svc.SaveRestaurant(context.Background(), &SaveRestaurantParam{RestaurantID: "1002"})
+	assert.ErrorIs(t, err, errGuestUser)
+	_, err = svc.ListSaved(context.Background())
+	assert.ErrorIs(t, err, errGuestUser)
+}
+
+func TestUnsaveRestaurant(t *testing.T) {
+	svc := &fakeService{repo: database}
+	alice := WithUserID(context.Background(), "u1001")
+
+	_, err := svc.SaveRestaurant(alice, &SaveRestaurantParam{RestaurantID: "1002"})
+	assert.NoError(t, err)
+	_, err = svc.SaveRestaurant(alice, &SaveRestaurantParam{RestaurantID: "2001"})
+	assert.NoError(t, err)
+
+	// InferTool 推导出的 schema 和手写的一样带 required 和描述
+	it := newUnsaveRestaurantTool(svc)
+	info, err := it.Info(alice)
+	assert.NoError(t, err)
+	assert.Equal(t, "unsave_restaurant", info.Name)
+	js, err := info.ParamsOneOf.ToJSONSchema()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"restaurant_id"}, js.Required)
+
+	out, err := it.InvokableRun(alice, `{"restaurant_id":"1002"}`)
+	assert.NoError(t, err)
+	assert.Contains(t, out, "removed from favorites")
+
+	saved, err := svc.ListSaved(alice)
+	assert.NoError(t, err)
+	assert.Len(t, saved.Restaurants, 1)
+	assert.Equal(t, "2001", saved.Restaurants[0].ID)
+
+	// 重复取消收藏不报错
+	ack, err := svc.UnsaveRestaurant(alice, &UnsaveRestaurantParam{RestaurantID: "1002"})
+	assert.NoError(t, err)
+	assert.True(t, ack.NotSaved)
+	assert.Equal(t, 1, ack.SavedCount)
+
+	_, err = svc.UnsaveRestaurant(context.Background(), &UnsaveRestaurantParam{RestaurantID: "1002"})
+	assert.ErrorIs(t, err, errGuestUser)
+}
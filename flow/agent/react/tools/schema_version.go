@@ -0,0 +1,58 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tools
+
+import (
+	"context"
+	"maps"
+
+	"github.com/cloudwego/eino/components/tool"
+	"github.com/cloudwego/eino/schema"
+)
+
+// SchemaVersion 是本包所有 tool 参数 schema 的版本. 增删参数、修改参数的类型或是否必填时必须加一,
+// 并用 go test -run TestToolSchemaSnapshot -update-schemas 重新生成快照, 否则测试会失败.
+// 依赖某个 tool 参数的 prompt 可以对照 Info 的 Extra[SchemaVersionKey] 确认 schema 没有变.
+const SchemaVersion = 6
+
+// SchemaVersionKey 是 ToolInfo.Extra 中 schema 版本的 key.
+const SchemaVersionKey = "schema_version"
+
+// schemaVersionTool 在 Info 的 Extra 中带上 SchemaVersion, 其余原样交给被包装的 tool.
+type schemaVersionTool struct {
+	tool.InvokableTool
+}
+
+// NewSchemaVersionTool wraps t so that its Info carries SchemaVersion in Extra[SchemaVersionKey].
+func NewSchemaVersionTool(t tool.InvokableTool) tool.InvokableTool {
+	return &schemaVersionTool{InvokableTool: t}
+}
+
+func (s *schemaVersionTool) Info(ctx context.Context) (*schema.ToolInfo, error) {
+	info, err := s.InvokableTool.Info(ctx)
+	if err != nil {
+		return nil, err
+	}
+	// 复制一份再修改, 被包装的 tool 可能每次返回同一个 ToolInfo
+	cp := *info
+	cp.Extra = maps.Clone(info.Extra)
+	if cp.Extra == nil {
+		cp.Extra = map[string]any{}
+	}
+	cp.Extra[SchemaVersionKey] = SchemaVersion
+	return &cp, nil
+}
@@ -0,0 +1,132 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/cloudwego/eino/components/tool"
+	"github.com/cloudwego/eino/schema"
+)
+
+func GetSeasonalMenuTool() tool.InvokableTool {
+	return safeTool{
+		InvokableTool: NewCancellableTool(&ToolSeasonalMenu{
+			backService: restService,
+		}),
+	}
+}
+
+// ToolSeasonalMenu 按 clock 的当前月份判断季节, 返回餐厅中标记为这个季节应季的菜, 比如夏天的凉菜和冬天的火锅.
+type ToolSeasonalMenu struct {
+	backService *fakeService // fake service
+}
+
+func (t *ToolSeasonalMenu) Info(ctx context.Context) (*schema.ToolInfo, error) {
+	return &schema.ToolInfo{
+		Name: "query_seasonal_menu",
+		Desc: "Query the seasonal specials of a restaurant for the current season, e.g. cold dishes in summer. " +
+			"Returns the detected season and the dishes of that season",
+		ParamsOneOf: schema.NewParamsOneOfByParams(map[string]*schema.ParameterInfo{
+			"restaurant_id": {
+				Type:     "string",
+				Desc:     "The id of one restaurant",
+				Required: true,
+			},
+		}),
+	}, nil
+}
+
+func (t *ToolSeasonalMenu) InvokableRun(ctx context.Context, argumentsInJSON string, opts ...tool.Option) (string, error) {
+	// 解析参数
+	if err := checkRequired(ctx, t, argumentsInJSON); err != nil {
+		return "", err
+	}
+	p := &SeasonalMenuParam{}
+	err := json.Unmarshal([]byte(argumentsInJSON), p)
+	if err != nil {
+		return "", err
+	}
+
+	// 请求后端服务
+	menu, err := t.backService.QuerySeasonalMenu(ctx, p)
+	if err != nil {
+		return "", err
+	}
+
+	// 序列化结果
+	res, err := marshalResult(menu)
+	if err != nil {
+		return "", err
+	}
+
+	return string(res), nil
+}
+
+type SeasonalMenuParam struct {
+	RestaurantID string `json:"restaurant_id"`
+}
+
+// SeasonalMenu 中 dishes 为空时 message 说明这家餐厅这个季节没有应季菜.
+type SeasonalMenu struct {
+	RestaurantID string `json:"restaurant_id"`
+	Season       string `json:"season"`
+	Month        string `json:"month"`
+	Dishes       []Dish `json:"dishes"`
+	Message      string `json:"message,omitempty"`
+}
+
+// seasonOf 按北半球的习惯划分季节: 3-5 月为春, 6-8 月为夏, 9-11 月为秋, 12-2 月为冬.
+func seasonOf(month time.Month) string {
+	switch month {
+	case time.March, time.April, time.May:
+		return "spring"
+	case time.June, time.July, time.August:
+		return "summer"
+	case time.September, time.October, time.November:
+		return "autumn"
+	}
+	return "winter"
+}
+
+// QuerySeasonalMenu 返回 in.RestaurantID 中 Seasons 包含当前季节的菜, 保持菜单的顺序.
+func (ft *fakeService) QuerySeasonalMenu(ctx context.Context, in *SeasonalMenuParam) (*SeasonalMenu, error) {
+	if err := ft.simulateLatency(ctx); err != nil {
+		return nil, err
+	}
+
+	rest, err := ft.repo.GetRestaurantByID(ctx, in.RestaurantID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := ft.now()
+	out := &SeasonalMenu{RestaurantID: rest.ID, Season: seasonOf(now.Month()), Month: now.Month().String(), Dishes: []Dish{}}
+	for _, dish := range rest.Dishes {
+		if slices.Contains(dish.Seasons, out.Season) {
+			out.Dishes = append(out.Dishes, toDish(dish))
+		}
+	}
+	if len(out.Dishes) == 0 {
+		out.Message = fmt.Sprintf("%s has no seasonal dishes for %s", rest.Name, out.Season)
+	}
+	return out, nil
+}
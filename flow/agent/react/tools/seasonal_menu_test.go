@@ -0,0 +1,66 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tools
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSeasonOf(t *testing.T) {
+	assert.Equal(t, "spring", seasonOf(time.March))
+	assert.Equal(t, "summer", seasonOf(time.August))
+	assert.Equal(t, "autumn", seasonOf(time.November))
+	assert.Equal(t, "winter", seasonOf(time.December))
+	assert.Equal(t, "winter", seasonOf(time.February))
+}
+
+func TestQuerySeasonalMenu(t *testing.T) {
+	ctx := context.Background()
+	at := func(month time.Month) *fakeService {
+		return &fakeService{repo: database, clock: FixedClock{T: time.Date(2024, month, 1, 12, 0, 0, 0, time.FixedZone("CST", 8*3600))}}
+	}
+
+	menu, err := at(time.June).QuerySeasonalMenu(ctx, &SeasonalMenuParam{RestaurantID: "1002"})
+	assert.NoError(t, err)
+	assert.Equal(t, "summer", menu.Season)
+	assert.Equal(t, "June", menu.Month)
+	if assert.Len(t, menu.Dishes, 1) {
+		assert.Equal(t, "辣椒拌皮蛋", menu.Dishes[0].Name)
+		assert.Equal(t, []string{"summer"}, menu.Dishes[0].Seasons)
+	}
+	assert.Empty(t, menu.Message)
+
+	menu, err = at(time.January).QuerySeasonalMenu(ctx, &SeasonalMenuParam{RestaurantID: "2010"})
+	assert.NoError(t, err)
+	assert.Equal(t, "winter", menu.Season)
+	if assert.Len(t, menu.Dishes, 1) {
+		assert.Equal(t, "超级大火锅🍲", menu.Dishes[0].Name)
+	}
+
+	// 这个季节没有应季菜
+	menu, err = at(time.January).QuerySeasonalMenu(ctx, &SeasonalMenuParam{RestaurantID: "1002"})
+	assert.NoError(t, err)
+	assert.Empty(t, menu.Dishes)
+	assert.Contains(t, menu.Message, "no seasonal dishes for winter")
+
+	_, err = at(time.June).QuerySeasonalMenu(ctx, &SeasonalMenuParam{RestaurantID: "9999"})
+	assert.Error(t, err)
+}
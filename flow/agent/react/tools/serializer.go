@@ -0,0 +1,215 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tools
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"sync/atomic"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ResultSerializer 决定 tool 结果以什么格式交给模型, 用来比较不同格式对模型理解结果的影响.
+// 输入是 marshalResult 按 json tag 序列化并截断之后的 JSON, 这样字段名和截断规则在各种格式之间保持一致.
+type ResultSerializer interface {
+	Serialize(js []byte) ([]byte, error)
+}
+
+// JSONSerializer 原样返回 JSON, 是默认的格式.
+type JSONSerializer struct{}
+
+func (JSONSerializer) Serialize(js []byte) ([]byte, error) {
+	return js, nil
+}
+
+// YAMLSerializer 把结果转成 YAML, 字段保持 JSON 中的顺序.
+type YAMLSerializer struct{}
+
+func (YAMLSerializer) Serialize(js []byte) ([]byte, error) {
+	node, err := decodeOrdered(js)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(node); err != nil {
+		return nil, err
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
+}
+
+// KeyValueSerializer 把结果展开成每行一个 "路径=值", 比如 "dishes[0].name=红烧肉", 是最紧凑的格式.
+// 字符串不加引号, 包含换行时才按 Go 的语法加上引号; 空的列表和对象写成 [] 和 {}.
+type KeyValueSerializer struct{}
+
+func (KeyValueSerializer) Serialize(js []byte) ([]byte, error) {
+	node, err := decodeOrdered(js)
+	if err != nil {
+		return nil, err
+	}
+	var lines []string
+	flattenNode("", node, &lines)
+	return []byte(strings.Join(lines, "\n")), nil
+}
+
+// resultSerializers 是 ResultSerializerByName 支持的格式.
+var resultSerializers = map[string]ResultSerializer{
+	"json": JSONSerializer{},
+	"yaml": YAMLSerializer{},
+	"kv":   KeyValueSerializer{},
+}
+
+// ResultSerializerByName 按名称 (json, yaml 或 kv) 返回对应的 ResultSerializer.
+func ResultSerializerByName(name string) (ResultSerializer, error) {
+	s, ok := resultSerializers[strings.ToLower(strings.TrimSpace(name))]
+	if !ok {
+		names := make([]string, 0, len(resultSerializers))
+		for n := range resultSerializers {
+			names = append(names, n)
+		}
+		sort.Strings(names)
+		return nil, fmt.Errorf("unknown result format %q, use one of %s", name, strings.Join(names, ", "))
+	}
+	return s, nil
+}
+
+type serializerBox struct{ s ResultSerializer }
+
+var resultSerializer atomic.Pointer[serializerBox]
+
+// SetResultSerializer 设置所有 tool 结果使用的格式, 传 nil 恢复为 JSONSerializer.
+func SetResultSerializer(s ResultSerializer) {
+	if s == nil {
+		s = JSONSerializer{}
+	}
+	resultSerializer.Store(&serializerBox{s: s})
+}
+
+func currentSerializer() ResultSerializer {
+	if box := resultSerializer.Load(); box != nil {
+		return box.s
+	}
+	return JSONSerializer{}
+}
+
+// decodeOrdered 把 JSON 解析成 yaml.Node, 和 map[string]any 不同, 对象的字段保持原来的顺序.
+func decodeOrdered(js []byte) (*yaml.Node, error) {
+	dec := json.NewDecoder(bytes.NewReader(js))
+	dec.UseNumber()
+	node, err := decodeNode(dec)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return nil, fmt.Errorf("unexpected data after the JSON value")
+	}
+	return node, nil
+}
+
+func decodeNode(dec *json.Decoder) (*yaml.Node, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+	switch v := tok.(type) {
+	case json.Delim:
+		if v == '{' {
+			node := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+			for dec.More() {
+				key, err := dec.Token()
+				if err != nil {
+					return nil, err
+				}
+				value, err := decodeNode(dec)
+				if err != nil {
+					return nil, err
+				}
+				node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key.(string)}, value)
+			}
+			_, err := dec.Token()
+			return node, err
+		}
+		node := &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+		for dec.More() {
+			item, err := decodeNode(dec)
+			if err != nil {
+				return nil, err
+			}
+			node.Content = append(node.Content, item)
+		}
+		_, err := dec.Token()
+		return node, err
+	case string:
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: v}, nil
+	case json.Number:
+		tag := "!!int"
+		if strings.ContainsAny(v.String(), ".eE") {
+			tag = "!!float"
+		}
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: tag, Value: v.String()}, nil
+	case bool:
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!bool", Value: strconv.FormatBool(v)}, nil
+	}
+	return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!null", Value: "null"}, nil
+}
+
+func flattenNode(path string, node *yaml.Node, lines *[]string) {
+	emit := func(value string) {
+		if path == "" {
+			*lines = append(*lines, value)
+			return
+		}
+		*lines = append(*lines, path+"="+value)
+	}
+
+	switch node.Kind {
+	case yaml.MappingNode:
+		if len(node.Content) == 0 {
+			emit("{}")
+		}
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key := node.Content[i].Value
+			if path != "" {
+				key = path + "." + key
+			}
+			flattenNode(key, node.Content[i+1], lines)
+		}
+	case yaml.SequenceNode:
+		if len(node.Content) == 0 {
+			emit("[]")
+		}
+		for i, item := range node.Content {
+			flattenNode(fmt.Sprintf("%s[%d]", path, i), item, lines)
+		}
+	default:
+		if strings.ContainsAny(node.Value, "\n\r") {
+			emit(strconv.Quote(node.Value))
+			return
+		}
+		emit(node.Value)
+	}
+}
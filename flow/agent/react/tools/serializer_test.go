@@ -0,0 +1,137 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tools
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResultSerializers(t *testing.T) {
+	js := []byte(`{"name":"红烧肉","price":38.5,"score":9,"spicy":false,"note":"true","chef":null,"tags":["meat","braised"],"desc":"line1\nline2","extra":{},"items":[]}`)
+
+	out, err := JSONSerializer{}.Serialize(js)
+	assert.NoError(t, err)
+	assert.Equal(t, string(js), string(out))
+
+	// 字段保持原来的顺序, 看起来像其他类型的字符串 "true" 要加引号
+	out, err = YAMLSerializer{}.Serialize(js)
+	assert.NoError(t, err)
+	assert.Equal(t, `name: 红烧肉
+price: 38.5
+score: 9
+spicy: false
+note: "true"
+chef: null
+tags:
+  - meat
+  - braised
+desc: |-
+  line1
+  line2
+extra: {}
+items: []`, string(out))
+
+	out, err = KeyValueSerializer{}.Serialize(js)
+	assert.NoError(t, err)
+	assert.Equal(t, `name=红烧肉
+price=38.5
+score=9
+spicy=false
+note=true
+chef=null
+tags[0]=meat
+tags[1]=braised
+desc="line1\nline2"
+extra={}
+items=[]`, string(out))
+
+	out, err = KeyValueSerializer{}.Serialize([]byte(`[{"id":"1001","dishes":[{"name":"a"}]},"x"]`))
+	assert.NoError(t, err)
+	assert.Equal(t, "[0].id=1001\n[0].dishes[0].name=a\n[1]=x", string(out))
+
+	_, err = YAMLSerializer{}.Serialize([]byte(`{"a":1}{}`))
+	assert.Error(t, err)
+}
+
+func TestMarshalResultUsesSerializer(t *testing.T) {
+	defer SetResultSerializer(nil)
+	defer SetMaxResults(DefaultMaxResults)
+	SetMaxResults(1)
+
+	SetResultSerializer(KeyValueSerializer{})
+	out, err := marshalResult(map[string]any{"results": []string{"a", "b"}})
+	assert.NoError(t, err)
+	// 先按 SetMaxResults 截断, 再转换格式
+	assert.Equal(t, "results[0]=a\ntotal_available.results=2\ntruncated=true", string(out))
+
+	SetResultSerializer(nil)
+	out, err = marshalResult([]string{"a"})
+	assert.NoError(t, err)
+	assert.Equal(t, `["a"]`, string(out))
+}
+
+func TestResultSerializerByName(t *testing.T) {
+	s, err := ResultSerializerByName(" YAML ")
+	assert.NoError(t, err)
+	assert.Equal(t, YAMLSerializer{}, s)
+
+	_, err = ResultSerializerByName("xml")
+	assert.ErrorContains(t, err, "json, kv, yaml")
+}
+
+// BenchmarkResultSerializers 测量序列化一个很大的结果 (1000 家餐厅) 的耗时和内存分配, 给分页和截断的默认值提供参考.
+// 每次的 marshalResult 都不截断, 输出的字节数作为自定义指标 out-bytes 输出, 它大致决定了结果占用多少上下文.
+func BenchmarkResultSerializers(b *testing.B) {
+	defer SetResultSerializer(nil)
+	defer SetMaxResults(DefaultMaxResults)
+	SetMaxResults(0)
+
+	rests := make([]Restaurant, 1000)
+	for i := range rests {
+		rests[i] = Restaurant{
+			ID:       fmt.Sprintf("%04d", i),
+			Name:     fmt.Sprintf("餐厅 %d", i),
+			Place:    "北京",
+			Desc:     "这是一家在北京的餐厅, 口味多种多样",
+			Score:    i % 10,
+			Cuisine:  "家常菜",
+			Ambiance: &Ambiance{Tags: []string{"casual", "family-friendly"}, NoiseLevel: i % 5},
+		}
+	}
+
+	for _, name := range []string{"json", "yaml", "kv"} {
+		b.Run(name, func(b *testing.B) {
+			s, err := ResultSerializerByName(name)
+			if err != nil {
+				b.Fatal(err)
+			}
+			SetResultSerializer(s)
+			b.ReportAllocs()
+
+			var out []byte
+			for i := 0; i < b.N; i++ {
+				if out, err = marshalResult(rests); err != nil {
+					b.Fatal(err)
+				}
+			}
+			b.ReportMetric(float64(len(out)), "out-bytes")
+		})
+	}
+}
@@ -17,85 +17,336 @@
 package tools
 
 import (
+	"bytes"
+	"cmp"
 	"context"
+	"embed"
+	"encoding/json"
 	"fmt"
+	"io/fs"
+	"maps"
+	"math"
+	"math/rand"
+	"slices"
+	"sort"
 	"strings"
+	"sync"
+	"time"
 )
 
-// fake service 模拟的后端服务的 service
-// 提供 QueryDishes, QueryRestaurants 两个方法.
-var restService = &fakeService{
-	repo: database,
+// dataFS 中的 dataFile 是 fake database 的餐厅和菜品数据, 格式为 location => 餐厅列表.
+// 加载时餐厅和菜品都按 score 从高到低排序, 同分的保持文件中的顺序, topn 取的就是评分最高的几个.
+// 修改数据不需要改代码, 重新编译即可.
+//
+//go:embed data/restaurants.json
+var dataFS embed.FS
+
+const dataFile = "data/restaurants.json"
+
+var (
+	// fake service 模拟的后端服务的 service
+	// 提供 QueryDishes, QueryRestaurants 两个方法.
+	restService *fakeService
+	// fake database.
+	database *restaurantDatabase
+	// dataErr 是加载 dataFile 时的错误, 见 DataError.
+	dataErr error
+)
+
+func init() {
+	restService, dataErr = newFakeService()
+	if dataErr != nil {
+		// 数据加载失败时不返回空结果, 每次查询都返回这个错误, 由 safeTool 交给模型
+		restService = &fakeService{repo: &restaurantDatabase{err: dataErr}}
+	}
+	database = restService.repo
 }
 
-// fake database.
-var database = &restaurantDatabase{
-	restaurantByID:        make(map[string]restaurantDataItem),
-	restaurantsByLocation: make(map[string][]restaurantDataItem),
+// DataError 返回加载餐厅数据时的错误, 数据正常时为 nil. main 可以在启动时检查, 不必等到第一次调用 tool 才发现.
+func DataError() error {
+	return dataErr
 }
 
-func init() {
-	// prepare database
-	restData := getData()
-	for location, rests := range restData {
-		for _, rest := range rests {
-			database.restaurantByID[rest.ID] = rest
-			database.restaurantsByLocation[location] = append(database.restaurantsByLocation[location], rest)
+// newFakeService 从 dataFS 加载餐厅数据, 创建 fake service.
+func newFakeService() (*fakeService, error) {
+	repo, err := loadDatabase(dataFS, dataFile)
+	if err != nil {
+		return nil, err
+	}
+	return &fakeService{
+		repo:   repo,
+		orders: defaultOrderHistory(),
+		points: defaultLoyaltyPoints(),
+	}, nil
+}
+
+// loadDatabase 从 fsys 中的 path 加载餐厅数据. 文件不存在、不是合法的 JSON、有不认识的字段 (多半是字段名写错了)、
+// 没有任何餐厅或者餐厅的 id 为空或重复时返回错误. 同一个 location 的餐厅和每家餐厅的菜品按 score 从高到低排序.
+func loadDatabase(fsys fs.FS, path string) (*restaurantDatabase, error) {
+	b, err := fs.ReadFile(fsys, path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read restaurant data: %w", err)
+	}
+	var data map[string][]restaurantDataItem
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&data); err != nil {
+		return nil, fmt.Errorf("invalid restaurant data %s: %w", path, err)
+	}
+
+	db := &restaurantDatabase{
+		restaurantByID:        make(map[string]restaurantDataItem),
+		restaurantsByLocation: make(map[string][]restaurantDataItem),
+	}
+	for _, location := range slices.Sorted(maps.Keys(data)) {
+		for i, rest := range data[location] {
+			if rest.ID == "" {
+				return nil, fmt.Errorf("invalid restaurant data %s: restaurant #%d in %s has no id", path, i+1, location)
+			}
+			if _, ok := db.restaurantByID[rest.ID]; ok {
+				return nil, fmt.Errorf("invalid restaurant data %s: duplicate restaurant id %s", path, rest.ID)
+			}
+			slices.SortStableFunc(rest.Dishes, byScoreDesc(func(d restaurantDishDataItem) int { return d.Score }))
+			db.restaurantByID[rest.ID] = rest
+			db.restaurantsByLocation[location] = append(db.restaurantsByLocation[location], rest)
 		}
+		slices.SortStableFunc(db.restaurantsByLocation[location], byScoreDesc(func(r restaurantDataItem) int { return r.Score }))
+	}
+	if len(db.restaurantByID) == 0 {
+		return nil, fmt.Errorf("invalid restaurant data %s: no restaurants", path)
 	}
+	return db, nil
+}
+
+// byScoreDesc 返回按 score 从高到低排序的比较函数, 配合 slices.SortStableFunc 使用, 同分的保持数据文件中的顺序.
+func byScoreDesc[T any](score func(T) int) func(a, b T) int {
+	return func(a, b T) int { return cmp.Compare(score(b), score(a)) }
 }
 
 // ====== fake service ======
 type fakeService struct {
 	repo *restaurantDatabase
+
+	// latency 模拟后端的响应耗时, 等待期间会响应 ctx 的取消.
+	latency time.Duration
+
+	// clock 提供 "现在" 的时间, 为空时使用 RealClock.
+	clock Clock
+
+	randMu sync.Mutex // *rand.Rand 不是并发安全的
+	// rand 用于打乱同分的餐厅, 为空时在第一次使用时以当前时间为种子创建.
+	rand *rand.Rand
+
+	mu         sync.Mutex              // 保护下面这些由写操作类 tool 修改的状态
+	shareLinks map[string][]string     // token => restaurant ids
+	menuQRs    map[string]MenuSnapshot // token => 生成二维码时的菜单
+	reports    []RestaurantReport
+	orders     map[string][]pastOrder        // user id => 历史订单
+	points     map[string]map[string]int     // user id => restaurant id => 积分
+	saved      map[string][]string           // user id => 收藏的 restaurant ids, 按收藏顺序
+	prefs      map[string]DietaryPreferences // user id => 饮食偏好, 匿名用户的偏好在 context 中
+	bookings   []Booking
+	bookingIdx map[string]int // user id + idempotency key => bookings 中的下标
+}
+
+// SetBackendLatency 设置 fake service 的模拟耗时, 方便演示 tool 调用过程中被取消.
+func SetBackendLatency(d time.Duration) {
+	restService.latency = d
+}
+
+// simulateLatency 等待 latency, 如果 ctx 先结束则返回 ctx 的错误.
+func (ft *fakeService) simulateLatency(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if ft.latency <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(ft.latency)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
 }
 
 // QueryRestaurants 查询一个 location 的餐厅列表.
 func (ft *fakeService) QueryRestaurants(ctx context.Context, in *QueryRestaurantsParam) (out []Restaurant, err error) {
-	rests, err := ft.repo.GetRestaurantsByLocation(ctx, in.Location, in.Topn)
+	if err := ft.simulateLatency(ctx); err != nil {
+		return nil, err
+	}
+
+	if in.MinHygieneGrade != "" && !validHygieneGrade(in.MinHygieneGrade) {
+		return nil, fmt.Errorf("min_hygiene_grade must be one of A, B, C, got %q", in.MinHygieneGrade)
+	}
+
+	// 同分的餐厅在整个排序中打乱一次, 之后每次按这个顺序取前 limit 家, 筛选后不够 topn 家时再多取一些;
+	// 只在取到的窗口内打乱的话, 排在窗口外的同分餐厅永远没有机会排进 topn
+	var ranked []restaurantDataItem
+	fetch := func(ctx context.Context, limit int) ([]restaurantDataItem, error) {
+		if ranked == nil {
+			all, err := ft.repo.GetRestaurantsByLocation(ctx, in.Location, math.MaxInt)
+			if err != nil {
+				return nil, err
+			}
+			ranked = ft.shuffleTies(all)
+		}
+		return ranked[:min(limit, len(ranked))], nil
+	}
+	rests, err := fetchFiltered(ctx, in.Topn, fetch, in.matches)
 	if err != nil {
 		return nil, err
 	}
 
 	res := make([]Restaurant, 0, len(rests))
 	for _, rest := range rests {
-
-		res = append(res, Restaurant{
-			ID:    rest.ID,
-			Name:  rest.Name,
-			Place: rest.Place,
-			Score: rest.Score,
-		})
+		res = append(res, toRestaurant(rest))
 	}
 
 	return res, nil
 }
 
+// matches 表示餐厅是否满足 accessible_only、min_hygiene_grade 和 WithMinScore 的筛选条件.
+func (in *QueryRestaurantsParam) matches(rest restaurantDataItem) bool {
+	if in.AccessibleOnly && !isAccessible(rest.Accessibility) {
+		return false
+	}
+	if in.MinHygieneGrade != "" && !meetsHygieneGrade(rest.Certifications, in.MinHygieneGrade) {
+		return false
+	}
+	return rest.Score >= in.minScore
+}
+
+func toRestaurant(rest restaurantDataItem) Restaurant {
+	return Restaurant{
+		ID:      rest.ID,
+		Name:    rest.Name,
+		Place:   rest.Place,
+		Score:   rest.Score,
+		Cuisine: rest.Cuisine,
+		WalkIns: rest.WalkIns,
+
+		Ambiance:      toAmbiance(rest.Ambiance),
+		Accessibility: toAccessibility(rest.Accessibility),
+
+		Certifications: toCertifications(rest.Certifications),
+
+		ReviewCount: len(rest.Reviews),
+	}
+}
+
+// RestaurantStats 统计一个 location (为空时为全部) 的餐厅聚合信息.
+func (ft *fakeService) RestaurantStats(ctx context.Context, in *RestaurantStatsParam) (*RestaurantStats, error) {
+	if err := ft.simulateLatency(ctx); err != nil {
+		return nil, err
+	}
+
+	rests, err := ft.repo.GetRestaurants(ctx, in.Location)
+	if err != nil {
+		return nil, err
+	}
+
+	stats := computeRestaurantStats(rests)
+	stats.Location = in.Location
+	return stats, nil
+}
+
 // QueryDishes 根据餐厅的 id, 查询餐厅的菜品列表.
 func (ft *fakeService) QueryDishes(ctx context.Context, in *QueryDishesParam) (res []Dish, err error) {
-	dishes, err := ft.repo.GetDishesByRestaurant(ctx, in.RestaurantID, in.Topn)
+	if err := ft.simulateLatency(ctx); err != nil {
+		return nil, err
+	}
+
+	// 按用户的饮食偏好 (和 in_stock_only、WithMinScore) 筛选, 筛掉的菜多时向后端多取一些, 尽量凑满 topn
+	prefs := ft.preferencesFor(ctx)
+	if in.IgnorePreferences {
+		prefs = DietaryPreferences{}
+	}
+	fetch := func(ctx context.Context, limit int) ([]restaurantDishDataItem, error) {
+		return ft.repo.GetDishesByRestaurant(ctx, in.RestaurantID, limit)
+	}
+	allows := func(dish restaurantDishDataItem) bool { return prefs.allows(dish) && dish.Score >= in.minScore }
+	if in.InStockOnly {
+		inStock, allowed := ft.inStock(in.RestaurantID), allows
+		allows = func(dish restaurantDishDataItem) bool { return allowed(dish) && inStock(dish) }
+	}
+	dishes, err := fetchFiltered(ctx, in.Topn, fetch, allows)
 	if err != nil {
 		return nil, err
 	}
 
 	res = make([]Dish, 0, len(dishes))
 	for _, dish := range dishes {
-		res = append(res, Dish{
-			Name:  dish.Name,
-			Desc:  dish.Desc,
-			Price: dish.Price,
-			Score: dish.Score,
-		})
+		res = append(res, toDish(dish))
 	}
 
 	return res, nil
 }
 
+func toDish(dish restaurantDishDataItem) Dish {
+	return Dish{
+		Name:      dish.Name,
+		Desc:      dish.Desc,
+		Price:     dish.Price,
+		Score:     dish.Score,
+		Allergens: dish.Allergens,
+		Nutrition: toNutrition(dish.Nutrition),
+
+		Ingredients: dish.Ingredients,
+
+		PrepMinutes: dish.PrepMinutes,
+
+		SpiceLevel: dish.SpiceLevel,
+		Vegetarian: dish.Vegetarian,
+
+		CarbonKg: dish.CarbonKg,
+
+		Seasons: dish.Seasons,
+
+		Portion:   dish.Portion,
+		Shareable: dish.Shareable,
+
+		CookingMethod: dish.CookingMethod,
+		Course:        dish.Course,
+	}
+}
+
 type restaurantDishDataItem struct {
 	Name  string `json:"name"`
 	Desc  string `json:"desc"`
 	Price int    `json:"price"`
 	Score int    `json:"score"`
+
+	Allergens []string `json:"allergens"` // nuts, dairy, gluten, shellfish, egg, fish
+
+	Ingredients []string `json:"ingredients,omitempty"` // 主要食材, 为空表示没有数据
+
+	Nutrition *restaurantNutritionItem `json:"nutrition,omitempty"` // 每份的营养成分, 为空表示没有数据
+
+	PrepMinutes int `json:"prep_minutes"` // 从下单到上桌的制作时间, 0 表示没有数据
+
+	SpiceLevel int  `json:"spice_level"` // 0 (不辣) - 5 (特辣)
+	Vegetarian bool `json:"vegetarian"`  // 不含肉和海鲜, 可以含蛋奶
+
+	CarbonKg float64 `json:"carbon_kg"` // 每份估算的碳排放, kg CO2e, 0 表示没有数据
+
+	Seasons []string `json:"seasons,omitempty"` // 应季的季节: spring, summer, autumn, winter, 为空表示常年供应
+
+	Portion   string `json:"portion,omitempty"` // 分量: small, medium, large, 为空表示没有数据
+	Shareable bool   `json:"shareable"`         // 适合几个人分着吃; 面、粉这类一人一份的为 false
+
+	CookingMethod string `json:"cooking_method,omitempty"` // 烹饪方式: braised, boiled, stir-fried, fried, steamed, roasted, grilled, cold, pickled, 为空表示没有数据
+	Course        string `json:"course,omitempty"`         // 在一餐中的位置: appetizer, main, dessert, 为空表示没有数据
+}
+
+type restaurantNutritionItem struct {
+	Calories int     `json:"calories"` // kcal
+	ProteinG float64 `json:"protein_g"`
+	CarbsG   float64 `json:"carbs_g"`
+	FatG     float64 `json:"fat_g"`
 }
 
 type restaurantDataItem struct {
@@ -105,15 +356,117 @@ type restaurantDataItem struct {
 	Place string `json:"place"`
 	Score int    `json:"score"` // 0 - 10
 
+	Cuisine string `json:"cuisine"` // 菜系
+
+	Delivery *restaurantDeliveryItem `json:"delivery,omitempty"` // 为空表示不提供外卖
+	Chef     *restaurantChefItem     `json:"chef,omitempty"`     // 主厨, 为空表示没有公开信息
+	Ambiance *restaurantAmbianceItem `json:"ambiance,omitempty"` // 氛围
+	Geo      *restaurantGeoItem      `json:"geo,omitempty"`      // 坐标, 为空表示没有公开的位置
+
+	Accessibility *restaurantAccessibilityItem `json:"accessibility,omitempty"` // 无障碍设施, 为空表示没有公开信息
+	Loyalty       *restaurantLoyaltyItem       `json:"loyalty,omitempty"`       // 会员积分计划, 为空表示没有
+	Social        *restaurantSocialItem        `json:"social,omitempty"`        // 社交媒体账号, 为空表示没有
+	Contact       *restaurantContactItem       `json:"contact,omitempty"`       // 联系方式, 为空表示没有公开
+
+	Certifications *restaurantCertificationsItem `json:"certifications,omitempty"` // 卫生等级和获奖, 为空表示没有公开信息
+
+	Reviews []restaurantReviewItem `json:"reviews,omitempty"` // 用户评价
+	Events  []restaurantEventItem  `json:"events,omitempty"`  // 每周固定的活动
+
+	ClosedOn []time.Weekday `json:"closed_on,omitempty"` // 每周固定的休息日
+
+	MaxTable int `json:"max_table,omitempty"` // 最大的桌子能坐几人, 为 0 时按 defaultMaxTable
+
+	WalkIns bool `json:"walk_ins,omitempty"` // 是否接待没有预订的客人, 为 false 表示只接受预订
+
 	Dishes []restaurantDishDataItem `json:"dishes"` // 餐厅中的菜
 }
 
+type restaurantReviewItem struct {
+	Rating  int    `json:"rating"` // 1 - 5 星
+	Comment string `json:"comment"`
+}
+
+type restaurantEventItem struct {
+	Name    string       `json:"name"`
+	Desc    string       `json:"desc"`
+	Weekday time.Weekday `json:"weekday"` // 每周的这一天举办
+	Time    string       `json:"time"`    // 开始时间, 15:04
+}
+
+type restaurantGeoItem struct {
+	Lat float64 `json:"lat"` // 纬度
+	Lng float64 `json:"lng"` // 经度
+}
+
+type restaurantAccessibilityItem struct {
+	WheelchairAccessible bool `json:"wheelchair_accessible"` // 轮椅可以进入并就座
+	BrailleMenu          bool `json:"braille_menu"`          // 提供盲文菜单
+	StepFreeEntry        bool `json:"step_free_entry"`       // 入口无台阶
+}
+
+type restaurantCertificationsItem struct {
+	HygieneGrade string                `json:"hygiene_grade"` // 卫生等级, A 最好, C 最差
+	Awards       []restaurantAwardItem `json:"awards"`
+}
+
+type restaurantAwardItem struct {
+	Name string `json:"name"`
+	Year int    `json:"year"`
+}
+
+// restaurantContactItem 中的 email 为空表示没有公开的邮箱.
+type restaurantContactItem struct {
+	Phone string `json:"phone"`
+	Email string `json:"email,omitempty"`
+}
+
+// restaurantSocialItem 中为空的平台表示餐厅没有开通.
+type restaurantSocialItem struct {
+	Instagram *restaurantSocialAccountItem `json:"instagram,omitempty"`
+	WeChat    *restaurantSocialAccountItem `json:"wechat,omitempty"`
+}
+
+type restaurantSocialAccountItem struct {
+	Handle    string `json:"handle"`
+	Followers int    `json:"followers"`
+}
+
+type restaurantLoyaltyItem struct {
+	ProgramName   string  `json:"program_name"`
+	PointsPerYuan float64 `json:"points_per_yuan"` // 每消费 1 元得到的积分
+}
+
+type restaurantDeliveryItem struct {
+	BaseFee       int     `json:"base_fee"`        // 起送费, 元
+	FeePerKm      int     `json:"fee_per_km"`      // 每公里配送费, 元
+	MaxDistanceKm float64 `json:"max_distance_km"` // 最大配送距离
+	PrepMinutes   int     `json:"prep_minutes"`    // 出餐时间
+	MinOrder      int     `json:"min_order"`       // 菜品合计的最低金额, 元, 0 表示不限
+}
+
+type restaurantChefItem struct {
+	Name              string `json:"name"`
+	Specialty         string `json:"specialty"`
+	YearsOfExperience int    `json:"years_of_experience"`
+}
+
+type restaurantAmbianceItem struct {
+	Tags       []string `json:"tags"`        // romantic, family-friendly, casual, lively, upscale, quiet
+	NoiseLevel int      `json:"noise_level"` // 1 (安静) - 5 (嘈杂)
+}
+
 type restaurantDatabase struct {
 	restaurantByID        map[string]restaurantDataItem   // id => restaurantDataItem
 	restaurantsByLocation map[string][]restaurantDataItem // location => []restaurantDataItem
+
+	err error // 加载数据时的错误, 不为空时所有查询都返回它
 }
 
 func (rd *restaurantDatabase) GetRestaurantsByLocation(ctx context.Context, location string, topn int) ([]restaurantDataItem, error) {
+	if rd.err != nil {
+		return nil, rd.err
+	}
 	for locationName, rests := range rd.restaurantsByLocation {
 		if strings.Contains(locationName, location) || strings.Contains(location, locationName) {
 
@@ -126,10 +479,73 @@ func (rd *restaurantDatabase) GetRestaurantsByLocation(ctx context.Context, loca
 		}
 	}
 
+	// 找不到 location 不算错误, 由 tool 统一返回 "no matching restaurants found"
+	return nil, nil
+}
+
+// GetRestaurantByID 根据 id 查询一家餐厅.
+func (rd *restaurantDatabase) GetRestaurantByID(ctx context.Context, restaurantID string) (restaurantDataItem, error) {
+	if rd.err != nil {
+		return restaurantDataItem{}, rd.err
+	}
+	rest, ok := rd.restaurantByID[restaurantID]
+	if !ok {
+		return restaurantDataItem{}, fmt.Errorf("restaurant %s not found", restaurantID)
+	}
+	return rest, nil
+}
+
+// GetRestaurants 返回一个 location 的全部餐厅, location 为空时返回所有餐厅.
+func (rd *restaurantDatabase) GetRestaurants(ctx context.Context, location string) ([]restaurantDataItem, error) {
+	if rd.err != nil {
+		return nil, rd.err
+	}
+	locations := make([]string, 0, len(rd.restaurantsByLocation))
+	for locationName := range rd.restaurantsByLocation {
+		locations = append(locations, locationName)
+	}
+	sort.Strings(locations)
+
+	if location == "" {
+		var res []restaurantDataItem
+		for _, locationName := range locations {
+			res = append(res, rd.restaurantsByLocation[locationName]...)
+		}
+		return res, nil
+	}
+
+	for _, locationName := range locations {
+		if strings.Contains(locationName, location) || strings.Contains(location, locationName) {
+			return rd.restaurantsByLocation[locationName], nil
+		}
+	}
+
 	return nil, fmt.Errorf("location %s not found", location)
 }
 
+// findDish 在菜品列表中按名字查找, 优先完全匹配, 其次忽略大小写的包含匹配.
+func findDish(dishes []restaurantDishDataItem, name string) (restaurantDishDataItem, bool) {
+	for _, dish := range dishes {
+		if dish.Name == name {
+			return dish, true
+		}
+	}
+	lower := strings.ToLower(strings.TrimSpace(name))
+	if lower == "" {
+		return restaurantDishDataItem{}, false
+	}
+	for _, dish := range dishes {
+		if strings.Contains(strings.ToLower(dish.Name), lower) {
+			return dish, true
+		}
+	}
+	return restaurantDishDataItem{}, false
+}
+
 func (rd *restaurantDatabase) GetDishesByRestaurant(ctx context.Context, restaurantID string, topn int) ([]restaurantDishDataItem, error) {
+	if rd.err != nil {
+		return nil, rd.err
+	}
 	rest, ok := rd.restaurantByID[restaurantID]
 	if !ok {
 		return nil, fmt.Errorf("restaurant %s not found", restaurantID)
@@ -143,179 +559,3 @@ func (rd *restaurantDatabase) GetDishesByRestaurant(ctx context.Context, restaur
 
 	return res, nil
 }
-
-func getData() map[string][]restaurantDataItem {
-	return map[string][]restaurantDataItem{
-		"北京": {
-			{
-				ID:    "1001",
-				Name:  "云边小馆",
-				Place: "北京",
-				Desc:  "这个是云边小馆, 在北京, 口味多种多样",
-				Score: 3,
-				Dishes: []restaurantDishDataItem{
-					{
-						Name:  "红烧肉",
-						Desc:  "一块红烧肉",
-						Price: 20,
-						Score: 8,
-					},
-					{
-						Name:  "清泉牛肉",
-						Desc:  "很多的水煮牛肉",
-						Price: 50,
-						Score: 8,
-					},
-					{
-						Name:  "清炒小南瓜",
-						Desc:  "炒的糊糊的南瓜",
-						Price: 5,
-						Score: 5,
-					},
-					{
-						Name:  "韩式辣白菜",
-						Desc:  "这可是开过光的辣白菜，好吃得很",
-						Price: 20,
-						Score: 9,
-					},
-					{
-						Name:  "酸辣土豆丝",
-						Desc:  "酸酸辣辣的土豆丝",
-						Price: 10,
-						Score: 9,
-					},
-					{
-						Name:  "酸辣粉",
-						Desc:  "酸酸辣辣的粉",
-						Price: 5,
-					},
-				},
-			},
-			{
-				ID:    "1002",
-				Name:  "聚福轩食府",
-				Place: "北京",
-				Desc:  "北京的聚福轩食府, 很多档口, 等你来探索",
-				Score: 5,
-				Dishes: []restaurantDishDataItem{
-					{
-						Name:  "红烧排骨",
-						Desc:  "一块一块的排骨",
-						Price: 43,
-						Score: 7,
-					},
-					{
-						Name:  "大刀回锅肉",
-						Desc:  "经典的回锅肉, 肉很大",
-						Price: 40,
-						Score: 8,
-					},
-					{
-						Name:  "火辣辣的吻",
-						Desc:  "凉拌猪嘴，口味辣而不腻",
-						Price: 60,
-						Score: 9,
-					},
-					{
-						Name:  "辣椒拌皮蛋",
-						Desc:  "擂椒皮蛋，下饭的神器",
-						Price: 15,
-						Score: 8,
-					},
-				},
-			},
-			{
-				ID:    "1003",
-				Name:  "花影食舍",
-				Place: "上海",
-				Desc:  "非常豪华的花影食舍, 好吃不贵",
-				Score: 10,
-				Dishes: []restaurantDishDataItem{
-					{
-						Name:  "超级红烧肉",
-						Desc:  "非常红润的一块红烧肉",
-						Price: 30,
-						Score: 9,
-					},
-					{
-						Name:  "超级北京烤肉",
-						Desc:  "卷好了的烤鸭，配上酱汁",
-						Price: 60,
-						Score: 9,
-					},
-					{
-						Name:  "超级大白菜",
-						Desc:  "就是炒的水水的大白菜",
-						Price: 8,
-						Score: 8,
-					},
-				},
-			},
-		},
-		"上海": {
-			{
-				ID:    "2001",
-				Name:  "鸿宾雅膳楼",
-				Place: "上海",
-				Desc:  "这个是鸿宾雅膳楼, 在上海, 口味多种多样",
-				Score: 3,
-				Dishes: []restaurantDishDataItem{
-					{
-						Name:  "糖醋西红柿",
-						Desc:  "酸酸甜甜就是一个西红柿",
-						Price: 80,
-						Score: 5,
-					},
-					{
-						Name:  "糖渍🐟",
-						Desc:  "加了挺多糖的鱼，和醋鱼齐名",
-						Price: 99,
-						Score: 6,
-					},
-				},
-			},
-			{
-				ID:    "2002",
-				Name:  "饭醉团伙根据地",
-				Desc:  "专注糖醋口味，你值得拥有",
-				Place: "上海",
-				Score: 5,
-				Dishes: []restaurantDishDataItem{
-					{
-						Name:  "糖醋西瓜瓤",
-						Desc:  "糖醋味，嘎嘣脆",
-						Price: 69,
-						Score: 7,
-					},
-					{
-						Name:  "糖醋大包子",
-						Desc:  "和天津狗不理齐名",
-						Price: 99,
-						Score: 4,
-					},
-				},
-			},
-			{
-				ID:    "2010",
-				Name:  "好吃到跺 jiojio 餐馆",
-				Desc:  "这个是好吃到跺 jiojio 餐馆, 藏在一个你找不到的位置, 只等待有缘人来探索, 口味以川菜为主, 辣椒、花椒 大把大把放.",
-				Place: "它在它不在的地方",
-				Score: 10,
-				Dishes: []restaurantDishDataItem{
-					{
-						Name:  "无敌香辣虾🦞",
-						Desc:  "香香香香香香香香香香",
-						Price: 199,
-						Score: 9,
-					},
-					{
-						Name:  "超级大火锅🍲",
-						Desc:  "有很多辣椒和醪糟的火锅，可以煮东西，比如苹果🍌",
-						Price: 198,
-						Score: 9,
-					},
-				},
-			},
-		},
-	}
-}
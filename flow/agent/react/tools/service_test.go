@@ -0,0 +1,107 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tools
+
+import (
+	"context"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadDatabase(t *testing.T) {
+	ctx := context.Background()
+	assert.NoError(t, DataError())
+
+	// 内嵌的数据
+	svc, err := newFakeService()
+	assert.NoError(t, err)
+	rests, err := svc.repo.GetRestaurantsByLocation(ctx, "北京", 10)
+	assert.NoError(t, err)
+	assert.Len(t, rests, 3)
+	dishes, err := svc.repo.GetDishesByRestaurant(ctx, "1001", 2)
+	assert.NoError(t, err)
+	assert.Len(t, dishes, 2)
+
+	// 按评分从高到低, topn 取的是评分最高的
+	assert.Equal(t, "1003", rests[0].ID)
+	assert.Equal(t, []int{9, 9}, []int{dishes[0].Score, dishes[1].Score})
+	out, err := (&ToolQueryRestaurants{backService: svc}).InvokableRun(ctx, `{"location": "北京", "topn": 1}`)
+	assert.NoError(t, err)
+	assert.Contains(t, out, `"id":"1003"`)
+
+	fsys := fstest.MapFS{
+		"ok.json":         {Data: []byte(`{"杭州": [{"id": "9001", "name": "楼外楼", "score": 8, "dishes": [{"name": "西湖醋鱼", "price": 88}]}]}`)},
+		"unsorted.json":   {Data: []byte(`{"杭州": [{"id": "9001", "score": 3}, {"id": "9002", "score": 8}, {"id": "9003", "score": 3, "dishes": [{"name": "a", "score": 5}, {"name": "b", "score": 7}, {"name": "c", "score": 5}]}]}`)},
+		"malformed.json":  {Data: []byte(`{"杭州": [{"id": "9001"`)},
+		"typo.json":       {Data: []byte(`{"杭州": [{"id": "9001", "scroe": 8}]}`)},
+		"empty.json":      {Data: []byte(`{}`)},
+		"no_id.json":      {Data: []byte(`{"杭州": [{"name": "楼外楼"}]}`)},
+		"duplicate.json":  {Data: []byte(`{"杭州": [{"id": "9001"}], "苏州": [{"id": "9001"}]}`)},
+		"wrong_type.json": {Data: []byte(`{"杭州": {"id": "9001"}}`)},
+	}
+	db, err := loadDatabase(fsys, "ok.json")
+	assert.NoError(t, err)
+	rest, err := db.GetRestaurantByID(ctx, "9001")
+	assert.NoError(t, err)
+	assert.Equal(t, "西湖醋鱼", rest.Dishes[0].Name)
+
+	// 同分的保持文件中的顺序
+	db, err = loadDatabase(fsys, "unsorted.json")
+	assert.NoError(t, err)
+	var order []string
+	for _, rest := range db.restaurantsByLocation["杭州"] {
+		order = append(order, rest.ID)
+	}
+	assert.Equal(t, []string{"9002", "9001", "9003"}, order)
+	var names []string
+	for _, dish := range db.restaurantByID["9003"].Dishes {
+		names = append(names, dish.Name)
+	}
+	assert.Equal(t, []string{"b", "a", "c"}, names)
+
+	for path, want := range map[string]string{
+		"missing.json":    "failed to read restaurant data",
+		"malformed.json":  "invalid restaurant data malformed.json",
+		"typo.json":       `unknown field "scroe"`,
+		"empty.json":      "no restaurants",
+		"no_id.json":      "restaurant #1 in 杭州 has no id",
+		"duplicate.json":  "duplicate restaurant id 9001",
+		"wrong_type.json": "invalid restaurant data wrong_type.json",
+	} {
+		_, err := loadDatabase(fsys, path)
+		assert.ErrorContains(t, err, want, path)
+	}
+}
+
+func TestDataErrorSurfacesInTools(t *testing.T) {
+	ctx := context.Background()
+	_, loadErr := loadDatabase(fstest.MapFS{}, dataFile)
+	svc := &fakeService{repo: &restaurantDatabase{err: loadErr}}
+
+	// 数据加载失败时查询返回加载的错误, 不会返回空结果
+	_, err := (&ToolQueryRestaurants{backService: svc}).InvokableRun(ctx, `{"location": "北京"}`)
+	assert.ErrorIs(t, err, loadErr)
+	_, err = (&ToolQueryDishes{backService: svc}).InvokableRun(ctx, `{"restaurant_id": "1001"}`)
+	assert.ErrorIs(t, err, loadErr)
+
+	// 经过 safeTool 后作为 content 交给模型
+	out, err := NewSafeTool(&ToolQueryDishes{backService: svc}).InvokableRun(ctx, `{"restaurant_id": "1001"}`)
+	assert.NoError(t, err)
+	assert.Contains(t, out, "failed to read restaurant data")
+}
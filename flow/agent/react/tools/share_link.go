@@ -0,0 +1,140 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tools
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"strings"
+
+	"github.com/cloudwego/eino/components/tool"
+	"github.com/cloudwego/eino/schema"
+)
+
+func GetShareLinkTool() tool.InvokableTool {
+	return safeTool{
+		InvokableTool: NewCancellableTool(&ToolCreateShareLink{
+			backService: restService,
+		}),
+	}
+}
+
+// ToolCreateShareLink 是一个会产生持久状态的 tool: 把推荐的餐厅保存在后端, 返回一个可以分享的链接.
+type ToolCreateShareLink struct {
+	backService *fakeService // fake service
+}
+
+func (t *ToolCreateShareLink) Info(ctx context.Context) (*schema.ToolInfo, error) {
+	return &schema.ToolInfo{
+		Name: "create_share_link",
+		Desc: "Save a list of recommended restaurants and return a link the user can share with friends",
+		ParamsOneOf: schema.NewParamsOneOfByParams(map[string]*schema.ParameterInfo{
+			"restaurant_ids": {
+				Type:     "array",
+				Desc:     "The ids of the recommended restaurants",
+				ElemInfo: &schema.ParameterInfo{Type: "string"},
+				Required: true,
+			},
+		}),
+	}, nil
+}
+
+func (t *ToolCreateShareLink) InvokableRun(ctx context.Context, argumentsInJSON string, opts ...tool.Option) (string, error) {
+	// 解析参数
+	if err := checkRequired(ctx, t, argumentsInJSON); err != nil {
+		return "", err
+	}
+	p := &CreateShareLinkParam{}
+	err := json.Unmarshal([]byte(argumentsInJSON), p)
+	if err != nil {
+		return "", err
+	}
+
+	// 请求后端服务
+	link, err := t.backService.CreateShareLink(ctx, p)
+	if err != nil {
+		return "", err
+	}
+
+	// 序列化结果
+	res, err := marshalResult(link)
+	if err != nil {
+		return "", err
+	}
+
+	return string(res), nil
+}
+
+type CreateShareLinkParam struct {
+	RestaurantIDs []string `json:"restaurant_ids"`
+}
+
+type ShareLink struct {
+	URL           string   `json:"url"`
+	Token         string   `json:"token"`
+	RestaurantIDs []string `json:"restaurant_ids"`
+}
+
+const shareLinkBaseURL = "https://eino.example.com/share/"
+
+// CreateShareLink 保存一组餐厅, 相同的餐厅列表总是得到相同的 token, 重复分享不会产生新的记录.
+func (ft *fakeService) CreateShareLink(ctx context.Context, in *CreateShareLinkParam) (*ShareLink, error) {
+	if err := ft.simulateLatency(ctx); err != nil {
+		return nil, err
+	}
+
+	if len(in.RestaurantIDs) == 0 {
+		return nil, errors.New("restaurant_ids must not be empty")
+	}
+	for _, id := range in.RestaurantIDs {
+		if _, err := ft.repo.GetRestaurantByID(ctx, id); err != nil {
+			return nil, err
+		}
+	}
+
+	token := shareToken(in.RestaurantIDs)
+
+	ft.mu.Lock()
+	defer ft.mu.Unlock()
+	if ft.shareLinks == nil {
+		ft.shareLinks = make(map[string][]string)
+	}
+	ft.shareLinks[token] = append([]string(nil), in.RestaurantIDs...)
+
+	return &ShareLink{
+		URL:           shareLinkBaseURL + token,
+		Token:         token,
+		RestaurantIDs: in.RestaurantIDs,
+	}, nil
+}
+
+// SharedRestaurants 返回 token 对应的餐厅 id, 用于调试和测试.
+func (ft *fakeService) SharedRestaurants(token string) ([]string, bool) {
+	ft.mu.Lock()
+	defer ft.mu.Unlock()
+	ids, ok := ft.shareLinks[token]
+	return ids, ok
+}
+
+// shareToken 由餐厅 id 列表决定, 保证可复现.
+func shareToken(ids []string) string {
+	sum := sha1.Sum([]byte(strings.Join(ids, ",")))
+	return hex.EncodeToString(sum[:])[:12]
+}
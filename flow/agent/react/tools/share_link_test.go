@@ -0,0 +1,47 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tools
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateShareLink(t *testing.T) {
+	ctx := context.Background()
+	svc := &fakeService{repo: database}
+
+	link, err := svc.CreateShareLink(ctx, &CreateShareLinkParam{RestaurantIDs: []string{"1001", "1002"}})
+	assert.NoError(t, err)
+	assert.Equal(t, shareLinkBaseURL+link.Token, link.URL)
+	assert.Len(t, link.Token, 12)
+
+	again, err := svc.CreateShareLink(ctx, &CreateShareLinkParam{RestaurantIDs: []string{"1001", "1002"}})
+	assert.NoError(t, err)
+	assert.Equal(t, link.Token, again.Token)
+
+	ids, ok := svc.SharedRestaurants(link.Token)
+	assert.True(t, ok)
+	assert.Equal(t, []string{"1001", "1002"}, ids)
+
+	_, err = svc.CreateShareLink(ctx, &CreateShareLinkParam{})
+	assert.Error(t, err)
+	_, err = svc.CreateShareLink(ctx, &CreateShareLinkParam{RestaurantIDs: []string{"1001", "404"}})
+	assert.ErrorContains(t, err, "404")
+}
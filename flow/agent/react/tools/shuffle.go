@@ -0,0 +1,63 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tools
+
+import (
+	"math/rand"
+	"slices"
+	"time"
+)
+
+// SetShuffleSeed 固定打乱同分餐厅所用的随机种子, 相同的种子总是得到相同的顺序, 便于复现一次推荐.
+func SetShuffleSeed(seed int64) {
+	restService.randMu.Lock()
+	defer restService.randMu.Unlock()
+	restService.rand = rand.New(rand.NewSource(seed))
+}
+
+// shuffleTies 先按分数从高到低排序, 再打乱同分餐厅的相对顺序, 让同分的餐厅在多次运行之间轮流出现, 推荐更有新鲜感.
+// 同分的餐厅排在一起, 只在这一组内交换, 分数不同的餐厅顺序不变; 不修改 rests.
+func (ft *fakeService) shuffleTies(rests []restaurantDataItem) []restaurantDataItem {
+	res := slices.Clone(rests)
+	slices.SortStableFunc(res, byScoreDesc(func(r restaurantDataItem) int { return r.Score }))
+
+	var ties [][]restaurantDataItem // 排序后连续的同分餐厅, 从高分到低分
+	for start := 0; start < len(res); {
+		end := start + 1
+		for end < len(res) && res[end].Score == res[start].Score {
+			end++
+		}
+		if end-start > 1 {
+			ties = append(ties, res[start:end])
+		}
+		start = end
+	}
+	if len(ties) == 0 {
+		return res
+	}
+
+	// 按固定的顺序消耗随机数, 才能在固定种子下得到确定的结果
+	ft.randMu.Lock()
+	defer ft.randMu.Unlock()
+	if ft.rand == nil {
+		ft.rand = rand.New(rand.NewSource(time.Now().UnixNano()))
+	}
+	for _, group := range ties {
+		ft.rand.Shuffle(len(group), func(i, j int) { group[i], group[j] = group[j], group[i] })
+	}
+	return res
+}
@@ -0,0 +1,98 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tools
+
+import (
+	"context"
+	"math/rand"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestShuffleTies(t *testing.T) {
+	rests := []restaurantDataItem{
+		{ID: "a", Score: 9}, {ID: "b", Score: 5}, {ID: "c", Score: 9}, {ID: "d", Score: 7}, {ID: "e", Score: 9}, {ID: "f", Score: 5},
+	}
+	ids := func(rests []restaurantDataItem) string {
+		var res string
+		for _, rest := range rests {
+			res += rest.ID
+		}
+		return res
+	}
+	shuffle := func(seed int64) string {
+		svc := &fakeService{rand: rand.New(rand.NewSource(seed))}
+		return ids(svc.shuffleTies(rests))
+	}
+
+	// 相同的种子得到相同的顺序, 不同的种子让同分的餐厅轮流排在前面
+	seen := map[string]bool{}
+	for seed := int64(0); seed < 20; seed++ {
+		order := shuffle(seed)
+		assert.Equal(t, order, shuffle(seed))
+		seen[order] = true
+
+		// 先按分数从高到低排序, 同分的餐厅只在组内交换
+		assert.ElementsMatch(t, []byte("ace"), []byte(order[:3]))
+		assert.Equal(t, byte('d'), order[3])
+		assert.ElementsMatch(t, []byte("bf"), []byte(order[4:]))
+		svc := &fakeService{rand: rand.New(rand.NewSource(seed))}
+		assert.True(t, slices.IsSortedFunc(svc.shuffleTies(rests), byScoreDesc(func(r restaurantDataItem) int { return r.Score })), order)
+	}
+	assert.Greater(t, len(seen), 1)
+	assert.Equal(t, "abcdef", ids(rests), "the input is not modified")
+
+	// 没有同分的餐厅时只排序, 不消耗随机数
+	svc := &fakeService{}
+	assert.Equal(t, "db", ids(svc.shuffleTies([]restaurantDataItem{{ID: "b", Score: 5}, {ID: "d", Score: 7}})))
+	assert.Nil(t, svc.rand)
+}
+
+func TestQueryRestaurantsShufflesTies(t *testing.T) {
+	ctx := context.Background()
+	repo := &restaurantDatabase{
+		restaurantByID: map[string]restaurantDataItem{},
+		restaurantsByLocation: map[string][]restaurantDataItem{
+			"杭州": {{ID: "1", Score: 8}, {ID: "2", Score: 8}, {ID: "3", Score: 8}},
+		},
+	}
+
+	first := map[string]bool{}
+	for seed := int64(0); seed < 20; seed++ {
+		svc := &fakeService{repo: repo, rand: rand.New(rand.NewSource(seed))}
+		rests, err := svc.QueryRestaurants(ctx, &QueryRestaurantsParam{Location: "杭州", Topn: 1})
+		assert.NoError(t, err)
+		assert.Len(t, rests, 1)
+		first[rests[0].ID] = true
+	}
+	// 同分的餐厅都有机会排进 topn
+	assert.Len(t, first, 3)
+
+	// 分数更高的餐厅总是排在同分的餐厅前面, 不论它在数据中的位置
+	repo.restaurantsByLocation["苏州"] = []restaurantDataItem{{ID: "1", Score: 6}, {ID: "2", Score: 6}, {ID: "3", Score: 9}}
+	for seed := int64(0); seed < 20; seed++ {
+		svc := &fakeService{repo: repo, rand: rand.New(rand.NewSource(seed))}
+		rests, err := svc.QueryRestaurants(ctx, &QueryRestaurantsParam{Location: "苏州", Topn: 2})
+		assert.NoError(t, err)
+		if assert.Len(t, rests, 2) {
+			assert.Equal(t, "3", rests[0].ID)
+			assert.Equal(t, 6, rests[1].Score)
+		}
+	}
+}
@@ -0,0 +1,196 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"sort"
+
+	"github.com/cloudwego/eino/components/tool"
+	"github.com/cloudwego/eino/schema"
+)
+
+func GetSimilarRestaurantsTool() tool.InvokableTool {
+	return safeTool{
+		InvokableTool: NewCancellableTool(&ToolSimilarRestaurants{
+			backService: restService,
+		}),
+	}
+}
+
+// ToolSimilarRestaurants 是一个基于内容的推荐 tool: 按菜系、评分和氛围算出和给定餐厅最相似的其他餐厅.
+type ToolSimilarRestaurants struct {
+	backService *fakeService // fake service
+}
+
+func (t *ToolSimilarRestaurants) Info(ctx context.Context) (*schema.ToolInfo, error) {
+	return &schema.ToolInfo{
+		Name: "similar_restaurants",
+		Desc: "Find restaurants similar to a given one, with the same cuisine and a comparable score, most similar first",
+		ParamsOneOf: schema.NewParamsOneOfByParams(map[string]*schema.ParameterInfo{
+			"restaurant_id": {
+				Type:     "string",
+				Desc:     "The id of the restaurant to find similar ones for",
+				Required: true,
+			},
+			"topn": {
+				Type: "number",
+				Desc: "top n similar restaurants, default 3",
+			},
+		}),
+	}, nil
+}
+
+func (t *ToolSimilarRestaurants) InvokableRun(ctx context.Context, argumentsInJSON string, opts ...tool.Option) (string, error) {
+	// 解析参数
+	if err := checkRequired(ctx, t, argumentsInJSON); err != nil {
+		return "", err
+	}
+	p := &SimilarRestaurantsParam{}
+	err := json.Unmarshal([]byte(argumentsInJSON), p)
+	if err != nil {
+		return "", err
+	}
+
+	// 请求后端服务
+	similar, err := t.backService.SimilarRestaurants(ctx, p)
+	if err != nil {
+		return "", err
+	}
+	if len(similar) == 0 {
+		return emptyResult("similar restaurants"), nil
+	}
+
+	// 序列化结果
+	res, err := marshalResult(similar)
+	if err != nil {
+		return "", err
+	}
+
+	return string(res), nil
+}
+
+type SimilarRestaurantsParam struct {
+	RestaurantID string `json:"restaurant_id"`
+	Topn         int    `json:"topn"`
+}
+
+type SimilarRestaurant struct {
+	ID          string  `json:"id"`
+	Name        string  `json:"name"`
+	Place       string  `json:"place"`
+	Cuisine     string  `json:"cuisine,omitempty"`
+	Score       int     `json:"score"`
+	SameCuisine bool    `json:"same_cuisine"`
+	Similarity  float64 `json:"similarity"` // 0 - 1
+}
+
+// SimilarRestaurants 返回和 in.RestaurantID 最相似的 topn 家餐厅, 不包括它自己.
+func (ft *fakeService) SimilarRestaurants(ctx context.Context, in *SimilarRestaurantsParam) ([]SimilarRestaurant, error) {
+	if err := ft.simulateLatency(ctx); err != nil {
+		return nil, err
+	}
+
+	target, err := ft.repo.GetRestaurantByID(ctx, in.RestaurantID)
+	if err != nil {
+		return nil, err
+	}
+	rests, err := ft.repo.GetRestaurants(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+
+	topn := in.Topn
+	if topn <= 0 {
+		topn = 3
+	}
+	return rankSimilar(&target, rests, topn), nil
+}
+
+// rankSimilar 按 similarity 从高到低排序, 相同时按 id 排序, 保证结果稳定.
+// 完全没有共同点 (similarity 为 0) 的餐厅不返回.
+func rankSimilar(target *restaurantDataItem, rests []restaurantDataItem, topn int) []SimilarRestaurant {
+	res := make([]SimilarRestaurant, 0, len(rests))
+	for _, rest := range rests {
+		if rest.ID == target.ID {
+			continue
+		}
+		sim := similarity(target, &rest)
+		if sim <= 0 {
+			continue
+		}
+		res = append(res, SimilarRestaurant{
+			ID:          rest.ID,
+			Name:        rest.Name,
+			Place:       rest.Place,
+			Cuisine:     rest.Cuisine,
+			Score:       rest.Score,
+			SameCuisine: rest.Cuisine != "" && rest.Cuisine == target.Cuisine,
+			Similarity:  math.Round(sim*100) / 100,
+		})
+	}
+
+	sort.SliceStable(res, func(i, j int) bool {
+		if res[i].Similarity != res[j].Similarity {
+			return res[i].Similarity > res[j].Similarity
+		}
+		return res[i].ID < res[j].ID
+	})
+	if len(res) > topn {
+		res = res[:topn]
+	}
+	return res
+}
+
+// similarity 是菜系 (0.5)、评分接近程度 (0.3) 和氛围标签重合度 (0.2) 的加权和.
+// 评分相差 5 分及以上时评分部分为 0.
+func similarity(a, b *restaurantDataItem) float64 {
+	var sim float64
+	if a.Cuisine != "" && a.Cuisine == b.Cuisine {
+		sim += 0.5
+	}
+
+	diff := math.Abs(float64(a.Score - b.Score))
+	sim += 0.3 * math.Max(0, 1-diff/5)
+
+	if a.Ambiance != nil && b.Ambiance != nil {
+		sim += 0.2 * jaccard(a.Ambiance.Tags, b.Ambiance.Tags)
+	}
+	return sim
+}
+
+func jaccard(a, b []string) float64 {
+	set := map[string]bool{}
+	for _, s := range a {
+		set[s] = true
+	}
+	var inter int
+	union := len(set)
+	for _, s := range b {
+		if set[s] {
+			inter++
+		} else {
+			union++
+		}
+	}
+	if union == 0 {
+		return 0
+	}
+	return float64(inter) / float64(union)
+}
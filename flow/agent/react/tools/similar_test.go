@@ -0,0 +1,50 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tools
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSimilarRestaurants(t *testing.T) {
+	ctx := context.Background()
+
+	out, err := restService.SimilarRestaurants(ctx, &SimilarRestaurantsParam{RestaurantID: "2001", Topn: 10})
+	assert.NoError(t, err)
+	var ids []string
+	for _, r := range out {
+		ids = append(ids, r.ID)
+		assert.NotEqual(t, "2001", r.ID)
+	}
+	// 同为本帮菜的 2002 最相似, 2010 没有任何共同点, 不返回
+	assert.Equal(t, []string{"2002", "1001", "1002", "1003"}, ids)
+	assert.True(t, out[0].SameCuisine)
+	assert.Equal(t, 0.68, out[0].Similarity)
+
+	out, err = restService.SimilarRestaurants(ctx, &SimilarRestaurantsParam{RestaurantID: "2001", Topn: 2})
+	assert.NoError(t, err)
+	assert.Len(t, out, 2)
+
+	_, err = restService.SimilarRestaurants(ctx, &SimilarRestaurantsParam{RestaurantID: "404"})
+	assert.Error(t, err)
+
+	assert.Equal(t, 0.5, jaccard([]string{"a", "b"}, []string{"b"}))
+	assert.Equal(t, 0.0, jaccard(nil, nil))
+}
@@ -0,0 +1,49 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tools
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cloudwego/eino/components/tool"
+)
+
+// DefaultMaxArgumentBytes 是 tool 参数的默认大小上限, 正常的调用远小于这个值.
+const DefaultMaxArgumentBytes = 16 << 10
+
+// argSizeLimitTool 在反序列化之前检查参数大小, 防止失控的模型把几 MB 的参数打到后端.
+type argSizeLimitTool struct {
+	tool.InvokableTool
+	maxBytes int
+}
+
+// NewArgSizeLimitTool wraps t so that arguments larger than maxBytes are rejected.
+// maxBytes <= 0 means DefaultMaxArgumentBytes.
+func NewArgSizeLimitTool(t tool.InvokableTool, maxBytes int) tool.InvokableTool {
+	if maxBytes <= 0 {
+		maxBytes = DefaultMaxArgumentBytes
+	}
+	return &argSizeLimitTool{InvokableTool: t, maxBytes: maxBytes}
+}
+
+func (a *argSizeLimitTool) InvokableRun(ctx context.Context, argumentsInJSON string, opts ...tool.Option) (string, error) {
+	if len(argumentsInJSON) > a.maxBytes {
+		return refuse(ctx, fmt.Sprintf("arguments are %d bytes, exceeding the limit of %d bytes", len(argumentsInJSON), a.maxBytes)), nil
+	}
+	return a.InvokableTool.InvokableRun(ctx, argumentsInJSON, opts...)
+}
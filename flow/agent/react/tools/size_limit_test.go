@@ -0,0 +1,43 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tools
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestArgSizeLimitTool(t *testing.T) {
+	ctx := context.Background()
+	limited := NewArgSizeLimitTool(&ToolQueryDishes{backService: restService}, 64)
+
+	out, err := limited.InvokableRun(ctx, `{"restaurant_id": "1001", "topn": 1}`)
+	assert.NoError(t, err)
+	assert.NotContains(t, out, "arguments rejected")
+
+	state := &ToolExecutionState{Success: true}
+	oversized := `{"restaurant_id": "` + strings.Repeat("1", 100) + `"}`
+	out, err = limited.InvokableRun(SetToolState(ctx, state), oversized)
+	assert.NoError(t, err)
+	assert.Contains(t, out, "exceeding the limit of 64 bytes")
+	assert.False(t, state.Success)
+
+	assert.Equal(t, DefaultMaxArgumentBytes, NewArgSizeLimitTool(limited, 0).(*argSizeLimitTool).maxBytes)
+}
@@ -0,0 +1,125 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tools
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/cloudwego/eino/components/tool"
+	"github.com/cloudwego/eino/schema"
+)
+
+func GetSocialMediaTool() tool.InvokableTool {
+	return safeTool{
+		InvokableTool: NewCancellableTool(&ToolSocialMedia{
+			backService: restService,
+		}),
+	}
+}
+
+// ToolSocialMedia 返回餐厅在各个社交媒体平台的账号和粉丝数.
+type ToolSocialMedia struct {
+	backService *fakeService // fake service
+}
+
+func (t *ToolSocialMedia) Info(ctx context.Context) (*schema.ToolInfo, error) {
+	return &schema.ToolInfo{
+		Name: "query_social_media",
+		Desc: "Query the Instagram and WeChat accounts of a restaurant and their follower counts. Platforms the restaurant is not on are omitted",
+		ParamsOneOf: schema.NewParamsOneOfByParams(map[string]*schema.ParameterInfo{
+			"restaurant_id": {
+				Type:     "string",
+				Desc:     "The id of one restaurant",
+				Required: true,
+			},
+		}),
+	}, nil
+}
+
+func (t *ToolSocialMedia) InvokableRun(ctx context.Context, argumentsInJSON string, opts ...tool.Option) (string, error) {
+	// 解析参数
+	if err := checkRequired(ctx, t, argumentsInJSON); err != nil {
+		return "", err
+	}
+	p := &SocialMediaParam{}
+	err := json.Unmarshal([]byte(argumentsInJSON), p)
+	if err != nil {
+		return "", err
+	}
+
+	// 请求后端服务
+	social, err := t.backService.QuerySocialMedia(ctx, p)
+	if err != nil {
+		return "", err
+	}
+
+	// 序列化结果
+	res, err := marshalResult(social)
+	if err != nil {
+		return "", err
+	}
+
+	return string(res), nil
+}
+
+type SocialMediaParam struct {
+	RestaurantID string `json:"restaurant_id"`
+}
+
+type SocialMedia struct {
+	RestaurantID string `json:"restaurant_id"`
+	Name         string `json:"name"`
+	// 餐厅没有开通的平台不输出, 而不是输出空的账号
+	Instagram *SocialAccount `json:"instagram,omitempty"`
+	WeChat    *SocialAccount `json:"wechat,omitempty"`
+	Message   string         `json:"message,omitempty"`
+}
+
+type SocialAccount struct {
+	Handle    string `json:"handle"`
+	Followers int    `json:"followers"`
+}
+
+// QuerySocialMedia 查询一家餐厅的社交媒体账号.
+func (ft *fakeService) QuerySocialMedia(ctx context.Context, in *SocialMediaParam) (*SocialMedia, error) {
+	if err := ft.simulateLatency(ctx); err != nil {
+		return nil, err
+	}
+
+	rest, err := ft.repo.GetRestaurantByID(ctx, in.RestaurantID)
+	if err != nil {
+		return nil, err
+	}
+
+	social := &SocialMedia{RestaurantID: rest.ID, Name: rest.Name}
+	if rest.Social != nil {
+		social.Instagram = toSocialAccount(rest.Social.Instagram)
+		social.WeChat = toSocialAccount(rest.Social.WeChat)
+	}
+	if social.Instagram == nil && social.WeChat == nil {
+		social.Message = "this restaurant is not on any social media platform"
+	}
+	return social, nil
+}
+
+func toSocialAccount(item *restaurantSocialAccountItem) *SocialAccount {
+	if item == nil {
+		return nil
+	}
+	return &SocialAccount{Handle: item.Handle, Followers: item.Followers}
+}
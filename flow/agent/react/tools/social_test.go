@@ -0,0 +1,50 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tools
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cloudwego/eino-examples/flow/agent/react/internal/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQuerySocialMedia(t *testing.T) {
+	ctx := context.Background()
+
+	social, err := restService.QuerySocialMedia(ctx, &SocialMediaParam{RestaurantID: "1002"})
+	assert.NoError(t, err)
+	assert.Equal(t, &SocialAccount{Handle: "@jufuxuan_bj", Followers: 15800}, social.Instagram)
+	assert.Equal(t, &SocialAccount{Handle: "聚福轩食府", Followers: 42000}, social.WeChat)
+	assert.Empty(t, social.Message)
+
+	// 没有开通的平台不出现在结果中
+	out, err := GetSocialMediaTool().InvokableRun(ctx, `{"restaurant_id": "1003"}`)
+	assert.NoError(t, err)
+	testutil.AssertJSONField(t, out, "instagram", SocialAccount{Handle: "@huaying_kitchen", Followers: 8600})
+	assert.NotContains(t, out, "wechat")
+
+	social, err = restService.QuerySocialMedia(ctx, &SocialMediaParam{RestaurantID: "2010"})
+	assert.NoError(t, err)
+	assert.Nil(t, social.Instagram)
+	assert.Nil(t, social.WeChat)
+	assert.NotEmpty(t, social.Message)
+
+	_, err = restService.QuerySocialMedia(ctx, &SocialMediaParam{RestaurantID: "404"})
+	assert.Error(t, err)
+}
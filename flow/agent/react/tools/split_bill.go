@@ -0,0 +1,137 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+
+	"github.com/cloudwego/eino/components/tool"
+	"github.com/cloudwego/eino/schema"
+)
+
+func GetSplitBillTool() tool.InvokableTool {
+	return safeTool{
+		InvokableTool: &ToolSplitBill{},
+	}
+}
+
+// ToolSplitBill 和 ToolComputeBill 一样是纯计算的 tool: 把总价平均分给每个人. 除不尽时多出来的几分钱
+// 明确分给前几个人, 每个人的金额加起来正好等于总价, 模型自己算很容易因为四舍五入多收或少收一分钱.
+type ToolSplitBill struct{}
+
+func (t *ToolSplitBill) Info(ctx context.Context) (*schema.ToolInfo, error) {
+	return &schema.ToolInfo{
+		Name: "split_bill",
+		Desc: "Split the total of a bill evenly among the party. When it does not divide evenly, the remaining cents are assigned " +
+			"to the first people, so the shares always add up exactly to the total",
+		ParamsOneOf: schema.NewParamsOneOfByParams(map[string]*schema.ParameterInfo{
+			"total": {
+				Type:     schema.Number,
+				Desc:     "The total amount of the bill in yuan, e.g. the total returned by compute_bill",
+				Required: true,
+			},
+			"party_size": {
+				Type:     schema.Integer,
+				Desc:     fmt.Sprintf("How many people share the bill, from 1 to %d", maxPartySize),
+				Required: true,
+			},
+		}),
+	}, nil
+}
+
+func (t *ToolSplitBill) InvokableRun(ctx context.Context, argumentsInJSON string, opts ...tool.Option) (string, error) {
+	// 解析参数
+	if err := checkRequired(ctx, t, argumentsInJSON); err != nil {
+		return "", err
+	}
+	p := &SplitBillParam{}
+	err := json.Unmarshal([]byte(argumentsInJSON), p)
+	if err != nil {
+		return "", err
+	}
+
+	// 计算账单
+	split, err := splitBill(p)
+	if err != nil {
+		return "", err
+	}
+
+	// 序列化结果
+	res, err := marshalResult(split)
+	if err != nil {
+		return "", err
+	}
+
+	return string(res), nil
+}
+
+type SplitBillParam struct {
+	Total     float64 `json:"total"`
+	PartySize int     `json:"party_size"`
+}
+
+type BillSplit struct {
+	Total     float64     `json:"total"`
+	PartySize int         `json:"party_size"`
+	Shares    []BillShare `json:"shares"`
+	// RemainderCents 是平分之后多出来的分, 分给了前 RemainderCents 个人, 每人多付 0.01
+	RemainderCents int    `json:"remainder_cents"`
+	Message        string `json:"message,omitempty"`
+}
+
+type BillShare struct {
+	Person int     `json:"person"` // 从 1 开始
+	Amount float64 `json:"amount"`
+}
+
+// maxPartySize 限制人数, 让 shares 不超过 DefaultMaxResults, 不会被截断.
+const maxPartySize = DefaultMaxResults
+
+// splitBill 按分做整数运算: 每人先分到 total / n 分, 余下的 total % n 分给前几个人各一分.
+func splitBill(in *SplitBillParam) (*BillSplit, error) {
+	if in.PartySize <= 0 || in.PartySize > maxPartySize {
+		return nil, fmt.Errorf("party_size must be between 1 and %d, got %d", maxPartySize, in.PartySize)
+	}
+	if in.Total < 0 {
+		return nil, fmt.Errorf("total must be non-negative, got %v", in.Total)
+	}
+
+	totalCents := int64(math.Round(in.Total * 100))
+	n := int64(in.PartySize)
+	base, remainder := totalCents/n, totalCents%n
+
+	split := &BillSplit{
+		Total:          float64(totalCents) / 100,
+		PartySize:      in.PartySize,
+		Shares:         make([]BillShare, 0, in.PartySize),
+		RemainderCents: int(remainder),
+	}
+	for i := int64(0); i < n; i++ {
+		cents := base
+		if i < remainder {
+			cents++
+		}
+		split.Shares = append(split.Shares, BillShare{Person: int(i) + 1, Amount: float64(cents) / 100})
+	}
+	if remainder > 0 {
+		split.Message = fmt.Sprintf("the total does not divide evenly, the first %d people pay 0.01 more", remainder)
+	}
+	return split, nil
+}
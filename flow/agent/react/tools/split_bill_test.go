@@ -0,0 +1,64 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tools
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSplitBill(t *testing.T) {
+	split, err := splitBill(&SplitBillParam{Total: 100, PartySize: 3})
+	assert.NoError(t, err)
+	assert.Equal(t, []BillShare{{Person: 1, Amount: 33.34}, {Person: 2, Amount: 33.33}, {Person: 3, Amount: 33.33}}, split.Shares)
+	assert.Equal(t, 1, split.RemainderCents)
+	assert.Contains(t, split.Message, "first 1 people")
+
+	split, err = splitBill(&SplitBillParam{Total: 90, PartySize: 3})
+	assert.NoError(t, err)
+	assert.Equal(t, 0, split.RemainderCents)
+	assert.Empty(t, split.Message)
+
+	_, err = splitBill(&SplitBillParam{Total: 100, PartySize: 0})
+	assert.ErrorContains(t, err, "party_size")
+	_, err = splitBill(&SplitBillParam{Total: 100, PartySize: maxPartySize + 1})
+	assert.ErrorContains(t, err, "party_size")
+	_, err = splitBill(&SplitBillParam{Total: -1, PartySize: 2})
+	assert.ErrorContains(t, err, "total")
+}
+
+// 各种总价和人数下, 每个人的金额按分加起来都正好等于总价, 最多只差一分.
+func TestSplitBillNoRoundingLoss(t *testing.T) {
+	for _, total := range []float64{0, 0.01, 0.1, 1, 95.8, 99.99, 100, 1234.56, 9999.99} {
+		for n := 1; n <= maxPartySize; n++ {
+			split, err := splitBill(&SplitBillParam{Total: total, PartySize: n})
+			assert.NoError(t, err)
+			assert.Len(t, split.Shares, n)
+
+			var sum, lo, hi int64 = 0, math.MaxInt64, 0
+			for _, s := range split.Shares {
+				cents := int64(math.Round(s.Amount * 100))
+				sum += cents
+				lo, hi = min(lo, cents), max(hi, cents)
+			}
+			assert.Equal(t, int64(math.Round(total*100)), sum, "total %v split %d ways", total, n)
+			assert.LessOrEqual(t, hi-lo, int64(1))
+		}
+	}
+}
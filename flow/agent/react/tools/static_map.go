@@ -0,0 +1,134 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+
+	"github.com/cloudwego/eino/components/tool"
+	"github.com/cloudwego/eino/schema"
+)
+
+func GetStaticMapTool() tool.InvokableTool {
+	return safeTool{
+		InvokableTool: NewCancellableTool(&ToolStaticMap{
+			backService: restService,
+		}),
+	}
+}
+
+// ToolStaticMap 根据餐厅的坐标拼出一张静态地图图片的地址, 并附上描述位置的 alt text, 方便在支持图片的界面中展示.
+type ToolStaticMap struct {
+	backService *fakeService // fake service
+}
+
+func (t *ToolStaticMap) Info(ctx context.Context) (*schema.ToolInfo, error) {
+	return &schema.ToolInfo{
+		Name: "get_static_map",
+		Desc: "Get a static map image url showing where a restaurant is, with alt text describing the location",
+		ParamsOneOf: schema.NewParamsOneOfByParams(map[string]*schema.ParameterInfo{
+			"restaurant_id": {
+				Type:     "string",
+				Desc:     "The id of one restaurant",
+				Required: true,
+			},
+		}),
+	}, nil
+}
+
+func (t *ToolStaticMap) InvokableRun(ctx context.Context, argumentsInJSON string, opts ...tool.Option) (string, error) {
+	// 解析参数
+	if err := checkRequired(ctx, t, argumentsInJSON); err != nil {
+		return "", err
+	}
+	p := &StaticMapParam{}
+	err := json.Unmarshal([]byte(argumentsInJSON), p)
+	if err != nil {
+		return "", err
+	}
+
+	// 请求后端服务
+	m, err := t.backService.GetStaticMap(ctx, p)
+	if err != nil {
+		return "", err
+	}
+
+	// 序列化结果
+	res, err := marshalResult(m)
+	if err != nil {
+		return "", err
+	}
+
+	return string(res), nil
+}
+
+type StaticMapParam struct {
+	RestaurantID string `json:"restaurant_id"`
+}
+
+type StaticMap struct {
+	RestaurantID string  `json:"restaurant_id"`
+	Lat          float64 `json:"lat"`
+	Lng          float64 `json:"lng"`
+	ImageURL     string  `json:"image_url"`
+	AltText      string  `json:"alt_text"`
+}
+
+const (
+	staticMapBaseURL = "https://maps.eino.example.com/staticmap"
+	staticMapZoom    = 16
+	staticMapSize    = "600x400"
+)
+
+// GetStaticMap 返回餐厅所在位置的静态地图, 没有坐标的餐厅返回错误.
+func (ft *fakeService) GetStaticMap(ctx context.Context, in *StaticMapParam) (*StaticMap, error) {
+	if err := ft.simulateLatency(ctx); err != nil {
+		return nil, err
+	}
+
+	rest, err := ft.repo.GetRestaurantByID(ctx, in.RestaurantID)
+	if err != nil {
+		return nil, err
+	}
+	if rest.Geo == nil {
+		return nil, fmt.Errorf("restaurant %s has no published location", in.RestaurantID)
+	}
+
+	return &StaticMap{
+		RestaurantID: rest.ID,
+		Lat:          rest.Geo.Lat,
+		Lng:          rest.Geo.Lng,
+		ImageURL:     staticMapURL(rest.Geo.Lat, rest.Geo.Lng),
+		AltText:      fmt.Sprintf("%s 位于%s (北纬 %.4f, 东经 %.4f) 的地图, 红色标记为餐厅位置", rest.Name, rest.Place, rest.Geo.Lat, rest.Geo.Lng),
+	}, nil
+}
+
+// staticMapURL 只由坐标决定, 参数按固定顺序拼接, 相同的坐标总是得到相同的地址.
+func staticMapURL(lat, lng float64) string {
+	center := strconv.FormatFloat(lat, 'f', 6, 64) + "," + strconv.FormatFloat(lng, 'f', 6, 64)
+	// url.Values.Encode 按 key 排序
+	q := url.Values{}
+	q.Set("center", center)
+	q.Set("markers", center)
+	q.Set("zoom", strconv.Itoa(staticMapZoom))
+	q.Set("size", staticMapSize)
+	return staticMapBaseURL + "?" + q.Encode()
+}
@@ -0,0 +1,42 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tools
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetStaticMap(t *testing.T) {
+	ctx := context.Background()
+
+	m, err := restService.GetStaticMap(ctx, &StaticMapParam{RestaurantID: "1001"})
+	assert.NoError(t, err)
+	assert.Equal(t, 39.9087, m.Lat)
+	assert.Equal(t, "https://maps.eino.example.com/staticmap?center=39.908700%2C116.397500&markers=39.908700%2C116.397500&size=600x400&zoom=16", m.ImageURL)
+	assert.Contains(t, m.AltText, "云边小馆")
+	assert.Contains(t, m.AltText, "北京")
+
+	again, err := restService.GetStaticMap(ctx, &StaticMapParam{RestaurantID: "1001"})
+	assert.NoError(t, err)
+	assert.Equal(t, m.ImageURL, again.ImageURL)
+
+	_, err = restService.GetStaticMap(ctx, &StaticMapParam{RestaurantID: "2010"})
+	assert.ErrorContains(t, err, "no published location")
+}
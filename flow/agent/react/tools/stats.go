@@ -0,0 +1,124 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"strconv"
+
+	"github.com/cloudwego/eino/components/tool"
+	"github.com/cloudwego/eino/schema"
+)
+
+func GetRestaurantStatsTool() tool.InvokableTool {
+	return safeTool{
+		InvokableTool: NewCancellableTool(&ToolRestaurantStats{
+			backService: restService,
+		}),
+	}
+}
+
+// ToolRestaurantStats 对后端数据做聚合, 而不是逐行查询.
+type ToolRestaurantStats struct {
+	backService *fakeService // fake service
+}
+
+func (t *ToolRestaurantStats) Info(ctx context.Context) (*schema.ToolInfo, error) {
+	return &schema.ToolInfo{
+		Name: "restaurant_stats",
+		Desc: "Aggregate statistics of restaurants in a location (or all locations): count, average score, score distribution and most common cuisine",
+		ParamsOneOf: schema.NewParamsOneOfByParams(map[string]*schema.ParameterInfo{
+			"location": {
+				Type: "string",
+				Desc: "The location to aggregate, leave empty for all locations",
+			},
+		}),
+	}, nil
+}
+
+func (t *ToolRestaurantStats) InvokableRun(ctx context.Context, argumentsInJSON string, opts ...tool.Option) (string, error) {
+	// 解析参数
+	if err := checkRequired(ctx, t, argumentsInJSON); err != nil {
+		return "", err
+	}
+	p := &RestaurantStatsParam{}
+	err := json.Unmarshal([]byte(argumentsInJSON), p)
+	if err != nil {
+		return "", err
+	}
+
+	// 请求后端服务
+	stats, err := t.backService.RestaurantStats(ctx, p)
+	if err != nil {
+		return "", err
+	}
+
+	// 序列化结果
+	res, err := marshalResult(stats)
+	if err != nil {
+		return "", err
+	}
+
+	return string(res), nil
+}
+
+type RestaurantStatsParam struct {
+	Location string `json:"location"`
+}
+
+// RestaurantStats 的 key 都尽量表意清晰, 方便模型直接总结, 比如 "北京的餐厅平均 6 分".
+type RestaurantStats struct {
+	Location           string         `json:"location,omitempty"`
+	RestaurantCount    int            `json:"restaurant_count"`
+	AverageScore       float64        `json:"average_score"`      // 0 - 10, 保留一位小数
+	ScoreDistribution  map[string]int `json:"score_distribution"` // score => 餐厅数量
+	MostCommonCuisine  string         `json:"most_common_cuisine,omitempty"`
+	CuisineRestaurants int            `json:"most_common_cuisine_restaurant_count,omitempty"`
+}
+
+// computeRestaurantStats 计算一组餐厅的聚合信息, 菜系数量相同时取字典序较小的, 保证结果稳定.
+func computeRestaurantStats(rests []restaurantDataItem) *RestaurantStats {
+	stats := &RestaurantStats{
+		RestaurantCount:   len(rests),
+		ScoreDistribution: make(map[string]int),
+	}
+	if len(rests) == 0 {
+		return stats
+	}
+
+	total := 0
+	cuisines := make(map[string]int)
+	for _, rest := range rests {
+		total += rest.Score
+		stats.ScoreDistribution[strconv.Itoa(rest.Score)]++
+		if rest.Cuisine != "" {
+			cuisines[rest.Cuisine]++
+		}
+	}
+	stats.AverageScore = math.Round(float64(total)/float64(len(rests))*10) / 10
+
+	for cuisine, count := range cuisines {
+		if count > stats.CuisineRestaurants || (count == stats.CuisineRestaurants && cuisine < stats.MostCommonCuisine) {
+			stats.MostCommonCuisine = cuisine
+			stats.CuisineRestaurants = count
+		}
+	}
+
+	return stats
+}
@@ -0,0 +1,37 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tools
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestComputeRestaurantStats(t *testing.T) {
+	stats := computeRestaurantStats(database.restaurantsByLocation["上海"])
+	assert.Equal(t, 3, stats.RestaurantCount)
+	assert.Equal(t, 6.0, stats.AverageScore)
+	assert.Equal(t, map[string]int{"3": 1, "5": 1, "10": 1}, stats.ScoreDistribution)
+	assert.Equal(t, "本帮菜", stats.MostCommonCuisine)
+	assert.Equal(t, 2, stats.CuisineRestaurants)
+
+	empty := computeRestaurantStats(nil)
+	assert.Equal(t, 0, empty.RestaurantCount)
+	assert.Equal(t, 0.0, empty.AverageScore)
+	assert.Empty(t, empty.MostCommonCuisine)
+}
@@ -0,0 +1,204 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/cloudwego/eino/components/tool"
+	"github.com/cloudwego/eino/schema"
+)
+
+func GetTableETATool() tool.InvokableTool {
+	return safeTool{
+		InvokableTool: NewCancellableTool(&ToolTableETA{
+			backService: restService,
+		}),
+	}
+}
+
+// ToolTableETA 估算现在去餐厅要等多久才有一张坐得下 party_size 人的桌子. 等待时间由两部分组成:
+// 一桌客人吃完要多久 (tableTurnMinutes, 和 estimate_meal_duration 同一套计算) 和现在有多忙 (busyness, 和 query_busy_hours 同一条曲线).
+type ToolTableETA struct {
+	backService *fakeService // fake service
+}
+
+func (t *ToolTableETA) Info(ctx context.Context) (*schema.ToolInfo, error) {
+	return &schema.ToolInfo{
+		Name: "query_table_eta",
+		Desc: "Estimate how many minutes a party has to wait for a table at a restaurant right now, with a confidence level. " +
+			"Suggests splitting the party when it is larger than the biggest table",
+		ParamsOneOf: schema.NewParamsOneOfByParams(map[string]*schema.ParameterInfo{
+			"restaurant_id": {
+				Type:     "string",
+				Desc:     "The id of one restaurant",
+				Required: true,
+			},
+			"party_size": {
+				Type:     "number",
+				Desc:     fmt.Sprintf("How many people, from 1 to %d", maxPartySize),
+				Required: true,
+			},
+		}),
+	}, nil
+}
+
+func (t *ToolTableETA) InvokableRun(ctx context.Context, argumentsInJSON string, opts ...tool.Option) (string, error) {
+	// 解析参数
+	if err := checkRequired(ctx, t, argumentsInJSON); err != nil {
+		return "", err
+	}
+	p := &TableETAParam{}
+	err := json.Unmarshal([]byte(argumentsInJSON), p)
+	if err != nil {
+		return "", err
+	}
+
+	// 请求后端服务
+	eta, err := t.backService.TableETA(ctx, p)
+	if err != nil {
+		return "", err
+	}
+
+	// 序列化结果
+	res, err := marshalResult(eta)
+	if err != nil {
+		return "", err
+	}
+
+	return string(res), nil
+}
+
+type TableETAParam struct {
+	RestaurantID string `json:"restaurant_id"`
+	PartySize    int    `json:"party_size"`
+}
+
+// TableETA 在不营业的时段 open 为 false, 没有等待时间.
+type TableETA struct {
+	RestaurantID string `json:"restaurant_id"`
+	PartySize    int    `json:"party_size"`
+	Open         bool   `json:"open"`
+	Busyness     int    `json:"busyness,omitempty"` // 现在的繁忙程度, 0 - 100
+	ETAMinutes   int    `json:"eta_minutes"`
+	SeatedBy     string `json:"seated_by,omitempty"`  // RFC3339
+	Confidence   string `json:"confidence,omitempty"` // high, medium 或 low
+	// Tables 只在人数超过最大的桌子时出现, 是建议分开坐的每桌人数
+	Tables  []int  `json:"tables,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+const (
+	// defaultMaxTable 是没有 max_table 数据的餐厅最大的桌子能坐的人数
+	defaultMaxTable = 8
+	// typicalTableDishes 是估算翻台时间时一桌点的菜数
+	typicalTableDishes = 3
+	// freeTableBusyness 以下餐厅还有空桌, 不用等
+	freeTableBusyness = 40
+	// largePartySize 以上要等大桌, 大桌少, 等待时间乘以 largePartyFactor
+	largePartySize   = 4
+	largePartyFactor = 1.5
+)
+
+// TableETA 按当前时刻的 busyness 估算等位时间, 同一时刻总是得到相同的结果.
+func (ft *fakeService) TableETA(ctx context.Context, in *TableETAParam) (*TableETA, error) {
+	if err := ft.simulateLatency(ctx); err != nil {
+		return nil, err
+	}
+
+	if in.PartySize <= 0 || in.PartySize > maxPartySize {
+		return nil, fmt.Errorf("party_size must be between 1 and %d, got %d", maxPartySize, in.PartySize)
+	}
+	rest, err := ft.repo.GetRestaurantByID(ctx, in.RestaurantID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := ft.now()
+	out := &TableETA{RestaurantID: rest.ID, PartySize: in.PartySize}
+	if now.Hour() < busyFirstHour || now.Hour() > busyLastHour {
+		out.Message = fmt.Sprintf("the restaurant is closed, tables are available from %02d:00 to %02d:59", busyFirstHour, busyLastHour)
+		return out, nil
+	}
+
+	maxTable := rest.MaxTable
+	if maxTable <= 0 {
+		maxTable = defaultMaxTable
+	}
+	busy := busyness(rest.ID, now.Weekday(), now.Hour())
+	out.Open = true
+	out.Busyness = busy
+	out.Confidence = etaConfidence(busy)
+
+	tables := splitParty(in.PartySize, maxTable)
+	// 每多一桌要再等半桌的时间, 分开坐时估计也更不准
+	eta := float64(tableWaitMinutes(tableTurnMinutes(rest.Dishes), busy, tables[0])) * (1 + 0.5*float64(len(tables)-1))
+	out.ETAMinutes = int(math.Round(eta))
+	if len(tables) > 1 {
+		out.Tables = tables
+		out.Confidence = "low"
+		out.Message = fmt.Sprintf("the party of %d is larger than the biggest table (%d seats), consider sitting at %d tables", in.PartySize, maxTable, len(tables))
+	}
+	out.SeatedBy = now.Add(time.Duration(out.ETAMinutes) * time.Minute).Format(time.RFC3339)
+	return out, nil
+}
+
+// tableTurnMinutes 是一桌客人从点菜到吃完的时间, 按菜单的平均制作时间和 typicalTableDishes 道菜的用餐时间估算.
+func tableTurnMinutes(dishes []restaurantDishDataItem) int {
+	return averagePrepMinutes(dishes) + eatMinutesPerDish*typicalTableDishes
+}
+
+// tableWaitMinutes 在繁忙程度低于 freeTableBusyness 时为 0, 之后线性增加, 满座 (100) 时要等一整个翻台时间.
+func tableWaitMinutes(turnMinutes, busy, partySize int) int {
+	if busy <= freeTableBusyness {
+		return 0
+	}
+	wait := float64(turnMinutes) * float64(busy-freeTableBusyness) / float64(100-freeTableBusyness)
+	if partySize > largePartySize {
+		wait *= largePartyFactor
+	}
+	return int(math.Round(wait))
+}
+
+// etaConfidence 越忙翻台时间越不确定: 有空桌时是 high, 高峰时是 low.
+func etaConfidence(busy int) string {
+	switch {
+	case busy <= freeTableBusyness:
+		return "high"
+	case busy <= 75:
+		return "medium"
+	default:
+		return "low"
+	}
+}
+
+// splitParty 把 size 人尽量平均地分到最少的桌子上, 每桌不超过 maxTable 人, 人多的桌在前.
+func splitParty(size, maxTable int) []int {
+	n := (size + maxTable - 1) / maxTable
+	tables := make([]int, n)
+	for i := range tables {
+		tables[i] = size / n
+		if i < size%n {
+			tables[i]++
+		}
+	}
+	return tables
+}
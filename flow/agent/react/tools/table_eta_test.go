@@ -0,0 +1,92 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tools
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTableETA(t *testing.T) {
+	ctx := context.Background()
+	loc := time.FixedZone("CST", 8*3600)
+	at := func(hour int) *fakeService {
+		// 2024-06-01 是周六
+		return &fakeService{repo: restService.repo, clock: FixedClock{T: time.Date(2024, 6, 1, hour, 0, 0, 0, loc)}}
+	}
+
+	// 同一时刻总是得到相同的结果
+	peak, err := at(19).TableETA(ctx, &TableETAParam{RestaurantID: "1001", PartySize: 2})
+	assert.NoError(t, err)
+	again, err := at(19).TableETA(ctx, &TableETAParam{RestaurantID: "1001", PartySize: 2})
+	assert.NoError(t, err)
+	assert.Equal(t, peak, again)
+	assert.True(t, peak.Open)
+	assert.Equal(t, busyness("1001", time.Saturday, 19), peak.Busyness)
+	assert.Equal(t, "low", peak.Confidence)
+	assert.Empty(t, peak.Tables)
+
+	seatedBy, err := time.Parse(time.RFC3339, peak.SeatedBy)
+	assert.NoError(t, err)
+	assert.Equal(t, time.Duration(peak.ETAMinutes)*time.Minute, seatedBy.Sub(time.Date(2024, 6, 1, 19, 0, 0, 0, loc)))
+
+	// 下午有空桌, 不用等
+	afternoon, err := at(15).TableETA(ctx, &TableETAParam{RestaurantID: "1001", PartySize: 2})
+	assert.NoError(t, err)
+	assert.Zero(t, afternoon.ETAMinutes)
+	assert.Equal(t, "high", afternoon.Confidence)
+
+	// 大桌要等得更久
+	large, err := at(19).TableETA(ctx, &TableETAParam{RestaurantID: "1001", PartySize: 6})
+	assert.NoError(t, err)
+	assert.Greater(t, large.ETAMinutes, peak.ETAMinutes)
+
+	// 2010 最大的桌子坐 4 人
+	split, err := at(19).TableETA(ctx, &TableETAParam{RestaurantID: "2010", PartySize: 10})
+	assert.NoError(t, err)
+	assert.Equal(t, []int{4, 3, 3}, split.Tables)
+	assert.Equal(t, "low", split.Confidence)
+	assert.Contains(t, split.Message, "3 tables")
+
+	closed, err := at(8).TableETA(ctx, &TableETAParam{RestaurantID: "1001", PartySize: 2})
+	assert.NoError(t, err)
+	assert.False(t, closed.Open)
+	assert.Zero(t, closed.ETAMinutes)
+	assert.Contains(t, closed.Message, "closed")
+
+	_, err = at(19).TableETA(ctx, &TableETAParam{RestaurantID: "1001", PartySize: maxPartySize + 1})
+	assert.ErrorContains(t, err, "party_size")
+	_, err = at(19).TableETA(ctx, &TableETAParam{RestaurantID: "404", PartySize: 2})
+	assert.Error(t, err)
+}
+
+func TestSplitParty(t *testing.T) {
+	assert.Equal(t, []int{5}, splitParty(5, 8))
+	assert.Equal(t, []int{8}, splitParty(8, 8))
+	assert.Equal(t, []int{5, 4}, splitParty(9, 8))
+	assert.Equal(t, []int{7, 7, 6}, splitParty(20, 8))
+}
+
+func TestTableWaitMinutes(t *testing.T) {
+	assert.Equal(t, 0, tableWaitMinutes(60, freeTableBusyness, 2))
+	assert.Equal(t, 30, tableWaitMinutes(60, 70, 2))
+	assert.Equal(t, 60, tableWaitMinutes(60, 100, 2))
+	assert.Equal(t, 45, tableWaitMinutes(60, 70, largePartySize+1))
+}
@@ -0,0 +1,138 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/cloudwego/eino/components/tool"
+	"github.com/cloudwego/eino/schema"
+)
+
+func GetTakeoutQueueTool() tool.InvokableTool {
+	return safeTool{
+		InvokableTool: NewCancellableTool(&ToolTakeoutQueue{
+			backService: restService,
+		}),
+	}
+}
+
+// ToolTakeoutQueue 估算现在下一个外带订单要排第几、多久能取. 排队的长度来自 query_busy_hours 同一条繁忙程度曲线,
+// "现在" 来自 fake 后端的 clock, 测试中注入 FixedClock 就能得到确定的结果.
+type ToolTakeoutQueue struct {
+	backService *fakeService // fake service
+}
+
+func (t *ToolTakeoutQueue) Info(ctx context.Context) (*schema.ToolInfo, error) {
+	return &schema.ToolInfo{
+		Name: "query_takeout_queue",
+		Desc: "Estimate the queue position of a takeout order placed now at a restaurant, how many minutes it takes and when it will be ready for pickup",
+		ParamsOneOf: schema.NewParamsOneOfByParams(map[string]*schema.ParameterInfo{
+			"restaurant_id": {
+				Type:     "string",
+				Desc:     "The id of one restaurant",
+				Required: true,
+			},
+		}),
+	}, nil
+}
+
+func (t *ToolTakeoutQueue) InvokableRun(ctx context.Context, argumentsInJSON string, opts ...tool.Option) (string, error) {
+	// 解析参数
+	if err := checkRequired(ctx, t, argumentsInJSON); err != nil {
+		return "", err
+	}
+	p := &TakeoutQueueParam{}
+	err := json.Unmarshal([]byte(argumentsInJSON), p)
+	if err != nil {
+		return "", err
+	}
+
+	// 请求后端服务
+	queue, err := t.backService.TakeoutQueue(ctx, p)
+	if err != nil {
+		return "", err
+	}
+
+	// 序列化结果
+	res, err := marshalResult(queue)
+	if err != nil {
+		return "", err
+	}
+
+	return string(res), nil
+}
+
+type TakeoutQueueParam struct {
+	RestaurantID string `json:"restaurant_id"`
+}
+
+// TakeoutQueue 在不营业的时段 open 为 false, 没有排队和取餐时间.
+type TakeoutQueue struct {
+	RestaurantID string `json:"restaurant_id"`
+	Open         bool   `json:"open"`
+	// Position 是新订单排在第几个, 1 表示前面没有其他订单
+	Position   int    `json:"position,omitempty"`
+	ETAMinutes int    `json:"eta_minutes,omitempty"`
+	ReadyBy    string `json:"ready_by,omitempty"` // RFC3339
+	Message    string `json:"message,omitempty"`
+}
+
+// takeoutMinutesPerOrder 是前面每多一个订单多等的时间, 厨房可以同时做几单, 所以比一道菜的制作时间短.
+const takeoutMinutesPerOrder = 4
+
+// TakeoutQueue 按 busyness 估算当前小时的排队长度: 繁忙程度每 10% 前面多一个订单.
+// 制作时间是菜单上菜品的平均制作时间, 没有数据的菜按 defaultPrepMinutes 计算.
+func (ft *fakeService) TakeoutQueue(ctx context.Context, in *TakeoutQueueParam) (*TakeoutQueue, error) {
+	if err := ft.simulateLatency(ctx); err != nil {
+		return nil, err
+	}
+
+	rest, err := ft.repo.GetRestaurantByID(ctx, in.RestaurantID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := ft.now()
+	out := &TakeoutQueue{RestaurantID: rest.ID}
+	if now.Hour() < busyFirstHour || now.Hour() > busyLastHour {
+		out.Message = fmt.Sprintf("the restaurant is closed, takeout orders are accepted from %02d:00 to %02d:59", busyFirstHour, busyLastHour)
+		return out, nil
+	}
+
+	ahead := int(math.Round(float64(busyness(rest.ID, now.Weekday(), now.Hour())) / 10))
+	out.Open = true
+	out.Position = ahead + 1
+	out.ETAMinutes = averagePrepMinutes(rest.Dishes) + ahead*takeoutMinutesPerOrder
+	out.ReadyBy = now.Add(time.Duration(out.ETAMinutes) * time.Minute).Format(time.RFC3339)
+	return out, nil
+}
+
+func averagePrepMinutes(dishes []restaurantDishDataItem) int {
+	if len(dishes) == 0 {
+		return defaultPrepMinutes
+	}
+	total := 0
+	for _, prep := range mealDuration(dishes).Dishes {
+		total += prep.PrepMinutes
+	}
+	return int(math.Round(float64(total) / float64(len(dishes))))
+}
@@ -0,0 +1,66 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tools
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTakeoutQueue(t *testing.T) {
+	ctx := context.Background()
+	loc := time.FixedZone("CST", 8*3600)
+	at := func(hour int) *fakeService {
+		// 2024-06-01 是周六
+		return &fakeService{repo: restService.repo, clock: FixedClock{T: time.Date(2024, 6, 1, hour, 0, 0, 0, loc)}}
+	}
+
+	// 同一时刻总是得到相同的结果
+	first, err := at(19).TakeoutQueue(ctx, &TakeoutQueueParam{RestaurantID: "1001"})
+	assert.NoError(t, err)
+	again, err := at(19).TakeoutQueue(ctx, &TakeoutQueueParam{RestaurantID: "1001"})
+	assert.NoError(t, err)
+	assert.Equal(t, first, again)
+
+	// 晚高峰比下午排得更长
+	afternoon, err := at(15).TakeoutQueue(ctx, &TakeoutQueueParam{RestaurantID: "1001"})
+	assert.NoError(t, err)
+	assert.True(t, first.Open)
+	assert.Greater(t, first.Position, afternoon.Position)
+	assert.Greater(t, first.ETAMinutes, afternoon.ETAMinutes)
+
+	readyBy, err := time.Parse(time.RFC3339, first.ReadyBy)
+	assert.NoError(t, err)
+	assert.Equal(t, time.Duration(first.ETAMinutes)*time.Minute, readyBy.Sub(time.Date(2024, 6, 1, 19, 0, 0, 0, loc)))
+
+	closed, err := at(8).TakeoutQueue(ctx, &TakeoutQueueParam{RestaurantID: "1001"})
+	assert.NoError(t, err)
+	assert.False(t, closed.Open)
+	assert.Zero(t, closed.Position)
+	assert.Contains(t, closed.Message, "closed")
+
+	_, err = at(19).TakeoutQueue(ctx, &TakeoutQueueParam{RestaurantID: "404"})
+	assert.Error(t, err)
+}
+
+func TestAveragePrepMinutes(t *testing.T) {
+	assert.Equal(t, defaultPrepMinutes, averagePrepMinutes(nil))
+	assert.Equal(t, 13, averagePrepMinutes([]restaurantDishDataItem{{PrepMinutes: 10}, {}}))
+}
@@ -0,0 +1,257 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/cloudwego/eino/components/tool"
+	"github.com/cloudwego/eino/schema"
+)
+
+func GetTastingMenuTool() tool.InvokableTool {
+	return safeTool{
+		InvokableTool: NewCancellableTool(&ToolTastingMenu{
+			backService: restService,
+		}),
+	}
+}
+
+// ToolTastingMenu 在一家餐厅按 appetizer → main → dessert 各选一道菜, 组成一份同时满足预算和总制作时间的套餐.
+// 两个约束同时满足不了时放宽其中一个, 并在结果中说明放宽了哪个.
+type ToolTastingMenu struct {
+	backService *fakeService // fake service
+}
+
+func (t *ToolTastingMenu) Info(ctx context.Context) (*schema.ToolInfo, error) {
+	return &schema.ToolInfo{
+		Name: "plan_tasting_menu",
+		Desc: "Plan a multi-course tasting menu (one appetizer, one main and one dessert) of a restaurant within a budget and a time limit, " +
+			"picking the best rated dishes. Returns the courses in order, the total cost and the total preparation time. " +
+			"If both limits cannot be met, one of them is relaxed and the result says which",
+		ParamsOneOf: schema.NewParamsOneOfByParams(map[string]*schema.ParameterInfo{
+			"restaurant_id": {
+				Type:     "string",
+				Desc:     "The id of one restaurant",
+				Required: true,
+			},
+			"budget": {
+				Type:     "number",
+				Desc:     "The budget for the whole menu in CNY",
+				Required: true,
+			},
+			"max_minutes": {
+				Type:     "integer",
+				Desc:     "The longest total preparation time of all courses in minutes",
+				Required: true,
+			},
+		}),
+	}, nil
+}
+
+func (t *ToolTastingMenu) InvokableRun(ctx context.Context, argumentsInJSON string, opts ...tool.Option) (string, error) {
+	// 解析参数
+	if err := checkRequired(ctx, t, argumentsInJSON); err != nil {
+		return "", err
+	}
+	p := &TastingMenuParam{}
+	err := json.Unmarshal([]byte(argumentsInJSON), p)
+	if err != nil {
+		return "", err
+	}
+
+	// 请求后端服务
+	menu, err := t.backService.TastingMenu(ctx, p)
+	if err != nil {
+		return "", err
+	}
+
+	// 序列化结果
+	res, err := marshalResult(menu)
+	if err != nil {
+		return "", err
+	}
+
+	return string(res), nil
+}
+
+type TastingMenuParam struct {
+	RestaurantID string  `json:"restaurant_id"`
+	Budget       float64 `json:"budget"`
+	MaxMinutes   int     `json:"max_minutes"`
+}
+
+// TastingMenu 的金额单位是元, 时间单位是分钟. relaxed 为空表示两个约束都满足了, 否则是 time、budget 或 budget and time.
+// 菜单上没有某一道的菜时跳过这一道, 列在 missing_courses 中.
+type TastingMenu struct {
+	RestaurantID   string          `json:"restaurant_id"`
+	Budget         float64         `json:"budget"`
+	MaxMinutes     int             `json:"max_minutes"`
+	Courses        []TastingCourse `json:"courses"`
+	TotalCost      int             `json:"total_cost"`
+	TotalMinutes   int             `json:"total_minutes"`
+	Relaxed        string          `json:"relaxed,omitempty"`
+	MissingCourses []string        `json:"missing_courses,omitempty"`
+	Message        string          `json:"message"`
+}
+
+type TastingCourse struct {
+	Course      string `json:"course"`
+	Name        string `json:"name"`
+	Price       int    `json:"price"`
+	Score       int    `json:"score"`
+	PrepMinutes int    `json:"prep_minutes"`
+	// Estimated 为 true 表示餐厅没有这道菜的制作时间, 使用了 defaultPrepMinutes
+	Estimated bool `json:"estimated,omitempty"`
+}
+
+// tastingCourses 是套餐上菜的顺序.
+var tastingCourses = []string{"appetizer", "main", "dessert"}
+
+// tastingCandidate 是每一道各选一道菜的一种组合.
+type tastingCandidate struct {
+	courses       []TastingCourse
+	cost, minutes int
+	score         int
+}
+
+// TastingMenu 枚举每一道各选一道菜的所有组合, 一道接一道上菜, 所以总时间是各道制作时间之和.
+// 两个约束都满足时选总评分最高的组合, 同分时选更便宜、再更快的. 否则依次尝试:
+// 只满足预算时放宽时间, 选最快的; 只满足时间时放宽预算, 选最便宜的; 都满足不了时选最便宜、再最快的.
+func (ft *fakeService) TastingMenu(ctx context.Context, in *TastingMenuParam) (*TastingMenu, error) {
+	if err := ft.simulateLatency(ctx); err != nil {
+		return nil, err
+	}
+
+	if in.Budget <= 0 {
+		return nil, fmt.Errorf("budget must be positive, got %v", in.Budget)
+	}
+	if in.MaxMinutes <= 0 {
+		return nil, fmt.Errorf("max_minutes must be positive, got %d", in.MaxMinutes)
+	}
+	rest, err := ft.repo.GetRestaurantByID(ctx, in.RestaurantID)
+	if err != nil {
+		return nil, err
+	}
+
+	out := &TastingMenu{RestaurantID: rest.ID, Budget: in.Budget, MaxMinutes: in.MaxMinutes, Courses: []TastingCourse{}}
+	var byCourse [][]TastingCourse
+	for _, course := range tastingCourses {
+		var options []TastingCourse
+		for _, dish := range rest.Dishes {
+			if dish.Course == course {
+				options = append(options, tastingCourse(dish))
+			}
+		}
+		if len(options) == 0 {
+			out.MissingCourses = append(out.MissingCourses, course)
+			continue
+		}
+		byCourse = append(byCourse, options)
+	}
+	if len(byCourse) == 0 {
+		out.Message = "the restaurant has no course data to plan a tasting menu"
+		return out, nil
+	}
+
+	candidates := tastingCandidates(byCourse)
+	withinBudget := func(c tastingCandidate) bool { return float64(c.cost) <= in.Budget }
+	withinTime := func(c tastingCandidate) bool { return c.minutes <= in.MaxMinutes }
+	best, ok := bestTasting(candidates, func(c tastingCandidate) bool { return withinBudget(c) && withinTime(c) }, func(a, b tastingCandidate) bool {
+		return a.score > b.score || (a.score == b.score && (a.cost < b.cost || (a.cost == b.cost && a.minutes < b.minutes)))
+	})
+	if !ok {
+		best, ok = bestTasting(candidates, withinBudget, func(a, b tastingCandidate) bool {
+			return a.minutes < b.minutes || (a.minutes == b.minutes && a.score > b.score)
+		})
+		out.Relaxed = "time"
+	}
+	if !ok {
+		best, ok = bestTasting(candidates, withinTime, func(a, b tastingCandidate) bool {
+			return a.cost < b.cost || (a.cost == b.cost && a.score > b.score)
+		})
+		out.Relaxed = "budget"
+	}
+	if !ok {
+		best, _ = bestTasting(candidates, func(tastingCandidate) bool { return true }, func(a, b tastingCandidate) bool {
+			return a.cost < b.cost || (a.cost == b.cost && a.minutes < b.minutes)
+		})
+		out.Relaxed = "budget and time"
+	}
+
+	out.Courses, out.TotalCost, out.TotalMinutes = best.courses, best.cost, best.minutes
+	names := make([]string, 0, len(best.courses))
+	for _, c := range best.courses {
+		names = append(names, c.Name)
+	}
+	out.Message = fmt.Sprintf("%s for %d yuan in about %d minutes", strings.Join(names, " → "), out.TotalCost, out.TotalMinutes)
+	switch out.Relaxed {
+	case "time":
+		out.Message += fmt.Sprintf("; no menu is ready within %d minutes, so the time limit was relaxed", in.MaxMinutes)
+	case "budget":
+		out.Message += fmt.Sprintf("; no menu fits the budget of %v yuan, so the budget was relaxed", in.Budget)
+	case "budget and time":
+		out.Message += fmt.Sprintf("; no menu fits the budget of %v yuan or is ready within %d minutes, this is the cheapest one", in.Budget, in.MaxMinutes)
+	}
+	if len(out.MissingCourses) > 0 {
+		out.Message += fmt.Sprintf("; the menu has no %s", strings.Join(out.MissingCourses, " or "))
+	}
+	return out, nil
+}
+
+func tastingCourse(dish restaurantDishDataItem) TastingCourse {
+	c := TastingCourse{Course: dish.Course, Name: dish.Name, Price: dish.Price, Score: dish.Score, PrepMinutes: dish.PrepMinutes}
+	if c.PrepMinutes <= 0 {
+		c.PrepMinutes, c.Estimated = defaultPrepMinutes, true
+	}
+	return c
+}
+
+// tastingCandidates 返回 byCourse 的笛卡尔积, 每个组合中的菜按上菜顺序排列.
+func tastingCandidates(byCourse [][]TastingCourse) []tastingCandidate {
+	res := []tastingCandidate{{}}
+	for _, options := range byCourse {
+		next := make([]tastingCandidate, 0, len(res)*len(options))
+		for _, c := range res {
+			for _, o := range options {
+				next = append(next, tastingCandidate{
+					courses: append(append([]TastingCourse{}, c.courses...), o),
+					cost:    c.cost + o.Price,
+					minutes: c.minutes + o.PrepMinutes,
+					score:   c.score + o.Score,
+				})
+			}
+		}
+		res = next
+	}
+	return res
+}
+
+// bestTasting 返回满足 allowed 的组合中按 better 最好的一个, 同样好时保持菜单上的顺序.
+func bestTasting(candidates []tastingCandidate, allowed func(tastingCandidate) bool, better func(a, b tastingCandidate) bool) (tastingCandidate, bool) {
+	var best tastingCandidate
+	found := false
+	for _, c := range candidates {
+		if allowed(c) && (!found || better(c, best)) {
+			best, found = c, true
+		}
+	}
+	return best, found
+}
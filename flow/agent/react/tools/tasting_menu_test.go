@@ -0,0 +1,103 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tools
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTastingMenu(t *testing.T) {
+	ctx := context.Background()
+	svc := &fakeService{repo: database}
+
+	names := func(menu *TastingMenu) []string {
+		var res []string
+		for _, c := range menu.Courses {
+			res = append(res, c.Course+":"+c.Name)
+		}
+		return res
+	}
+
+	// 两个约束都宽松: 评分最高的组合有 4 个 (9+8), 选最便宜的; 1001 没有甜点
+	menu, err := svc.TastingMenu(ctx, &TastingMenuParam{RestaurantID: "1001", Budget: 100, MaxMinutes: 60})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"appetizer:酸辣土豆丝", "main:红烧肉"}, names(menu))
+	assert.Equal(t, 30, menu.TotalCost)
+	assert.Equal(t, 43, menu.TotalMinutes)
+	assert.Empty(t, menu.Relaxed)
+	assert.Equal(t, []string{"dessert"}, menu.MissingCourses)
+	assert.Equal(t, "酸辣土豆丝 → 红烧肉 for 30 yuan in about 43 minutes; the menu has no dessert", menu.Message)
+
+	// 时间很紧时只能选做得快的菜
+	menu, err = svc.TastingMenu(ctx, &TastingMenuParam{RestaurantID: "1001", Budget: 100, MaxMinutes: 15})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"appetizer:韩式辣白菜", "main:清炒小南瓜"}, names(menu))
+	assert.Equal(t, 13, menu.TotalMinutes)
+	assert.Empty(t, menu.Relaxed)
+
+	// 没有 10 分钟内能做好的组合, 放宽时间, 选最快的
+	menu, err = svc.TastingMenu(ctx, &TastingMenuParam{RestaurantID: "1001", Budget: 100, MaxMinutes: 10})
+	assert.NoError(t, err)
+	assert.Equal(t, "time", menu.Relaxed)
+	assert.Equal(t, 13, menu.TotalMinutes)
+	assert.Contains(t, menu.Message, "time limit was relaxed")
+
+	// 没有 10 元以内的组合, 放宽预算, 选最便宜的, 同样便宜时选评分高的
+	menu, err = svc.TastingMenu(ctx, &TastingMenuParam{RestaurantID: "1001", Budget: 10, MaxMinutes: 60})
+	assert.NoError(t, err)
+	assert.Equal(t, "budget", menu.Relaxed)
+	assert.Equal(t, []string{"appetizer:酸辣土豆丝", "main:清炒小南瓜"}, names(menu))
+	assert.Equal(t, 15, menu.TotalCost)
+	assert.Contains(t, menu.Message, "budget was relaxed")
+
+	menu, err = svc.TastingMenu(ctx, &TastingMenuParam{RestaurantID: "1001", Budget: 10, MaxMinutes: 10})
+	assert.NoError(t, err)
+	assert.Equal(t, "budget and time", menu.Relaxed)
+	assert.Equal(t, 15, menu.TotalCost)
+	assert.Equal(t, 16, menu.TotalMinutes)
+
+	// 三道都有时按 appetizer → main → dessert 的顺序; 没有制作时间的菜按 defaultPrepMinutes 估算
+	repo := &restaurantDatabase{restaurantByID: map[string]restaurantDataItem{
+		"9000": {ID: "9000", Dishes: []restaurantDishDataItem{
+			{Name: "甜点", Course: "dessert", Price: 10, Score: 5, PrepMinutes: 5},
+			{Name: "主菜", Course: "main", Price: 30, Score: 8},
+			{Name: "前菜", Course: "appetizer", Price: 10, Score: 6, PrepMinutes: 5},
+		}},
+		"9001": {ID: "9001", Dishes: []restaurantDishDataItem{{Name: "神秘菜"}}},
+	}}
+	menu, err = (&fakeService{repo: repo}).TastingMenu(ctx, &TastingMenuParam{RestaurantID: "9000", Budget: 50, MaxMinutes: 30})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"appetizer:前菜", "main:主菜", "dessert:甜点"}, names(menu))
+	assert.True(t, menu.Courses[1].Estimated)
+	assert.Equal(t, 10+defaultPrepMinutes, menu.TotalMinutes)
+	assert.Empty(t, menu.MissingCourses)
+
+	menu, err = (&fakeService{repo: repo}).TastingMenu(ctx, &TastingMenuParam{RestaurantID: "9001", Budget: 50, MaxMinutes: 30})
+	assert.NoError(t, err)
+	assert.Empty(t, menu.Courses)
+	assert.Contains(t, menu.Message, "no course data")
+
+	_, err = svc.TastingMenu(ctx, &TastingMenuParam{RestaurantID: "1001", Budget: 0, MaxMinutes: 30})
+	assert.ErrorContains(t, err, "budget")
+	_, err = svc.TastingMenu(ctx, &TastingMenuParam{RestaurantID: "1001", Budget: 50, MaxMinutes: -1})
+	assert.ErrorContains(t, err, "max_minutes")
+	_, err = svc.TastingMenu(ctx, &TastingMenuParam{RestaurantID: "404", Budget: 50, MaxMinutes: 30})
+	assert.Error(t, err)
+}
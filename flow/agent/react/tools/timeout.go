@@ -0,0 +1,121 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/cloudwego/eino/components/tool"
+)
+
+// DefaultToolTimeout 是每次 tool 调用的默认超时时间, 和整个运行的 deadline 无关.
+const DefaultToolTimeout = 10 * time.Second
+
+// timeoutTool 给每次调用一个单独的超时: 被包装的 tool 拿到的 ctx 最多存活 timeout,
+// 超时后立即返回一个结构化的超时信息, 即使被包装的 tool 不看 ctx 也不会一直等下去, 丢下的调用在后台结束.
+type timeoutTool struct {
+	tool.InvokableTool
+	timeout time.Duration
+}
+
+// NewTimeoutTool wraps t so that each call is abandoned after timeout. timeout <= 0 returns t unchanged.
+func NewTimeoutTool(t tool.InvokableTool, timeout time.Duration) tool.InvokableTool {
+	if timeout <= 0 {
+		return t
+	}
+	return &timeoutTool{InvokableTool: t, timeout: timeout}
+}
+
+func (tt *timeoutTool) InvokableRun(ctx context.Context, argumentsInJSON string, opts ...tool.Option) (string, error) {
+	res, timedOut := runWithTimeout(ctx, tt.InvokableTool, tt.timeout, argumentsInJSON, opts...)
+	if timedOut {
+		return tt.timedOut(ctx), nil
+	}
+	return res.out, res.err
+}
+
+// runWithTimeout 调用 t, 最多等待 timeout. 超时时 timedOut 为 true, 丢下的调用在后台结束;
+// 整个运行被取消或者到了 deadline 时不算超时, 返回 cancelledError.
+//
+// 被包装的 tool 拿到的是 ctx 中 ToolExecutionState 的副本, 及时返回时才把副本写回. 丢下的调用在后台结束时
+// 只会写自己的副本, 不会和调用方并发地写同一个状态, 也不会覆盖超时的结果.
+func runWithTimeout(ctx context.Context, t tool.InvokableTool, timeout time.Duration, argumentsInJSON string, opts ...tool.Option) (res toolResult, timedOut bool) {
+	tctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	state := GetToolState(ctx)
+	var detached *ToolExecutionState
+	if state != nil {
+		copied := *state
+		detached = &copied
+		tctx = SetToolState(tctx, detached)
+	}
+
+	// buffered, 同 cancellableTool
+	done := make(chan toolResult, 1)
+	go func() {
+		out, err := t.InvokableRun(tctx, argumentsInJSON, opts...)
+		done <- toolResult{out: out, err: err}
+	}()
+
+	select {
+	case res = <-done:
+		// 被包装的 safeTool 可能已经把 ctx 的错误转成了结果, 超时时统一按超时处理
+		if ctx.Err() == nil && errors.Is(tctx.Err(), context.DeadlineExceeded) {
+			return toolResult{}, true
+		}
+		// 被包装的 tool 已经返回, 不会再写副本
+		if state != nil {
+			*state = *detached
+		}
+		return res, false
+	case <-tctx.Done():
+		// 整个运行被取消或者到了 deadline, 不是这个 tool 太慢
+		if err := ctx.Err(); err != nil {
+			return toolResult{err: cancelledError(err)}, false
+		}
+		return toolResult{}, true
+	}
+}
+
+// timedOutMessage 是超时时记录在 ToolExecutionState.ErrMsg 中的信息.
+func timedOutMessage(timeout time.Duration) string {
+	return fmt.Sprintf("tool timed out after %v", timeout)
+}
+
+// timedOut 把这次调用标记为失败, 返回给模型的信息说明可以重试, 也可以不用这个结果继续回答.
+func (tt *timeoutTool) timedOut(ctx context.Context) string {
+	if state := GetToolState(ctx); state != nil {
+		state.Success = false
+		state.ErrMsg = timedOutMessage(tt.timeout)
+	}
+	name := "the tool"
+	if info, err := tt.Info(ctx); err == nil {
+		name = info.Name
+	}
+	msg, _ := json.Marshal(map[string]string{
+		"error": "tool timeout",
+		"message": fmt.Sprintf("%s did not finish within %v and was abandoned. "+
+			"Try again once, or continue with the information you already have", name, tt.timeout),
+		"retry": "true",
+	})
+	return string(msg)
+}
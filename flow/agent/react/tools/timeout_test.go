@@ -0,0 +1,131 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tools
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTimeoutTool(t *testing.T) {
+	ctx := context.Background()
+
+	// 不看 ctx 的 tool 也会在超时后立即返回
+	state := &ToolExecutionState{Success: true}
+	start := time.Now()
+	out, err := NewTimeoutTool(&stubbornTool{sleep: 5 * time.Second}, 50*time.Millisecond).InvokableRun(SetToolState(ctx, state), `{}`)
+	assert.NoError(t, err)
+	assert.Less(t, time.Since(start), time.Second)
+	assert.Contains(t, out, `"error":"tool timeout"`)
+	assert.Contains(t, out, "stubborn did not finish within 50ms")
+	assert.False(t, state.Success)
+	assert.Equal(t, "tool timed out after 50ms", state.ErrMsg)
+
+	// 被包装的 safeTool 把 ctx 的错误转成了结果, 仍然返回超时信息
+	svc := &fakeService{repo: database, latency: 5 * time.Second}
+	wrapped := safeTool{InvokableTool: NewCancellableTool(&ToolQueryDishes{backService: svc})}
+	out, err = NewTimeoutTool(wrapped, 50*time.Millisecond).InvokableRun(ctx, `{"restaurant_id":"1001","topn":1}`)
+	assert.NoError(t, err)
+	assert.Contains(t, out, "query_dishes did not finish within 50ms")
+
+	out, err = NewTimeoutTool(&stubbornTool{}, time.Second).InvokableRun(ctx, `{}`)
+	assert.NoError(t, err)
+	assert.Equal(t, "done", out)
+
+	// 整个运行被取消时不是超时
+	cctx, cancel := context.WithCancel(ctx)
+	time.AfterFunc(20*time.Millisecond, cancel)
+	_, err = NewTimeoutTool(&stubbornTool{sleep: 5 * time.Second}, time.Second).InvokableRun(cctx, `{}`)
+	assert.ErrorContains(t, err, `"error":"cancelled"`)
+
+	stubborn := &stubbornTool{}
+	assert.Same(t, stubborn, NewTimeoutTool(stubborn, 0))
+}
+
+func TestSafeToolWithTimeout(t *testing.T) {
+	ctx := context.Background()
+
+	// 超时的调用作为失败交给模型, 不用等慢的 tool 结束
+	state := &ToolExecutionState{}
+	start := time.Now()
+	out, err := NewSafeToolWithTimeout(&stubbornTool{sleep: 5 * time.Second}, 50*time.Millisecond).InvokableRun(SetToolState(ctx, state), `{}`)
+	assert.NoError(t, err)
+	assert.Less(t, time.Since(start), time.Second)
+	assert.Equal(t, "tool timed out after 50ms", out)
+	assert.False(t, state.Success)
+	assert.Equal(t, "tool timed out after 50ms", state.ErrMsg)
+	assert.Equal(t, 1, state.Attempts)
+
+	// 被包装的 tool 自己也写执行状态时, 等它在后台结束之后记录的仍然是超时. 需要用 -race 运行
+	state = &ToolExecutionState{}
+	svc := &fakeService{repo: database, latency: 200 * time.Millisecond}
+	out, err = NewSafeToolWithTimeout(NewSafeTool(NewCancellableTool(&ToolQueryDishes{backService: svc})), 20*time.Millisecond).InvokableRun(SetToolState(ctx, state), `{"restaurant_id":"1001"}`)
+	assert.NoError(t, err)
+	assert.Equal(t, "tool timed out after 20ms", out)
+	time.Sleep(300 * time.Millisecond)
+	assert.False(t, state.Success)
+	assert.Equal(t, "tool timed out after 20ms", state.ErrMsg)
+
+	// 及时返回的调用不受影响
+	state = &ToolExecutionState{}
+	out, err = NewSafeToolWithTimeout(&stubbornTool{}, time.Second).InvokableRun(SetToolState(ctx, state), `{}`)
+	assert.NoError(t, err)
+	assert.Equal(t, "done", out)
+	assert.True(t, state.Success)
+
+	// 严格模式下超时作为错误返回
+	SetStrictMode(true)
+	defer SetStrictMode(false)
+	_, err = NewSafeToolWithTimeout(&stubbornTool{sleep: 5 * time.Second}, 50*time.Millisecond).InvokableRun(ctx, `{}`)
+	assert.EqualError(t, err, "tool timed out after 50ms")
+	SetStrictMode(false)
+
+	// 整个运行被取消时不是超时
+	cctx, cancel := context.WithCancel(ctx)
+	time.AfterFunc(20*time.Millisecond, cancel)
+	out, err = NewSafeToolWithTimeout(&stubbornTool{sleep: 5 * time.Second}, time.Second).InvokableRun(cctx, `{}`)
+	assert.NoError(t, err)
+	assert.Contains(t, out, `"error":"cancelled"`)
+
+	// 默认不限制
+	assert.Equal(t, NewSafeTool(&stubbornTool{}), NewSafeToolWithTimeout(&stubbornTool{}, 0))
+}
+
+func TestTimeoutIsolatesToolState(t *testing.T) {
+	ctx := context.Background()
+	svc := &fakeService{repo: database, latency: 200 * time.Millisecond}
+
+	// 被丢下的调用在后台结束时也会写执行状态, 它写的是自己的副本, 不会覆盖超时的结果. 需要用 -race 运行
+	state := &ToolExecutionState{}
+	inner := NewSafeTool(NewCancellableTool(&ToolQueryDishes{backService: svc}))
+	_, err := NewTimeoutTool(inner, 20*time.Millisecond).InvokableRun(SetToolState(ctx, state), `{"restaurant_id":"1001"}`)
+	assert.NoError(t, err)
+	time.Sleep(300 * time.Millisecond)
+	assert.False(t, state.Success)
+	assert.Equal(t, "tool timed out after 20ms", state.ErrMsg)
+
+	// 及时返回时被包装的 tool 写的状态照常保留
+	state = &ToolExecutionState{}
+	_, err = NewTimeoutTool(NewSafeToolWithRetry(&flakyTool{failures: 1, err: errTemporary}, 2, 0), time.Second).
+		InvokableRun(SetToolState(ctx, state), `{}`)
+	assert.NoError(t, err)
+	assert.True(t, state.Success)
+	assert.Equal(t, 2, state.Attempts)
+}
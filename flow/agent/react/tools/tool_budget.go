@@ -0,0 +1,107 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"sync/atomic"
+
+	"github.com/cloudwego/eino/components/tool"
+)
+
+// ToolBudget 限制一次运行中 tool 的总调用次数. 和 MaxStep 不同, 它数的是 tool 调用而不是 ReAct 的步数:
+// 模型在一条消息里并发调用多个 tool 时, 每个都算一次. 用完以后的调用不再执行, 而是返回 "tool budget exhausted",
+// 让模型用已有的结果给出回答.
+type ToolBudget struct {
+	max    int64
+	used   atomic.Int64
+	denied atomic.Int64
+	once   sync.Once
+}
+
+type toolBudgetKey struct{}
+
+// WithToolBudget 为新的一次运行创建最多 max 次 tool 调用的 ToolBudget. max 小于等于 0 时不限制, 原样返回 ctx.
+func WithToolBudget(ctx context.Context, max int) context.Context {
+	if max <= 0 {
+		return ctx
+	}
+	return context.WithValue(ctx, toolBudgetKey{}, &ToolBudget{max: int64(max)})
+}
+
+// ToolBudgetFrom 返回 ctx 中这次运行的 ToolBudget, 没有时返回 nil.
+func ToolBudgetFrom(ctx context.Context) *ToolBudget {
+	b, _ := ctx.Value(toolBudgetKey{}).(*ToolBudget)
+	return b
+}
+
+// Used 返回已经执行的调用次数, 不包括被拒绝的.
+func (b *ToolBudget) Used() int {
+	return int(min(b.used.Load(), b.max))
+}
+
+// Denied 返回因为预算用完而被拒绝的调用次数.
+func (b *ToolBudget) Denied() int {
+	return int(b.denied.Load())
+}
+
+// take 占用一次调用, 预算已经用完时返回 false. 并发调用时恰好有 max 次返回 true.
+func (b *ToolBudget) take() bool {
+	if b.used.Add(1) <= b.max {
+		return true
+	}
+	b.denied.Add(1)
+	return false
+}
+
+// toolBudgetTool 在 ctx 中有 ToolBudget 时先占用一次调用, 预算用完时直接返回提示, 不再调用被包装的 tool.
+// 它应该包装在 dedupTool 里面: 合并掉的重复调用没有真正执行, 不占用预算.
+type toolBudgetTool struct {
+	tool.InvokableTool
+}
+
+// NewToolBudgetTool wraps t so that calls beyond the ToolBudget of the run are answered with "tool budget exhausted".
+func NewToolBudgetTool(t tool.InvokableTool) tool.InvokableTool {
+	return &toolBudgetTool{InvokableTool: t}
+}
+
+func (b *toolBudgetTool) InvokableRun(ctx context.Context, argumentsInJSON string, opts ...tool.Option) (string, error) {
+	budget := ToolBudgetFrom(ctx)
+	if budget == nil || budget.take() {
+		return b.InvokableTool.InvokableRun(ctx, argumentsInJSON, opts...)
+	}
+
+	name := "the tool"
+	if info, err := b.InvokableTool.Info(ctx); err == nil {
+		name = info.Name
+	}
+	budget.once.Do(func() {
+		fmt.Printf("[TOOL BUDGET] reached the cap of %d tool calls, refusing %s and any further calls\n", budget.max, name)
+	})
+	if state := GetToolState(ctx); state != nil {
+		state.Success = false
+	}
+	res, _ := json.Marshal(map[string]string{
+		"error":   "tool budget exhausted",
+		"message": fmt.Sprintf("this run already made the maximum of %d tool calls, %s was not called; do not call any more tools, answer with the results you already have", budget.max, name),
+		"retry":   "false",
+	})
+	return string(res), nil
+}
@@ -0,0 +1,63 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tools
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestToolBudget(t *testing.T) {
+	ctx := WithToolBudget(context.Background(), 3)
+	budget := ToolBudgetFrom(ctx)
+	wrapped := NewToolBudgetTool(&ToolGreeting{backService: restService})
+
+	// 并发调用时恰好执行 3 次
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	var refused int
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			state := &ToolExecutionState{Success: true}
+			out, err := wrapped.InvokableRun(SetToolState(ctx, state), `{}`)
+			assert.NoError(t, err)
+			if strings.Contains(out, "tool budget exhausted") {
+				mu.Lock()
+				refused++
+				mu.Unlock()
+				assert.False(t, state.Success)
+				assert.Contains(t, out, "get_greeting was not called")
+			}
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, 7, refused)
+	assert.Equal(t, 3, budget.Used())
+	assert.Equal(t, 7, budget.Denied())
+
+	// 没有预算时不限制
+	assert.Nil(t, ToolBudgetFrom(WithToolBudget(context.Background(), 0)))
+	out, err := wrapped.InvokableRun(context.Background(), `{}`)
+	assert.NoError(t, err)
+	assert.NotContains(t, out, "tool budget exhausted")
+}
@@ -19,8 +19,14 @@ package tools
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"math/rand"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/cloudwego/eino/components/tool"
@@ -32,6 +38,12 @@ import (
 type ToolExecutionState struct {
 	// Success 表示工具调用是否成功
 	Success bool
+	// ErrMsg 是最近一次失败的错误信息, 成功时清空, 不会留下之前某次尝试的错误
+	ErrMsg string
+	// Attempts 是 tool 实际被调用的次数, 包括 retryTool 的重试; 同一个 state 被多次调用时累加.
+	// RetryDelay 是重试之间累计等待的时长
+	Attempts   int
+	RetryDelay time.Duration
 }
 
 type toolStateKey struct{}
@@ -60,8 +72,22 @@ func SetToolState(ctx context.Context, state *ToolExecutionState) context.Contex
 // safeTool wraps a tool to convert errors into error messages that the model can handle.
 // When a tool returns an error, safeTool returns the error message as a string instead of propagating the error,
 // allowing the model to see the error and decide whether to retry or use another tool.
+// In strict mode (see SetStrictMode) errors are propagated as is.
 type safeTool struct {
 	tool.InvokableTool
+	timeout time.Duration // <= 0 表示不限制, 见 NewSafeToolWithTimeout
+}
+
+// NewSafeTool 用 safeTool 包装任意 InvokableTool, 其他包中自己实现的 tool 也可以得到同样的降级能力:
+// InvokableRun 的错误作为 content 交给模型, 并在 ctx 中的 ToolExecutionState 记下是否成功. Info 的错误不会被转换, 照常返回.
+func NewSafeTool(t tool.InvokableTool) tool.InvokableTool {
+	return safeTool{InvokableTool: t}
+}
+
+// NewSafeToolWithTimeout 是带超时的 NewSafeTool: 每次调用最多等待 timeout, 超时后丢下这次调用,
+// 把 "tool timed out after Xs" 作为 content 交给模型并记为失败, 模型可以不等这个结果继续回答. timeout <= 0 时等同于 NewSafeTool.
+func NewSafeToolWithTimeout(t tool.InvokableTool, timeout time.Duration) tool.InvokableTool {
+	return safeTool{InvokableTool: t, timeout: timeout}
 }
 
 func (s safeTool) Info(ctx context.Context) (*schema.ToolInfo, error) {
@@ -69,39 +95,172 @@ func (s safeTool) Info(ctx context.Context) (*schema.ToolInfo, error) {
 }
 
 func (s safeTool) InvokableRun(ctx context.Context, argumentsInJSON string, opts ...tool.Option) (string, error) {
-	out, e := s.InvokableTool.InvokableRun(ctx, argumentsInJSON, opts...)
-	
-	// 设置执行状态：仅当 e 为空时认为成功
 	state := GetToolState(ctx)
+	var attempts int
+	if state != nil {
+		attempts = state.Attempts
+	}
+
+	out, e := s.run(ctx, argumentsInJSON, opts...)
+
+	// 设置执行状态：仅当 e 为空时认为成功
 	if state != nil {
 		state.Success = (e == nil)
+		state.ErrMsg = ""
+		if e != nil {
+			state.ErrMsg = e.Error()
+		}
+		// 内层的 retryTool 已经按实际的调用次数计过数时不再重复计数
+		if state.Attempts == attempts {
+			state.Attempts++
+		}
 	}
-	
+
 	if e != nil {
+		if strictMode.Load() {
+			return "", e
+		}
 		// Return error message as string instead of error, so the model can see it and decide next action
 		return e.Error(), nil
 	}
 	return out, nil
 }
 
-func GetRestaurantTool() tool.InvokableTool {
-	return safeTool{
-		InvokableTool: &ToolQueryRestaurants{
-			backService: restService,
-		},
+// run 调用被包装的 tool, 设置了 timeout 时超时的调用返回 timedOutMessage 作为错误.
+func (s safeTool) run(ctx context.Context, argumentsInJSON string, opts ...tool.Option) (string, error) {
+	if s.timeout <= 0 {
+		return s.InvokableTool.InvokableRun(ctx, argumentsInJSON, opts...)
+	}
+	res, timedOut := runWithTimeout(ctx, s.InvokableTool, s.timeout, argumentsInJSON, opts...)
+	if timedOut {
+		return "", errors.New(timedOutMessage(s.timeout))
+	}
+	return res.out, res.err
+}
+
+// NewSafeStreamTool 是 NewSafeTool 的流式版本. 开始输出之前的错误转成只有一帧的 stream; 输出过程中 stream 返回的错误
+// 转成最后一帧, 之前已经输出的帧保留, 模型能同时看到部分结果和出错的原因. ToolExecutionState 在 stream 结束时才设置,
+// 所以 callback 要在读完 stream 之后再读取它. 严格模式 (见 SetStrictMode) 下错误照常返回.
+func NewSafeStreamTool(t tool.StreamableTool) tool.StreamableTool {
+	return safeStreamTool{StreamableTool: t}
+}
+
+type safeStreamTool struct {
+	tool.StreamableTool
+}
+
+func (s safeStreamTool) StreamableRun(ctx context.Context, argumentsInJSON string, opts ...tool.Option) (*schema.StreamReader[string], error) {
+	state := GetToolState(ctx)
+	if state != nil {
+		state.Attempts++
+	}
+	setResult := func(err error) {
+		if state != nil {
+			state.Success, state.ErrMsg = err == nil, ""
+			if err != nil {
+				state.ErrMsg = err.Error()
+			}
+		}
+	}
+
+	sr, e := s.StreamableTool.StreamableRun(ctx, argumentsInJSON, opts...)
+	if e != nil {
+		setResult(e)
+		if strictMode.Load() {
+			return nil, e
+		}
+		return schema.StreamReaderFromArray([]string{e.Error()}), nil
 	}
+	if strictMode.Load() {
+		setResult(nil)
+		return sr, nil
+	}
+
+	out, sw := schema.Pipe[string](1)
+	go func() {
+		defer sw.Close()
+		defer sr.Close()
+		for {
+			chunk, err := sr.Recv()
+			if errors.Is(err, io.EOF) {
+				setResult(nil)
+				return
+			}
+			if err != nil {
+				setResult(err)
+				sw.Send(err.Error(), nil)
+				return
+			}
+			if closed := sw.Send(chunk, nil); closed {
+				return
+			}
+		}
+	}()
+	return out, nil
 }
 
+// strictMode 为 true 时 safeTool 直接返回错误, 整个 agent 会因此中断.
+var strictMode atomic.Bool
+
+// SetStrictMode 开启后 tool 的错误不再转成 content 交给模型, 而是作为 Go error 返回, 开发时可以第一时间发现 tool 本身的问题,
+// 区分 "模型处理了一个错误" 和 "tool 坏了". guardTool 等拒绝参数的结果不是错误, 不受影响.
+func SetStrictMode(strict bool) {
+	strictMode.Store(strict)
+}
+
+// emptyResult 是查询类 tool 没有找到任何结果时统一返回的内容, 比 [] 或 null 更明确, 模型不容易误读.
+func emptyResult(kind string) string {
+	res, _ := marshalResult(map[string]any{
+		"results": []any{},
+		"message": fmt.Sprintf("no matching %s found", kind),
+	})
+	return string(res)
+}
+
+func GetRestaurantTool() tool.InvokableTool {
+	// 后端会随机失败, 先在 tool 内部重试, 仍然失败才把错误交给模型; 重试后仍然连续失败时熔断一段时间.
+	// 只重试标记了 "retry":"true" 的错误, 参数不合法这类错误重试多少次结果都一样
+	return Chain(SafeMiddleware(), CircuitBreakerMiddleware(CircuitBreakerConfig{}), RetryMiddleware(RetryConfig{Retryable: RetryHinted}), NewCancellableTool)(&ToolQueryRestaurants{
+		backService: restService,
+		globalRate:  true,
+	})
+}
+
+// BrokenDishToolEnv 设置为 "true" 时, GetDishTool 返回一个永远失败的 tool, 用于演示降级:
+// safeTool 会把错误作为 content 返回给模型, 整个 agent 不会因此中断, 模型仍然可以只基于餐厅信息给出部分推荐.
+const BrokenDishToolEnv = "REACT_BROKEN_DISH_TOOL"
+
 func GetDishTool() tool.InvokableTool {
-	return safeTool{
-		InvokableTool: &ToolQueryDishes{
-			backService: restService,
-		},
+	var dishTool tool.InvokableTool = &ToolQueryDishes{
+		backService: restService,
+	}
+	if os.Getenv(BrokenDishToolEnv) == "true" {
+		dishTool = brokenTool{InvokableTool: dishTool}
 	}
+
+	return Chain(SafeMiddleware(), CircuitBreakerMiddleware(CircuitBreakerConfig{}), NewCancellableTool)(dishTool)
+}
+
+// brokenTool 保留被包装 tool 的 Info, 但每次调用都返回一个不可重试的错误.
+type brokenTool struct {
+	tool.InvokableTool
+}
+
+func (b brokenTool) InvokableRun(ctx context.Context, argumentsInJSON string, opts ...tool.Option) (string, error) {
+	return "", errors.New(`{"error":"service permanently unavailable","message":"This service is down and will not recover during this conversation. Do not retry, continue with the information you already have.","retry":"false"}`)
 }
 
 type ToolQueryRestaurants struct {
 	backService *fakeService // fake service
+
+	// FailureRate 是每次调用返回模拟错误的概率: 0 表示从不返回, 1 表示每次都返回.
+	// GetRestaurantTool 创建的 tool 不看这个字段, 使用 SetFailureRate 的全局设置.
+	FailureRate float32
+	// Rand 决定每次调用是否模拟失败, 测试中可以注入固定种子的 *rand.Rand 得到确定的结果. 为空时使用全局的随机数.
+	Rand   *rand.Rand
+	randMu sync.Mutex
+
+	globalRate bool // 使用 SetFailureRate 的全局设置, 见 GetRestaurantTool
 }
 
 func (t *ToolQueryRestaurants) Info(ctx context.Context) (*schema.ToolInfo, error) {
@@ -118,6 +277,23 @@ func (t *ToolQueryRestaurants) Info(ctx context.Context) (*schema.ToolInfo, erro
 				Type: "number",
 				Desc: "top n restaurant in some location sorted by score",
 			},
+			"page": {
+				Type: "number",
+				Desc: pageDesc,
+			},
+			"page_size": {
+				Type: "number",
+				Desc: pageSizeDesc,
+			},
+			"accessible_only": {
+				Type: "boolean",
+				Desc: "Only return restaurants with wheelchair access and step-free entry",
+			},
+			"min_hygiene_grade": {
+				Type: "string",
+				Desc: "Only return restaurants with at least this hygiene grade, A is the best",
+				Enum: []string{"A", "B", "C"},
+			},
 		}),
 	}, nil
 }
@@ -129,17 +305,19 @@ func (t *ToolQueryRestaurants) Info(ctx context.Context) (*schema.ToolInfo, erro
 func (t *ToolQueryRestaurants) InvokableRun(ctx context.Context, argumentsInJSON string, opts ...tool.Option) (string, error) {
 	// 解析参数
 	p := &QueryRestaurantsParam{}
-	err := json.Unmarshal([]byte(argumentsInJSON), p)
-	if err != nil {
+	if err := decodeArguments(ctx, t, argumentsInJSON, p); err != nil {
+		return "", err
+	}
+	if err := p.validate(); err != nil {
 		return "", err
 	}
 	if p.Topn == 0 {
 		p.Topn = 3
 	}
+	p.minScore = applyQueryOptions(&p.Topn, opts...)
 
-	// 随机报错测试（50% 概率），错误中提示可以重试
-	rand.Seed(time.Now().UnixNano())
-	if rand.Float32() < 0.5 {
+	// 随机报错测试（默认 50% 概率, 见 SetFailureRate 和 FailureRate），错误中提示可以重试
+	if t.simulateFailure() {
 		errorMsg := map[string]string{
 			"error":   "service temporarily unavailable",
 			"message": "The restaurant service is temporarily unavailable. Please retry later.",
@@ -149,14 +327,33 @@ func (t *ToolQueryRestaurants) InvokableRun(ctx context.Context, argumentsInJSON
 		return "", fmt.Errorf("%s", string(errorJSON))
 	}
 
+	// 分页查询返回 next_page, 模型可以继续查下一页
+	if paged(p.Page, p.PageSize) {
+		page, err := t.backService.QueryRestaurantsPage(ctx, p)
+		if err != nil {
+			return "", err
+		}
+		if page.Total == 0 {
+			return emptyResult("restaurants"), nil
+		}
+		res, err := marshalResult(page)
+		if err != nil {
+			return "", err
+		}
+		return string(res), nil
+	}
+
 	// 请求后端服务
 	rests, err := t.backService.QueryRestaurants(ctx, p)
 	if err != nil {
 		return "", err
 	}
+	if len(rests) == 0 {
+		return emptyResult("restaurants"), nil
+	}
 
 	// 序列化结果
-	res, err := json.Marshal(rests)
+	res, err := marshalResult(rests)
 	if err != nil {
 		return "", err
 	}
@@ -164,9 +361,45 @@ func (t *ToolQueryRestaurants) InvokableRun(ctx context.Context, argumentsInJSON
 	return string(res), nil
 }
 
+// simulateFailure 按 FailureRate 决定这次调用是否返回模拟的错误.
+func (t *ToolQueryRestaurants) simulateFailure() bool {
+	rate := float64(t.FailureRate)
+	if t.globalRate {
+		rate = currentFailureRate()
+	}
+	if rate <= 0 {
+		return false
+	}
+	if t.Rand == nil {
+		return rand.Float64() < rate
+	}
+	// *rand.Rand 不能并发使用, 而模型可能在一轮中并发调用同一个 tool
+	t.randMu.Lock()
+	defer t.randMu.Unlock()
+	return t.Rand.Float64() < rate
+}
+
 type QueryRestaurantsParam struct {
-	Location string `json:"location"`
-	Topn     int    `json:"topn"`
+	Location       string `json:"location"`
+	Topn           int    `json:"topn"`
+	AccessibleOnly bool   `json:"accessible_only"`
+	Page           int    `json:"page"`
+	PageSize       int    `json:"page_size"`
+
+	MinHygieneGrade string `json:"min_hygiene_grade"`
+
+	minScore int // WithMinScore 设置的最低评分
+}
+
+// validate 检查反序列化之后的参数, 代替 checkRequired: location 不存在、为 null、空字符串或只有空白都算缺少, topn 不能为负数.
+func (p *QueryRestaurantsParam) validate() error {
+	if strings.TrimSpace(p.Location) == "" {
+		return missingArgumentError("query_restaurants", "location")
+	}
+	if p.Topn < 0 {
+		return invalidArgumentError("query_restaurants", "topn", fmt.Sprintf("must not be negative, got %d", p.Topn))
+	}
+	return nil
 }
 
 type Restaurant struct {
@@ -175,6 +408,22 @@ type Restaurant struct {
 	Place string `json:"place"`
 	Desc  string `json:"desc"`
 	Score int    `json:"score"`
+
+	Cuisine string `json:"cuisine,omitempty"`
+	WalkIns bool   `json:"walk_ins"` // false 表示只接受预订
+
+	Ambiance      *Ambiance      `json:"ambiance,omitempty"`
+	Accessibility *Accessibility `json:"accessibility,omitempty"`
+
+	Certifications *Certifications `json:"certifications,omitempty"`
+
+	ReviewCount int `json:"review_count,omitempty"` // 用户评价的数量
+}
+
+// Ambiance 是餐厅的氛围标签和噪音等级 (1 安静 - 5 嘈杂).
+type Ambiance struct {
+	Tags       []string `json:"tags"`
+	NoiseLevel int      `json:"noise_level"`
 }
 
 // ToolQueryDishes.
@@ -184,42 +433,72 @@ type ToolQueryDishes struct {
 
 func (t *ToolQueryDishes) Info(ctx context.Context) (*schema.ToolInfo, error) {
 	return &schema.ToolInfo{
-		Name: "query_dishes",
-		Desc: "查询一家餐厅有哪些菜品",
-		ParamsOneOf: schema.NewParamsOneOfByParams(map[string]*schema.ParameterInfo{
-			"restaurant_id": {
-				Type:     "string",
-				Desc:     "The id of one restaurant",
-				Required: true,
-			},
-			"topn": {
-				Type: "number",
-				Desc: "top n dishes in one restaurant sorted by score",
-			},
-		}),
+		Name:        "query_dishes",
+		Desc:        "查询一家餐厅有哪些菜品",
+		ParamsOneOf: schema.NewParamsOneOfByParams(queryDishesParams()),
 	}, nil
 }
 
+// queryDishesParams 是 query_dishes 的参数, 流式的版本 (见 GetDishStreamTool) 去掉其中的分页参数.
+func queryDishesParams() map[string]*schema.ParameterInfo {
+	return map[string]*schema.ParameterInfo{
+		"restaurant_id": {
+			Type:     "string",
+			Desc:     "The id of one restaurant",
+			Required: true,
+		},
+		"topn": {
+			Type: "number",
+			Desc: "top n dishes in one restaurant sorted by score",
+		},
+		"page": {
+			Type: "number",
+			Desc: pageDesc,
+		},
+		"page_size": {
+			Type: "number",
+			Desc: pageSizeDesc,
+		},
+		"ignore_preferences": {
+			Type: "boolean",
+			Desc: "Also return dishes that do not match the dietary preferences stored for the user, only when the user asks for them explicitly",
+		},
+		"in_stock_only": {
+			Type: "boolean",
+			Desc: "Only return dishes that are in stock right now, e.g. before recommending dishes to order",
+		},
+	}
+}
+
 func (t *ToolQueryDishes) InvokableRun(ctx context.Context, argumentsInJSON string, opts ...tool.Option) (string, error) {
 	// 解析参数
-	p := &QueryDishesParam{}
-	err := json.Unmarshal([]byte(argumentsInJSON), p)
+	p, err := parseQueryDishes(ctx, t, argumentsInJSON, opts...)
 	if err != nil {
 		return "", err
 	}
 
-	if p.Topn == 0 {
-		p.Topn = 5
+	// 请求后端服务, 指定了 page 或 page_size 时分页返回
+	var result any
+	var count int
+	if paged(p.Page, p.PageSize) {
+		page, err := t.backService.QueryDishesPage(ctx, p)
+		if err != nil {
+			return "", err
+		}
+		result, count = page, page.Total
+	} else {
+		dishes, err := t.backService.QueryDishes(ctx, p)
+		if err != nil {
+			return "", err
+		}
+		result, count = dishes, len(dishes)
 	}
-
-	// 请求后端服务
-	rests, err := t.backService.QueryDishes(ctx, p)
-	if err != nil {
-		return "", err
+	if count == 0 {
+		return noDishesResult(ctx, t.backService, p), nil
 	}
 
 	// 序列化结果
-	res, err := json.Marshal(rests)
+	res, err := marshalResult(result)
 	if err != nil {
 		return "", err
 	}
@@ -227,9 +506,56 @@ func (t *ToolQueryDishes) InvokableRun(ctx context.Context, argumentsInJSON stri
 	return string(res), nil
 }
 
+// parseQueryDishes 解析并检查 query_dishes 的参数, 填上 topn 的默认值和 opts 中的调用选项. t 用于读取参数的 schema.
+func parseQueryDishes(ctx context.Context, t tool.BaseTool, argumentsInJSON string, opts ...tool.Option) (*QueryDishesParam, error) {
+	p := &QueryDishesParam{}
+	if err := decodeArguments(ctx, t, argumentsInJSON, p); err != nil {
+		return nil, err
+	}
+	if err := p.validate(); err != nil {
+		return nil, err
+	}
+
+	if p.Topn == 0 {
+		p.Topn = 5
+	}
+	p.minScore = applyQueryOptions(&p.Topn, opts...)
+	return p, nil
+}
+
+// noDishesResult 是没有查到菜时的结果, 说明是被饮食偏好还是 in_stock_only 筛掉了.
+func noDishesResult(ctx context.Context, svc *fakeService, p *QueryDishesParam) string {
+	if prefs := svc.preferencesFor(ctx); prefs.active() && !p.IgnorePreferences {
+		return emptyResultFilteredBy(prefs)
+	}
+	if p.InStockOnly {
+		return emptyResult("in-stock dishes")
+	}
+	return emptyResult("dishes")
+}
+
 type QueryDishesParam struct {
 	RestaurantID string `json:"restaurant_id"`
 	Topn         int    `json:"topn"`
+	Page         int    `json:"page"`
+	PageSize     int    `json:"page_size"`
+
+	IgnorePreferences bool `json:"ignore_preferences"`
+	// InStockOnly 为 true 时筛掉现在卖完的菜, 见 ToolDishAvailability
+	InStockOnly bool `json:"in_stock_only"`
+
+	minScore int // WithMinScore 设置的最低评分
+}
+
+// validate 检查反序列化之后的参数, 代替 checkRequired: restaurant_id 不存在、为 null、空字符串或只有空白都算缺少, topn 不能为负数.
+func (p *QueryDishesParam) validate() error {
+	if strings.TrimSpace(p.RestaurantID) == "" {
+		return missingArgumentError("query_dishes", "restaurant_id")
+	}
+	if p.Topn < 0 {
+		return invalidArgumentError("query_dishes", "topn", fmt.Sprintf("must not be negative, got %d", p.Topn))
+	}
+	return nil
 }
 
 type Dish struct {
@@ -237,4 +563,32 @@ type Dish struct {
 	Desc  string `json:"desc"`
 	Price int    `json:"price"`
 	Score int    `json:"score"`
+
+	Allergens []string   `json:"allergens,omitempty"`
+	Nutrition *Nutrition `json:"nutrition,omitempty"`
+
+	Ingredients []string `json:"ingredients,omitempty"`
+
+	PrepMinutes int `json:"prep_minutes,omitempty"`
+
+	SpiceLevel int  `json:"spice_level"` // 0 (不辣) - 5 (特辣)
+	Vegetarian bool `json:"vegetarian"`
+
+	CarbonKg float64 `json:"carbon_kg,omitempty"` // 每份估算的碳排放, kg CO2e
+
+	Seasons []string `json:"seasons,omitempty"` // 应季的季节, 为空表示常年供应
+
+	Portion   string `json:"portion,omitempty"` // 分量: small, medium, large
+	Shareable bool   `json:"shareable,omitempty"`
+
+	CookingMethod string `json:"cooking_method,omitempty"` // 烹饪方式, 如 stir-fried, steamed
+	Course        string `json:"course,omitempty"`         // appetizer, main 或 dessert
+}
+
+// Nutrition 是一份菜的营养成分, 单位为 kcal 和克.
+type Nutrition struct {
+	Calories int     `json:"calories"`
+	ProteinG float64 `json:"protein_g"`
+	CarbsG   float64 `json:"carbs_g"`
+	FatG     float64 `json:"fat_g"`
 }
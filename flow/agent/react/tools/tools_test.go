@@ -0,0 +1,214 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tools
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/cloudwego/eino/components/tool"
+	"github.com/cloudwego/eino/schema"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEmptyResults(t *testing.T) {
+	ctx := context.Background()
+	assert.JSONEq(t, `{"results":[],"message":"no matching dishes found"}`, emptyResult("dishes"))
+
+	// FailureRate 为零值, 不会随机失败
+	out, err := (&ToolQueryRestaurants{backService: restService}).InvokableRun(ctx, `{"location": "火星"}`)
+	assert.NoError(t, err)
+	assert.JSONEq(t, emptyResult("restaurants"), out)
+
+	emptyDB := &restaurantDatabase{restaurantByID: map[string]restaurantDataItem{"9001": {ID: "9001", Name: "空空如也"}}}
+	dishes := &ToolQueryDishes{backService: &fakeService{repo: emptyDB}}
+	out, err = dishes.InvokableRun(ctx, `{"restaurant_id": "9001"}`)
+	assert.NoError(t, err)
+	assert.JSONEq(t, emptyResult("dishes"), out)
+
+	// 不存在的餐厅仍然是错误, 而不是空结果
+	_, err = dishes.InvokableRun(ctx, `{"restaurant_id": "404"}`)
+	assert.Error(t, err)
+}
+
+// safeTool 把错误转成 content 交给模型, 并在 ToolExecutionState 中记下失败; 整个降级的设计都依赖这一点.
+func TestSafeTool(t *testing.T) {
+	ctx := context.Background()
+
+	wrapped := safeTool{InvokableTool: &flakyTool{failures: 1, err: errors.New(`{"error":"backend down","retry":"false"}`)}}
+	state := &ToolExecutionState{}
+	out, err := wrapped.InvokableRun(SetToolState(ctx, state), `{}`)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"error":"backend down","retry":"false"}`, out)
+	assert.False(t, state.Success)
+
+	// 成功时原样返回结果
+	state = &ToolExecutionState{}
+	out, err = wrapped.InvokableRun(SetToolState(ctx, state), `{}`)
+	assert.NoError(t, err)
+	assert.Equal(t, "done", out)
+	assert.True(t, state.Success)
+
+	// ctx 中没有 ToolExecutionState 时也能正常工作
+	out, err = safeTool{InvokableTool: &flakyTool{failures: 1, err: errors.New("boom")}}.InvokableRun(ctx, `{}`)
+	assert.NoError(t, err)
+	assert.Equal(t, "boom", out)
+}
+
+func TestSafeToolErrMsgAndAttempts(t *testing.T) {
+	ctx := context.Background()
+
+	// 同一个 state 被多次调用时 Attempts 累加, 成功后清空之前的错误信息
+	wrapped := NewSafeTool(&flakyTool{failures: 1, err: errors.New("backend down")})
+	state := &ToolExecutionState{}
+	_, err := wrapped.InvokableRun(SetToolState(ctx, state), `{}`)
+	assert.NoError(t, err)
+	assert.Equal(t, ToolExecutionState{Success: false, ErrMsg: "backend down", Attempts: 1}, *state)
+	_, err = wrapped.InvokableRun(SetToolState(ctx, state), `{}`)
+	assert.NoError(t, err)
+	assert.Equal(t, ToolExecutionState{Success: true, ErrMsg: "", Attempts: 2}, *state)
+
+	// 内层有 retryTool 时按实际的调用次数计数, 不重复计数
+	flaky := &flakyTool{failures: 2, err: errors.New("backend down")}
+	retried := Chain(SafeMiddleware(), RetryMiddleware(RetryConfig{BaseDelay: time.Millisecond}))(flaky)
+	state = &ToolExecutionState{}
+	_, err = retried.InvokableRun(SetToolState(ctx, state), `{}`)
+	assert.NoError(t, err)
+	assert.True(t, state.Success)
+	assert.Empty(t, state.ErrMsg)
+	assert.Equal(t, 3, flaky.calls)
+	assert.Equal(t, 3, state.Attempts)
+}
+
+func TestSafeToolStrictMode(t *testing.T) {
+	ctx := context.Background()
+	wrapped := safeTool{InvokableTool: brokenTool{InvokableTool: &ToolQueryDishes{backService: restService}}}
+
+	state := &ToolExecutionState{}
+	out, err := wrapped.InvokableRun(SetToolState(ctx, state), `{"restaurant_id": "1001"}`)
+	assert.NoError(t, err)
+	assert.Contains(t, out, "service permanently unavailable")
+	assert.False(t, state.Success)
+
+	SetStrictMode(true)
+	defer SetStrictMode(false)
+	state = &ToolExecutionState{}
+	_, err = wrapped.InvokableRun(SetToolState(ctx, state), `{"restaurant_id": "1001"}`)
+	assert.ErrorContains(t, err, "service permanently unavailable")
+	assert.False(t, state.Success)
+}
+
+// infoErrorTool 的 Info 总是失败, 用来确认 NewSafeTool 不会吞掉 Info 的错误.
+type infoErrorTool struct {
+	flakyTool
+}
+
+func (*infoErrorTool) Info(ctx context.Context) (*schema.ToolInfo, error) {
+	return nil, errors.New("no info")
+}
+
+func TestNewSafeTool(t *testing.T) {
+	ctx := context.Background()
+
+	state := &ToolExecutionState{}
+	out, err := NewSafeTool(&flakyTool{failures: 1, err: errors.New("boom")}).InvokableRun(SetToolState(ctx, state), `{}`)
+	assert.NoError(t, err)
+	assert.Equal(t, "boom", out)
+	assert.False(t, state.Success)
+
+	state = &ToolExecutionState{}
+	out, err = NewSafeTool(&flakyTool{}).InvokableRun(SetToolState(ctx, state), `{}`)
+	assert.NoError(t, err)
+	assert.Equal(t, "done", out)
+	assert.True(t, state.Success)
+
+	// 只转换 InvokableRun 的错误, Info 的错误照常返回
+	info, err := NewSafeTool(&flakyTool{}).Info(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, "flaky", info.Name)
+	_, err = NewSafeTool(&infoErrorTool{}).Info(ctx)
+	assert.EqualError(t, err, "no info")
+}
+
+// frameTool 依次输出 frames, 之后以 err 结束 stream; startErr 不为空时在开始输出之前就失败.
+type frameTool struct {
+	frames   []string
+	err      error
+	startErr error
+}
+
+func (f frameTool) Info(ctx context.Context) (*schema.ToolInfo, error) {
+	return &schema.ToolInfo{Name: "frames"}, nil
+}
+
+func (f frameTool) StreamableRun(ctx context.Context, argumentsInJSON string, opts ...tool.Option) (*schema.StreamReader[string], error) {
+	if f.startErr != nil {
+		return nil, f.startErr
+	}
+	sr, sw := schema.Pipe[string](len(f.frames) + 1)
+	for _, frame := range f.frames {
+		sw.Send(frame, nil)
+	}
+	if f.err != nil {
+		sw.Send("", f.err)
+	}
+	sw.Close()
+	return sr, nil
+}
+
+func TestNewSafeStreamTool(t *testing.T) {
+	ctx := context.Background()
+	collect := func(st tool.StreamableTool) ([]string, *ToolExecutionState) {
+		state := &ToolExecutionState{}
+		sr, err := st.StreamableRun(SetToolState(ctx, state), `{}`)
+		assert.NoError(t, err)
+		defer sr.Close()
+		var frames []string
+		for {
+			frame, err := sr.Recv()
+			if errors.Is(err, io.EOF) {
+				return frames, state
+			}
+			assert.NoError(t, err)
+			frames = append(frames, frame)
+		}
+	}
+
+	frames, state := collect(NewSafeStreamTool(frameTool{frames: []string{"a", "b"}}))
+	assert.Equal(t, []string{"a", "b"}, frames)
+	assert.True(t, state.Success)
+
+	// 输出过程中的错误变成最后一帧, 之前的帧保留
+	frames, state = collect(NewSafeStreamTool(frameTool{frames: []string{"a"}, err: errors.New("backend down")}))
+	assert.Equal(t, []string{"a", "backend down"}, frames)
+	assert.False(t, state.Success)
+	assert.Equal(t, "backend down", state.ErrMsg)
+	assert.Equal(t, 1, state.Attempts)
+
+	// 开始输出之前的错误变成只有一帧的 stream
+	frames, state = collect(NewSafeStreamTool(frameTool{startErr: errors.New("bad args")}))
+	assert.Equal(t, []string{"bad args"}, frames)
+	assert.False(t, state.Success)
+
+	SetStrictMode(true)
+	defer SetStrictMode(false)
+	_, err := NewSafeStreamTool(frameTool{startErr: errors.New("bad args")}).StreamableRun(ctx, `{}`)
+	assert.EqualError(t, err, "bad args")
+}
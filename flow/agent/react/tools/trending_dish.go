@@ -0,0 +1,162 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"math"
+
+	"github.com/cloudwego/eino/components/tool"
+	"github.com/cloudwego/eino/schema"
+)
+
+func GetTrendingDishTool() tool.InvokableTool {
+	return safeTool{
+		InvokableTool: NewCancellableTool(&ToolTrendingDish{
+			backService: restService,
+		}),
+	}
+}
+
+// ToolTrendingDish 返回餐厅当前这个小时点单最多的菜. 点单量是按菜名和小时算出的 fake 数据,
+// 同一个小时内结果不变, "现在" 来自 fake 后端的 clock.
+type ToolTrendingDish struct {
+	backService *fakeService // fake service
+}
+
+func (t *ToolTrendingDish) Info(ctx context.Context) (*schema.ToolInfo, error) {
+	return &schema.ToolInfo{
+		Name: "query_trending_dish",
+		Desc: "Query which dish of a restaurant is ordered the most right now, with the orders in the current hour and a volume level",
+		ParamsOneOf: schema.NewParamsOneOfByParams(map[string]*schema.ParameterInfo{
+			"restaurant_id": {
+				Type:     "string",
+				Desc:     "The id of one restaurant",
+				Required: true,
+			},
+		}),
+	}, nil
+}
+
+func (t *ToolTrendingDish) InvokableRun(ctx context.Context, argumentsInJSON string, opts ...tool.Option) (string, error) {
+	// 解析参数
+	if err := checkRequired(ctx, t, argumentsInJSON); err != nil {
+		return "", err
+	}
+	p := &TrendingDishParam{}
+	err := json.Unmarshal([]byte(argumentsInJSON), p)
+	if err != nil {
+		return "", err
+	}
+
+	// 请求后端服务
+	trending, err := t.backService.TrendingDish(ctx, p)
+	if err != nil {
+		return "", err
+	}
+
+	// 序列化结果
+	res, err := marshalResult(trending)
+	if err != nil {
+		return "", err
+	}
+
+	return string(res), nil
+}
+
+type TrendingDishParam struct {
+	RestaurantID string `json:"restaurant_id"`
+}
+
+// TrendingDish 在不营业的时段 open 为 false, 没有 dish.
+type TrendingDish struct {
+	RestaurantID string `json:"restaurant_id"`
+	Hour         string `json:"hour"` // 统计的小时, 格式 2006-01-02 15:00
+	Open         bool   `json:"open"`
+	Dish         *Dish  `json:"dish,omitempty"`
+	// Orders 是这道菜在这个小时内的点单数
+	Orders  int    `json:"orders,omitempty"`
+	Volume  string `json:"volume,omitempty"` // low, moderate, high 或 very high
+	Message string `json:"message,omitempty"`
+}
+
+// trendingMaxOrders 是最忙的时段里一道菜每小时最多的点单数.
+const trendingMaxOrders = 30
+
+// TrendingDish 计算每道菜在当前小时的点单量, 返回最多的那道, 同样多时取菜单中靠前的.
+func (ft *fakeService) TrendingDish(ctx context.Context, in *TrendingDishParam) (*TrendingDish, error) {
+	if err := ft.simulateLatency(ctx); err != nil {
+		return nil, err
+	}
+
+	rest, err := ft.repo.GetRestaurantByID(ctx, in.RestaurantID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := ft.now()
+	out := &TrendingDish{RestaurantID: rest.ID, Hour: now.Format("2006-01-02 15:00")}
+	if now.Hour() < busyFirstHour || now.Hour() > busyLastHour {
+		out.Message = fmt.Sprintf("the restaurant is closed, orders are taken from %02d:00 to %02d:59", busyFirstHour, busyLastHour)
+		return out, nil
+	}
+	if len(rest.Dishes) == 0 {
+		return nil, errors.New("the restaurant has no dishes")
+	}
+
+	busy := busyness(rest.ID, now.Weekday(), now.Hour())
+	best := -1
+	var top restaurantDishDataItem
+	for _, dish := range rest.Dishes {
+		if orders := hourlyOrders(rest.ID, dish.Name, out.Hour, busy); orders > best {
+			best, top = orders, dish
+		}
+	}
+
+	d := toDish(top)
+	out.Open = true
+	out.Dish = &d
+	out.Orders = best
+	out.Volume = orderVolume(best)
+	return out, nil
+}
+
+// hourlyOrders 是一道菜在 hour 这个小时的点单数: 餐厅越忙点单越多, 再由餐厅、菜名和小时决定一个 0.2 - 1 的系数,
+// 所以每道菜的热度随小时变化, 同一个小时内总是相同.
+func hourlyOrders(restaurantID, dishName, hour string, busy int) int {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(restaurantID + "\x00" + dishName + "\x00" + hour))
+	factor := 0.2 + float64(h.Sum32()%81)/100
+	return int(math.Round(float64(trendingMaxOrders) * float64(busy) / 100 * factor))
+}
+
+func orderVolume(orders int) string {
+	switch {
+	case orders >= 20:
+		return "very high"
+	case orders >= 12:
+		return "high"
+	case orders >= 6:
+		return "moderate"
+	default:
+		return "low"
+	}
+}
@@ -0,0 +1,73 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tools
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTrendingDish(t *testing.T) {
+	ctx := context.Background()
+	loc := time.FixedZone("CST", 8*3600)
+	at := func(hour, minute int) *fakeService {
+		return &fakeService{repo: restService.repo, clock: FixedClock{T: time.Date(2024, 6, 1, hour, minute, 0, 0, loc)}}
+	}
+
+	// 同一个小时内结果不变
+	first, err := at(19, 5).TrendingDish(ctx, &TrendingDishParam{RestaurantID: "1001"})
+	assert.NoError(t, err)
+	later, err := at(19, 55).TrendingDish(ctx, &TrendingDishParam{RestaurantID: "1001"})
+	assert.NoError(t, err)
+	assert.Equal(t, first, later)
+	assert.True(t, first.Open)
+	assert.Equal(t, "2024-06-01 19:00", first.Hour)
+	assert.NotNil(t, first.Dish)
+	assert.Equal(t, orderVolume(first.Orders), first.Volume)
+
+	// 返回的是这个小时点单最多的菜
+	rest, err := restService.repo.GetRestaurantByID(ctx, "1001")
+	assert.NoError(t, err)
+	busy := busyness("1001", time.Saturday, 19)
+	for _, dish := range rest.Dishes {
+		assert.LessOrEqual(t, hourlyOrders("1001", dish.Name, first.Hour, busy), first.Orders, dish.Name)
+	}
+
+	// 晚高峰比下午点单多
+	afternoon, err := at(15, 0).TrendingDish(ctx, &TrendingDishParam{RestaurantID: "1001"})
+	assert.NoError(t, err)
+	assert.Greater(t, first.Orders, afternoon.Orders)
+
+	closed, err := at(8, 0).TrendingDish(ctx, &TrendingDishParam{RestaurantID: "1001"})
+	assert.NoError(t, err)
+	assert.False(t, closed.Open)
+	assert.Nil(t, closed.Dish)
+	assert.Contains(t, closed.Message, "closed")
+
+	_, err = at(19, 0).TrendingDish(ctx, &TrendingDishParam{RestaurantID: "404"})
+	assert.Error(t, err)
+}
+
+func TestOrderVolume(t *testing.T) {
+	assert.Equal(t, "low", orderVolume(0))
+	assert.Equal(t, "moderate", orderVolume(6))
+	assert.Equal(t, "high", orderVolume(12))
+	assert.Equal(t, "very high", orderVolume(20))
+}
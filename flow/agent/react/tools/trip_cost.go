@@ -0,0 +1,213 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+
+	"github.com/cloudwego/eino/components/tool"
+	"github.com/cloudwego/eino/schema"
+)
+
+func GetTripCostTool() tool.InvokableTool {
+	return safeTool{
+		InvokableTool: NewCancellableTool(&ToolTripCost{
+			backService: restService,
+		}),
+	}
+}
+
+// ToolTripCost 估算一次去餐厅吃饭的总花费: 按人均菜价估算的餐费, 往返交通费, 以及服务费或外卖配送费,
+// 一次调用给出分项明细, 模型不需要自己把 price tier, delivery 等几个 tool 的结果加起来.
+type ToolTripCost struct {
+	backService *fakeService // fake service
+}
+
+func (t *ToolTripCost) Info(ctx context.Context) (*schema.ToolInfo, error) {
+	return &schema.ToolInfo{
+		Name: "estimate_trip_cost",
+		Desc: "Estimate the total cost in CNY of a meal in a restaurant for a party, including the food, the round trip and the service or delivery fee. " +
+			"Returns an itemized breakdown",
+		ParamsOneOf: schema.NewParamsOneOfByParams(map[string]*schema.ParameterInfo{
+			"restaurant_id": {
+				Type:     "string",
+				Desc:     "The id of one restaurant",
+				Required: true,
+			},
+			"party_size": {
+				Type:     "integer",
+				Desc:     "How many people are eating",
+				Required: true,
+			},
+			"transport": {
+				Type: "string",
+				Desc: "How the party gets to the restaurant, default taxi. delivery means ordering takeout instead of going",
+				Enum: tripTransports,
+			},
+			"address": {
+				Type: "string",
+				Desc: "The address of the user, used to estimate the distance",
+			},
+		}),
+	}, nil
+}
+
+func (t *ToolTripCost) InvokableRun(ctx context.Context, argumentsInJSON string, opts ...tool.Option) (string, error) {
+	// 解析参数
+	if err := checkRequired(ctx, t, argumentsInJSON); err != nil {
+		return "", err
+	}
+	p := &TripCostParam{}
+	err := json.Unmarshal([]byte(argumentsInJSON), p)
+	if err != nil {
+		return "", err
+	}
+
+	// 请求后端服务
+	cost, err := t.backService.EstimateTripCost(ctx, p)
+	if err != nil {
+		return "", err
+	}
+
+	// 序列化结果
+	res, err := marshalResult(cost)
+	if err != nil {
+		return "", err
+	}
+
+	return string(res), nil
+}
+
+var tripTransports = []string{"walk", "transit", "taxi", "delivery"}
+
+type TripCostParam struct {
+	RestaurantID string `json:"restaurant_id"`
+	PartySize    int    `json:"party_size"`
+	Transport    string `json:"transport"`
+	Address      string `json:"address"`
+}
+
+type TripCostItem struct {
+	Item   string  `json:"item"`
+	Amount float64 `json:"amount"`
+	Note   string  `json:"note"`
+}
+
+// TripCost 中的金额单位都是元, 保留两位小数.
+type TripCost struct {
+	RestaurantID string         `json:"restaurant_id"`
+	PartySize    int            `json:"party_size"`
+	Transport    string         `json:"transport"`
+	DistanceKm   float64        `json:"distance_km"`
+	Items        []TripCostItem `json:"items"`
+	Total        float64        `json:"total"`
+	PerPerson    float64        `json:"per_person"`
+}
+
+const (
+	serviceFeePercent = 10 // 堂食的服务费
+	transitFarePerLeg = 4  // 公交地铁每人单程
+	taxiBaseFare      = 13 // 出租车起步价, 含 taxiBaseKm 公里
+	taxiBaseKm        = 3
+	taxiFarePerKm     = 2.5 // 超过起步里程后每公里
+	taxiSeats         = 4   // 每辆车坐的人数
+)
+
+// EstimateTripCost 估算 in.PartySize 个人去 in.RestaurantID 吃一顿的总花费. 餐费按人均菜价 (同 price tier) 乘以人数,
+// 距离用 fakeDistanceKm 估算. delivery 不去餐厅, 没有交通费和服务费, 改为收配送费.
+func (ft *fakeService) EstimateTripCost(ctx context.Context, in *TripCostParam) (*TripCost, error) {
+	if err := ft.simulateLatency(ctx); err != nil {
+		return nil, err
+	}
+
+	transport := strings.ToLower(strings.TrimSpace(in.Transport))
+	if transport == "" {
+		transport = "taxi"
+	}
+	if !slices.Contains(tripTransports, transport) {
+		return nil, fmt.Errorf("unknown transport %q, expected one of %s", in.Transport, strings.Join(tripTransports, ", "))
+	}
+	if in.PartySize <= 0 || in.PartySize > maxPartySize {
+		return nil, fmt.Errorf("party_size must be between 1 and %d, got %d", maxPartySize, in.PartySize)
+	}
+
+	rest, err := ft.repo.GetRestaurantByID(ctx, in.RestaurantID)
+	if err != nil {
+		return nil, err
+	}
+	if len(rest.Dishes) == 0 {
+		return nil, fmt.Errorf("restaurant %s has no dishes to estimate the food cost from", rest.ID)
+	}
+	dishes := make([]Dish, 0, len(rest.Dishes))
+	for _, d := range rest.Dishes {
+		dishes = append(dishes, toDish(d))
+	}
+	_, avg := priceTier(dishes)
+
+	km := fakeDistanceKm(rest.ID, in.Address)
+	out := &TripCost{RestaurantID: rest.ID, PartySize: in.PartySize, Transport: transport, DistanceKm: km}
+	add := func(item string, amount float64, note string) {
+		out.Items = append(out.Items, TripCostItem{Item: item, Amount: roundYuan(amount), Note: note})
+	}
+
+	food := avg * float64(in.PartySize)
+	add("food", food, fmt.Sprintf("average dish price %.1f x %d people", avg, in.PartySize))
+
+	switch transport {
+	case "delivery":
+		if rest.Delivery == nil {
+			return nil, fmt.Errorf(`{"error":"delivery unavailable","message":"restaurant %s does not offer delivery, choose another transport","retry":"false"}`, rest.ID)
+		}
+		if km > rest.Delivery.MaxDistanceKm {
+			return nil, fmt.Errorf(`{"error":"delivery unavailable","message":"the address is %.1f km away, out of the delivery range of restaurant %s","retry":"false"}`, km, rest.ID)
+		}
+		add("delivery fee", float64(deliveryFee(rest.Delivery, km)), fmt.Sprintf("%.1f km", km))
+	case "walk":
+		add("transport", 0, fmt.Sprintf("walking %.1f km each way", km))
+		add("service fee", food*serviceFeePercent/100, fmt.Sprintf("%d%% of the food", serviceFeePercent))
+	case "transit":
+		add("transport", float64(transitFarePerLeg*2*in.PartySize), fmt.Sprintf("round trip, %d yuan per person each way", transitFarePerLeg))
+		add("service fee", food*serviceFeePercent/100, fmt.Sprintf("%d%% of the food", serviceFeePercent))
+	case "taxi":
+		cars := (in.PartySize + taxiSeats - 1) / taxiSeats
+		add("transport", taxiFare(km)*2*float64(cars), fmt.Sprintf("round trip, %d taxi(s) for %.1f km", cars, km))
+		add("service fee", food*serviceFeePercent/100, fmt.Sprintf("%d%% of the food", serviceFeePercent))
+	}
+
+	// 按分累加, 避免浮点数的误差 (同 computeBill)
+	var totalCents int64
+	for _, it := range out.Items {
+		totalCents += int64(math.Round(it.Amount * 100))
+	}
+	out.Total = float64(totalCents) / 100
+	out.PerPerson = roundYuan(out.Total / float64(in.PartySize))
+	return out, nil
+}
+
+// taxiFare 是一辆车单程的车费: 起步价含 taxiBaseKm 公里, 之后按公里计费.
+func taxiFare(km float64) float64 {
+	return taxiBaseFare + math.Max(0, km-taxiBaseKm)*taxiFarePerKm
+}
+
+func roundYuan(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
@@ -0,0 +1,64 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tools
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEstimateTripCost(t *testing.T) {
+	ctx := context.Background()
+
+	// 默认打车: 餐费 + 往返车费 + 服务费
+	cost, err := restService.EstimateTripCost(ctx, &TripCostParam{RestaurantID: "1001", PartySize: 2, Address: "王府井"})
+	assert.NoError(t, err)
+	assert.Equal(t, "taxi", cost.Transport)
+	assert.Equal(t, []TripCostItem{
+		{Item: "food", Amount: 36.6, Note: "average dish price 18.3 x 2 people"},
+		{Item: "transport", Amount: 26, Note: "round trip, 1 taxi(s) for 2.8 km"},
+		{Item: "service fee", Amount: 3.66, Note: "10% of the food"},
+	}, cost.Items)
+	assert.Equal(t, 66.26, cost.Total)
+	assert.Equal(t, 33.13, cost.PerPerson)
+
+	// 超过 taxiSeats 人需要两辆车
+	cost, err = restService.EstimateTripCost(ctx, &TripCostParam{RestaurantID: "1001", PartySize: 5, Address: "王府井"})
+	assert.NoError(t, err)
+	assert.Equal(t, 52.0, cost.Items[1].Amount)
+
+	// 外卖没有交通费和服务费, 只有配送费
+	cost, err = restService.EstimateTripCost(ctx, &TripCostParam{RestaurantID: "1001", PartySize: 2, Transport: "Delivery", Address: "王府井"})
+	assert.NoError(t, err)
+	assert.Len(t, cost.Items, 2)
+	assert.Equal(t, TripCostItem{Item: "delivery fee", Amount: 11, Note: "2.8 km"}, cost.Items[1])
+	assert.Equal(t, 47.6, cost.Total)
+
+	cost, err = restService.EstimateTripCost(ctx, &TripCostParam{RestaurantID: "1001", PartySize: 2, Transport: "walk", Address: "王府井"})
+	assert.NoError(t, err)
+	assert.Equal(t, 0.0, cost.Items[1].Amount)
+	assert.Equal(t, 40.26, cost.Total)
+
+	_, err = restService.EstimateTripCost(ctx, &TripCostParam{RestaurantID: "2010", PartySize: 2, Transport: "delivery"})
+	assert.ErrorContains(t, err, "does not offer delivery")
+	_, err = restService.EstimateTripCost(ctx, &TripCostParam{RestaurantID: "1001", PartySize: 2, Transport: "rocket"})
+	assert.ErrorContains(t, err, "unknown transport")
+	_, err = restService.EstimateTripCost(ctx, &TripCostParam{RestaurantID: "1001", PartySize: 0})
+	assert.Error(t, err)
+}
@@ -0,0 +1,97 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"slices"
+
+	"github.com/cloudwego/eino/components/tool"
+	"github.com/cloudwego/eino/components/tool/utils"
+)
+
+func GetUnsaveRestaurantTool() tool.InvokableTool {
+	return safeTool{
+		InvokableTool: NewCancellableTool(newUnsaveRestaurantTool(restService)),
+	}
+}
+
+// newUnsaveRestaurantTool 演示另一种写 tool 的方式: 用 utils.InferTool 直接包装一个普通的函数,
+// 参数的 schema 由 UnsaveRestaurantParam 的 json 和 jsonschema tag 推导 (没有 omitempty 的字段是 required),
+// 不需要像 ToolQueryRestaurants 那样手写 Info 和 InvokableRun.
+// 为了和手写的 tool 表现一致, 参数检查和结果序列化通过 option 换成本包的 checkRequired 和 marshalResult.
+func newUnsaveRestaurantTool(svc *fakeService) tool.InvokableTool {
+	var t tool.InvokableTool
+	t, err := utils.InferTool("unsave_restaurant",
+		"Remove a restaurant from the current user's favorites, the opposite of save_restaurant",
+		svc.UnsaveRestaurant,
+		utils.WithUnmarshalArguments(func(ctx context.Context, arguments string) (any, error) {
+			if err := checkRequired(ctx, t, arguments); err != nil {
+				return nil, err
+			}
+			p := &UnsaveRestaurantParam{}
+			return p, json.Unmarshal([]byte(arguments), p)
+		}),
+		utils.WithMarshalOutput(func(ctx context.Context, output any) (string, error) {
+			res, err := marshalResult(output)
+			return string(res), err
+		}),
+	)
+	if err != nil {
+		// schema 由固定的 struct 推导, 只有改错了 tag 才会走到这里
+		panic(err)
+	}
+	return t
+}
+
+type UnsaveRestaurantParam struct {
+	RestaurantID string `json:"restaurant_id" jsonschema:"description=The id of the restaurant to remove from favorites"`
+}
+
+type UnsaveAck struct {
+	RestaurantID string `json:"restaurant_id"`
+	// NotSaved 为 true 表示这家餐厅本来就不在收藏中, 这次没有任何改变
+	NotSaved   bool   `json:"not_saved"`
+	SavedCount int    `json:"saved_count"`
+	Message    string `json:"message"`
+}
+
+// UnsaveRestaurant 把餐厅从 context 中用户的收藏里去掉, 和 SaveRestaurant 一样是幂等的.
+func (ft *fakeService) UnsaveRestaurant(ctx context.Context, in *UnsaveRestaurantParam) (*UnsaveAck, error) {
+	if err := ft.simulateLatency(ctx); err != nil {
+		return nil, err
+	}
+
+	userID := UserIDFrom(ctx)
+	if userID == "" {
+		return nil, errGuestUser
+	}
+
+	ft.mu.Lock()
+	defer ft.mu.Unlock()
+	ack := &UnsaveAck{RestaurantID: in.RestaurantID}
+	if i := slices.Index(ft.saved[userID], in.RestaurantID); i >= 0 {
+		ft.saved[userID] = slices.Delete(ft.saved[userID], i, i+1)
+		ack.Message = "removed from favorites"
+	} else {
+		ack.NotSaved = true
+		ack.Message = "the restaurant was not in the favorites"
+	}
+	ack.SavedCount = len(ft.saved[userID])
+	return ack, nil
+}
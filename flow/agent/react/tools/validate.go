@@ -0,0 +1,103 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/cloudwego/eino/components/tool"
+)
+
+// decodeArguments 把参数反序列化到 p. 失败时不返回 encoding/json 的原始错误 (比如 "invalid character ..."),
+// 而是返回一个结构化错误, 说明哪个字段的类型不对并列出 t 的全部参数, 模型据此改正参数重新调用.
+func decodeArguments(ctx context.Context, t tool.BaseTool, argumentsInJSON string, p any) error {
+	err := json.Unmarshal([]byte(argumentsInJSON), p)
+	if err == nil {
+		return nil
+	}
+
+	detail := ""
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) && typeErr.Field != "" {
+		detail = fmt.Sprintf(" (%s should be of type %s, got %s)", typeErr.Field, jsonTypeName(typeErr.Type), typeErr.Value)
+	}
+	expected := "see the tool schema"
+	if fields, infoErr := argumentFields(ctx, t); infoErr == nil && len(fields) > 0 {
+		expected = "expected fields: " + strings.Join(fields, ", ")
+	}
+	return malformedArgumentsError(fmt.Sprintf("could not parse arguments%s, %s", detail, expected))
+}
+
+// argumentFields 返回 t 的参数名, 必填的在前并标上 (required), 同一组内按名称排序.
+func argumentFields(ctx context.Context, t tool.BaseTool) ([]string, error) {
+	info, err := t.Info(ctx)
+	if err != nil || info.ParamsOneOf == nil {
+		return nil, err
+	}
+	js, err := info.ParamsOneOf.ToJSONSchema()
+	if err != nil || js == nil || js.Properties == nil {
+		return nil, err
+	}
+
+	var required, optional []string
+	for pair := js.Properties.Oldest(); pair != nil; pair = pair.Next() {
+		if slices.Contains(js.Required, pair.Key) {
+			required = append(required, pair.Key+" (required)")
+		} else {
+			optional = append(optional, pair.Key)
+		}
+	}
+	slices.Sort(required)
+	slices.Sort(optional)
+	return append(required, optional...), nil
+}
+
+// jsonTypeName 把参数结构体中字段的 Go 类型换成 JSON schema 中的说法, 模型看到的是 schema 而不是 Go 代码.
+func jsonTypeName(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "integer"
+	case reflect.Float32, reflect.Float64:
+		return "number"
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Slice, reflect.Array:
+		return "array"
+	case reflect.Map, reflect.Struct:
+		return "object"
+	}
+	return t.Kind().String()
+}
+
+// invalidArgumentError 是参数能解析但取值不合法时的结构化错误, 指明字段和原因. 用同样的参数重试没有意义,
+// 所以 retry 为 false, 内层的 retryTool 不会重试, 模型改正参数后再调用.
+func invalidArgumentError(toolName, field, problem string) error {
+	msg, _ := json.Marshal(map[string]string{
+		"error":   "invalid argument",
+		"field":   field,
+		"message": fmt.Sprintf("%s: %q %s, call it again with a corrected value", toolName, field, problem),
+		"retry":   "false",
+	})
+	return errors.New(string(msg))
+}
@@ -0,0 +1,103 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/cloudwego/eino/components/tool"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateQueryArguments(t *testing.T) {
+	ctx := context.Background()
+	restaurants := &ToolQueryRestaurants{backService: restService}
+	dishes := &ToolQueryDishes{backService: restService}
+
+	// 错误是结构化的 JSON, retry 为 false
+	decode := func(err error) map[string]string {
+		assert.Error(t, err)
+		res := map[string]string{}
+		assert.NoError(t, json.Unmarshal([]byte(err.Error()), &res), err.Error())
+		assert.Equal(t, "false", res["retry"])
+		return res
+	}
+
+	for _, c := range []struct {
+		tool    tool.InvokableTool
+		args    string
+		field   string
+		message string
+	}{
+		{restaurants, `{"location": "北京", "topn": -1}`, "topn", "must not be negative, got -1"},
+		{dishes, `{"restaurant_id": "1001", "topn": -3}`, "topn", "must not be negative, got -3"},
+	} {
+		_, err := c.tool.InvokableRun(ctx, c.args)
+		res := decode(err)
+		assert.Equal(t, "invalid argument", res["error"], c.args)
+		assert.Equal(t, c.field, res["field"], c.args)
+		assert.Contains(t, res["message"], c.message, c.args)
+	}
+
+	// 不存在、为 null、空字符串和只有空白都由 validate 报告为缺少
+	for _, c := range []struct {
+		tool  tool.InvokableTool
+		args  string
+		field string
+	}{
+		{restaurants, `{"topn": 2}`, "location"},
+		{restaurants, `{"location": null}`, "location"},
+		{restaurants, `{"location": ""}`, "location"},
+		{restaurants, `{"location": "  "}`, "location"},
+		{dishes, `{"restaurant_id": " "}`, "restaurant_id"},
+	} {
+		_, err := c.tool.InvokableRun(ctx, c.args)
+		res := decode(err)
+		assert.Equal(t, "missing required argument", res["error"], c.args)
+		assert.Equal(t, c.field, res["field"], c.args)
+	}
+
+	var err error
+
+	// 不是合法的 JSON 时列出全部参数, 必填的在前
+	res := decode(func() error { _, err := restaurants.InvokableRun(ctx, `{"location": "北京", topn: 2`); return err }())
+	assert.Equal(t, "malformed arguments", res["error"])
+	assert.Equal(t, "could not parse arguments, expected fields: location (required), accessible_only, min_hygiene_grade, page, page_size, topn", res["message"])
+	assert.NotContains(t, res["message"], "invalid character")
+
+	// 和 jsonArgsTool 拦下的参数是同一个错误码, retry 也相同
+	out, err := NewJSONArgsTool(restaurants).InvokableRun(ctx, `{"location": "北京", topn: 2`)
+	assert.NoError(t, err)
+	viaJSONArgs := map[string]string{}
+	assert.NoError(t, json.Unmarshal([]byte(out), &viaJSONArgs))
+	assert.Equal(t, res["error"], viaJSONArgs["error"])
+	assert.Equal(t, res["retry"], viaJSONArgs["retry"])
+
+	// 类型不对时指出字段
+	res = decode(func() error {
+		_, err := dishes.InvokableRun(ctx, `{"restaurant_id": "1001", "topn": "three"}`)
+		return err
+	}())
+	assert.Contains(t, res["message"], "could not parse arguments (topn should be of type integer, got string)")
+	assert.Contains(t, res["message"], "expected fields: restaurant_id (required), ")
+
+	// 合法的参数不受影响
+	_, err = restaurants.InvokableRun(ctx, `{"location": "北京", "topn": 0}`)
+	assert.NoError(t, err)
+}
@@ -0,0 +1,162 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/cloudwego/eino/components/tool"
+	"github.com/cloudwego/eino/schema"
+)
+
+func GetWalkInPolicyTool() tool.InvokableTool {
+	return safeTool{
+		InvokableTool: NewCancellableTool(&ToolWalkInPolicy{
+			backService: restService,
+		}),
+	}
+}
+
+// ToolWalkInPolicy 回答 "现在直接过去能吃上吗": 餐厅接不接待没有预订的客人, 接待的话现在和今天高峰时大概要等多久.
+// 等位时间和 query_table_eta 一样由 busyness 和 tableWaitMinutes 估算.
+type ToolWalkInPolicy struct {
+	backService *fakeService // fake service
+}
+
+func (t *ToolWalkInPolicy) Info(ctx context.Context) (*schema.ToolInfo, error) {
+	return &schema.ToolInfo{
+		Name: "query_walk_in_policy",
+		Desc: "Check whether a restaurant accepts walk-ins without a reservation, and if so the typical wait for a table right now " +
+			"and at today's peak. Reservation-only restaurants must be booked with book_table",
+		ParamsOneOf: schema.NewParamsOneOfByParams(map[string]*schema.ParameterInfo{
+			"restaurant_id": {
+				Type:     "string",
+				Desc:     "The id of one restaurant",
+				Required: true,
+			},
+		}),
+	}, nil
+}
+
+func (t *ToolWalkInPolicy) InvokableRun(ctx context.Context, argumentsInJSON string, opts ...tool.Option) (string, error) {
+	// 解析参数
+	if err := checkRequired(ctx, t, argumentsInJSON); err != nil {
+		return "", err
+	}
+	p := &WalkInPolicyParam{}
+	err := json.Unmarshal([]byte(argumentsInJSON), p)
+	if err != nil {
+		return "", err
+	}
+
+	// 请求后端服务
+	policy, err := t.backService.WalkInPolicy(ctx, p)
+	if err != nil {
+		return "", err
+	}
+
+	// 序列化结果
+	res, err := marshalResult(policy)
+	if err != nil {
+		return "", err
+	}
+
+	return string(res), nil
+}
+
+type WalkInPolicyParam struct {
+	RestaurantID string `json:"restaurant_id"`
+}
+
+// WalkInPolicy 中 policy 为 walk-ins welcome 或 reservation only. 只有接待 walk-in 并且现在营业时才有 wait_minutes,
+// 只有接待 walk-in 并且今天营业时才有 peak_hour 和 peak_wait_minutes.
+type WalkInPolicy struct {
+	RestaurantID string `json:"restaurant_id"`
+	Name         string `json:"name"`
+	WalkIns      bool   `json:"walk_ins"`
+	Policy       string `json:"policy"`
+	Open         bool   `json:"open"`
+	// WaitMinutes 是 walkInPartySize 个人现在到店大概要等的分钟数
+	WaitMinutes     *int   `json:"wait_minutes,omitempty"`
+	PeakHour        string `json:"peak_hour,omitempty"` // 15:04
+	PeakWaitMinutes *int   `json:"peak_wait_minutes,omitempty"`
+	Message         string `json:"message"`
+}
+
+// walkInPartySize 是估算 walk-in 等位时间时的人数, 临时起意出去吃饭大多是两个人.
+const walkInPartySize = 2
+
+// WalkInPolicy 查询餐厅的 walk-in 政策, 接待 walk-in 时按 fake 后端的 clock 估算现在和今天高峰时的等位时间.
+func (ft *fakeService) WalkInPolicy(ctx context.Context, in *WalkInPolicyParam) (*WalkInPolicy, error) {
+	if err := ft.simulateLatency(ctx); err != nil {
+		return nil, err
+	}
+
+	rest, err := ft.repo.GetRestaurantByID(ctx, in.RestaurantID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := ft.now()
+	closedToday := slices.Contains(rest.ClosedOn, now.Weekday())
+	out := &WalkInPolicy{
+		RestaurantID: rest.ID,
+		Name:         rest.Name,
+		WalkIns:      rest.WalkIns,
+		Open:         !closedToday && now.Hour() >= busyFirstHour && now.Hour() <= busyLastHour,
+	}
+	if !rest.WalkIns {
+		out.Policy = "reservation only"
+		out.Message = "the restaurant does not accept walk-ins, book a table in advance with book_table"
+		return out, nil
+	}
+	out.Policy = "walk-ins welcome"
+	if closedToday {
+		out.Message = fmt.Sprintf("walk-ins are welcome, but the restaurant is closed on %s", strings.ToLower(now.Weekday().String()))
+		return out, nil
+	}
+
+	turn := tableTurnMinutes(rest.Dishes)
+	peakHour, peakBusy := busyFirstHour, -1
+	for hour := busyFirstHour; hour <= busyLastHour; hour++ {
+		if busy := busyness(rest.ID, now.Weekday(), hour); busy > peakBusy {
+			peakHour, peakBusy = hour, busy
+		}
+	}
+	peakWait := tableWaitMinutes(turn, peakBusy, walkInPartySize)
+	out.PeakHour = fmt.Sprintf("%02d:00", peakHour)
+	out.PeakWaitMinutes = &peakWait
+
+	if !out.Open {
+		out.Message = fmt.Sprintf("walk-ins are welcome from %02d:00 to %02d:59, it is closed now; expect about %d minutes at the %s peak",
+			busyFirstHour, busyLastHour, peakWait, out.PeakHour)
+		return out, nil
+	}
+	wait := tableWaitMinutes(turn, busyness(rest.ID, now.Weekday(), now.Hour()), walkInPartySize)
+	out.WaitMinutes = &wait
+	if wait == 0 {
+		out.Message = fmt.Sprintf("walk-ins are welcome and there are free tables now; expect about %d minutes at the %s peak", peakWait, out.PeakHour)
+	} else {
+		out.Message = fmt.Sprintf("walk-ins are welcome, the wait for %d is about %d minutes now and %d minutes at the %s peak",
+			walkInPartySize, wait, peakWait, out.PeakHour)
+	}
+	return out, nil
+}
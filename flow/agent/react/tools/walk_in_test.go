@@ -0,0 +1,85 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tools
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWalkInPolicy(t *testing.T) {
+	ctx := context.Background()
+	loc := time.FixedZone("CST", 8*3600)
+	at := func(day, hour int) *fakeService {
+		// 2024-06-01 是周六, 2024-06-03 是周一
+		return &fakeService{repo: database, clock: FixedClock{T: time.Date(2024, 6, day, hour, 30, 0, 0, loc)}}
+	}
+
+	// 周六晚高峰要等位, 等位时间和 query_table_eta 的估算一致
+	peak, err := at(1, 19).WalkInPolicy(ctx, &WalkInPolicyParam{RestaurantID: "1001"})
+	assert.NoError(t, err)
+	assert.True(t, peak.WalkIns)
+	assert.Equal(t, "walk-ins welcome", peak.Policy)
+	assert.True(t, peak.Open)
+	eta, err := at(1, 19).TableETA(ctx, &TableETAParam{RestaurantID: "1001", PartySize: walkInPartySize})
+	assert.NoError(t, err)
+	if assert.NotNil(t, peak.WaitMinutes) {
+		assert.Equal(t, eta.ETAMinutes, *peak.WaitMinutes)
+		assert.Positive(t, *peak.WaitMinutes)
+	}
+	assert.Equal(t, "19:00", peak.PeakHour)
+
+	// 下午有空桌, 不用等, 但仍然给出高峰时的等位时间
+	afternoon, err := at(1, 15).WalkInPolicy(ctx, &WalkInPolicyParam{RestaurantID: "1001"})
+	assert.NoError(t, err)
+	if assert.NotNil(t, afternoon.WaitMinutes) {
+		assert.Zero(t, *afternoon.WaitMinutes)
+	}
+	assert.Equal(t, peak.PeakWaitMinutes, afternoon.PeakWaitMinutes)
+	assert.Contains(t, afternoon.Message, "free tables")
+
+	// 只接受预订的餐厅没有等位时间, 提示用 book_table
+	reserved, err := at(1, 19).WalkInPolicy(ctx, &WalkInPolicyParam{RestaurantID: "1003"})
+	assert.NoError(t, err)
+	assert.False(t, reserved.WalkIns)
+	assert.Equal(t, "reservation only", reserved.Policy)
+	assert.Nil(t, reserved.WaitMinutes)
+	assert.Nil(t, reserved.PeakWaitMinutes)
+	assert.Contains(t, reserved.Message, "book_table")
+
+	// 营业时间外
+	early, err := at(1, 8).WalkInPolicy(ctx, &WalkInPolicyParam{RestaurantID: "1001"})
+	assert.NoError(t, err)
+	assert.False(t, early.Open)
+	assert.Nil(t, early.WaitMinutes)
+	assert.NotNil(t, early.PeakWaitMinutes)
+	assert.Contains(t, early.Message, "closed now")
+
+	// 1002 周一休息
+	monday, err := at(3, 19).WalkInPolicy(ctx, &WalkInPolicyParam{RestaurantID: "1002"})
+	assert.NoError(t, err)
+	assert.True(t, monday.WalkIns)
+	assert.False(t, monday.Open)
+	assert.Nil(t, monday.PeakWaitMinutes)
+	assert.Contains(t, monday.Message, "closed on monday")
+
+	_, err = at(1, 19).WalkInPolicy(ctx, &WalkInPolicyParam{RestaurantID: "404"})
+	assert.Error(t, err)
+}
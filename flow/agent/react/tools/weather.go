@@ -0,0 +1,121 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"hash/fnv"
+
+	"github.com/cloudwego/eino/components/tool"
+	"github.com/cloudwego/eino/schema"
+)
+
+func GetWeatherTool() tool.InvokableTool {
+	return safeTool{
+		InvokableTool: NewCancellableTool(&ToolQueryWeather{
+			backService: restService,
+		}),
+	}
+}
+
+// ToolQueryWeather 提供餐厅数据之外的外部信息: 当前天气, 让模型决定是否推荐室外座位.
+type ToolQueryWeather struct {
+	backService *fakeService // fake service
+}
+
+func (t *ToolQueryWeather) Info(ctx context.Context) (*schema.ToolInfo, error) {
+	return &schema.ToolInfo{
+		Name: "query_weather",
+		Desc: "Query the current weather of a location, to decide whether outdoor or patio seating is a good idea",
+		ParamsOneOf: schema.NewParamsOneOfByParams(map[string]*schema.ParameterInfo{
+			"location": {
+				Type:     "string",
+				Desc:     "The city, e.g. 北京",
+				Required: true,
+			},
+		}),
+	}, nil
+}
+
+func (t *ToolQueryWeather) InvokableRun(ctx context.Context, argumentsInJSON string, opts ...tool.Option) (string, error) {
+	// 解析参数
+	if err := checkRequired(ctx, t, argumentsInJSON); err != nil {
+		return "", err
+	}
+	p := &QueryWeatherParam{}
+	err := json.Unmarshal([]byte(argumentsInJSON), p)
+	if err != nil {
+		return "", err
+	}
+
+	// 请求后端服务
+	w, err := t.backService.QueryWeather(ctx, p)
+	if err != nil {
+		return "", err
+	}
+
+	// 序列化结果
+	res, err := marshalResult(w)
+	if err != nil {
+		return "", err
+	}
+
+	return string(res), nil
+}
+
+type QueryWeatherParam struct {
+	Location string `json:"location"`
+}
+
+type Weather struct {
+	Location     string `json:"location"`
+	Date         string `json:"date"` // 2006-01-02
+	TemperatureC int    `json:"temperature_c"`
+	Condition    string `json:"condition"` // sunny, cloudy, overcast, light_rain
+	// OutdoorSeatingSuitable 为 true 表示不下雨并且温度在 18 - 30 度之间, 适合坐在室外
+	OutdoorSeatingSuitable bool `json:"outdoor_seating_suitable"`
+}
+
+var weatherConditions = []string{"sunny", "cloudy", "overcast", "light_rain"}
+
+// QueryWeather 返回 location 在 fake 后端当前日期的天气, 同一个 location 同一天的结果总是相同.
+func (ft *fakeService) QueryWeather(ctx context.Context, in *QueryWeatherParam) (*Weather, error) {
+	if err := ft.simulateLatency(ctx); err != nil {
+		return nil, err
+	}
+
+	date := ft.now().Format("2006-01-02")
+	w := fakeWeather(in.Location, date)
+	return &w, nil
+}
+
+// fakeWeather 由 location 和日期的哈希决定天气, 温度范围 5 - 34 度.
+func fakeWeather(location, date string) Weather {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(location + "|" + date))
+	sum := h.Sum32()
+
+	w := Weather{
+		Location:     location,
+		Date:         date,
+		TemperatureC: 5 + int(sum%30),
+		Condition:    weatherConditions[(sum/30)%uint32(len(weatherConditions))],
+	}
+	w.OutdoorSeatingSuitable = w.Condition != "light_rain" && w.TemperatureC >= 18 && w.TemperatureC <= 30
+	return w
+}
@@ -0,0 +1,64 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tools
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFakeWeather(t *testing.T) {
+	w := fakeWeather("北京", "2025-06-01")
+	assert.Equal(t, w, fakeWeather("北京", "2025-06-01"))
+	assert.GreaterOrEqual(t, w.TemperatureC, 5)
+	assert.LessOrEqual(t, w.TemperatureC, 34)
+	assert.Contains(t, weatherConditions, w.Condition)
+
+	// 不同的日期和城市会得到不同的天气
+	seen := map[Weather]bool{}
+	for _, date := range []string{"2025-06-01", "2025-06-02", "2025-06-03", "2025-06-04"} {
+		for _, location := range []string{"北京", "上海"} {
+			w := fakeWeather(location, date)
+			w.Location, w.Date = "", ""
+			seen[w] = true
+		}
+	}
+	assert.Greater(t, len(seen), 1)
+
+	// 下雨或者太冷太热时不适合室外
+	for day := 1; day <= 30; day++ {
+		w := fakeWeather("上海", time.Date(2025, 6, day, 0, 0, 0, 0, time.UTC).Format("2006-01-02"))
+		if w.Condition == "light_rain" || w.TemperatureC < 18 || w.TemperatureC > 30 {
+			assert.False(t, w.OutdoorSeatingSuitable, w)
+		} else {
+			assert.True(t, w.OutdoorSeatingSuitable, w)
+		}
+	}
+}
+
+func TestQueryWeather(t *testing.T) {
+	ctx := context.Background()
+	svc := &fakeService{repo: database, clock: FixedClock{T: time.Date(2025, 6, 1, 12, 0, 0, 0, time.Local)}}
+
+	w, err := svc.QueryWeather(ctx, &QueryWeatherParam{Location: "北京"})
+	assert.NoError(t, err)
+	assert.Equal(t, "2025-06-01", w.Date)
+	assert.Equal(t, fakeWeather("北京", "2025-06-01"), *w)
+}
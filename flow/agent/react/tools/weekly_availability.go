@@ -0,0 +1,224 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/cloudwego/eino/components/tool"
+	"github.com/cloudwego/eino/schema"
+)
+
+func GetWeeklyAvailabilityTool() tool.InvokableTool {
+	return safeTool{
+		InvokableTool: NewCancellableTool(&ToolWeeklyAvailability{
+			backService: restService,
+		}),
+	}
+}
+
+// ToolWeeklyAvailability 把 7 天的可预订时段汇总成每天一个评级, 回答 "这周哪天去比较好订" 这类问题,
+// 模型不需要逐天调用 query_best_reservation_time 再自己比较.
+type ToolWeeklyAvailability struct {
+	backService *fakeService // fake service
+}
+
+func (t *ToolWeeklyAvailability) Info(ctx context.Context) (*schema.ToolInfo, error) {
+	return &schema.ToolInfo{
+		Name: "query_weekly_availability",
+		Desc: "Summarize how easy it is to book a table at a restaurant on each of the next 7 days, starting today. " +
+			"Each day is rated good, limited, none or closed, with the quietest bookable time",
+		ParamsOneOf: schema.NewParamsOneOfByParams(map[string]*schema.ParameterInfo{
+			"restaurant_id": {
+				Type:     "string",
+				Desc:     "The id of one restaurant",
+				Required: true,
+			},
+			"party_size": {
+				Type:     "integer",
+				Desc:     "How many people the table is for",
+				Required: true,
+			},
+		}),
+	}, nil
+}
+
+func (t *ToolWeeklyAvailability) InvokableRun(ctx context.Context, argumentsInJSON string, opts ...tool.Option) (string, error) {
+	// 解析参数
+	if err := checkRequired(ctx, t, argumentsInJSON); err != nil {
+		return "", err
+	}
+	p := &WeeklyAvailabilityParam{}
+	err := json.Unmarshal([]byte(argumentsInJSON), p)
+	if err != nil {
+		return "", err
+	}
+
+	// 请求后端服务
+	week, err := t.backService.WeeklyAvailability(ctx, p)
+	if err != nil {
+		return "", err
+	}
+
+	// 序列化结果
+	res, err := marshalResult(week)
+	if err != nil {
+		return "", err
+	}
+
+	return string(res), nil
+}
+
+type WeeklyAvailabilityParam struct {
+	RestaurantID string `json:"restaurant_id"`
+	PartySize    int    `json:"party_size"`
+}
+
+// WeeklyAvailability 的 days 从今天开始, 共 availabilityDays 天. 没有一天评级为 good 时没有 best_day.
+type WeeklyAvailability struct {
+	RestaurantID string            `json:"restaurant_id"`
+	PartySize    int               `json:"party_size"`
+	Days         []DayAvailability `json:"days"`
+	BestDay      string            `json:"best_day,omitempty"` // 2006-01-02
+	Message      string            `json:"message"`
+}
+
+// DayAvailability 中 rating 为:
+//   - closed: 餐厅这一天休息
+//   - good: 午餐和晚餐的每个整点 (availabilityMealHours) 都能预订
+//   - limited: 能预订, 但有的饭点已经订满, 或者今天的饭点已经过去
+//   - none: 这一天没有能预订的整点, 原因在 note 中
+type DayAvailability struct {
+	Date          string `json:"date"` // 2006-01-02
+	Day           string `json:"day"`
+	Rating        string `json:"rating"`
+	BookableHours int    `json:"bookable_hours"`
+	// MealBusyness 是还没过去的饭点的平均繁忙程度, 0 - 100, 越低越容易订到好位置
+	MealBusyness int    `json:"meal_busyness,omitempty"`
+	BestTime     string `json:"best_time,omitempty"` // 15:04, 这一天最闲的可预订整点
+	Note         string `json:"note,omitempty"`
+}
+
+// availabilityDays 是汇总的天数.
+const availabilityDays = 7
+
+// availabilityMealHours 是评级时看的午餐和晚餐时间, 其他时间大多有空位, 不影响评级.
+var availabilityMealHours = []int{11, 12, 13, 18, 19, 20}
+
+// WeeklyAvailability 对从今天开始的每一天, 逐个检查 busyFirstHour 到 busyLastHour 的整点能不能预订
+// (reservationTimeProblem 和 unavailableReason, 同 suggest_alternative), 再按饭点能不能预订评级.
+// 今天只算还没过去的整点. 日期以 fake 后端的 clock 为准.
+func (ft *fakeService) WeeklyAvailability(ctx context.Context, in *WeeklyAvailabilityParam) (*WeeklyAvailability, error) {
+	if err := ft.simulateLatency(ctx); err != nil {
+		return nil, err
+	}
+
+	if in.PartySize <= 0 || in.PartySize > maxPartySize {
+		return nil, fmt.Errorf("party_size must be between 1 and %d, got %d", maxPartySize, in.PartySize)
+	}
+	rest, err := ft.repo.GetRestaurantByID(ctx, in.RestaurantID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := ft.now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	out := &WeeklyAvailability{RestaurantID: rest.ID, PartySize: in.PartySize, Days: make([]DayAvailability, 0, availabilityDays)}
+	// 最好的一天是饭点最闲的 good, 同样闲时取较早的一天
+	bestBusy := -1
+	var good []string
+	for i := 0; i < availabilityDays; i++ {
+		day := dayAvailability(&rest, today.AddDate(0, 0, i), now, in.PartySize)
+		out.Days = append(out.Days, day)
+		if day.Rating != "good" {
+			continue
+		}
+		good = append(good, day.Day)
+		if bestBusy < 0 || day.MealBusyness < bestBusy {
+			bestBusy, out.BestDay = day.MealBusyness, day.Date
+		}
+	}
+
+	if len(good) == 0 {
+		out.Message = fmt.Sprintf("no day in the next %d has good availability for %d people", availabilityDays, in.PartySize)
+		return out, nil
+	}
+	out.Message = fmt.Sprintf("good availability on %s, the best day is %s", strings.Join(good, ", "), out.BestDay)
+	return out, nil
+}
+
+// dayAvailability 汇总 rest 在 date 这一天的可预订情况, now 之前的整点不能预订.
+func dayAvailability(rest *restaurantDataItem, date, now time.Time, partySize int) DayAvailability {
+	out := DayAvailability{Date: date.Format(reservationDateLayout), Day: strings.ToLower(date.Weekday().String())}
+	if slices.Contains(rest.ClosedOn, date.Weekday()) {
+		out.Rating = "closed"
+		out.Note = fmt.Sprintf("the restaurant is closed on %s", out.Day)
+		return out
+	}
+
+	quietest, reason := -1, ""
+	meals, mealBusy := 0, 0
+	var full []string // 订满的饭点
+	for hour := busyFirstHour; hour <= busyLastHour; hour++ {
+		at := date.Add(time.Duration(hour) * time.Hour)
+		if problem := reservationTimeProblem(at, now); problem != "" {
+			reason = problem
+			continue
+		}
+		busy := busyness(rest.ID, date.Weekday(), hour)
+		if slices.Contains(availabilityMealHours, hour) {
+			meals++
+			mealBusy += busy
+		}
+		if reason = unavailableReason(rest, at, partySize); reason != "" {
+			if slices.Contains(availabilityMealHours, hour) {
+				full = append(full, fmt.Sprintf("%02d:00", hour))
+			}
+			continue
+		}
+		out.BookableHours++
+		if quietest < 0 || busy < quietest {
+			quietest = busy
+			out.BestTime = fmt.Sprintf("%02d:00", hour)
+		}
+	}
+	if meals > 0 {
+		out.MealBusyness = int(math.Round(float64(mealBusy) / float64(meals)))
+	}
+
+	switch {
+	case out.BookableHours == 0:
+		out.Rating = "none"
+		// 最后一个整点的原因最能说明问题: 已经过去、订满或者桌子太小
+		out.Note = reason
+	case len(full) > 0:
+		out.Rating = "limited"
+		out.Note = "fully booked at " + strings.Join(full, ", ")
+	case meals == 0:
+		out.Rating = "limited"
+		out.Note = "lunch and dinner are already over today"
+	default:
+		out.Rating = "good"
+	}
+	return out
+}
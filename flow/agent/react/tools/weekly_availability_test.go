@@ -0,0 +1,77 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tools
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWeeklyAvailability(t *testing.T) {
+	ctx := context.Background()
+	loc := time.FixedZone("CST", 8*3600)
+	// 2024-06-01 是星期六
+	at := func(hour int) *fakeService {
+		return &fakeService{repo: database, clock: FixedClock{T: time.Date(2024, 6, 1, hour, 30, 0, 0, loc)}}
+	}
+
+	week, err := at(9).WeeklyAvailability(ctx, &WeeklyAvailabilityParam{RestaurantID: "1002", PartySize: 4})
+	assert.NoError(t, err)
+	if assert.Len(t, week.Days, 7) {
+		// 周末晚上 19 点订满
+		assert.Equal(t, DayAvailability{
+			Date: "2024-06-01", Day: "saturday", Rating: "limited", BookableHours: 12, MealBusyness: 61, BestTime: "15:00",
+			Note: "fully booked at 19:00",
+		}, week.Days[0])
+		// 1002 每周一休息
+		assert.Equal(t, "closed", week.Days[2].Rating)
+		assert.Contains(t, week.Days[2].Note, "closed on monday")
+		assert.Equal(t, "good", week.Days[3].Rating)
+		assert.Equal(t, 13, week.Days[3].BookableHours)
+		assert.Equal(t, "2024-06-07", week.Days[6].Date)
+	}
+	assert.Equal(t, "2024-06-04", week.BestDay)
+	assert.Contains(t, week.Message, "good availability on tuesday, wednesday, thursday, friday")
+
+	// 今天只算还没过去的整点
+	week, err = at(21).WeeklyAvailability(ctx, &WeeklyAvailabilityParam{RestaurantID: "1001", PartySize: 2})
+	assert.NoError(t, err)
+	assert.Equal(t, "limited", week.Days[0].Rating)
+	assert.Equal(t, 1, week.Days[0].BookableHours)
+	assert.Contains(t, week.Days[0].Note, "already over today")
+	week, err = at(23).WeeklyAvailability(ctx, &WeeklyAvailabilityParam{RestaurantID: "1001", PartySize: 2})
+	assert.NoError(t, err)
+	assert.Equal(t, "none", week.Days[0].Rating)
+
+	// 1003 最大的桌子坐 6 人
+	week, err = at(9).WeeklyAvailability(ctx, &WeeklyAvailabilityParam{RestaurantID: "1003", PartySize: 8})
+	assert.NoError(t, err)
+	for _, day := range week.Days {
+		assert.Equal(t, "none", day.Rating)
+		assert.Contains(t, day.Note, "does not fit")
+	}
+	assert.Empty(t, week.BestDay)
+	assert.Contains(t, week.Message, "no day in the next 7")
+
+	_, err = at(9).WeeklyAvailability(ctx, &WeeklyAvailabilityParam{RestaurantID: "1001", PartySize: 0})
+	assert.ErrorContains(t, err, "party_size")
+	_, err = at(9).WeeklyAvailability(ctx, &WeeklyAvailabilityParam{RestaurantID: "9999", PartySize: 2})
+	assert.Error(t, err)
+}
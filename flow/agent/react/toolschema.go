@@ -0,0 +1,87 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/cloudwego/eino/components/tool"
+	"github.com/eino-contrib/jsonschema"
+)
+
+// toolSchemaSnapshot 是所有 tool 参数的快照, 用来发现参数变了却没有增加 tools.SchemaVersion 的情况.
+type toolSchemaSnapshot struct {
+	SchemaVersion int                 `json:"schema_version"`
+	Tools         map[string][]string `json:"tools"`
+}
+
+// toolParamSets 返回每个 tool 排好序的参数列表, 每项是 "名字 类型", 必填的参数后面带 " required".
+// 对象参数的子参数用 "." 连接名字, 数组元素的子参数用 "[]." 连接, 比如 items[].price. 参数的描述不算在内, 只改描述不需要增加版本.
+func toolParamSets(ctx context.Context, agentTools []tool.BaseTool) (map[string][]string, error) {
+	res := make(map[string][]string, len(agentTools))
+	for _, t := range agentTools {
+		info, err := t.Info(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		params := []string{}
+		if info.ParamsOneOf != nil {
+			js, err := info.ParamsOneOf.ToJSONSchema()
+			if err != nil {
+				return nil, fmt.Errorf("failed to get the schema of %s: %w", info.Name, err)
+			}
+			params = appendParams(params, "", js)
+		}
+		sort.Strings(params)
+		res[info.Name] = params
+	}
+	return res, nil
+}
+
+// appendParams 把 js 的子参数加到 params 中, path 是 js 自己的名字, 顶层为空.
+func appendParams(params []string, path string, js *jsonschema.Schema) []string {
+	if js == nil {
+		return params
+	}
+	if js.Type == "array" && js.Items != nil {
+		return appendParams(params, path+"[]", js.Items)
+	}
+	if js.Properties == nil {
+		return params
+	}
+
+	required := map[string]bool{}
+	for _, name := range js.Required {
+		required[name] = true
+	}
+	for pair := js.Properties.Oldest(); pair != nil; pair = pair.Next() {
+		name := pair.Key
+		if path != "" {
+			name = path + "." + pair.Key
+		}
+		param := name + " " + schemaType(pair.Value)
+		if required[pair.Key] {
+			param += " required"
+		}
+		params = append(params, param)
+		params = appendParams(params, name, pair.Value)
+	}
+	return params
+}
@@ -0,0 +1,96 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"os"
+	"testing"
+
+	"github.com/cloudwego/eino-examples/flow/agent/react/tools"
+	"github.com/stretchr/testify/assert"
+)
+
+var updateSchemas = flag.Bool("update-schemas", false, "rewrite testdata/tool_schemas.json with the current tool parameters")
+
+const toolSchemasFile = "testdata/tool_schemas.json"
+
+// TestToolSchemaSnapshot 对比当前 tool 的参数和快照: 参数变了而 tools.SchemaVersion 没变时失败.
+// 增加版本以后用 -update-schemas 重新生成快照.
+func TestToolSchemaSnapshot(t *testing.T) {
+	current, err := toolParamSets(context.Background(), defaultTools())
+	assert.NoError(t, err)
+
+	if *updateSchemas {
+		// 不转义 <>, 快照中的 array<string> 保持可读
+		var buf bytes.Buffer
+		enc := json.NewEncoder(&buf)
+		enc.SetEscapeHTML(false)
+		enc.SetIndent("", "  ")
+		assert.NoError(t, enc.Encode(toolSchemaSnapshot{SchemaVersion: tools.SchemaVersion, Tools: current}))
+		assert.NoError(t, os.WriteFile(toolSchemasFile, buf.Bytes(), 0o644))
+		return
+	}
+
+	b, err := os.ReadFile(toolSchemasFile)
+	if !assert.NoError(t, err, "run go test -run TestToolSchemaSnapshot -update-schemas to create the snapshot") {
+		return
+	}
+	var snapshot toolSchemaSnapshot
+	assert.NoError(t, json.Unmarshal(b, &snapshot))
+
+	if snapshot.SchemaVersion != tools.SchemaVersion {
+		t.Errorf("tools.SchemaVersion is %d but the snapshot is for version %d, run go test -run TestToolSchemaSnapshot -update-schemas",
+			tools.SchemaVersion, snapshot.SchemaVersion)
+		return
+	}
+	// 逐个 tool 对比, 失败信息只包含变化的 tool
+	names := map[string]bool{}
+	for name := range snapshot.Tools {
+		names[name] = true
+	}
+	for name := range current {
+		names[name] = true
+	}
+	for name := range names {
+		assert.Equal(t, snapshot.Tools[name], current[name],
+			"parameters of %s changed without bumping tools.SchemaVersion (%d), bump it and run go test -run TestToolSchemaSnapshot -update-schemas",
+			name, tools.SchemaVersion)
+	}
+}
+
+func TestToolParamSets(t *testing.T) {
+	sets, err := toolParamSets(context.Background(), defaultTools())
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"party_size integer required", "restaurant_id string required"}, sets["plan_cheapest_group_meal"])
+	assert.Contains(t, sets["compute_bill"], "prices array<number> required")
+	assert.Contains(t, sets["compute_bill"], "tip_percent number")
+}
+
+func TestSchemaVersionInInfo(t *testing.T) {
+	for _, it := range defaultTools() {
+		info, err := it.Info(context.Background())
+		assert.NoError(t, err)
+		if info.Name == "format_menu" && *streamTools {
+			continue
+		}
+		assert.Equal(t, tools.SchemaVersion, info.Extra[tools.SchemaVersionKey], info.Name)
+	}
+}
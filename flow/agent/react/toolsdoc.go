@@ -0,0 +1,82 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/cloudwego/eino/components/tool"
+	"github.com/eino-contrib/jsonschema"
+)
+
+// toolsMarkdown 通过每个 tool 的 Info 生成 Markdown 文档: 每个 tool 一个小节, 参数列成一张表.
+func toolsMarkdown(ctx context.Context, agentTools []tool.BaseTool) (string, error) {
+	var sb strings.Builder
+	for _, t := range agentTools {
+		info, err := t.Info(ctx)
+		if err != nil {
+			return "", err
+		}
+
+		fmt.Fprintf(&sb, "## %s\n\n%s\n\n", info.Name, info.Desc)
+
+		var js *jsonschema.Schema
+		if info.ParamsOneOf != nil {
+			if js, err = info.ParamsOneOf.ToJSONSchema(); err != nil {
+				return "", fmt.Errorf("failed to get the schema of %s: %w", info.Name, err)
+			}
+		}
+		if js == nil || js.Properties == nil || js.Properties.Len() == 0 {
+			sb.WriteString("No parameters.\n\n")
+			continue
+		}
+
+		required := map[string]bool{}
+		for _, name := range js.Required {
+			required[name] = true
+		}
+		var names []string
+		for pair := js.Properties.Oldest(); pair != nil; pair = pair.Next() {
+			names = append(names, pair.Key)
+		}
+		sort.Strings(names)
+
+		sb.WriteString("| Name | Type | Required | Description |\n")
+		sb.WriteString("| --- | --- | --- | --- |\n")
+		for _, name := range names {
+			param, _ := js.Properties.Get(name)
+			fmt.Fprintf(&sb, "| %s | %s | %v | %s |\n", name, schemaType(param), required[name],
+				strings.ReplaceAll(param.Description, "|", `\|`))
+		}
+		sb.WriteString("\n")
+	}
+	return sb.String(), nil
+}
+
+// schemaType 返回参数的类型, 数组会带上元素类型, 比如 array<number>.
+func schemaType(s *jsonschema.Schema) string {
+	if s.Type == "array" && s.Items != nil {
+		return "array<" + schemaType(s.Items) + ">"
+	}
+	if s.Type == "" && len(s.TypeEnhanced) > 0 {
+		return strings.Join(s.TypeEnhanced, " \\| ")
+	}
+	return s.Type
+}
@@ -0,0 +1,35 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestToolsMarkdown(t *testing.T) {
+	doc, err := toolsMarkdown(context.Background(), defaultTools())
+	assert.NoError(t, err)
+
+	assert.Contains(t, doc, "## query_restaurants\n")
+	assert.Contains(t, doc, "| Name | Type | Required | Description |")
+	assert.Contains(t, doc, "| restaurant_id | string | true | The id of one restaurant |")
+	assert.Contains(t, doc, "| prices | array<number> | true |")
+	assert.Contains(t, doc, "| tip_percent | number | false |")
+}
@@ -0,0 +1,135 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/cloudwego/eino/callbacks"
+	"github.com/cloudwego/eino/components"
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/schema"
+)
+
+// TTFTCallback 统计流式模式下每次 ChatModel 调用的 time-to-first-token: 从 OnStart 到第一帧带 content 的输出.
+// 只带 tool call 的帧不算, 所以只发起 tool call 的模型调用没有 TTFT. generate 模式下不统计.
+type TTFTCallback struct {
+	// Out 是输出, 为空时输出到 os.Stdout.
+	Out io.Writer
+
+	mu    sync.Mutex
+	calls int
+	ttfts []time.Duration
+
+	wg sync.WaitGroup // 跟踪 OnEndWithStreamOutput 中启动的 goroutine
+}
+
+type ttftStartKey struct{}
+
+func (c *TTFTCallback) printf(format string, a ...any) {
+	out := c.Out
+	if out == nil {
+		out = os.Stdout
+	}
+	_, _ = fmt.Fprintf(out, format, a...)
+}
+
+func (c *TTFTCallback) OnStart(ctx context.Context, info *callbacks.RunInfo, input callbacks.CallbackInput) context.Context {
+	if info.Component == components.ComponentOfChatModel {
+		return context.WithValue(ctx, ttftStartKey{}, time.Now())
+	}
+	return ctx
+}
+
+func (c *TTFTCallback) OnEnd(ctx context.Context, info *callbacks.RunInfo, output callbacks.CallbackOutput) context.Context {
+	return ctx
+}
+
+func (c *TTFTCallback) OnError(ctx context.Context, info *callbacks.RunInfo, err error) context.Context {
+	return ctx
+}
+
+func (c *TTFTCallback) OnStartWithStreamInput(ctx context.Context, info *callbacks.RunInfo,
+	input *schema.StreamReader[callbacks.CallbackInput]) context.Context {
+	input.Close()
+	return ctx
+}
+
+func (c *TTFTCallback) OnEndWithStreamOutput(ctx context.Context, info *callbacks.RunInfo,
+	output *schema.StreamReader[callbacks.CallbackOutput]) context.Context {
+	start, ok := ctx.Value(ttftStartKey{}).(time.Time)
+	if info.Component != components.ComponentOfChatModel || !ok {
+		output.Close()
+		return ctx
+	}
+
+	c.mu.Lock()
+	c.calls++
+	call := c.calls
+	c.mu.Unlock()
+
+	c.wg.Add(1)
+	go func() {
+		defer c.wg.Done()
+		defer output.Close()
+
+		for {
+			frame, err := output.Recv()
+			if err != nil {
+				return
+			}
+			if mco := model.ConvCallbackOutput(frame); mco != nil && mco.Message != nil && mco.Message.Content != "" {
+				ttft := time.Since(start)
+				c.mu.Lock()
+				c.ttfts = append(c.ttfts, ttft)
+				c.printf("[TTFT] chat model call %d: %v\n", call, ttft)
+				c.mu.Unlock()
+				// 读完剩下的帧, 让 stream 正常结束
+				for {
+					if _, err := output.Recv(); err != nil {
+						return
+					}
+				}
+			}
+		}
+	}()
+	return ctx
+}
+
+// Summary 等待所有流读完, 打印这次运行的 TTFT 汇总.
+func (c *TTFTCallback) Summary() {
+	c.wg.Wait()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.ttfts) == 0 {
+		c.printf("[TTFT] %d chat model calls, none streamed content\n", c.calls)
+		return
+	}
+
+	lo, hi, sum := c.ttfts[0], c.ttfts[0], time.Duration(0)
+	for _, d := range c.ttfts {
+		lo, hi, sum = min(lo, d), max(hi, d), sum+d
+	}
+	c.printf("[TTFT] %d chat model calls, %d streamed content: min %v, avg %v, max %v\n",
+		c.calls, len(c.ttfts), lo, sum/time.Duration(len(c.ttfts)), hi)
+}
@@ -0,0 +1,50 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/cloudwego/eino/callbacks"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTTFTCallback(t *testing.T) {
+	ctx := context.Background()
+	var buf bytes.Buffer
+	ttft := &TTFTCallback{Out: &buf}
+
+	runner, err := NewAgentRunner(ctx, &AgentRunnerConfig{
+		ChatModel:  newScriptedModel(defaultMockScript()...),
+		PromptVars: map[string]string{"City": "北京"},
+		Handlers:   []callbacks.Handler{ttft},
+	})
+	assert.NoError(t, err)
+	defer runner.Close(ctx)
+
+	_, err = runner.Stream(ctx, "我在北京，给我推荐一些辣的菜")
+	assert.NoError(t, err)
+	ttft.Summary()
+
+	// 前 3 次调用只有 tool call, 只有最终回答那一次有 TTFT
+	out := buf.String()
+	assert.Equal(t, 1, strings.Count(out, "[TTFT] chat model call 4: "))
+	assert.Contains(t, out, "[TTFT] 4 chat model calls, 1 streamed content")
+}
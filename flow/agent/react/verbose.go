@@ -0,0 +1,197 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"unicode/utf8"
+
+	"github.com/cloudwego/eino/callbacks"
+	"github.com/cloudwego/eino/components"
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/components/tool"
+	"github.com/cloudwego/eino/schema"
+)
+
+// verboseResultLimit 是打印 tool 结果时保留的最大字节数.
+const verboseResultLimit = 200
+
+// VerboseCallback 用 "Thinking → Calling tool → Got result → ... → Final answer" 的阶段标签讲述 ReAct 循环的每一步,
+// 面向第一次接触 agent 的读者, 和面向调试的 LoggerCallback 不同, 它不打印所有细节, 只说明 agent 在做什么.
+// 每次 ChatModel 调用是一个 step; 流式输出会先读完再按阶段讲述, 模型的思考过程 (reasoning content) 单独标注.
+// 流式输出在 goroutine 中读取, 为了让讲述的顺序和实际执行的顺序一致, 下一个 ChatModel 或 Tool 开始前会先等上一步讲完;
+// agent 总是在读完模型的输出后才执行 tool, 所以这里的等待不会阻塞 agent.
+type VerboseCallback struct {
+	// Out 是输出, 为空时输出到 os.Stdout.
+	Out io.Writer
+
+	mu    sync.Mutex
+	steps atomic.Int64
+	wg    sync.WaitGroup // 跟踪 OnEndWithStreamOutput 中启动的 goroutine
+
+	modelWG sync.WaitGroup // 只跟踪读取 ChatModel 输出的 goroutine
+}
+
+type verboseStepKey struct{}
+
+func (c *VerboseCallback) printf(format string, a ...any) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	out := c.Out
+	if out == nil {
+		out = os.Stdout
+	}
+	_, _ = fmt.Fprintf(out, format, a...)
+}
+
+// Wait 等待所有流读完, 在退出前调用才能保证讲述完整.
+func (c *VerboseCallback) Wait() {
+	c.modelWG.Wait()
+	c.wg.Wait()
+}
+
+func (c *VerboseCallback) OnStart(ctx context.Context, info *callbacks.RunInfo, input callbacks.CallbackInput) context.Context {
+	c.modelWG.Wait()
+	if info.Component == components.ComponentOfChatModel {
+		step := c.steps.Add(1)
+		c.printf("[VERBOSE] step %d | Thinking...\n", step)
+		return context.WithValue(ctx, verboseStepKey{}, step)
+	}
+	return ctx
+}
+
+func (c *VerboseCallback) OnEnd(ctx context.Context, info *callbacks.RunInfo, output callbacks.CallbackOutput) context.Context {
+	switch info.Component {
+	case components.ComponentOfChatModel:
+		if mco := model.ConvCallbackOutput(output); mco != nil && mco.Message != nil {
+			c.narrate(ctx, mco.Message)
+		}
+	case components.ComponentOfTool:
+		if tco := tool.ConvCallbackOutput(output); tco != nil {
+			c.gotResult(info.Name, tco.Response)
+		}
+	}
+	return ctx
+}
+
+func (c *VerboseCallback) OnError(ctx context.Context, info *callbacks.RunInfo, err error) context.Context {
+	switch info.Component {
+	case components.ComponentOfChatModel:
+		c.printf("[VERBOSE] step %d | Thinking failed: %v\n", stepFrom(ctx), err)
+	case components.ComponentOfTool:
+		c.printf("[VERBOSE]        | Tool %s failed: %v\n", info.Name, err)
+	}
+	return ctx
+}
+
+func (c *VerboseCallback) OnStartWithStreamInput(ctx context.Context, info *callbacks.RunInfo,
+	input *schema.StreamReader[callbacks.CallbackInput]) context.Context {
+	input.Close()
+	return ctx
+}
+
+func (c *VerboseCallback) OnEndWithStreamOutput(ctx context.Context, info *callbacks.RunInfo,
+	output *schema.StreamReader[callbacks.CallbackOutput]) context.Context {
+	switch info.Component {
+	case components.ComponentOfChatModel:
+		c.modelWG.Add(1)
+		go func() {
+			defer c.modelWG.Done()
+			defer output.Close()
+			var frames []*schema.Message
+			for {
+				frame, err := output.Recv()
+				if err != nil {
+					break
+				}
+				if mco := model.ConvCallbackOutput(frame); mco != nil && mco.Message != nil {
+					frames = append(frames, mco.Message)
+				}
+			}
+			if len(frames) == 0 {
+				return
+			}
+			msg, err := schema.ConcatMessages(frames)
+			if err != nil {
+				c.printf("[VERBOSE] step %d | failed to read the streamed output: %v\n", stepFrom(ctx), err)
+				return
+			}
+			c.narrate(ctx, msg)
+		}()
+	case components.ComponentOfTool:
+		c.wg.Add(1)
+		go func() {
+			defer c.wg.Done()
+			defer output.Close()
+			var sb strings.Builder
+			for {
+				frame, err := output.Recv()
+				if err != nil {
+					break
+				}
+				if tco := tool.ConvCallbackOutput(frame); tco != nil {
+					sb.WriteString(tco.Response)
+				}
+			}
+			c.gotResult(info.Name, sb.String())
+		}()
+	default:
+		output.Close()
+	}
+	return ctx
+}
+
+// narrate 讲述一次 ChatModel 调用的结果: 先是思考过程, 然后是要调用的 tool, 没有 tool call 时就是最终回答.
+func (c *VerboseCallback) narrate(ctx context.Context, msg *schema.Message) {
+	step := stepFrom(ctx)
+	if reasoning := strings.TrimSpace(msg.ReasoningContent); reasoning != "" {
+		c.printf("[VERBOSE] step %d | Reasoning: %s\n", step, reasoning)
+	}
+	if len(msg.ToolCalls) == 0 {
+		c.printf("[VERBOSE] step %d | Final answer: %s\n", step, strings.TrimSpace(msg.Content))
+		return
+	}
+	if content := strings.TrimSpace(msg.Content); content != "" {
+		c.printf("[VERBOSE] step %d | Thought: %s\n", step, content)
+	}
+	for _, tc := range msg.ToolCalls {
+		c.printf("[VERBOSE] step %d | Calling tool %s with %s\n", step, tc.Function.Name, tc.Function.Arguments)
+	}
+}
+
+func (c *VerboseCallback) gotResult(toolName, result string) {
+	if len(result) > verboseResultLimit {
+		// 在字符边界截断, 避免把中文截成乱码
+		n := verboseResultLimit
+		for n > 0 && !utf8.RuneStart(result[n]) {
+			n--
+		}
+		result = result[:n] + "..."
+	}
+	c.printf("[VERBOSE]        | Got result from %s: %s\n", toolName, strings.TrimSpace(result))
+}
+
+func stepFrom(ctx context.Context) int64 {
+	step, _ := ctx.Value(verboseStepKey{}).(int64)
+	return step
+}
@@ -0,0 +1,73 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/cloudwego/eino/components/tool"
+	"github.com/cloudwego/eino/compose"
+	"github.com/cloudwego/eino/flow/agent"
+	"github.com/cloudwego/eino/schema"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVerboseCallback(t *testing.T) {
+	ctx := context.Background()
+
+	for _, stream := range []bool{false, true} {
+		call := toolCallMessage("call_1", "sleepy", `{"sleep_ms": 1}`)
+		call.ReasoningContent = "用户想吃点东西, 先查一下"
+		ragent, err := newAgent(ctx, newScriptedModel(call, schema.AssistantMessage("done", nil)), []tool.BaseTool{&sleepyTool{}}, 0, 0)
+		assert.NoError(t, err)
+
+		out := &strings.Builder{}
+		c := &VerboseCallback{Out: out}
+		opt := agent.WithComposeOptions(compose.WithCallbacks(c))
+		messages := []*schema.Message{schema.UserMessage("hi")}
+		if stream {
+			_, err = runStream(ctx, ragent, messages, &LoggerCallback{Out: &strings.Builder{}}, opt)
+		} else {
+			_, err = ragent.Generate(ctx, messages, opt)
+		}
+		assert.NoError(t, err)
+		c.Wait()
+
+		lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+		assert.Equal(t, []string{
+			"[VERBOSE] step 1 | Thinking...",
+			"[VERBOSE] step 1 | Reasoning: 用户想吃点东西, 先查一下",
+			`[VERBOSE] step 1 | Calling tool sleepy with {"sleep_ms": 1}`,
+			`[VERBOSE]        | Got result from sleepy: {"sleep_ms": 1}`,
+			"[VERBOSE] step 2 | Thinking...",
+			"[VERBOSE] step 2 | Final answer: done",
+		}, lines, "stream=%v", stream)
+	}
+}
+
+func TestVerboseCallbackTruncatesResult(t *testing.T) {
+	out := &strings.Builder{}
+	c := &VerboseCallback{Out: out}
+	c.gotResult("query_dishes", strings.Repeat("x", verboseResultLimit+10))
+	assert.Equal(t, "[VERBOSE]        | Got result from query_dishes: "+strings.Repeat("x", verboseResultLimit)+"...\n", out.String())
+
+	out.Reset()
+	c.gotResult("query_dishes", strings.Repeat("x", verboseResultLimit-1)+"辣")
+	assert.Equal(t, "[VERBOSE]        | Got result from query_dishes: "+strings.Repeat("x", verboseResultLimit-1)+"...\n", out.String())
+}
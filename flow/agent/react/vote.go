@@ -0,0 +1,135 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/schema"
+
+	"github.com/cloudwego/eino-examples/flow/agent/react/tools"
+)
+
+// voteTemperature 是采样最终回答时使用的 temperature, 需要大于 0 才能得到不同的样本.
+const voteTemperature = 0.8
+
+// RunWithVote 演示 self-consistency: 先正常运行一次 agent 得到 tool 调用的结果,
+// 再基于同样的上下文采样 samples 次最终回答, 从每个回答中识别推荐的餐厅并投票,
+// 返回提到得票最多的餐厅的第一个回答. 每个样本和最终的票数都会打印出来.
+func (r *AgentRunner) RunWithVote(ctx context.Context, userMessage string, samples int) (string, error) {
+	if samples < 1 {
+		return "", fmt.Errorf("samples must be at least 1, got %d", samples)
+	}
+
+	messages := []*schema.Message{schema.SystemMessage(r.systemPrompt), schema.UserMessage(userMessage)}
+	_, steps, err := r.RunWithSteps(ctx, messages)
+	if err != nil {
+		return "", err
+	}
+	transcript := append(messages, steps...)
+
+	known := tools.RestaurantNames()
+	answers := make([]string, 0, samples)
+	picks := make([][]string, 0, samples)
+	for i := 0; i < samples; i++ {
+		msg, err := r.chatModel.Generate(ctx, transcript, model.WithTemperature(voteTemperature))
+		if err != nil {
+			return "", fmt.Errorf("failed to sample answer %d: %w", i+1, err)
+		}
+		if isEmptyAnswer(msg) || len(msg.ToolCalls) > 0 {
+			fmt.Printf("[SAMPLE %d] no answer, skipped\n", i+1)
+			continue
+		}
+		names := extractRecommendations(msg.Content, known)
+		fmt.Printf("[SAMPLE %d] recommends [%s]: %s\n", i+1, strings.Join(names, ", "), msg.Content)
+		answers = append(answers, msg.Content)
+		picks = append(picks, names)
+	}
+	if len(answers) == 0 {
+		return "", errEmptyAnswer
+	}
+
+	tally := tallyVotes(picks)
+	if len(tally) == 0 {
+		fmt.Printf("[VOTE] no restaurant recognized, using the first sample\n")
+		return answers[0], nil
+	}
+	parts := make([]string, 0, len(tally))
+	for _, v := range tally {
+		parts = append(parts, fmt.Sprintf("%s=%d", v.name, v.count))
+	}
+	fmt.Printf("[VOTE] %s\n", strings.Join(parts, ", "))
+
+	winner := tally[0].name
+	for i, names := range picks {
+		for _, name := range names {
+			if name == winner {
+				return answers[i], nil
+			}
+		}
+	}
+	return "", errors.New("unreachable: winner not found in any sample")
+}
+
+// extractRecommendations 按在回答中第一次出现的顺序, 返回回答提到的已知餐厅.
+func extractRecommendations(answer string, known []string) []string {
+	type hit struct {
+		name string
+		pos  int
+	}
+	var hits []hit
+	for _, name := range known {
+		if pos := strings.Index(answer, name); pos >= 0 {
+			hits = append(hits, hit{name: name, pos: pos})
+		}
+	}
+	sort.SliceStable(hits, func(i, j int) bool { return hits[i].pos < hits[j].pos })
+	names := make([]string, 0, len(hits))
+	for _, h := range hits {
+		names = append(names, h.name)
+	}
+	return names
+}
+
+type vote struct {
+	name  string
+	count int
+}
+
+// tallyVotes 每个样本给它提到的每家餐厅投一票, 按票数从高到低排序, 票数相同时先出现的在前.
+func tallyVotes(picks [][]string) []vote {
+	var tally []vote
+	index := map[string]int{}
+	for _, names := range picks {
+		for _, name := range names {
+			i, ok := index[name]
+			if !ok {
+				i = len(tally)
+				index[name] = i
+				tally = append(tally, vote{name: name})
+			}
+			tally[i].count++
+		}
+	}
+	sort.SliceStable(tally, func(i, j int) bool { return tally[i].count > tally[j].count })
+	return tally
+}
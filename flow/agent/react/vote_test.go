@@ -0,0 +1,63 @@
+/*
+ * Copyright 2024 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cloudwego/eino/schema"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExtractRecommendations(t *testing.T) {
+	known := []string{"云边小馆", "聚福轩食府", "花影食舍"}
+	assert.Equal(t, []string{"聚福轩食府", "云边小馆"}, extractRecommendations("首选聚福轩食府, 其次是云边小馆.", known))
+	assert.Empty(t, extractRecommendations("没有合适的餐厅", known))
+}
+
+func TestTallyVotes(t *testing.T) {
+	tally := tallyVotes([][]string{{"云边小馆"}, {"聚福轩食府", "云边小馆"}, {"聚福轩食府"}, {"花影食舍", "聚福轩食府"}})
+	assert.Equal(t, []vote{{"聚福轩食府", 3}, {"云边小馆", 2}, {"花影食舍", 1}}, tally)
+
+	// 票数相同时先出现的在前
+	assert.Equal(t, []vote{{"云边小馆", 1}, {"花影食舍", 1}}, tallyVotes([][]string{{"云边小馆"}, {"花影食舍"}}))
+}
+
+func TestAgentRunnerRunWithVote(t *testing.T) {
+	ctx := context.Background()
+
+	script := append(defaultMockScript(),
+		schema.AssistantMessage("推荐花影食舍.", nil),
+		schema.AssistantMessage("推荐聚福轩食府的火辣辣的吻.", nil),
+		schema.AssistantMessage("", nil),
+		schema.AssistantMessage("聚福轩食府和云边小馆都不错.", nil),
+	)
+	runner, err := NewAgentRunner(ctx, &AgentRunnerConfig{
+		ChatModel:  newScriptedModel(script...),
+		PromptVars: map[string]string{"City": "北京"},
+	})
+	assert.NoError(t, err)
+	defer runner.Close(ctx)
+
+	answer, err := runner.RunWithVote(ctx, "我在北京，给我推荐一些辣的菜", 4)
+	assert.NoError(t, err)
+	assert.Equal(t, "推荐聚福轩食府的火辣辣的吻.", answer)
+
+	_, err = runner.RunWithVote(ctx, "你好", 0)
+	assert.Error(t, err)
+}